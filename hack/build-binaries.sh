@@ -14,10 +14,24 @@
 # See the License for the specific language governing permissions and
 # limitations under the License.
 
-set -e -x -u
+set -e -u
 
-GOOS=darwin GOARCH=amd64 go build -o knctl-darwin-amd64 ./cmd/...
-GOOS=linux GOARCH=amd64 go build -o knctl-linux-amd64 ./cmd/...
-GOOS=windows GOARCH=amd64 go build -o knctl-windows-amd64.exe ./cmd/...
+# Cosign private key that signs release checksums (see 'knctl update')
+if [ -z "${KNCTL_RELEASE_KEY:-}" ]; then
+  echo "Expected \$KNCTL_RELEASE_KEY to point to cosign private key" >&2
+  exit 1
+fi
 
-shasum -a 256 ./knctl-*-amd64*
+# Public key is embedded so that 'knctl update' verifies signature by default
+release_public_key=$(cosign public-key --key "$KNCTL_RELEASE_KEY" | base64 | tr -d '\n')
+ldflags="-X github.com/cppforlife/knctl/pkg/knctl/selfupdate.ReleasePublicKey=${release_public_key}"
+
+set -x
+
+GOOS=darwin GOARCH=amd64 go build -ldflags "$ldflags" -o knctl-darwin-amd64 ./cmd/...
+GOOS=linux GOARCH=amd64 go build -ldflags "$ldflags" -o knctl-linux-amd64 ./cmd/...
+GOOS=windows GOARCH=amd64 go build -ldflags "$ldflags" -o knctl-windows-amd64.exe ./cmd/...
+
+shasum -a 256 ./knctl-*-amd64* | tee checksums.txt
+
+cosign sign-blob --yes --key "$KNCTL_RELEASE_KEY" --output-signature checksums.txt.sig checksums.txt
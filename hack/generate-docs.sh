@@ -18,6 +18,7 @@ set -e -x -u
 
 export KNCTL_NAMESPACE=
 export KNCTL_KUBECONFIG=
+export KNCTL_TOKEN=
 export KNCTL_BASIC_AUTH_SECRET_PASSWORD=
 export KNCTL_SSH_AUTH_SECRET_PRIVATE_KEY=
 
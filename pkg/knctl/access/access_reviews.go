@@ -0,0 +1,90 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package access
+
+import (
+	"fmt"
+
+	authv1 "k8s.io/api/authorization/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+type Permission struct {
+	Verb        string
+	Group       string
+	Resource    string
+	Subresource string
+}
+
+func (p Permission) ResourceDesc() string {
+	desc := p.Resource
+	if len(p.Subresource) > 0 {
+		desc += "/" + p.Subresource
+	}
+	if len(p.Group) > 0 {
+		desc += "." + p.Group
+	}
+	return desc
+}
+
+type PermissionResult struct {
+	Permission
+
+	Allowed bool
+	Reason  string
+}
+
+type AccessReviews struct {
+	coreClient kubernetes.Interface
+}
+
+func NewAccessReviews(coreClient kubernetes.Interface) AccessReviews {
+	return AccessReviews{coreClient}
+}
+
+// Check asks API server whether current user is allowed to perform
+// each of the given actions within a namespace (via SelfSubjectAccessReview)
+func (r AccessReviews) Check(namespace string, perms []Permission) ([]PermissionResult, error) {
+	var results []PermissionResult
+
+	for _, perm := range perms {
+		review := &authv1.SelfSubjectAccessReview{
+			Spec: authv1.SelfSubjectAccessReviewSpec{
+				ResourceAttributes: &authv1.ResourceAttributes{
+					Namespace:   namespace,
+					Verb:        perm.Verb,
+					Group:       perm.Group,
+					Resource:    perm.Resource,
+					Subresource: perm.Subresource,
+				},
+			},
+		}
+
+		createdReview, err := r.coreClient.AuthorizationV1().SelfSubjectAccessReviews().Create(review)
+		if err != nil {
+			return nil, fmt.Errorf("Checking access to %s '%s': %s", perm.Verb, perm.ResourceDesc(), err)
+		}
+
+		results = append(results, PermissionResult{
+			Permission: perm,
+			Allowed:    createdReview.Status.Allowed,
+			Reason:     createdReview.Status.Reason,
+		})
+	}
+
+	return results, nil
+}
@@ -0,0 +1,158 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/cppforlife/go-cli-ui/ui"
+	. "github.com/cppforlife/knctl/pkg/knctl/api"
+	ctlservice "github.com/cppforlife/knctl/pkg/knctl/service"
+	"github.com/cppforlife/knctl/pkg/knctl/testkit"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestOperations_RequireNames(t *testing.T) {
+	client := NewClient(ClientOpts{KubeconfigPath: "/non-existent"})
+	ctx := context.Background()
+
+	err := NewDeploy(client, DeployOpts{Image: "img"}).Run(ctx)
+	expectErr(t, err, "Expected service name to be non-empty")
+
+	err = NewDeploy(client, DeployOpts{Service: "svc1"}).Run(ctx)
+	expectErr(t, err, "Expected image to be non-empty")
+
+	err = NewRollout(client, RolloutOpts{}).Run(ctx)
+	expectErr(t, err, "Expected route name to be non-empty")
+
+	err = NewDelete(client, DeleteOpts{}).Run(ctx)
+	expectErr(t, err, "Expected service name to be non-empty")
+
+	err = NewLogs(client, LogsOpts{}).Run(ctx)
+	expectErr(t, err, "Expected service name to be non-empty")
+
+	_, err = NewCurlRequest(client, CurlRequestOpts{}).Run(ctx)
+	expectErr(t, err, "Expected service name to be non-empty")
+}
+
+func TestDeploy_CancelledContext(t *testing.T) {
+	client := NewClient(ClientOpts{KubeconfigPath: "/non-existent"})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := NewDeploy(client, DeployOpts{Namespace: "ns1", Service: "svc1", Image: "img"}).Run(ctx)
+	expectErr(t, err, "context canceled")
+}
+
+func TestDeploy_RequiresSignatureVerificationInAnnotatedNamespace(t *testing.T) {
+	cluster := testkit.NewCluster(t, &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "ns1",
+			Annotations: map[string]string{"cli.knative.dev/require-image-signature": "true"},
+		},
+	})
+
+	client := NewClientWithFactories(ui.NewNoopUI(), cluster.ConfigFactory(), cluster.DepsFactory())
+
+	err := NewDeploy(client, DeployOpts{Namespace: "ns1", Service: "svc1", Image: "gcr.io/proj/app"}).Run(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "Expected --verify-signature to be specified") {
+		t.Fatalf("Expected signature verification to be required, but was: %v", err)
+	}
+}
+
+func TestDeploy_ChecksImagePullSecrets(t *testing.T) {
+	cluster := testkit.NewCluster(t)
+
+	client := NewClientWithFactories(ui.NewNoopUI(), cluster.ConfigFactory(), cluster.DepsFactory())
+
+	opts := DeployOpts{Namespace: "ns1", Service: "svc1", Image: "gcr.io/proj/app", ImagePullSecrets: []string{"reg1"}}
+
+	err := NewDeploy(client, opts).Run(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "Getting image pull secret 'reg1'") {
+		t.Fatalf("Expected image pull secret to be checked, but was: %v", err)
+	}
+
+	_, err = cluster.ServingClient().ServingV1alpha1().Services("ns1").Get("svc1", metav1.GetOptions{})
+	if !errors.IsNotFound(err) {
+		t.Fatalf("Expected service to not be created, but was: %v", err)
+	}
+}
+
+func TestDelete_PinnedRevisions(t *testing.T) {
+	cluster := testkit.NewCluster(t,
+		testkit.NewService("ns1", "svc1").Build(),
+		testkit.NewRevision("ns1", "svc1", "svc1-00001").Annotations(map[string]string{
+			ctlservice.PinnedAnnotationKey: "true",
+		}).Build(),
+	)
+
+	client := NewClientWithFactories(ui.NewNoopUI(), cluster.ConfigFactory(), cluster.DepsFactory())
+
+	err := NewDelete(client, DeleteOpts{Namespace: "ns1", Service: "svc1"}).Run(context.Background())
+	expectErr(t, err, "Expected service 'svc1' to not have pinned revisions, but found: svc1-00001")
+
+	err = NewDelete(client, DeleteOpts{Namespace: "ns1", Service: "svc1", Force: true}).Run(context.Background())
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	_, err = cluster.ServingClient().ServingV1alpha1().Services("ns1").Get("svc1", metav1.GetOptions{})
+	if !errors.IsNotFound(err) {
+		t.Fatalf("Expected service to be deleted, but was: %v", err)
+	}
+}
+
+func TestRollout_UpdatesRoute(t *testing.T) {
+	cluster := testkit.NewCluster(t,
+		testkit.NewService("ns1", "svc1").Manual().Build(),
+		testkit.NewRevision("ns1", "svc1", "svc1-00001").Build(),
+		testkit.NewRevision("ns1", "svc1", "svc1-00002").Build(),
+		testkit.NewRoute("ns1", "svc1").Traffic("svc1-00001", 100).Build(),
+	)
+
+	client := NewClientWithFactories(ui.NewNoopUI(), cluster.ConfigFactory(), cluster.DepsFactory())
+
+	opts := RolloutOpts{Namespace: "ns1", Route: "svc1", RevisionPercentages: []string{"svc1-00002=100%"}}
+
+	err := NewRollout(client, opts).Run(context.Background())
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	route, err := cluster.ServingClient().ServingV1alpha1().Routes("ns1").Get("svc1", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	if len(route.Spec.Traffic) != 1 || route.Spec.Traffic[0].RevisionName != "svc1-00002" {
+		t.Fatalf("Expected route to send traffic to svc1-00002, but was: %#v", route.Spec.Traffic)
+	}
+}
+
+func expectErr(t *testing.T, err error, expected string) {
+	if err == nil {
+		t.Fatalf("Expected error '%s'", expected)
+	}
+	if err.Error() != expected {
+		t.Fatalf("Expected error '%s' but was '%s'", expected, err)
+	}
+}
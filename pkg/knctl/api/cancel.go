@@ -0,0 +1,38 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"context"
+)
+
+// cancelCh returns channel that is closed once ctx is done;
+// returned func must be called to stop watching ctx
+func cancelCh(ctx context.Context) (chan struct{}, func()) {
+	ch := make(chan struct{})
+	doneCh := make(chan struct{})
+
+	go func() {
+		select {
+		case <-ctx.Done():
+			close(ch)
+		case <-doneCh:
+		}
+	}()
+
+	return ch, func() { close(doneCh) }
+}
@@ -0,0 +1,84 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package api exposes knctl operations to other Go programs
+// without requiring them to exec knctl binary.
+package api
+
+import (
+	"fmt"
+
+	"github.com/cppforlife/go-cli-ui/ui"
+	cmdcore "github.com/cppforlife/knctl/pkg/knctl/cmd/core"
+)
+
+type ClientOpts struct {
+	// Defaults to $KNCTL_KUBECONFIG, $KUBECONFIG or ~/.kube/config
+	KubeconfigPath string
+	// Defaults to current context
+	KubeconfigContext string
+
+	// Receives same output as knctl would print; defaults to no output
+	UI ui.UI
+}
+
+type Client struct {
+	ui            ui.UI
+	configFactory cmdcore.ConfigFactory
+	depsFactory   cmdcore.DepsFactory
+}
+
+func NewClient(opts ClientOpts) *Client {
+	pathFlag := cmdcore.NewKubeconfigPathFlag()
+	pathFlag.Set(opts.KubeconfigPath)
+
+	contextFlag := cmdcore.NewKubeconfigContextFlag()
+	contextFlag.Set(opts.KubeconfigContext)
+
+	configFactory := cmdcore.NewConfigFactoryImpl()
+	configFactory.ConfigurePathResolver(pathFlag.Value)
+	configFactory.ConfigureContextResolver(contextFlag.Value)
+
+	output := opts.UI
+	if output == nil {
+		output = ui.NewNoopUI()
+	}
+
+	return &Client{
+		ui:            output,
+		configFactory: configFactory,
+		depsFactory:   cmdcore.NewDepsFactoryImpl(configFactory),
+	}
+}
+
+// NewClientWithFactories returns client that shares configuration
+// (including global flags) of a running knctl command
+func NewClientWithFactories(ui ui.UI, configFactory cmdcore.ConfigFactory, depsFactory cmdcore.DepsFactory) *Client {
+	return &Client{ui: ui, configFactory: configFactory, depsFactory: depsFactory}
+}
+
+func (c *Client) namespace(name string) (string, error) {
+	if len(name) > 0 {
+		return name, nil
+	}
+
+	name, err := c.configFactory.DefaultNamespace()
+	if err != nil {
+		return "", fmt.Errorf("Determining default namespace: %s", err)
+	}
+
+	return name, nil
+}
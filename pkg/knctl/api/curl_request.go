@@ -0,0 +1,143 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	ctlservice "github.com/cppforlife/knctl/pkg/knctl/service"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type CurlRequestOpts struct {
+	Namespace string
+	Service   string
+
+	// Defaults to 80
+	Port int32
+	// Defaults to GET
+	Method string
+	Path   string
+	Header http.Header
+	Body   io.Reader
+}
+
+// CurlRequest sends HTTP request to the first ingress address
+// with Host header set to service's domain (same as `knctl curl`)
+type CurlRequest struct {
+	client *Client
+	opts   CurlRequestOpts
+}
+
+func NewCurlRequest(client *Client, opts CurlRequestOpts) CurlRequest {
+	return CurlRequest{client, opts}
+}
+
+// Run returns response from the service; caller is responsible for closing its body
+func (op CurlRequest) Run(ctx context.Context) (*http.Response, error) {
+	if len(op.opts.Service) == 0 {
+		return nil, fmt.Errorf("Expected service name to be non-empty")
+	}
+
+	namespace, err := op.client.namespace(op.opts.Namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	servingClient, err := op.client.depsFactory.ServingClient()
+	if err != nil {
+		return nil, err
+	}
+
+	coreClient, err := op.client.depsFactory.CoreClient()
+	if err != nil {
+		return nil, err
+	}
+
+	service, err := servingClient.ServingV1alpha1().Services(namespace).Get(op.opts.Service, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("Getting service: %s", err)
+	}
+
+	cache, err := op.client.depsFactory.Cache()
+	if err != nil {
+		return nil, err
+	}
+
+	logger, err := op.client.depsFactory.Logger()
+	if err != nil {
+		return nil, err
+	}
+
+	inCluster, err := op.client.depsFactory.InCluster()
+	if err != nil {
+		return nil, err
+	}
+
+	port := op.opts.Port
+	if port == 0 {
+		port = 80
+	}
+
+	serviceAddr := ctlservice.NewServiceAddress(service, coreClient, cache, logger, inCluster)
+
+	domain, err := serviceAddr.Domain()
+	if err != nil {
+		return nil, err
+	}
+
+	url, err := serviceAddr.URL(port, false)
+	if err != nil {
+		return nil, err
+	}
+
+	method := op.opts.Method
+	if len(method) == 0 {
+		method = http.MethodGet
+	}
+
+	req, err := http.NewRequest(method, url+"/"+strings.TrimPrefix(op.opts.Path, "/"), op.opts.Body)
+	if err != nil {
+		return nil, fmt.Errorf("Building request: %s", err)
+	}
+
+	for key, vals := range op.opts.Header {
+		req.Header[key] = vals
+	}
+
+	req.Host = domain
+
+	httpClient, err := op.client.depsFactory.HTTPClient()
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := httpClient.Do(req.WithContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("Sending request: %s", err)
+	}
+
+	return resp, nil
+}
@@ -0,0 +1,77 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"context"
+	"fmt"
+
+	ctlservice "github.com/cppforlife/knctl/pkg/knctl/service"
+)
+
+type DeleteOpts struct {
+	Namespace string
+	Service   string
+
+	// Deletes service even if some of its revisions are pinned
+	Force bool
+}
+
+// Delete deletes service (same as `knctl service delete`)
+type Delete struct {
+	client *Client
+	opts   DeleteOpts
+}
+
+func NewDelete(client *Client, opts DeleteOpts) Delete {
+	return Delete{client, opts}
+}
+
+func (op Delete) Run(ctx context.Context) error {
+	if len(op.opts.Service) == 0 {
+		return fmt.Errorf("Expected service name to be non-empty")
+	}
+
+	namespace, err := op.client.namespace(op.opts.Namespace)
+	if err != nil {
+		return err
+	}
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	servingClient, err := op.client.depsFactory.ServingClient()
+	if err != nil {
+		return err
+	}
+
+	deletion := ctlservice.NewDeletion(servingClient)
+
+	if !op.opts.Force {
+		err := deletion.CheckNotPinned(namespace, op.opts.Service)
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	return deletion.Delete(namespace, op.opts.Service)
+}
@@ -0,0 +1,126 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	cmdcore "github.com/cppforlife/knctl/pkg/knctl/cmd/core"
+	cmdflags "github.com/cppforlife/knctl/pkg/knctl/cmd/flags"
+	cmdsvc "github.com/cppforlife/knctl/pkg/knctl/cmd/service"
+)
+
+type DeployOpts struct {
+	Namespace string
+	Service   string
+
+	Image         string
+	EnvVars       []string // format: ENV_KEY=value
+	EnvSecrets    []string // format: ENV_KEY=secret-name/key
+	EnvConfigMaps []string // format: ENV_KEY=config-map-name/key
+
+	ImagePullSecrets []string
+
+	ContainerConcurrency *int
+	MinScale             *int
+	MaxScale             *int
+	StartupCPUBoost      *int
+
+	Tags        []string
+	Annotations []string // format: key=value
+
+	UnmanagedRoute  bool
+	RequireApproval bool
+
+	// Zero value skips waiting for new revision to become ready
+	RevisionReadyTimeout time.Duration
+}
+
+// Deploy creates or updates service with given image (same as `knctl deploy`)
+type Deploy struct {
+	client *Client
+	opts   DeployOpts
+}
+
+func NewDeploy(client *Client, opts DeployOpts) Deploy {
+	return Deploy{client, opts}
+}
+
+func (op Deploy) Run(ctx context.Context) error {
+	c, d := op.client, op.opts
+
+	if len(d.Service) == 0 {
+		return fmt.Errorf("Expected service name to be non-empty")
+	}
+	if len(d.Image) == 0 {
+		return fmt.Errorf("Expected image to be non-empty")
+	}
+
+	namespace, err := c.namespace(d.Namespace)
+	if err != nil {
+		return err
+	}
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	deployOpts := cmdsvc.NewDeployOptions(c.ui, c.configFactory, c.depsFactory, cmdcore.CancelContext{ctx})
+	deployOpts.ServiceFlags = cmdflags.ServiceFlags{cmdcore.NamespaceFlags{namespace}, d.Service}
+	deployOpts.DeployFlags = op.deployFlags()
+	deployOpts.RequireRevisionReady = true
+
+	err = deployOpts.Run()
+	if err != nil && ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	return err
+}
+
+func (op Deploy) deployFlags() cmdsvc.DeployFlags {
+	d := op.opts
+
+	return cmdsvc.DeployFlags{
+		TagFlags:      cmdflags.TagFlags{d.Tags},
+		AnnotateFlags: cmdflags.AnnotateFlags{d.Annotations},
+
+		Image:         d.Image,
+		EnvVars:       d.EnvVars,
+		EnvSecrets:    d.EnvSecrets,
+		EnvConfigMaps: d.EnvConfigMaps,
+
+		ImagePullSecrets: d.ImagePullSecrets,
+
+		ContainerConcurrency: d.ContainerConcurrency,
+		MinScale:             d.MinScale,
+		MaxScale:             d.MaxScale,
+		StartupCPUBoost:      d.StartupCPUBoost,
+
+		WatchRevisionReady:        d.RevisionReadyTimeout > 0,
+		WatchRevisionReadyTimeout: d.RevisionReadyTimeout,
+
+		ManagedRoute:    !d.UnmanagedRoute,
+		RequireApproval: d.RequireApproval,
+
+		// Git metadata of working directory (e.g. of `knctl serve`)
+		// is not related to deployed image
+		GitMetadata: false,
+	}
+}
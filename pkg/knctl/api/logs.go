@@ -0,0 +1,95 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cppforlife/knctl/pkg/knctl/logs"
+	ctlservice "github.com/cppforlife/knctl/pkg/knctl/service"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type LogsOpts struct {
+	Namespace string
+	Service   string
+
+	// Follow keeps printing logs until context is done
+	Follow bool
+	// Defaults to 10 when not following
+	Lines int64
+
+	ShowSecrets bool
+}
+
+// Logs prints service logs to client's UI (same as `knctl logs`)
+type Logs struct {
+	client *Client
+	opts   LogsOpts
+}
+
+func NewLogs(client *Client, opts LogsOpts) Logs {
+	return Logs{client, opts}
+}
+
+func (op Logs) Run(ctx context.Context) error {
+	if len(op.opts.Service) == 0 {
+		return fmt.Errorf("Expected service name to be non-empty")
+	}
+
+	namespace, err := op.client.namespace(op.opts.Namespace)
+	if err != nil {
+		return err
+	}
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	tailOpts := logs.PodLogOpts{Follow: op.opts.Follow}
+
+	if !op.opts.Follow {
+		lines := op.opts.Lines
+		if lines <= 0 {
+			lines = 10
+		}
+		tailOpts.Lines = &lines
+	}
+
+	servingClient, err := op.client.depsFactory.ServingClient()
+	if err != nil {
+		return err
+	}
+
+	coreClient, err := op.client.depsFactory.CoreClient()
+	if err != nil {
+		return err
+	}
+
+	service, err := servingClient.ServingV1alpha1().Services(namespace).Get(op.opts.Service, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+
+	podWatcher := ctlservice.NewServicePodWatcher(service, servingClient, coreClient, op.client.ui)
+
+	logsCancelCh, stopLogsCancel := cancelCh(ctx)
+	defer stopLogsCancel()
+
+	return logs.NewView(tailOpts, podWatcher, coreClient, op.client.ui, op.opts.ShowSecrets).Show(logsCancelCh)
+}
@@ -0,0 +1,74 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"context"
+	"fmt"
+
+	cmdcore "github.com/cppforlife/knctl/pkg/knctl/cmd/core"
+	cmdrte "github.com/cppforlife/knctl/pkg/knctl/cmd/route"
+)
+
+type RolloutOpts struct {
+	Namespace string
+	Route     string
+
+	// format: revision=percentage (example: app-00001=100%, app:latest=100%)
+	RevisionPercentages []string
+	// format: service=percentage (example: app=100%)
+	ServicePercentages []string
+}
+
+// Rollout creates or updates route with traffic percentages (same as `knctl rollout`)
+type Rollout struct {
+	client *Client
+	opts   RolloutOpts
+}
+
+func NewRollout(client *Client, opts RolloutOpts) Rollout {
+	return Rollout{client, opts}
+}
+
+func (op Rollout) Run(ctx context.Context) error {
+	if len(op.opts.Route) == 0 {
+		return fmt.Errorf("Expected route name to be non-empty")
+	}
+
+	namespace, err := op.client.namespace(op.opts.Namespace)
+	if err != nil {
+		return err
+	}
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	createOpts := cmdrte.NewCreateOptions(op.client.ui, op.client.depsFactory).WithCancelWatcher(cmdcore.CancelContext{ctx})
+	createOpts.RouteFlags = cmdrte.RouteFlags{cmdcore.NamespaceFlags{namespace}, op.opts.Route}
+	createOpts.TrafficFlags = cmdrte.TrafficFlags{
+		RevisionPercentages: op.opts.RevisionPercentages,
+		ServicePercentages:  op.opts.ServicePercentages,
+	}
+
+	err = createOpts.Run()
+	if err != nil && ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	return err
+}
@@ -0,0 +1,122 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package approval
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	configMapNamePrefix = "knctl-approval-"
+	configMapPendingKey = "pending"
+
+	// ServiceLabelKey labels approval config maps with service name
+	ServiceLabelKey = "cli.knative.dev/approval-service"
+)
+
+// Pending describes deployed revision that waits for someone
+// to approve shifting service's traffic to it
+type Pending struct {
+	Service          string    `json:"service"`
+	Revision         string    `json:"revision"`
+	PreviousRevision string    `json:"previousRevision"`
+	RequestedBy      string    `json:"requestedBy"`
+	RequestedAt      time.Time `json:"requestedAt"`
+
+	// Service is switched back from manual mode once approved
+	RestoreManagedRoute bool `json:"restoreManagedRoute,omitempty"`
+}
+
+// Approvals stores at most one pending approval per service
+// in a config map next to that service (newer deploy replaces older one)
+type Approvals struct {
+	coreClient kubernetes.Interface
+}
+
+func NewApprovals(coreClient kubernetes.Interface) Approvals {
+	return Approvals{coreClient}
+}
+
+func (a Approvals) Request(namespace string, pending Pending) error {
+	pendingBytes, err := json.Marshal(pending)
+	if err != nil {
+		return fmt.Errorf("Marshaling pending approval: %s", err)
+	}
+
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      a.configMapName(pending.Service),
+			Namespace: namespace,
+			Labels:    map[string]string{ServiceLabelKey: pending.Service},
+		},
+		Data: map[string]string{configMapPendingKey: string(pendingBytes)},
+	}
+
+	configMaps := a.coreClient.CoreV1().ConfigMaps(namespace)
+
+	_, err = configMaps.Create(configMap)
+	if err != nil {
+		if !errors.IsAlreadyExists(err) {
+			return fmt.Errorf("Requesting approval: %s", err)
+		}
+
+		_, err = configMaps.Update(configMap)
+		if err != nil {
+			return fmt.Errorf("Requesting approval: %s", err)
+		}
+	}
+
+	return nil
+}
+
+func (a Approvals) Get(namespace, serviceName string) (Pending, bool, error) {
+	configMap, err := a.coreClient.CoreV1().ConfigMaps(namespace).Get(a.configMapName(serviceName), metav1.GetOptions{})
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return Pending{}, false, nil
+		}
+		return Pending{}, false, fmt.Errorf("Getting pending approval: %s", err)
+	}
+
+	var pending Pending
+
+	err = json.Unmarshal([]byte(configMap.Data[configMapPendingKey]), &pending)
+	if err != nil {
+		return Pending{}, false, fmt.Errorf("Unmarshaling pending approval: %s", err)
+	}
+
+	return pending, true, nil
+}
+
+func (a Approvals) Delete(namespace, serviceName string) error {
+	err := a.coreClient.CoreV1().ConfigMaps(namespace).Delete(a.configMapName(serviceName), &metav1.DeleteOptions{})
+	if err != nil && !errors.IsNotFound(err) {
+		return fmt.Errorf("Deleting pending approval: %s", err)
+	}
+	return nil
+}
+
+func (a Approvals) configMapName(serviceName string) string {
+	return configMapNamePrefix + serviceName
+}
@@ -0,0 +1,83 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package approval_test
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	. "github.com/cppforlife/knctl/pkg/knctl/approval"
+	"github.com/cppforlife/knctl/pkg/knctl/testkit"
+)
+
+func TestApprovals_RequestGetDelete(t *testing.T) {
+	cluster := testkit.NewCluster(t)
+	approvals := NewApprovals(cluster.CoreClient())
+
+	_, found, err := approvals.Get("ns1", "svc1")
+	if err != nil || found {
+		t.Fatalf("Expected no pending approval: %v", err)
+	}
+
+	requestedAt := time.Date(2018, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	for _, rev := range []string{"svc1-00002", "svc1-00003"} {
+		err := approvals.Request("ns1", Pending{
+			Service:          "svc1",
+			Revision:         rev,
+			PreviousRevision: "svc1-00001",
+			RequestedBy:      "user1",
+			RequestedAt:      requestedAt,
+		})
+		if err != nil {
+			t.Fatalf("Expected no error: %s", err)
+		}
+	}
+
+	pending, found, err := approvals.Get("ns1", "svc1")
+	if err != nil || !found {
+		t.Fatalf("Expected pending approval: %v", err)
+	}
+
+	expectedPending := Pending{
+		Service:          "svc1",
+		Revision:         "svc1-00003",
+		PreviousRevision: "svc1-00001",
+		RequestedBy:      "user1",
+		RequestedAt:      requestedAt,
+	}
+
+	if !reflect.DeepEqual(pending, expectedPending) {
+		t.Fatalf("Expected latest request to replace previous one, but was: %#v", pending)
+	}
+
+	err = approvals.Delete("ns1", "svc1")
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	_, found, err = approvals.Get("ns1", "svc1")
+	if err != nil || found {
+		t.Fatalf("Expected pending approval to be deleted: %v", err)
+	}
+
+	err = approvals.Delete("ns1", "svc1")
+	if err != nil {
+		t.Fatalf("Expected deleting missing approval to succeed: %s", err)
+	}
+}
@@ -18,126 +18,66 @@ package build
 
 import (
 	"fmt"
-	"time"
 
+	"github.com/cppforlife/knctl/pkg/knctl/waiter"
 	"github.com/knative/build/pkg/apis/build/v1alpha1"
-	buildclientset "github.com/knative/build/pkg/client/clientset/versioned"
 	corev1 "k8s.io/api/core/v1"
-	"k8s.io/apimachinery/pkg/api/errors"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
 )
 
 type BuildWaiter struct {
-	build            *v1alpha1.Build
-	buildClient      buildclientset.Interface
-	podsGetterClient typedcorev1.PodsGetter
+	build     *v1alpha1.Build
+	objWaiter *waiter.Waiter
 }
 
-func NewBuildWaiter(
-	build *v1alpha1.Build,
-	buildClient buildclientset.Interface,
-	podsGetterClient typedcorev1.PodsGetter,
-) BuildWaiter {
-	return BuildWaiter{build, buildClient, podsGetterClient}
+func NewBuildWaiter(build *v1alpha1.Build, objWaiter *waiter.Waiter) BuildWaiter {
+	return BuildWaiter{build, objWaiter}
 }
 
 func (w BuildWaiter) WaitForBuilderAssignment(cancelCh chan struct{}) (*v1alpha1.Build, error) {
-	for {
-		// TODO infinite retry
-
-		build, err := w.buildClient.BuildV1alpha1().Builds(w.build.Namespace).Get(w.build.Name, metav1.GetOptions{})
-		if err != nil {
-			return nil, fmt.Errorf("Getting build while waiting for builder assignment: %s", err)
-		}
-
-		if len(build.Status.Builder) > 0 {
-			return build, nil
-		}
-
-		select {
-		case <-cancelCh:
-			return build, nil
-		default:
-			time.Sleep(1 * time.Second)
-		}
+	build, _, err := w.objWaiter.BuildBuilderAssigned(w.build.Namespace, w.build.Name, cancelCh)
+	if err != nil {
+		return nil, fmt.Errorf("Waiting for builder assignment: %s", err)
 	}
+
+	return w.buildOrOriginal(build), nil
 }
 
 func (w BuildWaiter) WaitForCompletion(cancelCh chan struct{}) (*v1alpha1.Build, error) {
-	for {
-		// TODO infinite retry
-
-		build, err := w.buildClient.BuildV1alpha1().Builds(w.build.Namespace).Get(w.build.Name, metav1.GetOptions{})
-		if err != nil {
-			return nil, fmt.Errorf("Getting build while waiting for completion: %s", err)
-		}
-
-		cond := build.Status.GetCondition(v1alpha1.BuildSucceeded)
-		if cond != nil {
-			switch cond.Status {
-			case corev1.ConditionTrue, corev1.ConditionFalse:
-				return build, nil
-			default:
-				// continue waiting
-			}
-		}
-
-		select {
-		case <-cancelCh:
-			return build, nil
-		default:
-			time.Sleep(1 * time.Second)
-		}
+	build, _, err := w.objWaiter.BuildCompleted(w.build.Namespace, w.build.Name, cancelCh)
+	if err != nil {
+		return nil, fmt.Errorf("Waiting for completion: %s", err)
+	}
+
+	return w.buildOrOriginal(build), nil
+}
+
+// buildOrOriginal returns build as given when it was not observed before cancellation
+func (w BuildWaiter) buildOrOriginal(build *v1alpha1.Build) *v1alpha1.Build {
+	if build == nil {
+		return w.build
 	}
+	return build
 }
 
+// WaitForClusterBuilderPodAssignment returns nil pod if cancelled before pod was observed
 func (w BuildWaiter) WaitForClusterBuilderPodAssignment(cancelCh chan struct{}) (*v1alpha1.Build, *corev1.Pod, error) {
-	var build *v1alpha1.Build
-
-	for {
-		// TODO infinite retry
-
-		var err error
-
-		build, err = w.buildClient.BuildV1alpha1().Builds(w.build.Namespace).Get(w.build.Name, metav1.GetOptions{})
-		if err != nil {
-			return nil, nil, fmt.Errorf("Getting build while waiting for cluster build to assign pod: %s", err)
-		}
-
-		if build.Status.Cluster != nil {
-			if len(build.Status.Cluster.Namespace) > 0 && len(build.Status.Cluster.PodName) > 0 {
-				break
-			}
-		}
-
-		select {
-		case <-cancelCh:
-			return build, nil, nil
-		default:
-			time.Sleep(1 * time.Second)
-		}
+	build, assigned, err := w.objWaiter.BuildClusterPodAssigned(w.build.Namespace, w.build.Name, cancelCh)
+	if err != nil {
+		return nil, nil, fmt.Errorf("Waiting for cluster build to assign pod: %s", err)
+	}
+
+	build = w.buildOrOriginal(build)
+
+	if !assigned {
+		return build, nil, nil
 	}
 
 	// Check if pod was initialized and is ready to be interacted via the API
-	for {
-		// TODO infinite retry
-		podsClient := w.podsGetterClient.Pods(build.Status.Cluster.Namespace)
-
-		pod, err := podsClient.Get(build.Status.Cluster.PodName, metav1.GetOptions{})
-		if err != nil {
-			if !errors.IsNotFound(err) {
-				return build, nil, fmt.Errorf("Getting assigned building pod: %s", err)
-			}
-		} else {
-			return build, pod, nil
-		}
-
-		select {
-		case <-cancelCh:
-			return build, pod, nil
-		default:
-			time.Sleep(1 * time.Second)
-		}
+	pod, _, err := w.objWaiter.Pod(build.Status.Cluster.Namespace,
+		build.Status.Cluster.PodName, waiter.Exists, cancelCh)
+	if err != nil {
+		return build, nil, fmt.Errorf("Waiting for assigned building pod: %s", err)
 	}
+
+	return build, pod, nil
 }
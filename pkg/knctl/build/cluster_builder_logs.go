@@ -43,13 +43,17 @@ func (l ClusterBuilderLogs) Tail(ui ui.UI, cancelCh chan struct{}) error { // TO
 		return fmt.Errorf("Waiting for build to be assigned a pod: %s", err)
 	}
 
+	if pod == nil {
+		return nil // cancelled
+	}
+
 	if build.Status.Cluster == nil {
 		return fmt.Errorf("Expected build to have cluster configuration assigned")
 	}
 
 	podsClient := l.podsGetterClient.Pods(build.Status.Cluster.Namespace)
 
-	statusWatcher := PodTerminalStatusWatcher{*pod, podsClient}
+	statusWatcher := PodTerminalStatusWatcher{*pod, podsClient, l.waiter.objWaiter}
 	cancelPodTailCh := make(chan struct{})
 
 	done, _, _ := statusWatcher.IsDone()
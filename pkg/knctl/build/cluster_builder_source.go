@@ -61,13 +61,15 @@ func (s ClusterBuilderSource) Upload(ui ui.UI, cancelCh chan struct{}) error { /
 		return fmt.Errorf("Waiting for build to be assigned a pod: %s", err)
 	}
 
+	if pod == nil {
+		return nil // cancelled
+	}
+
 	if build.Status.Cluster == nil {
 		return fmt.Errorf("Expected build to have cluster configuration assigned")
 	}
 
-	podsClient := s.coreClient.CoreV1().Pods(build.Status.Cluster.Namespace)
-
-	err = PodInitContainerRunningWatcher{*pod, podsClient, clusterBuilderCustomSourceStep}.Wait(cancelCh)
+	err = PodInitContainerRunningWatcher{*pod, s.waiter.objWaiter, clusterBuilderCustomSourceStep}.Wait(cancelCh)
 	if err != nil {
 		return fmt.Errorf("Waiting for init container: %s", err)
 	}
@@ -17,28 +17,28 @@ limitations under the License.
 package build
 
 import (
+	"github.com/cppforlife/knctl/pkg/knctl/waiter"
 	"github.com/knative/build/pkg/apis/build/v1alpha1"
-	buildclientset "github.com/knative/build/pkg/client/clientset/versioned"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 )
 
 type Factory struct {
-	buildClient buildclientset.Interface
-	coreClient  kubernetes.Interface
-	restConfig  *rest.Config
+	coreClient kubernetes.Interface
+	restConfig *rest.Config
+	objWaiter  *waiter.Waiter
 }
 
 func NewFactory(
-	buildClient buildclientset.Interface,
 	coreClient kubernetes.Interface,
 	restConfig *rest.Config,
+	objWaiter *waiter.Waiter,
 ) Factory {
-	return Factory{buildClient, coreClient, restConfig}
+	return Factory{coreClient, restConfig, objWaiter}
 }
 
 func (f Factory) New(build *v1alpha1.Build) Build {
-	waiter := NewBuildWaiter(build, f.buildClient, f.coreClient.CoreV1())
+	waiter := NewBuildWaiter(build, f.objWaiter)
 	logs := NewLogs(waiter, f.coreClient.CoreV1())
 	sourceFactory := NewSourceFactory(waiter, f.coreClient, f.restConfig)
 	return NewBuild(waiter, logs, sourceFactory)
@@ -17,42 +17,18 @@ limitations under the License.
 package build
 
 import (
-	"time"
-
+	"github.com/cppforlife/knctl/pkg/knctl/waiter"
 	corev1 "k8s.io/api/core/v1"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
 )
 
 type PodInitContainerRunningWatcher struct {
 	Pod           corev1.Pod
-	PodsClient    typedcorev1.PodInterface
+	Waiter        *waiter.Waiter
 	InitContainer string
 }
 
 func (l PodInitContainerRunningWatcher) Wait(cancelCh chan struct{}) error {
-	for {
-		// TODO infinite retry
-
-		pod, err := l.PodsClient.Get(l.Pod.Name, metav1.GetOptions{})
-		if err != nil {
-			return err
-		}
-
-		for _, status := range pod.Status.InitContainerStatuses {
-			if status.Name == l.InitContainer {
-				// TODO what if pod is no longer progressing?
-				if status.State.Running != nil {
-					return nil
-				}
-			}
-		}
-
-		select {
-		case <-cancelCh:
-			return nil
-		default:
-			time.Sleep(1 * time.Second)
-		}
-	}
+	_, _, err := l.Waiter.Pod(l.Pod.Namespace, l.Pod.Name,
+		waiter.IsPodInitContainerRunning(l.InitContainer), cancelCh)
+	return err
 }
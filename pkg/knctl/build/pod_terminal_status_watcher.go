@@ -17,8 +17,7 @@ limitations under the License.
 package build
 
 import (
-	"time"
-
+	"github.com/cppforlife/knctl/pkg/knctl/waiter"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
@@ -27,6 +26,7 @@ import (
 type PodTerminalStatusWatcher struct {
 	Pod        corev1.Pod
 	PodsClient typedcorev1.PodInterface
+	Waiter     *waiter.Waiter
 }
 
 func (l PodTerminalStatusWatcher) IsDone() (bool, corev1.PodPhase, error) {
@@ -35,29 +35,20 @@ func (l PodTerminalStatusWatcher) IsDone() (bool, corev1.PodPhase, error) {
 		return false, "", err
 	}
 
-	done := pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed
+	done, _ := waiter.IsPodTerminal(pod)
 
 	return done, pod.Status.Phase, nil
 }
 
 func (l PodTerminalStatusWatcher) Wait(cancelCh chan struct{}) (corev1.PodPhase, error) {
-	for {
-		// TODO infinite retry
-
-		done, phase, err := l.IsDone()
-		if err != nil {
-			return "", err
-		}
-
-		if done {
-			return phase, nil
-		}
-
-		select {
-		case <-cancelCh:
-			return phase, nil
-		default:
-			time.Sleep(1 * time.Second)
-		}
+	pod, _, err := l.Waiter.Pod(l.Pod.Namespace, l.Pod.Name, waiter.IsPodTerminal, cancelCh)
+	if err != nil {
+		return "", err
 	}
+
+	if pod == nil {
+		return "", nil
+	}
+
+	return pod.Status.Phase, nil
 }
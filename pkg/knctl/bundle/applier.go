@@ -0,0 +1,247 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bundle
+
+import (
+	"fmt"
+
+	ctlkube "github.com/cppforlife/knctl/pkg/knctl/kube"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/dynamic"
+)
+
+const (
+	// AppliedLabelKey marks resources applied from a manifest directory so that
+	// resources removed from it could be pruned (other objects created
+	// by knctl are labeled with ctlkube.ManagedByLabelKey only)
+	AppliedLabelKey   = "cli.knative.dev/bundle"
+	AppliedLabelValue = "true"
+)
+
+type ChangeAction string
+
+const (
+	ChangeActionCreate ChangeAction = "created"
+	ChangeActionUpdate ChangeAction = "updated"
+	ChangeActionDelete ChangeAction = "deleted"
+)
+
+type Change struct {
+	Kind   string
+	Name   string
+	Action ChangeAction
+
+	// Previous is nil for created resources;
+	// Current is nil for deleted resources
+	Previous *unstructured.Unstructured
+	Current  *unstructured.Unstructured
+}
+
+type Applier struct {
+	dynamicClient dynamic.Interface
+	resources     []Resource
+}
+
+func NewApplier(dynamicClient dynamic.Interface, resources []Resource) Applier {
+	return Applier{dynamicClient, resources}
+}
+
+// Apply creates or updates given objects in a namespace.
+// All objects are checked to be Knative resources before any changes are made.
+func (a Applier) Apply(nsName string, objs []unstructured.Unstructured) ([]Change, error) {
+	var ress []Resource
+
+	for _, obj := range objs {
+		res, err := a.resourceFor(obj)
+		if err != nil {
+			return nil, err
+		}
+		if ns := obj.GetNamespace(); len(ns) > 0 && ns != nsName {
+			return nil, fmt.Errorf("Expected %s '%s' to be in namespace '%s' but was in '%s'",
+				obj.GetKind(), obj.GetName(), nsName, ns)
+		}
+		ress = append(ress, res)
+	}
+
+	var changes []Change
+
+	for i, obj := range objs {
+		change, err := a.apply(nsName, ress[i], obj)
+		if err != nil {
+			return changes, err
+		}
+
+		changes = append(changes, change)
+	}
+
+	return changes, nil
+}
+
+func (a Applier) apply(nsName string, res Resource, obj unstructured.Unstructured) (Change, error) {
+	obj = *obj.DeepCopy()
+	obj.SetNamespace(nsName)
+
+	lbls := obj.GetLabels()
+	if lbls == nil {
+		lbls = map[string]string{}
+	}
+	lbls[ctlkube.ManagedByLabelKey] = ctlkube.ManagedByLabelValue
+	lbls[AppliedLabelKey] = AppliedLabelValue
+	obj.SetLabels(lbls)
+
+	change := Change{Kind: obj.GetKind(), Name: obj.GetName()}
+	client := a.dynamicClient.Resource(res.GroupVersionResource).Namespace(nsName)
+
+	existingObj, err := client.Get(obj.GetName(), metav1.GetOptions{})
+	if err != nil {
+		if !errors.IsNotFound(err) {
+			return change, fmt.Errorf("Getting %s '%s': %s", change.Kind, change.Name, err)
+		}
+
+		change.Current, err = client.Create(&obj)
+		if err != nil {
+			return change, fmt.Errorf("Creating %s '%s': %s", change.Kind, change.Name, err)
+		}
+
+		change.Action = ChangeActionCreate
+		return change, nil
+	}
+
+	obj.SetResourceVersion(existingObj.GetResourceVersion())
+
+	// Preserve annotations populated by controllers and webhooks
+	existingAnns := existingObj.GetAnnotations()
+	anns := obj.GetAnnotations()
+
+	for _, key := range systemAnnotations {
+		if val, found := existingAnns[key]; found {
+			if anns == nil {
+				anns = map[string]string{}
+			}
+			anns[key] = val
+		}
+	}
+
+	obj.SetAnnotations(anns)
+
+	change.Current, err = client.Update(&obj)
+	if err != nil {
+		return change, fmt.Errorf("Updating %s '%s': %s", change.Kind, change.Name, err)
+	}
+
+	change.Action = ChangeActionUpdate
+	change.Previous = existingObj
+	return change, nil
+}
+
+// Prune deletes knctl managed resources in a namespace
+// that are not included in given objects. Only kinds present in given objects
+// are considered, and objects controlled by other objects (e.g. routes and
+// configurations created for a service, which inherit its labels) are skipped.
+func (a Applier) Prune(nsName string, objs []unstructured.Unstructured) ([]Change, error) {
+	keep := map[string]struct{}{}
+	kinds := map[string]struct{}{}
+
+	for _, obj := range objs {
+		keep[a.objKey(obj)] = struct{}{}
+		kinds[a.kindKey(obj.GroupVersionKind().Group, obj.GetKind())] = struct{}{}
+	}
+
+	listOpts := metav1.ListOptions{
+		LabelSelector: labels.Set(map[string]string{
+			ctlkube.ManagedByLabelKey: ctlkube.ManagedByLabelValue,
+			AppliedLabelKey:           AppliedLabelValue,
+		}).String(),
+	}
+
+	var changes []Change
+
+	for _, res := range a.resources {
+		if _, found := kinds[a.kindKey(res.Group, res.Kind)]; !found {
+			continue
+		}
+
+		client := a.dynamicClient.Resource(res.GroupVersionResource).Namespace(nsName)
+
+		list, err := client.List(listOpts)
+		if err != nil {
+			return changes, fmt.Errorf("Listing %s: %s", res.GroupResource(), err)
+		}
+
+		for _, obj := range list.Items {
+			if _, found := keep[a.objKey(obj)]; found {
+				continue
+			}
+			if metav1.GetControllerOf(&obj) != nil {
+				continue
+			}
+
+			err := client.Delete(obj.GetName(), &metav1.DeleteOptions{})
+			if err != nil && !errors.IsNotFound(err) {
+				return changes, fmt.Errorf("Deleting %s '%s': %s", obj.GetKind(), obj.GetName(), err)
+			}
+
+			changes = append(changes, Change{
+				Kind:     obj.GetKind(),
+				Name:     obj.GetName(),
+				Action:   ChangeActionDelete,
+				Previous: obj.DeepCopy(),
+			})
+		}
+	}
+
+	return changes, nil
+}
+
+// Partition separates objects that are supported Knative resources from the rest
+func (a Applier) Partition(objs []unstructured.Unstructured) ([]unstructured.Unstructured, []unstructured.Unstructured) {
+	var supported, unsupported []unstructured.Unstructured
+
+	for _, obj := range objs {
+		if _, err := a.resourceFor(obj); err == nil {
+			supported = append(supported, obj)
+		} else {
+			unsupported = append(unsupported, obj)
+		}
+	}
+
+	return supported, unsupported
+}
+
+func (a Applier) resourceFor(obj unstructured.Unstructured) (Resource, error) {
+	gvk := obj.GroupVersionKind()
+
+	for _, res := range a.resources {
+		if res.GroupVersion() == gvk.GroupVersion() && res.Kind == gvk.Kind {
+			return res, nil
+		}
+	}
+
+	return Resource{}, fmt.Errorf("Expected %s '%s' (%s) to be a supported Knative resource",
+		obj.GetKind(), obj.GetName(), obj.GetAPIVersion())
+}
+
+func (a Applier) objKey(obj unstructured.Unstructured) string {
+	return a.kindKey(obj.GroupVersionKind().Group, obj.GetKind()) + "/" + obj.GetName()
+}
+
+func (Applier) kindKey(group, kind string) string {
+	return group + "/" + kind
+}
@@ -0,0 +1,100 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bundle_test
+
+import (
+	"testing"
+
+	"github.com/cppforlife/knctl/pkg/knctl/bundle"
+	ctlkube "github.com/cppforlife/knctl/pkg/knctl/kube"
+	"github.com/cppforlife/knctl/pkg/knctl/testkit"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/dynamic"
+)
+
+func TestApplierPrune(t *testing.T) {
+	managedLabels := map[string]string{
+		ctlkube.ManagedByLabelKey: ctlkube.ManagedByLabelValue,
+		bundle.AppliedLabelKey:    bundle.AppliedLabelValue,
+	}
+	isController := true
+
+	svc1 := testkit.NewService("ns1", "svc1").Build()
+	svc1.Labels = managedLabels
+
+	svc2 := testkit.NewService("ns1", "svc2").Build()
+	svc2.Labels = managedLabels
+
+	// Service created by other knctl commands (e.g. deploy) is not pruned
+	deployedSvc := testkit.NewService("ns1", "svc3").Build()
+	deployedSvc.Labels = map[string]string{ctlkube.ManagedByLabelKey: ctlkube.ManagedByLabelValue}
+
+	// Route created by service controller inherits service labels
+	ownedRoute := testkit.NewRoute("ns1", "svc1").Build()
+	ownedRoute.Labels = managedLabels
+	ownedRoute.OwnerReferences = []metav1.OwnerReference{{
+		APIVersion: "serving.knative.dev/v1alpha1", Kind: "Service", Name: "svc1", Controller: &isController,
+	}}
+
+	// Standalone route is not pruned since manifest does not contain routes
+	standaloneRoute := testkit.NewRoute("ns1", "route1").Build()
+	standaloneRoute.Labels = managedLabels
+
+	cluster := testkit.NewCluster(t, svc1, svc2, deployedSvc, ownedRoute, standaloneRoute)
+
+	dynamicClient, err := dynamic.NewForConfig(cluster.RESTConfig())
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	applier := bundle.NewApplier(dynamicClient, []bundle.Resource{bundle.ServicesResource, bundle.RoutesResource})
+
+	manifestSvc := unstructured.Unstructured{}
+	manifestSvc.SetAPIVersion("serving.knative.dev/v1alpha1")
+	manifestSvc.SetKind("Service")
+	manifestSvc.SetName("svc1")
+
+	changes, err := applier.Prune("ns1", []unstructured.Unstructured{manifestSvc})
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	if len(changes) != 1 || changes[0].Kind != "Service" || changes[0].Name != "svc2" {
+		t.Fatalf("Expected only service 'svc2' to be pruned, but was: %#v", changes)
+	}
+
+	servingV1alpha1 := cluster.ServingClient().ServingV1alpha1()
+
+	_, err = servingV1alpha1.Services("ns1").Get("svc2", metav1.GetOptions{})
+	if !errors.IsNotFound(err) {
+		t.Fatalf("Expected service 'svc2' to be deleted, but was: %v", err)
+	}
+
+	_, err = servingV1alpha1.Services("ns1").Get("svc3", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Expected service 'svc3' to be kept: %s", err)
+	}
+
+	for _, name := range []string{"svc1", "route1"} {
+		_, err = servingV1alpha1.Routes("ns1").Get(name, metav1.GetOptions{})
+		if err != nil {
+			t.Fatalf("Expected route '%s' to be kept: %s", name, err)
+		}
+	}
+}
@@ -0,0 +1,63 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bundle
+
+import (
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+var (
+	// Metadata fields populated by the API server
+	systemMetadataFields = []string{
+		"namespace", "uid", "resourceVersion", "generation", "creationTimestamp",
+		"deletionTimestamp", "deletionGracePeriodSeconds", "selfLink", "managedFields",
+		"ownerReferences", "finalizers", "initializers", "clusterName",
+	}
+
+	// Annotations populated by controllers and webhooks
+	systemAnnotations = []string{
+		"serving.knative.dev/creator",
+		"serving.knative.dev/lastModifier",
+		"kubectl.kubernetes.io/last-applied-configuration",
+	}
+)
+
+// Clean returns a copy of the object stripped of status and server populated
+// metadata so that it could be applied to any namespace or cluster
+func Clean(obj unstructured.Unstructured) unstructured.Unstructured {
+	obj = *obj.DeepCopy()
+
+	unstructured.RemoveNestedField(obj.Object, "status")
+
+	for _, field := range systemMetadataFields {
+		unstructured.RemoveNestedField(obj.Object, "metadata", field)
+	}
+
+	anns := obj.GetAnnotations()
+	if anns != nil {
+		for _, key := range systemAnnotations {
+			delete(anns, key)
+		}
+		if len(anns) == 0 {
+			unstructured.RemoveNestedField(obj.Object, "metadata", "annotations")
+		} else {
+			obj.SetAnnotations(anns)
+		}
+	}
+
+	return obj
+}
@@ -0,0 +1,67 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bundle_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/cppforlife/knctl/pkg/knctl/bundle"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestClean(t *testing.T) {
+	obj := unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "serving.knative.dev/v1alpha1",
+		"kind":       "Service",
+		"metadata": map[string]interface{}{
+			"name":              "app",
+			"namespace":         "ns",
+			"uid":               "uid",
+			"resourceVersion":   "123",
+			"generation":        int64(2),
+			"creationTimestamp": "2018-01-01T00:00:00Z",
+			"labels":            map[string]interface{}{"key": "val"},
+			"annotations": map[string]interface{}{
+				"serving.knative.dev/creator":      "user",
+				"serving.knative.dev/lastModifier": "user",
+			},
+		},
+		"spec":   map[string]interface{}{"runLatest": map[string]interface{}{}},
+		"status": map[string]interface{}{"domain": "app.ns.example.com"},
+	}}
+
+	result := bundle.Clean(obj)
+
+	expected := map[string]interface{}{
+		"apiVersion": "serving.knative.dev/v1alpha1",
+		"kind":       "Service",
+		"metadata": map[string]interface{}{
+			"name":   "app",
+			"labels": map[string]interface{}{"key": "val"},
+		},
+		"spec": map[string]interface{}{"runLatest": map[string]interface{}{}},
+	}
+
+	if !reflect.DeepEqual(result.Object, expected) {
+		t.Fatalf("Expected cleaned object to match: %#v", result.Object)
+	}
+
+	if _, found := obj.Object["status"]; !found {
+		t.Fatalf("Expected original object to be unmodified")
+	}
+}
@@ -0,0 +1,108 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bundle
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+type FieldDiff struct {
+	Path     string
+	Previous string
+	Current  string
+}
+
+// Diff compares cleaned versions of two objects and returns
+// differences for each changed leaf field sorted by path.
+// Either object may be nil (e.g. resource is created or deleted).
+func Diff(previous, current *unstructured.Unstructured) []FieldDiff {
+	prevFields := map[string]interface{}{}
+	currFields := map[string]interface{}{}
+
+	if previous != nil {
+		flattenFields("", Clean(*previous).Object, prevFields)
+	}
+	if current != nil {
+		flattenFields("", Clean(*current).Object, currFields)
+	}
+
+	var diffs []FieldDiff
+
+	for path, prevVal := range prevFields {
+		currVal, found := currFields[path]
+		if !found {
+			diffs = append(diffs, FieldDiff{Path: path, Previous: fieldString(prevVal)})
+		} else if !reflect.DeepEqual(prevVal, currVal) {
+			diffs = append(diffs, FieldDiff{Path: path, Previous: fieldString(prevVal), Current: fieldString(currVal)})
+		}
+	}
+
+	for path, currVal := range currFields {
+		if _, found := prevFields[path]; !found {
+			diffs = append(diffs, FieldDiff{Path: path, Current: fieldString(currVal)})
+		}
+	}
+
+	sort.Slice(diffs, func(i, j int) bool { return diffs[i].Path < diffs[j].Path })
+
+	return diffs
+}
+
+func flattenFields(prefix string, val interface{}, result map[string]interface{}) {
+	switch typedVal := val.(type) {
+	case map[string]interface{}:
+		if len(typedVal) == 0 && len(prefix) > 0 {
+			result[prefix] = typedVal
+		}
+		for k, v := range typedVal {
+			path := k
+			if len(prefix) > 0 {
+				path = prefix + "." + k
+			}
+			flattenFields(path, v, result)
+		}
+
+	case []interface{}:
+		if len(typedVal) == 0 {
+			result[prefix] = typedVal
+		}
+		for i, v := range typedVal {
+			flattenFields(fmt.Sprintf("%s[%d]", prefix, i), v, result)
+		}
+
+	default:
+		result[prefix] = typedVal
+	}
+}
+
+func fieldString(val interface{}) string {
+	if str, ok := val.(string); ok {
+		return str
+	}
+
+	bs, err := json.Marshal(val)
+	if err != nil {
+		return fmt.Sprintf("%v", val)
+	}
+
+	return string(bs)
+}
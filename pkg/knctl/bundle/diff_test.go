@@ -0,0 +1,66 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bundle_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/cppforlife/knctl/pkg/knctl/bundle"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestDiff(t *testing.T) {
+	previous := &unstructured.Unstructured{Object: map[string]interface{}{
+		"metadata": map[string]interface{}{"name": "app", "resourceVersion": "1"},
+		"spec": map[string]interface{}{
+			"image": "img:1",
+			"env":   []interface{}{map[string]interface{}{"name": "A", "value": "1"}},
+			"old":   true,
+		},
+		"status": map[string]interface{}{"ready": true},
+	}}
+
+	current := &unstructured.Unstructured{Object: map[string]interface{}{
+		"metadata": map[string]interface{}{"name": "app", "resourceVersion": "2"},
+		"spec": map[string]interface{}{
+			"image": "img:2",
+			"env":   []interface{}{map[string]interface{}{"name": "A", "value": "1"}},
+			"new":   int64(5),
+		},
+	}}
+
+	expected := []bundle.FieldDiff{
+		{Path: "spec.image", Previous: "img:1", Current: "img:2"},
+		{Path: "spec.new", Current: "5"},
+		{Path: "spec.old", Previous: "true"},
+	}
+
+	diffs := bundle.Diff(previous, current)
+
+	if !reflect.DeepEqual(diffs, expected) {
+		t.Fatalf("Expected diffs %#v, but was %#v", expected, diffs)
+	}
+
+	if len(bundle.Diff(previous, previous)) != 0 {
+		t.Fatalf("Expected no diffs for the same object")
+	}
+
+	if len(bundle.Diff(nil, current)) != 5 {
+		t.Fatalf("Expected all fields to be added: %#v", bundle.Diff(nil, current))
+	}
+}
@@ -0,0 +1,87 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bundle
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/dynamic"
+)
+
+type Exporter struct {
+	dynamicClient dynamic.Interface
+	resources     []Resource
+}
+
+func NewExporter(dynamicClient dynamic.Interface, resources []Resource) Exporter {
+	return Exporter{dynamicClient, resources}
+}
+
+// Export returns cleaned objects for all resources in a namespace
+func (e Exporter) Export(nsName string) ([]unstructured.Unstructured, error) {
+	var result []unstructured.Unstructured
+
+	for _, res := range e.resources {
+		list, err := e.dynamicClient.Resource(res.GroupVersionResource).Namespace(nsName).List(metav1.ListOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("Listing %s: %s", res.GroupResource(), err)
+		}
+
+		for _, obj := range list.Items {
+			if res.Includes(obj) {
+				result = append(result, Clean(obj))
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// WriteDir writes each object into its own YAML file named '<kind>-<name>.yml'
+func (e Exporter) WriteDir(dir string, objs []unstructured.Unstructured) ([]string, error) {
+	err := os.MkdirAll(dir, 0755)
+	if err != nil {
+		return nil, fmt.Errorf("Creating output directory: %s", err)
+	}
+
+	var paths []string
+
+	for _, obj := range objs {
+		bs, err := yaml.Marshal(obj.Object)
+		if err != nil {
+			return nil, fmt.Errorf("Serializing %s '%s': %s", obj.GetKind(), obj.GetName(), err)
+		}
+
+		path := filepath.Join(dir, fmt.Sprintf("%s-%s.yml", strings.ToLower(obj.GetKind()), obj.GetName()))
+
+		err = ioutil.WriteFile(path, bs, 0644)
+		if err != nil {
+			return nil, fmt.Errorf("Writing file '%s': %s", path, err)
+		}
+
+		paths = append(paths, path)
+	}
+
+	return paths, nil
+}
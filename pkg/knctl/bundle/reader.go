@@ -0,0 +1,122 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bundle
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+var (
+	docSeparatorRegexp = regexp.MustCompile("(?m)^---\\s*$")
+)
+
+// ReadPath reads objects from a single YAML file or from all
+// YAML files ('.yml' or '.yaml') in a directory (non-recursively).
+// Files may contain multiple documents separated with '---'.
+func ReadPath(path string) ([]unstructured.Unstructured, error) {
+	fileInfo, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("Checking path '%s': %s", path, err)
+	}
+
+	paths := []string{path}
+
+	if fileInfo.IsDir() {
+		paths, err = yamlFilesInDir(path)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var result []unstructured.Unstructured
+
+	for _, path := range paths {
+		bs, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("Reading file '%s': %s", path, err)
+		}
+
+		objs, err := parseDocs(bs)
+		if err != nil {
+			return nil, fmt.Errorf("Parsing file '%s': %s", path, err)
+		}
+
+		result = append(result, objs...)
+	}
+
+	return result, nil
+}
+
+func yamlFilesInDir(dir string) ([]string, error) {
+	fileInfos, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("Listing directory '%s': %s", dir, err)
+	}
+
+	var paths []string
+
+	for _, fileInfo := range fileInfos {
+		ext := filepath.Ext(fileInfo.Name())
+		if !fileInfo.IsDir() && (ext == ".yml" || ext == ".yaml") {
+			paths = append(paths, filepath.Join(dir, fileInfo.Name()))
+		}
+	}
+
+	sort.Strings(paths)
+
+	return paths, nil
+}
+
+func parseDocs(bs []byte) ([]unstructured.Unstructured, error) {
+	var result []unstructured.Unstructured
+
+	for i, doc := range docSeparatorRegexp.Split(string(bs), -1) {
+		if len(strings.TrimSpace(doc)) == 0 {
+			continue
+		}
+
+		var obj map[string]interface{}
+
+		err := yaml.Unmarshal([]byte(doc), &obj)
+		if err != nil {
+			return nil, fmt.Errorf("Unmarshaling document %d: %s", i, err)
+		}
+
+		if len(obj) == 0 {
+			continue // e.g. comments only
+		}
+
+		uns := unstructured.Unstructured{Object: obj}
+
+		if len(uns.GetAPIVersion()) == 0 || len(uns.GetKind()) == 0 || len(uns.GetName()) == 0 {
+			return nil, fmt.Errorf("Expected document %d to specify apiVersion, kind and metadata.name", i)
+		}
+
+		result = append(result, uns)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,105 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bundle_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/cppforlife/knctl/pkg/knctl/bundle"
+)
+
+func TestReadPath(t *testing.T) {
+	dir, err := ioutil.TempDir("", "knctl-bundle")
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	defer os.RemoveAll(dir)
+
+	files := map[string]string{
+		"b.yml": `
+apiVersion: serving.knative.dev/v1alpha1
+kind: Service
+metadata:
+  name: svc2
+---
+# only comments
+---
+apiVersion: serving.knative.dev/v1alpha1
+kind: Route
+metadata:
+  name: route1
+`,
+		"a.yaml": `
+apiVersion: serving.knative.dev/v1alpha1
+kind: Service
+metadata:
+  name: svc1
+`,
+		"README.md": "not yaml",
+	}
+
+	for name, content := range files {
+		err := ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0600)
+		if err != nil {
+			t.Fatalf("Expected no error: %s", err)
+		}
+	}
+
+	objs, err := bundle.ReadPath(dir)
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	var names []string
+	for _, obj := range objs {
+		names = append(names, obj.GetKind()+"/"+obj.GetName())
+	}
+
+	expected := []string{"Service/svc1", "Service/svc2", "Route/route1"}
+
+	if len(names) != len(expected) {
+		t.Fatalf("Expected objects %#v, but was %#v", expected, names)
+	}
+	for i := range expected {
+		if names[i] != expected[i] {
+			t.Fatalf("Expected objects %#v, but was %#v", expected, names)
+		}
+	}
+}
+
+func TestReadPath_MissingName(t *testing.T) {
+	file, err := ioutil.TempFile("", "knctl-bundle")
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	defer os.Remove(file.Name())
+
+	_, err = file.Write([]byte("apiVersion: v1\nkind: Service\n"))
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	_, err = bundle.ReadPath(file.Name())
+	if err == nil {
+		t.Fatalf("Expected error")
+	}
+}
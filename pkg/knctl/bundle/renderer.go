@@ -0,0 +1,126 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bundle
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+var (
+	kustomizationFileNames = []string{"kustomization.yaml", "kustomization.yml", "Kustomization"}
+	helmChartFileName      = "Chart.yaml"
+)
+
+type InputType string
+
+const (
+	InputTypeYAML          InputType = "yaml"
+	InputTypeKustomization InputType = "kustomization"
+	InputTypeHelmChart     InputType = "helm-chart"
+)
+
+// Renderer reads objects from plain YAML files, kustomization directories
+// (via 'kubectl kustomize') or Helm charts (via 'helm template')
+type Renderer struct {
+	HelmValues []string
+}
+
+func (r Renderer) InputType(path string) (InputType, error) {
+	fileInfo, err := os.Stat(path)
+	if err != nil {
+		return "", fmt.Errorf("Checking path '%s': %s", path, err)
+	}
+
+	if !fileInfo.IsDir() {
+		return InputTypeYAML, nil
+	}
+
+	for _, name := range kustomizationFileNames {
+		if r.fileExists(filepath.Join(path, name)) {
+			return InputTypeKustomization, nil
+		}
+	}
+
+	if r.fileExists(filepath.Join(path, helmChartFileName)) {
+		return InputTypeHelmChart, nil
+	}
+
+	return InputTypeYAML, nil
+}
+
+func (r Renderer) Render(path string) ([]unstructured.Unstructured, InputType, error) {
+	inputType, err := r.InputType(path)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if inputType != InputTypeHelmChart && len(r.HelmValues) > 0 {
+		return nil, "", fmt.Errorf("Expected Helm values to be provided only for Helm charts")
+	}
+
+	var objs []unstructured.Unstructured
+
+	switch inputType {
+	case InputTypeKustomization:
+		objs, err = r.renderCmd("kubectl", []string{"kustomize", path})
+
+	case InputTypeHelmChart:
+		args := []string{"template", path}
+		for _, val := range r.HelmValues {
+			args = append(args, "--set", val)
+		}
+		objs, err = r.renderCmd("helm", args)
+
+	default:
+		objs, err = ReadPath(path)
+	}
+
+	return objs, inputType, err
+}
+
+func (r Renderer) renderCmd(name string, args []string) ([]unstructured.Unstructured, error) {
+	var stdout, stderr bytes.Buffer
+
+	cmd := exec.Command(name, args...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	err := cmd.Run()
+	if err != nil {
+		return nil, fmt.Errorf("Running '%s %s': %s (stderr: %s)",
+			name, strings.Join(args, " "), err, strings.TrimSpace(stderr.String()))
+	}
+
+	objs, err := parseDocs(stdout.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("Parsing output of '%s': %s", name, err)
+	}
+
+	return objs, nil
+}
+
+func (Renderer) fileExists(path string) bool {
+	fileInfo, err := os.Stat(path)
+	return err == nil && !fileInfo.IsDir()
+}
@@ -0,0 +1,73 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bundle_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/cppforlife/knctl/pkg/knctl/bundle"
+)
+
+func TestRendererInputType(t *testing.T) {
+	dir, err := ioutil.TempDir("", "knctl-bundle")
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	defer os.RemoveAll(dir)
+
+	for _, name := range []string{"plain", "kust", "chart"} {
+		err := os.Mkdir(filepath.Join(dir, name), 0700)
+		if err != nil {
+			t.Fatalf("Expected no error: %s", err)
+		}
+	}
+
+	files := []string{"plain/svc.yml", "kust/kustomization.yaml", "chart/Chart.yaml"}
+
+	for _, name := range files {
+		err := ioutil.WriteFile(filepath.Join(dir, name), []byte{}, 0600)
+		if err != nil {
+			t.Fatalf("Expected no error: %s", err)
+		}
+	}
+
+	expected := map[string]bundle.InputType{
+		"plain/svc.yml": bundle.InputTypeYAML,
+		"plain":         bundle.InputTypeYAML,
+		"kust":          bundle.InputTypeKustomization,
+		"chart":         bundle.InputTypeHelmChart,
+	}
+
+	for path, expectedType := range expected {
+		inputType, err := bundle.Renderer{}.InputType(filepath.Join(dir, path))
+		if err != nil {
+			t.Fatalf("Expected no error: %s", err)
+		}
+		if inputType != expectedType {
+			t.Fatalf("Expected path '%s' to be of type '%s' but was '%s'", path, expectedType, inputType)
+		}
+	}
+
+	_, _, err = bundle.Renderer{HelmValues: []string{"a=b"}}.Render(filepath.Join(dir, "plain"))
+	if err == nil {
+		t.Fatalf("Expected error for Helm values with non-chart input")
+	}
+}
@@ -0,0 +1,131 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bundle
+
+import (
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+)
+
+var (
+	ServicesResource = Resource{
+		GroupVersionResource: schema.GroupVersionResource{Group: "serving.knative.dev", Version: "v1alpha1", Resource: "services"},
+		Kind:                 "Service",
+	}
+
+	// Routes created by services are reproduced by applying services,
+	// hence only standalone (customized) routes are included
+	RoutesResource = Resource{
+		GroupVersionResource: schema.GroupVersionResource{Group: "serving.knative.dev", Version: "v1alpha1", Resource: "routes"},
+		Kind:                 "Route",
+		Standalone:           true,
+	}
+
+	TriggersResource = Resource{
+		GroupVersionResource: schema.GroupVersionResource{Group: "eventing.knative.dev", Version: "v1alpha1", Resource: "triggers"},
+		Kind:                 "Trigger",
+	}
+
+	sourcesGroupVersion = schema.GroupVersion{Group: "sources.eventing.knative.dev", Version: "v1alpha1"}
+)
+
+type Resource struct {
+	schema.GroupVersionResource
+	Kind string
+
+	// Standalone resources exclude objects owned by other objects
+	Standalone bool
+}
+
+func (r Resource) Includes(obj unstructured.Unstructured) bool {
+	return !r.Standalone || len(obj.GetOwnerReferences()) == 0
+}
+
+// Resources returns Knative resources that make up namespace's estate.
+// Resources from API groups that are not installed in the cluster are omitted.
+func Resources(discoveryClient discovery.DiscoveryInterface) ([]Resource, error) {
+	candidates := []Resource{ServicesResource, RoutesResource, TriggersResource}
+
+	sources, err := sourceResources(discoveryClient)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []Resource
+
+	for _, res := range append(candidates, sources...) {
+		served, err := servedResources(discoveryClient, res.GroupVersion())
+		if err != nil {
+			return nil, err
+		}
+
+		if _, found := served[res.Resource]; found {
+			result = append(result, res)
+		}
+	}
+
+	return result, nil
+}
+
+func sourceResources(discoveryClient discovery.DiscoveryInterface) ([]Resource, error) {
+	resList, err := discoveryClient.ServerResourcesForGroupVersion(sourcesGroupVersion.String())
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("Listing resources for '%s': %s", sourcesGroupVersion, err)
+	}
+
+	var result []Resource
+
+	for _, apiRes := range resList.APIResources {
+		// Skip subresources (e.g. 'containersources/status')
+		if !apiRes.Namespaced || strings.Contains(apiRes.Name, "/") {
+			continue
+		}
+
+		result = append(result, Resource{
+			GroupVersionResource: sourcesGroupVersion.WithResource(apiRes.Name),
+			Kind:                 apiRes.Kind,
+		})
+	}
+
+	return result, nil
+}
+
+func servedResources(discoveryClient discovery.DiscoveryInterface, gv schema.GroupVersion) (map[string]struct{}, error) {
+	resList, err := discoveryClient.ServerResourcesForGroupVersion(gv.String())
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("Listing resources for '%s': %s", gv, err)
+	}
+
+	result := map[string]struct{}{}
+
+	for _, apiRes := range resList.APIResources {
+		result[apiRes.Name] = struct{}{}
+	}
+
+	return result, nil
+}
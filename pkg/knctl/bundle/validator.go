@@ -0,0 +1,171 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bundle
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/knative/pkg/apis"
+	"github.com/knative/serving/pkg/apis/autoscaling"
+	"github.com/knative/serving/pkg/apis/serving/v1alpha1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+const (
+	autoscalingTargetAnnotationKey = autoscaling.GroupName + "/target"
+)
+
+var (
+	knownAutoscalingClasses = []string{"kpa.autoscaling.knative.dev", "hpa.autoscaling.knative.dev"}
+)
+
+type ValidationIssue struct {
+	Kind    string
+	Name    string
+	Message string
+}
+
+// Validator checks Knative manifests without contacting a cluster.
+// Objects are strictly decoded into Knative API types (hence unknown
+// or mistyped fields are reported), then validated with the same rules
+// that Knative's admission webhook uses and with knctl's own rules.
+type Validator struct{}
+
+func NewValidator() Validator { return Validator{} }
+
+// Validate returns found issues and objects whose kinds cannot be validated offline.
+func (v Validator) Validate(objs []unstructured.Unstructured) ([]ValidationIssue, []unstructured.Unstructured) {
+	var issues []ValidationIssue
+	var skipped []unstructured.Unstructured
+
+	for _, obj := range objs {
+		validatable := v.typedObj(obj)
+		if validatable == nil {
+			skipped = append(skipped, obj)
+			continue
+		}
+
+		var msgs []string
+
+		err := v.strictDecode(obj, validatable)
+		if err != nil {
+			msgs = append(msgs, fmt.Sprintf("Expected object to match schema: %s", err))
+		} else {
+			msgs = append(msgs, v.fieldErrorMsgs(validatable.Validate())...)
+			msgs = append(msgs, v.annotationMsgs(validatable)...)
+		}
+
+		for _, msg := range msgs {
+			issues = append(issues, ValidationIssue{Kind: obj.GetKind(), Name: obj.GetName(), Message: msg})
+		}
+	}
+
+	return issues, skipped
+}
+
+func (Validator) typedObj(obj unstructured.Unstructured) apis.Validatable {
+	if obj.GetAPIVersion() != v1alpha1.SchemeGroupVersion.String() {
+		return nil
+	}
+
+	switch obj.GetKind() {
+	case "Service":
+		return &v1alpha1.Service{}
+	case "Configuration":
+		return &v1alpha1.Configuration{}
+	case "Route":
+		return &v1alpha1.Route{}
+	default:
+		return nil
+	}
+}
+
+func (Validator) strictDecode(obj unstructured.Unstructured, typedObj interface{}) error {
+	bs, err := obj.MarshalJSON()
+	if err != nil {
+		return fmt.Errorf("Marshaling object: %s", err)
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(bs))
+	decoder.DisallowUnknownFields()
+
+	return decoder.Decode(typedObj)
+}
+
+func (Validator) fieldErrorMsgs(fieldErr *apis.FieldError) []string {
+	if fieldErr == nil {
+		return nil
+	}
+	return strings.Split(fieldErr.Error(), "\n")
+}
+
+// annotationMsgs checks annotations that Knative's webhook does not
+// validate but that would otherwise silently misconfigure autoscaling
+func (v Validator) annotationMsgs(obj apis.Validatable) []string {
+	var msgs []string
+
+	for path, anns := range v.revisionTemplateAnnotations(obj) {
+		if val, found := anns[autoscalingTargetAnnotationKey]; found {
+			num, err := strconv.ParseFloat(val, 64)
+			if err != nil || num <= 0 {
+				msgs = append(msgs, fmt.Sprintf("Expected annotation '%s' to be a number greater than 0: %s",
+					autoscalingTargetAnnotationKey, path))
+			}
+		}
+
+		if val, found := anns[autoscaling.ClassAnnotationKey]; found {
+			var known bool
+			for _, class := range knownAutoscalingClasses {
+				if val == class {
+					known = true
+				}
+			}
+			if !known {
+				msgs = append(msgs, fmt.Sprintf("Expected annotation '%s' to be one of '%s': %s",
+					autoscaling.ClassAnnotationKey, strings.Join(knownAutoscalingClasses, "', '"), path))
+			}
+		}
+	}
+
+	return msgs
+}
+
+func (Validator) revisionTemplateAnnotations(obj apis.Validatable) map[string]map[string]string {
+	result := map[string]map[string]string{}
+
+	const annsPath = "configuration.revisionTemplate.metadata.annotations"
+
+	switch typedObj := obj.(type) {
+	case *v1alpha1.Service:
+		switch {
+		case typedObj.Spec.RunLatest != nil:
+			result["spec.runLatest."+annsPath] = typedObj.Spec.RunLatest.Configuration.RevisionTemplate.Annotations
+		case typedObj.Spec.Pinned != nil:
+			result["spec.pinned."+annsPath] = typedObj.Spec.Pinned.Configuration.RevisionTemplate.Annotations
+		case typedObj.Spec.Release != nil:
+			result["spec.release."+annsPath] = typedObj.Spec.Release.Configuration.RevisionTemplate.Annotations
+		}
+	case *v1alpha1.Configuration:
+		result["spec.revisionTemplate.metadata.annotations"] = typedObj.Spec.RevisionTemplate.Annotations
+	}
+
+	return result
+}
@@ -0,0 +1,132 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bundle_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/cppforlife/knctl/pkg/knctl/bundle"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestValidatorValidate(t *testing.T) {
+	objs := readValidatorObjs(t, `
+apiVersion: serving.knative.dev/v1alpha1
+kind: Service
+metadata:
+  name: valid
+spec:
+  runLatest:
+    configuration:
+      revisionTemplate:
+        metadata:
+          annotations:
+            autoscaling.knative.dev/target: "10"
+            autoscaling.knative.dev/minScale: "1"
+        spec:
+          container:
+            image: img1
+---
+apiVersion: serving.knative.dev/v1alpha1
+kind: Service
+metadata:
+  name: unknown-field
+spec:
+  runLatest:
+    configuration:
+      revisionTemplate:
+        spec:
+          container:
+            imag: img1
+---
+apiVersion: serving.knative.dev/v1alpha1
+kind: Service
+metadata:
+  name: bad-annotations
+spec:
+  runLatest:
+    configuration:
+      revisionTemplate:
+        metadata:
+          annotations:
+            autoscaling.knative.dev/target: "-1"
+            autoscaling.knative.dev/class: kpa
+        spec:
+          container:
+            image: img1
+---
+apiVersion: serving.knative.dev/v1alpha1
+kind: Route
+metadata:
+  name: route1
+spec:
+  traffic:
+  - revisionName: rev1
+    percent: 50
+  - revisionName: rev2
+    percent: 40
+---
+apiVersion: eventing.knative.dev/v1alpha1
+kind: Trigger
+metadata:
+  name: trigger1
+`)
+
+	issues, skipped := bundle.NewValidator().Validate(objs)
+
+	expectedIssues := []bundle.ValidationIssue{
+		{Kind: "Service", Name: "unknown-field", Message: `Expected object to match schema: json: unknown field "imag"`},
+		{Kind: "Service", Name: "bad-annotations", Message: "Expected annotation 'autoscaling.knative.dev/target' to be a number greater than 0: spec.runLatest.configuration.revisionTemplate.metadata.annotations"},
+		{Kind: "Service", Name: "bad-annotations", Message: "Expected annotation 'autoscaling.knative.dev/class' to be one of 'kpa.autoscaling.knative.dev', 'hpa.autoscaling.knative.dev': spec.runLatest.configuration.revisionTemplate.metadata.annotations"},
+		{Kind: "Route", Name: "route1", Message: "Traffic targets sum to 90, want 100: spec.traffic"},
+	}
+
+	if !reflect.DeepEqual(issues, expectedIssues) {
+		t.Fatalf("Expected issues to match, but was: %#v", issues)
+	}
+
+	if len(skipped) != 1 || skipped[0].GetName() != "trigger1" {
+		t.Fatalf("Expected trigger to be skipped, but was: %#v", skipped)
+	}
+}
+
+func readValidatorObjs(t *testing.T, content string) []unstructured.Unstructured {
+	dir, err := ioutil.TempDir("", "knctl-bundle")
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "service.yml")
+
+	err = ioutil.WriteFile(path, []byte(content), 0600)
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	objs, err := bundle.ReadPath(path)
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	return objs
+}
@@ -0,0 +1,131 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"time"
+
+	"github.com/cppforlife/knctl/pkg/knctl/util"
+)
+
+var (
+	unsafeFileNameCharsRegexp = regexp.MustCompile(`[^\w.-]`)
+)
+
+// Cache stores JSON serialized values on disk for a limited time.
+// Zero value is a disabled cache (nothing is stored or returned).
+type Cache struct {
+	dir string
+	ttl time.Duration
+}
+
+func NewCache(dir string, ttl time.Duration) Cache {
+	return Cache{dir, ttl}
+}
+
+// DefaultRootDir returns directory that holds caches for all clusters
+func DefaultRootDir() (string, error) {
+	homeDir := os.Getenv("HOME")
+	if len(homeDir) == 0 {
+		return "", fmt.Errorf("Expected $HOME to be set to determine cache directory")
+	}
+	return filepath.Join(homeDir, ".knctl", "cache"), nil
+}
+
+// ClusterDir returns directory unique to an API server host and user identity
+// (e.g. credentials) since cached values may differ between users;
+// identity is hashed so that it does not show up in file names
+func ClusterDir(rootDir, host, identity string) string {
+	identitySHA := sha256.Sum256([]byte(identity))
+	name := unsafeFileNameCharsRegexp.ReplaceAllString(host, "_") + "-" + hex.EncodeToString(identitySHA[:8])
+	return filepath.Join(rootDir, name)
+}
+
+func (c Cache) Enabled() bool      { return c.ttl > 0 && len(c.dir) > 0 }
+func (c Cache) Dir() string        { return c.dir }
+func (c Cache) TTL() time.Duration { return c.ttl }
+
+// Get returns false if value is not found, expired or cannot be read
+func (c Cache) Get(key string, val interface{}) bool {
+	if !c.Enabled() {
+		return false
+	}
+
+	path := c.path(key)
+
+	fileInfo, err := os.Stat(path)
+	if err != nil || time.Since(fileInfo.ModTime()) > c.ttl {
+		return false
+	}
+
+	bs, err := ioutil.ReadFile(path)
+	if err != nil {
+		return false
+	}
+
+	return json.Unmarshal(bs, val) == nil
+}
+
+func (c Cache) Set(key string, val interface{}) error {
+	if !c.Enabled() {
+		return nil
+	}
+
+	bs, err := json.Marshal(val)
+	if err != nil {
+		return fmt.Errorf("Serializing cache value '%s': %s", key, err)
+	}
+
+	err = os.MkdirAll(c.dir, 0700)
+	if err != nil {
+		return fmt.Errorf("Creating cache directory: %s", err)
+	}
+
+	// Other knctl processes may read the same value concurrently
+	err = util.WriteFileAtomically(c.path(key), bs, 0600)
+	if err != nil {
+		return fmt.Errorf("Writing cache value '%s': %s", key, err)
+	}
+
+	return nil
+}
+
+// Delete removes value so that next Get does not return stale data
+func (c Cache) Delete(key string) error {
+	if !c.Enabled() {
+		return nil
+	}
+
+	err := os.Remove(c.path(key))
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("Removing cache value '%s': %s", key, err)
+	}
+
+	return nil
+}
+
+func (c Cache) path(key string) string {
+	return filepath.Join(c.dir, unsafeFileNameCharsRegexp.ReplaceAllString(key, "_")+".json")
+}
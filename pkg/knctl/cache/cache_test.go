@@ -0,0 +1,92 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cache_test
+
+import (
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cppforlife/knctl/pkg/knctl/cache"
+)
+
+func TestCache(t *testing.T) {
+	dir, err := ioutil.TempDir("", "knctl-cache")
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	defer os.RemoveAll(dir)
+
+	c := cache.NewCache(cache.ClusterDir(dir, "https://1.2.3.4:443", "user1"), time.Minute)
+
+	var val []string
+
+	if c.Get("key", &val) {
+		t.Fatalf("Expected value to not be found")
+	}
+
+	err = c.Set("key", []string{"a", "b"})
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	if !c.Get("key", &val) || len(val) != 2 || val[1] != "b" {
+		t.Fatalf("Expected value to be found, but was %#v", val)
+	}
+
+	expiredCache := cache.NewCache(c.Dir(), time.Nanosecond)
+	time.Sleep(time.Millisecond)
+
+	if expiredCache.Get("key", &val) {
+		t.Fatalf("Expected value to be expired")
+	}
+}
+
+func TestCache_Disabled(t *testing.T) {
+	c := cache.Cache{}
+
+	err := c.Set("key", "val")
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	var val string
+
+	if c.Get("key", &val) {
+		t.Fatalf("Expected disabled cache to not return values")
+	}
+}
+
+func TestClusterDir(t *testing.T) {
+	dir1 := cache.ClusterDir("/root", "https://1.2.3.4:443", "user1")
+	dir2 := cache.ClusterDir("/root", "https://1.2.3.4:443", "user2")
+
+	if dir1 == dir2 {
+		t.Fatalf("Expected different users to use different directories")
+	}
+
+	if dir1 != cache.ClusterDir("/root", "https://1.2.3.4:443", "user1") {
+		t.Fatalf("Expected same user to use same directory")
+	}
+
+	if !strings.HasPrefix(dir1, "/root/https___1.2.3.4_443-") || strings.Contains(dir1, "user1") {
+		t.Fatalf("Expected directory to include host and hashed identity, but was '%s'", dir1)
+	}
+}
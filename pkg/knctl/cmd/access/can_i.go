@@ -0,0 +1,129 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package access
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cppforlife/go-cli-ui/ui"
+	uitable "github.com/cppforlife/go-cli-ui/ui/table"
+	ctlacc "github.com/cppforlife/knctl/pkg/knctl/access"
+	cmdcore "github.com/cppforlife/knctl/pkg/knctl/cmd/core"
+	"github.com/spf13/cobra"
+)
+
+type CanIOptions struct {
+	ui          ui.UI
+	depsFactory cmdcore.DepsFactory
+
+	NamespaceFlags cmdcore.NamespaceFlags
+	Command        string
+}
+
+func NewCanIOptions(ui ui.UI, depsFactory cmdcore.DepsFactory) *CanIOptions {
+	return &CanIOptions{ui: ui, depsFactory: depsFactory}
+}
+
+func NewCanICmd(o *CanIOptions, flagsFactory cmdcore.FlagsFactory) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "can-i",
+		Short: "Check permissions required by a command",
+		Long: fmt.Sprintf(`Check whether current user has all permissions required by a command in a namespace.
+
+Supported commands: %s.`, strings.Join(CommandNames(), ", ")),
+		Example: `
+  # Check if current user can deploy services in namespace 'ns1'
+  knctl can-i deploy -n ns1
+
+  # Check if current user can create builds in namespace 'ns1'
+  knctl can-i build -n ns1`,
+		Annotations: map[string]string{
+			cmdcore.OtherHelpGroup.Key: cmdcore.OtherHelpGroup.Value,
+		},
+		Args:      cobra.ExactArgs(1),
+		ValidArgs: CommandNames(),
+		RunE: func(_ *cobra.Command, args []string) error {
+			o.Command = args[0]
+			return o.Run()
+		},
+	}
+	o.NamespaceFlags.Set(cmd, flagsFactory)
+	return cmd
+}
+
+func (o *CanIOptions) Run() error {
+	perms, found := CommandPermissions[o.Command]
+	if !found {
+		return fmt.Errorf("Expected command to be one of: %s", strings.Join(CommandNames(), ", "))
+	}
+
+	coreClient, err := o.depsFactory.CoreClient()
+	if err != nil {
+		return err
+	}
+
+	results, err := ctlacc.NewAccessReviews(coreClient).Check(o.NamespaceFlags.Name, perms)
+	if err != nil {
+		return err
+	}
+
+	o.printTable(results)
+
+	var deniedCount int
+
+	for _, result := range results {
+		if !result.Allowed {
+			deniedCount++
+		}
+	}
+
+	if deniedCount > 0 {
+		return fmt.Errorf("Expected all permissions for command '%s' to be granted in namespace '%s', but %d were not",
+			o.Command, o.NamespaceFlags.Name, deniedCount)
+	}
+
+	return nil
+}
+
+func (o *CanIOptions) printTable(results []ctlacc.PermissionResult) {
+	table := uitable.Table{
+		Title:   fmt.Sprintf("Permissions for command '%s' in namespace '%s'", o.Command, o.NamespaceFlags.Name),
+		Content: "permissions",
+
+		Header: []uitable.Header{
+			uitable.NewHeader("Verb"),
+			uitable.NewHeader("Resource"),
+			uitable.NewHeader("Allowed"),
+			uitable.NewHeader("Reason"),
+		},
+	}
+
+	for _, result := range results {
+		table.Rows = append(table.Rows, []uitable.Value{
+			uitable.NewValueString(result.Verb),
+			uitable.NewValueString(result.ResourceDesc()),
+			uitable.ValueFmt{
+				V:     uitable.NewValueBool(result.Allowed),
+				Error: !result.Allowed,
+			},
+			uitable.NewValueString(result.Reason),
+		})
+	}
+
+	o.ui.PrintTable(table)
+}
@@ -0,0 +1,67 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package access_test
+
+import (
+	"testing"
+
+	. "github.com/cppforlife/knctl/pkg/knctl/cmd"
+	. "github.com/cppforlife/knctl/pkg/knctl/cmd/access"
+	cmdcore "github.com/cppforlife/knctl/pkg/knctl/cmd/core"
+)
+
+func TestNewCanICmd_Ok(t *testing.T) {
+	realCmd := NewCanIOptions(nil, cmdcore.NewDepsFactory())
+	cmd := NewTestCmd(t, NewCanICmd(realCmd, cmdcore.FlagsFactory{}))
+	cmd.ExpectBasicConfig()
+	cmd.Execute([]string{
+		"deploy",
+		"-n", "test-namespace",
+	})
+	cmd.ExpectReachesExecution()
+
+	DeepEqual(t, realCmd.NamespaceFlags, cmdcore.NamespaceFlags{"test-namespace"})
+}
+
+func TestNewCanICmd_OkLongFlagNames(t *testing.T) {
+	realCmd := NewCanIOptions(nil, cmdcore.NewDepsFactory())
+	cmd := NewTestCmd(t, NewCanICmd(realCmd, cmdcore.FlagsFactory{}))
+	cmd.Execute([]string{
+		"rollout",
+		"--namespace", "test-namespace",
+	})
+	cmd.ExpectReachesExecution()
+
+	DeepEqual(t, realCmd.NamespaceFlags, cmdcore.NamespaceFlags{"test-namespace"})
+}
+
+func TestNewCanICmd_RequiresCommand(t *testing.T) {
+	realCmd := NewCanIOptions(nil, cmdcore.NewDepsFactory())
+	cmd := NewTestCmd(t, NewCanICmd(realCmd, cmdcore.FlagsFactory{}))
+	cmd.Execute([]string{"-n", "test-namespace"})
+	cmd.ExpectErr("accepts 1 arg(s), received 0")
+}
+
+func TestCommandPermissions_AllCommandsHavePermissions(t *testing.T) {
+	DeepEqual(t, CommandNames(), []string{"build", "deploy", "rollout"})
+
+	for _, name := range CommandNames() {
+		if len(CommandPermissions[name]) == 0 {
+			t.Fatalf("Expected command '%s' to require at least one permission", name)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package access
+
+import (
+	"sort"
+
+	ctlacc "github.com/cppforlife/knctl/pkg/knctl/access"
+	"github.com/knative/build/pkg/apis/build"
+	"github.com/knative/serving/pkg/apis/serving"
+)
+
+var (
+	podsPermissions = []ctlacc.Permission{
+		{Verb: "get", Resource: "pods"},
+		{Verb: "list", Resource: "pods"},
+		{Verb: "watch", Resource: "pods"},
+		{Verb: "get", Resource: "pods", Subresource: "log"},
+	}
+
+	buildPermissions = []ctlacc.Permission{
+		{Verb: "create", Group: build.GroupName, Resource: "builds"},
+		{Verb: "get", Group: build.GroupName, Resource: "builds"},
+		{Verb: "list", Group: build.GroupName, Resource: "builds"},
+		{Verb: "watch", Group: build.GroupName, Resource: "builds"},
+		// Necessary for uploading source code from a local directory
+		{Verb: "create", Resource: "pods", Subresource: "exec"},
+	}
+
+	// CommandPermissions lists permissions that need to be granted
+	// within a namespace for a command to succeed in all of its steps
+	CommandPermissions = map[string][]ctlacc.Permission{
+		"deploy": concatPermissions([]ctlacc.Permission{
+			{Verb: "get", Group: serving.GroupName, Resource: "services"},
+			{Verb: "create", Group: serving.GroupName, Resource: "services"},
+			{Verb: "update", Group: serving.GroupName, Resource: "services"},
+			{Verb: "get", Group: serving.GroupName, Resource: "configurations"},
+			{Verb: "create", Group: serving.GroupName, Resource: "configurations"},
+			{Verb: "update", Group: serving.GroupName, Resource: "configurations"},
+			{Verb: "get", Group: serving.GroupName, Resource: "revisions"},
+			{Verb: "list", Group: serving.GroupName, Resource: "revisions"},
+			{Verb: "watch", Group: serving.GroupName, Resource: "revisions"},
+			{Verb: "patch", Group: serving.GroupName, Resource: "revisions"},
+		}, buildPermissions, podsPermissions),
+
+		"build": concatPermissions(buildPermissions, podsPermissions),
+
+		"rollout": []ctlacc.Permission{
+			{Verb: "get", Group: serving.GroupName, Resource: "services"},
+			{Verb: "get", Group: serving.GroupName, Resource: "revisions"},
+			{Verb: "list", Group: serving.GroupName, Resource: "revisions"},
+			{Verb: "get", Group: serving.GroupName, Resource: "routes"},
+			{Verb: "create", Group: serving.GroupName, Resource: "routes"},
+			{Verb: "update", Group: serving.GroupName, Resource: "routes"},
+		},
+	}
+)
+
+func CommandNames() []string {
+	var names []string
+	for name, _ := range CommandPermissions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func concatPermissions(permsList ...[]ctlacc.Permission) []ctlacc.Permission {
+	var result []ctlacc.Permission
+	for _, perms := range permsList {
+		result = append(result, perms...)
+	}
+	return result
+}
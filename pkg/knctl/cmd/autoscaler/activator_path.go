@@ -0,0 +1,168 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package autoscaler
+
+import (
+	"fmt"
+	"strconv"
+
+	duckv1alpha1 "github.com/knative/pkg/apis/duck/v1alpha1"
+	kpav1alpha1 "github.com/knative/serving/pkg/apis/autoscaling/v1alpha1"
+	"github.com/knative/serving/pkg/apis/serving"
+	servingclientset "github.com/knative/serving/pkg/client/clientset/versioned"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+)
+
+const (
+	RequestPathActivator = "activator"
+	RequestPathPods      = "pods"
+
+	sksModeProxy = "Proxy"
+	sksModeServe = "Serve"
+)
+
+var (
+	serverlessServiceGVR = schema.GroupVersionResource{
+		Group:    "networking.internal.knative.dev",
+		Version:  "v1alpha1",
+		Resource: "serverlessservices",
+	}
+)
+
+type RevisionActivatorPath struct {
+	Revision            string
+	Active              string
+	TargetBurstCapacity string
+	ActivationScale     string
+	Path                string
+	Reason              string
+}
+
+// RevisionActivatorPaths determines whether requests to service's revisions
+// are proxied through the activator or sent directly to revision's pods.
+// Knative records this as serverless service's mode (Proxy or Serve);
+// for clusters without serverless services it's derived from whether
+// revision's pod autoscaler is active.
+type RevisionActivatorPaths struct {
+	servingClient servingclientset.Interface
+	dynamicClient dynamic.Interface
+}
+
+func NewRevisionActivatorPaths(servingClient servingclientset.Interface, dynamicClient dynamic.Interface) RevisionActivatorPaths {
+	return RevisionActivatorPaths{servingClient, dynamicClient}
+}
+
+func (p RevisionActivatorPaths) List(namespace, serviceName string) ([]RevisionActivatorPath, error) {
+	listOpts := metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("%s=%s", serving.ServiceLabelKey, serviceName),
+	}
+
+	revisions, err := p.servingClient.ServingV1alpha1().Revisions(namespace).List(listOpts)
+	if err != nil {
+		return nil, fmt.Errorf("Listing revisions: %s", err)
+	}
+
+	var result []RevisionActivatorPath
+
+	for _, revision := range revisions.Items {
+		item := RevisionActivatorPath{
+			Revision:            revision.Name,
+			Active:              "Unknown",
+			TargetBurstCapacity: p.targetBurstCapacity(revision.Annotations),
+			ActivationScale:     revision.Annotations[ActivationScaleAnnotationKey],
+		}
+
+		pa, err := p.servingClient.AutoscalingV1alpha1().PodAutoscalers(namespace).Get(revision.Name, metav1.GetOptions{})
+		if err != nil && !errors.IsNotFound(err) {
+			return nil, fmt.Errorf("Getting pod autoscaler for revision '%s': %s", revision.Name, err)
+		}
+
+		var activeCond *duckv1alpha1.Condition
+
+		if err == nil {
+			activeCond = pa.Status.GetCondition(kpav1alpha1.PodAutoscalerConditionActive)
+			if activeCond != nil {
+				item.Active = string(activeCond.Status)
+			}
+		}
+
+		sksMode, err := p.sksMode(namespace, revision.Name)
+		if err != nil {
+			return nil, err
+		}
+
+		switch {
+		case sksMode == sksModeProxy:
+			item.Path = RequestPathActivator
+			item.Reason = "Serverless service is in Proxy mode"
+			if item.TargetBurstCapacity == "-1" {
+				item.Reason += " (target burst capacity -1 keeps activator in path)"
+			}
+		case sksMode == sksModeServe:
+			item.Path = RequestPathPods
+			item.Reason = "Serverless service is in Serve mode"
+		case activeCond != nil && activeCond.IsFalse():
+			item.Path = RequestPathActivator
+			item.Reason = "Revision is inactive (scaled to zero)"
+		case activeCond != nil && activeCond.IsUnknown():
+			item.Path = RequestPathActivator
+			item.Reason = "Revision is activating"
+		case activeCond != nil:
+			item.Path = RequestPathPods
+			item.Reason = "Revision is active"
+		default:
+			item.Path = "unknown"
+			item.Reason = "Pod autoscaler has not reported activity yet"
+		}
+
+		result = append(result, item)
+	}
+
+	return result, nil
+}
+
+func (p RevisionActivatorPaths) sksMode(namespace, name string) (string, error) {
+	sks, err := p.dynamicClient.Resource(serverlessServiceGVR).Namespace(namespace).Get(name, metav1.GetOptions{})
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return "", nil // older Knative versions do not have serverless services
+		}
+		return "", fmt.Errorf("Getting serverless service '%s': %s", name, err)
+	}
+
+	mode, _, err := unstructured.NestedString(sks.Object, "spec", "mode")
+	if err != nil {
+		return "", fmt.Errorf("Reading serverless service '%s' mode: %s", name, err)
+	}
+
+	return mode, nil
+}
+
+func (RevisionActivatorPaths) targetBurstCapacity(anns map[string]string) string {
+	for _, key := range []string{TargetBurstCapacityAnnotationKey, legacyTargetBurstCapacityAnnotationKey} {
+		if val, found := anns[key]; found {
+			if _, err := strconv.ParseFloat(val, 64); err == nil {
+				return val
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,110 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package autoscaler_test
+
+import (
+	"testing"
+
+	. "github.com/cppforlife/knctl/pkg/knctl/cmd"
+	. "github.com/cppforlife/knctl/pkg/knctl/cmd/autoscaler"
+	"github.com/cppforlife/knctl/pkg/knctl/testkit"
+	duckv1alpha1 "github.com/knative/pkg/apis/duck/v1alpha1"
+	kpav1alpha1 "github.com/knative/serving/pkg/apis/autoscaling/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func TestRevisionActivatorPaths_List(t *testing.T) {
+	rev1 := testkit.NewRevision("ns1", "svc1", "svc1-00001").Build()
+	rev1.Annotations = map[string]string{"autoscaling.knative.dev/target-burst-capacity": "-1"}
+
+	rev2 := testkit.NewRevision("ns1", "svc1", "svc1-00002").Build()
+	rev2.Annotations = map[string]string{"autoscaling.knative.dev/activation-scale": "2"}
+
+	cluster := testkit.NewCluster(t,
+		rev1, rev2,
+		testkit.NewRevision("ns1", "svc1", "svc1-00003").Build(),
+		testkit.NewRevision("ns1", "svc1", "svc1-00004").Build(),
+		testkit.NewRevision("ns1", "svc2", "svc2-00001").Build(),
+		newTestActivePodAutoscaler("ns1", "svc1-00001", corev1.ConditionTrue),
+		newTestActivePodAutoscaler("ns1", "svc1-00002", corev1.ConditionTrue),
+		newTestActivePodAutoscaler("ns1", "svc1-00003", corev1.ConditionFalse),
+		newTestServerlessService("ns1", "svc1-00001", "Proxy"),
+		newTestServerlessService("ns1", "svc1-00002", "Serve"),
+	)
+
+	dynamicClient, err := cluster.DepsFactory().DynamicClient()
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	paths, err := NewRevisionActivatorPaths(cluster.ServingClient(), dynamicClient).List("ns1", "svc1")
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	DeepEqual(t, paths, []RevisionActivatorPath{
+		{
+			Revision:            "svc1-00001",
+			Active:              "True",
+			TargetBurstCapacity: "-1",
+			Path:                RequestPathActivator,
+			Reason:              "Serverless service is in Proxy mode (target burst capacity -1 keeps activator in path)",
+		},
+		{
+			Revision:        "svc1-00002",
+			Active:          "True",
+			ActivationScale: "2",
+			Path:            RequestPathPods,
+			Reason:          "Serverless service is in Serve mode",
+		},
+		{
+			Revision: "svc1-00003",
+			Active:   "False",
+			Path:     RequestPathActivator,
+			Reason:   "Revision is inactive (scaled to zero)",
+		},
+		{
+			Revision: "svc1-00004",
+			Active:   "Unknown",
+			Path:     "unknown",
+			Reason:   "Pod autoscaler has not reported activity yet",
+		},
+	})
+}
+
+func newTestActivePodAutoscaler(namespace, name string, status corev1.ConditionStatus) runtime.Object {
+	return &kpav1alpha1.PodAutoscaler{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+		Status: kpav1alpha1.PodAutoscalerStatus{
+			Conditions: duckv1alpha1.Conditions{
+				{Type: kpav1alpha1.PodAutoscalerConditionActive, Status: status},
+			},
+		},
+	}
+}
+
+func newTestServerlessService(namespace, name, mode string) runtime.Object {
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "networking.internal.knative.dev/v1alpha1",
+		"kind":       "ServerlessService",
+		"metadata":   map[string]interface{}{"name": name, "namespace": namespace},
+		"spec":       map[string]interface{}{"mode": mode},
+	}}
+}
@@ -0,0 +1,153 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package autoscaler
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	autoscalerMetricsPath = "/api/v1/namespaces/knative-serving/services/autoscaler:9090/proxy/metrics"
+)
+
+// RevisionMetrics holds autoscaler's most recent view of a revision
+type RevisionMetrics struct {
+	DesiredPods       float64
+	ActualPods        float64
+	StableConcurrency float64
+	PanicConcurrency  float64
+	TargetConcurrency float64
+	PanicMode         bool
+}
+
+// AutoscalerMetrics scrapes autoscaler's Prometheus endpoint
+// via API server's service proxy
+type AutoscalerMetrics struct {
+	coreClient kubernetes.Interface
+}
+
+func NewAutoscalerMetrics(coreClient kubernetes.Interface) AutoscalerMetrics {
+	return AutoscalerMetrics{coreClient}
+}
+
+func (m AutoscalerMetrics) List(namespace string) (map[string]RevisionMetrics, error) {
+	bs, err := m.coreClient.Discovery().RESTClient().Get().AbsPath(autoscalerMetricsPath).DoRaw()
+	if err != nil {
+		return nil, fmt.Errorf("Getting autoscaler metrics: %s", err)
+	}
+
+	return ParseAutoscalerMetrics(bs, namespace)
+}
+
+// ParseAutoscalerMetrics extracts per revision gauges from Prometheus text format
+func ParseAutoscalerMetrics(data []byte, namespace string) (map[string]RevisionMetrics, error) {
+	result := map[string]RevisionMetrics{}
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if len(line) == 0 || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		name, labels, value, err := parseMetricLine(line)
+		if err != nil {
+			return nil, err
+		}
+
+		if labels["namespace_name"] != namespace || len(labels["revision_name"]) == 0 {
+			continue
+		}
+
+		revMetrics := result[labels["revision_name"]]
+
+		switch name {
+		case "autoscaler_desired_pods":
+			revMetrics.DesiredPods = value
+		case "autoscaler_actual_pods":
+			revMetrics.ActualPods = value
+		case "autoscaler_stable_request_concurrency":
+			revMetrics.StableConcurrency = value
+		case "autoscaler_panic_request_concurrency":
+			revMetrics.PanicConcurrency = value
+		case "autoscaler_target_concurrency_per_pod":
+			revMetrics.TargetConcurrency = value
+		case "autoscaler_panic_mode":
+			revMetrics.PanicMode = value > 0
+		default:
+			continue
+		}
+
+		result[labels["revision_name"]] = revMetrics
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("Reading autoscaler metrics: %s", err)
+	}
+
+	return result, nil
+}
+
+func parseMetricLine(line string) (string, map[string]string, float64, error) {
+	labels := map[string]string{}
+	name := line
+	rest := ""
+
+	if idx := strings.Index(line, "{"); idx >= 0 {
+		endIdx := strings.LastIndex(line, "}")
+		if endIdx < idx {
+			return "", nil, 0, fmt.Errorf("Expected metric line '%s' to have closing brace", line)
+		}
+
+		name = line[:idx]
+		rest = line[endIdx+1:]
+
+		for _, pair := range strings.Split(line[idx+1:endIdx], ",") {
+			pieces := strings.SplitN(pair, "=", 2)
+			if len(pieces) != 2 {
+				continue
+			}
+			labels[strings.TrimSpace(pieces[0])] = strings.Trim(pieces[1], `"`)
+		}
+	} else {
+		pieces := strings.SplitN(line, " ", 2)
+		if len(pieces) != 2 {
+			return "", nil, 0, fmt.Errorf("Expected metric line '%s' to have value", line)
+		}
+		name, rest = pieces[0], pieces[1]
+	}
+
+	// Value may be followed by optional timestamp
+	fields := strings.Fields(rest)
+	if len(fields) == 0 {
+		return "", nil, 0, fmt.Errorf("Expected metric line '%s' to have value", line)
+	}
+
+	value, err := strconv.ParseFloat(fields[0], 64)
+	if err != nil {
+		return "", nil, 0, fmt.Errorf("Parsing metric value in line '%s': %s", line, err)
+	}
+
+	return name, labels, value, nil
+}
@@ -0,0 +1,62 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package autoscaler_test
+
+import (
+	"testing"
+
+	. "github.com/cppforlife/knctl/pkg/knctl/cmd"
+	. "github.com/cppforlife/knctl/pkg/knctl/cmd/autoscaler"
+)
+
+func TestParseAutoscalerMetrics(t *testing.T) {
+	data := `
+# HELP autoscaler_desired_pods Number of pods autoscaler wants to allocate
+# TYPE autoscaler_desired_pods gauge
+autoscaler_desired_pods{configuration_name="svc1",namespace_name="ns1",revision_name="svc1-00001",service_name="svc1"} 3
+autoscaler_actual_pods{configuration_name="svc1",namespace_name="ns1",revision_name="svc1-00001",service_name="svc1"} 2
+autoscaler_stable_request_concurrency{configuration_name="svc1",namespace_name="ns1",revision_name="svc1-00001",service_name="svc1"} 251.5
+autoscaler_panic_request_concurrency{configuration_name="svc1",namespace_name="ns1",revision_name="svc1-00001",service_name="svc1"} 300
+autoscaler_target_concurrency_per_pod{configuration_name="svc1",namespace_name="ns1",revision_name="svc1-00001",service_name="svc1"} 100
+autoscaler_panic_mode{configuration_name="svc1",namespace_name="ns1",revision_name="svc1-00001",service_name="svc1"} 1 1541000000000
+autoscaler_desired_pods{configuration_name="svc1",namespace_name="ns2",revision_name="svc1-00001",service_name="svc1"} 5
+go_goroutines 42
+`
+
+	metrics, err := ParseAutoscalerMetrics([]byte(data), "ns1")
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	DeepEqual(t, metrics, map[string]RevisionMetrics{
+		"svc1-00001": RevisionMetrics{
+			DesiredPods:       3,
+			ActualPods:        2,
+			StableConcurrency: 251.5,
+			PanicConcurrency:  300,
+			TargetConcurrency: 100,
+			PanicMode:         true,
+		},
+	})
+}
+
+func TestParseAutoscalerMetrics_InvalidValue(t *testing.T) {
+	_, err := ParseAutoscalerMetrics([]byte(`autoscaler_desired_pods{namespace_name="ns1"} abc`), "ns1")
+	if err == nil {
+		t.Fatalf("Expected error")
+	}
+}
@@ -0,0 +1,163 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package autoscaler
+
+import (
+	"fmt"
+
+	"github.com/cppforlife/go-cli-ui/ui"
+	uitable "github.com/cppforlife/go-cli-ui/ui/table"
+	cmdcore "github.com/cppforlife/knctl/pkg/knctl/cmd/core"
+	cmdflags "github.com/cppforlife/knctl/pkg/knctl/cmd/flags"
+	"github.com/spf13/cobra"
+)
+
+type InspectOptions struct {
+	ui          ui.UI
+	depsFactory cmdcore.DepsFactory
+
+	ServiceFlags cmdflags.ServiceFlags
+}
+
+func NewInspectOptions(ui ui.UI, depsFactory cmdcore.DepsFactory) *InspectOptions {
+	return &InspectOptions{ui: ui, depsFactory: depsFactory}
+}
+
+func NewInspectCmd(o *InspectOptions, flagsFactory cmdcore.FlagsFactory) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "inspect",
+		Short: "Explain autoscaler decisions for service's revisions",
+		Long: `Explain autoscaler decisions for service's revisions.
+
+Reads revision's pod autoscaler and autoscaler's metrics (scraped via
+API server's service proxy) to show desired scale, observed concurrency,
+panic mode status and which limit (minScale, maxScale or concurrency target)
+currently determines number of pods. When autoscaler metrics are not
+accessible, explanation is based on pod autoscaler and ready pods only.`,
+		Example: `
+  # Explain autoscaler decisions for service 'svc1' in namespace 'ns1'
+  knctl autoscaler inspect -s svc1 -n ns1`,
+		RunE: func(_ *cobra.Command, _ []string) error { return o.Run() },
+	}
+	o.ServiceFlags.Set(cmd, flagsFactory)
+	return cmd
+}
+
+func (o *InspectOptions) Run() error {
+	coreClient, err := o.depsFactory.CoreClient()
+	if err != nil {
+		return err
+	}
+
+	servingClient, err := o.depsFactory.ServingClient()
+	if err != nil {
+		return err
+	}
+
+	metrics, err := NewAutoscalerMetrics(coreClient).List(o.ServiceFlags.NamespaceFlags.Name)
+	if err != nil {
+		o.ui.PrintLinef("Autoscaler metrics are not available: %s", err)
+	}
+
+	items, err := NewRevisionAutoscalings(coreClient, servingClient).List(
+		o.ServiceFlags.NamespaceFlags.Name, o.ServiceFlags.Name, metrics)
+	if err != nil {
+		return err
+	}
+
+	table := uitable.Table{
+		Title:   fmt.Sprintf("Autoscaler decisions for service '%s'", o.ServiceFlags.Name),
+		Content: "revisions",
+
+		Header: []uitable.Header{
+			uitable.NewHeader("Revision"),
+			uitable.NewHeader("Class"),
+			uitable.NewHeader("Active"),
+			uitable.NewHeader("Ready pods"),
+			uitable.NewHeader("Desired pods"),
+			uitable.NewHeader("Stable concurrency"),
+			uitable.NewHeader("Panic concurrency"),
+			uitable.NewHeader("Panic mode"),
+			uitable.NewHeader("Target"),
+			uitable.NewHeader("Container concurrency"),
+			uitable.NewHeader("Min scale"),
+			uitable.NewHeader("Max scale"),
+			uitable.NewHeader("Binding limit"),
+			uitable.NewHeader("Explanation"),
+		},
+
+		SortBy: []uitable.ColumnSort{
+			{Column: 0, Asc: true},
+		},
+
+		Transpose: true,
+	}
+
+	for _, item := range items {
+		row := []uitable.Value{
+			uitable.NewValueString(item.Revision),
+			uitable.NewValueString(item.Class),
+			uitable.NewValueString(item.Active),
+			uitable.NewValueInt(item.ReadyPods),
+		}
+
+		if item.Metrics != nil {
+			row = append(row,
+				uitable.NewValueString(fmt.Sprintf("%g", item.Metrics.DesiredPods)),
+				uitable.NewValueString(fmt.Sprintf("%g", item.Metrics.StableConcurrency)),
+				uitable.NewValueString(fmt.Sprintf("%g", item.Metrics.PanicConcurrency)),
+				uitable.NewValueBool(item.Metrics.PanicMode),
+			)
+		} else {
+			row = append(row,
+				uitable.NewValueString("?"),
+				uitable.NewValueString("?"),
+				uitable.NewValueString("?"),
+				uitable.NewValueString("?"),
+			)
+		}
+
+		row = append(row,
+			o.optionalFloat(item.Target),
+			o.optionalInt(int64(item.ContainerConcurrency), "unlimited"),
+			o.optionalInt(int64(item.MinScale), "0"),
+			o.optionalInt(int64(item.MaxScale), "unlimited"),
+			uitable.NewValueString(item.BindingLimit),
+			uitable.NewValueString(item.Explanation),
+		)
+
+		table.Rows = append(table.Rows, row)
+	}
+
+	o.ui.PrintTable(table)
+
+	return nil
+}
+
+func (InspectOptions) optionalFloat(val float64) uitable.Value {
+	if val == 0 {
+		return uitable.NewValueString("default")
+	}
+	return uitable.NewValueString(fmt.Sprintf("%g", val))
+}
+
+func (InspectOptions) optionalInt(val int64, zeroDesc string) uitable.Value {
+	if val == 0 {
+		return uitable.NewValueString(zeroDesc)
+	}
+	return uitable.NewValueString(fmt.Sprintf("%d", val))
+}
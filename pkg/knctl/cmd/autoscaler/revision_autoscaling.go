@@ -0,0 +1,209 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package autoscaler
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/knative/serving/pkg/apis/autoscaling"
+	kpav1alpha1 "github.com/knative/serving/pkg/apis/autoscaling/v1alpha1"
+	"github.com/knative/serving/pkg/apis/serving"
+	servingclientset "github.com/knative/serving/pkg/client/clientset/versioned"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	TargetAnnotationKey = autoscaling.GroupName + "/target"
+
+	BindingLimitMinScale     = "minScale"
+	BindingLimitMaxScale     = "maxScale"
+	BindingLimitTarget       = "target"
+	BindingLimitScaledToZero = "scaled to zero"
+	BindingLimitUnknown      = "unknown"
+)
+
+type RevisionAutoscaling struct {
+	Revision string
+	Class    string
+
+	MinScale             int32
+	MaxScale             int32
+	Target               float64
+	ContainerConcurrency int64
+
+	Active    string
+	ReadyPods int
+
+	// Metrics is nil when autoscaler metrics are not available
+	Metrics *RevisionMetrics
+
+	BindingLimit string
+	Explanation  string
+}
+
+// RevisionAutoscalings explains scaling decisions of service's revisions
+// based on their pod autoscalers and autoscaler's reported metrics
+type RevisionAutoscalings struct {
+	coreClient    kubernetes.Interface
+	servingClient servingclientset.Interface
+}
+
+func NewRevisionAutoscalings(coreClient kubernetes.Interface, servingClient servingclientset.Interface) RevisionAutoscalings {
+	return RevisionAutoscalings{coreClient, servingClient}
+}
+
+func (s RevisionAutoscalings) List(namespace, serviceName string, metrics map[string]RevisionMetrics) ([]RevisionAutoscaling, error) {
+	listOpts := metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("%s=%s", serving.ServiceLabelKey, serviceName),
+	}
+
+	revisions, err := s.servingClient.ServingV1alpha1().Revisions(namespace).List(listOpts)
+	if err != nil {
+		return nil, fmt.Errorf("Listing revisions: %s", err)
+	}
+
+	var result []RevisionAutoscaling
+
+	for _, revision := range revisions.Items {
+		pa, err := s.servingClient.AutoscalingV1alpha1().PodAutoscalers(namespace).Get(revision.Name, metav1.GetOptions{})
+		if err != nil {
+			if errors.IsNotFound(err) {
+				continue // e.g. revision failed before autoscaler was created
+			}
+			return nil, fmt.Errorf("Getting pod autoscaler for revision '%s': %s", revision.Name, err)
+		}
+
+		readyPods, err := s.readyPods(namespace, revision.Name)
+		if err != nil {
+			return nil, err
+		}
+
+		item := RevisionAutoscaling{
+			Revision:             revision.Name,
+			Class:                pa.Annotations[autoscaling.ClassAnnotationKey],
+			ContainerConcurrency: int64(pa.Spec.ContainerConcurrency),
+			Active:               s.active(pa),
+			ReadyPods:            readyPods,
+		}
+
+		item.MinScale, item.MaxScale = pa.ScaleBounds()
+
+		if revMetrics, found := metrics[revision.Name]; found {
+			item.Metrics = &revMetrics
+			item.Target = revMetrics.TargetConcurrency
+		}
+
+		if item.Target == 0 {
+			fmt.Sscanf(pa.Annotations[TargetAnnotationKey], "%g", &item.Target)
+		}
+
+		item.BindingLimit, item.Explanation = s.explain(item)
+
+		result = append(result, item)
+	}
+
+	return result, nil
+}
+
+func (s RevisionAutoscalings) explain(item RevisionAutoscaling) (string, string) {
+	if item.Metrics == nil {
+		switch {
+		case item.ReadyPods == 0 && item.MinScale == 0:
+			return BindingLimitScaledToZero, "Revision has no ready pods; activator buffers requests until it scales up"
+		case item.MinScale > 0 && int32(item.ReadyPods) <= item.MinScale:
+			return BindingLimitMinScale, fmt.Sprintf(
+				"Revision runs at its minimum scale of %d pod(s) (autoscaler metrics unavailable)", item.MinScale)
+		case item.MaxScale > 0 && int32(item.ReadyPods) >= item.MaxScale:
+			return BindingLimitMaxScale, fmt.Sprintf(
+				"Revision runs at its maximum scale of %d pod(s) (autoscaler metrics unavailable)", item.MaxScale)
+		default:
+			return BindingLimitUnknown, "Autoscaler metrics are unavailable to determine observed concurrency"
+		}
+	}
+
+	observed := item.Metrics.StableConcurrency
+	mode := "stable"
+
+	if item.Metrics.PanicMode {
+		observed = item.Metrics.PanicConcurrency
+		mode = "panic"
+	}
+
+	var wanted int32
+
+	if item.Target > 0 {
+		wanted = int32(math.Ceil(observed / item.Target))
+	}
+
+	switch {
+	case wanted == 0 && item.MinScale == 0 && item.Metrics.DesiredPods == 0:
+		return BindingLimitScaledToZero, "No requests observed; revision is scaled (or scaling) to zero"
+	case wanted < item.MinScale:
+		return BindingLimitMinScale, fmt.Sprintf(
+			"Observed %s concurrency %g needs %d pod(s) but minScale keeps %d pod(s)",
+			mode, observed, wanted, item.MinScale)
+	case item.MaxScale > 0 && wanted > item.MaxScale:
+		return BindingLimitMaxScale, fmt.Sprintf(
+			"Observed %s concurrency %g needs %d pod(s) but maxScale caps it at %d pod(s)",
+			mode, observed, wanted, item.MaxScale)
+	default:
+		return BindingLimitTarget, fmt.Sprintf(
+			"Observed %s concurrency %g with target %g per pod needs %d pod(s)",
+			mode, observed, item.Target, wanted)
+	}
+}
+
+func (s RevisionAutoscalings) readyPods(namespace, revisionName string) (int, error) {
+	listOpts := metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("%s=%s", serving.RevisionLabelKey, revisionName),
+	}
+
+	pods, err := s.coreClient.CoreV1().Pods(namespace).List(listOpts)
+	if err != nil {
+		return 0, fmt.Errorf("Listing pods: %s", err)
+	}
+
+	var count int
+
+	for _, pod := range pods.Items {
+		for _, cond := range pod.Status.Conditions {
+			if cond.Type == corev1.PodReady && cond.Status == corev1.ConditionTrue {
+				count++
+				break
+			}
+		}
+	}
+
+	return count, nil
+}
+
+func (RevisionAutoscalings) active(pa *kpav1alpha1.PodAutoscaler) string {
+	cond := pa.Status.GetCondition(kpav1alpha1.PodAutoscalerConditionActive)
+	if cond == nil {
+		return "Unknown"
+	}
+
+	result := string(cond.Status)
+	if len(cond.Reason) > 0 {
+		result += " (" + cond.Reason + ")"
+	}
+	return result
+}
@@ -0,0 +1,120 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package autoscaler_test
+
+import (
+	"testing"
+
+	. "github.com/cppforlife/knctl/pkg/knctl/cmd"
+	. "github.com/cppforlife/knctl/pkg/knctl/cmd/autoscaler"
+	"github.com/cppforlife/knctl/pkg/knctl/testkit"
+	kpav1alpha1 "github.com/knative/serving/pkg/apis/autoscaling/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func TestRevisionAutoscalings_WithMetrics(t *testing.T) {
+	cluster := testkit.NewCluster(t,
+		testkit.NewRevision("ns1", "svc1", "svc1-00001").Build(),
+		testkit.NewRevision("ns1", "svc1", "svc1-00002").Build(),
+		testkit.NewRevision("ns1", "svc1", "svc1-00003").Build(),
+		testkit.NewRevision("ns1", "svc2", "svc2-00001").Build(),
+		newTestPodAutoscaler("ns1", "svc1-00001", map[string]string{"autoscaling.knative.dev/minScale": "2"}),
+		newTestPodAutoscaler("ns1", "svc1-00002", map[string]string{"autoscaling.knative.dev/maxScale": "3"}),
+		newTestPodAutoscaler("ns1", "svc1-00003", nil),
+		newTestPodAutoscaler("ns1", "svc2-00001", nil),
+		newTestPod("ns1", "svc1-00002-pod1", "svc1-00002", true),
+		newTestPod("ns1", "svc1-00002-pod2", "svc1-00002", false),
+	)
+
+	metrics := map[string]RevisionMetrics{
+		"svc1-00001": {DesiredPods: 2, StableConcurrency: 50, TargetConcurrency: 100},
+		"svc1-00002": {DesiredPods: 3, StableConcurrency: 100, PanicConcurrency: 900, TargetConcurrency: 100, PanicMode: true},
+		"svc1-00003": {DesiredPods: 3, StableConcurrency: 250, TargetConcurrency: 100},
+	}
+
+	items, err := NewRevisionAutoscalings(cluster.CoreClient(), cluster.ServingClient()).List("ns1", "svc1", metrics)
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	if len(items) != 3 {
+		t.Fatalf("Expected 3 revisions, but was %d", len(items))
+	}
+
+	DeepEqual(t, items[0].BindingLimit, BindingLimitMinScale)
+	DeepEqual(t, items[0].Explanation, "Observed stable concurrency 50 needs 1 pod(s) but minScale keeps 2 pod(s)")
+
+	DeepEqual(t, items[1].ReadyPods, 1)
+	DeepEqual(t, items[1].BindingLimit, BindingLimitMaxScale)
+	DeepEqual(t, items[1].Explanation, "Observed panic concurrency 900 needs 9 pod(s) but maxScale caps it at 3 pod(s)")
+
+	DeepEqual(t, items[2].BindingLimit, BindingLimitTarget)
+	DeepEqual(t, items[2].Explanation, "Observed stable concurrency 250 with target 100 per pod needs 3 pod(s)")
+}
+
+func TestRevisionAutoscalings_WithoutMetrics(t *testing.T) {
+	cluster := testkit.NewCluster(t,
+		testkit.NewRevision("ns1", "svc1", "svc1-00001").Build(),
+		testkit.NewRevision("ns1", "svc1", "svc1-00002").Build(),
+		newTestPodAutoscaler("ns1", "svc1-00001", map[string]string{
+			"autoscaling.knative.dev/minScale": "1",
+			"autoscaling.knative.dev/target":   "10",
+		}),
+		newTestPodAutoscaler("ns1", "svc1-00002", nil),
+		newTestPod("ns1", "svc1-00001-pod1", "svc1-00001", true),
+	)
+
+	items, err := NewRevisionAutoscalings(cluster.CoreClient(), cluster.ServingClient()).List("ns1", "svc1", nil)
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	if len(items) != 2 {
+		t.Fatalf("Expected 2 revisions, but was %d", len(items))
+	}
+
+	DeepEqual(t, items[0].Metrics == nil, true)
+	DeepEqual(t, items[0].Target, float64(10))
+	DeepEqual(t, items[0].BindingLimit, BindingLimitMinScale)
+
+	DeepEqual(t, items[1].BindingLimit, BindingLimitScaledToZero)
+}
+
+func newTestPodAutoscaler(namespace, name string, annotations map[string]string) runtime.Object {
+	return &kpav1alpha1.PodAutoscaler{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name, Annotations: annotations},
+	}
+}
+
+func newTestPod(namespace, name, revisionName string, ready bool) runtime.Object {
+	status := corev1.ConditionFalse
+	if ready {
+		status = corev1.ConditionTrue
+	}
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: namespace,
+			Name:      name,
+			Labels:    map[string]string{"serving.knative.dev/revision": revisionName},
+		},
+		Status: corev1.PodStatus{
+			Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: status}},
+		},
+	}
+}
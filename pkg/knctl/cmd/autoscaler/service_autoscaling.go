@@ -0,0 +1,125 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package autoscaler
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/knative/serving/pkg/apis/autoscaling"
+	"github.com/knative/serving/pkg/apis/serving/v1alpha1"
+	servingclientset "github.com/knative/serving/pkg/client/clientset/versioned"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
+)
+
+const (
+	TargetBurstCapacityAnnotationKey = autoscaling.GroupName + "/target-burst-capacity"
+	ActivationScaleAnnotationKey     = autoscaling.GroupName + "/activation-scale"
+
+	// Older spelling still accepted by Knative; it must not be set together with the new one
+	legacyTargetBurstCapacityAnnotationKey = autoscaling.GroupName + "/targetBurstCapacity"
+)
+
+type ServiceAutoscalingSettings struct {
+	// TargetBurstCapacity of -1 keeps activator always in request path,
+	// 0 puts it in path only when scaling from zero
+	TargetBurstCapacity *int
+	// ActivationScale is minimum number of pods revision scales to when it becomes active
+	ActivationScale *int
+}
+
+func (s ServiceAutoscalingSettings) Validate() error {
+	if s.TargetBurstCapacity == nil && s.ActivationScale == nil {
+		return fmt.Errorf("Expected at least one autoscaling setting to be specified")
+	}
+	if s.TargetBurstCapacity != nil && *s.TargetBurstCapacity < -1 {
+		return fmt.Errorf("Expected target burst capacity to be -1 or greater")
+	}
+	if s.ActivationScale != nil && *s.ActivationScale < 1 {
+		return fmt.Errorf("Expected activation scale to be 1 or greater")
+	}
+	return nil
+}
+
+// ServiceAutoscaling sets autoscaling annotations on service's
+// revision template (which results in a new revision)
+type ServiceAutoscaling struct {
+	servingClient servingclientset.Interface
+}
+
+func NewServiceAutoscaling(servingClient servingclientset.Interface) ServiceAutoscaling {
+	return ServiceAutoscaling{servingClient}
+}
+
+func (a ServiceAutoscaling) Set(namespace, name string, settings ServiceAutoscalingSettings) error {
+	err := settings.Validate()
+	if err != nil {
+		return err
+	}
+
+	services := a.servingClient.ServingV1alpha1().Services(namespace)
+
+	err = retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		service, err := services.Get(name, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+
+		confSpec, err := a.configurationSpec(service)
+		if err != nil {
+			return err
+		}
+
+		anns := confSpec.RevisionTemplate.Annotations
+		if anns == nil {
+			anns = map[string]string{}
+		}
+
+		if settings.TargetBurstCapacity != nil {
+			delete(anns, legacyTargetBurstCapacityAnnotationKey)
+			anns[TargetBurstCapacityAnnotationKey] = strconv.Itoa(*settings.TargetBurstCapacity)
+		}
+		if settings.ActivationScale != nil {
+			anns[ActivationScaleAnnotationKey] = strconv.Itoa(*settings.ActivationScale)
+		}
+
+		confSpec.RevisionTemplate.Annotations = anns
+
+		_, err = services.Update(service)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("Updating service autoscaling settings: %s", err)
+	}
+
+	return nil
+}
+
+func (ServiceAutoscaling) configurationSpec(service *v1alpha1.Service) (*v1alpha1.ConfigurationSpec, error) {
+	switch {
+	case service.Spec.RunLatest != nil:
+		return &service.Spec.RunLatest.Configuration, nil
+	case service.Spec.Release != nil:
+		return &service.Spec.Release.Configuration, nil
+	case service.Spec.Pinned != nil:
+		return &service.Spec.Pinned.Configuration, nil
+	default:
+		return nil, fmt.Errorf(
+			"Expected service '%s' to manage its configuration (manual mode is not supported)", service.Name)
+	}
+}
@@ -0,0 +1,94 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package autoscaler_test
+
+import (
+	"testing"
+
+	. "github.com/cppforlife/knctl/pkg/knctl/cmd"
+	. "github.com/cppforlife/knctl/pkg/knctl/cmd/autoscaler"
+	"github.com/cppforlife/knctl/pkg/knctl/testkit"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestServiceAutoscaling_Set(t *testing.T) {
+	service := testkit.NewService("ns1", "svc1").Build()
+	service.Spec.RunLatest.Configuration.RevisionTemplate.Annotations = map[string]string{
+		"autoscaling.knative.dev/targetBurstCapacity": "200",
+		"other": "val",
+	}
+
+	cluster := testkit.NewCluster(t, service)
+
+	tbc := 0
+	activationScale := 2
+
+	err := NewServiceAutoscaling(cluster.ServingClient()).Set("ns1", "svc1", ServiceAutoscalingSettings{
+		TargetBurstCapacity: &tbc,
+		ActivationScale:     &activationScale,
+	})
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	service, err = cluster.ServingClient().ServingV1alpha1().Services("ns1").Get("svc1", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	DeepEqual(t, service.Spec.RunLatest.Configuration.RevisionTemplate.Annotations, map[string]string{
+		"autoscaling.knative.dev/target-burst-capacity": "0",
+		"autoscaling.knative.dev/activation-scale":      "2",
+		"other": "val",
+	})
+}
+
+func TestServiceAutoscaling_SetInvalid(t *testing.T) {
+	cluster := testkit.NewCluster(t, testkit.NewService("ns1", "svc1").Build())
+
+	tbc := -2
+	activationScale := 0
+
+	examples := []struct {
+		Settings ServiceAutoscalingSettings
+		Err      string
+	}{
+		{ServiceAutoscalingSettings{}, "Expected at least one autoscaling setting to be specified"},
+		{ServiceAutoscalingSettings{TargetBurstCapacity: &tbc}, "Expected target burst capacity to be -1 or greater"},
+		{ServiceAutoscalingSettings{ActivationScale: &activationScale}, "Expected activation scale to be 1 or greater"},
+	}
+
+	for _, ex := range examples {
+		err := NewServiceAutoscaling(cluster.ServingClient()).Set("ns1", "svc1", ex.Settings)
+		if err == nil || err.Error() != ex.Err {
+			t.Fatalf("Expected error '%s', but was: %v", ex.Err, err)
+		}
+	}
+}
+
+func TestServiceAutoscaling_SetManualService(t *testing.T) {
+	cluster := testkit.NewCluster(t, testkit.NewService("ns1", "svc1").Manual().Build())
+
+	activationScale := 1
+
+	err := NewServiceAutoscaling(cluster.ServingClient()).Set("ns1", "svc1", ServiceAutoscalingSettings{
+		ActivationScale: &activationScale,
+	})
+	if err == nil {
+		t.Fatalf("Expected error")
+	}
+}
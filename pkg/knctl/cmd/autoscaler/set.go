@@ -0,0 +1,86 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package autoscaler
+
+import (
+	"github.com/cppforlife/go-cli-ui/ui"
+	cmdcore "github.com/cppforlife/knctl/pkg/knctl/cmd/core"
+	cmdflags "github.com/cppforlife/knctl/pkg/knctl/cmd/flags"
+	"github.com/spf13/cobra"
+)
+
+type SetOptions struct {
+	ui          ui.UI
+	depsFactory cmdcore.DepsFactory
+
+	ServiceFlags cmdflags.ServiceFlags
+
+	TargetBurstCapacity *int
+	ActivationScale     *int
+}
+
+func NewSetOptions(ui ui.UI, depsFactory cmdcore.DepsFactory) *SetOptions {
+	return &SetOptions{ui: ui, depsFactory: depsFactory}
+}
+
+func NewSetCmd(o *SetOptions, flagsFactory cmdcore.FlagsFactory) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "set",
+		Short: "Set autoscaling settings for service",
+		Long: `Set autoscaling settings for service.
+
+Settings are saved as annotations on service's revision template,
+hence a new revision is created.
+
+Target burst capacity determines when activator stays in request path:
+-1 keeps it always in path, 0 puts it in path only when scaling from zero.
+Activation scale sets minimum number of pods revision scales to
+once it receives traffic.`,
+		Example: `
+  # Always route requests for service 'svc1' in namespace 'ns1' through activator
+  knctl autoscaler set -s svc1 --target-burst-capacity -1 -n ns1
+
+  # Start at least 3 pods when service 'svc1' in namespace 'ns1' scales from zero
+  knctl autoscaler set -s svc1 --activation-scale 3 -n ns1`,
+		RunE: func(_ *cobra.Command, _ []string) error { return o.Run() },
+	}
+	o.ServiceFlags.Set(cmd, flagsFactory)
+	cmd.Flags().Var(cmdcore.NewDefaultlessIntValue(&o.TargetBurstCapacity), "target-burst-capacity", "Set target burst capacity (-1 keeps activator in request path)")
+	cmd.Flags().Var(cmdcore.NewDefaultlessIntValue(&o.ActivationScale), "activation-scale", "Set minimum number of pods when scaling from zero")
+	return cmd
+}
+
+func (o *SetOptions) Run() error {
+	servingClient, err := o.depsFactory.ServingClient()
+	if err != nil {
+		return err
+	}
+
+	settings := ServiceAutoscalingSettings{
+		TargetBurstCapacity: o.TargetBurstCapacity,
+		ActivationScale:     o.ActivationScale,
+	}
+
+	err = NewServiceAutoscaling(servingClient).Set(o.ServiceFlags.NamespaceFlags.Name, o.ServiceFlags.Name, settings)
+	if err != nil {
+		return err
+	}
+
+	o.ui.PrintLinef("Updated autoscaling settings of service '%s'", o.ServiceFlags.Name)
+
+	return nil
+}
@@ -0,0 +1,66 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package autoscaler_test
+
+import (
+	"testing"
+
+	. "github.com/cppforlife/knctl/pkg/knctl/cmd"
+	. "github.com/cppforlife/knctl/pkg/knctl/cmd/autoscaler"
+	cmdcore "github.com/cppforlife/knctl/pkg/knctl/cmd/core"
+	cmdflags "github.com/cppforlife/knctl/pkg/knctl/cmd/flags"
+)
+
+func TestNewSetCmd_Ok(t *testing.T) {
+	realCmd := NewSetOptions(nil, cmdcore.NewDepsFactory())
+	cmd := NewTestCmd(t, NewSetCmd(realCmd, cmdcore.FlagsFactory{}))
+	cmd.ExpectBasicConfig()
+	cmd.Execute([]string{
+		"-n", "test-namespace",
+		"-s", "test-service",
+		"--target-burst-capacity", "-1",
+		"--activation-scale", "3",
+	})
+	cmd.ExpectReachesExecution()
+
+	tbc := -1
+	activationScale := 3
+
+	DeepEqual(t, realCmd.ServiceFlags,
+		cmdflags.ServiceFlags{cmdcore.NamespaceFlags{"test-namespace"}, "test-service"})
+	DeepEqual(t, realCmd.TargetBurstCapacity, &tbc)
+	DeepEqual(t, realCmd.ActivationScale, &activationScale)
+}
+
+func TestNewSetCmd_Defaults(t *testing.T) {
+	realCmd := NewSetOptions(nil, cmdcore.NewDepsFactory())
+	cmd := NewTestCmd(t, NewSetCmd(realCmd, cmdcore.FlagsFactory{}))
+	cmd.ExpectBasicConfig()
+	cmd.Execute([]string{"-n", "test-namespace", "-s", "test-service"})
+	cmd.ExpectReachesExecution()
+
+	DeepEqual(t, realCmd.TargetBurstCapacity == nil, true)
+	DeepEqual(t, realCmd.ActivationScale == nil, true)
+}
+
+func TestNewSetCmd_RequiredFlags(t *testing.T) {
+	realCmd := NewSetOptions(nil, cmdcore.NewDepsFactory())
+	cmd := NewTestCmd(t, NewSetCmd(realCmd, cmdcore.FlagsFactory{}))
+	cmd.ExpectBasicConfig()
+	cmd.Execute([]string{})
+	cmd.ExpectRequiredFlags([]string{"service"})
+}
@@ -0,0 +1,104 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package autoscaler
+
+import (
+	"fmt"
+
+	"github.com/cppforlife/go-cli-ui/ui"
+	uitable "github.com/cppforlife/go-cli-ui/ui/table"
+	cmdcore "github.com/cppforlife/knctl/pkg/knctl/cmd/core"
+	cmdflags "github.com/cppforlife/knctl/pkg/knctl/cmd/flags"
+	"github.com/spf13/cobra"
+)
+
+type StatusOptions struct {
+	ui          ui.UI
+	depsFactory cmdcore.DepsFactory
+
+	ServiceFlags cmdflags.ServiceFlags
+}
+
+func NewStatusOptions(ui ui.UI, depsFactory cmdcore.DepsFactory) *StatusOptions {
+	return &StatusOptions{ui: ui, depsFactory: depsFactory}
+}
+
+func NewStatusCmd(o *StatusOptions, flagsFactory cmdcore.FlagsFactory) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "status",
+		Short: "Show whether requests flow through activator",
+		Long: `Show whether requests for service's revisions currently flow
+through the activator or directly to revision's pods.`,
+		Example: `
+  # Show request path of revisions of service 'svc1' in namespace 'ns1'
+  knctl autoscaler status -s svc1 -n ns1`,
+		RunE: func(_ *cobra.Command, _ []string) error { return o.Run() },
+	}
+	o.ServiceFlags.Set(cmd, flagsFactory)
+	return cmd
+}
+
+func (o *StatusOptions) Run() error {
+	servingClient, err := o.depsFactory.ServingClient()
+	if err != nil {
+		return err
+	}
+
+	dynamicClient, err := o.depsFactory.DynamicClient()
+	if err != nil {
+		return err
+	}
+
+	paths, err := NewRevisionActivatorPaths(servingClient, dynamicClient).List(
+		o.ServiceFlags.NamespaceFlags.Name, o.ServiceFlags.Name)
+	if err != nil {
+		return err
+	}
+
+	table := uitable.Table{
+		Title:   fmt.Sprintf("Request path for service '%s'", o.ServiceFlags.Name),
+		Content: "revisions",
+
+		Header: []uitable.Header{
+			uitable.NewHeader("Revision"),
+			uitable.NewHeader("Active"),
+			uitable.NewHeader("Target burst capacity"),
+			uitable.NewHeader("Activation scale"),
+			uitable.NewHeader("Path"),
+			uitable.NewHeader("Reason"),
+		},
+
+		SortBy: []uitable.ColumnSort{
+			{Column: 0, Asc: true},
+		},
+	}
+
+	for _, path := range paths {
+		table.Rows = append(table.Rows, []uitable.Value{
+			uitable.NewValueString(path.Revision),
+			uitable.NewValueString(path.Active),
+			uitable.NewValueString(path.TargetBurstCapacity),
+			uitable.NewValueString(path.ActivationScale),
+			uitable.NewValueString(path.Path),
+			uitable.NewValueString(path.Reason),
+		})
+	}
+
+	o.ui.PrintTable(table)
+
+	return nil
+}
@@ -19,11 +19,14 @@ package basicauthsecret
 import (
 	"encoding/json"
 	"fmt"
+	"io"
+	"os"
 
 	"github.com/cppforlife/go-cli-ui/ui"
 	uitable "github.com/cppforlife/go-cli-ui/ui/table"
 	cmdcore "github.com/cppforlife/knctl/pkg/knctl/cmd/core"
 	cmdflags "github.com/cppforlife/knctl/pkg/knctl/cmd/flags"
+	cmdsa "github.com/cppforlife/knctl/pkg/knctl/cmd/serviceaccount"
 	"github.com/spf13/cobra"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -32,13 +35,20 @@ import (
 type CreateOptions struct {
 	ui          ui.UI
 	depsFactory cmdcore.DepsFactory
+	stdin       io.Reader
 
 	SecretFlags cmdflags.SecretFlags
 	CreateFlags CreateFlags
 }
 
 func NewCreateOptions(ui ui.UI, depsFactory cmdcore.DepsFactory) *CreateOptions {
-	return &CreateOptions{ui: ui, depsFactory: depsFactory}
+	return &CreateOptions{ui: ui, depsFactory: depsFactory, stdin: os.Stdin}
+}
+
+// WithStdin returns options that read password from given reader (useful for tests)
+func (o *CreateOptions) WithStdin(stdin io.Reader) *CreateOptions {
+	o.stdin = stdin
+	return o
 }
 
 func NewCreateCmd(o *CreateOptions, flagsFactory cmdcore.FlagsFactory) *cobra.Command {
@@ -63,7 +73,15 @@ Use 'kubectl delete secret <name> -n <namespace>' to delete secret.`,
   knctl basic-auth-secret create -s secret1 --gcr --username username --password password -n ns1
 
   # Create generic Docker registry basic auth secret 'secret1' in namespace 'ns1'
-  knctl basic-auth-secret create -s secret1 --type docker --url https://registry.domain.com/ --username username --password password -n ns1`,
+  knctl basic-auth-secret create -s secret1 --type docker --url https://registry.domain.com/ --username username --password password -n ns1
+
+  # Create Docker registry basic auth secret 'secret1' with password prompt
+  # and add it to service account 'sa1' in namespace 'ns1'
+  knctl basic-auth-secret create -s secret1 --docker-hub --username username --service-account sa1 -n ns1
+
+  # Create Docker registry basic auth secret 'secret1' with password read from stdin in namespace 'ns1'
+  # (e.g. 'cat password.txt | knctl ...')
+  knctl basic-auth-secret create -s secret1 --docker-hub --username username --password-stdin -n ns1`,
 		RunE: func(_ *cobra.Command, _ []string) error { return o.Run() },
 	}
 	o.SecretFlags.Set(cmd, flagsFactory)
@@ -77,6 +95,11 @@ func (o *CreateOptions) Run() error {
 		return err
 	}
 
+	err = o.CreateFlags.BackfillPassword(o.stdin, o.ui.AskForPassword)
+	if err != nil {
+		return err
+	}
+
 	coreClient, err := o.depsFactory.CoreClient()
 	if err != nil {
 		return err
@@ -100,6 +123,20 @@ func (o *CreateOptions) Run() error {
 
 	o.printTable(createdSecret)
 
+	if len(o.CreateFlags.ServiceAccount) > 0 {
+		bindOpts := cmdsa.NewBindOptions(o.ui, o.depsFactory)
+		bindOpts.ServiceAccountFlags = cmdsa.ServiceAccountFlags{
+			NamespaceFlags: o.SecretFlags.NamespaceFlags,
+			Name:           o.CreateFlags.ServiceAccount,
+		}
+		bindOpts.BindFlags.Secrets = []string{createdSecret.Name}
+
+		err = bindOpts.Run()
+		if err != nil {
+			return fmt.Errorf("Adding secret to service account '%s': %s", o.CreateFlags.ServiceAccount, err)
+		}
+	}
+
 	return nil
 }
 
@@ -18,7 +18,10 @@ package basicauthsecret
 
 import (
 	"fmt"
+	"io"
+	"io/ioutil"
 	"os"
+	"strings"
 
 	cmdcore "github.com/cppforlife/knctl/pkg/knctl/cmd/core"
 	"github.com/spf13/cobra"
@@ -27,15 +30,18 @@ import (
 type CreateFlags struct {
 	GenerateNameFlags cmdcore.GenerateNameFlags
 
-	Type     string
-	URL      string
-	Username string
-	Password string
+	Type          string
+	URL           string
+	Username      string
+	Password      string
+	PasswordStdin bool
 
 	DockerHub bool
 	GCR       bool
 
 	ForPulling bool
+
+	ServiceAccount string
 }
 
 func (s *CreateFlags) Set(cmd *cobra.Command, flagsFactory cmdcore.FlagsFactory) {
@@ -48,15 +54,15 @@ func (s *CreateFlags) Set(cmd *cobra.Command, flagsFactory cmdcore.FlagsFactory)
 	cmd.MarkFlagRequired("username")
 
 	defaultPassword := os.Getenv("KNCTL_BASIC_AUTH_SECRET_PASSWORD")
-	cmd.Flags().StringVarP(&s.Password, "password", "p", defaultPassword, "Set password ($KNCTL_BASIC_AUTH_SECRET_PASSWORD)")
-	if len(defaultPassword) == 0 {
-		cmd.MarkFlagRequired("password")
-	}
+	cmd.Flags().StringVarP(&s.Password, "password", "p", defaultPassword, "Set password; if not specified, password is prompted for ($KNCTL_BASIC_AUTH_SECRET_PASSWORD)")
+	cmd.Flags().BoolVar(&s.PasswordStdin, "password-stdin", false, "Read password from stdin")
 
 	cmd.Flags().BoolVar(&s.DockerHub, "docker-hub", false, "Preconfigure type and url for Docker Hub registry")
 	cmd.Flags().BoolVar(&s.GCR, "gcr", false, "Preconfigure type and url for gcr.io registry")
 
 	cmd.Flags().BoolVar(&s.ForPulling, "for-pulling", false, "Convert to pull secret ('kubernetes.io/dockerconfigjson' type)")
+
+	cmd.Flags().StringVar(&s.ServiceAccount, "service-account", "", "Add created secret to existing service account")
 }
 
 func (s *CreateFlags) BackfillTypeAndURL() error {
@@ -83,3 +89,35 @@ func (s *CreateFlags) BackfillTypeAndURL() error {
 
 	return nil
 }
+
+// BackfillPassword reads password from stdin or asks for it
+// when it was not provided via flag or environment variable
+func (s *CreateFlags) BackfillPassword(stdin io.Reader, askFunc func(string) (string, error)) error {
+	if s.PasswordStdin {
+		if len(s.Password) > 0 {
+			return fmt.Errorf("Expected to not specify --password (or $KNCTL_BASIC_AUTH_SECRET_PASSWORD) together with --password-stdin")
+		}
+
+		contents, err := ioutil.ReadAll(stdin)
+		if err != nil {
+			return fmt.Errorf("Reading password from stdin: %s", err)
+		}
+
+		s.Password = strings.TrimRight(string(contents), "\r\n")
+	}
+
+	if len(s.Password) == 0 && !s.PasswordStdin {
+		password, err := askFunc("Password")
+		if err != nil {
+			return err
+		}
+
+		s.Password = password
+	}
+
+	if len(s.Password) == 0 {
+		return fmt.Errorf("Expected password to be non-empty")
+	}
+
+	return nil
+}
@@ -17,12 +17,18 @@ limitations under the License.
 package basicauthsecret_test
 
 import (
+	"fmt"
+	"strings"
 	"testing"
 
+	"github.com/cppforlife/go-cli-ui/ui"
 	. "github.com/cppforlife/knctl/pkg/knctl/cmd"
 	. "github.com/cppforlife/knctl/pkg/knctl/cmd/basicauthsecret"
 	cmdcore "github.com/cppforlife/knctl/pkg/knctl/cmd/core"
 	cmdflags "github.com/cppforlife/knctl/pkg/knctl/cmd/flags"
+	"github.com/cppforlife/knctl/pkg/knctl/testkit"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 func TestNewCreateCmd_Ok(t *testing.T) {
@@ -178,5 +184,80 @@ func TestNewCreateCmd_RequiredFlags(t *testing.T) {
 	realCmd := NewCreateOptions(nil, cmdcore.NewDepsFactory())
 	cmd := NewTestCmd(t, NewCreateCmd(realCmd, cmdcore.FlagsFactory{}))
 	cmd.Execute([]string{})
-	cmd.ExpectRequiredFlags([]string{"password", "secret", "username"})
+	cmd.ExpectRequiredFlags([]string{"secret", "username"})
+}
+
+func TestCreateFlags_BackfillPassword(t *testing.T) {
+	noAsk := func(string) (string, error) { return "", fmt.Errorf("Expected to not ask") }
+
+	flags := CreateFlags{PasswordStdin: true}
+
+	err := flags.BackfillPassword(strings.NewReader("stdin-password\n"), noAsk)
+	if err != nil || flags.Password != "stdin-password" {
+		t.Fatalf("Expected password to be read from stdin but was '%s' (err: %v)", flags.Password, err)
+	}
+
+	flags = CreateFlags{}
+
+	err = flags.BackfillPassword(nil, func(label string) (string, error) { return "prompted-password", nil })
+	if err != nil || flags.Password != "prompted-password" {
+		t.Fatalf("Expected password to be prompted for but was '%s' (err: %v)", flags.Password, err)
+	}
+
+	flags = CreateFlags{Password: "flag-password"}
+
+	err = flags.BackfillPassword(nil, noAsk)
+	if err != nil || flags.Password != "flag-password" {
+		t.Fatalf("Expected password from flag to be kept but was '%s' (err: %v)", flags.Password, err)
+	}
+
+	flags = CreateFlags{Password: "flag-password", PasswordStdin: true}
+
+	err = flags.BackfillPassword(strings.NewReader("stdin-password"), noAsk)
+	if err == nil {
+		t.Fatalf("Expected error when both password and stdin are specified")
+	}
+
+	flags = CreateFlags{PasswordStdin: true}
+
+	err = flags.BackfillPassword(strings.NewReader(""), noAsk)
+	if err == nil || err.Error() != "Expected password to be non-empty" {
+		t.Fatalf("Expected error about empty password but was: %v", err)
+	}
+}
+
+func TestCreateOptions_AddsSecretToServiceAccount(t *testing.T) {
+	cluster := testkit.NewCluster(t, &corev1.ServiceAccount{
+		ObjectMeta: metav1.ObjectMeta{Name: "sa1", Namespace: "ns1"},
+	})
+
+	opts := NewCreateOptions(ui.NewNoopUI(), cluster.DepsFactory()).WithStdin(strings.NewReader("password\n"))
+	opts.SecretFlags = cmdflags.SecretFlags{cmdcore.NamespaceFlags{"ns1"}, "secret1"}
+	opts.CreateFlags = CreateFlags{
+		DockerHub:      true,
+		Username:       "username",
+		PasswordStdin:  true,
+		ServiceAccount: "sa1",
+	}
+
+	err := opts.Run()
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	secret, err := cluster.CoreClient().CoreV1().Secrets("ns1").Get("secret1", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Expected secret to be created: %s", err)
+	}
+
+	if secret.StringData[corev1.BasicAuthPasswordKey] != "password" {
+		t.Fatalf("Expected password from stdin to be saved: %#v", secret.StringData)
+	}
+
+	sa, err := cluster.CoreClient().CoreV1().ServiceAccounts("ns1").Get("sa1", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	DeepEqual(t, sa.Secrets, []corev1.ObjectReference{{Name: "secret1"}})
 }
@@ -105,7 +105,12 @@ func (o *CreateOptions) Run() error {
 
 	cancelCh := make(chan struct{})
 
-	buildObjFactory := ctlbuild.NewFactory(buildClient, coreClient, restConfig)
+	objWaiter, err := o.depsFactory.Waiter()
+	if err != nil {
+		return err
+	}
+
+	buildObjFactory := ctlbuild.NewFactory(coreClient, restConfig, objWaiter)
 	buildObj := buildObjFactory.New(createdBuild)
 
 	if len(o.CreateFlags.CreateArgsFlags.SourceDirectory) > 0 {
@@ -125,7 +125,12 @@ func (o *ShowOptions) showLogs(build *v1alpha1.Build, buildClient buildclientset
 		return err
 	}
 
-	buildObjFactory := ctlbuild.NewFactory(buildClient, coreClient, restConfig)
+	objWaiter, err := o.depsFactory.Waiter()
+	if err != nil {
+		return err
+	}
+
+	buildObjFactory := ctlbuild.NewFactory(coreClient, restConfig, objWaiter)
 	cancelCh := make(chan struct{})
 
 	return buildObjFactory.New(build).TailLogs(o.ui, cancelCh)
@@ -0,0 +1,134 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bulk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/cppforlife/go-cli-ui/ui"
+	"github.com/cppforlife/knctl/pkg/knctl/api"
+	cmdcore "github.com/cppforlife/knctl/pkg/knctl/cmd/core"
+	"github.com/cppforlife/knctl/pkg/knctl/util"
+	"github.com/spf13/cobra"
+)
+
+type BulkOptions struct {
+	ui            ui.UI
+	configFactory cmdcore.ConfigFactory
+	depsFactory   cmdcore.DepsFactory
+	cancelSignals cmdcore.CancelWatcher
+	stdin         io.Reader
+
+	Concurrency          int
+	RevisionReadyTimeout time.Duration
+}
+
+func NewBulkOptions(ui ui.UI, configFactory cmdcore.ConfigFactory, depsFactory cmdcore.DepsFactory, cancelSignals cmdcore.CancelWatcher) *BulkOptions {
+	return &BulkOptions{ui: ui, configFactory: configFactory, depsFactory: depsFactory,
+		cancelSignals: cancelSignals, stdin: os.Stdin}
+}
+
+func NewBulkCmd(o *BulkOptions, flagsFactory cmdcore.FlagsFactory) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "bulk",
+		Short: "Run deploy, rollout and delete operations read from stdin",
+		Long: `Run deploy, rollout and delete operations read from stdin.
+
+Each stdin line is a JSON object with 'op' (one of 'deploy', 'rollout' or 'delete'),
+'params' and optional 'id'. Params correspond to options of Go API operations
+(DeployOpts, RolloutOpts and DeleteOpts; see docs/go-api.md), for example:
+
+  {"id": "a", "op": "deploy", "params": {"namespace": "ns1", "service": "svc1", "image": "registry/app:v2"}}
+  {"id": "b", "op": "rollout", "params": {"namespace": "ns1", "route": "rt1", "revisionPercentages": ["svc1:latest=100%"]}}
+  {"id": "c", "op": "delete", "params": {"namespace": "ns1", "service": "svc3"}}
+
+Operations run concurrently (bounded by --concurrency) and a JSON result
+is printed per line as soon as operation completes. Command fails
+if at least one operation failed.`,
+		Example: `
+  # Run operations from stdin (e.g. 'knctl bulk < ops.jsonl')
+  knctl bulk
+
+  # Run at most 20 operations at once without waiting for deployed revisions to become ready
+  knctl bulk --concurrency 20 --revision-ready-timeout 0`,
+		Annotations: map[string]string{
+			cmdcore.OtherHelpGroup.Key: cmdcore.OtherHelpGroup.Value,
+		},
+		RunE: func(_ *cobra.Command, _ []string) error { return o.Run() },
+	}
+	cmd.Flags().IntVar(&o.Concurrency, "concurrency", util.DefaultParallelism, "Set maximum number of operations running at once")
+	cmd.Flags().DurationVar(&o.RevisionReadyTimeout, "revision-ready-timeout", 5*time.Minute,
+		"Set timeout for waiting for deployed revision to become ready (0 skips waiting)")
+	return cmd
+}
+
+func (o *BulkOptions) Run() error {
+	if o.Concurrency < 1 {
+		return fmt.Errorf("Expected concurrency to be greater than 0")
+	}
+
+	ops, err := ReadOperations(o.stdin)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	o.cancelSignals.Watch(cancel)
+
+	// Operations' own output is discarded so that only results are printed
+	client := api.NewClientWithFactories(ui.NewNoopUI(), o.configFactory, o.depsFactory)
+	runner := NewRunner(client, o.RevisionReadyTimeout)
+
+	var printLock sync.Mutex
+	var failed int
+
+	err = util.NewParallel(o.Concurrency).Run(len(ops), func(i int) error {
+		result := runner.Run(ctx, ops[i])
+
+		bs, err := json.Marshal(result)
+		if err != nil {
+			return err
+		}
+
+		printLock.Lock()
+		defer printLock.Unlock()
+
+		if !result.OK {
+			failed++
+		}
+
+		o.ui.PrintBlock(append(bs, '\n'))
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("Expected all %d operations to succeed, but %d failed", len(ops), failed)
+	}
+
+	return nil
+}
@@ -0,0 +1,50 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bulk_test
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/cppforlife/knctl/pkg/knctl/cmd"
+	. "github.com/cppforlife/knctl/pkg/knctl/cmd/bulk"
+	cmdcore "github.com/cppforlife/knctl/pkg/knctl/cmd/core"
+)
+
+func TestNewBulkCmd_Ok(t *testing.T) {
+	realCmd := NewBulkOptions(nil, cmdcore.NewConfigFactoryImpl(), cmdcore.NewDepsFactory(), cmdcore.CancelSignals{})
+	cmd := NewTestCmd(t, NewBulkCmd(realCmd, cmdcore.FlagsFactory{}))
+	cmd.ExpectBasicConfig()
+	cmd.Execute([]string{
+		"--concurrency", "3",
+		"--revision-ready-timeout", "1m",
+	})
+	cmd.ExpectReachesExecution()
+
+	DeepEqual(t, realCmd.Concurrency, 3)
+	DeepEqual(t, realCmd.RevisionReadyTimeout, time.Minute)
+}
+
+func TestNewBulkCmd_Defaults(t *testing.T) {
+	realCmd := NewBulkOptions(nil, cmdcore.NewConfigFactoryImpl(), cmdcore.NewDepsFactory(), cmdcore.CancelSignals{})
+	cmd := NewTestCmd(t, NewBulkCmd(realCmd, cmdcore.FlagsFactory{}))
+	cmd.Execute([]string{})
+	cmd.ExpectReachesExecution()
+
+	DeepEqual(t, realCmd.Concurrency, 10)
+	DeepEqual(t, realCmd.RevisionReadyTimeout, 5*time.Minute)
+}
@@ -0,0 +1,183 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bulk
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/cppforlife/knctl/pkg/knctl/api"
+)
+
+const (
+	OperationDeploy  = "deploy"
+	OperationRollout = "rollout"
+	OperationDelete  = "delete"
+)
+
+// Operation is a single line of bulk input. Params are decoded
+// into options of corresponding Go API operation (e.g. api.DeployOpts)
+// with field names matched case-insensitively.
+type Operation struct {
+	ID     string          `json:"id"`
+	Op     string          `json:"op"`
+	Params json.RawMessage `json:"params"`
+
+	Line     int   `json:"-"`
+	ParseErr error `json:"-"`
+}
+
+type Result struct {
+	Line     int    `json:"line"`
+	ID       string `json:"id,omitempty"`
+	Op       string `json:"op"`
+	OK       bool   `json:"ok"`
+	Error    string `json:"error,omitempty"`
+	Duration string `json:"duration"`
+}
+
+// ReadOperations reads newline-delimited JSON operations skipping empty lines.
+// Lines that cannot be parsed are returned with ParseErr so that they
+// are reported alongside other results instead of aborting whole run.
+func ReadOperations(reader io.Reader) ([]Operation, error) {
+	var ops []Operation
+
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if len(text) == 0 {
+			continue
+		}
+
+		var op Operation
+
+		err := strictUnmarshal([]byte(text), &op)
+		if err != nil {
+			op.ParseErr = fmt.Errorf("Unmarshaling operation: %s", err)
+		}
+
+		op.Line = line
+		ops = append(ops, op)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("Reading operations: %s", err)
+	}
+
+	return ops, nil
+}
+
+type Runner struct {
+	client               *api.Client
+	revisionReadyTimeout time.Duration
+}
+
+func NewRunner(client *api.Client, revisionReadyTimeout time.Duration) Runner {
+	return Runner{client, revisionReadyTimeout}
+}
+
+func (r Runner) Run(ctx context.Context, op Operation) Result {
+	startTime := time.Now()
+
+	err := op.ParseErr
+	if err == nil {
+		err = r.run(ctx, op)
+	}
+
+	result := Result{
+		Line:     op.Line,
+		ID:       op.ID,
+		Op:       op.Op,
+		OK:       err == nil,
+		Duration: time.Now().Sub(startTime).Round(time.Millisecond).String(),
+	}
+
+	if err != nil {
+		result.Error = err.Error()
+	}
+
+	return result
+}
+
+func (r Runner) run(ctx context.Context, op Operation) error {
+	switch op.Op {
+	case OperationDeploy:
+		var opts api.DeployOpts
+
+		err := r.decodeParams(op, &opts)
+		if err != nil {
+			return err
+		}
+
+		if opts.RevisionReadyTimeout == 0 {
+			opts.RevisionReadyTimeout = r.revisionReadyTimeout
+		}
+
+		return api.NewDeploy(r.client, opts).Run(ctx)
+
+	case OperationRollout:
+		var opts api.RolloutOpts
+
+		err := r.decodeParams(op, &opts)
+		if err != nil {
+			return err
+		}
+
+		return api.NewRollout(r.client, opts).Run(ctx)
+
+	case OperationDelete:
+		var opts api.DeleteOpts
+
+		err := r.decodeParams(op, &opts)
+		if err != nil {
+			return err
+		}
+
+		return api.NewDelete(r.client, opts).Run(ctx)
+
+	default:
+		return fmt.Errorf("Expected operation to be one of '%s', '%s' or '%s', but was '%s'",
+			OperationDeploy, OperationRollout, OperationDelete, op.Op)
+	}
+}
+
+func (Runner) decodeParams(op Operation, opts interface{}) error {
+	if len(op.Params) == 0 {
+		return fmt.Errorf("Expected operation to have params")
+	}
+
+	err := strictUnmarshal(op.Params, opts)
+	if err != nil {
+		return fmt.Errorf("Unmarshaling %s params: %s", op.Op, err)
+	}
+
+	return nil
+}
+
+func strictUnmarshal(bs []byte, val interface{}) error {
+	decoder := json.NewDecoder(bytes.NewReader(bs))
+	decoder.DisallowUnknownFields()
+	return decoder.Decode(val)
+}
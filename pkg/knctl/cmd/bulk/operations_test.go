@@ -0,0 +1,98 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bulk_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cppforlife/go-cli-ui/ui"
+	"github.com/cppforlife/knctl/pkg/knctl/api"
+	. "github.com/cppforlife/knctl/pkg/knctl/cmd/bulk"
+	"github.com/cppforlife/knctl/pkg/knctl/testkit"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestReadOperations(t *testing.T) {
+	input := `{"id": "a", "op": "delete", "params": {"namespace": "ns1", "service": "svc1"}}
+
+{"op": "deploy", "unknown": true}
+not-json
+`
+
+	ops, err := ReadOperations(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	if len(ops) != 3 {
+		t.Fatalf("Expected 3 operations, but was: %d", len(ops))
+	}
+
+	if ops[0].ID != "a" || ops[0].Op != "delete" || ops[0].Line != 1 || ops[0].ParseErr != nil {
+		t.Fatalf("Expected first operation to be parsed: %#v", ops[0])
+	}
+
+	if ops[1].Line != 3 || ops[1].ParseErr == nil ||
+		ops[1].ParseErr.Error() != `Unmarshaling operation: json: unknown field "unknown"` {
+		t.Fatalf("Expected second operation to have unknown field error: %#v", ops[1])
+	}
+
+	if ops[2].Line != 4 || ops[2].ParseErr == nil {
+		t.Fatalf("Expected third operation to have parse error: %#v", ops[2])
+	}
+}
+
+func TestRunner(t *testing.T) {
+	cluster := testkit.NewCluster(t, testkit.NewService("ns1", "svc1").Build())
+
+	client := api.NewClientWithFactories(ui.NewNoopUI(), cluster.ConfigFactory(), cluster.DepsFactory())
+	runner := NewRunner(client, time.Minute)
+	ctx := context.Background()
+
+	result := runner.Run(ctx, Operation{ID: "a", Op: "delete", Line: 1,
+		Params: []byte(`{"namespace": "ns1", "service": "svc1"}`)})
+
+	if !result.OK || result.ID != "a" || result.Line != 1 || result.Op != "delete" {
+		t.Fatalf("Expected delete to succeed: %#v", result)
+	}
+
+	_, err := cluster.ServingClient().ServingV1alpha1().Services("ns1").Get("svc1", metav1.GetOptions{})
+	if err == nil {
+		t.Fatalf("Expected service to be deleted")
+	}
+
+	expectResultErr(t, runner.Run(ctx, Operation{Op: "scale", Params: []byte(`{}`)}),
+		"Expected operation to be one of 'deploy', 'rollout' or 'delete', but was 'scale'")
+
+	expectResultErr(t, runner.Run(ctx, Operation{Op: "rollout"}),
+		"Expected operation to have params")
+
+	expectResultErr(t, runner.Run(ctx, Operation{Op: "deploy", Params: []byte(`{"service": "svc1", "img": "img1"}`)}),
+		`Unmarshaling deploy params: json: unknown field "img"`)
+
+	expectResultErr(t, runner.Run(ctx, Operation{Op: "deploy", Params: []byte(`{"service": "svc1"}`)}),
+		"Expected image to be non-empty")
+}
+
+func expectResultErr(t *testing.T, result Result, expected string) {
+	if result.OK || result.Error != expected {
+		t.Fatalf("Expected result error '%s', but was: %#v", expected, result)
+	}
+}
@@ -0,0 +1,138 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bundle
+
+import (
+	"fmt"
+
+	"github.com/cppforlife/go-cli-ui/ui"
+	uitable "github.com/cppforlife/go-cli-ui/ui/table"
+	"github.com/cppforlife/knctl/pkg/knctl/bundle"
+	cmdcore "github.com/cppforlife/knctl/pkg/knctl/cmd/core"
+	"github.com/spf13/cobra"
+)
+
+type ApplyOptions struct {
+	ui          ui.UI
+	depsFactory cmdcore.DepsFactory
+
+	NamespaceFlags cmdcore.NamespaceFlags
+	ManifestFlags  ManifestFlags
+	Prune          bool
+}
+
+func NewApplyOptions(ui ui.UI, depsFactory cmdcore.DepsFactory) *ApplyOptions {
+	return &ApplyOptions{ui: ui, depsFactory: depsFactory}
+}
+
+func NewApplyCmd(o *ApplyOptions, flagsFactory cmdcore.FlagsFactory) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "apply",
+		Short: "Apply Knative resources from YAML files",
+		Long: `Apply Knative resources from YAML files.
+
+Creates or updates Knative services, routes, triggers and event sources
+found in a YAML file or in a directory of YAML files (e.g. created by 'knctl export').
+
+Directories containing kustomization file are rendered via 'kubectl kustomize'.
+Directories containing Chart.yaml are rendered via 'helm template'.
+Rendered resources that are not Knative resources are skipped.
+Applied resources are labeled with '` + bundle.AppliedLabelKey + `=` + bundle.AppliedLabelValue + `'.
+
+With --prune flag, labeled resources in the namespace that are not found
+in given files are deleted.`,
+		Example: `
+  # Apply all resources from 'estate/' directory to namespace 'ns1'
+  knctl apply -f estate/ -n ns1
+
+  # Apply resources and delete previously applied resources that are no longer in 'estate/' directory
+  knctl apply -f estate/ --prune -n ns1
+
+  # Apply Knative resources from a kustomization directory
+  knctl apply -f overlays/prod/ -n ns1
+
+  # Apply Knative resources from a Helm chart with custom values
+  knctl apply -f chart/ --set image.tag=v2 -n ns1`,
+		Annotations: map[string]string{
+			cmdcore.OtherHelpGroup.Key: cmdcore.OtherHelpGroup.Value,
+		},
+		RunE: func(_ *cobra.Command, _ []string) error { return o.Run() },
+	}
+	o.NamespaceFlags.Set(cmd, flagsFactory)
+	o.ManifestFlags.Set(cmd, flagsFactory)
+	cmd.Flags().BoolVar(&o.Prune, "prune", false, "Delete previously applied resources not found in given files (only kinds present in given files are considered)")
+	return cmd
+}
+
+func (o *ApplyOptions) Run() error {
+	discoveryClient, err := o.depsFactory.DiscoveryClient()
+	if err != nil {
+		return err
+	}
+
+	dynamicClient, err := o.depsFactory.DynamicClient()
+	if err != nil {
+		return err
+	}
+
+	resources, err := bundle.Resources(discoveryClient)
+	if err != nil {
+		return err
+	}
+
+	applier := bundle.NewApplier(dynamicClient, resources)
+
+	objs, err := o.ManifestFlags.Objects(applier, o.ui)
+	if err != nil {
+		return err
+	}
+
+	changes, err := applier.Apply(o.NamespaceFlags.Name, objs)
+	if err == nil && o.Prune {
+		var pruneChanges []bundle.Change
+		pruneChanges, err = applier.Prune(o.NamespaceFlags.Name, objs)
+		changes = append(changes, pruneChanges...)
+	}
+
+	// Show changes that were made even if some failed
+	o.printChanges(changes)
+
+	return err
+}
+
+func (o *ApplyOptions) printChanges(changes []bundle.Change) {
+	table := uitable.Table{
+		Title:   fmt.Sprintf("Changes in namespace '%s'", o.NamespaceFlags.Name),
+		Content: "changes",
+
+		Header: []uitable.Header{
+			uitable.NewHeader("Kind"),
+			uitable.NewHeader("Name"),
+			uitable.NewHeader("Action"),
+		},
+	}
+
+	for _, change := range changes {
+		table.Rows = append(table.Rows, []uitable.Value{
+			uitable.NewValueString(change.Kind),
+			uitable.NewValueString(change.Name),
+			uitable.NewValueString(string(change.Action)),
+		})
+	}
+
+	o.ui.PrintTable(table)
+}
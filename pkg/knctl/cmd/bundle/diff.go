@@ -0,0 +1,141 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bundle
+
+import (
+	"fmt"
+
+	"github.com/cppforlife/go-cli-ui/ui"
+	uitable "github.com/cppforlife/go-cli-ui/ui/table"
+	"github.com/cppforlife/knctl/pkg/knctl/bundle"
+	cmdcore "github.com/cppforlife/knctl/pkg/knctl/cmd/core"
+	"github.com/spf13/cobra"
+)
+
+type DiffOptions struct {
+	ui          ui.UI
+	depsFactory cmdcore.DepsFactory
+
+	NamespaceFlags cmdcore.NamespaceFlags
+	ManifestFlags  ManifestFlags
+	ExitCode       bool
+}
+
+func NewDiffOptions(ui ui.UI, depsFactory cmdcore.DepsFactory) *DiffOptions {
+	return &DiffOptions{ui: ui, depsFactory: depsFactory}
+}
+
+func NewDiffCmd(o *DiffOptions, flagsFactory cmdcore.FlagsFactory) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "diff",
+		Short: "Show differences between YAML files and live Knative resources",
+		Long: `Show differences between YAML files and live Knative resources.
+
+Resources are applied using server-side dry run (requires Kubernetes 1.13+)
+so that defaulting and validation performed by the API server and Knative webhooks
+is included in the comparison. Nothing is persisted.
+
+Accepts same inputs as 'knctl apply' (YAML files, kustomization directories and Helm charts).`,
+		Example: `
+  # Show differences between 'estate/' directory and resources in namespace 'ns1'
+  knctl diff -f estate/ -n ns1
+
+  # Fail when there are differences (e.g. in CI)
+  knctl diff -f estate/ --exit-code -n ns1`,
+		Annotations: map[string]string{
+			cmdcore.OtherHelpGroup.Key: cmdcore.OtherHelpGroup.Value,
+		},
+		RunE: func(_ *cobra.Command, _ []string) error { return o.Run() },
+	}
+	o.NamespaceFlags.Set(cmd, flagsFactory)
+	o.ManifestFlags.Set(cmd, flagsFactory)
+	cmd.Flags().BoolVar(&o.ExitCode, "exit-code", false, "Return error if there are differences")
+	return cmd
+}
+
+func (o *DiffOptions) Run() error {
+	discoveryClient, err := o.depsFactory.DiscoveryClient()
+	if err != nil {
+		return err
+	}
+
+	dryRunClient, err := o.depsFactory.DryRunDynamicClient()
+	if err != nil {
+		return err
+	}
+
+	resources, err := bundle.Resources(discoveryClient)
+	if err != nil {
+		return err
+	}
+
+	applier := bundle.NewApplier(dryRunClient, resources)
+
+	objs, err := o.ManifestFlags.Objects(applier, o.ui)
+	if err != nil {
+		return err
+	}
+
+	changes, err := applier.Apply(o.NamespaceFlags.Name, objs)
+	if err != nil {
+		return err
+	}
+
+	table := uitable.Table{
+		Title:   fmt.Sprintf("Differences in namespace '%s'", o.NamespaceFlags.Name),
+		Content: "differences",
+
+		Header: []uitable.Header{
+			uitable.NewHeader("Kind"),
+			uitable.NewHeader("Name"),
+			uitable.NewHeader("Path"),
+			uitable.NewHeader("Live"),
+			uitable.NewHeader("Manifest"),
+		},
+	}
+
+	for _, change := range changes {
+		if change.Action == bundle.ChangeActionCreate {
+			table.Rows = append(table.Rows, []uitable.Value{
+				uitable.NewValueString(change.Kind),
+				uitable.NewValueString(change.Name),
+				uitable.NewValueString("(new resource)"),
+				uitable.NewValueString(""),
+				uitable.NewValueString(""),
+			})
+			continue
+		}
+
+		for _, diff := range bundle.Diff(change.Previous, change.Current) {
+			table.Rows = append(table.Rows, []uitable.Value{
+				uitable.NewValueString(change.Kind),
+				uitable.NewValueString(change.Name),
+				uitable.NewValueString(diff.Path),
+				uitable.NewValueString(diff.Previous),
+				uitable.NewValueString(diff.Current),
+			})
+		}
+	}
+
+	o.ui.PrintTable(table)
+
+	if o.ExitCode && len(table.Rows) > 0 {
+		return fmt.Errorf("Expected no differences, but found %d", len(table.Rows))
+	}
+
+	return nil
+}
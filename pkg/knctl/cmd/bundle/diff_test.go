@@ -0,0 +1,50 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bundle_test
+
+import (
+	"testing"
+
+	. "github.com/cppforlife/knctl/pkg/knctl/cmd"
+	. "github.com/cppforlife/knctl/pkg/knctl/cmd/bundle"
+	cmdcore "github.com/cppforlife/knctl/pkg/knctl/cmd/core"
+)
+
+func TestNewDiffCmd_Ok(t *testing.T) {
+	realCmd := NewDiffOptions(nil, cmdcore.NewDepsFactory())
+	cmd := NewTestCmd(t, NewDiffCmd(realCmd, cmdcore.FlagsFactory{}))
+	cmd.ExpectBasicConfig()
+	cmd.Execute([]string{
+		"-n", "test-namespace",
+		"-f", "test-dir",
+		"--set", "a=b",
+		"--exit-code",
+	})
+	cmd.ExpectReachesExecution()
+
+	DeepEqual(t, realCmd.NamespaceFlags.Name, "test-namespace")
+	DeepEqual(t, realCmd.ManifestFlags.File, "test-dir")
+	DeepEqual(t, realCmd.ManifestFlags.HelmValues, []string{"a=b"})
+	DeepEqual(t, realCmd.ExitCode, true)
+}
+
+func TestNewDiffCmd_RequiredFlags(t *testing.T) {
+	realCmd := NewDiffOptions(nil, cmdcore.NewDepsFactory())
+	cmd := NewTestCmd(t, NewDiffCmd(realCmd, cmdcore.FlagsFactory{}))
+	cmd.Execute([]string{})
+	cmd.ExpectRequiredFlags([]string{"file"})
+}
@@ -0,0 +1,114 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bundle
+
+import (
+	"fmt"
+
+	"github.com/cppforlife/go-cli-ui/ui"
+	uitable "github.com/cppforlife/go-cli-ui/ui/table"
+	"github.com/cppforlife/knctl/pkg/knctl/bundle"
+	cmdcore "github.com/cppforlife/knctl/pkg/knctl/cmd/core"
+	"github.com/spf13/cobra"
+)
+
+type ExportOptions struct {
+	ui          ui.UI
+	depsFactory cmdcore.DepsFactory
+
+	NamespaceFlags cmdcore.NamespaceFlags
+	Output         string
+}
+
+func NewExportOptions(ui ui.UI, depsFactory cmdcore.DepsFactory) *ExportOptions {
+	return &ExportOptions{ui: ui, depsFactory: depsFactory}
+}
+
+func NewExportCmd(o *ExportOptions, flagsFactory cmdcore.FlagsFactory) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "Export Knative resources in a namespace as YAML files",
+		Long: `Export Knative resources in a namespace as YAML files.
+
+Includes Knative services, standalone routes, triggers and event sources.
+Exported resources are stripped of status and server populated metadata
+(including namespace) so that they could be applied to any namespace or cluster.`,
+		Example: `
+  # Export all Knative resources in namespace 'ns1' into 'estate/' directory
+  knctl export -n ns1 -o estate/`,
+		Annotations: map[string]string{
+			cmdcore.OtherHelpGroup.Key: cmdcore.OtherHelpGroup.Value,
+		},
+		RunE: func(_ *cobra.Command, _ []string) error { return o.Run() },
+	}
+	o.NamespaceFlags.Set(cmd, flagsFactory)
+	cmd.Flags().StringVarP(&o.Output, "output", "o", "", "Set output directory (will be created if does not exist)")
+	cmd.MarkFlagRequired("output")
+	return cmd
+}
+
+func (o *ExportOptions) Run() error {
+	discoveryClient, err := o.depsFactory.DiscoveryClient()
+	if err != nil {
+		return err
+	}
+
+	dynamicClient, err := o.depsFactory.DynamicClient()
+	if err != nil {
+		return err
+	}
+
+	resources, err := bundle.Resources(discoveryClient)
+	if err != nil {
+		return err
+	}
+
+	exporter := bundle.NewExporter(dynamicClient, resources)
+
+	objs, err := exporter.Export(o.NamespaceFlags.Name)
+	if err != nil {
+		return fmt.Errorf("Exporting namespace '%s': %s", o.NamespaceFlags.Name, err)
+	}
+
+	paths, err := exporter.WriteDir(o.Output, objs)
+	if err != nil {
+		return err
+	}
+
+	table := uitable.Table{
+		Title:   fmt.Sprintf("Exported resources in namespace '%s'", o.NamespaceFlags.Name),
+		Content: "resources",
+
+		Header: []uitable.Header{
+			uitable.NewHeader("Kind"),
+			uitable.NewHeader("Name"),
+			uitable.NewHeader("File"),
+		},
+	}
+
+	for i, obj := range objs {
+		table.Rows = append(table.Rows, []uitable.Value{
+			uitable.NewValueString(obj.GetKind()),
+			uitable.NewValueString(obj.GetName()),
+			uitable.NewValueString(paths[i]),
+		})
+	}
+
+	o.ui.PrintTable(table)
+
+	return nil
+}
@@ -0,0 +1,59 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bundle
+
+import (
+	"github.com/cppforlife/go-cli-ui/ui"
+	"github.com/cppforlife/knctl/pkg/knctl/bundle"
+	cmdcore "github.com/cppforlife/knctl/pkg/knctl/cmd/core"
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+type ManifestFlags struct {
+	File       string
+	HelmValues []string
+}
+
+func (s *ManifestFlags) Set(cmd *cobra.Command, flagsFactory cmdcore.FlagsFactory) {
+	cmd.Flags().StringVarP(&s.File, "file", "f", "", "Set path to YAML file or directory with YAML files")
+	cmd.Flags().StringArrayVar(&s.HelmValues, "set", nil, "Set Helm chart value (format: key=value) (can be specified multiple times)")
+	cmd.MarkFlagRequired("file")
+}
+
+// Objects renders manifest and returns Knative resources from it.
+// Rendered templates commonly include other resources (e.g. config maps),
+// hence such resources are skipped; plain YAML files are expected
+// to only contain Knative resources.
+func (s *ManifestFlags) Objects(applier bundle.Applier, ui ui.UI) ([]unstructured.Unstructured, error) {
+	objs, inputType, err := bundle.Renderer{HelmValues: s.HelmValues}.Render(s.File)
+	if err != nil {
+		return nil, err
+	}
+
+	if inputType != bundle.InputTypeYAML {
+		var skippedObjs []unstructured.Unstructured
+
+		objs, skippedObjs = applier.Partition(objs)
+
+		for _, obj := range skippedObjs {
+			ui.PrintLinef("Skipping non-Knative resource %s '%s'", obj.GetKind(), obj.GetName())
+		}
+	}
+
+	return objs, nil
+}
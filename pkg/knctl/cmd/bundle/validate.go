@@ -0,0 +1,109 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bundle
+
+import (
+	"fmt"
+
+	"github.com/cppforlife/go-cli-ui/ui"
+	uitable "github.com/cppforlife/go-cli-ui/ui/table"
+	"github.com/cppforlife/knctl/pkg/knctl/bundle"
+	cmdcore "github.com/cppforlife/knctl/pkg/knctl/cmd/core"
+	"github.com/spf13/cobra"
+)
+
+type ValidateOptions struct {
+	ui ui.UI
+
+	ManifestFlags ManifestFlags
+}
+
+func NewValidateOptions(ui ui.UI) *ValidateOptions {
+	return &ValidateOptions{ui: ui}
+}
+
+func NewValidateCmd(o *ValidateOptions, flagsFactory cmdcore.FlagsFactory) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "validate",
+		Short: "Validate Knative resources in YAML files without cluster access",
+		Long: `Validate Knative resources in YAML files without cluster access.
+
+Knative services, configurations and routes are checked against
+Knative API types built into knctl (unknown or mistyped fields are reported),
+against rules enforced by Knative's admission webhook
+(e.g. traffic percentages sum to 100, scale bounds annotations)
+and against knctl's rules (e.g. autoscaling target and class annotations).
+
+Other resources are skipped. Exits with non-zero status if any issues are found,
+hence it is suitable for use in pre-commit hooks and CI.`,
+		Example: `
+  # Validate service defined in 'service.yml'
+  knctl validate -f service.yml
+
+  # Validate all resources in 'estate/' directory
+  knctl validate -f estate/
+
+  # Validate Knative resources rendered from a Helm chart
+  knctl validate -f chart/ --set image.tag=v2`,
+		Annotations: map[string]string{
+			cmdcore.OtherHelpGroup.Key: cmdcore.OtherHelpGroup.Value,
+		},
+		RunE: func(_ *cobra.Command, _ []string) error { return o.Run() },
+	}
+	o.ManifestFlags.Set(cmd, flagsFactory)
+	return cmd
+}
+
+func (o *ValidateOptions) Run() error {
+	objs, _, err := bundle.Renderer{HelmValues: o.ManifestFlags.HelmValues}.Render(o.ManifestFlags.File)
+	if err != nil {
+		return err
+	}
+
+	issues, skippedObjs := bundle.NewValidator().Validate(objs)
+
+	for _, obj := range skippedObjs {
+		o.ui.PrintLinef("Skipping %s '%s' since it cannot be validated offline", obj.GetKind(), obj.GetName())
+	}
+
+	table := uitable.Table{
+		Title:   "Validation issues",
+		Content: "issues",
+
+		Header: []uitable.Header{
+			uitable.NewHeader("Kind"),
+			uitable.NewHeader("Name"),
+			uitable.NewHeader("Issue"),
+		},
+	}
+
+	for _, issue := range issues {
+		table.Rows = append(table.Rows, []uitable.Value{
+			uitable.NewValueString(issue.Kind),
+			uitable.NewValueString(issue.Name),
+			uitable.NewValueString(issue.Message),
+		})
+	}
+
+	o.ui.PrintTable(table)
+
+	if len(issues) > 0 {
+		return fmt.Errorf("Expected resources to be valid, but found %d issue(s)", len(issues))
+	}
+
+	return nil
+}
@@ -0,0 +1,67 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cache
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/cppforlife/go-cli-ui/ui"
+	"github.com/cppforlife/knctl/pkg/knctl/cache"
+	cmdcore "github.com/cppforlife/knctl/pkg/knctl/cmd/core"
+	"github.com/spf13/cobra"
+)
+
+type ClearOptions struct {
+	ui ui.UI
+}
+
+func NewClearOptions(ui ui.UI) *ClearOptions {
+	return &ClearOptions{ui: ui}
+}
+
+func NewClearCmd(o *ClearOptions, flagsFactory cmdcore.FlagsFactory) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "clear",
+		Short: "Clear cache",
+		Long: `Clear cache.
+
+Removes cached API discovery information, namespaces and ingress addresses for all clusters.
+Caching is enabled via --cache-ttl flag or $KNCTL_CACHE_TTL.`,
+		Example: `
+  # Clear cache
+  knctl cache clear`,
+		RunE: func(_ *cobra.Command, _ []string) error { return o.Run() },
+	}
+	return cmd
+}
+
+func (o *ClearOptions) Run() error {
+	rootDir, err := cache.DefaultRootDir()
+	if err != nil {
+		return err
+	}
+
+	err = os.RemoveAll(rootDir)
+	if err != nil {
+		return fmt.Errorf("Removing cache directory: %s", err)
+	}
+
+	o.ui.PrintLinef("Cleared cache in '%s'", rootDir)
+
+	return nil
+}
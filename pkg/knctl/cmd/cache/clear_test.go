@@ -0,0 +1,65 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cache_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/cppforlife/go-cli-ui/ui"
+	. "github.com/cppforlife/knctl/pkg/knctl/cmd"
+	. "github.com/cppforlife/knctl/pkg/knctl/cmd/cache"
+	cmdcore "github.com/cppforlife/knctl/pkg/knctl/cmd/core"
+)
+
+func TestNewClearCmd_Ok(t *testing.T) {
+	realCmd := NewClearOptions(nil)
+	cmd := NewTestCmd(t, NewClearCmd(realCmd, cmdcore.FlagsFactory{}))
+	cmd.ExpectBasicConfig()
+	cmd.Execute([]string{})
+	cmd.ExpectReachesExecution()
+}
+
+func TestClearOptions_RemovesCacheDir(t *testing.T) {
+	homeDir, err := ioutil.TempDir("", "knctl-home")
+	if err != nil {
+		t.Fatalf("Creating temp dir: %s", err)
+	}
+	defer os.RemoveAll(homeDir)
+
+	origHomeDir := os.Getenv("HOME")
+	os.Setenv("HOME", homeDir)
+	defer os.Setenv("HOME", origHomeDir)
+
+	cacheDir := filepath.Join(homeDir, ".knctl", "cache", "host")
+
+	err = os.MkdirAll(cacheDir, 0700)
+	if err != nil {
+		t.Fatalf("Creating cache dir: %s", err)
+	}
+
+	err = NewClearOptions(ui.NewNoopUI()).Run()
+	if err != nil {
+		t.Fatalf("Expected clearing cache to succeed: %s", err)
+	}
+
+	if _, err := os.Stat(cacheDir); !os.IsNotExist(err) {
+		t.Fatalf("Expected cache dir to be removed: %s", err)
+	}
+}
@@ -0,0 +1,62 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"github.com/cppforlife/go-cli-ui/ui"
+	cmdcore "github.com/cppforlife/knctl/pkg/knctl/cmd/core"
+	"github.com/spf13/cobra"
+)
+
+type UseContextOptions struct {
+	ui            ui.UI
+	configFactory cmdcore.ConfigFactory
+
+	Context string
+}
+
+func NewUseContextOptions(ui ui.UI, configFactory cmdcore.ConfigFactory) *UseContextOptions {
+	return &UseContextOptions{ui: ui, configFactory: configFactory}
+}
+
+func NewUseContextCmd(o *UseContextOptions, flagsFactory cmdcore.FlagsFactory) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "use-context",
+		Short: "Set current context",
+		Long:  "Set current context in kubeconfig file",
+		Example: `
+  # Use context 'prod' for subsequent commands
+  knctl config use-context prod`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(_ *cobra.Command, args []string) error {
+			o.Context = args[0]
+			return o.Run()
+		},
+	}
+	return cmd
+}
+
+func (o *UseContextOptions) Run() error {
+	err := o.configFactory.UseContext(o.Context)
+	if err != nil {
+		return err
+	}
+
+	o.ui.PrintLinef("Switched to context '%s'", o.Context)
+
+	return nil
+}
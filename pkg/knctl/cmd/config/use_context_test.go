@@ -0,0 +1,106 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config_test
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/cppforlife/go-cli-ui/ui"
+	. "github.com/cppforlife/knctl/pkg/knctl/cmd"
+	. "github.com/cppforlife/knctl/pkg/knctl/cmd/config"
+	cmdcore "github.com/cppforlife/knctl/pkg/knctl/cmd/core"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+func TestNewUseContextCmd_Ok(t *testing.T) {
+	realCmd := NewUseContextOptions(nil, cmdcore.NewConfigFactoryImpl())
+	cmd := NewTestCmd(t, NewUseContextCmd(realCmd, cmdcore.FlagsFactory{}))
+	cmd.ExpectBasicConfig()
+	cmd.Execute([]string{"test-context"})
+	cmd.ExpectReachesExecution()
+}
+
+func TestNewUseContextCmd_RequiresContext(t *testing.T) {
+	realCmd := NewUseContextOptions(nil, cmdcore.NewConfigFactoryImpl())
+	cmd := NewTestCmd(t, NewUseContextCmd(realCmd, cmdcore.FlagsFactory{}))
+	cmd.Execute([]string{})
+	cmd.ExpectErr("accepts 1 arg(s), received 0")
+}
+
+func TestUseContextOptions_UpdatesCurrentContext(t *testing.T) {
+	file, err := ioutil.TempFile("", "knctl-kubeconfig")
+	if err != nil {
+		t.Fatalf("Creating temp file: %s", err)
+	}
+	defer os.Remove(file.Name())
+
+	_, err = file.Write([]byte(`
+apiVersion: v1
+kind: Config
+current-context: ctx1
+clusters:
+- name: cluster1
+  cluster: {server: "https://127.0.0.1:6443"}
+users:
+- name: user1
+  user: {token: token1}
+contexts:
+- name: ctx1
+  context: {cluster: cluster1, user: user1}
+- name: ctx2
+  context: {cluster: cluster1, user: user1}
+`))
+	if err != nil {
+		t.Fatalf("Writing temp file: %s", err)
+	}
+	file.Close()
+
+	configFactory := cmdcore.NewConfigFactoryImpl()
+	configFactory.ConfigurePathResolver(func() (string, error) { return file.Name(), nil })
+	configFactory.ConfigureContextResolver(func() (string, error) { return "", nil })
+
+	contexts, err := configFactory.Contexts()
+	if err != nil {
+		t.Fatalf("Expected listing contexts to succeed: %s", err)
+	}
+
+	DeepEqual(t, contexts, []string{"ctx1", "ctx2"})
+
+	useContextOpts := NewUseContextOptions(ui.NewNoopUI(), configFactory)
+	useContextOpts.Context = "ctx2"
+
+	err = useContextOpts.Run()
+	if err != nil {
+		t.Fatalf("Expected use-context to succeed: %s", err)
+	}
+
+	config, err := clientcmd.LoadFromFile(file.Name())
+	if err != nil {
+		t.Fatalf("Loading config: %s", err)
+	}
+
+	DeepEqual(t, config.CurrentContext, "ctx2")
+
+	useContextOpts.Context = "ctx3"
+
+	err = useContextOpts.Run()
+	if err == nil {
+		t.Fatalf("Expected use-context to fail for unknown context")
+	}
+}
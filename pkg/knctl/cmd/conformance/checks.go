@@ -0,0 +1,536 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package conformance
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/cppforlife/knctl/pkg/knctl/api"
+	cmdcore "github.com/cppforlife/knctl/pkg/knctl/cmd/core"
+	"github.com/cppforlife/knctl/pkg/knctl/junit"
+	ctlservice "github.com/cppforlife/knctl/pkg/knctl/service"
+	"github.com/knative/serving/pkg/apis/serving"
+	"github.com/knative/serving/pkg/apis/serving/v1alpha1"
+	"golang.org/x/net/http2"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+const (
+	CheckProbing           = "probing"
+	CheckHeaderPropagation = "header-propagation"
+	CheckGRPC              = "grpc"
+	CheckScaleBounds       = "scale-bounds"
+
+	servicePrefix = "knctl-conformance-"
+	headerName    = "Knctl-Conformance-Id"
+)
+
+var (
+	CheckNames = []string{CheckProbing, CheckHeaderPropagation, CheckGRPC, CheckScaleBounds}
+)
+
+// SkipError indicates that check could not be run against the cluster
+type SkipError struct {
+	Reason string
+}
+
+func (e SkipError) Error() string { return e.Reason }
+
+type Check struct {
+	Name string
+	Run  func(ctx context.Context) error
+}
+
+// Checks are a curated subset of Knative Serving conformance checks
+// performed with knctl's own deploy and curl operations. Each check
+// deploys its own service so that checks can be run independently.
+type Checks struct {
+	client      *api.Client
+	depsFactory cmdcore.DepsFactory
+
+	Namespace string
+	Image     string
+	GRPCImage string
+	Timeout   time.Duration
+	Keep      bool
+
+	PollInterval time.Duration
+}
+
+func NewChecks(client *api.Client, depsFactory cmdcore.DepsFactory, namespace string) Checks {
+	return Checks{
+		client:      client,
+		depsFactory: depsFactory,
+		Namespace:   namespace,
+
+		Timeout:      5 * time.Minute,
+		PollInterval: 2 * time.Second,
+	}
+}
+
+func (c Checks) All() []Check {
+	return []Check{
+		{CheckProbing, c.Probing},
+		{CheckHeaderPropagation, c.HeaderPropagation},
+		{CheckGRPC, c.GRPC},
+		{CheckScaleBounds, c.ScaleBounds},
+	}
+}
+
+// Probing checks that deployed service becomes ready and is reachable via ingress
+func (c Checks) Probing(ctx context.Context) error {
+	serviceName := servicePrefix + CheckProbing
+
+	err := c.deploy(ctx, api.DeployOpts{Service: serviceName, Image: c.Image})
+	if err != nil {
+		return err
+	}
+
+	defer c.delete(serviceName)
+
+	_, err = c.getUntilOK(ctx, serviceName, http.Header{})
+	return err
+}
+
+// HeaderPropagation checks that request headers reach application
+// (requires application to include received headers in response body)
+func (c Checks) HeaderPropagation(ctx context.Context) error {
+	serviceName := servicePrefix + CheckHeaderPropagation
+
+	err := c.deploy(ctx, api.DeployOpts{Service: serviceName, Image: c.Image})
+	if err != nil {
+		return err
+	}
+
+	defer c.delete(serviceName)
+
+	id := fmt.Sprintf("%d", time.Now().UnixNano())
+
+	body, err := c.getUntilOK(ctx, serviceName, http.Header{headerName: []string{id}})
+	if err != nil {
+		return err
+	}
+
+	if !strings.Contains(body, id) {
+		return fmt.Errorf("Expected response body to include value of '%s' request header", headerName)
+	}
+
+	return nil
+}
+
+// GRPC checks that gRPC requests (HTTP/2 without TLS) are routed to application
+// by calling standard gRPC health check service (grpc.health.v1.Health/Check)
+func (c Checks) GRPC(ctx context.Context) error {
+	if len(c.GRPCImage) == 0 {
+		return SkipError{"gRPC image is not specified"}
+	}
+
+	serviceName := servicePrefix + CheckGRPC
+
+	// Container port has to be named 'h2c' for Knative to route HTTP/2 requests,
+	// which knctl deploy does not support setting hence service is created directly
+	err := c.deployH2C(ctx, serviceName, c.GRPCImage)
+	if err != nil {
+		return err
+	}
+
+	defer c.delete(serviceName)
+
+	domain, url, err := c.addr(serviceName)
+	if err != nil {
+		return err
+	}
+
+	client := &http.Client{
+		Transport: &http2.Transport{
+			AllowHTTP: true,
+			DialTLS: func(network, addr string, _ *tls.Config) (net.Conn, error) {
+				return net.Dial(network, addr)
+			},
+		},
+	}
+
+	var lastErr error
+
+	err = c.poll(ctx, func() (bool, error) {
+		lastErr = c.grpcHealthCheck(ctx, client, url, domain)
+		return lastErr == nil, nil
+	})
+	if err != nil {
+		return fmt.Errorf("Expected gRPC health check to succeed: %s", lastErr)
+	}
+
+	return nil
+}
+
+// ScaleBounds checks that minScale and maxScale annotations are respected
+func (c Checks) ScaleBounds(ctx context.Context) error {
+	const minScale, maxScale = 2, 3
+
+	serviceName := servicePrefix + CheckScaleBounds
+
+	min, max := minScale, maxScale
+
+	err := c.deploy(ctx, api.DeployOpts{Service: serviceName, Image: c.Image, MinScale: &min, MaxScale: &max})
+	if err != nil {
+		return err
+	}
+
+	defer c.delete(serviceName)
+
+	servingClient, err := c.depsFactory.ServingClient()
+	if err != nil {
+		return err
+	}
+
+	coreClient, err := c.depsFactory.CoreClient()
+	if err != nil {
+		return err
+	}
+
+	service, err := servingClient.ServingV1alpha1().Services(c.Namespace).Get(serviceName, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+
+	revisionName := service.Status.LatestReadyRevisionName
+
+	pa, err := servingClient.AutoscalingV1alpha1().PodAutoscalers(c.Namespace).Get(revisionName, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("Getting pod autoscaler: %s", err)
+	}
+
+	if paMin, paMax := pa.ScaleBounds(); paMin != minScale || paMax != maxScale {
+		return fmt.Errorf("Expected pod autoscaler scale bounds to be %d-%d, but was %d-%d", minScale, maxScale, paMin, paMax)
+	}
+
+	listOpts := metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("%s=%s", serving.RevisionLabelKey, revisionName),
+	}
+
+	var readyPods int
+
+	err = c.poll(ctx, func() (bool, error) {
+		pods, err := coreClient.CoreV1().Pods(c.Namespace).List(listOpts)
+		if err != nil {
+			return false, fmt.Errorf("Listing pods: %s", err)
+		}
+
+		readyPods = 0
+
+		for _, pod := range pods.Items {
+			for _, cond := range pod.Status.Conditions {
+				if cond.Type == corev1.PodReady && cond.Status == corev1.ConditionTrue {
+					readyPods++
+				}
+			}
+		}
+
+		if readyPods > maxScale {
+			return false, fmt.Errorf("Expected at most %d ready pods, but found %d", maxScale, readyPods)
+		}
+
+		return readyPods >= minScale, nil
+	})
+	if err == wait.ErrWaitTimeout {
+		return fmt.Errorf("Expected at least %d ready pods, but found %d", minScale, readyPods)
+	}
+
+	return err
+}
+
+func (c Checks) deploy(ctx context.Context, opts api.DeployOpts) error {
+	opts.Namespace = c.Namespace
+	opts.RevisionReadyTimeout = c.Timeout
+
+	err := api.NewDeploy(c.client, opts).Run(ctx)
+	if err != nil {
+		return fmt.Errorf("Deploying service '%s': %s", opts.Service, err)
+	}
+
+	return nil
+}
+
+func (c Checks) deployH2C(ctx context.Context, serviceName, image string) error {
+	servingClient, err := c.depsFactory.ServingClient()
+	if err != nil {
+		return err
+	}
+
+	service := &v1alpha1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: serviceName, Namespace: c.Namespace},
+		Spec: v1alpha1.ServiceSpec{
+			RunLatest: &v1alpha1.RunLatestType{
+				Configuration: v1alpha1.ConfigurationSpec{
+					RevisionTemplate: v1alpha1.RevisionTemplateSpec{
+						Spec: v1alpha1.RevisionSpec{
+							Container: corev1.Container{
+								Image: image,
+								Ports: []corev1.ContainerPort{{Name: "h2c", ContainerPort: 8080}},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	_, err = servingClient.ServingV1alpha1().Services(c.Namespace).Create(service)
+	if err != nil {
+		return fmt.Errorf("Creating service '%s': %s", serviceName, err)
+	}
+
+	w, err := c.depsFactory.Waiter()
+	if err != nil {
+		return err
+	}
+
+	var revisionName string
+
+	err = c.poll(ctx, func() (bool, error) {
+		service, err := servingClient.ServingV1alpha1().Services(c.Namespace).Get(serviceName, metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+		revisionName = service.Status.LatestCreatedRevisionName
+		return len(revisionName) > 0, nil
+	})
+	if err != nil {
+		return fmt.Errorf("Waiting for service '%s' to create revision: %s", serviceName, err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, c.Timeout)
+	defer cancel()
+
+	cancelCh := make(chan struct{})
+	go func() {
+		<-ctx.Done()
+		close(cancelCh)
+	}()
+
+	_, ready, err := w.RevisionReady(c.Namespace, revisionName, cancelCh)
+	if err != nil {
+		return err
+	}
+
+	if !ready {
+		return fmt.Errorf("Expected revision '%s' to become ready: %s", revisionName, ctx.Err())
+	}
+
+	return nil
+}
+
+func (c Checks) delete(serviceName string) {
+	if c.Keep {
+		return
+	}
+	// Deletion failures do not affect outcome of the check
+	api.NewDelete(c.client, api.DeleteOpts{Namespace: c.Namespace, Service: serviceName, Force: true}).Run(context.Background())
+}
+
+// getUntilOK retries request until service responds with 200
+// since ingress may take a bit of time to start routing to new services
+func (c Checks) getUntilOK(ctx context.Context, serviceName string, header http.Header) (string, error) {
+	var body string
+	var lastErr error
+
+	err := c.poll(ctx, func() (bool, error) {
+		resp, err := api.NewCurlRequest(c.client, api.CurlRequestOpts{
+			Namespace: c.Namespace,
+			Service:   serviceName,
+			Header:    header,
+		}).Run(ctx)
+		if err != nil {
+			lastErr = err
+			return false, nil
+		}
+
+		defer resp.Body.Close()
+
+		bs, err := ioutil.ReadAll(resp.Body)
+		if err != nil {
+			lastErr = fmt.Errorf("Reading response: %s", err)
+			return false, nil
+		}
+
+		if resp.StatusCode != http.StatusOK {
+			lastErr = fmt.Errorf("Response status was %d", resp.StatusCode)
+			return false, nil
+		}
+
+		body = string(bs)
+
+		return true, nil
+	})
+	if err != nil {
+		return "", fmt.Errorf("Expected service '%s' to respond with 200: %s", serviceName, lastErr)
+	}
+
+	return body, nil
+}
+
+func (Checks) grpcHealthCheck(ctx context.Context, client *http.Client, url, domain string) error {
+	// Length-prefixed empty HealthCheckRequest message (checks overall server health)
+	req, err := http.NewRequest("POST", url+"/grpc.health.v1.Health/Check", bytes.NewReader([]byte{0, 0, 0, 0, 0}))
+	if err != nil {
+		return err
+	}
+
+	req.Host = domain
+	req.Header.Set("Content-Type", "application/grpc")
+	req.Header.Set("TE", "trailers")
+
+	resp, err := client.Do(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+
+	defer resp.Body.Close()
+
+	ioutil.ReadAll(resp.Body)
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("Response status was %d", resp.StatusCode)
+	}
+
+	// Responses without message include status in headers instead of trailers
+	status := resp.Trailer.Get("Grpc-Status")
+	if len(status) == 0 {
+		status = resp.Header.Get("Grpc-Status")
+	}
+
+	if status != "0" {
+		return fmt.Errorf("gRPC status was '%s'", status)
+	}
+
+	return nil
+}
+
+func (c Checks) addr(serviceName string) (string, string, error) {
+	servingClient, err := c.depsFactory.ServingClient()
+	if err != nil {
+		return "", "", err
+	}
+
+	coreClient, err := c.depsFactory.CoreClient()
+	if err != nil {
+		return "", "", err
+	}
+
+	service, err := servingClient.ServingV1alpha1().Services(c.Namespace).Get(serviceName, metav1.GetOptions{})
+	if err != nil {
+		return "", "", err
+	}
+
+	cache, err := c.depsFactory.Cache()
+	if err != nil {
+		return "", "", err
+	}
+
+	logger, err := c.depsFactory.Logger()
+	if err != nil {
+		return "", "", err
+	}
+
+	inCluster, err := c.depsFactory.InCluster()
+	if err != nil {
+		return "", "", err
+	}
+
+	serviceAddr := ctlservice.NewServiceAddress(service, coreClient, cache, logger, inCluster)
+
+	domain, err := serviceAddr.Domain()
+	if err != nil {
+		return "", "", err
+	}
+
+	url, err := serviceAddr.URL(80, false)
+	if err != nil {
+		return "", "", err
+	}
+
+	return domain, url, nil
+}
+
+func (c Checks) poll(ctx context.Context, condFunc wait.ConditionFunc) error {
+	ctx, cancel := context.WithTimeout(ctx, c.Timeout)
+	defer cancel()
+
+	return wait.PollImmediateUntil(c.PollInterval, condFunc, ctx.Done())
+}
+
+// SelectChecks returns checks with given names in order of names;
+// all checks are returned if no names are given
+func SelectChecks(checks []Check, names []string) ([]Check, error) {
+	if len(names) == 0 {
+		return checks, nil
+	}
+
+	var result []Check
+
+	for _, name := range names {
+		var found bool
+
+		for _, check := range checks {
+			if check.Name == name {
+				result = append(result, check)
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			return nil, fmt.Errorf("Expected check '%s' to be one of: %s", name, strings.Join(CheckNames, ", "))
+		}
+	}
+
+	return result, nil
+}
+
+// RunChecks runs checks one by one; beforeFunc is called before each check
+func RunChecks(ctx context.Context, checks []Check, beforeFunc func(Check)) []junit.Result {
+	var results []junit.Result
+
+	for _, check := range checks {
+		beforeFunc(check)
+
+		startedAt := time.Now()
+		err := check.Run(ctx)
+
+		result := junit.Result{Name: check.Name, Duration: time.Since(startedAt)}
+
+		if skipErr, ok := err.(SkipError); ok {
+			result.SkipReason = skipErr.Reason
+		} else {
+			result.Err = err
+		}
+
+		results = append(results, result)
+	}
+
+	return results
+}
@@ -0,0 +1,74 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package conformance_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	. "github.com/cppforlife/knctl/pkg/knctl/cmd"
+	. "github.com/cppforlife/knctl/pkg/knctl/cmd/conformance"
+	"github.com/cppforlife/knctl/pkg/knctl/junit"
+)
+
+func TestSelectChecks(t *testing.T) {
+	checks := []Check{{Name: "probing"}, {Name: "grpc"}, {Name: "scale-bounds"}}
+
+	selected, err := SelectChecks(checks, nil)
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+	DeepEqual(t, len(selected), 3)
+
+	selected, err = SelectChecks(checks, []string{"scale-bounds", "probing"})
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+	DeepEqual(t, []string{selected[0].Name, selected[1].Name}, []string{"scale-bounds", "probing"})
+
+	_, err = SelectChecks(checks, []string{"unknown"})
+	if err == nil {
+		t.Fatalf("Expected error for unknown check")
+	}
+}
+
+func TestRunChecks(t *testing.T) {
+	checks := []Check{
+		{"pass", func(context.Context) error { return nil }},
+		{"skip", func(context.Context) error { return SkipError{"not supported"} }},
+		{"fail", func(context.Context) error { return fmt.Errorf("failed") }},
+	}
+
+	var started []string
+
+	results := RunChecks(context.Background(), checks, func(check Check) {
+		started = append(started, check.Name)
+	})
+
+	DeepEqual(t, started, []string{"pass", "skip", "fail"})
+
+	for i := range results {
+		results[i].Duration = 0
+	}
+
+	DeepEqual(t, results, []junit.Result{
+		{Name: "pass"},
+		{Name: "skip", SkipReason: "not supported"},
+		{Name: "fail", Err: fmt.Errorf("failed")},
+	})
+}
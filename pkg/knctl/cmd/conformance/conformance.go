@@ -0,0 +1,185 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package conformance
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/cppforlife/go-cli-ui/ui"
+	uitable "github.com/cppforlife/go-cli-ui/ui/table"
+	"github.com/cppforlife/knctl/pkg/knctl/api"
+	cmdcore "github.com/cppforlife/knctl/pkg/knctl/cmd/core"
+	"github.com/cppforlife/knctl/pkg/knctl/junit"
+	"github.com/spf13/cobra"
+)
+
+type ConformanceOptions struct {
+	ui            ui.UI
+	configFactory cmdcore.ConfigFactory
+	depsFactory   cmdcore.DepsFactory
+	cancelSignals cmdcore.CancelWatcher
+
+	NamespaceFlags cmdcore.NamespaceFlags
+	Checks         []string
+	Image          string
+	GRPCImage      string
+	Timeout        time.Duration
+	Keep           bool
+	JUnitReport    string
+}
+
+func NewConformanceOptions(ui ui.UI, configFactory cmdcore.ConfigFactory, depsFactory cmdcore.DepsFactory, cancelSignals cmdcore.CancelWatcher) *ConformanceOptions {
+	return &ConformanceOptions{ui: ui, configFactory: configFactory, depsFactory: depsFactory, cancelSignals: cancelSignals}
+}
+
+func NewConformanceCmd(o *ConformanceOptions, flagsFactory cmdcore.FlagsFactory) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "conformance",
+		Short: "Run conformance checks against cluster",
+		Long: `Run a curated subset of Knative Serving conformance checks against cluster.
+
+Checks deploy services (prefixed with 'knctl-conformance-') to given namespace
+using the same deploy and curl operations as knctl commands:
+
+  - probing: service becomes ready and responds via ingress
+  - header-propagation: request headers reach application
+    (image must include received request headers in response body)
+  - grpc: gRPC health check request reaches application over HTTP/2
+    (skipped unless --grpc-image is specified; image must serve
+    grpc.health.v1.Health on port 8080)
+  - scale-bounds: minScale and maxScale annotations are respected
+
+Services are deleted after each check unless --keep is specified.`,
+		Example: `
+  # Run all conformance checks in namespace 'ns1'
+  knctl conformance -n ns1
+
+  # Run probing and scale bounds checks and save JUnit report
+  knctl conformance -n ns1 --check probing --check scale-bounds --junit-report report.xml`,
+		Annotations: map[string]string{
+			cmdcore.OtherHelpGroup.Key: cmdcore.OtherHelpGroup.Value,
+		},
+		RunE: func(_ *cobra.Command, _ []string) error { return o.Run() },
+	}
+	o.NamespaceFlags.Set(cmd, flagsFactory)
+	cmd.Flags().StringSliceVar(&o.Checks, "check", nil, "Set check to run (can be specified multiple times; one of: "+strings.Join(CheckNames, ", ")+")")
+	cmd.Flags().StringVar(&o.Image, "image", "ealen/echo-server", "Set image used for deployed services (must respond on / with 200 and echo request headers)")
+	cmd.Flags().StringVar(&o.GRPCImage, "grpc-image", "", "Set image serving gRPC health check used by grpc check")
+	cmd.Flags().DurationVar(&o.Timeout, "timeout", 5*time.Minute, "Set maximum time for each step of a check (e.g. waiting for revision to become ready)")
+	cmd.Flags().BoolVar(&o.Keep, "keep", false, "Keep deployed services after checks complete")
+	cmd.Flags().StringVar(&o.JUnitReport, "junit-report", "", "Write JUnit XML report to given path")
+	return cmd
+}
+
+func (o *ConformanceOptions) Run() error {
+	client := api.NewClientWithFactories(ui.NewNoopUI(), o.configFactory, o.depsFactory)
+
+	checks := NewChecks(client, o.depsFactory, o.NamespaceFlags.Name)
+	checks.Image = o.Image
+	checks.GRPCImage = o.GRPCImage
+	checks.Timeout = o.Timeout
+	checks.Keep = o.Keep
+
+	selected, err := SelectChecks(checks.All(), o.Checks)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	o.cancelSignals.Watch(cancel)
+
+	results := RunChecks(ctx, selected, func(check Check) {
+		o.ui.PrintLinef("Running check '%s'", check.Name)
+	})
+
+	o.printResults(results)
+
+	if len(o.JUnitReport) > 0 {
+		err := o.writeReport(results)
+		if err != nil {
+			return err
+		}
+	}
+
+	var failed int
+
+	for _, result := range results {
+		if result.Err != nil {
+			failed++
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("Expected all conformance checks to pass, but %d of %d failed", failed, len(results))
+	}
+
+	return nil
+}
+
+func (o *ConformanceOptions) printResults(results []junit.Result) {
+	table := uitable.Table{
+		Title:   fmt.Sprintf("Conformance checks in namespace '%s'", o.NamespaceFlags.Name),
+		Content: "checks",
+
+		Header: []uitable.Header{
+			uitable.NewHeader("Check"),
+			uitable.NewHeader("Duration"),
+			uitable.NewHeader("Result"),
+			uitable.NewHeader("Details"),
+		},
+	}
+
+	for _, result := range results {
+		var status, details string
+		var failed bool
+
+		switch {
+		case len(result.SkipReason) > 0:
+			status, details = "skipped", result.SkipReason
+		case result.Err != nil:
+			status, details, failed = "failed", result.Err.Error(), true
+		default:
+			status = "passed"
+		}
+
+		table.Rows = append(table.Rows, []uitable.Value{
+			uitable.NewValueString(result.Name),
+			uitable.NewValueString(result.Duration.Round(time.Second).String()),
+			uitable.ValueFmt{V: uitable.NewValueString(status), Error: failed},
+			uitable.NewValueString(details),
+		})
+	}
+
+	o.ui.PrintTable(table)
+}
+
+func (o *ConformanceOptions) writeReport(results []junit.Result) error {
+	file, err := os.Create(o.JUnitReport)
+	if err != nil {
+		return fmt.Errorf("Creating JUnit report: %s", err)
+	}
+
+	defer file.Close()
+
+	return junit.Write(file, junit.NewTestSuite("knctl-conformance", results))
+}
@@ -0,0 +1,48 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package conformance_test
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/cppforlife/knctl/pkg/knctl/cmd"
+	. "github.com/cppforlife/knctl/pkg/knctl/cmd/conformance"
+	cmdcore "github.com/cppforlife/knctl/pkg/knctl/cmd/core"
+)
+
+func TestNewConformanceCmd_Ok(t *testing.T) {
+	realCmd := NewConformanceOptions(nil, cmdcore.NewConfigFactoryImpl(), cmdcore.NewDepsFactory(), cmdcore.CancelSignals{})
+	cmd := NewTestCmd(t, NewConformanceCmd(realCmd, cmdcore.FlagsFactory{}))
+	cmd.ExpectBasicConfig()
+	cmd.Execute([]string{
+		"-n", "test-namespace",
+		"--check", "probing",
+		"--check", "grpc",
+		"--grpc-image", "grpc-image",
+		"--junit-report", "report.xml",
+	})
+	cmd.ExpectReachesExecution()
+
+	DeepEqual(t, realCmd.NamespaceFlags, cmdcore.NamespaceFlags{"test-namespace"})
+	DeepEqual(t, realCmd.Checks, []string{"probing", "grpc"})
+	DeepEqual(t, realCmd.Image, "ealen/echo-server")
+	DeepEqual(t, realCmd.GRPCImage, "grpc-image")
+	DeepEqual(t, realCmd.Timeout, 5*time.Minute)
+	DeepEqual(t, realCmd.Keep, false)
+	DeepEqual(t, realCmd.JUnitReport, "report.xml")
+}
@@ -0,0 +1,82 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package core
+
+import (
+	"fmt"
+
+	"github.com/cppforlife/go-cli-ui/ui"
+	uitable "github.com/cppforlife/go-cli-ui/ui/table"
+)
+
+// BatchResults collects per-resource outcomes of an operation
+// applied to multiple resources (e.g. services matching selector)
+type BatchResults struct {
+	title  string
+	names  []string
+	errs   []error
+	failed int
+}
+
+func NewBatchResults(title string) *BatchResults {
+	return &BatchResults{title: title}
+}
+
+func (r *BatchResults) Add(name string, err error) {
+	r.names = append(r.names, name)
+	r.errs = append(r.errs, err)
+	if err != nil {
+		r.failed++
+	}
+}
+
+func (r *BatchResults) Print(ui ui.UI) {
+	table := uitable.Table{
+		Title:   r.title,
+		Content: "results",
+
+		Header: []uitable.Header{
+			uitable.NewHeader("Name"),
+			uitable.NewHeader("Result"),
+		},
+	}
+
+	for i, name := range r.names {
+		result := "ok"
+		if r.errs[i] != nil {
+			result = r.errs[i].Error()
+		}
+
+		table.Rows = append(table.Rows, []uitable.Value{
+			uitable.NewValueString(name),
+			uitable.ValueFmt{
+				V:     uitable.NewValueString(result),
+				Error: r.errs[i] != nil,
+			},
+		})
+	}
+
+	ui.PrintTable(table)
+}
+
+// Err returns error if operation failed for at least one resource
+func (r *BatchResults) Err() error {
+	if r.failed > 0 {
+		return fmt.Errorf("Expected operation to succeed for all %d resources, but it failed for %d", len(r.names), r.failed)
+	}
+	return nil
+}
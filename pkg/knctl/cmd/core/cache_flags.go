@@ -0,0 +1,51 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package core
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+type CacheFlags struct {
+	TTL time.Duration
+
+	envErr error
+}
+
+func (f *CacheFlags) Set(cmd *cobra.Command, flagsFactory FlagsFactory) {
+	var defaultTTL time.Duration
+
+	if envVal := os.Getenv("KNCTL_CACHE_TTL"); len(envVal) > 0 {
+		ttl, err := time.ParseDuration(envVal)
+		if err != nil {
+			// Reported once TTL is needed instead of silently disabling caching
+			f.envErr = fmt.Errorf("Expected $KNCTL_CACHE_TTL to be a duration (example: 30s): %s", err)
+		}
+		defaultTTL = ttl
+	}
+
+	cmd.PersistentFlags().DurationVar(&f.TTL, "cache-ttl", defaultTTL,
+		"Cache API discovery information, namespaces and ingress addresses on disk for given duration (example: 30s) ($KNCTL_CACHE_TTL)")
+}
+
+func (f *CacheFlags) TTLValue() (time.Duration, error) {
+	return f.TTL, f.envErr
+}
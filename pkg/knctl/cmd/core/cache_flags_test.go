@@ -0,0 +1,51 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package core_test
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/cppforlife/knctl/pkg/knctl/cmd/core"
+	"github.com/spf13/cobra"
+)
+
+func TestCacheFlags_EnvTTL(t *testing.T) {
+	defer os.Unsetenv("KNCTL_CACHE_TTL")
+
+	os.Setenv("KNCTL_CACHE_TTL", "30s")
+
+	flags := CacheFlags{}
+	flags.Set(&cobra.Command{}, NewFlagsFactory(nil, nil))
+
+	ttl, err := flags.TTLValue()
+	if err != nil || ttl != 30*time.Second {
+		t.Fatalf("Expected TTL to be taken from env, but was: %s %v", ttl, err)
+	}
+
+	os.Setenv("KNCTL_CACHE_TTL", "30")
+
+	flags = CacheFlags{}
+	flags.Set(&cobra.Command{}, NewFlagsFactory(nil, nil))
+
+	_, err = flags.TTLValue()
+	if err == nil || !strings.Contains(err.Error(), "Expected $KNCTL_CACHE_TTL to be a duration") {
+		t.Fatalf("Expected invalid env TTL error, but was: %v", err)
+	}
+}
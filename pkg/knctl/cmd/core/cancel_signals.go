@@ -17,13 +17,21 @@ limitations under the License.
 package core
 
 import (
+	"context"
 	"os"
 	"os/signal"
 	"syscall"
 )
 
+// CancelWatcher calls stopFunc once operation should be cancelled
+type CancelWatcher interface {
+	Watch(stopFunc func())
+}
+
 type CancelSignals struct{}
 
+var _ CancelWatcher = CancelSignals{}
+
 func (CancelSignals) Watch(stopFunc func()) {
 	signalCh := make(chan os.Signal, 1)
 	signal.Notify(signalCh, syscall.SIGINT, syscall.SIGHUP)
@@ -35,3 +43,17 @@ func (CancelSignals) Watch(stopFunc func()) {
 		}
 	}()
 }
+
+// CancelContext cancels operation once context is done
+type CancelContext struct {
+	Context context.Context
+}
+
+var _ CancelWatcher = CancelContext{}
+
+func (c CancelContext) Watch(stopFunc func()) {
+	go func() {
+		<-c.Context.Done()
+		stopFunc()
+	}()
+}
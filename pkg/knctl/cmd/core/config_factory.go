@@ -18,22 +18,62 @@ package core
 
 import (
 	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"sort"
 	"strings"
 
+	"github.com/cppforlife/knctl/pkg/knctl/logger"
+	"github.com/cppforlife/knctl/pkg/knctl/util"
+	"gopkg.in/yaml.v2"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
 )
 
 type ConfigFactory interface {
 	ConfigurePathResolver(func() (string, error))
 	ConfigureContextResolver(func() (string, error))
+	ConfigureClusterResolver(func() (string, error))
+	ConfigureAuthOverridesResolver(func() (AuthOverrides, error))
+	ConfigureRequestRetriesResolver(func() (int, error))
+	ConfigureLoggerResolver(func() (logger.Logger, error))
+	ConfigureTransportResolver(func() (TransportConfig, error))
+	ConfigureManifestWriterResolver(func() (io.Writer, error))
+	ConfigureCommandResolver(func() (string, error))
 	RESTConfig() (*rest.Config, error)
 	DefaultNamespace() (string, error)
+	InCluster() (bool, error)
+
+	Contexts() ([]string, error)
+	CurrentContext() (string, error)
+	UseContext(string) error
+
+	// ForContext returns config factory that targets given context
+	// while keeping all other overrides (kubeconfig path, auth, etc.)
+	ForContext(string) ConfigFactory
+}
+
+// AuthOverrides take precedence over user information found in kubeconfig.
+// Exec credential plugins and auth provider plugins configured
+// in kubeconfig continue to work when no overrides are given.
+type AuthOverrides struct {
+	Impersonate       string
+	ImpersonateGroups []string
+	Token             string
 }
 
 type ConfigFactoryImpl struct {
-	pathResolverFunc    func() (string, error)
-	contextResolverFunc func() (string, error)
+	pathResolverFunc           func() (string, error)
+	contextResolverFunc        func() (string, error)
+	clusterResolverFunc        func() (string, error)
+	authOverridesResolverFunc  func() (AuthOverrides, error)
+	requestRetriesResolverFunc func() (int, error)
+	loggerResolverFunc         func() (logger.Logger, error)
+	transportResolverFunc      func() (TransportConfig, error)
+	manifestWriterResolverFunc func() (io.Writer, error)
+	commandResolverFunc        func() (string, error)
 }
 
 var _ ConfigFactory = &ConfigFactoryImpl{}
@@ -50,6 +90,36 @@ func (f *ConfigFactoryImpl) ConfigureContextResolver(resolverFunc func() (string
 	f.contextResolverFunc = resolverFunc
 }
 
+func (f *ConfigFactoryImpl) ConfigureClusterResolver(resolverFunc func() (string, error)) {
+	f.clusterResolverFunc = resolverFunc
+}
+
+func (f *ConfigFactoryImpl) ConfigureAuthOverridesResolver(resolverFunc func() (AuthOverrides, error)) {
+	f.authOverridesResolverFunc = resolverFunc
+}
+
+func (f *ConfigFactoryImpl) ConfigureRequestRetriesResolver(resolverFunc func() (int, error)) {
+	f.requestRetriesResolverFunc = resolverFunc
+}
+
+func (f *ConfigFactoryImpl) ConfigureLoggerResolver(resolverFunc func() (logger.Logger, error)) {
+	f.loggerResolverFunc = resolverFunc
+}
+
+func (f *ConfigFactoryImpl) ConfigureTransportResolver(resolverFunc func() (TransportConfig, error)) {
+	f.transportResolverFunc = resolverFunc
+}
+
+func (f *ConfigFactoryImpl) ConfigureManifestWriterResolver(resolverFunc func() (io.Writer, error)) {
+	f.manifestWriterResolverFunc = resolverFunc
+}
+
+// ConfigureCommandResolver enables labeling of created objects
+// with knctl ownership and name of the running command
+func (f *ConfigFactoryImpl) ConfigureCommandResolver(resolverFunc func() (string, error)) {
+	f.commandResolverFunc = resolverFunc
+}
+
 func (f *ConfigFactoryImpl) RESTConfig() (*rest.Config, error) {
 	config, err := f.clientConfig()
 	if err != nil {
@@ -65,6 +135,105 @@ func (f *ConfigFactoryImpl) RESTConfig() (*rest.Config, error) {
 		return nil, fmt.Errorf("Building Kubernetes config: %s%s", err, hintMsg)
 	}
 
+	if f.requestRetriesResolverFunc != nil {
+		retries, err := f.requestRetriesResolverFunc()
+		if err != nil {
+			return nil, fmt.Errorf("Resolving request retries: %s", err)
+		}
+
+		if retries > 0 {
+			prevWrapTransport := restConfig.WrapTransport
+
+			restConfig.WrapTransport = func(rt http.RoundTripper) http.RoundTripper {
+				if prevWrapTransport != nil {
+					rt = prevWrapTransport(rt)
+				}
+				return NewRetryRoundTripper(rt, retries)
+			}
+		}
+	}
+
+	if f.loggerResolverFunc != nil {
+		logger, err := f.loggerResolverFunc()
+		if err != nil {
+			return nil, fmt.Errorf("Resolving logger: %s", err)
+		}
+
+		if logger.Enabled() {
+			prevWrapTransport := restConfig.WrapTransport
+
+			// Wraps retries so that each attempt is logged
+			restConfig.WrapTransport = func(rt http.RoundTripper) http.RoundTripper {
+				rt = NewTracingRoundTripper(rt, logger)
+				if prevWrapTransport != nil {
+					rt = prevWrapTransport(rt)
+				}
+				return rt
+			}
+		}
+	}
+
+	if f.commandResolverFunc != nil {
+		command, err := f.commandResolverFunc()
+		if err != nil {
+			return nil, fmt.Errorf("Resolving command: %s", err)
+		}
+
+		prevWrapTransport := restConfig.WrapTransport
+
+		restConfig.WrapTransport = func(rt http.RoundTripper) http.RoundTripper {
+			if prevWrapTransport != nil {
+				rt = prevWrapTransport(rt)
+			}
+			return NewOwnershipRoundTripper(rt, command)
+		}
+	}
+
+	if f.manifestWriterResolverFunc != nil {
+		writer, err := f.manifestWriterResolverFunc()
+		if err != nil {
+			return nil, fmt.Errorf("Resolving manifest writer: %s", err)
+		}
+
+		if writer != nil {
+			prevWrapTransport := restConfig.WrapTransport
+
+			// Wraps retries so that only final response is printed
+			restConfig.WrapTransport = func(rt http.RoundTripper) http.RoundTripper {
+				if prevWrapTransport != nil {
+					rt = prevWrapTransport(rt)
+				}
+				return NewManifestRoundTripper(rt, writer)
+			}
+		}
+	}
+
+	if f.transportResolverFunc != nil {
+		transportConfig, err := f.transportResolverFunc()
+		if err != nil {
+			return nil, fmt.Errorf("Resolving transport config: %s", err)
+		}
+
+		if transportConfig.HasProxyOverrides() {
+			_, err := transportConfig.ProxyFunc()
+			if err != nil {
+				return nil, fmt.Errorf("Resolving transport config: %s", err)
+			}
+
+			prevWrapTransport := restConfig.WrapTransport
+
+			// Applied last so that proxy is set on underlying transport
+			// before any other wrapping (e.g. tracing, retries) happens
+			restConfig.WrapTransport = func(rt http.RoundTripper) http.RoundTripper {
+				rt = transportConfig.WrapTransport(rt)
+				if prevWrapTransport != nil {
+					rt = prevWrapTransport(rt)
+				}
+				return rt
+			}
+		}
+	}
+
 	return restConfig, nil
 }
 
@@ -78,6 +247,124 @@ func (f *ConfigFactoryImpl) DefaultNamespace() (string, error) {
 	return name, err
 }
 
+// InCluster returns true when in-cluster configuration is used
+// instead of kubeconfig (i.e. knctl runs inside a pod)
+func (f *ConfigFactoryImpl) InCluster() (bool, error) {
+	path, err := f.pathResolverFunc()
+	if err != nil {
+		return false, fmt.Errorf("Resolving config path: %s", err)
+	}
+
+	return len(path) == 0, nil
+}
+
+func (f *ConfigFactoryImpl) Contexts() ([]string, error) {
+	config, err := f.clientConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	rawConfig, err := config.RawConfig()
+	if err != nil {
+		return nil, fmt.Errorf("Loading Kubernetes config: %s", err)
+	}
+
+	var names []string
+	for name, _ := range rawConfig.Contexts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names, nil
+}
+
+func (f *ConfigFactoryImpl) CurrentContext() (string, error) {
+	config, err := f.clientConfig()
+	if err != nil {
+		return "", err
+	}
+
+	rawConfig, err := config.RawConfig()
+	if err != nil {
+		return "", fmt.Errorf("Loading Kubernetes config: %s", err)
+	}
+
+	context, err := f.contextResolverFunc()
+	if err != nil {
+		return "", fmt.Errorf("Resolving config context: %s", err)
+	}
+
+	if len(context) > 0 {
+		return context, nil
+	}
+
+	return rawConfig.CurrentContext, nil
+}
+
+func (f *ConfigFactoryImpl) UseContext(name string) error {
+	path, err := f.pathResolverFunc()
+	if err != nil {
+		return fmt.Errorf("Resolving config path: %s", err)
+	}
+
+	if len(path) == 0 {
+		return fmt.Errorf("Expected kubeconfig path to be set since in-cluster configuration does not have contexts")
+	}
+
+	config, err := clientcmd.LoadFromFile(path)
+	if err != nil {
+		return fmt.Errorf("Loading Kubernetes config: %s", err)
+	}
+
+	if _, found := config.Contexts[name]; !found {
+		return fmt.Errorf("Expected context '%s' to exist in Kubernetes config '%s'", name, path)
+	}
+
+	// Only current-context key is updated to preserve the rest of the file as is
+	contents, err := ioutil.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("Reading Kubernetes config: %s", err)
+	}
+
+	var doc yaml.MapSlice
+
+	err = yaml.Unmarshal(contents, &doc)
+	if err != nil {
+		return fmt.Errorf("Unmarshaling Kubernetes config: %s", err)
+	}
+
+	var found bool
+
+	for i, item := range doc {
+		if item.Key == "current-context" {
+			doc[i].Value = name
+			found = true
+		}
+	}
+
+	if !found {
+		doc = append(doc, yaml.MapItem{Key: "current-context", Value: name})
+	}
+
+	contents, err = yaml.Marshal(doc)
+	if err != nil {
+		return fmt.Errorf("Marshaling Kubernetes config: %s", err)
+	}
+
+	err = util.WriteFileAtomically(path, contents, 0600)
+	if err != nil {
+		return fmt.Errorf("Writing Kubernetes config: %s", err)
+	}
+
+	return nil
+}
+
+func (f *ConfigFactoryImpl) ForContext(name string) ConfigFactory {
+	copied := *f
+	copied.contextResolverFunc = func() (string, error) { return name, nil }
+	return &copied
+}
+
 func (f *ConfigFactoryImpl) clientConfig() (clientcmd.ClientConfig, error) {
 	path, err := f.pathResolverFunc()
 	if err != nil {
@@ -89,8 +376,38 @@ func (f *ConfigFactoryImpl) clientConfig() (clientcmd.ClientConfig, error) {
 		return nil, fmt.Errorf("Resolving config context: %s", err)
 	}
 
-	return clientcmd.NewNonInteractiveDeferredLoadingClientConfig(
-		&clientcmd.ClientConfigLoadingRules{ExplicitPath: path},
-		&clientcmd.ConfigOverrides{CurrentContext: context},
-	), nil
+	overrides := &clientcmd.ConfigOverrides{CurrentContext: context}
+
+	if f.clusterResolverFunc != nil {
+		cluster, err := f.clusterResolverFunc()
+		if err != nil {
+			return nil, fmt.Errorf("Resolving config cluster: %s", err)
+		}
+
+		overrides.Context.Cluster = cluster
+	}
+
+	if f.authOverridesResolverFunc != nil {
+		authOverrides, err := f.authOverridesResolverFunc()
+		if err != nil {
+			return nil, fmt.Errorf("Resolving auth overrides: %s", err)
+		}
+
+		overrides.AuthInfo = clientcmdapi.AuthInfo{
+			Impersonate:       authOverrides.Impersonate,
+			ImpersonateGroups: authOverrides.ImpersonateGroups,
+			Token:             authOverrides.Token,
+		}
+	}
+
+	// Only given file is read (no merging with other kubeconfig files
+	// and no lock files), hence many knctl processes may share same
+	// kubeconfig concurrently. Without a path deferred loading
+	// falls back to in-cluster configuration.
+	loadingRules := &clientcmd.ClientConfigLoadingRules{ExplicitPath: path}
+	if len(path) == 0 {
+		loadingRules = &clientcmd.ClientConfigLoadingRules{}
+	}
+
+	return clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, overrides), nil
 }
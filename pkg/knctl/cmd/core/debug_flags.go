@@ -0,0 +1,41 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package core
+
+import (
+	"os"
+
+	"github.com/cppforlife/knctl/pkg/knctl/logger"
+	"github.com/spf13/cobra"
+)
+
+type DebugFlags struct {
+	Debug bool
+}
+
+func (f *DebugFlags) Set(cmd *cobra.Command, flagsFactory FlagsFactory) {
+	cmd.PersistentFlags().BoolVar(&f.Debug, "debug", os.Getenv("KNCTL_DEBUG") == "true",
+		"Log API requests and internal decisions to stderr ($KNCTL_DEBUG)")
+}
+
+// Logger returns logger that writes to stderr when debugging is enabled
+func (f *DebugFlags) Logger() (logger.Logger, error) {
+	if f.Debug {
+		return logger.NewLogger(os.Stderr), nil
+	}
+	return logger.NewNoopLogger(), nil
+}
@@ -0,0 +1,52 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package core
+
+import (
+	"strconv"
+)
+
+// DefaultlessIntValue is an int flag value that stays nil
+// unless flag was explicitly specified
+type DefaultlessIntValue struct {
+	val **int
+}
+
+func NewDefaultlessIntValue(p **int) *DefaultlessIntValue {
+	return &DefaultlessIntValue{p}
+}
+
+func (i *DefaultlessIntValue) Set(s string) error {
+	v, err := strconv.ParseInt(s, 0, 64)
+	if err != nil {
+		return err
+	}
+	val := int(v)
+	(*i.val) = &val
+	return nil
+}
+
+func (i *DefaultlessIntValue) Type() string {
+	return "int"
+}
+
+func (i *DefaultlessIntValue) String() string {
+	if i.val == nil || *i.val == nil {
+		return "unspecified"
+	}
+	return strconv.Itoa(int(**i.val))
+}
@@ -18,16 +18,44 @@ package core
 
 import (
 	"fmt"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
 
+	"github.com/cppforlife/knctl/pkg/knctl/cache"
+	"github.com/cppforlife/knctl/pkg/knctl/logger"
+	"github.com/cppforlife/knctl/pkg/knctl/waiter"
 	buildclientset "github.com/knative/build/pkg/client/clientset/versioned"
 	servingclientset "github.com/knative/serving/pkg/client/clientset/versioned"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
 )
 
 type DepsFactory interface {
 	ServingClient() (servingclientset.Interface, error)
 	BuildClient() (buildclientset.Interface, error)
 	CoreClient() (kubernetes.Interface, error)
+	DynamicClient() (dynamic.Interface, error)
+	DryRunDynamicClient() (dynamic.Interface, error)
+	DiscoveryClient() (discovery.DiscoveryInterface, error)
+
+	Waiter() (*waiter.Waiter, error)
+
+	InCluster() (bool, error)
+
+	ConfigureCacheTTLResolver(func() (time.Duration, error))
+	Cache() (cache.Cache, error)
+
+	ConfigureLoggerResolver(func() (logger.Logger, error))
+	Logger() (logger.Logger, error)
+
+	ConfigureTransportResolver(func() (TransportConfig, error))
+	TransportConfig() (TransportConfig, error)
+	HTTPClient() (*http.Client, error)
 }
 
 func NewDepsFactory() DepsFactory { // Concise for testing
@@ -36,12 +64,149 @@ func NewDepsFactory() DepsFactory { // Concise for testing
 
 type DepsFactoryImpl struct {
 	configFactory ConfigFactory
+
+	cacheTTLResolverFunc  func() (time.Duration, error)
+	loggerResolverFunc    func() (logger.Logger, error)
+	transportResolverFunc func() (TransportConfig, error)
+
+	waiterLock sync.Mutex
+	waiter     *waiter.Waiter
 }
 
 var _ DepsFactory = &DepsFactoryImpl{}
 
 func NewDepsFactoryImpl(configFactory ConfigFactory) *DepsFactoryImpl {
-	return &DepsFactoryImpl{configFactory}
+	return &DepsFactoryImpl{configFactory: configFactory}
+}
+
+// InCluster returns true when knctl runs inside a pod, hence cluster
+// services (e.g. ingress gateway) are reachable via cluster DNS
+func (f *DepsFactoryImpl) InCluster() (bool, error) {
+	return f.configFactory.InCluster()
+}
+
+func (f *DepsFactoryImpl) ConfigureCacheTTLResolver(resolverFunc func() (time.Duration, error)) {
+	f.cacheTTLResolverFunc = resolverFunc
+}
+
+func (f *DepsFactoryImpl) ConfigureLoggerResolver(resolverFunc func() (logger.Logger, error)) {
+	f.loggerResolverFunc = resolverFunc
+}
+
+// Logger returns logger for internal decisions (e.g. chosen ingress).
+// Returned logger discards messages unless debugging is enabled.
+func (f *DepsFactoryImpl) Logger() (logger.Logger, error) {
+	if f.loggerResolverFunc == nil {
+		return logger.NewNoopLogger(), nil
+	}
+	return f.loggerResolverFunc()
+}
+
+func (f *DepsFactoryImpl) ConfigureTransportResolver(resolverFunc func() (TransportConfig, error)) {
+	f.transportResolverFunc = resolverFunc
+}
+
+// TransportConfig returns proxy and CA configuration for non-Kubernetes
+// HTTP requests (proxy environment variables are used by default)
+func (f *DepsFactoryImpl) TransportConfig() (TransportConfig, error) {
+	if f.transportResolverFunc == nil {
+		return TransportConfig{}, nil
+	}
+	return f.transportResolverFunc()
+}
+
+// HTTPClient returns client for non-Kubernetes HTTP requests
+// (e.g. requests to ingress addresses or downloads)
+func (f *DepsFactoryImpl) HTTPClient() (*http.Client, error) {
+	config, err := f.TransportConfig()
+	if err != nil {
+		return nil, err
+	}
+	return config.HTTPClient()
+}
+
+// Cache returns on-disk cache specific to targeted API server.
+// Returned cache is disabled unless cache TTL is configured.
+func (f *DepsFactoryImpl) Cache() (cache.Cache, error) {
+	if f.cacheTTLResolverFunc == nil {
+		return cache.Cache{}, nil
+	}
+
+	ttl, err := f.cacheTTLResolverFunc()
+	if err != nil || ttl <= 0 {
+		return cache.Cache{}, err
+	}
+
+	config, err := f.configFactory.RESTConfig()
+	if err != nil {
+		return cache.Cache{}, err
+	}
+
+	rootDir, err := cache.DefaultRootDir()
+	if err != nil {
+		return cache.Cache{}, err
+	}
+
+	return cache.NewCache(cache.ClusterDir(rootDir, config.Host, f.cacheIdentity(config)), ttl), nil
+}
+
+// cacheIdentity describes user on whose behalf API requests are made
+func (f *DepsFactoryImpl) cacheIdentity(config *rest.Config) string {
+	pieces := []string{
+		config.Username,
+		config.BearerToken,
+		config.CertFile,
+		string(config.CertData),
+		config.Impersonate.UserName,
+		strings.Join(config.Impersonate.Groups, ","),
+	}
+
+	if config.AuthProvider != nil {
+		pieces = append(pieces, config.AuthProvider.Name)
+		for _, key := range []string{"id-token", "access-token", "client-id"} {
+			pieces = append(pieces, config.AuthProvider.Config[key])
+		}
+	}
+
+	if config.ExecProvider != nil {
+		pieces = append(pieces, config.ExecProvider.Command)
+		pieces = append(pieces, config.ExecProvider.Args...)
+		for _, env := range config.ExecProvider.Env {
+			pieces = append(pieces, env.Name+"="+env.Value)
+		}
+	}
+
+	return strings.Join(pieces, "\n")
+}
+
+// DiscoveryClient returns discovery client that caches
+// API groups and resources on disk when cache is enabled
+func (f *DepsFactoryImpl) DiscoveryClient() (discovery.DiscoveryInterface, error) {
+	config, err := f.configFactory.RESTConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	c, err := f.Cache()
+	if err != nil {
+		return nil, err
+	}
+
+	if !c.Enabled() {
+		client, err := discovery.NewDiscoveryClientForConfig(config)
+		if err != nil {
+			return nil, fmt.Errorf("Building Discovery client: %s", err)
+		}
+		return client, nil
+	}
+
+	client, err := discovery.NewCachedDiscoveryClientForConfig(
+		rest.CopyConfig(config), filepath.Join(c.Dir(), "discovery"), "", c.TTL())
+	if err != nil {
+		return nil, fmt.Errorf("Building Discovery client: %s", err)
+	}
+
+	return client, nil
 }
 
 func (f *DepsFactoryImpl) ServingClient() (servingclientset.Interface, error) {
@@ -72,12 +237,19 @@ func (f *DepsFactoryImpl) BuildClient() (buildclientset.Interface, error) {
 	return clientset, nil
 }
 
+// CoreClient uses protobuf for core and apps resources (e.g. listing thousands
+// of pods backing revisions) since it's noticeably smaller and faster to decode
+// than JSON and Kubernetes clientset knows how to decode it (unlike Knative ones)
 func (f *DepsFactoryImpl) CoreClient() (kubernetes.Interface, error) {
 	config, err := f.configFactory.RESTConfig()
 	if err != nil {
 		return nil, err
 	}
 
+	config = rest.CopyConfig(config)
+	config.AcceptContentTypes = protobufAcceptContentTypes
+	config.ContentType = ProtobufContentType
+
 	clientset, err := kubernetes.NewForConfig(config)
 	if err != nil {
 		return nil, fmt.Errorf("Building Core clientset: %s", err)
@@ -85,3 +257,106 @@ func (f *DepsFactoryImpl) CoreClient() (kubernetes.Interface, error) {
 
 	return clientset, nil
 }
+
+// Waiter returns waiter shared by all operations so that
+// concurrent waits for the same object share an informer
+func (f *DepsFactoryImpl) Waiter() (*waiter.Waiter, error) {
+	f.waiterLock.Lock()
+	defer f.waiterLock.Unlock()
+
+	if f.waiter != nil {
+		return f.waiter, nil
+	}
+
+	coreClient, err := f.CoreClient()
+	if err != nil {
+		return nil, err
+	}
+
+	servingClient, err := f.ServingClient()
+	if err != nil {
+		return nil, err
+	}
+
+	buildClient, err := f.BuildClient()
+	if err != nil {
+		return nil, err
+	}
+
+	f.waiter = waiter.NewWaiter(coreClient, servingClient, buildClient)
+
+	return f.waiter, nil
+}
+
+func (f *DepsFactoryImpl) DynamicClient() (dynamic.Interface, error) {
+	config, err := f.configFactory.RESTConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := dynamic.NewForConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("Building Dynamic client: %s", err)
+	}
+
+	return client, nil
+}
+
+// DryRunDynamicClient returns dynamic client that asks API server
+// to validate and default mutating requests without persisting them.
+// Error is returned for API servers that would ignore dry run and persist changes.
+func (f *DepsFactoryImpl) DryRunDynamicClient() (dynamic.Interface, error) {
+	config, err := f.configFactory.RESTConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	discoveryClient, err := discovery.NewDiscoveryClientForConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("Building Discovery client: %s", err)
+	}
+
+	supported, err := DryRunSupported(discoveryClient)
+	if err != nil {
+		return nil, fmt.Errorf("Checking server version for dry run support: %s", err)
+	}
+	if !supported {
+		return nil, fmt.Errorf("Expected Kubernetes API server version to be >=1.%d to support dry run", dryRunMinMinorVersion)
+	}
+
+	config = rest.CopyConfig(config)
+	prevWrapTransport := config.WrapTransport
+
+	config.WrapTransport = func(rt http.RoundTripper) http.RoundTripper {
+		if prevWrapTransport != nil {
+			rt = prevWrapTransport(rt)
+		}
+		return dryRunRoundTripper{rt}
+	}
+
+	client, err := dynamic.NewForConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("Building Dynamic client: %s", err)
+	}
+
+	return client, nil
+}
+
+type dryRunRoundTripper struct {
+	rt http.RoundTripper
+}
+
+func (t dryRunRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method == http.MethodGet {
+		return t.rt.RoundTrip(req)
+	}
+
+	req = req.WithContext(req.Context())
+	url := *req.URL
+	query := url.Query()
+	query.Set("dryRun", "All")
+	url.RawQuery = query.Encode()
+	req.URL = &url
+
+	return t.rt.RoundTrip(req)
+}
@@ -0,0 +1,90 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package core_test
+
+import (
+	"fmt"
+	"testing"
+
+	. "github.com/cppforlife/knctl/pkg/knctl/cmd/core"
+	"github.com/cppforlife/knctl/pkg/knctl/testkit"
+)
+
+func TestDepsFactoryImpl_SharesWaiter(t *testing.T) {
+	cluster := testkit.NewCluster(t)
+	depsFactory := NewDepsFactoryImpl(cluster.ConfigFactory())
+
+	waiter1, err := depsFactory.Waiter()
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	waiter2, err := depsFactory.Waiter()
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	if waiter1 != waiter2 {
+		t.Fatalf("Expected waiter to be built once")
+	}
+}
+
+func TestDepsFactoryImpl_DoesNotShareWaiterAfterError(t *testing.T) {
+	cluster := testkit.NewCluster(t)
+	path := cluster.KubeconfigPath()
+
+	var resolved int
+
+	configFactory := NewConfigFactoryImpl()
+	configFactory.ConfigureContextResolver(func() (string, error) { return "", nil })
+	configFactory.ConfigurePathResolver(func() (string, error) {
+		resolved++
+		if resolved == 1 {
+			return "", fmt.Errorf("fake-err")
+		}
+		return path, nil
+	})
+
+	depsFactory := NewDepsFactoryImpl(configFactory)
+
+	_, err := depsFactory.Waiter()
+	if err == nil {
+		t.Fatalf("Expected first attempt to fail")
+	}
+
+	_, err = depsFactory.Waiter()
+	if err != nil {
+		t.Fatalf("Expected second attempt to succeed: %s", err)
+	}
+}
+
+func TestDepsFactoryImpl_DryRunDynamicClientRequiresDryRunSupport(t *testing.T) {
+	cluster := testkit.NewCluster(t)
+	cluster.SetMinorVersion("12")
+
+	_, err := NewDepsFactoryImpl(cluster.ConfigFactory()).DryRunDynamicClient()
+	if err == nil || err.Error() != "Expected Kubernetes API server version to be >=1.13 to support dry run" {
+		t.Fatalf("Expected dry run to be refused, but was: %v", err)
+	}
+
+	cluster.SetMinorVersion("13+")
+
+	_, err = NewDepsFactoryImpl(cluster.ConfigFactory()).DryRunDynamicClient()
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+}
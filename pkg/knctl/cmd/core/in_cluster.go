@@ -0,0 +1,36 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package core
+
+import (
+	"os"
+)
+
+const (
+	inClusterTokenPath = "/var/run/secrets/kubernetes.io/serviceaccount/token"
+)
+
+// InClusterConfigPossible returns true when knctl runs inside a pod
+// with a mounted service account token (e.g. as part of a Job or a controller)
+func InClusterConfigPossible() bool {
+	if len(os.Getenv("KUBERNETES_SERVICE_HOST")) == 0 || len(os.Getenv("KUBERNETES_SERVICE_PORT")) == 0 {
+		return false
+	}
+
+	fileInfo, err := os.Stat(inClusterTokenPath)
+	return err == nil && !fileInfo.IsDir()
+}
@@ -17,6 +17,7 @@ limitations under the License.
 package core
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
 
@@ -28,14 +29,59 @@ import (
 type KubeconfigFlags struct {
 	Path    *KubeconfigPathFlag
 	Context *KubeconfigContextFlag
+	Cluster string
+
+	Impersonate       string
+	ImpersonateGroups []string
+	Token             *KubeconfigTokenFlag
+
+	RequestRetries int
 }
 
 func (f *KubeconfigFlags) Set(cmd *cobra.Command, flagsFactory FlagsFactory) {
 	f.Path = NewKubeconfigPathFlag()
-	cmd.PersistentFlags().Var(f.Path, "kubeconfig", "Path to the kubeconfig file ($KNCTL_KUBECONFIG or $KUBECONFIG)")
+	cmd.PersistentFlags().Var(f.Path, "kubeconfig", "Path to the kubeconfig file ($KNCTL_KUBECONFIG or $KUBECONFIG; in-cluster configuration is used inside a pod without one)")
 
 	f.Context = NewKubeconfigContextFlag()
 	cmd.PersistentFlags().Var(f.Context, "kubeconfig-context", "Kubeconfig context override ($KNCTL_KUBECONFIG_CONTEXT)")
+	cmd.PersistentFlags().Var(f.Context, "context", "Kubeconfig context override (same as --kubeconfig-context)")
+
+	cmd.PersistentFlags().StringVar(&f.Cluster, "cluster", "", "Kubeconfig cluster override")
+
+	cmd.PersistentFlags().StringVar(&f.Impersonate, "as", "", "Username to impersonate for the operation")
+	cmd.PersistentFlags().StringArrayVar(&f.ImpersonateGroups, "as-group", nil, "Group to impersonate for the operation (can be specified multiple times; requires --as)")
+
+	f.Token = NewKubeconfigTokenFlag()
+	cmd.PersistentFlags().Var(f.Token, "token", "Bearer token for authentication to the API server ($KNCTL_TOKEN)")
+
+	cmd.PersistentFlags().IntVar(&f.RequestRetries, "request-retries", 3,
+		"Number of times to retry API requests that failed due to throttling, server errors or dropped connections")
+}
+
+func (f *KubeconfigFlags) ClusterValue() (string, error) {
+	return f.Cluster, nil
+}
+
+func (f *KubeconfigFlags) RequestRetriesValue() (int, error) {
+	return f.RequestRetries, nil
+}
+
+func (f *KubeconfigFlags) AuthOverrides() (AuthOverrides, error) {
+	// API server rejects impersonated groups without impersonated user
+	if len(f.ImpersonateGroups) > 0 && len(f.Impersonate) == 0 {
+		return AuthOverrides{}, fmt.Errorf("Expected --as to be specified when --as-group is specified")
+	}
+
+	token, err := f.Token.Value()
+	if err != nil {
+		return AuthOverrides{}, err
+	}
+
+	return AuthOverrides{
+		Impersonate:       f.Impersonate,
+		ImpersonateGroups: f.ImpersonateGroups,
+		Token:             token,
+	}, nil
 }
 
 type KubeconfigPathFlag struct {
@@ -87,7 +133,14 @@ func (s *KubeconfigPathFlag) resolveValue() string {
 		return path
 	}
 
-	return filepath.Join(s.homeDir(), ".kube", "config")
+	path = filepath.Join(s.homeDir(), ".kube", "config")
+
+	// Empty path makes config factory use in-cluster configuration
+	if _, err := os.Stat(path); os.IsNotExist(err) && InClusterConfigPossible() {
+		return ""
+	}
+
+	return path
 }
 
 func (*KubeconfigPathFlag) homeDir() string {
@@ -134,3 +187,41 @@ func (s *KubeconfigContextFlag) Resolve() error {
 
 	return nil
 }
+
+type KubeconfigTokenFlag struct {
+	value string
+}
+
+var _ pflag.Value = &KubeconfigTokenFlag{}
+var _ cobrautil.ResolvableFlag = &KubeconfigTokenFlag{}
+
+func NewKubeconfigTokenFlag() *KubeconfigTokenFlag {
+	return &KubeconfigTokenFlag{}
+}
+
+func (s *KubeconfigTokenFlag) Set(val string) error {
+	s.value = val
+	return nil
+}
+
+func (s *KubeconfigTokenFlag) Type() string   { return "string" }
+func (s *KubeconfigTokenFlag) String() string { return "" } // default for usage (never show token)
+
+func (s *KubeconfigTokenFlag) Value() (string, error) {
+	err := s.Resolve()
+	if err != nil {
+		return "", err
+	}
+
+	return s.value, nil
+}
+
+func (s *KubeconfigTokenFlag) Resolve() error {
+	if len(s.value) > 0 {
+		return nil
+	}
+
+	s.value = os.Getenv("KNCTL_TOKEN")
+
+	return nil
+}
@@ -0,0 +1,70 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package core_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	. "github.com/cppforlife/knctl/pkg/knctl/cmd/core"
+	"github.com/spf13/cobra"
+)
+
+func TestKubeconfigFlags_AuthOverrides(t *testing.T) {
+	cmd := &cobra.Command{}
+
+	flags := KubeconfigFlags{}
+	flags.Set(cmd, NewFlagsFactory(nil, nil))
+
+	err := cmd.PersistentFlags().Parse([]string{"--as", "user1", "--as-group", "group1", "--as-group", "group2", "--token", "token1"})
+	if err != nil {
+		t.Fatalf("Expected flags to parse: %s", err)
+	}
+
+	overrides, err := flags.AuthOverrides()
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	expectedOverrides := AuthOverrides{
+		Impersonate:       "user1",
+		ImpersonateGroups: []string{"group1", "group2"},
+		Token:             "token1",
+	}
+
+	if !reflect.DeepEqual(overrides, expectedOverrides) {
+		t.Fatalf("Expected overrides to match, but was: %#v", overrides)
+	}
+}
+
+func TestKubeconfigFlags_AuthOverridesGroupsWithoutUser(t *testing.T) {
+	cmd := &cobra.Command{}
+
+	flags := KubeconfigFlags{}
+	flags.Set(cmd, NewFlagsFactory(nil, nil))
+
+	err := cmd.PersistentFlags().Parse([]string{"--as-group", "group1"})
+	if err != nil {
+		t.Fatalf("Expected flags to parse: %s", err)
+	}
+
+	_, err = flags.AuthOverrides()
+	if err == nil || !strings.Contains(err.Error(), "Expected --as to be specified when --as-group is specified") {
+		t.Fatalf("Expected groups without user error, but was: %v", err)
+	}
+}
@@ -0,0 +1,42 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package core
+
+import (
+	"io"
+
+	"github.com/spf13/cobra"
+)
+
+type ManifestFlags struct {
+	Show bool
+}
+
+func (f *ManifestFlags) Set(cmd *cobra.Command, flagsFactory FlagsFactory) {
+	cmd.PersistentFlags().BoolVar(&f.Show, "show-manifest", false,
+		"Print objects created or updated by command (status, server populated metadata and secret values are removed)")
+}
+
+// WriterResolver returns resolver that provides given writer only when manifests should be shown
+func (f *ManifestFlags) WriterResolver(writer io.Writer) func() (io.Writer, error) {
+	return func() (io.Writer, error) {
+		if f.Show {
+			return writer, nil
+		}
+		return nil, nil
+	}
+}
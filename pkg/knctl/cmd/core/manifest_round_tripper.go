@@ -0,0 +1,134 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package core
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/cppforlife/knctl/pkg/knctl/bundle"
+	"github.com/ghodss/yaml"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+const (
+	redactedManifestValue = "<redacted>"
+)
+
+// ManifestRoundTripper prints objects returned by successful mutating API requests
+// so that users could see (and reproduce) what each command changed
+type ManifestRoundTripper struct {
+	rt     http.RoundTripper
+	writer io.Writer
+}
+
+var _ http.RoundTripper = ManifestRoundTripper{}
+
+func NewManifestRoundTripper(rt http.RoundTripper, writer io.Writer) ManifestRoundTripper {
+	return ManifestRoundTripper{rt: rt, writer: writer}
+}
+
+func (t ManifestRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.rt.RoundTrip(req)
+	if err != nil || !t.isMutation(req, resp) {
+		return resp, err
+	}
+
+	body, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+	// Core clientset receives protobuf
+	if IsProtobuf(resp.Header.Get("Content-Type")) {
+		body, err = ProtobufToJSON(body)
+		if err != nil {
+			return resp, nil
+		}
+	}
+
+	manifest, err := SanitizedManifest(body)
+	if err == nil && len(manifest) > 0 {
+		t.writer.Write(append([]byte("---\n"), manifest...))
+	}
+
+	return resp, nil
+}
+
+func (t ManifestRoundTripper) isMutation(req *http.Request, resp *http.Response) bool {
+	switch req.Method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch:
+	default:
+		return false
+	}
+
+	// Dry run requests (e.g. preflight validation) do not change anything
+	if len(req.URL.Query().Get("dryRun")) > 0 {
+		return false
+	}
+
+	// Upgraded connections (exec, port forwarding) do not carry objects
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		return false
+	}
+
+	contentType := resp.Header.Get("Content-Type")
+
+	return strings.HasPrefix(contentType, "application/json") || IsProtobuf(contentType)
+}
+
+// SanitizedManifest converts API object to YAML without status,
+// server populated metadata and secret values
+func SanitizedManifest(body []byte) ([]byte, error) {
+	var obj unstructured.Unstructured
+
+	err := json.Unmarshal(body, &obj.Object)
+	if err != nil {
+		return nil, err
+	}
+
+	// Reviews (e.g. access checks) are not persisted
+	if len(obj.GetKind()) == 0 || obj.GetKind() == "Status" || strings.HasSuffix(obj.GetKind(), "Review") {
+		return nil, nil
+	}
+
+	namespace := obj.GetNamespace()
+	obj = bundle.Clean(obj)
+	obj.SetNamespace(namespace)
+
+	if obj.GetKind() == "Secret" {
+		for _, field := range []string{"data", "stringData"} {
+			vals, found, err := unstructured.NestedMap(obj.Object, field)
+			if err != nil || !found {
+				continue
+			}
+			for key := range vals {
+				vals[key] = redactedManifestValue
+			}
+			unstructured.SetNestedMap(obj.Object, vals, field)
+		}
+	}
+
+	return yaml.Marshal(obj.Object)
+}
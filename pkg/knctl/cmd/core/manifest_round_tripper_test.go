@@ -0,0 +1,87 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package core_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	. "github.com/cppforlife/knctl/pkg/knctl/cmd/core"
+)
+
+func TestManifestRoundTripper(t *testing.T) {
+	type example struct {
+		Method   string
+		Query    string
+		Status   int
+		Body     string
+		Expected string
+	}
+
+	svcBody := `{"apiVersion":"serving.knative.dev/v1alpha1","kind":"Service",` +
+		`"metadata":{"name":"svc1","namespace":"ns1","uid":"uid1","resourceVersion":"1"},"spec":{},"status":{"ready":true}}`
+	svcManifest := "---\napiVersion: serving.knative.dev/v1alpha1\nkind: Service\nmetadata:\n  name: svc1\n  namespace: ns1\nspec: {}\n"
+
+	secretBody := `{"apiVersion":"v1","kind":"Secret","metadata":{"name":"s1","namespace":"ns1"},"data":{"key":"dmFs"}}`
+	secretManifest := "---\napiVersion: v1\ndata:\n  key: <redacted>\nkind: Secret\nmetadata:\n  name: s1\n  namespace: ns1\n"
+
+	exs := []example{
+		{Method: "POST", Status: 201, Body: svcBody, Expected: svcManifest},
+		{Method: "PUT", Status: 200, Body: svcBody, Expected: svcManifest},
+		{Method: "PATCH", Status: 200, Body: secretBody, Expected: secretManifest},
+		{Method: "GET", Status: 200, Body: svcBody},
+		{Method: "POST", Query: "dryRun=All", Status: 201, Body: svcBody},
+		{Method: "POST", Status: 422, Body: `{"kind":"Status"}`},
+		{Method: "POST", Status: 201, Body: `{"apiVersion":"authorization.k8s.io/v1","kind":"SelfSubjectAccessReview"}`},
+	}
+
+	for _, ex := range exs {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(ex.Status)
+			w.Write([]byte(ex.Body))
+		}))
+
+		var output bytes.Buffer
+		rt := NewManifestRoundTripper(http.DefaultTransport, &output)
+
+		req, err := http.NewRequest(ex.Method, server.URL+"?"+ex.Query, strings.NewReader("body"))
+		if err != nil {
+			t.Fatalf("Building request: %s", err)
+		}
+
+		resp, err := rt.RoundTrip(req)
+		if err != nil {
+			t.Fatalf("Expected request to succeed: %s", err)
+		}
+
+		body, err := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		server.Close()
+
+		if err != nil || string(body) != ex.Body {
+			t.Fatalf("Expected response body to be preserved: %s", body)
+		}
+		if output.String() != ex.Expected {
+			t.Fatalf("Expected %s %s to print manifest '%s', but was '%s'", ex.Method, ex.Query, ex.Expected, output.String())
+		}
+	}
+}
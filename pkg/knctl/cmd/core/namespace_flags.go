@@ -51,6 +51,9 @@ func (s *NamespaceNameFlag) Set(val string) error {
 	return nil
 }
 
+// Value returns namespace name once it's resolved
+func (s *NamespaceNameFlag) Value() string { return *s.value }
+
 func (s *NamespaceNameFlag) Type() string   { return "string" }
 func (s *NamespaceNameFlag) String() string { return "" } // default for usage
 
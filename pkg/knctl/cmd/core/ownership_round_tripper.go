@@ -0,0 +1,154 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package core
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	ctlkube "github.com/cppforlife/knctl/pkg/knctl/kube"
+)
+
+const (
+	CommandLabelKey = "cli.knative.dev/command"
+)
+
+// OwnershipRoundTripper labels every object created via API
+// with knctl ownership and name of the command that created it
+type OwnershipRoundTripper struct {
+	rt      http.RoundTripper
+	command string
+}
+
+var _ http.RoundTripper = OwnershipRoundTripper{}
+
+func NewOwnershipRoundTripper(rt http.RoundTripper, command string) OwnershipRoundTripper {
+	return OwnershipRoundTripper{rt: rt, command: command}
+}
+
+func (t OwnershipRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method != http.MethodPost || req.Body == nil || req.Body == http.NoBody {
+		return t.rt.RoundTrip(req)
+	}
+
+	body, err := ioutil.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	labeledBody, ok := t.labeledObject(body, req.Header.Get("Content-Type"))
+	if !ok {
+		labeledBody = body
+	}
+
+	// Round trippers should not modify original request
+	req = req.WithContext(req.Context())
+
+	// Core clientset sends protobuf; labeled object is sent as JSON
+	// instead since API server accepts both for all resources
+	if ok && IsProtobuf(req.Header.Get("Content-Type")) {
+		req.Header = cloneHeader(req.Header)
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Body = ioutil.NopCloser(bytes.NewReader(labeledBody))
+	req.ContentLength = int64(len(labeledBody))
+	req.GetBody = func() (io.ReadCloser, error) {
+		return ioutil.NopCloser(bytes.NewReader(labeledBody)), nil
+	}
+
+	return t.rt.RoundTrip(req)
+}
+
+func (t OwnershipRoundTripper) labeledObject(body []byte, contentType string) ([]byte, bool) {
+	if IsProtobuf(contentType) {
+		var err error
+		body, err = ProtobufToJSON(body)
+		if err != nil {
+			return nil, false
+		}
+	}
+
+	var obj map[string]interface{}
+
+	err := json.Unmarshal(body, &obj)
+	if err != nil {
+		return nil, false
+	}
+
+	// Reviews (e.g. access checks) are not persisted
+	kind, _ := obj["kind"].(string)
+	if strings.HasSuffix(kind, "Review") {
+		return nil, false
+	}
+
+	meta, ok := obj["metadata"].(map[string]interface{})
+	if !ok {
+		if len(kind) == 0 {
+			return nil, false
+		}
+		meta = map[string]interface{}{}
+	}
+
+	labels, _ := meta["labels"].(map[string]interface{})
+	if labels == nil {
+		labels = map[string]interface{}{}
+	}
+
+	labels[ctlkube.ManagedByLabelKey] = ctlkube.ManagedByLabelValue
+	if len(t.command) > 0 {
+		labels[CommandLabelKey] = t.command
+	}
+
+	meta["labels"] = labels
+	obj["metadata"] = meta
+
+	labeledBody, err := json.Marshal(obj)
+	if err != nil {
+		return nil, false
+	}
+
+	return labeledBody, true
+}
+
+// CommandLabelValue converts command path (e.g. 'knctl route create')
+// into a value that could be used as a label value (e.g. 'route-create')
+func CommandLabelValue(commandPath string) string {
+	pieces := strings.Fields(commandPath)
+	if len(pieces) > 1 {
+		pieces = pieces[1:]
+	}
+
+	val := strings.Join(pieces, "-")
+	if len(val) > 63 {
+		val = val[:63]
+	}
+
+	return strings.Trim(val, "-")
+}
+
+func cloneHeader(header http.Header) http.Header {
+	result := http.Header{}
+	for k, vs := range header {
+		result[k] = append([]string{}, vs...)
+	}
+	return result
+}
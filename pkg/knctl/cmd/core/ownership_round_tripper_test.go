@@ -0,0 +1,109 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package core_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	. "github.com/cppforlife/knctl/pkg/knctl/cmd/core"
+)
+
+func TestOwnershipRoundTripper(t *testing.T) {
+	type example struct {
+		Method         string
+		Body           string
+		ExpectedLabels map[string]interface{}
+	}
+
+	managedLabels := map[string]interface{}{
+		"cli.knative.dev/managed-by": "knctl",
+		"cli.knative.dev/command":    "route-create",
+	}
+
+	exs := []example{
+		{Method: "POST", Body: `{"kind":"Route","metadata":{"name":"r1"}}`, ExpectedLabels: managedLabels},
+		{Method: "POST", Body: `{"kind":"Route","metadata":{"name":"r1","labels":{"k1":"v1"}}}`,
+			ExpectedLabels: map[string]interface{}{"cli.knative.dev/managed-by": "knctl", "cli.knative.dev/command": "route-create", "k1": "v1"}},
+		{Method: "POST", Body: `{"kind":"Route"}`, ExpectedLabels: managedLabels},
+		{Method: "POST", Body: `{"kind":"SelfSubjectAccessReview","metadata":{}}`},
+		{Method: "POST", Body: `not-json`},
+		{Method: "PUT", Body: `{"kind":"Route","metadata":{"name":"r1"}}`},
+	}
+
+	for _, ex := range exs {
+		var receivedBody []byte
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			receivedBody, _ = ioutil.ReadAll(r.Body)
+		}))
+
+		rt := NewOwnershipRoundTripper(http.DefaultTransport, "route-create")
+
+		req, err := http.NewRequest(ex.Method, server.URL, strings.NewReader(ex.Body))
+		if err != nil {
+			t.Fatalf("Building request: %s", err)
+		}
+
+		resp, err := rt.RoundTrip(req)
+		server.Close()
+
+		if err != nil {
+			t.Fatalf("Expected request to succeed: %s", err)
+		}
+		resp.Body.Close()
+
+		if ex.ExpectedLabels == nil {
+			if string(receivedBody) != ex.Body {
+				t.Fatalf("Expected body to be unchanged, but was: %s", receivedBody)
+			}
+			continue
+		}
+
+		var obj map[string]interface{}
+
+		err = json.Unmarshal(receivedBody, &obj)
+		if err != nil {
+			t.Fatalf("Unmarshaling body: %s", err)
+		}
+
+		labels := obj["metadata"].(map[string]interface{})["labels"]
+		if !reflect.DeepEqual(labels, ex.ExpectedLabels) {
+			t.Fatalf("Expected labels to match, but was: %#v", labels)
+		}
+	}
+}
+
+func TestCommandLabelValue(t *testing.T) {
+	exs := map[string]string{
+		"knctl":                    "knctl",
+		"knctl deploy":             "deploy",
+		"knctl route create":       "route-create",
+		"knctl knative-config set": "knative-config-set",
+	}
+
+	for path, expected := range exs {
+		if val := CommandLabelValue(path); val != expected {
+			t.Fatalf("Expected command label value for '%s' to be '%s', but was '%s'", path, expected, val)
+		}
+	}
+}
@@ -0,0 +1,55 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package core
+
+import (
+	"encoding/json"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/runtime/serializer/protobuf"
+	"k8s.io/client-go/kubernetes/scheme"
+)
+
+const (
+	ProtobufContentType = "application/vnd.kubernetes.protobuf"
+
+	// Servers fall back to JSON for resources that cannot be
+	// encoded as protobuf (e.g. custom resources, subresources)
+	protobufAcceptContentTypes = ProtobufContentType + ",application/json"
+)
+
+var (
+	protobufSerializer = protobuf.NewSerializer(scheme.Scheme, scheme.Scheme, ProtobufContentType)
+)
+
+func IsProtobuf(contentType string) bool {
+	return strings.HasPrefix(contentType, ProtobufContentType)
+}
+
+// ProtobufToJSON re-encodes protobuf object (e.g. Secret sent by Core clientset)
+// as JSON so that it could be inspected the same way as other objects
+func ProtobufToJSON(body []byte) ([]byte, error) {
+	obj, gvk, err := protobufSerializer.Decode(body, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// Kind is only included in protobuf envelope
+	obj.GetObjectKind().SetGroupVersionKind(*gvk)
+
+	return json.Marshal(obj)
+}
@@ -0,0 +1,209 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package core_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	. "github.com/cppforlife/knctl/pkg/knctl/cmd/core"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/serializer/protobuf"
+	"k8s.io/client-go/kubernetes/scheme"
+)
+
+func TestProtobufToJSON(t *testing.T) {
+	body, err := ProtobufToJSON(protobufSecret(t))
+	if err != nil {
+		t.Fatalf("Expected conversion to succeed: %s", err)
+	}
+
+	expectedBody := `{"kind":"Secret","apiVersion":"v1","metadata":{"name":"s1","namespace":"ns1","creationTimestamp":null},"data":{"key":"dmFs"}}`
+	if string(body) != expectedBody {
+		t.Fatalf("Expected body to be '%s', but was '%s'", expectedBody, body)
+	}
+
+	_, err = ProtobufToJSON([]byte(`{"kind":"Secret"}`))
+	if err == nil {
+		t.Fatalf("Expected conversion of non-protobuf body to fail")
+	}
+}
+
+func TestDepsFactoryImpl_CoreClientUsesProtobuf(t *testing.T) {
+	var receivedAccept, receivedContentType string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		receivedAccept = r.Header.Get("Accept")
+		receivedContentType = r.Header.Get("Content-Type")
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"apiVersion":"v1","kind":"Secret","metadata":{"name":"s1","namespace":"ns1"}}`))
+	}))
+	defer server.Close()
+
+	kubeconfigPath := filepath.Join(t.TempDir(), "kubeconfig")
+	kubeconfig := fmt.Sprintf(`
+apiVersion: v1
+kind: Config
+current-context: test
+clusters:
+- name: test
+  cluster: {server: "%s"}
+users:
+- name: test
+  user: {}
+contexts:
+- name: test
+  context: {cluster: test, user: test}
+`, server.URL)
+
+	err := ioutil.WriteFile(kubeconfigPath, []byte(kubeconfig), 0600)
+	if err != nil {
+		t.Fatalf("Writing kubeconfig: %s", err)
+	}
+
+	configFactory := NewConfigFactoryImpl()
+	configFactory.ConfigurePathResolver(func() (string, error) { return kubeconfigPath, nil })
+	configFactory.ConfigureContextResolver(func() (string, error) { return "", nil })
+
+	coreClient, err := NewDepsFactoryImpl(configFactory).CoreClient()
+	if err != nil {
+		t.Fatalf("Expected core client to be built: %s", err)
+	}
+
+	secret, err := coreClient.CoreV1().Secrets("ns1").Create(&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "s1"}})
+	if err != nil {
+		t.Fatalf("Expected secret to be created: %s", err)
+	}
+	if secret.Name != "s1" {
+		t.Fatalf("Expected JSON response to be decoded, but was: %#v", secret)
+	}
+
+	if receivedAccept != "application/vnd.kubernetes.protobuf,application/json" {
+		t.Fatalf("Expected protobuf to be accepted, but was '%s'", receivedAccept)
+	}
+	if receivedContentType != "application/vnd.kubernetes.protobuf" {
+		t.Fatalf("Expected protobuf to be sent, but was '%s'", receivedContentType)
+	}
+}
+
+func TestOwnershipRoundTripper_Protobuf(t *testing.T) {
+	var receivedBody []byte
+	var receivedContentType string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		receivedBody, _ = ioutil.ReadAll(r.Body)
+		receivedContentType = r.Header.Get("Content-Type")
+	}))
+	defer server.Close()
+
+	req, err := http.NewRequest("POST", server.URL, bytes.NewReader(protobufSecret(t)))
+	if err != nil {
+		t.Fatalf("Building request: %s", err)
+	}
+
+	req.Header.Set("Content-Type", "application/vnd.kubernetes.protobuf")
+
+	resp, err := NewOwnershipRoundTripper(http.DefaultTransport, "secret-create").RoundTrip(req)
+	if err != nil {
+		t.Fatalf("Expected request to succeed: %s", err)
+	}
+	resp.Body.Close()
+
+	if receivedContentType != "application/json" {
+		t.Fatalf("Expected labeled object to be sent as JSON, but was '%s'", receivedContentType)
+	}
+	if req.Header.Get("Content-Type") != "application/vnd.kubernetes.protobuf" {
+		t.Fatalf("Expected original request to be unchanged")
+	}
+
+	var obj map[string]interface{}
+
+	err = json.Unmarshal(receivedBody, &obj)
+	if err != nil {
+		t.Fatalf("Unmarshaling body: %s", err)
+	}
+
+	expectedLabels := map[string]interface{}{
+		"cli.knative.dev/managed-by": "knctl",
+		"cli.knative.dev/command":    "secret-create",
+	}
+
+	labels := obj["metadata"].(map[string]interface{})["labels"]
+	if !reflect.DeepEqual(labels, expectedLabels) {
+		t.Fatalf("Expected labels to match, but was: %#v", labels)
+	}
+}
+
+func TestManifestRoundTripper_Protobuf(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/vnd.kubernetes.protobuf")
+		w.WriteHeader(http.StatusCreated)
+		w.Write(protobufSecret(t))
+	}))
+	defer server.Close()
+
+	var output bytes.Buffer
+
+	req, err := http.NewRequest("POST", server.URL, nil)
+	if err != nil {
+		t.Fatalf("Building request: %s", err)
+	}
+
+	resp, err := NewManifestRoundTripper(http.DefaultTransport, &output).RoundTrip(req)
+	if err != nil {
+		t.Fatalf("Expected request to succeed: %s", err)
+	}
+
+	body, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+
+	if err != nil || !bytes.Equal(body, protobufSecret(t)) {
+		t.Fatalf("Expected response body to be preserved: %s", body)
+	}
+
+	expectedManifest := "---\napiVersion: v1\ndata:\n  key: <redacted>\nkind: Secret\nmetadata:\n  name: s1\n  namespace: ns1\n"
+	if output.String() != expectedManifest {
+		t.Fatalf("Expected manifest '%s', but was '%s'", expectedManifest, output.String())
+	}
+}
+
+func protobufSecret(t *testing.T) []byte {
+	secret := &corev1.Secret{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Secret"},
+		ObjectMeta: metav1.ObjectMeta{Name: "s1", Namespace: "ns1"},
+		Data:       map[string][]byte{"key": []byte("val")},
+	}
+
+	var buf bytes.Buffer
+
+	err := protobuf.NewSerializer(scheme.Scheme, scheme.Scheme, ProtobufContentType).Encode(secret, &buf)
+	if err != nil {
+		t.Fatalf("Encoding secret: %s", err)
+	}
+
+	return buf.Bytes()
+}
@@ -0,0 +1,144 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package core
+
+import (
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	retryBaseDelay     = 250 * time.Millisecond
+	retryMaxDelay      = 8 * time.Second
+	retryMaxRetryAfter = 60 * time.Second
+)
+
+// RetryRoundTripper retries requests that failed due to throttling (429),
+// server errors (5xx) or dropped connections. Requests that may have been
+// processed by the API server are only retried when they are idempotent.
+type RetryRoundTripper struct {
+	rt         http.RoundTripper
+	maxRetries int
+
+	// Overridden in tests
+	BaseDelay time.Duration
+}
+
+var _ http.RoundTripper = RetryRoundTripper{}
+
+func NewRetryRoundTripper(rt http.RoundTripper, maxRetries int) RetryRoundTripper {
+	return RetryRoundTripper{rt: rt, maxRetries: maxRetries, BaseDelay: retryBaseDelay}
+}
+
+func (t RetryRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		resp, err := t.rt.RoundTrip(req)
+
+		if attempt >= t.maxRetries || !t.retriable(req, resp, err) {
+			return resp, err
+		}
+
+		if req.Body != nil && req.Body != http.NoBody {
+			if req.GetBody == nil {
+				return resp, err // body cannot be replayed
+			}
+
+			body, bodyErr := req.GetBody()
+			if bodyErr != nil {
+				return resp, err
+			}
+
+			req = req.WithContext(req.Context())
+			req.Body = body
+		}
+
+		delay := t.delay(attempt, resp)
+
+		if resp != nil {
+			io.Copy(ioutil.Discard, resp.Body)
+			resp.Body.Close()
+		}
+
+		select {
+		case <-time.After(delay):
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		}
+	}
+}
+
+func (t RetryRoundTripper) retriable(req *http.Request, resp *http.Response, err error) bool {
+	if err != nil {
+		errMsg := err.Error()
+
+		// Nothing was sent to the API server
+		if strings.Contains(errMsg, "connection refused") {
+			return true
+		}
+
+		if strings.Contains(errMsg, "connection reset by peer") || strings.HasSuffix(errMsg, "EOF") {
+			return t.idempotent(req)
+		}
+
+		return false
+	}
+
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests:
+		return true
+	case http.StatusInternalServerError, http.StatusBadGateway,
+		http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return t.idempotent(req)
+	default:
+		return false
+	}
+}
+
+func (RetryRoundTripper) idempotent(req *http.Request) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	default:
+		return false
+	}
+}
+
+func (t RetryRoundTripper) delay(attempt int, resp *http.Response) time.Duration {
+	if resp != nil {
+		secs, err := strconv.Atoi(resp.Header.Get("Retry-After"))
+		if err == nil && secs >= 0 {
+			delay := time.Duration(secs) * time.Second
+			if delay > retryMaxRetryAfter {
+				delay = retryMaxRetryAfter
+			}
+			return delay
+		}
+	}
+
+	delay := t.BaseDelay << uint(attempt)
+	if delay > retryMaxDelay || delay <= 0 {
+		delay = retryMaxDelay
+	}
+
+	// Jitter between half and full delay to avoid retrying in lockstep
+	return delay/2 + time.Duration(rand.Int63n(int64(delay/2)+1))
+}
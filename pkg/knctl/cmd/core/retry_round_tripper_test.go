@@ -0,0 +1,92 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package core_test
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/cppforlife/knctl/pkg/knctl/cmd/core"
+)
+
+func TestRetryRoundTripper(t *testing.T) {
+	type example struct {
+		Method   string
+		Statuses []int
+		Retries  int
+
+		ExpectedStatus   int
+		ExpectedRequests int
+	}
+
+	exs := []example{
+		{Method: "GET", Statuses: []int{200}, Retries: 3, ExpectedStatus: 200, ExpectedRequests: 1},
+		{Method: "GET", Statuses: []int{503, 502, 200}, Retries: 3, ExpectedStatus: 200, ExpectedRequests: 3},
+		{Method: "GET", Statuses: []int{500, 500, 500}, Retries: 1, ExpectedStatus: 500, ExpectedRequests: 2},
+		{Method: "GET", Statuses: []int{404}, Retries: 3, ExpectedStatus: 404, ExpectedRequests: 1},
+		{Method: "POST", Statuses: []int{429, 201}, Retries: 3, ExpectedStatus: 201, ExpectedRequests: 2},
+		{Method: "POST", Statuses: []int{503, 201}, Retries: 3, ExpectedStatus: 503, ExpectedRequests: 1},
+		{Method: "PUT", Statuses: []int{503, 200}, Retries: 3, ExpectedStatus: 200, ExpectedRequests: 2},
+		{Method: "GET", Statuses: []int{503, 200}, Retries: 0, ExpectedStatus: 503, ExpectedRequests: 1},
+	}
+
+	for _, ex := range exs {
+		var reqs int
+		var bodies []string
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := ioutil.ReadAll(r.Body)
+			bodies = append(bodies, string(body))
+
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(ex.Statuses[reqs])
+			reqs++
+		}))
+
+		rt := NewRetryRoundTripper(http.DefaultTransport, ex.Retries)
+		rt.BaseDelay = time.Millisecond
+
+		req, err := http.NewRequest(ex.Method, server.URL, strings.NewReader("body"))
+		if err != nil {
+			t.Fatalf("Building request: %s", err)
+		}
+
+		resp, err := rt.RoundTrip(req)
+		server.Close()
+
+		if err != nil {
+			t.Fatalf("Expected request to succeed: %s", err)
+		}
+		resp.Body.Close()
+
+		if resp.StatusCode != ex.ExpectedStatus {
+			t.Fatalf("Expected status %d but was %d (%#v)", ex.ExpectedStatus, resp.StatusCode, ex)
+		}
+		if reqs != ex.ExpectedRequests {
+			t.Fatalf("Expected %d requests but was %d (%#v)", ex.ExpectedRequests, reqs, ex)
+		}
+		for _, body := range bodies {
+			if body != "body" {
+				t.Fatalf("Expected request body to be replayed but was '%s'", body)
+			}
+		}
+	}
+}
@@ -0,0 +1,51 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package core
+
+import (
+	"strconv"
+	"strings"
+
+	"k8s.io/client-go/discovery"
+)
+
+const (
+	// Dry run became enabled by default in Kubernetes 1.13;
+	// older API servers ignore dryRun query param and persist changes
+	dryRunMinMinorVersion = 13
+)
+
+// DryRunSupported returns true if API server honors dryRun query param
+func DryRunSupported(client discovery.ServerVersionInterface) (bool, error) {
+	version, err := client.ServerVersion()
+	if err != nil {
+		return false, err
+	}
+
+	major, err := strconv.Atoi(version.Major)
+	if err != nil {
+		return false, err
+	}
+
+	// Minor version may include suffix (e.g. '14+')
+	minor, err := strconv.Atoi(strings.TrimRight(version.Minor, "+"))
+	if err != nil {
+		return false, err
+	}
+
+	return major > 1 || (major == 1 && minor >= dryRunMinMinorVersion), nil
+}
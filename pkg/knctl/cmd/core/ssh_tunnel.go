@@ -0,0 +1,130 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package core
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// SSHTunnel starts local SOCKS5 proxy that forwards connections
+// through a bastion (jump) host. Relies on 'ssh' command so that
+// user's SSH configuration, agent and known hosts are respected.
+type SSHTunnel struct {
+	bastion      string
+	startTimeout time.Duration
+
+	cmd    *exec.Cmd
+	stderr bytes.Buffer
+}
+
+func NewSSHTunnel(bastion string) *SSHTunnel {
+	return &SSHTunnel{bastion: bastion, startTimeout: 15 * time.Second}
+}
+
+// SSHArgs returns ssh arguments for bastion in format [user@]host[:port]
+func (t *SSHTunnel) SSHArgs(localAddr string) ([]string, error) {
+	dest := t.bastion
+	args := []string{"-N", "-D", localAddr, "-o", "ExitOnForwardFailure=yes", "-o", "BatchMode=yes"}
+
+	userPrefix := ""
+	if idx := strings.LastIndex(dest, "@"); idx >= 0 {
+		userPrefix, dest = dest[:idx+1], dest[idx+1:]
+	}
+
+	if host, port, err := net.SplitHostPort(dest); err == nil {
+		dest = host
+		args = append(args, "-p", port)
+	}
+
+	if len(dest) == 0 || userPrefix == "@" {
+		return nil, fmt.Errorf("Expected SSH bastion '%s' to be in format [user@]host[:port]", t.bastion)
+	}
+
+	return append(args, userPrefix+dest), nil
+}
+
+// Start returns proxy URL (socks5h scheme to resolve hosts on the bastion side)
+func (t *SSHTunnel) Start() (string, error) {
+	localAddr, err := t.freeLocalAddr()
+	if err != nil {
+		return "", err
+	}
+
+	args, err := t.SSHArgs(localAddr)
+	if err != nil {
+		return "", err
+	}
+
+	t.cmd = exec.Command("ssh", args...)
+	t.cmd.Stderr = &t.stderr
+
+	err = t.cmd.Start()
+	if err != nil {
+		return "", fmt.Errorf("Starting SSH tunnel: %s", err)
+	}
+
+	exitCh := make(chan error, 1)
+	go func() { exitCh <- t.cmd.Wait() }()
+
+	deadline := time.Now().Add(t.startTimeout)
+
+	for {
+		conn, err := net.DialTimeout("tcp", localAddr, 1*time.Second)
+		if err == nil {
+			conn.Close()
+			return "socks5h://" + localAddr, nil
+		}
+
+		select {
+		case err := <-exitCh:
+			t.cmd = nil
+			return "", fmt.Errorf("SSH tunnel via '%s' exited: %v (stderr: %s)",
+				t.bastion, err, strings.TrimSpace(t.stderr.String()))
+		default:
+		}
+
+		if time.Now().After(deadline) {
+			t.Stop()
+			return "", fmt.Errorf("Timed out waiting for SSH tunnel via '%s' to start", t.bastion)
+		}
+
+		time.Sleep(200 * time.Millisecond)
+	}
+}
+
+func (t *SSHTunnel) Stop() {
+	if t.cmd != nil && t.cmd.Process != nil {
+		t.cmd.Process.Kill()
+		t.cmd = nil
+	}
+}
+
+func (*SSHTunnel) freeLocalAddr() (string, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return "", fmt.Errorf("Finding free local port: %s", err)
+	}
+
+	defer listener.Close()
+
+	return listener.Addr().String(), nil
+}
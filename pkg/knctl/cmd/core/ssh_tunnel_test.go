@@ -0,0 +1,94 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package core_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	. "github.com/cppforlife/knctl/pkg/knctl/cmd/core"
+)
+
+func TestSSHTunnel_SSHArgs(t *testing.T) {
+	exs := map[string][]string{
+		"bastion.corp":         {"bastion.corp"},
+		"ops@bastion.corp":     {"ops@bastion.corp"},
+		"ops@bastion.corp:222": {"-p", "222", "ops@bastion.corp"},
+		"10.0.0.5:2222":        {"-p", "2222", "10.0.0.5"},
+	}
+
+	for bastion, expectedSuffix := range exs {
+		args, err := NewSSHTunnel(bastion).SSHArgs("127.0.0.1:9999")
+		if err != nil {
+			t.Fatalf("Expected no error for '%s': %s", bastion, err)
+		}
+
+		expectedArgs := append([]string{"-N", "-D", "127.0.0.1:9999",
+			"-o", "ExitOnForwardFailure=yes", "-o", "BatchMode=yes"}, expectedSuffix...)
+
+		if !reflect.DeepEqual(args, expectedArgs) {
+			t.Fatalf("Expected args for '%s' to be %#v but was %#v", bastion, expectedArgs, args)
+		}
+	}
+}
+
+func TestSSHTunnel_SSHArgsInvalid(t *testing.T) {
+	for _, bastion := range []string{"ops@", "@bastion.corp", ":22"} {
+		_, err := NewSSHTunnel(bastion).SSHArgs("127.0.0.1:9999")
+		if err == nil || !strings.Contains(err.Error(), "to be in format [user@]host[:port]") {
+			t.Fatalf("Expected error about format for '%s' but was: %v", bastion, err)
+		}
+	}
+}
+
+func TestSSHTunnel_StartFailsWhenSSHExits(t *testing.T) {
+	binDir, err := ioutil.TempDir("", "knctl-ssh")
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	defer os.RemoveAll(binDir)
+
+	script := "#!/bin/sh\necho 'Permission denied (publickey).' >&2\nexit 255\n"
+
+	err = ioutil.WriteFile(filepath.Join(binDir, "ssh"), []byte(script), 0700)
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	origPath := os.Getenv("PATH")
+	os.Setenv("PATH", binDir)
+	defer os.Setenv("PATH", origPath)
+
+	_, err = NewSSHTunnel("ops@bastion.corp").Start()
+	if err == nil || !strings.Contains(err.Error(), "Permission denied (publickey).") {
+		t.Fatalf("Expected error about ssh exiting but was: %v", err)
+	}
+}
+
+func TestTransportFlags_SSHBastionConflictsWithProxy(t *testing.T) {
+	flags := TransportFlags{Proxy: "http://proxy.corp:3128", SSHBastion: "ops@bastion.corp"}
+
+	_, err := flags.TransportConfig()
+	if err == nil || err.Error() != "Expected only one of --proxy or --ssh-bastion to be specified" {
+		t.Fatalf("Expected error about conflicting flags but was: %v", err)
+	}
+}
@@ -0,0 +1,63 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package core
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/cppforlife/knctl/pkg/knctl/logger"
+)
+
+// TracingRoundTripper logs every API request with its latency and response code
+type TracingRoundTripper struct {
+	rt     http.RoundTripper
+	logger logger.Logger
+}
+
+var _ http.RoundTripper = TracingRoundTripper{}
+
+func NewTracingRoundTripper(rt http.RoundTripper, logger logger.Logger) TracingRoundTripper {
+	return TracingRoundTripper{rt: rt, logger: logger.WithTag("api")}
+}
+
+func (t TracingRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	startTime := time.Now()
+
+	resp, err := t.rt.RoundTrip(req)
+
+	fields := logger.Fields{
+		"verb":    req.Method,
+		"host":    req.URL.Host,
+		"path":    req.URL.Path,
+		"latency": time.Since(startTime).Round(time.Millisecond),
+	}
+
+	if len(req.URL.RawQuery) > 0 {
+		fields["query"] = req.URL.RawQuery
+	}
+
+	if err != nil {
+		fields["error"] = err.Error()
+	} else {
+		fields["status"] = resp.StatusCode
+	}
+
+	t.logger.Debug("request", fields)
+
+	return resp, err
+}
@@ -0,0 +1,51 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package core_test
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	. "github.com/cppforlife/knctl/pkg/knctl/cmd/core"
+	"github.com/cppforlife/knctl/pkg/knctl/logger"
+)
+
+func TestTracingRoundTripper(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	var buf bytes.Buffer
+
+	client := &http.Client{Transport: NewTracingRoundTripper(http.DefaultTransport, logger.NewLogger(&buf))}
+
+	resp, err := client.Get(server.URL + "/api/v1/namespaces?limit=10")
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+	resp.Body.Close()
+
+	for _, expected := range []string{"tag=api", "msg=request", "verb=GET", "path=/api/v1/namespaces", `query="limit=10"`, "status=404", "latency="} {
+		if !strings.Contains(buf.String(), expected) {
+			t.Fatalf("Expected log '%s' to include '%s'", buf.String(), expected)
+		}
+	}
+}
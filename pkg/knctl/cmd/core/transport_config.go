@@ -0,0 +1,214 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package core
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// TransportConfig describes how knctl reaches non-Kubernetes HTTP endpoints
+// (e.g. ingress addresses, release assets) and overrides proxy settings
+// for Kubernetes API requests. Proxy environment variables are honored
+// unless overridden.
+type TransportConfig struct {
+	Proxy        string
+	NoProxy      []string
+	CABundlePath string
+}
+
+func (c TransportConfig) HasProxyOverrides() bool {
+	return len(c.Proxy) > 0 || len(c.NoProxy) > 0
+}
+
+// ProxyFunc uses explicitly configured proxy or falls back
+// to HTTP_PROXY, HTTPS_PROXY and NO_PROXY environment variables
+func (c TransportConfig) ProxyFunc() (func(*http.Request) (*url.URL, error), error) {
+	var proxyURL *url.URL
+
+	if len(c.Proxy) > 0 {
+		var err error
+
+		proxyURL, err = url.Parse(c.Proxy)
+		if err != nil || len(proxyURL.Host) == 0 {
+			return nil, fmt.Errorf("Expected proxy '%s' to be a URL (example: http://proxy:3128)", c.Proxy)
+		}
+	}
+
+	return func(req *http.Request) (*url.URL, error) {
+		if c.bypassesProxy(req.URL) {
+			return nil, nil
+		}
+		if proxyURL != nil {
+			return proxyURL, nil
+		}
+		return http.ProxyFromEnvironment(req)
+	}, nil
+}
+
+// HTTPClient returns client that honors proxy and CA bundle configuration
+func (c TransportConfig) HTTPClient() (*http.Client, error) {
+	proxyFunc, err := c.ProxyFunc()
+	if err != nil {
+		return nil, err
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = proxyFunc
+
+	if len(c.CABundlePath) > 0 {
+		pool, err := c.certPool()
+		if err != nil {
+			return nil, err
+		}
+
+		transport.TLSClientConfig = &tls.Config{RootCAs: pool}
+	}
+
+	return &http.Client{Transport: transport}, nil
+}
+
+// CurlArgs returns arguments that configure curl the same way
+// (curl itself honors proxy environment variables)
+func (c TransportConfig) CurlArgs() []string {
+	var args []string
+
+	if len(c.Proxy) > 0 {
+		args = append(args, "--proxy", c.Proxy)
+	}
+	if len(c.NoProxy) > 0 {
+		noProxy := c.NoProxy
+		if envNoProxy := c.envNoProxy(); len(envNoProxy) > 0 {
+			noProxy = append(envNoProxy, noProxy...)
+		}
+		args = append(args, "--noproxy", strings.Join(noProxy, ","))
+	}
+	if len(c.CABundlePath) > 0 {
+		args = append(args, "--cacert", c.CABundlePath)
+	}
+
+	return args
+}
+
+// WrapTransport applies proxy overrides to Kubernetes client transport.
+// Requests fail (instead of silently bypassing proxy) if overrides cannot be applied.
+func (c TransportConfig) WrapTransport(rt http.RoundTripper) http.RoundTripper {
+	if !c.HasProxyOverrides() {
+		return rt
+	}
+
+	transport, ok := rt.(*http.Transport)
+	if !ok {
+		return failingRoundTripper{fmt.Errorf(
+			"Expected Kubernetes client transport to be *http.Transport to apply proxy overrides, but was %T", rt)}
+	}
+
+	proxyFunc, err := c.ProxyFunc()
+	if err != nil {
+		return failingRoundTripper{err}
+	}
+
+	transport = transport.Clone()
+	transport.Proxy = proxyFunc
+
+	return transport
+}
+
+type failingRoundTripper struct {
+	err error
+}
+
+func (t failingRoundTripper) RoundTrip(*http.Request) (*http.Response, error) { return nil, t.err }
+
+func (c TransportConfig) certPool() (*x509.CertPool, error) {
+	pool, err := x509.SystemCertPool()
+	if err != nil || pool == nil {
+		pool = x509.NewCertPool()
+	}
+
+	pemBytes, err := ioutil.ReadFile(c.CABundlePath)
+	if err != nil {
+		return nil, fmt.Errorf("Reading CA bundle: %s", err)
+	}
+
+	if !pool.AppendCertsFromPEM(pemBytes) {
+		return nil, fmt.Errorf("Expected CA bundle '%s' to contain at least one PEM encoded certificate", c.CABundlePath)
+	}
+
+	return pool, nil
+}
+
+// bypassesProxy checks explicitly configured no-proxy entries;
+// NO_PROXY environment variable is checked by http.ProxyFromEnvironment
+func (c TransportConfig) bypassesProxy(u *url.URL) bool {
+	host := u.Hostname()
+
+	noProxy := c.NoProxy
+	if len(c.Proxy) > 0 {
+		// Explicit proxy replaces HTTP(S)_PROXY but NO_PROXY still applies
+		noProxy = append(c.envNoProxy(), noProxy...)
+	}
+
+	for _, entry := range noProxy {
+		entry = strings.TrimSpace(entry)
+
+		switch {
+		case len(entry) == 0:
+			continue
+
+		case entry == "*":
+			return true
+
+		case strings.Contains(entry, "/"):
+			_, cidr, err := net.ParseCIDR(entry)
+			if err == nil {
+				if ip := net.ParseIP(host); ip != nil && cidr.Contains(ip) {
+					return true
+				}
+			}
+
+		default:
+			if h, _, err := net.SplitHostPort(entry); err == nil {
+				entry = h
+			}
+			entry = strings.TrimPrefix(entry, "*")
+			if host == strings.TrimPrefix(entry, ".") || strings.HasSuffix(host, "."+strings.TrimPrefix(entry, ".")) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func (TransportConfig) envNoProxy() []string {
+	val := os.Getenv("NO_PROXY")
+	if len(val) == 0 {
+		val = os.Getenv("no_proxy")
+	}
+	if len(val) == 0 {
+		return nil
+	}
+	return strings.Split(val, ",")
+}
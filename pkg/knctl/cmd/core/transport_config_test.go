@@ -0,0 +1,164 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package core_test
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"reflect"
+	"sync/atomic"
+	"testing"
+
+	. "github.com/cppforlife/knctl/pkg/knctl/cmd/core"
+	"github.com/cppforlife/knctl/pkg/knctl/logger"
+	"github.com/cppforlife/knctl/pkg/knctl/testkit"
+	"k8s.io/client-go/kubernetes"
+)
+
+func TestTransportConfig_ProxyFunc(t *testing.T) {
+	os.Unsetenv("NO_PROXY")
+	os.Unsetenv("no_proxy")
+
+	config := TransportConfig{
+		Proxy:   "http://proxy.corp:3128",
+		NoProxy: []string{".internal.corp", "10.0.0.0/8", "example.com"},
+	}
+
+	proxyFunc, err := config.ProxyFunc()
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	exs := map[string]string{
+		"http://lb.us-east-1.elb.amazonaws.com/": "http://proxy.corp:3128",
+		"http://svc.internal.corp/":              "",
+		"https://internal.corp/":                 "",
+		"http://10.1.2.3:31380/":                 "",
+		"http://11.1.2.3/":                       "http://proxy.corp:3128",
+		"http://example.com/":                    "",
+		"http://sub.example.com/":                "",
+		"http://notexample.com/":                 "http://proxy.corp:3128",
+	}
+
+	for reqURL, expectedProxy := range exs {
+		req, _ := http.NewRequest("GET", reqURL, nil)
+
+		proxyURL, err := proxyFunc(req)
+		if err != nil {
+			t.Fatalf("Expected no error: %s", err)
+		}
+
+		var actualProxy string
+		if proxyURL != nil {
+			actualProxy = proxyURL.String()
+		}
+
+		if actualProxy != expectedProxy {
+			t.Fatalf("Expected proxy for '%s' to be '%s' but was '%s'", reqURL, expectedProxy, actualProxy)
+		}
+	}
+}
+
+func TestTransportConfig_ProxyFuncInvalidProxy(t *testing.T) {
+	_, err := TransportConfig{Proxy: "proxy.corp"}.ProxyFunc()
+	if err == nil || err.Error() != "Expected proxy 'proxy.corp' to be a URL (example: http://proxy:3128)" {
+		t.Fatalf("Expected error about invalid proxy URL but was: %v", err)
+	}
+}
+
+func TestTransportConfig_CurlArgs(t *testing.T) {
+	os.Unsetenv("NO_PROXY")
+	os.Unsetenv("no_proxy")
+
+	args := TransportConfig{
+		Proxy:        "http://proxy.corp:3128",
+		NoProxy:      []string{".internal.corp", "10.0.0.0/8"},
+		CABundlePath: "/tmp/ca.pem",
+	}.CurlArgs()
+
+	expectedArgs := []string{
+		"--proxy", "http://proxy.corp:3128",
+		"--noproxy", ".internal.corp,10.0.0.0/8",
+		"--cacert", "/tmp/ca.pem",
+	}
+
+	if !reflect.DeepEqual(args, expectedArgs) {
+		t.Fatalf("Expected args to be %#v but was %#v", expectedArgs, args)
+	}
+
+	if len(TransportConfig{}.CurlArgs()) != 0 {
+		t.Fatalf("Expected no args without overrides")
+	}
+}
+
+func TestTransportConfig_HTTPClientInvalidCABundle(t *testing.T) {
+	_, err := TransportConfig{CABundlePath: "/non-existent-ca.pem"}.HTTPClient()
+	if err == nil {
+		t.Fatalf("Expected error for missing CA bundle")
+	}
+}
+
+func TestTransportConfig_WrapTransportFailsForUnknownTransport(t *testing.T) {
+	rt := TransportConfig{Proxy: "http://proxy:3128"}.WrapTransport(http.NewFileTransport(http.Dir("/")))
+
+	_, err := rt.RoundTrip(&http.Request{})
+	if err == nil {
+		t.Fatalf("Expected requests to fail instead of bypassing proxy")
+	}
+}
+
+func TestConfigFactoryImpl_ProxyAppliedWithDebugLogging(t *testing.T) {
+	cluster := testkit.NewCluster(t)
+
+	var proxied int32
+
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&proxied, 1)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer proxy.Close()
+
+	path := cluster.KubeconfigPath()
+
+	configFactory := NewConfigFactoryImpl()
+	configFactory.ConfigurePathResolver(func() (string, error) { return path, nil })
+	configFactory.ConfigureContextResolver(func() (string, error) { return "", nil })
+	configFactory.ConfigureTransportResolver(func() (TransportConfig, error) {
+		return TransportConfig{Proxy: proxy.URL}, nil
+	})
+	configFactory.ConfigureLoggerResolver(func() (logger.Logger, error) {
+		return logger.NewLogger(&bytes.Buffer{}), nil
+	})
+
+	config, err := configFactory.RESTConfig()
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	client, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	client.Discovery().ServerVersion()
+
+	if atomic.LoadInt32(&proxied) == 0 {
+		t.Fatalf("Expected request to go through proxy")
+	}
+}
@@ -0,0 +1,84 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package core
+
+import (
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/spf13/cobra"
+)
+
+type TransportFlags struct {
+	Proxy      string
+	NoProxy    []string
+	CABundle   string
+	SSHBastion string
+
+	sshTunnel      *SSHTunnel
+	sshTunnelOnce  sync.Once
+	sshTunnelProxy string
+	sshTunnelErr   error
+}
+
+func (f *TransportFlags) Set(cmd *cobra.Command, flagsFactory FlagsFactory) {
+	cmd.PersistentFlags().StringVar(&f.Proxy, "proxy", "",
+		"Proxy URL for HTTP requests (defaults to $HTTPS_PROXY or $HTTP_PROXY)")
+	cmd.PersistentFlags().StringSliceVar(&f.NoProxy, "no-proxy", nil,
+		"Hosts, domains or CIDRs that should not be proxied, in addition to $NO_PROXY (can be specified multiple times)")
+	cmd.PersistentFlags().StringVar(&f.CABundle, "ca-bundle", os.Getenv("KNCTL_CA_BUNDLE"),
+		"Path to PEM encoded CA certificates trusted when reaching ingress and download URLs ($KNCTL_CA_BUNDLE)")
+	cmd.PersistentFlags().StringVar(&f.SSHBastion, "ssh-bastion", os.Getenv("KNCTL_SSH_BASTION"),
+		"Tunnel HTTP requests through SSH bastion host in format [user@]host[:port] (requires 'ssh' command) ($KNCTL_SSH_BASTION)")
+}
+
+func (f *TransportFlags) TransportConfig() (TransportConfig, error) {
+	config := TransportConfig{Proxy: f.Proxy, NoProxy: f.NoProxy, CABundlePath: f.CABundle}
+
+	if len(f.SSHBastion) > 0 {
+		if len(f.Proxy) > 0 {
+			return TransportConfig{}, fmt.Errorf("Expected only one of --proxy or --ssh-bastion to be specified")
+		}
+
+		// Tunnel is shared by all clients and stays up until Close is called
+		f.sshTunnelOnce.Do(func() {
+			f.sshTunnel = NewSSHTunnel(f.SSHBastion)
+			f.sshTunnelProxy, f.sshTunnelErr = f.sshTunnel.Start()
+		})
+		if f.sshTunnelErr != nil {
+			return TransportConfig{}, f.sshTunnelErr
+		}
+
+		config.Proxy = f.sshTunnelProxy
+	}
+
+	// Validate proxy URL early
+	_, err := config.ProxyFunc()
+	if err != nil {
+		return TransportConfig{}, err
+	}
+
+	return config, nil
+}
+
+// Close stops SSH tunnel if it was started
+func (f *TransportFlags) Close() {
+	if f.sshTunnel != nil {
+		f.sshTunnel.Stop()
+	}
+}
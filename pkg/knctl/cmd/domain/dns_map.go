@@ -50,12 +50,17 @@ func (o *DNSMapOptions) Run() error {
 		return err
 	}
 
+	dynamicClient, err := o.depsFactory.DynamicClient()
+	if err != nil {
+		return err
+	}
+
 	domains, err := NewDomains(coreClient).List()
 	if err != nil {
 		return err
 	}
 
-	ingSvcs, err := ctling.NewIngressServices(coreClient).List()
+	ingSvcs, err := ctling.NewIngressServices(coreClient).WithDynamicClient(dynamicClient).List()
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,128 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package domain
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cppforlife/go-cli-ui/ui"
+)
+
+var (
+	// DefaultPublicResolvers are well known public DNS resolvers operated by different providers
+	DefaultPublicResolvers = []string{"8.8.8.8", "1.1.1.1", "9.9.9.9"}
+)
+
+// DNSLookupFunc returns addresses of a domain as seen by given resolver
+type DNSLookupFunc func(resolver, domain string) ([]string, error)
+
+type DNSPropagation struct {
+	Resolvers    []string
+	LookupFunc   DNSLookupFunc
+	PollInterval time.Duration
+	Timeout      time.Duration
+
+	ui ui.UI
+}
+
+func NewDNSPropagation(resolvers []string, timeout time.Duration, ui ui.UI) DNSPropagation {
+	return DNSPropagation{
+		Resolvers:    resolvers,
+		LookupFunc:   LookupWithResolver,
+		PollInterval: 10 * time.Second,
+		Timeout:      timeout,
+
+		ui: ui,
+	}
+}
+
+// Wait returns once all resolvers return at least one of expected IPs for the domain
+func (p DNSPropagation) Wait(domain string, expectedIPs []string) error {
+	p.ui.PrintLinef("Waiting for domain '%s' to resolve to %s via %s for up to %s...",
+		domain, strings.Join(expectedIPs, ", "), strings.Join(p.Resolvers, ", "), p.Timeout)
+
+	lastResults := map[string]string{}
+	deadline := time.Now().Add(p.Timeout)
+
+	for {
+		propagated := true
+
+		for _, resolver := range p.Resolvers {
+			result, matched := p.check(resolver, domain, expectedIPs)
+			if !matched {
+				propagated = false
+			}
+
+			if lastResults[resolver] != result {
+				p.ui.PrintLinef("%s: %s", resolver, result)
+				lastResults[resolver] = result
+			}
+		}
+
+		if propagated {
+			p.ui.PrintLinef("Domain '%s' resolves to expected IP via all resolvers", domain)
+			return nil
+		}
+
+		if time.Now().After(deadline) {
+			return fmt.Errorf("Expected domain '%s' to resolve to %s via all resolvers within %s",
+				domain, strings.Join(expectedIPs, ", "), p.Timeout)
+		}
+
+		time.Sleep(p.PollInterval)
+	}
+}
+
+func (p DNSPropagation) check(resolver, domain string, expectedIPs []string) (string, bool) {
+	addrs, err := p.LookupFunc(resolver, domain)
+	if err != nil {
+		return fmt.Sprintf("lookup failed (%s)", err), false
+	}
+
+	sort.Strings(addrs)
+
+	for _, addr := range addrs {
+		for _, expectedIP := range expectedIPs {
+			if addr == expectedIP {
+				return fmt.Sprintf("resolved to %s (propagated)", strings.Join(addrs, ", ")), true
+			}
+		}
+	}
+
+	return fmt.Sprintf("resolved to %s (not propagated)", strings.Join(addrs, ", ")), false
+}
+
+// LookupWithResolver queries given DNS server directly, bypassing local resolver configuration
+func LookupWithResolver(resolver, domain string) ([]string, error) {
+	r := &net.Resolver{
+		PreferGo: true,
+		Dial: func(ctx context.Context, network, _ string) (net.Conn, error) {
+			var dialer net.Dialer
+			return dialer.DialContext(ctx, network, net.JoinHostPort(resolver, "53"))
+		},
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	return r.LookupHost(ctx, domain)
+}
@@ -0,0 +1,71 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package domain_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/cppforlife/go-cli-ui/ui"
+	. "github.com/cppforlife/knctl/pkg/knctl/cmd/domain"
+)
+
+func TestDNSPropagation_WaitsForAllResolvers(t *testing.T) {
+	lookups := map[string]int{}
+
+	propagation := NewDNSPropagation([]string{"r1", "r2"}, time.Minute, ui.NewNoopUI())
+	propagation.PollInterval = time.Millisecond
+	propagation.LookupFunc = func(resolver, domain string) ([]string, error) {
+		lookups[resolver]++
+
+		if domain != "api.example.com" {
+			t.Fatalf("Expected domain to be looked up, but was: %s", domain)
+		}
+
+		switch {
+		case resolver == "r1":
+			return []string{"1.2.3.4"}, nil
+		case lookups[resolver] == 1:
+			return nil, fmt.Errorf("no such host")
+		case lookups[resolver] == 2:
+			return []string{"5.6.7.8"}, nil
+		default:
+			return []string{"5.6.7.8", "1.2.3.4"}, nil
+		}
+	}
+
+	err := propagation.Wait("api.example.com", []string{"1.2.3.4"})
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	if lookups["r2"] != 3 {
+		t.Fatalf("Expected resolver to be polled until propagated, but was polled %d times", lookups["r2"])
+	}
+}
+
+func TestDNSPropagation_TimesOut(t *testing.T) {
+	propagation := NewDNSPropagation([]string{"r1"}, 0, ui.NewNoopUI())
+	propagation.PollInterval = time.Millisecond
+	propagation.LookupFunc = func(_, _ string) ([]string, error) { return []string{"5.6.7.8"}, nil }
+
+	err := propagation.Wait("api.example.com", []string{"1.2.3.4"})
+	if err == nil || err.Error() != "Expected domain 'api.example.com' to resolve to 1.2.3.4 via all resolvers within 0s" {
+		t.Fatalf("Expected timeout error, but was: %v", err)
+	}
+}
@@ -18,6 +18,7 @@ package domain
 
 import (
 	"fmt"
+	"sort"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
@@ -36,6 +37,9 @@ type Domains struct {
 type Domain struct {
 	Name    string
 	Default bool
+
+	// Selector restricts domain to routes with matching labels
+	Selector map[string]string
 }
 
 func NewDomains(coreClient kubernetes.Interface) Domains {
@@ -62,11 +66,39 @@ func (d Domains) List() ([]Domain, error) {
 }
 
 func (d Domains) Create(domain Domain) error {
+	return d.create(domain, true)
+}
+
+// CreateWithoutOverwrite refuses to replace existing domain entry
+// (e.g. cluster default domain) with a selector based one
+func (d Domains) CreateWithoutOverwrite(domain Domain) error {
+	return d.create(domain, false)
+}
+
+// CheckOverwrite returns an error if creating domain
+// would replace existing domain entry with a different value
+func (d Domains) CheckOverwrite(domain Domain) error {
+	config, err := d.coreClient.CoreV1().ConfigMaps(domainsNs).Get(domainsConfigMapName, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+
+	return d.checkOverwrite(config.Data, domain)
+}
+
+func (d Domains) create(domain Domain, overwrite bool) error {
 	config, err := d.coreClient.CoreV1().ConfigMaps(domainsNs).Get(domainsConfigMapName, metav1.GetOptions{})
 	if err != nil {
 		return err
 	}
 
+	if !overwrite {
+		err := d.checkOverwrite(config.Data, domain)
+		if err != nil {
+			return err
+		}
+	}
+
 	if domain.Default {
 		for k, v := range config.Data {
 			if v == domainsDefaultValue {
@@ -76,8 +108,13 @@ func (d Domains) Create(domain Domain) error {
 		}
 
 		config.Data[domain.Name] = domainsDefaultValue
+	} else if len(domain.Selector) > 0 {
+		if config.Data == nil {
+			config.Data = map[string]string{}
+		}
+		config.Data[domain.Name] = domain.selectorValue()
 	} else {
-		return fmt.Errorf("Setting non-default domain is not supported")
+		return fmt.Errorf("Setting non-default domain without selector is not supported")
 	}
 
 	_, err = d.coreClient.CoreV1().ConfigMaps(domainsNs).Update(config)
@@ -87,3 +124,82 @@ func (d Domains) Create(domain Domain) error {
 
 	return nil
 }
+
+func (d Domains) checkOverwrite(data map[string]string, domain Domain) error {
+	existingVal, found := data[domain.Name]
+	if !found {
+		return nil
+	}
+
+	newVal := domainsDefaultValue
+	if !domain.Default {
+		newVal = domain.selectorValue()
+	}
+
+	if existingVal == newVal {
+		return nil
+	}
+
+	if existingVal == domainsDefaultValue {
+		return fmt.Errorf("Expected domain '%s' to not be already used as default domain", domain.Name)
+	}
+
+	return fmt.Errorf("Expected domain '%s' to not be already used with a different selector", domain.Name)
+}
+
+func (d Domains) Delete(name string) error {
+	config, err := d.coreClient.CoreV1().ConfigMaps(domainsNs).Get(domainsConfigMapName, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+
+	if _, found := config.Data[name]; !found {
+		return nil
+	}
+
+	delete(config.Data, name)
+
+	_, err = d.coreClient.CoreV1().ConfigMaps(domainsNs).Update(config)
+	if err != nil {
+		return fmt.Errorf("Updating domains: %s", err)
+	}
+
+	return nil
+}
+
+// FindBySelector returns names of domains with exactly matching selector
+func (d Domains) FindBySelector(selector map[string]string) ([]string, error) {
+	config, err := d.coreClient.CoreV1().ConfigMaps(domainsNs).Get(domainsConfigMapName, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	expectedVal := Domain{Selector: selector}.selectorValue()
+
+	var names []string
+
+	for k, v := range config.Data {
+		if v == expectedVal {
+			names = append(names, k)
+		}
+	}
+
+	sort.Strings(names)
+
+	return names, nil
+}
+
+// Format is described in https://github.com/knative/serving/blob/master/config/config-domain.yaml
+func (d Domain) selectorValue() string {
+	var keys []string
+	for k, _ := range d.Selector {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	result := "selector:\n"
+	for _, k := range keys {
+		result += fmt.Sprintf("  %s: %s\n", k, d.Selector[k])
+	}
+	return result
+}
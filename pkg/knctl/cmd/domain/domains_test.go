@@ -0,0 +1,91 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package domain_test
+
+import (
+	"testing"
+
+	. "github.com/cppforlife/knctl/pkg/knctl/cmd/domain"
+	"github.com/cppforlife/knctl/pkg/knctl/testkit"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestDomainsCreateWithoutOverwrite(t *testing.T) {
+	cluster := testkit.NewCluster(t, &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "config-domain", Namespace: "knative-serving"},
+		Data:       map[string]string{"example.com": ""},
+	})
+	domains := NewDomains(cluster.CoreClient())
+
+	err := domains.CreateWithoutOverwrite(Domain{Name: "example.com", Selector: map[string]string{"app": "ns1"}})
+	if err == nil || err.Error() != "Expected domain 'example.com' to not be already used as default domain" {
+		t.Fatalf("Expected error refusing to overwrite default domain, but was: %v", err)
+	}
+
+	err = domains.CreateWithoutOverwrite(Domain{Name: "sub.example.com", Selector: map[string]string{"app": "ns1"}})
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	// Same selector is not an overwrite
+	err = domains.CreateWithoutOverwrite(Domain{Name: "sub.example.com", Selector: map[string]string{"app": "ns1"}})
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	err = domains.CreateWithoutOverwrite(Domain{Name: "sub.example.com", Selector: map[string]string{"app": "ns2"}})
+	if err == nil || err.Error() != "Expected domain 'sub.example.com' to not be already used with a different selector" {
+		t.Fatalf("Expected error refusing to overwrite selector, but was: %v", err)
+	}
+
+	config, err := cluster.CoreClient().CoreV1().ConfigMaps("knative-serving").Get("config-domain", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	expectedData := map[string]string{"example.com": "", "sub.example.com": "selector:\n  app: ns1\n"}
+
+	if len(config.Data) != len(expectedData) {
+		t.Fatalf("Expected domains to be %#v, but was %#v", expectedData, config.Data)
+	}
+	for k, v := range expectedData {
+		if config.Data[k] != v {
+			t.Fatalf("Expected domains to be %#v, but was %#v", expectedData, config.Data)
+		}
+	}
+}
+
+func TestDomainsCreateOverwrites(t *testing.T) {
+	cluster := testkit.NewCluster(t, &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "config-domain", Namespace: "knative-serving"},
+		Data:       map[string]string{"example.com": ""},
+	})
+
+	err := NewDomains(cluster.CoreClient()).Create(Domain{Name: "example.com", Selector: map[string]string{"app": "ns1"}})
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	config, err := cluster.CoreClient().CoreV1().ConfigMaps("knative-serving").Get("config-domain", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+	if config.Data["example.com"] != "selector:\n  app: ns1\n" {
+		t.Fatalf("Expected domain to be overwritten, but was %#v", config.Data)
+	}
+}
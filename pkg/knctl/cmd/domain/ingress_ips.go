@@ -0,0 +1,55 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package domain
+
+import (
+	"net"
+
+	cmdcore "github.com/cppforlife/knctl/pkg/knctl/cmd/core"
+	ctling "github.com/cppforlife/knctl/pkg/knctl/ingress"
+)
+
+// IngressIPs returns IP addresses of ingress services
+func IngressIPs(depsFactory cmdcore.DepsFactory) ([]string, error) {
+	coreClient, err := depsFactory.CoreClient()
+	if err != nil {
+		return nil, err
+	}
+
+	dynamicClient, err := depsFactory.DynamicClient()
+	if err != nil {
+		return nil, err
+	}
+
+	ingSvcs, err := ctling.NewIngressServices(coreClient).WithDynamicClient(dynamicClient).List()
+	if err != nil {
+		return nil, err
+	}
+
+	var ips []string
+
+	for _, svc := range ingSvcs {
+		for _, addr := range svc.Addresses() {
+			// Hostname based load balancers (e.g. AWS ELB) cannot be compared against
+			if net.ParseIP(addr) != nil {
+				ips = append(ips, addr)
+			}
+		}
+	}
+
+	return ips, nil
+}
@@ -0,0 +1,136 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package domain
+
+import (
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/cppforlife/go-cli-ui/ui"
+	cmdcore "github.com/cppforlife/knctl/pkg/knctl/cmd/core"
+	"github.com/spf13/cobra"
+)
+
+var (
+	MagicDNSProviders = []string{"sslip.io", "nip.io"}
+)
+
+type UseMagicDNSOptions struct {
+	ui          ui.UI
+	depsFactory cmdcore.DepsFactory
+
+	IP            string
+	Provider      string
+	Verify        bool
+	VerifyTimeout time.Duration
+}
+
+func NewUseMagicDNSOptions(ui ui.UI, depsFactory cmdcore.DepsFactory) *UseMagicDNSOptions {
+	return &UseMagicDNSOptions{ui: ui, depsFactory: depsFactory}
+}
+
+func NewUseMagicDNSCmd(o *UseMagicDNSOptions, flagsFactory cmdcore.FlagsFactory) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "use-magic-dns",
+		Short: "Set default domain to magic DNS domain of ingress IP",
+		Long: `Set default domain to magic DNS domain of ingress IP (e.g. '1.2.3.4.sslip.io').
+
+Magic DNS providers resolve any subdomain of '<ip>.<provider>' to that IP,
+hence services become reachable without configuring DNS records.`,
+		Example: `
+  # Set default domain based on detected ingress IP
+  knctl domain use-magic-dns
+
+  # Set default domain to '1.2.3.4.nip.io'
+  knctl domain use-magic-dns --ip 1.2.3.4 --provider nip.io`,
+		RunE: func(_ *cobra.Command, _ []string) error { return o.Run() },
+	}
+	cmd.Flags().StringVar(&o.IP, "ip", "", "Set ingress IP (detected from ingress services by default)")
+	cmd.Flags().StringVar(&o.Provider, "provider", MagicDNSProviders[0], "Set magic DNS provider ("+strings.Join(MagicDNSProviders, " or ")+")")
+	cmd.Flags().BoolVar(&o.Verify, "verify", true, "Verify that domain resolves to ingress IP")
+	cmd.Flags().DurationVar(&o.VerifyTimeout, "verify-timeout", time.Minute, "Set maximum time to wait for domain to resolve")
+	return cmd
+}
+
+func (o *UseMagicDNSOptions) Run() error {
+	err := o.validateProvider()
+	if err != nil {
+		return err
+	}
+
+	ip, err := o.ip()
+	if err != nil {
+		return err
+	}
+
+	coreClient, err := o.depsFactory.CoreClient()
+	if err != nil {
+		return err
+	}
+
+	domain := ip + "." + o.Provider
+
+	err = NewDomains(coreClient).Create(Domain{Name: domain, Default: true})
+	if err != nil {
+		return err
+	}
+
+	o.ui.PrintLinef("Set default domain to '%s'", domain)
+
+	if !o.Verify {
+		return nil
+	}
+
+	// Any subdomain resolves to the same IP; check one that looks like a route's domain
+	return NewDNSPropagation(DefaultPublicResolvers, o.VerifyTimeout, o.ui).Wait("knctl-check."+domain, []string{ip})
+}
+
+func (o *UseMagicDNSOptions) validateProvider() error {
+	for _, provider := range MagicDNSProviders {
+		if o.Provider == provider {
+			return nil
+		}
+	}
+	return fmt.Errorf("Expected --provider to be one of: %s", strings.Join(MagicDNSProviders, ", "))
+}
+
+func (o *UseMagicDNSOptions) ip() (string, error) {
+	if len(o.IP) > 0 {
+		ip := net.ParseIP(o.IP)
+		if ip == nil || ip.To4() == nil {
+			return "", fmt.Errorf("Expected --ip '%s' to be a valid IPv4 address", o.IP)
+		}
+		return o.IP, nil
+	}
+
+	ips, err := IngressIPs(o.depsFactory)
+	if err != nil {
+		return "", err
+	}
+
+	for _, ip := range ips {
+		// Magic DNS providers only support IPv4 addresses in dotted form
+		if net.ParseIP(ip).To4() != nil {
+			o.ui.PrintLinef("Using ingress IP '%s'", ip)
+			return ip, nil
+		}
+	}
+
+	return "", fmt.Errorf("Expected to find ingress IPv4 address (use --ip to specify it explicitly)")
+}
@@ -0,0 +1,103 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package domain_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cppforlife/go-cli-ui/ui"
+	. "github.com/cppforlife/knctl/pkg/knctl/cmd"
+	cmdcore "github.com/cppforlife/knctl/pkg/knctl/cmd/core"
+	. "github.com/cppforlife/knctl/pkg/knctl/cmd/domain"
+	"github.com/cppforlife/knctl/pkg/knctl/testkit"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestNewUseMagicDNSCmd_Ok(t *testing.T) {
+	realCmd := NewUseMagicDNSOptions(nil, cmdcore.NewDepsFactory())
+	cmd := NewTestCmd(t, NewUseMagicDNSCmd(realCmd, cmdcore.FlagsFactory{}))
+	cmd.ExpectBasicConfig()
+	cmd.Execute([]string{
+		"--ip", "1.2.3.4",
+		"--provider", "nip.io",
+		"--verify=false",
+		"--verify-timeout", "2m",
+	})
+	cmd.ExpectReachesExecution()
+
+	DeepEqual(t, realCmd.IP, "1.2.3.4")
+	DeepEqual(t, realCmd.Provider, "nip.io")
+	DeepEqual(t, realCmd.Verify, false)
+	DeepEqual(t, realCmd.VerifyTimeout, 2*time.Minute)
+}
+
+func TestNewUseMagicDNSCmd_OkMinimum(t *testing.T) {
+	realCmd := NewUseMagicDNSOptions(nil, cmdcore.NewDepsFactory())
+	cmd := NewTestCmd(t, NewUseMagicDNSCmd(realCmd, cmdcore.FlagsFactory{}))
+	cmd.Execute([]string{})
+	cmd.ExpectReachesExecution()
+
+	DeepEqual(t, realCmd.IP, "")
+	DeepEqual(t, realCmd.Provider, "sslip.io")
+	DeepEqual(t, realCmd.Verify, true)
+	DeepEqual(t, realCmd.VerifyTimeout, time.Minute)
+}
+
+func TestUseMagicDNSOptions_SetsDefaultDomainFromIngressIP(t *testing.T) {
+	cluster := testkit.NewCluster(t,
+		testkit.NewLoadBalancerService("istio-system", "istio-ingressgateway",
+			map[string]string{"knative": "ingressgateway"}, "1.2.3.4", 80),
+		&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "config-domain", Namespace: "knative-serving"},
+			Data:       map[string]string{"example.com": ""},
+		},
+	)
+
+	opts := NewUseMagicDNSOptions(ui.NewNoopUI(), cluster.DepsFactory())
+	opts.Provider = "sslip.io"
+
+	err := opts.Run()
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	config, err := cluster.CoreClient().CoreV1().ConfigMaps("knative-serving").Get("config-domain", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	DeepEqual(t, config.Data, map[string]string{"1.2.3.4.sslip.io": ""})
+}
+
+func TestUseMagicDNSOptions_ValidatesFlags(t *testing.T) {
+	opts := NewUseMagicDNSOptions(ui.NewNoopUI(), testkit.NewCluster(t).DepsFactory())
+
+	opts.Provider = "xip.io"
+	err := opts.Run()
+	if err == nil || err.Error() != "Expected --provider to be one of: sslip.io, nip.io" {
+		t.Fatalf("Expected provider error, but was: %v", err)
+	}
+
+	opts.Provider = "nip.io"
+	opts.IP = "::1"
+	err = opts.Run()
+	if err == nil || err.Error() != "Expected --ip '::1' to be a valid IPv4 address" {
+		t.Fatalf("Expected IP error, but was: %v", err)
+	}
+}
@@ -0,0 +1,93 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package domain
+
+import (
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/cppforlife/go-cli-ui/ui"
+	cmdcore "github.com/cppforlife/knctl/pkg/knctl/cmd/core"
+	"github.com/spf13/cobra"
+)
+
+type WaitOptions struct {
+	ui          ui.UI
+	depsFactory cmdcore.DepsFactory
+
+	Domain    string
+	ExpectIPs []string
+	Resolvers []string
+	Timeout   time.Duration
+}
+
+func NewWaitOptions(ui ui.UI, depsFactory cmdcore.DepsFactory) *WaitOptions {
+	return &WaitOptions{ui: ui, depsFactory: depsFactory}
+}
+
+func NewWaitCmd(o *WaitOptions, flagsFactory cmdcore.FlagsFactory) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "wait",
+		Short: "Wait for domain to resolve to ingress IP",
+		Long: `Wait for domain to resolve to ingress IP via public DNS resolvers.
+
+Useful after changing DNS records or domain mappings. When --expect-ip is not specified,
+addresses of ingress services are used.`,
+		Example: `
+  # Wait for domain 'api.example.com' to point to ingress
+  knctl domain wait -d api.example.com
+
+  # Wait for domain 'api.example.com' to point to 1.2.3.4 for up to 15 minutes
+  knctl domain wait -d api.example.com --expect-ip 1.2.3.4 --timeout 15m`,
+		RunE: func(_ *cobra.Command, _ []string) error { return o.Run() },
+	}
+
+	cmd.Flags().StringVarP(&o.Domain, "domain", "d", "", "Specified domain (example: api.domain.com)")
+	cmd.MarkFlagRequired("domain")
+
+	cmd.Flags().StringSliceVar(&o.ExpectIPs, "expect-ip", nil, "Set expected IP (can be specified multiple times) (defaults to ingress addresses)")
+	cmd.Flags().StringSliceVar(&o.Resolvers, "resolver", DefaultPublicResolvers, "Set DNS resolver to query (can be specified multiple times)")
+	cmd.Flags().DurationVar(&o.Timeout, "timeout", 15*time.Minute, "Set maximum time to wait for domain to resolve")
+
+	return cmd
+}
+
+func (o *WaitOptions) Run() error {
+	for _, ip := range o.ExpectIPs {
+		if net.ParseIP(ip) == nil {
+			return fmt.Errorf("Expected --expect-ip '%s' to be a valid IP", ip)
+		}
+	}
+
+	expectedIPs := o.ExpectIPs
+
+	if len(expectedIPs) == 0 {
+		ips, err := IngressIPs(o.depsFactory)
+		if err != nil {
+			return err
+		}
+
+		if len(ips) == 0 {
+			return fmt.Errorf("Expected to find ingress IP (use --expect-ip to specify it explicitly)")
+		}
+
+		expectedIPs = ips
+	}
+
+	return NewDNSPropagation(o.Resolvers, o.Timeout, o.ui).Wait(o.Domain, expectedIPs)
+}
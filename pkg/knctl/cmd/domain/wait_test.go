@@ -0,0 +1,63 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package domain_test
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/cppforlife/knctl/pkg/knctl/cmd"
+	cmdcore "github.com/cppforlife/knctl/pkg/knctl/cmd/core"
+	. "github.com/cppforlife/knctl/pkg/knctl/cmd/domain"
+)
+
+func TestNewWaitCmd_Ok(t *testing.T) {
+	realCmd := NewWaitOptions(nil, cmdcore.NewDepsFactory())
+	cmd := NewTestCmd(t, NewWaitCmd(realCmd, cmdcore.FlagsFactory{}))
+	cmd.ExpectBasicConfig()
+	cmd.Execute([]string{
+		"-d", "api.example.com",
+		"--expect-ip", "1.2.3.4",
+		"--expect-ip", "1.2.3.5",
+		"--resolver", "8.8.4.4",
+		"--timeout", "1m",
+	})
+	cmd.ExpectReachesExecution()
+
+	DeepEqual(t, realCmd.Domain, "api.example.com")
+	DeepEqual(t, realCmd.ExpectIPs, []string{"1.2.3.4", "1.2.3.5"})
+	DeepEqual(t, realCmd.Resolvers, []string{"8.8.4.4"})
+	DeepEqual(t, realCmd.Timeout, time.Minute)
+}
+
+func TestNewWaitCmd_OkMinimum(t *testing.T) {
+	realCmd := NewWaitOptions(nil, cmdcore.NewDepsFactory())
+	cmd := NewTestCmd(t, NewWaitCmd(realCmd, cmdcore.FlagsFactory{}))
+	cmd.Execute([]string{"-d", "api.example.com"})
+	cmd.ExpectReachesExecution()
+
+	DeepEqual(t, realCmd.ExpectIPs, []string(nil))
+	DeepEqual(t, realCmd.Resolvers, []string{"8.8.8.8", "1.1.1.1", "9.9.9.9"})
+	DeepEqual(t, realCmd.Timeout, 15*time.Minute)
+}
+
+func TestNewWaitCmd_RequiredFlags(t *testing.T) {
+	realCmd := NewWaitOptions(nil, cmdcore.NewDepsFactory())
+	cmd := NewTestCmd(t, NewWaitCmd(realCmd, cmdcore.FlagsFactory{}))
+	cmd.Execute([]string{})
+	cmd.ExpectRequiredFlags([]string{"domain"})
+}
@@ -0,0 +1,99 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package egress
+
+import (
+	"fmt"
+
+	"github.com/cppforlife/go-cli-ui/ui"
+	cmdcore "github.com/cppforlife/knctl/pkg/knctl/cmd/core"
+	cmdflags "github.com/cppforlife/knctl/pkg/knctl/cmd/flags"
+	"github.com/spf13/cobra"
+)
+
+type AllowOptions struct {
+	ui          ui.UI
+	depsFactory cmdcore.DepsFactory
+
+	ServiceFlags cmdflags.ServiceFlags
+	Host         string
+	Port         int
+	Protocol     string
+}
+
+func NewAllowOptions(ui ui.UI, depsFactory cmdcore.DepsFactory) *AllowOptions {
+	return &AllowOptions{ui: ui, depsFactory: depsFactory}
+}
+
+func NewAllowCmd(o *AllowOptions, flagsFactory cmdcore.FlagsFactory) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "allow",
+		Short: "Allow service to reach external host",
+		Long: `Allow service to reach external host.
+
+Creates Istio service entry for the host, required when Istio's outbound traffic policy
+only allows registered hosts (REGISTRY_ONLY). Sidecar resources in the namespace that
+restrict egress hosts are updated to include the host.
+
+Service entry is visible to all services in the namespace.`,
+		Example: `
+  # Allow service 'svc1' to call 'api.stripe.com' over HTTPS in namespace 'ns1'
+  knctl egress allow -s svc1 --host api.stripe.com --port 443 -n ns1`,
+		RunE: func(_ *cobra.Command, _ []string) error { return o.Run() },
+	}
+	o.ServiceFlags.Set(cmd, flagsFactory)
+	cmd.Flags().StringVar(&o.Host, "host", "", "Set external host (e.g. api.stripe.com)")
+	cmd.Flags().IntVar(&o.Port, "port", 443, "Set external port")
+	cmd.Flags().StringVar(&o.Protocol, "protocol", "", "Set port protocol (e.g. HTTP, HTTPS, TLS, TCP; default: based on port)")
+	return cmd
+}
+
+func (o *AllowOptions) Run() error {
+	if len(o.Host) == 0 {
+		return fmt.Errorf("Expected host to be specified via --host")
+	}
+	if o.Port < 1 || o.Port > 65535 {
+		return fmt.Errorf("Expected port to be between 1 and 65535")
+	}
+
+	dynamicClient, err := o.depsFactory.DynamicClient()
+	if err != nil {
+		return err
+	}
+
+	rule := EgressRule{
+		Namespace: o.ServiceFlags.NamespaceFlags.Name,
+		Service:   o.ServiceFlags.Name,
+		Host:      o.Host,
+		Port:      o.Port,
+		Protocol:  o.Protocol,
+	}
+
+	sidecarNames, err := NewEgressRules(dynamicClient).Allow(rule)
+	if err != nil {
+		return err
+	}
+
+	o.ui.PrintLinef("Allowed egress to '%s:%d' (%s) via service entry '%s'",
+		rule.Host, rule.Port, rule.PortProtocol(), rule.Name())
+
+	for _, name := range sidecarNames {
+		o.ui.PrintLinef("Updated sidecar '%s' to include host '%s'", name, rule.Host)
+	}
+
+	return nil
+}
@@ -0,0 +1,147 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package egress_test
+
+import (
+	"testing"
+
+	"github.com/cppforlife/go-cli-ui/ui"
+	. "github.com/cppforlife/knctl/pkg/knctl/cmd"
+	cmdcore "github.com/cppforlife/knctl/pkg/knctl/cmd/core"
+	. "github.com/cppforlife/knctl/pkg/knctl/cmd/egress"
+	cmdflags "github.com/cppforlife/knctl/pkg/knctl/cmd/flags"
+	"github.com/cppforlife/knctl/pkg/knctl/testkit"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestNewAllowCmd_Ok(t *testing.T) {
+	realCmd := NewAllowOptions(nil, cmdcore.NewDepsFactory())
+	cmd := NewTestCmd(t, NewAllowCmd(realCmd, cmdcore.FlagsFactory{}))
+	cmd.ExpectBasicConfig()
+	cmd.Execute([]string{
+		"-n", "test-namespace",
+		"-s", "test-service",
+		"--host", "api.stripe.com",
+		"--port", "8443",
+		"--protocol", "https",
+	})
+	cmd.ExpectReachesExecution()
+
+	DeepEqual(t, realCmd.ServiceFlags,
+		cmdflags.ServiceFlags{cmdcore.NamespaceFlags{"test-namespace"}, "test-service"})
+	DeepEqual(t, realCmd.Host, "api.stripe.com")
+	DeepEqual(t, realCmd.Port, 8443)
+	DeepEqual(t, realCmd.Protocol, "https")
+}
+
+func TestNewAllowCmd_RequiredFlags(t *testing.T) {
+	realCmd := NewAllowOptions(nil, cmdcore.NewDepsFactory())
+	cmd := NewTestCmd(t, NewAllowCmd(realCmd, cmdcore.FlagsFactory{}))
+	cmd.Execute([]string{})
+	cmd.ExpectRequiredFlags([]string{"service"})
+}
+
+func TestEgressRule_PortProtocol(t *testing.T) {
+	DeepEqual(t, EgressRule{Port: 443}.PortProtocol(), "TLS")
+	DeepEqual(t, EgressRule{Port: 80}.PortProtocol(), "HTTP")
+	DeepEqual(t, EgressRule{Port: 5432}.PortProtocol(), "TCP")
+	DeepEqual(t, EgressRule{Port: 443, Protocol: "https"}.PortProtocol(), "HTTPS")
+}
+
+func TestAllowOptions_CreatesServiceEntryAndUpdatesSidecars(t *testing.T) {
+	restrictedSidecar := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "networking.istio.io/v1alpha3",
+		"kind":       "Sidecar",
+		"metadata":   map[string]interface{}{"name": "restricted", "namespace": "ns1"},
+		"spec": map[string]interface{}{
+			"egress": []interface{}{
+				map[string]interface{}{"hosts": []interface{}{"istio-system/*"}},
+			},
+		},
+	}}
+
+	openSidecar := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "networking.istio.io/v1alpha3",
+		"kind":       "Sidecar",
+		"metadata":   map[string]interface{}{"name": "open", "namespace": "ns1"},
+		"spec": map[string]interface{}{
+			"egress": []interface{}{
+				map[string]interface{}{"hosts": []interface{}{"./*"}},
+			},
+		},
+	}}
+
+	cluster := testkit.NewCluster(t, restrictedSidecar, openSidecar)
+
+	opts := NewAllowOptions(ui.NewNoopUI(), cluster.DepsFactory())
+	opts.ServiceFlags = cmdflags.ServiceFlags{cmdcore.NamespaceFlags{"ns1"}, "svc1"}
+	opts.Host = "api.stripe.com"
+	opts.Port = 443
+
+	// Running twice updates existing resources
+	for i := 0; i < 2; i++ {
+		err := opts.Run()
+		if err != nil {
+			t.Fatalf("Expected no error: %s", err)
+		}
+	}
+
+	dynamicClient, err := cluster.DepsFactory().DynamicClient()
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	serviceEntry, err := dynamicClient.Resource(schema.GroupVersionResource{
+		Group: "networking.istio.io", Version: "v1alpha3", Resource: "serviceentries",
+	}).Namespace("ns1").Get("svc1-egress-api-stripe-com-443", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	DeepEqual(t, serviceEntry.Object["spec"], map[string]interface{}{
+		"hosts": []interface{}{"api.stripe.com"},
+		"ports": []interface{}{
+			map[string]interface{}{"number": int64(443), "name": "tls-443", "protocol": "TLS"},
+		},
+		"location":   "MESH_EXTERNAL",
+		"resolution": "DNS",
+	})
+
+	sidecars := dynamicClient.Resource(schema.GroupVersionResource{
+		Group: "networking.istio.io", Version: "v1alpha3", Resource: "sidecars",
+	}).Namespace("ns1")
+
+	sidecar, err := sidecars.Get("restricted", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	DeepEqual(t, sidecar.Object["spec"], map[string]interface{}{
+		"egress": []interface{}{
+			map[string]interface{}{"hosts": []interface{}{"istio-system/*", "./api.stripe.com"}},
+		},
+	})
+
+	sidecar, err = sidecars.Get("open", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	DeepEqual(t, sidecar.Object["spec"], openSidecar.Object["spec"])
+}
@@ -0,0 +1,234 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package egress
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+)
+
+const (
+	egressServiceLabelKey = "egress.cli.knative.dev/service"
+)
+
+var (
+	serviceEntryGVR = schema.GroupVersionResource{
+		Group:    "networking.istio.io",
+		Version:  "v1alpha3",
+		Resource: "serviceentries",
+	}
+	sidecarGVR = schema.GroupVersionResource{
+		Group:    "networking.istio.io",
+		Version:  "v1alpha3",
+		Resource: "sidecars",
+	}
+
+	invalidNameCharsRegexp = regexp.MustCompile("[^a-z0-9-]+")
+)
+
+// EgressRule allows service to reach an external host
+// when Istio only allows traffic to registered hosts (REGISTRY_ONLY)
+type EgressRule struct {
+	Namespace string
+	Service   string
+	Host      string
+	Port      int
+	Protocol  string
+}
+
+func (r EgressRule) Name() string {
+	host := invalidNameCharsRegexp.ReplaceAllString(strings.ToLower(r.Host), "-")
+	return strings.Trim(fmt.Sprintf("%s-egress-%s-%d", r.Service, host, r.Port), "-")
+}
+
+// PortProtocol returns explicitly specified protocol
+// or guesses protocol based on commonly used ports
+func (r EgressRule) PortProtocol() string {
+	if len(r.Protocol) > 0 {
+		return strings.ToUpper(r.Protocol)
+	}
+	switch r.Port {
+	case 80:
+		return "HTTP"
+	case 443:
+		return "TLS"
+	default:
+		return "TCP"
+	}
+}
+
+func (r EgressRule) ServiceEntry() *unstructured.Unstructured {
+	protocol := r.PortProtocol()
+
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "networking.istio.io/v1alpha3",
+		"kind":       "ServiceEntry",
+		"metadata": map[string]interface{}{
+			"name":      r.Name(),
+			"namespace": r.Namespace,
+			"labels": map[string]interface{}{
+				egressServiceLabelKey: r.Service,
+			},
+		},
+		"spec": map[string]interface{}{
+			"hosts": []interface{}{r.Host},
+			"ports": []interface{}{
+				map[string]interface{}{
+					"number":   int64(r.Port),
+					"name":     fmt.Sprintf("%s-%d", strings.ToLower(protocol), r.Port),
+					"protocol": protocol,
+				},
+			},
+			"location":   "MESH_EXTERNAL",
+			"resolution": "DNS",
+		},
+	}}
+}
+
+type EgressRules struct {
+	dynamicClient dynamic.Interface
+}
+
+func NewEgressRules(dynamicClient dynamic.Interface) EgressRules {
+	return EgressRules{dynamicClient}
+}
+
+// Allow creates or updates service entry for the external host and makes
+// sure that sidecar resources restricting egress in the namespace include it
+func (r EgressRules) Allow(rule EgressRule) ([]string, error) {
+	serviceEntries := r.dynamicClient.Resource(serviceEntryGVR).Namespace(rule.Namespace)
+	serviceEntry := rule.ServiceEntry()
+
+	_, err := serviceEntries.Create(serviceEntry)
+	if err != nil {
+		if !errors.IsAlreadyExists(err) {
+			return nil, fmt.Errorf("Creating service entry '%s': %s", serviceEntry.GetName(), err)
+		}
+
+		existing, err := serviceEntries.Get(serviceEntry.GetName(), metav1.GetOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("Getting service entry '%s': %s", serviceEntry.GetName(), err)
+		}
+
+		existing.SetLabels(serviceEntry.GetLabels())
+		existing.Object["spec"] = serviceEntry.Object["spec"]
+
+		_, err = serviceEntries.Update(existing)
+		if err != nil {
+			return nil, fmt.Errorf("Updating service entry '%s': %s", serviceEntry.GetName(), err)
+		}
+	}
+
+	return r.allowInSidecars(rule)
+}
+
+func (r EgressRules) allowInSidecars(rule EgressRule) ([]string, error) {
+	sidecars := r.dynamicClient.Resource(sidecarGVR).Namespace(rule.Namespace)
+
+	sidecarList, err := sidecars.List(metav1.ListOptions{})
+	if err != nil {
+		if errors.IsNotFound(err) {
+			// Sidecar resource is not available in older Istio versions
+			return nil, nil
+		}
+		return nil, fmt.Errorf("Listing sidecars: %s", err)
+	}
+
+	// Service entry is made visible in its own namespace
+	sidecarHost := "./" + rule.Host
+
+	var updatedNames []string
+
+	for _, sidecar := range sidecarList.Items {
+		egresses, found, err := unstructured.NestedSlice(sidecar.Object, "spec", "egress")
+		if err != nil {
+			return nil, fmt.Errorf("Reading sidecar '%s': %s", sidecar.GetName(), err)
+		}
+		if !found {
+			continue
+		}
+
+		var updated bool
+
+		for i, egress := range egresses {
+			typedEgress, ok := egress.(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf("Expected sidecar '%s' egress to be a map", sidecar.GetName())
+			}
+
+			hosts, _, err := unstructured.NestedStringSlice(typedEgress, "hosts")
+			if err != nil {
+				return nil, fmt.Errorf("Reading sidecar '%s': %s", sidecar.GetName(), err)
+			}
+
+			if r.includesHost(hosts, rule.Host) {
+				continue
+			}
+
+			var untypedHosts []interface{}
+			for _, host := range append(hosts, sidecarHost) {
+				untypedHosts = append(untypedHosts, host)
+			}
+
+			typedEgress["hosts"] = untypedHosts
+			egresses[i] = typedEgress
+			updated = true
+		}
+
+		if !updated {
+			continue
+		}
+
+		err = unstructured.SetNestedSlice(sidecar.Object, egresses, "spec", "egress")
+		if err != nil {
+			return nil, err
+		}
+
+		_, err = sidecars.Update(&sidecar)
+		if err != nil {
+			return nil, fmt.Errorf("Updating sidecar '%s': %s", sidecar.GetName(), err)
+		}
+
+		updatedNames = append(updatedNames, sidecar.GetName())
+	}
+
+	return updatedNames, nil
+}
+
+func (EgressRules) includesHost(hosts []string, host string) bool {
+	for _, h := range hosts {
+		pieces := strings.SplitN(h, "/", 2)
+		if len(pieces) != 2 {
+			continue
+		}
+		if pieces[0] != "." && pieces[0] != "*" {
+			// Hosts in other namespaces do not cover service entry in this namespace
+			continue
+		}
+		if pieces[1] == "*" || pieces[1] == host {
+			return true
+		}
+	}
+	return false
+}
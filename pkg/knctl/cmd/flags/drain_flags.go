@@ -0,0 +1,34 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package flags
+
+import (
+	"time"
+
+	cmdcore "github.com/cppforlife/knctl/pkg/knctl/cmd/core"
+	"github.com/spf13/cobra"
+)
+
+type DrainFlags struct {
+	Drain   bool
+	Timeout time.Duration
+}
+
+func (s *DrainFlags) Set(cmd *cobra.Command, flagsFactory cmdcore.FlagsFactory) {
+	cmd.Flags().BoolVar(&s.Drain, "drain", false, "Remove traffic and wait for in-flight requests to finish before deleting")
+	cmd.Flags().DurationVar(&s.Timeout, "drain-timeout", 5*time.Minute, "Set maximum time to wait for traffic to drain")
+}
@@ -0,0 +1,53 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package flags
+
+import (
+	cmdcore "github.com/cppforlife/knctl/pkg/knctl/cmd/core"
+	ctlhistory "github.com/cppforlife/knctl/pkg/knctl/history"
+	"github.com/cppforlife/knctl/pkg/knctl/notify"
+	"github.com/spf13/cobra"
+)
+
+type NotifyFlags struct {
+	URLs          []string
+	SlackWebhooks []string
+}
+
+func (s *NotifyFlags) Set(cmd *cobra.Command, flagsFactory cmdcore.FlagsFactory) {
+	cmd.Flags().StringArrayVar(&s.URLs, "notify-url", nil,
+		"Set URL to POST JSON summary of the result to (can be specified multiple times)")
+	cmd.Flags().StringArrayVar(&s.SlackWebhooks, "notify-slack-webhook", nil,
+		"Set Slack incoming webhook URL to post summary of the result to (can be specified multiple times)")
+}
+
+// Notify sends summary of the operation; failures to notify do not
+// affect operation's outcome, hence they are returned as warnings
+func (s *NotifyFlags) Notify(depsFactory cmdcore.DepsFactory, entry ctlhistory.Entry, namespace string, opErr error) error {
+	if len(s.URLs) == 0 && len(s.SlackWebhooks) == 0 {
+		return nil
+	}
+
+	httpClient, err := depsFactory.HTTPClient()
+	if err != nil {
+		return err
+	}
+
+	summary := notify.NewSummary(entry, namespace, opErr)
+
+	return notify.NewNotifier(httpClient, s.URLs, s.SlackWebhooks).Notify(summary)
+}
@@ -0,0 +1,81 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package flags
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+
+	"github.com/cppforlife/go-cli-ui/ui"
+	cmdcore "github.com/cppforlife/knctl/pkg/knctl/cmd/core"
+	"github.com/cppforlife/knctl/pkg/knctl/registry"
+	"github.com/cppforlife/knctl/pkg/knctl/scan"
+	"github.com/spf13/cobra"
+)
+
+type ScanFlags struct {
+	Scan       bool
+	ScannerURL string
+	FailOn     string
+}
+
+func (s *ScanFlags) Set(cmd *cobra.Command, flagsFactory cmdcore.FlagsFactory) {
+	cmd.Flags().BoolVar(&s.Scan, "scan", false, "Scan image for vulnerabilities before deploying")
+	cmd.Flags().StringVar(&s.ScannerURL, "scanner-url", os.Getenv("KNCTL_SCANNER_URL"),
+		"Set URL of scanner returning Trivy JSON report for 'image' query param ($KNCTL_SCANNER_URL)")
+	cmd.Flags().StringVar(&s.FailOn, "fail-on", "",
+		"Fail if vulnerabilities of given or higher severity are found (unknown, low, medium, high, critical)")
+}
+
+// ScanImage prints vulnerability summary for image resolved to digest
+func (s *ScanFlags) ScanImage(ui ui.UI, httpClient *http.Client, ref registry.Reference) error {
+	if len(s.ScannerURL) == 0 {
+		return fmt.Errorf("Expected scanner URL to be specified via --scanner-url or $KNCTL_SCANNER_URL")
+	}
+
+	var failOn scan.Severity
+
+	if len(s.FailOn) > 0 {
+		var err error
+
+		failOn, err = scan.ParseSeverity(s.FailOn)
+		if err != nil {
+			return err
+		}
+	}
+
+	digestImage := ref.String()
+
+	ui.PrintLinef("Scanning image '%s'", digestImage)
+
+	report, err := scan.NewScanner(httpClient, s.ScannerURL).Scan(digestImage)
+	if err != nil {
+		return err
+	}
+
+	ui.PrintLinef("Vulnerabilities: %s", report.Summary())
+
+	if len(failOn) > 0 {
+		if vulns := report.AtLeast(failOn); len(vulns) > 0 {
+			return fmt.Errorf("Expected image to have no vulnerabilities of severity '%s' or higher, but found %d",
+				failOn, len(vulns))
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,48 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package flags
+
+import (
+	"fmt"
+	"strings"
+
+	cmdcore "github.com/cppforlife/knctl/pkg/knctl/cmd/core"
+	"github.com/spf13/cobra"
+)
+
+type SelectorFlags struct {
+	Selector string
+}
+
+func (s *SelectorFlags) Set(cmd *cobra.Command, flagsFactory cmdcore.FlagsFactory) {
+	cmd.Flags().StringVar(&s.Selector, "selector", "", "Apply to all services matching label selector (example: team=payments)")
+}
+
+// ValidateWithName checks that exactly one of name or selector was specified;
+// blank selector is rejected since it would match everything
+func (s SelectorFlags) ValidateWithName(name, nameFlag string) error {
+	switch {
+	case len(s.Selector) > 0 && len(strings.TrimSpace(s.Selector)) == 0:
+		return fmt.Errorf("Expected --selector to not be blank")
+	case len(name) > 0 && len(s.Selector) > 0:
+		return fmt.Errorf("Expected only one of --%s or --selector to be specified", nameFlag)
+	case len(name) == 0 && len(s.Selector) == 0:
+		return fmt.Errorf("Expected either --%s or --selector to be specified", nameFlag)
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,68 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package flags
+
+import (
+	"fmt"
+	"io/ioutil"
+
+	"github.com/cppforlife/go-cli-ui/ui"
+	cmdcore "github.com/cppforlife/knctl/pkg/knctl/cmd/core"
+	"github.com/cppforlife/knctl/pkg/knctl/registry"
+	"github.com/cppforlife/knctl/pkg/knctl/signature"
+	"github.com/spf13/cobra"
+)
+
+type SignatureFlags struct {
+	VerifySignature bool
+	CosignKey       string
+}
+
+func (s *SignatureFlags) Set(cmd *cobra.Command, flagsFactory cmdcore.FlagsFactory) {
+	cmd.Flags().BoolVar(&s.VerifySignature, "verify-signature", false, "Verify image signature before deploying")
+	cmd.Flags().StringVar(&s.CosignKey, "cosign-key", "", "Set path to cosign public key used to verify image signature")
+}
+
+// VerifyImage checks signature of image resolved to digest
+func (s *SignatureFlags) VerifyImage(ui ui.UI, registryClient registry.Client, ref registry.Reference) error {
+	if len(s.CosignKey) == 0 {
+		// TODO keyless verification (Fulcio certificate identity and Rekor inclusion)
+		return fmt.Errorf("Expected cosign public key to be specified via --cosign-key " +
+			"(keyless verification is not supported)")
+	}
+
+	keyBytes, err := ioutil.ReadFile(s.CosignKey)
+	if err != nil {
+		return fmt.Errorf("Reading cosign key: %s", err)
+	}
+
+	verifier, err := signature.NewCosignVerifier(registryClient, keyBytes)
+	if err != nil {
+		return err
+	}
+
+	ui.PrintLinef("Verifying signature of image '%s'", ref)
+
+	err = verifier.Verify(ref)
+	if err != nil {
+		return err
+	}
+
+	ui.PrintLinef("Signature verified")
+
+	return nil
+}
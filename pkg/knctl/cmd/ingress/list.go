@@ -40,7 +40,7 @@ func NewListCmd(o *ListOptions, flagsFactory cmdcore.FlagsFactory) *cobra.Comman
 		Use:     "list",
 		Aliases: cmdcore.ListAliases,
 		Short:   "List ingresses",
-		Long:    "List all ingresses labeled as `knative: ingressgateway` in Istio's namespace",
+		Long:    "List all ingresses labeled as `knative: ingressgateway` in Istio's namespace, Kourier ingresses and Gateway API gateways",
 		RunE:    func(_ *cobra.Command, _ []string) error { return o.Run() },
 	}
 	return cmd
@@ -52,7 +52,12 @@ func (o *ListOptions) Run() error {
 		return err
 	}
 
-	ingSvcs, err := ctling.NewIngressServices(coreClient).List()
+	dynamicClient, err := o.depsFactory.DynamicClient()
+	if err != nil {
+		return err
+	}
+
+	ingSvcs, err := ctling.NewIngressServices(coreClient).WithDynamicClient(dynamicClient).List()
 	if err != nil {
 		return err
 	}
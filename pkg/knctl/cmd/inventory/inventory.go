@@ -0,0 +1,116 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package inventory
+
+import (
+	"github.com/cppforlife/go-cli-ui/ui"
+	uitable "github.com/cppforlife/go-cli-ui/ui/table"
+	cmdcore "github.com/cppforlife/knctl/pkg/knctl/cmd/core"
+	ctlkube "github.com/cppforlife/knctl/pkg/knctl/kube"
+	"github.com/spf13/cobra"
+)
+
+type InventoryOptions struct {
+	ui          ui.UI
+	depsFactory cmdcore.DepsFactory
+
+	Orphans bool
+}
+
+func NewInventoryOptions(ui ui.UI, depsFactory cmdcore.DepsFactory) *InventoryOptions {
+	return &InventoryOptions{ui: ui, depsFactory: depsFactory}
+}
+
+func NewInventoryCmd(o *InventoryOptions, flagsFactory cmdcore.FlagsFactory) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "inventory",
+		Short: "List resources created by knctl",
+		Long: `List resources created by knctl across all namespaces.
+
+Resources are found by '` + ctlkube.ManagedByLabelKey + `' label set on every object created by knctl.
+Resources that belong to a service that no longer exists are marked as orphaned.`,
+		Example: `
+  # List all resources created by knctl
+  knctl inventory
+
+  # List resources whose parent service is gone
+  knctl inventory --orphans`,
+		Annotations: map[string]string{
+			cmdcore.OtherHelpGroup.Key: cmdcore.OtherHelpGroup.Value,
+		},
+		RunE: func(_ *cobra.Command, _ []string) error { return o.Run() },
+	}
+	cmd.Flags().BoolVar(&o.Orphans, "orphans", false, "List only resources whose parent service no longer exists")
+	return cmd
+}
+
+func (o *InventoryOptions) Run() error {
+	discoveryClient, err := o.depsFactory.DiscoveryClient()
+	if err != nil {
+		return err
+	}
+
+	dynamicClient, err := o.depsFactory.DynamicClient()
+	if err != nil {
+		return err
+	}
+
+	resources, err := ListableResources(discoveryClient)
+	if err != nil {
+		return err
+	}
+
+	managedResources, err := NewManagedResources(dynamicClient).List(resources)
+	if err != nil {
+		return err
+	}
+
+	table := uitable.Table{
+		Title:   "Resources created by knctl",
+		Content: "resources",
+
+		Header: []uitable.Header{
+			uitable.NewHeader("Namespace"),
+			uitable.NewHeader("Kind"),
+			uitable.NewHeader("Name"),
+			uitable.NewHeader("Command"),
+			uitable.NewHeader("Service"),
+			uitable.NewHeader("Orphaned"),
+			uitable.NewHeader("Age"),
+		},
+	}
+
+	for _, res := range managedResources {
+		if o.Orphans && !res.Orphaned {
+			continue
+		}
+
+		table.Rows = append(table.Rows, []uitable.Value{
+			uitable.NewValueString(res.Namespace),
+			uitable.NewValueString(res.Kind),
+			uitable.NewValueString(res.Name),
+			uitable.NewValueString(res.Command),
+			uitable.NewValueString(res.ParentService),
+			uitable.NewValueBool(res.Orphaned),
+			cmdcore.NewValueAge(res.CreatedAt),
+		})
+	}
+
+	o.ui.PrintTable(table)
+
+	return nil
+}
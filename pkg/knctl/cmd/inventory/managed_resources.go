@@ -0,0 +1,193 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package inventory
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	cmdcore "github.com/cppforlife/knctl/pkg/knctl/cmd/core"
+	ctlkube "github.com/cppforlife/knctl/pkg/knctl/kube"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/dynamic"
+)
+
+var (
+	servicesGVR = schema.GroupVersionResource{Group: "serving.knative.dev", Version: "v1alpha1", Resource: "services"}
+
+	// parentServiceLabelKeys point from resources to Knative service they belong to
+	parentServiceLabelKeys = []string{
+		"serving.knative.dev/service",
+		"cli.knative.dev/history-service",
+		"scale.cli.knative.dev/service",
+		"limits.cli.knative.dev/service",
+		"egress.cli.knative.dev/service",
+	}
+)
+
+type ManagedResource struct {
+	Resource  schema.GroupVersionResource
+	Kind      string
+	Namespace string
+	Name      string
+	Command   string
+	CreatedAt time.Time
+
+	// ParentService is empty for resources that do not belong to a service
+	ParentService string
+	Orphaned      bool
+}
+
+type ManagedResources struct {
+	dynamicClient dynamic.Interface
+}
+
+func NewManagedResources(dynamicClient dynamic.Interface) ManagedResources {
+	return ManagedResources{dynamicClient}
+}
+
+// List returns resources labeled as created by knctl across all namespaces
+func (r ManagedResources) List(resources []schema.GroupVersionResource) ([]ManagedResource, error) {
+	listOpts := metav1.ListOptions{
+		LabelSelector: ctlkube.ManagedByLabelKey + "=" + ctlkube.ManagedByLabelValue,
+	}
+
+	var result []ManagedResource
+
+	for _, res := range resources {
+		list, err := r.dynamicClient.Resource(res).List(listOpts)
+		if err != nil {
+			// Resources that cannot be listed by user are not part of inventory
+			if errors.IsNotFound(err) || errors.IsForbidden(err) || errors.IsMethodNotSupported(err) {
+				continue
+			}
+			return nil, fmt.Errorf("Listing %s: %s", res.GroupResource(), err)
+		}
+
+		for _, item := range list.Items {
+			labels := item.GetLabels()
+
+			managedRes := ManagedResource{
+				Resource:  res,
+				Kind:      item.GetKind(),
+				Namespace: item.GetNamespace(),
+				Name:      item.GetName(),
+				Command:   labels[cmdcore.CommandLabelKey],
+				CreatedAt: item.GetCreationTimestamp().Time,
+			}
+
+			for _, key := range parentServiceLabelKeys {
+				if val, found := labels[key]; found {
+					managedRes.ParentService = val
+					break
+				}
+			}
+
+			result = append(result, managedRes)
+		}
+	}
+
+	err := r.markOrphans(result)
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Namespace != result[j].Namespace {
+			return result[i].Namespace < result[j].Namespace
+		}
+		if result[i].Kind != result[j].Kind {
+			return result[i].Kind < result[j].Kind
+		}
+		return result[i].Name < result[j].Name
+	})
+
+	return result, nil
+}
+
+func (r ManagedResources) markOrphans(resources []ManagedResource) error {
+	existingServices := map[string]bool{}
+
+	for i, res := range resources {
+		if len(res.ParentService) == 0 {
+			continue
+		}
+
+		key := res.Namespace + "/" + res.ParentService
+
+		exists, found := existingServices[key]
+		if !found {
+			_, err := r.dynamicClient.Resource(servicesGVR).Namespace(res.Namespace).Get(res.ParentService, metav1.GetOptions{})
+			if err != nil {
+				if !errors.IsNotFound(err) {
+					return fmt.Errorf("Getting service '%s': %s", key, err)
+				}
+			}
+
+			exists = err == nil
+			existingServices[key] = exists
+		}
+
+		resources[i].Orphaned = !exists
+	}
+
+	return nil
+}
+
+// ListableResources returns all resources served by the cluster that could be listed
+func ListableResources(discoveryClient discovery.DiscoveryInterface) ([]schema.GroupVersionResource, error) {
+	resLists, err := discoveryClient.ServerPreferredResources()
+	if err != nil {
+		// Unavailable API groups (e.g. broken aggregated APIs) are skipped
+		if !discovery.IsGroupDiscoveryFailedError(err) {
+			return nil, fmt.Errorf("Listing API resources: %s", err)
+		}
+	}
+
+	var result []schema.GroupVersionResource
+
+	for _, resList := range resLists {
+		gv, err := schema.ParseGroupVersion(resList.GroupVersion)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, apiRes := range resList.APIResources {
+			// Skip subresources (e.g. 'services/status')
+			if strings.Contains(apiRes.Name, "/") || !containsString(apiRes.Verbs, "list") {
+				continue
+			}
+			result = append(result, gv.WithResource(apiRes.Name))
+		}
+	}
+
+	return result, nil
+}
+
+func containsString(strs []string, str string) bool {
+	for _, s := range strs {
+		if s == str {
+			return true
+		}
+	}
+	return false
+}
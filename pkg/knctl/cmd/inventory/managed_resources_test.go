@@ -0,0 +1,91 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package inventory_test
+
+import (
+	"testing"
+
+	. "github.com/cppforlife/knctl/pkg/knctl/cmd/inventory"
+	"github.com/cppforlife/knctl/pkg/knctl/testkit"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+)
+
+func TestManagedResourcesList(t *testing.T) {
+	managedLabels := func(labels map[string]string) map[string]string {
+		labels["cli.knative.dev/managed-by"] = "knctl"
+		return labels
+	}
+
+	svc1 := testkit.NewService("ns1", "svc1").Build()
+	svc1.Labels = managedLabels(map[string]string{"cli.knative.dev/command": "deploy"})
+
+	cluster := testkit.NewCluster(t,
+		svc1,
+		&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "knctl-history-svc1",
+			Labels: managedLabels(map[string]string{"cli.knative.dev/history-service": "svc1"})}},
+		&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "knctl-history-svc2",
+			Labels: managedLabels(map[string]string{"cli.knative.dev/history-service": "svc2"})}},
+		&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: "ns2", Name: "secret1",
+			Labels: managedLabels(map[string]string{"cli.knative.dev/command": "basic-auth-secret-create"})}},
+		&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: "ns2", Name: "unmanaged"}},
+	)
+
+	dynamicClient, err := dynamic.NewForConfig(cluster.RESTConfig())
+	if err != nil {
+		t.Fatalf("Building dynamic client: %s", err)
+	}
+
+	resources := []schema.GroupVersionResource{
+		{Group: "serving.knative.dev", Version: "v1alpha1", Resource: "services"},
+		{Version: "v1", Resource: "configmaps"},
+		{Version: "v1", Resource: "secrets"},
+	}
+
+	managedResources, err := NewManagedResources(dynamicClient).List(resources)
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	type summary struct {
+		Namespace, Kind, Name, Command, ParentService string
+		Orphaned                                      bool
+	}
+
+	var actual []summary
+	for _, res := range managedResources {
+		actual = append(actual, summary{res.Namespace, res.Kind, res.Name, res.Command, res.ParentService, res.Orphaned})
+	}
+
+	expected := []summary{
+		{"ns1", "ConfigMap", "knctl-history-svc1", "", "svc1", false},
+		{"ns1", "ConfigMap", "knctl-history-svc2", "", "svc2", true},
+		{"ns1", "Service", "svc1", "deploy", "", false},
+		{"ns2", "Secret", "secret1", "basic-auth-secret-create", "", false},
+	}
+
+	if len(actual) != len(expected) {
+		t.Fatalf("Expected resources to match: %#v", actual)
+	}
+	for i := range expected {
+		if actual[i] != expected[i] {
+			t.Fatalf("Expected resource '%#v' to match '%#v'", actual[i], expected[i])
+		}
+	}
+}
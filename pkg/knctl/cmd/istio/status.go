@@ -0,0 +1,210 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package istio
+
+import (
+	"fmt"
+
+	"github.com/cppforlife/go-cli-ui/ui"
+	uitable "github.com/cppforlife/go-cli-ui/ui/table"
+	cmdcore "github.com/cppforlife/knctl/pkg/knctl/cmd/core"
+	ctling "github.com/cppforlife/knctl/pkg/knctl/ingress"
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	servingNs              = "knative-serving"
+	servingReleaseLabelKey = "serving.knative.dev/release"
+)
+
+type StatusOptions struct {
+	ui          ui.UI
+	depsFactory cmdcore.DepsFactory
+}
+
+func NewStatusOptions(ui ui.UI, depsFactory cmdcore.DepsFactory) *StatusOptions {
+	return &StatusOptions{ui: ui, depsFactory: depsFactory}
+}
+
+func NewStatusCmd(o *StatusOptions, flagsFactory cmdcore.FlagsFactory) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "status",
+		Short: "Show Istio version and mesh health",
+		Long: `Show Istio control plane version, sidecar injection webhook health,
+gateway pods readiness and known incompatibilities with installed Knative Serving version.`,
+		Example: `
+  # Show Istio status
+  knctl istio status`,
+		RunE: func(_ *cobra.Command, _ []string) error { return o.Run() },
+	}
+	return cmd
+}
+
+func (o *StatusOptions) Run() error {
+	coreClient, err := o.depsFactory.CoreClient()
+	if err != nil {
+		return err
+	}
+
+	status, err := ctling.NewIstioStatuses(coreClient).Get()
+	if err != nil {
+		return err
+	}
+
+	knativeVersion, err := o.knativeVersion(coreClient)
+	if err != nil {
+		return err
+	}
+
+	problems := status.Incompatibilities(knativeVersion)
+
+	o.printSummary(status, knativeVersion, problems)
+	o.printControlPlane(status)
+	o.printGatewayPods(status)
+
+	return nil
+}
+
+func (o *StatusOptions) knativeVersion(coreClient kubernetes.Interface) (string, error) {
+	deployments, err := coreClient.AppsV1().Deployments(servingNs).List(metav1.ListOptions{})
+	if err != nil {
+		return "", fmt.Errorf("Listing deployments in namespace '%s': %s", servingNs, err)
+	}
+
+	for _, dep := range deployments.Items {
+		if release, found := dep.Labels[servingReleaseLabelKey]; found {
+			return release, nil
+		}
+	}
+
+	return "", nil
+}
+
+func (o *StatusOptions) printSummary(status ctling.IstioStatus, knativeVersion string, problems []string) {
+	webhook := status.InjectionWebhook
+	webhookDesc := "not found"
+
+	if webhook.Found {
+		webhookDesc = fmt.Sprintf("%d ready endpoint(s)", webhook.ReadyEndpoints)
+		if len(webhook.Service) > 0 {
+			webhookDesc += fmt.Sprintf(" (service '%s', failure policy '%s')", webhook.Service, webhook.FailurePolicy)
+		}
+	}
+
+	var readyGateways int
+
+	for _, pod := range status.GatewayPods {
+		if pod.Ready {
+			readyGateways++
+		}
+	}
+
+	table := uitable.Table{
+		Title: "Istio",
+
+		Header: []uitable.Header{
+			uitable.NewHeader("Installed"),
+			uitable.NewHeader("Version"),
+			uitable.NewHeader("Knative Serving version"),
+			uitable.NewHeader("Injection webhook"),
+			uitable.NewHeader("Gateway pods"),
+			uitable.NewHeader("Problems"),
+		},
+
+		Transpose: true,
+
+		Rows: [][]uitable.Value{
+			{
+				uitable.NewValueBool(status.Installed),
+				uitable.NewValueString(status.Version),
+				uitable.NewValueString(knativeVersion),
+				uitable.ValueFmt{
+					V:     uitable.NewValueString(webhookDesc),
+					Error: !webhook.Healthy(),
+				},
+				uitable.ValueFmt{
+					V:     uitable.NewValueString(fmt.Sprintf("%d/%d ready", readyGateways, len(status.GatewayPods))),
+					Error: readyGateways == 0,
+				},
+				uitable.ValueFmt{
+					V:     uitable.NewValueStrings(problems),
+					Error: len(problems) > 0,
+				},
+			},
+		},
+	}
+
+	o.ui.PrintTable(table)
+}
+
+func (o *StatusOptions) printControlPlane(status ctling.IstioStatus) {
+	table := uitable.Table{
+		Title:   "Control plane",
+		Content: "deployments",
+
+		Header: []uitable.Header{
+			uitable.NewHeader("Name"),
+			uitable.NewHeader("Available"),
+			uitable.NewHeader("Image"),
+		},
+
+		SortBy: []uitable.ColumnSort{{Column: 0, Asc: true}},
+	}
+
+	for _, dep := range status.ControlPlane {
+		table.Rows = append(table.Rows, []uitable.Value{
+			uitable.NewValueString(dep.Name),
+			uitable.ValueFmt{
+				V:     uitable.NewValueString(fmt.Sprintf("%d/%d", dep.AvailableReplicas, dep.DesiredReplicas)),
+				Error: dep.AvailableReplicas < dep.DesiredReplicas,
+			},
+			uitable.NewValueString(dep.Image),
+		})
+	}
+
+	o.ui.PrintTable(table)
+}
+
+func (o *StatusOptions) printGatewayPods(status ctling.IstioStatus) {
+	table := uitable.Table{
+		Title:   "Gateway pods",
+		Content: "pods",
+
+		Header: []uitable.Header{
+			uitable.NewHeader("Name"),
+			uitable.NewHeader("Phase"),
+			uitable.NewHeader("Ready"),
+		},
+
+		SortBy: []uitable.ColumnSort{{Column: 0, Asc: true}},
+	}
+
+	for _, pod := range status.GatewayPods {
+		table.Rows = append(table.Rows, []uitable.Value{
+			uitable.NewValueString(pod.Name),
+			uitable.NewValueString(string(pod.Phase)),
+			uitable.ValueFmt{
+				V:     uitable.NewValueBool(pod.Ready),
+				Error: !pod.Ready,
+			},
+		})
+	}
+
+	o.ui.PrintTable(table)
+}
@@ -0,0 +1,117 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package job
+
+import (
+	"fmt"
+
+	"github.com/cppforlife/go-cli-ui/ui"
+	cmdcore "github.com/cppforlife/knctl/pkg/knctl/cmd/core"
+	"github.com/ghodss/yaml"
+	"github.com/spf13/cobra"
+)
+
+type JobOptions struct {
+	ui          ui.UI
+	depsFactory cmdcore.DepsFactory
+
+	NamespaceFlags cmdcore.NamespaceFlags
+	Image          string
+	ServiceAccount string
+	BackoffLimit   int32
+	Print          bool
+
+	Args []string
+}
+
+func NewJobOptions(ui ui.UI, depsFactory cmdcore.DepsFactory) *JobOptions {
+	return &JobOptions{ui: ui, depsFactory: depsFactory}
+}
+
+func NewJobCmd(o *JobOptions, flagsFactory cmdcore.FlagsFactory) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "job -- KNCTL-ARGS...",
+		Short: "Run knctl command inside the cluster as a Kubernetes job",
+		Long: `Run knctl command inside the cluster as a Kubernetes job.
+
+Creates a job in the namespace that runs given knctl command in a pod.
+knctl running in a pod uses in-cluster configuration (credentials of job's service account)
+and reaches ingress gateway via cluster DNS instead of load balancer or node addresses.
+Service account needs permissions required by the command (e.g. to update Knative services).
+
+Job is not retried by default since knctl commands are not necessarily idempotent.`,
+		Example: `
+  # Deploy service 'svc1' from a job in namespace 'ns1'
+  knctl job -n ns1 --image registry/knctl:latest --service-account deployer -- deploy -s svc1 --image registry/app:v2
+
+  # Print job YAML (e.g. to be committed to a repository) instead of creating it
+  knctl job -n ns1 --image registry/knctl:latest --print -- service list`,
+		Args: cobra.MinimumNArgs(1),
+		Annotations: map[string]string{
+			cmdcore.OtherHelpGroup.Key: cmdcore.OtherHelpGroup.Value,
+		},
+		RunE: func(_ *cobra.Command, args []string) error {
+			o.Args = args
+			return o.Run()
+		},
+	}
+	o.NamespaceFlags.Set(cmd, flagsFactory)
+	cmd.Flags().StringVar(&o.Image, "image", "", "Set image that contains knctl binary on its PATH")
+	cmd.Flags().StringVar(&o.ServiceAccount, "service-account", "default", "Set service account used by the job")
+	cmd.Flags().Int32Var(&o.BackoffLimit, "backoff-limit", 0, "Set number of retries before job is marked as failed")
+	cmd.Flags().BoolVar(&o.Print, "print", false, "Print job YAML instead of creating it")
+	cmd.MarkFlagRequired("image")
+	return cmd
+}
+
+func (o *JobOptions) Run() error {
+	if o.BackoffLimit < 0 {
+		return fmt.Errorf("Expected backoff limit to be non-negative")
+	}
+
+	job := KnctlJob{
+		Namespace:      o.NamespaceFlags.Name,
+		Image:          o.Image,
+		ServiceAccount: o.ServiceAccount,
+		BackoffLimit:   o.BackoffLimit,
+		Args:           o.Args,
+	}.Job()
+
+	if o.Print {
+		bs, err := yaml.Marshal(job)
+		if err != nil {
+			return fmt.Errorf("Marshaling job: %s", err)
+		}
+
+		o.ui.PrintBlock(bs)
+		return nil
+	}
+
+	coreClient, err := o.depsFactory.CoreClient()
+	if err != nil {
+		return err
+	}
+
+	createdJob, err := coreClient.BatchV1().Jobs(job.Namespace).Create(job)
+	if err != nil {
+		return fmt.Errorf("Creating job: %s", err)
+	}
+
+	o.ui.PrintLinef("Created job '%s' in namespace '%s'", createdJob.Name, createdJob.Namespace)
+
+	return nil
+}
@@ -0,0 +1,64 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package job_test
+
+import (
+	"testing"
+
+	. "github.com/cppforlife/knctl/pkg/knctl/cmd"
+	cmdcore "github.com/cppforlife/knctl/pkg/knctl/cmd/core"
+	. "github.com/cppforlife/knctl/pkg/knctl/cmd/job"
+)
+
+func TestNewJobCmd_Ok(t *testing.T) {
+	realCmd := NewJobOptions(nil, cmdcore.NewDepsFactory())
+	cmd := NewTestCmd(t, NewJobCmd(realCmd, cmdcore.FlagsFactory{}))
+	cmd.ExpectBasicConfig()
+	cmd.Execute([]string{
+		"-n", "test-namespace",
+		"--image", "test-image",
+		"--service-account", "test-sa",
+		"--backoff-limit", "3",
+		"--print",
+		"--", "service", "list",
+	})
+	cmd.ExpectReachesExecution()
+
+	DeepEqual(t, realCmd.NamespaceFlags.Name, "test-namespace")
+	DeepEqual(t, realCmd.Image, "test-image")
+	DeepEqual(t, realCmd.ServiceAccount, "test-sa")
+	DeepEqual(t, realCmd.BackoffLimit, int32(3))
+	DeepEqual(t, realCmd.Print, true)
+}
+
+func TestNewJobCmd_Defaults(t *testing.T) {
+	realCmd := NewJobOptions(nil, cmdcore.NewDepsFactory())
+	cmd := NewTestCmd(t, NewJobCmd(realCmd, cmdcore.FlagsFactory{}))
+	cmd.Execute([]string{"-n", "test-namespace", "--image", "test-image", "--", "service", "list"})
+	cmd.ExpectReachesExecution()
+
+	DeepEqual(t, realCmd.ServiceAccount, "default")
+	DeepEqual(t, realCmd.BackoffLimit, int32(0))
+	DeepEqual(t, realCmd.Print, false)
+}
+
+func TestNewJobCmd_RequiredFlags(t *testing.T) {
+	realCmd := NewJobOptions(nil, cmdcore.NewDepsFactory())
+	cmd := NewTestCmd(t, NewJobCmd(realCmd, cmdcore.FlagsFactory{}))
+	cmd.Execute([]string{"--", "service", "list"})
+	cmd.ExpectRequiredFlags([]string{"image"})
+}
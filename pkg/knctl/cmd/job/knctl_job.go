@@ -0,0 +1,83 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package job
+
+import (
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	knctlJobContainer = "knctl"
+	knctlJobLabelKey  = "cli.knative.dev/job"
+)
+
+// KnctlJob produces Kubernetes job that runs knctl with given arguments.
+// knctl inside the pod uses in-cluster configuration (service account
+// credentials) and reaches ingress gateway via cluster DNS.
+type KnctlJob struct {
+	Namespace      string
+	Image          string
+	ServiceAccount string
+	BackoffLimit   int32
+	Args           []string
+}
+
+// Job returns job that is not retried by default since knctl commands
+// are not necessarily idempotent. Istio sidecar is not injected
+// since it would prevent pod from ever completing.
+func (j KnctlJob) Job() *batchv1.Job {
+	backoffLimit := j.BackoffLimit
+
+	return &batchv1.Job{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "batch/v1",
+			Kind:       "Job",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: "knctl-",
+			Namespace:    j.Namespace,
+			Labels: map[string]string{
+				knctlJobLabelKey: "true",
+			},
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit: &backoffLimit,
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{
+						knctlJobLabelKey: "true",
+					},
+					Annotations: map[string]string{
+						"sidecar.istio.io/inject": "false",
+					},
+				},
+				Spec: corev1.PodSpec{
+					ServiceAccountName: j.ServiceAccount,
+					RestartPolicy:      corev1.RestartPolicyNever,
+					Containers: []corev1.Container{{
+						Name:    knctlJobContainer,
+						Image:   j.Image,
+						Command: []string{"knctl"},
+						Args:    j.Args,
+					}},
+				},
+			},
+		},
+	}
+}
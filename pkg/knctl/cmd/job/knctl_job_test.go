@@ -0,0 +1,60 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package job_test
+
+import (
+	"reflect"
+	"testing"
+
+	. "github.com/cppforlife/knctl/pkg/knctl/cmd/job"
+)
+
+func TestKnctlJob(t *testing.T) {
+	job := KnctlJob{
+		Namespace:      "ns1",
+		Image:          "registry/knctl",
+		ServiceAccount: "deployer",
+		BackoffLimit:   2,
+		Args:           []string{"service", "list"},
+	}.Job()
+
+	if job.Namespace != "ns1" || job.GenerateName != "knctl-" {
+		t.Fatalf("Expected job meta to match: %#v", job.ObjectMeta)
+	}
+
+	if job.Spec.BackoffLimit == nil || *job.Spec.BackoffLimit != 2 {
+		t.Fatalf("Expected backoff limit to be set")
+	}
+
+	podSpec := job.Spec.Template.Spec
+
+	if podSpec.ServiceAccountName != "deployer" || podSpec.RestartPolicy != "Never" {
+		t.Fatalf("Expected pod spec to match: %#v", podSpec)
+	}
+
+	if job.Spec.Template.Annotations["sidecar.istio.io/inject"] != "false" {
+		t.Fatalf("Expected istio sidecar to not be injected")
+	}
+
+	container := podSpec.Containers[0]
+
+	if container.Image != "registry/knctl" ||
+		!reflect.DeepEqual(container.Command, []string{"knctl"}) ||
+		!reflect.DeepEqual(container.Args, []string{"service", "list"}) {
+		t.Fatalf("Expected container to match: %#v", container)
+	}
+}
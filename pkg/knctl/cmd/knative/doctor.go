@@ -0,0 +1,102 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package knative
+
+import (
+	"fmt"
+
+	"github.com/cppforlife/go-cli-ui/ui"
+	uitable "github.com/cppforlife/go-cli-ui/ui/table"
+	cmdcore "github.com/cppforlife/knctl/pkg/knctl/cmd/core"
+	"github.com/spf13/cobra"
+)
+
+type DoctorOptions struct {
+	ui          ui.UI
+	depsFactory cmdcore.DepsFactory
+}
+
+func NewDoctorOptions(ui ui.UI, depsFactory cmdcore.DepsFactory) *DoctorOptions {
+	return &DoctorOptions{ui: ui, depsFactory: depsFactory}
+}
+
+func NewDoctorCmd(o *DoctorOptions, flagsFactory cmdcore.FlagsFactory) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "doctor",
+		Short: "Check cluster compatibility",
+		Long: `Check cluster compatibility.
+
+Verifies Kubernetes version, installed Knative and networking layer versions,
+Knative CRDs, webhook health, domain configuration, ingress gateway reachability
+from this machine and metrics API availability.
+
+Exits with non-zero status if any check results in an error (warnings are not fatal).`,
+		Example: `
+  # Check currently targeted cluster
+  knctl doctor`,
+		Annotations: map[string]string{
+			cmdcore.SystemHelpGroup.Key: cmdcore.SystemHelpGroup.Value,
+		},
+		RunE: func(_ *cobra.Command, _ []string) error { return o.Run() },
+	}
+	return cmd
+}
+
+func (o *DoctorOptions) Run() error {
+	coreClient, err := o.depsFactory.CoreClient()
+	if err != nil {
+		return err
+	}
+
+	checks := NewDoctorChecks(coreClient).Run()
+
+	table := uitable.Table{
+		Title:   "Cluster compatibility",
+		Content: "checks",
+
+		Header: []uitable.Header{
+			uitable.NewHeader("Check"),
+			uitable.NewHeader("Status"),
+			uitable.NewHeader("Details"),
+		},
+	}
+
+	var failed int
+
+	for _, check := range checks {
+		if check.Status == DoctorCheckError {
+			failed++
+		}
+
+		table.Rows = append(table.Rows, []uitable.Value{
+			uitable.NewValueString(check.Name),
+			uitable.ValueFmt{
+				V:     uitable.NewValueString(string(check.Status)),
+				Error: check.Status != DoctorCheckOK,
+			},
+			uitable.NewValueString(check.Details),
+		})
+	}
+
+	o.ui.PrintTable(table)
+
+	if failed > 0 {
+		return fmt.Errorf("Expected all checks to succeed, but %d failed", failed)
+	}
+
+	return nil
+}
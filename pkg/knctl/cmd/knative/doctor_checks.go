@@ -0,0 +1,307 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package knative
+
+import (
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	ctling "github.com/cppforlife/knctl/pkg/knctl/ingress"
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+type DoctorCheckStatus string
+
+const (
+	DoctorCheckOK      DoctorCheckStatus = "ok"
+	DoctorCheckWarning DoctorCheckStatus = "warning"
+	DoctorCheckError   DoctorCheckStatus = "error"
+
+	servingReleaseLabelKey = "serving.knative.dev/release"
+	servingWebhookName     = "webhook"
+	exampleDomain          = "example.com"
+)
+
+type DoctorCheck struct {
+	Name    string
+	Status  DoctorCheckStatus
+	Details string
+}
+
+// DoctorChecks verifies that cluster is compatible with knctl
+// and that installed Knative components are functional
+type DoctorChecks struct {
+	coreClient  kubernetes.Interface
+	dialTimeout time.Duration
+}
+
+func NewDoctorChecks(coreClient kubernetes.Interface) DoctorChecks {
+	return DoctorChecks{coreClient, 5 * time.Second}
+}
+
+func (c DoctorChecks) Run() []DoctorCheck {
+	return []DoctorCheck{
+		c.kubernetesVersion(),
+		c.servingVersion(),
+		c.networkingLayer(),
+		c.servingCRDs(),
+		c.servingWebhook(),
+		c.configDomain(),
+		c.ingressReachability(),
+		c.metricsAPI(),
+	}
+}
+
+func (c DoctorChecks) kubernetesVersion() DoctorCheck {
+	check := DoctorCheck{Name: "Kubernetes version"}
+
+	version, err := c.coreClient.Discovery().ServerVersion()
+	if err != nil {
+		return c.errored(check, fmt.Sprintf("Getting server version: %s", err))
+	}
+
+	major, minor, err := parseServerVersion(version)
+	if err != nil {
+		return c.errored(check, err.Error())
+	}
+
+	if major == 1 && minor < 10 {
+		return c.errored(check, fmt.Sprintf("Found %s, but Knative requires >=1.10; upgrade cluster", version.GitVersion))
+	}
+
+	return c.ok(check, version.GitVersion)
+}
+
+func (c DoctorChecks) servingVersion() DoctorCheck {
+	check := DoctorCheck{Name: "Knative Serving installed"}
+
+	deployments, err := c.deployments(servingNs)
+	if err != nil {
+		return c.errored(check, err.Error())
+	}
+
+	if len(deployments) == 0 {
+		return c.errored(check, "No deployments found; install Knative Serving via 'knctl install'")
+	}
+
+	for _, dep := range deployments {
+		if release, found := dep.Labels[servingReleaseLabelKey]; found {
+			return c.ok(check, release)
+		}
+	}
+
+	return c.ok(check, fmt.Sprintf("Unknown version (pre-release label installation, e.g. %s)", DefaultServingVersion))
+}
+
+func (c DoctorChecks) networkingLayer() DoctorCheck {
+	check := DoctorCheck{Name: "Networking layer installed"}
+
+	var found []string
+
+	nets := []struct {
+		Name      string
+		Namespace string
+	}{
+		{NetIstio, ctling.NewIstio().SystemNamespaceName()},
+		{NetKourier, ctling.NewKourier().SystemNamespaceName()},
+	}
+
+	for _, n := range nets {
+		deployments, err := c.deployments(n.Namespace)
+		if err != nil {
+			return c.errored(check, err.Error())
+		}
+
+		if len(deployments) > 0 {
+			desc := n.Name
+			if tag := c.imageTag(deployments); len(tag) > 0 {
+				desc += " " + tag
+			}
+			found = append(found, desc)
+		}
+	}
+
+	if len(found) == 0 {
+		return c.errored(check, "Neither Istio nor Kourier found; install networking layer via 'knctl install'")
+	}
+
+	return c.ok(check, strings.Join(found, ", "))
+}
+
+func (c DoctorChecks) servingCRDs() DoctorCheck {
+	check := DoctorCheck{Name: "Knative Serving CRDs present"}
+
+	groupVersion := "serving.knative.dev/v1alpha1"
+	expectedResources := []string{"services", "configurations", "routes", "revisions"}
+
+	resources, err := c.coreClient.Discovery().ServerResourcesForGroupVersion(groupVersion)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return c.errored(check, fmt.Sprintf("API group version '%s' is not served; "+
+				"installed Knative may be too new or missing for this knctl version", groupVersion))
+		}
+		return c.errored(check, fmt.Sprintf("Getting resources for '%s': %s", groupVersion, err))
+	}
+
+	served := map[string]struct{}{}
+
+	for _, res := range resources.APIResources {
+		served[res.Name] = struct{}{}
+	}
+
+	var missing []string
+
+	for _, name := range expectedResources {
+		if _, found := served[name]; !found {
+			missing = append(missing, name)
+		}
+	}
+
+	if len(missing) > 0 {
+		return c.errored(check, fmt.Sprintf("Missing resources: %s; reinstall Knative Serving", strings.Join(missing, ", ")))
+	}
+
+	return c.ok(check, groupVersion)
+}
+
+func (c DoctorChecks) servingWebhook() DoctorCheck {
+	check := DoctorCheck{Name: "Knative Serving webhook healthy"}
+
+	dep, err := c.coreClient.AppsV1().Deployments(servingNs).Get(servingWebhookName, metav1.GetOptions{})
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return c.errored(check, fmt.Sprintf("Deployment '%s/%s' not found; reinstall Knative Serving", servingNs, servingWebhookName))
+		}
+		return c.errored(check, fmt.Sprintf("Getting webhook deployment: %s", err))
+	}
+
+	if dep.Status.AvailableReplicas < 1 {
+		return c.errored(check, fmt.Sprintf("No available replicas; inspect pods via 'kubectl -n %s get pods'", servingNs))
+	}
+
+	return c.ok(check, fmt.Sprintf("%d available replica(s)", dep.Status.AvailableReplicas))
+}
+
+func (c DoctorChecks) configDomain() DoctorCheck {
+	check := DoctorCheck{Name: "Domain configured"}
+
+	configMap, err := c.coreClient.CoreV1().ConfigMaps(servingNs).Get("config-domain", metav1.GetOptions{})
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return c.errored(check, "Config map 'config-domain' not found; reinstall Knative Serving")
+		}
+		return c.errored(check, fmt.Sprintf("Getting config-domain config map: %s", err))
+	}
+
+	var domains []string
+
+	for k, _ := range configMap.Data {
+		if !strings.HasPrefix(k, "_") {
+			domains = append(domains, k)
+		}
+	}
+
+	switch {
+	case len(domains) == 0:
+		return c.warned(check, "No domains configured; configure one via 'knctl domain create'")
+	case len(domains) == 1 && domains[0] == exampleDomain:
+		return c.warned(check, fmt.Sprintf("Only default '%s' domain configured; configure a real domain via 'knctl domain create'", exampleDomain))
+	default:
+		return c.ok(check, strings.Join(domains, ", "))
+	}
+}
+
+func (c DoctorChecks) ingressReachability() DoctorCheck {
+	check := DoctorCheck{Name: "Ingress gateway reachable"}
+
+	addr, port, err := ctling.NewIngressServices(c.coreClient).PreferredAddress(80)
+	if err != nil {
+		return c.warned(check, fmt.Sprintf("%s; 'knctl curl' will require --ingress-address or port forwarding", err))
+	}
+
+	hostPort := net.JoinHostPort(addr, port)
+
+	conn, err := net.DialTimeout("tcp", hostPort, c.dialTimeout)
+	if err != nil {
+		return c.warned(check, fmt.Sprintf("Connecting to %s: %s; check firewall rules or load balancer provisioning", hostPort, err))
+	}
+
+	conn.Close()
+
+	return c.ok(check, hostPort)
+}
+
+func (c DoctorChecks) metricsAPI() DoctorCheck {
+	check := DoctorCheck{Name: "Metrics API available"}
+
+	groups, err := c.coreClient.Discovery().ServerGroups()
+	if err != nil {
+		return c.errored(check, fmt.Sprintf("Getting server groups: %s", err))
+	}
+
+	for _, group := range groups.Groups {
+		if group.Name == "metrics.k8s.io" {
+			return c.ok(check, group.PreferredVersion.GroupVersion)
+		}
+	}
+
+	return c.warned(check, "API group 'metrics.k8s.io' not found; install metrics-server "+
+		"to use HPA based autoscaling and resource usage reporting")
+}
+
+func (c DoctorChecks) deployments(nsName string) ([]appsv1.Deployment, error) {
+	deployments, err := c.coreClient.AppsV1().Deployments(nsName).List(metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("Listing deployments in namespace '%s': %s", nsName, err)
+	}
+	return deployments.Items, nil
+}
+
+func (DoctorChecks) imageTag(deployments []appsv1.Deployment) string {
+	for _, dep := range deployments {
+		for _, cont := range dep.Spec.Template.Spec.Containers {
+			pieces := strings.Split(cont.Image, ":")
+			if len(pieces) > 1 && !strings.Contains(pieces[len(pieces)-1], "/") {
+				return pieces[len(pieces)-1]
+			}
+		}
+	}
+	return ""
+}
+
+func (DoctorChecks) ok(check DoctorCheck, details string) DoctorCheck {
+	check.Status = DoctorCheckOK
+	check.Details = details
+	return check
+}
+
+func (DoctorChecks) warned(check DoctorCheck, details string) DoctorCheck {
+	check.Status = DoctorCheckWarning
+	check.Details = details
+	return check
+}
+
+func (DoctorChecks) errored(check DoctorCheck, details string) DoctorCheck {
+	check.Status = DoctorCheckError
+	check.Details = details
+	return check
+}
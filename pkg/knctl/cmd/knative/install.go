@@ -29,11 +29,14 @@ import (
 
 	"github.com/cppforlife/go-cli-ui/ui"
 	cmdcore "github.com/cppforlife/knctl/pkg/knctl/cmd/core"
+	cmding "github.com/cppforlife/knctl/pkg/knctl/cmd/ingress"
 	ctling "github.com/cppforlife/knctl/pkg/knctl/ingress"
 	"github.com/spf13/cobra"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sversion "k8s.io/apimachinery/pkg/version"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/util/retry"
 )
 
 var (
@@ -52,6 +55,10 @@ var (
 		URL:     "https://github.com/knative/serving/releases/download/v0.2.1/release-no-mon.yaml",
 		SHA256:  "6ec74658556241300af1e0c0ae6f08d92f36209c54ae7a213cc52c3adec972d5",
 	}
+	InstallKnativeEventingAsset = InstallationAsset{
+		Version: "0.2.1",
+		URL:     "https://github.com/knative/eventing/releases/download/v0.2.1/release.yaml",
+	}
 )
 
 type InstallOptions struct {
@@ -62,6 +69,10 @@ type InstallOptions struct {
 	ExcludeMonitoring bool
 	VersionCheck      bool
 
+	ServingVersion string
+	Net            string
+	Eventing       bool
+
 	kubeconfigFlags *cmdcore.KubeconfigFlags
 }
 
@@ -75,7 +86,16 @@ func NewInstallCmd(o *InstallOptions, flagsFactory cmdcore.FlagsFactory) *cobra.
 		Short: "Install Knative and Istio",
 		Long: `Install Knative and Istio.
 
-Requires 'kubectl' command installed on a the system.`,
+Requires 'kubectl' command installed on a the system.
+
+Assets for Knative Serving ` + DefaultServingVersion + ` are verified against known checksums.
+Other versions are installed from upstream release manifests without checksum verification.`,
+		Example: `
+  # Install Knative Serving with Istio
+  knctl install
+
+  # Install Knative Serving 1.2.0 with Kourier and Knative Eventing
+  knctl install --serving-version 1.2.0 --net kourier --eventing`,
 		Annotations: map[string]string{
 			cmdcore.SystemHelpGroup.Key: cmdcore.SystemHelpGroup.Value,
 		},
@@ -84,6 +104,9 @@ Requires 'kubectl' command installed on a the system.`,
 	cmd.Flags().BoolVarP(&o.NodePorts, "node-ports", "p", false, "Use service type NodePorts instead of type LoadBalancer")
 	cmd.Flags().BoolVarP(&o.ExcludeMonitoring, "exclude-monitoring", "m", false, "Exclude installation of monitoring components")
 	cmd.Flags().BoolVar(&o.VersionCheck, "version-check", true, "Check minimum Kubernetes API server version")
+	cmd.Flags().StringVar(&o.ServingVersion, "serving-version", DefaultServingVersion, "Set Knative Serving version")
+	cmd.Flags().StringVar(&o.Net, "net", NetIstio, "Set networking layer (values: istio, kourier)")
+	cmd.Flags().BoolVar(&o.Eventing, "eventing", false, "Install Knative Eventing")
 	return cmd
 }
 
@@ -97,6 +120,11 @@ func (o *InstallOptions) Run() error {
 		return err
 	}
 
+	httpClient, err := o.depsFactory.HTTPClient()
+	if err != nil {
+		return err
+	}
+
 	if o.VersionCheck {
 		err = o.ensureMinimumServerVersion(coreClient)
 		if err != nil {
@@ -104,19 +132,24 @@ func (o *InstallOptions) Run() error {
 		}
 	}
 
-	istio := ctling.NewIstio()
-	knativeAsset := InstallKnativeFullAsset
-
-	if o.ExcludeMonitoring {
-		knativeAsset = InstallKnativeNoMonAsset
+	profile := InstallProfile{
+		ServingVersion:    o.ServingVersion,
+		Net:               o.Net,
+		Eventing:          o.Eventing,
+		ExcludeMonitoring: o.ExcludeMonitoring,
 	}
 
-	components := []InstallationComponent{
-		{"Istio", YAMLSource{InstallIstioAsset, o.NodePorts}, NamespaceReadiness{istio.SystemNamespaceName(), o.ui, coreClient}, o.ui, o.kubeconfigFlags, 1},
-		{"Knative", YAMLSource{knativeAsset, o.NodePorts}, NamespaceReadiness{"knative-serving", o.ui, coreClient}, o.ui, o.kubeconfigFlags, 0},
+	profileComponents, err := profile.Components()
+	if err != nil {
+		return err
 	}
 
-	for _, c := range components {
+	for _, pc := range profileComponents {
+		c := InstallationComponent{
+			pc.Name, YAMLSource{pc.Asset, o.NodePorts, httpClient}, NamespaceReadiness{pc.Namespace, o.ui, coreClient},
+			o.ui, o.kubeconfigFlags, pc.RetryCount,
+		}
+
 		err = c.Install()
 		if err != nil {
 			return err
@@ -128,7 +161,36 @@ func (o *InstallOptions) Run() error {
 		}
 	}
 
-	return nil
+	if o.Net == NetKourier {
+		err = o.configureKourierIngressClass(coreClient)
+		if err != nil {
+			return err
+		}
+	}
+
+	return cmding.NewListOptions(o.ui, o.depsFactory).Run()
+}
+
+func (o *InstallOptions) configureKourierIngressClass(coreClient kubernetes.Interface) error {
+	o.ui.PrintLinef("Configuring Knative Serving to use Kourier")
+
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		configMap, err := coreClient.CoreV1().ConfigMaps(servingNs).Get("config-network", metav1.GetOptions{})
+		if err != nil {
+			return fmt.Errorf("Getting config map 'config-network': %s", err)
+		}
+
+		if configMap.Data == nil {
+			configMap.Data = map[string]string{}
+		}
+
+		// Older releases use 'ingress.class' key
+		configMap.Data["ingress-class"] = ctling.NewKourier().IngressClass()
+		configMap.Data["ingress.class"] = ctling.NewKourier().IngressClass()
+
+		_, err = coreClient.CoreV1().ConfigMaps(servingNs).Update(configMap)
+		return err
+	})
 }
 
 func (o *InstallOptions) ensureMinimumServerVersion(coreClient kubernetes.Interface) error {
@@ -137,22 +199,31 @@ func (o *InstallOptions) ensureMinimumServerVersion(coreClient kubernetes.Interf
 		return err
 	}
 
+	majorI, minorI, err := parseServerVersion(version)
+	if err != nil {
+		return err
+	}
+
+	if majorI == 1 && minorI < 10 {
+		return fmt.Errorf("Expected Kubernetes API server version to be >=1.10")
+	}
+
+	return nil
+}
+
+func parseServerVersion(version *k8sversion.Info) (int, int, error) {
 	majorI, err := strconv.Atoi(version.Major)
 	if err != nil {
-		return fmt.Errorf("Converting major version '%s' to int: %s", version.Major, err)
+		return 0, 0, fmt.Errorf("Converting major version '%s' to int: %s", version.Major, err)
 	}
 
 	// GKE shows minor as "10+"
 	minorI, err := strconv.Atoi(strings.TrimRight(version.Minor, "-+"))
 	if err != nil {
-		return fmt.Errorf("Converting minor version '%s' to int: %s", version.Minor, err)
+		return 0, 0, fmt.Errorf("Converting minor version '%s' to int: %s", version.Minor, err)
 	}
 
-	if majorI == 1 && minorI < 10 {
-		return fmt.Errorf("Expected Kubernetes API server version to be >=1.10")
-	}
-
-	return nil
+	return majorI, minorI, nil
 }
 
 type InstallationComponent struct {
@@ -169,6 +240,10 @@ type InstallationComponent struct {
 func (c InstallationComponent) Install() error {
 	c.ui.PrintLinef("Installing %s from '%s'", c.Name, c.source.Source())
 
+	if len(c.source.Asset.SHA256) == 0 {
+		c.ui.PrintLinef("Skipping checksum verification for '%s' since it's not known", c.source.Source())
+	}
+
 	kubeconfigPath, err := c.kubeconfigFlags.Path.Value()
 	if err != nil {
 		return err
@@ -201,13 +276,18 @@ func (c InstallationComponent) runKubectl(content string, opts []string) error {
 }
 
 func (c InstallationComponent) Monitor() error {
+	if len(c.nsReadiness.Namespace) == 0 {
+		return nil
+	}
+
 	c.ui.PrintLinef("Waiting for %s to start...", c.Name)
 	return c.nsReadiness.Monitor()
 }
 
 type YAMLSource struct {
-	Asset     InstallationAsset
-	NodePorts bool
+	Asset      InstallationAsset
+	NodePorts  bool
+	HTTPClient *http.Client // defaults to http.DefaultClient
 }
 
 func (s YAMLSource) Source() string {
@@ -227,8 +307,13 @@ func (s YAMLSource) Content() (string, error) {
 	return content, nil
 }
 
-func (YAMLSource) download(url, expectedSHA256 string) (string, error) {
-	resp, err := http.Get(url)
+func (s YAMLSource) download(url, expectedSHA256 string) (string, error) {
+	httpClient := s.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	resp, err := httpClient.Get(url)
 	if err != nil {
 		return "", fmt.Errorf("Downloading YAML from URL '%s': %s", url, err)
 	}
@@ -240,7 +325,7 @@ func (YAMLSource) download(url, expectedSHA256 string) (string, error) {
 		return "", fmt.Errorf("Reading YAML from URL '%s': %s", url, err)
 	}
 
-	if fmt.Sprintf("%x", gosha256.Sum256(result)) != expectedSHA256 {
+	if len(expectedSHA256) > 0 && fmt.Sprintf("%x", gosha256.Sum256(result)) != expectedSHA256 {
 		return "", fmt.Errorf("Expected URL '%s' content to match SHA256 '%s' but did not", url, expectedSHA256)
 	}
 
@@ -0,0 +1,137 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package knative
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	ctling "github.com/cppforlife/knctl/pkg/knctl/ingress"
+)
+
+const (
+	DefaultServingVersion = "0.2.1"
+
+	NetIstio   = "istio"
+	NetKourier = "kourier"
+
+	servingNs  = "knative-serving"
+	eventingNs = "knative-eventing"
+)
+
+type InstallProfile struct {
+	ServingVersion    string
+	Net               string
+	Eventing          bool
+	ExcludeMonitoring bool
+}
+
+// InstallProfileComponent describes single set of YAML to apply
+// and a namespace (optional) that should become ready afterwards
+type InstallProfileComponent struct {
+	Name       string
+	Asset      InstallationAsset
+	Namespace  string
+	RetryCount int
+}
+
+func (p InstallProfile) Components() ([]InstallProfileComponent, error) {
+	if p.Net != NetIstio && p.Net != NetKourier {
+		return nil, fmt.Errorf("Expected networking layer to be '%s' or '%s' but was '%s'", NetIstio, NetKourier, p.Net)
+	}
+
+	version := strings.TrimPrefix(p.ServingVersion, "v")
+
+	if version == DefaultServingVersion {
+		return p.legacyComponents()
+	}
+
+	major, err := p.majorVersion(version)
+	if err != nil {
+		return nil, err
+	}
+
+	// Releases starting with 1.0 are tagged as 'knative-v1.x.y'
+	tag := "v" + version
+	if major >= 1 {
+		tag = "knative-v" + version
+	}
+
+	servingURL := "https://github.com/knative/serving/releases/download/" + tag
+
+	components := []InstallProfileComponent{
+		{"Knative Serving CRDs", InstallationAsset{Version: version, URL: servingURL + "/serving-crds.yaml"}, "", 0},
+		{"Knative Serving", InstallationAsset{Version: version, URL: servingURL + "/serving-core.yaml"}, servingNs, 1},
+	}
+
+	switch p.Net {
+	case NetIstio:
+		netURL := "https://github.com/knative/net-istio/releases/download/" + tag
+		components = append(components, []InstallProfileComponent{
+			{"Istio", InstallationAsset{Version: version, URL: netURL + "/istio.yaml"}, ctling.NewIstio().SystemNamespaceName(), 1},
+			{"Knative Istio controller", InstallationAsset{Version: version, URL: netURL + "/net-istio.yaml"}, servingNs, 1},
+		}...)
+
+	case NetKourier:
+		netURL := "https://github.com/knative/net-kourier/releases/download/" + tag
+		components = append(components, InstallProfileComponent{
+			"Kourier", InstallationAsset{Version: version, URL: netURL + "/kourier.yaml"}, ctling.NewKourier().SystemNamespaceName(), 1,
+		})
+	}
+
+	if p.Eventing {
+		eventingURL := "https://github.com/knative/eventing/releases/download/" + tag
+		components = append(components, []InstallProfileComponent{
+			{"Knative Eventing CRDs", InstallationAsset{Version: version, URL: eventingURL + "/eventing-crds.yaml"}, "", 0},
+			{"Knative Eventing", InstallationAsset{Version: version, URL: eventingURL + "/eventing-core.yaml"}, eventingNs, 1},
+		}...)
+	}
+
+	return components, nil
+}
+
+func (p InstallProfile) legacyComponents() ([]InstallProfileComponent, error) {
+	if p.Net != NetIstio {
+		return nil, fmt.Errorf("Expected networking layer to be '%s' for Knative Serving %s", NetIstio, DefaultServingVersion)
+	}
+
+	knativeAsset := InstallKnativeFullAsset
+
+	if p.ExcludeMonitoring {
+		knativeAsset = InstallKnativeNoMonAsset
+	}
+
+	components := []InstallProfileComponent{
+		{"Istio", InstallIstioAsset, ctling.NewIstio().SystemNamespaceName(), 1},
+		{"Knative", knativeAsset, servingNs, 0},
+	}
+
+	if p.Eventing {
+		components = append(components, InstallProfileComponent{"Knative Eventing", InstallKnativeEventingAsset, eventingNs, 1})
+	}
+
+	return components, nil
+}
+
+func (InstallProfile) majorVersion(version string) (int, error) {
+	major, err := strconv.Atoi(strings.SplitN(version, ".", 2)[0])
+	if err != nil {
+		return 0, fmt.Errorf("Expected Knative Serving version '%s' to be in format 'X.Y.Z'", version)
+	}
+	return major, nil
+}
@@ -0,0 +1,69 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package knative_test
+
+import (
+	"testing"
+
+	. "github.com/cppforlife/knctl/pkg/knctl/cmd"
+	. "github.com/cppforlife/knctl/pkg/knctl/cmd/knative"
+)
+
+func TestInstallProfile_DefaultVersion(t *testing.T) {
+	components, err := InstallProfile{ServingVersion: DefaultServingVersion, Net: NetIstio, Eventing: true}.Components()
+	if err != nil {
+		t.Fatalf("Expected profile to succeed: %s", err)
+	}
+
+	DeepEqual(t, components, []InstallProfileComponent{
+		{"Istio", InstallIstioAsset, "istio-system", 1},
+		{"Knative", InstallKnativeFullAsset, "knative-serving", 0},
+		{"Knative Eventing", InstallKnativeEventingAsset, "knative-eventing", 1},
+	})
+}
+
+func TestInstallProfile_DefaultVersionRequiresIstio(t *testing.T) {
+	_, err := InstallProfile{ServingVersion: DefaultServingVersion, Net: NetKourier}.Components()
+	if err == nil {
+		t.Fatalf("Expected profile to fail")
+	}
+}
+
+func TestInstallProfile_NewerVersionWithKourier(t *testing.T) {
+	components, err := InstallProfile{ServingVersion: "v1.2.0", Net: NetKourier}.Components()
+	if err != nil {
+		t.Fatalf("Expected profile to succeed: %s", err)
+	}
+
+	var urls []string
+	for _, c := range components {
+		urls = append(urls, c.Asset.URL)
+	}
+
+	DeepEqual(t, urls, []string{
+		"https://github.com/knative/serving/releases/download/knative-v1.2.0/serving-crds.yaml",
+		"https://github.com/knative/serving/releases/download/knative-v1.2.0/serving-core.yaml",
+		"https://github.com/knative/net-kourier/releases/download/knative-v1.2.0/kourier.yaml",
+	})
+}
+
+func TestInstallProfile_InvalidNet(t *testing.T) {
+	_, err := InstallProfile{ServingVersion: "0.20.0", Net: "contour"}.Components()
+	if err == nil {
+		t.Fatalf("Expected profile to fail")
+	}
+}
@@ -38,6 +38,9 @@ func TestNewInstallCmd_Ok(t *testing.T) {
 	DeepEqual(t, realCmd.NodePorts, true)
 	DeepEqual(t, realCmd.ExcludeMonitoring, true)
 	DeepEqual(t, realCmd.VersionCheck, true)
+	DeepEqual(t, realCmd.ServingVersion, DefaultServingVersion)
+	DeepEqual(t, realCmd.Net, NetIstio)
+	DeepEqual(t, realCmd.Eventing, false)
 }
 
 func TestNewInstallCmd_OkLongFlagNames(t *testing.T) {
@@ -47,12 +50,18 @@ func TestNewInstallCmd_OkLongFlagNames(t *testing.T) {
 		"--node-ports",
 		"--exclude-monitoring",
 		"--version-check=false",
+		"--serving-version", "1.2.0",
+		"--net", "kourier",
+		"--eventing",
 	})
 	cmd.ExpectReachesExecution()
 
 	DeepEqual(t, realCmd.NodePorts, true)
 	DeepEqual(t, realCmd.ExcludeMonitoring, true)
 	DeepEqual(t, realCmd.VersionCheck, false)
+	DeepEqual(t, realCmd.ServingVersion, "1.2.0")
+	DeepEqual(t, realCmd.Net, NetKourier)
+	DeepEqual(t, realCmd.Eventing, true)
 }
 
 func TestNewInstallCmd_OkMinimum(t *testing.T) {
@@ -70,7 +79,7 @@ func TestNewInstallCmd_CheckInstallationAssets(t *testing.T) {
 	assets := []InstallationAsset{InstallIstioAsset, InstallKnativeFullAsset, InstallKnativeNoMonAsset}
 
 	for _, asset := range assets {
-		source := YAMLSource{asset, false}
+		source := YAMLSource{asset, false, nil}
 
 		content, err := source.Content()
 		if err != nil {
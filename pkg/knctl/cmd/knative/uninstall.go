@@ -34,6 +34,7 @@ type UninstallOptions struct {
 	depsFactory cmdcore.DepsFactory
 
 	ExcludeMonitoring bool
+	Purge             bool
 }
 
 func NewUninstallOptions(ui ui.UI, depsFactory cmdcore.DepsFactory) *UninstallOptions {
@@ -46,12 +47,21 @@ func NewUninstallCmd(o *UninstallOptions, flagsFactory cmdcore.FlagsFactory) *co
 		Short: "Uninstall Knative and Istio",
 		Long: `Uninstall Knative and Istio. 
 
-Requires 'kubectl' command installed on a the system.`,
+Fails if Knative services remain in any namespace unless --purge flag is provided.
+With --purge, user workloads are deleted first (while Knative controllers are still
+running to process finalizers) and then Knative and networking components are removed.`,
+		Example: `
+  # Uninstall Knative and Istio (fails if Knative services exist)
+  knctl uninstall
+
+  # Delete all Knative services and builds, then uninstall Knative and Istio
+  knctl uninstall --purge`,
 		Annotations: map[string]string{
 			cmdcore.SystemHelpGroup.Key: cmdcore.SystemHelpGroup.Value,
 		},
 		RunE: func(_ *cobra.Command, _ []string) error { return o.Run() },
 	}
+	cmd.Flags().BoolVar(&o.Purge, "purge", false, "Delete Knative services and builds in all namespaces before uninstalling")
 	return cmd
 }
 
@@ -61,13 +71,39 @@ func (o *UninstallOptions) Run() error {
 		return err
 	}
 
-	istio := ctling.NewIstio()
+	servingClient, err := o.depsFactory.ServingClient()
+	if err != nil {
+		return err
+	}
+
+	buildClient, err := o.depsFactory.BuildClient()
+	if err != nil {
+		return err
+	}
+
+	workloads := UserWorkloads{servingClient, buildClient, o.ui}
+
+	if o.Purge {
+		err = workloads.Delete()
+		if err != nil {
+			return err
+		}
+	} else {
+		err = workloads.ExpectNone()
+		if err != nil {
+			return err
+		}
+	}
 
+	// Order matters: controllers that may hold finalizers on resources
+	// in other components are removed after components they manage
 	components := []UninstallationComponent{
+		{"Knative Eventing", NamespaceRemoval{eventingNs, coreClient}, o.ui},
 		{"Knative Build", NamespaceRemoval{"knative-build", coreClient}, o.ui},
-		{"Knative Serving", NamespaceRemoval{"knative-serving", coreClient}, o.ui},
+		{"Knative Serving", NamespaceRemoval{servingNs, coreClient}, o.ui},
 		{"Knative Monitoring", NamespaceRemoval{"knative-monitoring", coreClient}, o.ui},
-		{"Istio", NamespaceRemoval{istio.SystemNamespaceName(), coreClient}, o.ui},
+		{"Kourier", NamespaceRemoval{ctling.NewKourier().SystemNamespaceName(), coreClient}, o.ui},
+		{"Istio", NamespaceRemoval{ctling.NewIstio().SystemNamespaceName(), coreClient}, o.ui},
 	}
 
 	for _, c := range components {
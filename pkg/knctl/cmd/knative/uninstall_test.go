@@ -0,0 +1,44 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package knative_test
+
+import (
+	"testing"
+
+	. "github.com/cppforlife/knctl/pkg/knctl/cmd"
+	cmdcore "github.com/cppforlife/knctl/pkg/knctl/cmd/core"
+	. "github.com/cppforlife/knctl/pkg/knctl/cmd/knative"
+)
+
+func TestNewUninstallCmd_Ok(t *testing.T) {
+	realCmd := NewUninstallOptions(nil, cmdcore.NewDepsFactory())
+	cmd := NewTestCmd(t, NewUninstallCmd(realCmd, cmdcore.FlagsFactory{}))
+	cmd.ExpectBasicConfig()
+	cmd.Execute([]string{"--purge"})
+	cmd.ExpectReachesExecution()
+
+	DeepEqual(t, realCmd.Purge, true)
+}
+
+func TestNewUninstallCmd_OkMinimum(t *testing.T) {
+	realCmd := NewUninstallOptions(nil, cmdcore.NewDepsFactory())
+	cmd := NewTestCmd(t, NewUninstallCmd(realCmd, cmdcore.FlagsFactory{}))
+	cmd.Execute([]string{})
+	cmd.ExpectReachesExecution()
+
+	DeepEqual(t, realCmd.Purge, false)
+}
@@ -0,0 +1,134 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package knative
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cppforlife/go-cli-ui/ui"
+	buildclientset "github.com/knative/build/pkg/client/clientset/versioned"
+	servingclientset "github.com/knative/serving/pkg/client/clientset/versioned"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// UserWorkloads represents Knative resources created by users
+// across all namespaces (services and builds)
+type UserWorkloads struct {
+	servingClient servingclientset.Interface
+	buildClient   buildclientset.Interface
+	ui            ui.UI
+}
+
+func (w UserWorkloads) ExpectNone() error {
+	counts, err := w.serviceCountsByNamespace()
+	if err != nil {
+		return err
+	}
+
+	if len(counts) == 0 {
+		return nil
+	}
+
+	var descs []string
+
+	for _, ns := range w.sortedKeys(counts) {
+		descs = append(descs, fmt.Sprintf("%s (%d)", ns, counts[ns]))
+	}
+
+	return fmt.Errorf("Expected no Knative services to exist, but found services in namespaces: %s "+
+		"(delete them or use --purge)", strings.Join(descs, ", "))
+}
+
+func (w UserWorkloads) Delete() error {
+	counts, err := w.serviceCountsByNamespace()
+	if err != nil {
+		return err
+	}
+
+	for _, ns := range w.sortedKeys(counts) {
+		w.ui.PrintLinef("Deleting %d Knative service(s) in namespace '%s'", counts[ns], ns)
+
+		err := w.servingClient.ServingV1alpha1().Services(ns).DeleteCollection(&metav1.DeleteOptions{}, metav1.ListOptions{})
+		if err != nil {
+			return fmt.Errorf("Deleting Knative services in namespace '%s': %s", ns, err)
+		}
+	}
+
+	builds, err := w.buildClient.BuildV1alpha1().Builds("").List(metav1.ListOptions{})
+	if err != nil {
+		// Build CRDs may not be installed
+		if !errors.IsNotFound(err) {
+			return fmt.Errorf("Listing builds: %s", err)
+		}
+	} else {
+		for _, build := range builds.Items {
+			err := w.buildClient.BuildV1alpha1().Builds(build.Namespace).Delete(build.Name, &metav1.DeleteOptions{})
+			if err != nil && !errors.IsNotFound(err) {
+				return fmt.Errorf("Deleting build '%s/%s': %s", build.Namespace, build.Name, err)
+			}
+		}
+	}
+
+	w.ui.PrintLinef("Waiting for Knative services to be deleted...")
+
+	for i := 0; i < 600; i++ {
+		counts, err := w.serviceCountsByNamespace()
+		if err != nil {
+			return err
+		}
+
+		if len(counts) == 0 {
+			return nil
+		}
+
+		time.Sleep(500 * time.Millisecond)
+	}
+
+	return fmt.Errorf("Expected Knative services to be deleted")
+}
+
+func (w UserWorkloads) serviceCountsByNamespace() (map[string]int, error) {
+	services, err := w.servingClient.ServingV1alpha1().Services("").List(metav1.ListOptions{})
+	if err != nil {
+		// Serving CRDs may not be installed
+		if errors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("Listing Knative services: %s", err)
+	}
+
+	counts := map[string]int{}
+
+	for _, svc := range services.Items {
+		counts[svc.Namespace]++
+	}
+
+	return counts, nil
+}
+
+func (UserWorkloads) sortedKeys(counts map[string]int) []string {
+	var keys []string
+	for k, _ := range counts {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,193 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package knativeconfig
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// ConfigValueType determines how config map values are validated
+type ConfigValueType string
+
+const (
+	ConfigValueString   ConfigValueType = "string"
+	ConfigValueBool     ConfigValueType = "bool"
+	ConfigValueInt      ConfigValueType = "int"
+	ConfigValueFloat    ConfigValueType = "float"
+	ConfigValueDuration ConfigValueType = "duration"
+	ConfigValueQuantity ConfigValueType = "quantity"
+	ConfigValueEnum     ConfigValueType = "enum"
+)
+
+type ConfigKey struct {
+	Type ConfigValueType
+	// Values lists allowed values for enum type
+	Values []string
+}
+
+var (
+	featureValues = []string{"enabled", "disabled", "allowed"}
+	logLevels     = []string{"debug", "info", "warn", "error", "dpanic", "panic", "fatal"}
+
+	// KnownConfigKeys lists keys of knative-serving config maps by config map name.
+	// Keys ending with '*' match any key with that prefix.
+	KnownConfigKeys = map[string]map[string]ConfigKey{
+		"config-autoscaler": {
+			"container-concurrency-target-percentage": {Type: ConfigValueFloat},
+			"container-concurrency-target-default":    {Type: ConfigValueFloat},
+			"requests-per-second-target-default":      {Type: ConfigValueFloat},
+			"target-burst-capacity":                   {Type: ConfigValueFloat},
+			"stable-window":                           {Type: ConfigValueDuration},
+			"panic-window":                            {Type: ConfigValueDuration},
+			"panic-window-percentage":                 {Type: ConfigValueFloat},
+			"panic-threshold-percentage":              {Type: ConfigValueFloat},
+			"max-scale-up-rate":                       {Type: ConfigValueFloat},
+			"max-scale-down-rate":                     {Type: ConfigValueFloat},
+			"enable-scale-to-zero":                    {Type: ConfigValueBool},
+			"scale-to-zero-grace-period":              {Type: ConfigValueDuration},
+			"scale-to-zero-pod-retention-period":      {Type: ConfigValueDuration},
+			"tick-interval":                           {Type: ConfigValueDuration},
+			"pod-autoscaler-class":                    {Type: ConfigValueString},
+			"activator-capacity":                      {Type: ConfigValueFloat},
+			"initial-scale":                           {Type: ConfigValueInt},
+			"allow-zero-initial-scale":                {Type: ConfigValueBool},
+			"max-scale":                               {Type: ConfigValueInt},
+		},
+		"config-defaults": {
+			"revision-timeout-seconds":     {Type: ConfigValueInt},
+			"max-revision-timeout-seconds": {Type: ConfigValueInt},
+			"revision-cpu-request":         {Type: ConfigValueQuantity},
+			"revision-memory-request":      {Type: ConfigValueQuantity},
+			"revision-cpu-limit":           {Type: ConfigValueQuantity},
+			"revision-memory-limit":        {Type: ConfigValueQuantity},
+			"container-name-template":      {Type: ConfigValueString},
+			"container-concurrency":        {Type: ConfigValueInt},
+		},
+		"config-deployment": {
+			"queueSidecarImage":              {Type: ConfigValueString},
+			"registriesSkippingTagResolving": {Type: ConfigValueString},
+			"progressDeadline":               {Type: ConfigValueDuration},
+		},
+		"config-features": {
+			"multi-container":                     {Type: ConfigValueEnum, Values: featureValues},
+			"kubernetes.podspec-affinity":         {Type: ConfigValueEnum, Values: featureValues},
+			"kubernetes.podspec-fieldref":         {Type: ConfigValueEnum, Values: featureValues},
+			"kubernetes.podspec-nodeselector":     {Type: ConfigValueEnum, Values: featureValues},
+			"kubernetes.podspec-tolerations":      {Type: ConfigValueEnum, Values: featureValues},
+			"kubernetes.podspec-volumes-emptydir": {Type: ConfigValueEnum, Values: featureValues},
+			"kubernetes.podspec-runtimeclassname": {Type: ConfigValueEnum, Values: featureValues},
+			"kubernetes.podspec-securitycontext":  {Type: ConfigValueEnum, Values: featureValues},
+			"kubernetes.podspec-init-containers":  {Type: ConfigValueEnum, Values: featureValues},
+			"tag-header-based-routing":            {Type: ConfigValueEnum, Values: featureValues},
+		},
+		"config-gc": {
+			"stale-revision-create-delay":        {Type: ConfigValueDuration},
+			"stale-revision-timeout":             {Type: ConfigValueDuration},
+			"stale-revision-minimum-generations": {Type: ConfigValueInt},
+			"stale-revision-lastpinned-debounce": {Type: ConfigValueDuration},
+		},
+		"config-logging": {
+			"zap-logger-config": {Type: ConfigValueString},
+			"loglevel.*":        {Type: ConfigValueEnum, Values: logLevels},
+		},
+		"config-network": {
+			"istio.sidecar.includeOutboundIPRanges": {Type: ConfigValueString},
+			"clusteringress.class":                  {Type: ConfigValueString},
+			"ingress.class":                         {Type: ConfigValueString},
+			"certificate.class":                     {Type: ConfigValueString},
+			"domainTemplate":                        {Type: ConfigValueString},
+			"tagTemplate":                           {Type: ConfigValueString},
+			"autoTLS":                               {Type: ConfigValueEnum, Values: []string{"Enabled", "Disabled"}},
+			"httpProtocol":                          {Type: ConfigValueEnum, Values: []string{"Enabled", "Disabled", "Redirected"}},
+		},
+		"config-observability": {
+			"logging.enable-var-log-collection":           {Type: ConfigValueBool},
+			"logging.revision-url-template":               {Type: ConfigValueString},
+			"metrics.backend-destination":                 {Type: ConfigValueEnum, Values: []string{"prometheus", "stackdriver", "opencensus"}},
+			"metrics.request-metrics-backend-destination": {Type: ConfigValueEnum, Values: []string{"prometheus", "stackdriver", "opencensus"}},
+			"metrics.stackdriver-project-id":              {Type: ConfigValueString},
+			"profiling.enable":                            {Type: ConfigValueBool},
+		},
+	}
+)
+
+// ConfigMapNames returns names of config maps that can be edited
+func ConfigMapNames() []string {
+	var names []string
+	for name := range KnownConfigKeys {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ValidateConfigValue checks that key is known for given config map and that value matches its type
+func ValidateConfigValue(configMapName, key, val string) error {
+	keys, found := KnownConfigKeys[configMapName]
+	if !found {
+		return fmt.Errorf("Expected config map to be one of: %s", strings.Join(ConfigMapNames(), ", "))
+	}
+
+	configKey, found := keys[key]
+	if !found {
+		for pattern, patternKey := range keys {
+			if strings.HasSuffix(pattern, "*") && strings.HasPrefix(key, strings.TrimSuffix(pattern, "*")) {
+				configKey, found = patternKey, true
+				break
+			}
+		}
+	}
+
+	if !found {
+		return fmt.Errorf("Expected key '%s' to be a known key of config map '%s' "+
+			"(use --allow-unknown-keys to set it anyway)", key, configMapName)
+	}
+
+	var err error
+
+	switch configKey.Type {
+	case ConfigValueBool:
+		_, err = strconv.ParseBool(val)
+	case ConfigValueInt:
+		_, err = strconv.Atoi(val)
+	case ConfigValueFloat:
+		_, err = strconv.ParseFloat(val, 64)
+	case ConfigValueDuration:
+		_, err = time.ParseDuration(val)
+	case ConfigValueQuantity:
+		_, err = resource.ParseQuantity(val)
+	case ConfigValueEnum:
+		for _, allowedVal := range configKey.Values {
+			if val == allowedVal {
+				return nil
+			}
+		}
+		return fmt.Errorf("Expected value of key '%s' to be one of: %s", key, strings.Join(configKey.Values, ", "))
+	}
+
+	if err != nil {
+		return fmt.Errorf("Expected value of key '%s' to be a valid %s: %s", key, configKey.Type, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,54 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package knativeconfig_test
+
+import (
+	"strings"
+	"testing"
+
+	. "github.com/cppforlife/knctl/pkg/knctl/cmd/knativeconfig"
+)
+
+func TestValidateConfigValue(t *testing.T) {
+	examples := []struct {
+		ConfigMap string
+		Key       string
+		Value     string
+		Err       string
+	}{
+		{"config-autoscaler", "stable-window", "120s", ""},
+		{"config-autoscaler", "stable-window", "120", "Expected value of key 'stable-window' to be a valid duration"},
+		{"config-autoscaler", "enable-scale-to-zero", "false", ""},
+		{"config-autoscaler", "enable-scale-to-zero", "no", "Expected value of key 'enable-scale-to-zero' to be a valid bool"},
+		{"config-autoscaler", "unknown-key", "1", "Expected key 'unknown-key' to be a known key of config map 'config-autoscaler'"},
+		{"config-logging", "loglevel.controller", "debug", ""},
+		{"config-unknown", "key", "val", "Expected config map to be one of: "},
+	}
+
+	for _, ex := range examples {
+		err := ValidateConfigValue(ex.ConfigMap, ex.Key, ex.Value)
+		if len(ex.Err) == 0 {
+			if err != nil {
+				t.Fatalf("Expected no error for %s/%s=%s: %s", ex.ConfigMap, ex.Key, ex.Value, err)
+			}
+			continue
+		}
+		if err == nil || !strings.HasPrefix(err.Error(), ex.Err) {
+			t.Fatalf("Expected error '%s' for %s/%s=%s, but was: %v", ex.Err, ex.ConfigMap, ex.Key, ex.Value, err)
+		}
+	}
+}
@@ -0,0 +1,90 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package knativeconfig
+
+import (
+	"fmt"
+	"sort"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/util/retry"
+)
+
+const (
+	servingNs = "knative-serving"
+)
+
+type ConfigChange struct {
+	Key      string
+	Previous string
+	Current  string
+	Existed  bool
+}
+
+type ConfigMaps struct {
+	coreClient kubernetes.Interface
+}
+
+func NewConfigMaps(coreClient kubernetes.Interface) ConfigMaps {
+	return ConfigMaps{coreClient}
+}
+
+// Set updates config map keys unless dryRun is specified
+// and returns changes in the order of keys
+func (c ConfigMaps) Set(name string, values map[string]string, dryRun bool) ([]ConfigChange, error) {
+	configMaps := c.coreClient.CoreV1().ConfigMaps(servingNs)
+
+	var changes []ConfigChange
+
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		configMap, err := configMaps.Get(name, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+
+		if configMap.Data == nil {
+			configMap.Data = map[string]string{}
+		}
+
+		changes = nil
+
+		for key, val := range values {
+			prevVal, found := configMap.Data[key]
+			if found && prevVal == val {
+				continue
+			}
+
+			changes = append(changes, ConfigChange{Key: key, Previous: prevVal, Current: val, Existed: found})
+			configMap.Data[key] = val
+		}
+
+		if dryRun || len(changes) == 0 {
+			return nil
+		}
+
+		_, err = configMaps.Update(configMap)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Updating config map '%s/%s': %s", servingNs, name, err)
+	}
+
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Key < changes[j].Key })
+
+	return changes, nil
+}
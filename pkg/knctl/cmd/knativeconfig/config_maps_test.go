@@ -0,0 +1,73 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package knativeconfig_test
+
+import (
+	"reflect"
+	"testing"
+
+	. "github.com/cppforlife/knctl/pkg/knctl/cmd/knativeconfig"
+	"github.com/cppforlife/knctl/pkg/knctl/testkit"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestConfigMapsSet(t *testing.T) {
+	cluster := testkit.NewCluster(t, &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "knative-serving", Name: "config-autoscaler"},
+		Data:       map[string]string{"stable-window": "60s", "max-scale-up-rate": "10"},
+	})
+	configMaps := NewConfigMaps(cluster.CoreClient())
+
+	values := map[string]string{"stable-window": "120s", "max-scale-up-rate": "10", "panic-window": "6s"}
+	expectedChanges := []ConfigChange{
+		{Key: "panic-window", Current: "6s"},
+		{Key: "stable-window", Previous: "60s", Current: "120s", Existed: true},
+	}
+
+	changes, err := configMaps.Set("config-autoscaler", values, true)
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+	if !reflect.DeepEqual(changes, expectedChanges) {
+		t.Fatalf("Expected changes to match: %#v", changes)
+	}
+
+	configMap, err := cluster.CoreClient().CoreV1().ConfigMaps("knative-serving").Get("config-autoscaler", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+	if configMap.Data["stable-window"] != "60s" {
+		t.Fatalf("Expected diff to not apply changes: %#v", configMap.Data)
+	}
+
+	changes, err = configMaps.Set("config-autoscaler", values, false)
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+	if !reflect.DeepEqual(changes, expectedChanges) {
+		t.Fatalf("Expected changes to match: %#v", changes)
+	}
+
+	configMap, err = cluster.CoreClient().CoreV1().ConfigMaps("knative-serving").Get("config-autoscaler", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+	if !reflect.DeepEqual(configMap.Data, values) {
+		t.Fatalf("Expected changes to be applied: %#v", configMap.Data)
+	}
+}
@@ -0,0 +1,98 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package knativeconfig
+
+import (
+	"github.com/cppforlife/go-cli-ui/ui"
+	uitable "github.com/cppforlife/go-cli-ui/ui/table"
+	cmdcore "github.com/cppforlife/knctl/pkg/knctl/cmd/core"
+	ctlknconfig "github.com/cppforlife/knctl/pkg/knctl/knativeconfig"
+	"github.com/spf13/cobra"
+)
+
+type FeaturesOptions struct {
+	ui          ui.UI
+	depsFactory cmdcore.DepsFactory
+}
+
+func NewFeaturesOptions(ui ui.UI, depsFactory cmdcore.DepsFactory) *FeaturesOptions {
+	return &FeaturesOptions{ui: ui, depsFactory: depsFactory}
+}
+
+func NewFeaturesCmd(o *FeaturesOptions, flagsFactory cmdcore.FlagsFactory) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "features",
+		Short: "List optional Knative Serving features",
+		Long: `List optional Knative Serving features and whether they are enabled.
+
+Features are read from 'config-features' and 'config-network' config maps (in 'knative-serving' namespace).
+Use 'knctl knative-config set' to enable or disable them.`,
+		Example: `
+  # List features
+  knctl knative-config features`,
+		RunE: func(_ *cobra.Command, _ []string) error { return o.Run() },
+	}
+	return cmd
+}
+
+func (o *FeaturesOptions) Run() error {
+	coreClient, err := o.depsFactory.CoreClient()
+	if err != nil {
+		return err
+	}
+
+	features, err := ctlknconfig.NewFeatures(coreClient).List()
+	if err != nil {
+		return err
+	}
+
+	table := uitable.Table{
+		Content: "features",
+
+		Header: []uitable.Header{
+			uitable.NewHeader("Name"),
+			uitable.NewHeader("Description"),
+			uitable.NewHeader("Config map"),
+			uitable.NewHeader("Value"),
+			uitable.NewHeader("Enabled"),
+		},
+
+		SortBy: []uitable.ColumnSort{
+			{Column: 2, Asc: true},
+			{Column: 0, Asc: true},
+		},
+	}
+
+	for _, feature := range features {
+		val := feature.Value
+		if feature.IsDefault {
+			val += " (default)"
+		}
+
+		table.Rows = append(table.Rows, []uitable.Value{
+			uitable.NewValueString(feature.Name),
+			uitable.NewValueString(feature.Description),
+			uitable.NewValueString(feature.ConfigMap),
+			uitable.NewValueString(val),
+			uitable.NewValueBool(feature.Enabled),
+		})
+	}
+
+	o.ui.PrintTable(table)
+
+	return nil
+}
@@ -0,0 +1,42 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package knativeconfig_test
+
+import (
+	"testing"
+
+	"github.com/cppforlife/go-cli-ui/ui"
+	. "github.com/cppforlife/knctl/pkg/knctl/cmd"
+	cmdcore "github.com/cppforlife/knctl/pkg/knctl/cmd/core"
+	. "github.com/cppforlife/knctl/pkg/knctl/cmd/knativeconfig"
+	"github.com/cppforlife/knctl/pkg/knctl/testkit"
+)
+
+func TestNewFeaturesCmd_Ok(t *testing.T) {
+	realCmd := NewFeaturesOptions(nil, cmdcore.NewDepsFactory())
+	cmd := NewTestCmd(t, NewFeaturesCmd(realCmd, cmdcore.FlagsFactory{}))
+	cmd.ExpectBasicConfig()
+	cmd.Execute([]string{})
+	cmd.ExpectReachesExecution()
+}
+
+func TestFeaturesOptions_RunWithoutConfigMaps(t *testing.T) {
+	err := NewFeaturesOptions(ui.NewNoopUI(), testkit.NewCluster(t).DepsFactory()).Run()
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+}
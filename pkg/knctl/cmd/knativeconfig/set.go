@@ -0,0 +1,142 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package knativeconfig
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cppforlife/go-cli-ui/ui"
+	uitable "github.com/cppforlife/go-cli-ui/ui/table"
+	cmdcore "github.com/cppforlife/knctl/pkg/knctl/cmd/core"
+	"github.com/spf13/cobra"
+)
+
+type SetOptions struct {
+	ui          ui.UI
+	depsFactory cmdcore.DepsFactory
+
+	ConfigMap        string
+	KeyValues        []string
+	Diff             bool
+	AllowUnknownKeys bool
+}
+
+func NewSetOptions(ui ui.UI, depsFactory cmdcore.DepsFactory) *SetOptions {
+	return &SetOptions{ui: ui, depsFactory: depsFactory}
+}
+
+func NewSetCmd(o *SetOptions, flagsFactory cmdcore.FlagsFactory) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "set CONFIG-MAP KEY=VALUE...",
+		Short: "Set keys of Knative Serving config map",
+		Long: `Set keys of Knative Serving config map (in 'knative-serving' namespace).
+
+Keys and values are validated against known keys of each config map.
+Changed keys are shown before being applied; use --diff to only show them.
+
+Config maps: ` + strings.Join(ConfigMapNames(), ", "),
+		Example: `
+  # Set stable window used by autoscaler
+  knctl knative-config set config-autoscaler stable-window=120s
+
+  # Show what would change without applying changes
+  knctl knative-config set config-autoscaler stable-window=120s enable-scale-to-zero=false --diff`,
+		Args: cobra.MinimumNArgs(2),
+		RunE: func(_ *cobra.Command, args []string) error {
+			o.ConfigMap = args[0]
+			o.KeyValues = args[1:]
+			return o.Run()
+		},
+	}
+	cmd.Flags().BoolVar(&o.Diff, "diff", false, "Show changes without applying them")
+	cmd.Flags().BoolVar(&o.AllowUnknownKeys, "allow-unknown-keys", false, "Allow setting keys that are not known to knctl")
+	return cmd
+}
+
+func (o *SetOptions) Run() error {
+	values, err := o.values()
+	if err != nil {
+		return err
+	}
+
+	coreClient, err := o.depsFactory.CoreClient()
+	if err != nil {
+		return err
+	}
+
+	changes, err := NewConfigMaps(coreClient).Set(o.ConfigMap, values, o.Diff)
+	if err != nil {
+		return err
+	}
+
+	title := fmt.Sprintf("Changes to config map '%s'", o.ConfigMap)
+	if o.Diff {
+		title += " (not applied)"
+	}
+
+	table := uitable.Table{
+		Title:   title,
+		Content: "changes",
+
+		Header: []uitable.Header{
+			uitable.NewHeader("Key"),
+			uitable.NewHeader("Previous"),
+			uitable.NewHeader("Current"),
+		},
+	}
+
+	for _, change := range changes {
+		prevVal := uitable.NewValueString(change.Previous)
+		if !change.Existed {
+			prevVal = uitable.NewValueString("(not set)")
+		}
+
+		table.Rows = append(table.Rows, []uitable.Value{
+			uitable.NewValueString(change.Key),
+			prevVal,
+			uitable.NewValueString(change.Current),
+		})
+	}
+
+	o.ui.PrintTable(table)
+
+	return nil
+}
+
+func (o *SetOptions) values() (map[string]string, error) {
+	values := map[string]string{}
+
+	for _, kv := range o.KeyValues {
+		pieces := strings.SplitN(kv, "=", 2)
+		if len(pieces) != 2 || len(pieces[0]) == 0 {
+			return nil, fmt.Errorf("Expected key value '%s' to be in format 'key=value'", kv)
+		}
+
+		err := ValidateConfigValue(o.ConfigMap, pieces[0], pieces[1])
+		if err != nil {
+			// Config map name is always validated
+			if !o.AllowUnknownKeys || !strings.HasPrefix(err.Error(), "Expected key") {
+				return nil, err
+			}
+		}
+
+		values[pieces[0]] = pieces[1]
+	}
+
+	return values, nil
+}
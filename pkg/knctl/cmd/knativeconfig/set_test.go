@@ -0,0 +1,70 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package knativeconfig_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/cppforlife/go-cli-ui/ui"
+	. "github.com/cppforlife/knctl/pkg/knctl/cmd"
+	cmdcore "github.com/cppforlife/knctl/pkg/knctl/cmd/core"
+	. "github.com/cppforlife/knctl/pkg/knctl/cmd/knativeconfig"
+	"github.com/cppforlife/knctl/pkg/knctl/testkit"
+)
+
+func TestNewSetCmd_Ok(t *testing.T) {
+	realCmd := NewSetOptions(nil, cmdcore.NewDepsFactory())
+	cmd := NewTestCmd(t, NewSetCmd(realCmd, cmdcore.FlagsFactory{}))
+	cmd.ExpectBasicConfig()
+	cmd.Execute([]string{"config-autoscaler", "stable-window=120s", "panic-window=6s", "--diff", "--allow-unknown-keys"})
+	cmd.ExpectReachesExecution()
+
+	DeepEqual(t, realCmd.Diff, true)
+	DeepEqual(t, realCmd.AllowUnknownKeys, true)
+}
+
+func TestNewSetCmd_RequiresArgs(t *testing.T) {
+	realCmd := NewSetOptions(nil, cmdcore.NewDepsFactory())
+	cmd := NewTestCmd(t, NewSetCmd(realCmd, cmdcore.FlagsFactory{}))
+	cmd.Execute([]string{"config-autoscaler"})
+	cmd.ExpectErr("requires at least 2 arg(s), only received 1")
+}
+
+func TestSetOptions_RunValidation(t *testing.T) {
+	examples := []struct {
+		Opts SetOptions
+		Err  string
+	}{
+		{SetOptions{ConfigMap: "config-autoscaler", KeyValues: []string{"stable-window"}}, "Expected key value 'stable-window' to be in format 'key=value'"},
+		{SetOptions{ConfigMap: "config-autoscaler", KeyValues: []string{"unknown=1"}}, "Expected key 'unknown' to be a known key"},
+		{SetOptions{ConfigMap: "config-unknown", KeyValues: []string{"key=1"}, AllowUnknownKeys: true}, "Expected config map to be one of"},
+		{SetOptions{ConfigMap: "config-autoscaler", KeyValues: []string{"unknown=1"}, AllowUnknownKeys: true}, "Updating config map"},
+	}
+
+	for _, ex := range examples {
+		opts := NewSetOptions(ui.NewNoopUI(), testkit.NewCluster(t).DepsFactory())
+		opts.ConfigMap = ex.Opts.ConfigMap
+		opts.KeyValues = ex.Opts.KeyValues
+		opts.AllowUnknownKeys = ex.Opts.AllowUnknownKeys
+
+		err := opts.Run()
+		if err == nil || !strings.HasPrefix(err.Error(), ex.Err) {
+			t.Fatalf("Expected error '%s', but was: %v", ex.Err, err)
+		}
+	}
+}
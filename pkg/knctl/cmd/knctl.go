@@ -20,21 +20,45 @@ import (
 	"fmt"
 	"io"
 	"strings"
+	"time"
 
 	"github.com/cppforlife/go-cli-ui/ui"
+	cmdacc "github.com/cppforlife/knctl/pkg/knctl/cmd/access"
+	cmdautoscaler "github.com/cppforlife/knctl/pkg/knctl/cmd/autoscaler"
 	cmdbas "github.com/cppforlife/knctl/pkg/knctl/cmd/basicauthsecret"
 	cmdbld "github.com/cppforlife/knctl/pkg/knctl/cmd/build"
+	cmdbulk "github.com/cppforlife/knctl/pkg/knctl/cmd/bulk"
+	cmdbundle "github.com/cppforlife/knctl/pkg/knctl/cmd/bundle"
+	cmdcache "github.com/cppforlife/knctl/pkg/knctl/cmd/cache"
+	cmdconf "github.com/cppforlife/knctl/pkg/knctl/cmd/config"
+	cmdconformance "github.com/cppforlife/knctl/pkg/knctl/cmd/conformance"
 	cmdcore "github.com/cppforlife/knctl/pkg/knctl/cmd/core"
 	cmddom "github.com/cppforlife/knctl/pkg/knctl/cmd/domain"
+	cmdegress "github.com/cppforlife/knctl/pkg/knctl/cmd/egress"
 	cmding "github.com/cppforlife/knctl/pkg/knctl/cmd/ingress"
+	cmdinv "github.com/cppforlife/knctl/pkg/knctl/cmd/inventory"
+	cmdistio "github.com/cppforlife/knctl/pkg/knctl/cmd/istio"
+	cmdjob "github.com/cppforlife/knctl/pkg/knctl/cmd/job"
 	cmdkn "github.com/cppforlife/knctl/pkg/knctl/cmd/knative"
+	cmdknconfig "github.com/cppforlife/knctl/pkg/knctl/cmd/knativeconfig"
+	cmdlimits "github.com/cppforlife/knctl/pkg/knctl/cmd/limits"
+	cmdmtls "github.com/cppforlife/knctl/pkg/knctl/cmd/mtls"
+	cmdns "github.com/cppforlife/knctl/pkg/knctl/cmd/namespace"
 	cmdpod "github.com/cppforlife/knctl/pkg/knctl/cmd/pod"
 	cmdrev "github.com/cppforlife/knctl/pkg/knctl/cmd/revision"
 	cmdrte "github.com/cppforlife/knctl/pkg/knctl/cmd/route"
+	cmdscaffold "github.com/cppforlife/knctl/pkg/knctl/cmd/scaffold"
+	cmdscale "github.com/cppforlife/knctl/pkg/knctl/cmd/scale"
+	cmdserve "github.com/cppforlife/knctl/pkg/knctl/cmd/serve"
 	cmdsvc "github.com/cppforlife/knctl/pkg/knctl/cmd/service"
 	cmdsa "github.com/cppforlife/knctl/pkg/knctl/cmd/serviceaccount"
 	cmdsas "github.com/cppforlife/knctl/pkg/knctl/cmd/sshauthsecret"
+	cmdtelemetry "github.com/cppforlife/knctl/pkg/knctl/cmd/telemetry"
+	cmdtest "github.com/cppforlife/knctl/pkg/knctl/cmd/test"
 	"github.com/cppforlife/knctl/pkg/knctl/cobrautil"
+	"github.com/cppforlife/knctl/pkg/knctl/logger"
+	"github.com/cppforlife/knctl/pkg/knctl/telemetry"
+	"github.com/cppforlife/knctl/pkg/knctl/util"
 	"github.com/spf13/cobra"
 )
 
@@ -45,6 +69,13 @@ type KnctlOptions struct {
 
 	UIFlags         cmdcore.UIFlags
 	KubeconfigFlags cmdcore.KubeconfigFlags
+	CacheFlags      cmdcore.CacheFlags
+	DebugFlags      cmdcore.DebugFlags
+	TransportFlags  cmdcore.TransportFlags
+	ManifestFlags   cmdcore.ManifestFlags
+
+	// Path of the command being executed (used for labeling created objects)
+	commandPath string
 }
 
 func NewKnctlOptions(ui *ui.ConfUI, configFactory cmdcore.ConfigFactory, depsFactory cmdcore.DepsFactory) *KnctlOptions {
@@ -97,25 +128,67 @@ Knative docs: https://github.com/knative/docs.`,
 
 	o.configFactory.ConfigurePathResolver(o.KubeconfigFlags.Path.Value)
 	o.configFactory.ConfigureContextResolver(o.KubeconfigFlags.Context.Value)
+	o.configFactory.ConfigureClusterResolver(o.KubeconfigFlags.ClusterValue)
+	o.configFactory.ConfigureAuthOverridesResolver(o.KubeconfigFlags.AuthOverrides)
+	o.configFactory.ConfigureRequestRetriesResolver(o.KubeconfigFlags.RequestRetriesValue)
+
+	o.CacheFlags.Set(cmd, flagsFactory)
+	o.depsFactory.ConfigureCacheTTLResolver(o.CacheFlags.TTLValue)
+
+	o.DebugFlags.Set(cmd, flagsFactory)
+	o.configFactory.ConfigureLoggerResolver(o.DebugFlags.Logger)
+	o.depsFactory.ConfigureLoggerResolver(o.DebugFlags.Logger)
+
+	o.TransportFlags.Set(cmd, flagsFactory)
+	o.configFactory.ConfigureTransportResolver(o.TransportFlags.TransportConfig)
+	o.depsFactory.ConfigureTransportResolver(o.TransportFlags.TransportConfig)
+
+	o.ManifestFlags.Set(cmd, flagsFactory)
+	o.configFactory.ConfigureManifestWriterResolver(o.ManifestFlags.WriterResolver(uiBlockWriter{o.ui}))
 
-	cmd.AddCommand(NewVersionCmd(NewVersionOptions(o.ui), flagsFactory))
+	o.configFactory.ConfigureCommandResolver(func() (string, error) {
+		return cmdcore.CommandLabelValue(o.commandPath), nil
+	})
+
+	cmd.AddCommand(NewVersionCmd(NewVersionOptions(o.ui, o.depsFactory), flagsFactory))
+	cmd.AddCommand(NewUpdateCmd(NewUpdateOptions(o.ui, o.depsFactory), flagsFactory))
+	cmd.AddCommand(NewSchemaCmd(NewSchemaOptions(o.ui), flagsFactory))
 
 	// Knative
 	cmd.AddCommand(cmdkn.NewInstallCmd(cmdkn.NewInstallOptions(o.ui, o.depsFactory, &o.KubeconfigFlags), flagsFactory))
 	cmd.AddCommand(cmdkn.NewUninstallCmd(cmdkn.NewUninstallOptions(o.ui, o.depsFactory), flagsFactory))
+	cmd.AddCommand(cmdkn.NewDoctorCmd(cmdkn.NewDoctorOptions(o.ui, o.depsFactory), flagsFactory))
 
 	serviceCmd := cmdsvc.NewCmd()
-	serviceCmd.AddCommand(cmdsvc.NewListCmd(cmdsvc.NewListOptions(o.ui, o.depsFactory), flagsFactory))
+	serviceCmd.AddCommand(cmdsvc.NewListCmd(cmdsvc.NewListOptions(o.ui, o.configFactory, o.depsFactory), flagsFactory))
 	serviceCmd.AddCommand(cmdsvc.NewShowCmd(cmdsvc.NewShowOptions(o.ui, o.depsFactory), flagsFactory))
 	serviceCmd.AddCommand(cmdsvc.NewDeleteCmd(cmdsvc.NewDeleteOptions(o.ui, o.depsFactory), flagsFactory))
 	serviceCmd.AddCommand(cmdsvc.NewAnnotateCmd(cmdsvc.NewAnnotateOptions(o.ui, o.depsFactory), flagsFactory))
 	serviceCmd.AddCommand(cmdsvc.NewOpenCmd(cmdsvc.NewOpenOptions(o.ui, o.depsFactory), flagsFactory))
 	serviceCmd.AddCommand(cmdsvc.NewURLCmd(cmdsvc.NewURLOptions(o.ui, o.depsFactory), flagsFactory))
+	serviceCmd.AddCommand(cmdsvc.NewCopyCmd(cmdsvc.NewCopyOptions(o.ui, o.configFactory, o.depsFactory), flagsFactory))
+	serviceCmd.AddCommand(cmdsvc.NewPauseCmd(cmdsvc.NewPauseOptions(o.ui, o.depsFactory), flagsFactory))
+	serviceCmd.AddCommand(cmdsvc.NewResumeCmd(cmdsvc.NewResumeOptions(o.ui, o.depsFactory), flagsFactory))
 	cmd.AddCommand(serviceCmd)
 
-	cmd.AddCommand(cmdsvc.NewDeployCmd(cmdsvc.NewDeployOptions(o.ui, o.configFactory, o.depsFactory), flagsFactory))
+	cmd.AddCommand(cmdsvc.NewGetCmd(cmdsvc.NewGetOptions(o.ui, o.depsFactory), flagsFactory))
+	cmd.AddCommand(cmdsvc.NewStatusCmd(cmdsvc.NewStatusOptions(o.ui, o.depsFactory), flagsFactory))
+	cmd.AddCommand(cmdsvc.NewHistoryCmd(cmdsvc.NewHistoryOptions(o.ui, o.depsFactory), flagsFactory))
+	cmd.AddCommand(cmdsvc.NewUndoCmd(cmdsvc.NewUndoOptions(o.ui, o.depsFactory), flagsFactory))
+	cmd.AddCommand(cmdsvc.NewApproveCmd(cmdsvc.NewApproveOptions(o.ui, o.depsFactory), flagsFactory))
+	cmd.AddCommand(cmdscaffold.NewInitCmd(cmdscaffold.NewInitOptions(o.ui), flagsFactory))
+	cmd.AddCommand(cmdsvc.NewDeployCmd(cmdsvc.NewDeployOptions(o.ui, o.configFactory, o.depsFactory, cmdcore.CancelSignals{}), flagsFactory))
+	cmd.AddCommand(cmdsvc.NewDevCmd(cmdsvc.NewDevOptions(o.ui, o.configFactory, o.depsFactory, cmdcore.CancelSignals{}), flagsFactory))
+	cmd.AddCommand(cmdsvc.NewRunLocalCmd(cmdsvc.NewRunLocalOptions(o.ui, o.depsFactory, cmdcore.CancelSignals{}), flagsFactory))
 	cmd.AddCommand(cmdsvc.NewLogsCmd(cmdsvc.NewLogsOptions(o.ui, o.depsFactory, cmdcore.CancelSignals{}), flagsFactory))
-	cmd.AddCommand(cmdsvc.NewCurlCmd(cmdsvc.NewCurlOptions(o.ui, o.depsFactory), flagsFactory))
+	cmd.AddCommand(cmdsvc.NewCurlCmd(cmdsvc.NewCurlOptions(o.ui, o.configFactory, o.depsFactory), flagsFactory))
+	cmd.AddCommand(cmdsvc.NewTopCmd(cmdsvc.NewTopOptions(o.ui, o.depsFactory), flagsFactory))
+	cmd.AddCommand(cmdsvc.NewCostCmd(cmdsvc.NewCostOptions(o.ui, o.depsFactory), flagsFactory))
+	cmd.AddCommand(cmdsvc.NewPromoteCmd(cmdsvc.NewPromoteOptions(o.ui, o.configFactory, o.depsFactory), flagsFactory))
+
+	scaleCmd := cmdscale.NewCmd()
+	scaleCmd.AddCommand(cmdscale.NewScheduleCmd(cmdscale.NewScheduleOptions(o.ui, o.depsFactory), flagsFactory))
+	cmd.AddCommand(scaleCmd)
 
 	revisionCmd := cmdrev.NewCmd()
 	revisionCmd.AddCommand(cmdrev.NewListCmd(cmdrev.NewListOptions(o.ui, o.depsFactory), flagsFactory))
@@ -123,18 +196,62 @@ Knative docs: https://github.com/knative/docs.`,
 	revisionCmd.AddCommand(cmdrev.NewDeleteCmd(cmdrev.NewDeleteOptions(o.ui, o.depsFactory), flagsFactory))
 	revisionCmd.AddCommand(cmdrev.NewTagCmd(cmdrev.NewTagOptions(o.ui, o.depsFactory), flagsFactory))
 	revisionCmd.AddCommand(cmdrev.NewUntagCmd(cmdrev.NewUntagOptions(o.ui, o.depsFactory), flagsFactory))
+	revisionCmd.AddCommand(cmdrev.NewPinCmd(cmdrev.NewPinOptions(o.ui, o.depsFactory), flagsFactory))
+	revisionCmd.AddCommand(cmdrev.NewUnpinCmd(cmdrev.NewUnpinOptions(o.ui, o.depsFactory), flagsFactory))
 	revisionCmd.AddCommand(cmdrev.NewAnnotateCmd(cmdrev.NewAnnotateOptions(o.ui, o.depsFactory), flagsFactory))
 	cmd.AddCommand(revisionCmd)
 
+	cmd.AddCommand(cmdrev.NewExplainCmd(cmdrev.NewExplainOptions(o.ui, o.depsFactory), flagsFactory))
+
 	routeCmd := cmdrte.NewCmd()
 	routeCmd.AddCommand(cmdrte.NewShowCmd(cmdrte.NewShowOptions(o.ui, o.depsFactory), flagsFactory))
 	routeCmd.AddCommand(cmdrte.NewListCmd(cmdrte.NewListOptions(o.ui, o.depsFactory), flagsFactory))
 	routeCmd.AddCommand(cmdrte.NewDeleteCmd(cmdrte.NewDeleteOptions(o.ui, o.depsFactory), flagsFactory))
 	routeCmd.AddCommand(cmdrte.NewCurlCmd(cmdrte.NewCurlOptions(o.ui, o.depsFactory), flagsFactory))
 	routeCmd.AddCommand(cmdrte.NewAnnotateCmd(cmdrte.NewAnnotateOptions(o.ui, o.depsFactory), flagsFactory))
+	routeCmd.AddCommand(cmdrte.NewMatchCmd(cmdrte.NewMatchOptions(o.ui, o.depsFactory), flagsFactory))
 	cmd.AddCommand(routeCmd)
 
-	cmd.AddCommand(cmdrte.NewCreateCmd(cmdrte.NewCreateOptions(o.ui, o.depsFactory), flagsFactory))
+	rolloutCmd := cmdrte.NewCreateCmd(cmdrte.NewCreateOptions(o.ui, o.depsFactory), flagsFactory)
+	rolloutCmd.AddCommand(cmdrte.NewMirrorCmd(cmdrte.NewMirrorOptions(o.ui, o.depsFactory), flagsFactory))
+	rolloutCmd.AddCommand(cmdrte.NewVerifyCmd(cmdrte.NewVerifyOptions(o.ui, o.depsFactory), flagsFactory))
+	cmd.AddCommand(rolloutCmd)
+
+	faultCmd := cmdrte.NewFaultCmd()
+	faultCmd.AddCommand(cmdrte.NewFaultInjectCmd(cmdrte.NewFaultInjectOptions(o.ui, o.depsFactory), flagsFactory))
+	faultCmd.AddCommand(cmdrte.NewFaultClearCmd(cmdrte.NewFaultClearOptions(o.ui, o.depsFactory), flagsFactory))
+	cmd.AddCommand(faultCmd)
+
+	limitsCmd := cmdlimits.NewCmd()
+	limitsCmd.AddCommand(cmdlimits.NewSetCmd(cmdlimits.NewSetOptions(o.ui, o.depsFactory), flagsFactory))
+	limitsCmd.AddCommand(cmdlimits.NewShowCmd(cmdlimits.NewShowOptions(o.ui, o.depsFactory), flagsFactory))
+	cmd.AddCommand(limitsCmd)
+
+	egressCmd := cmdegress.NewCmd()
+	egressCmd.AddCommand(cmdegress.NewAllowCmd(cmdegress.NewAllowOptions(o.ui, o.depsFactory), flagsFactory))
+	cmd.AddCommand(egressCmd)
+
+	mtlsCmd := cmdmtls.NewCmd()
+	mtlsCmd.AddCommand(cmdmtls.NewStatusCmd(cmdmtls.NewStatusOptions(o.ui, o.depsFactory), flagsFactory))
+	cmd.AddCommand(mtlsCmd)
+
+	autoscalerCmd := cmdautoscaler.NewCmd()
+	autoscalerCmd.AddCommand(cmdautoscaler.NewInspectCmd(cmdautoscaler.NewInspectOptions(o.ui, o.depsFactory), flagsFactory))
+	autoscalerCmd.AddCommand(cmdautoscaler.NewSetCmd(cmdautoscaler.NewSetOptions(o.ui, o.depsFactory), flagsFactory))
+	autoscalerCmd.AddCommand(cmdautoscaler.NewStatusCmd(cmdautoscaler.NewStatusOptions(o.ui, o.depsFactory), flagsFactory))
+	cmd.AddCommand(autoscalerCmd)
+
+	testCmd := cmdtest.NewCmd()
+	testCmd.AddCommand(cmdtest.NewScaleToZeroCmd(cmdtest.NewScaleToZeroOptions(o.ui, o.depsFactory), flagsFactory))
+	cmd.AddCommand(testCmd)
+
+	cmd.AddCommand(cmdbundle.NewExportCmd(cmdbundle.NewExportOptions(o.ui, o.depsFactory), flagsFactory))
+	cmd.AddCommand(cmdbundle.NewApplyCmd(cmdbundle.NewApplyOptions(o.ui, o.depsFactory), flagsFactory))
+	cmd.AddCommand(cmdbundle.NewDiffCmd(cmdbundle.NewDiffOptions(o.ui, o.depsFactory), flagsFactory))
+	cmd.AddCommand(cmdbundle.NewValidateCmd(cmdbundle.NewValidateOptions(o.ui), flagsFactory))
+	cmd.AddCommand(cmdbulk.NewBulkCmd(cmdbulk.NewBulkOptions(o.ui, o.configFactory, o.depsFactory, cmdcore.CancelSignals{}), flagsFactory))
+	cmd.AddCommand(cmdserve.NewServeCmd(cmdserve.NewServeOptions(o.ui, o.configFactory, o.depsFactory, cmdcore.CancelSignals{}), flagsFactory))
+	cmd.AddCommand(cmdconformance.NewConformanceCmd(cmdconformance.NewConformanceOptions(o.ui, o.configFactory, o.depsFactory, cmdcore.CancelSignals{}), flagsFactory))
 
 	buildCmd := cmdbld.NewCmd()
 	buildCmd.AddCommand(cmdbld.NewCreateCmd(cmdbld.NewCreateOptions(o.ui, o.configFactory, o.depsFactory), flagsFactory))
@@ -146,20 +263,43 @@ Knative docs: https://github.com/knative/docs.`,
 	domainCmd := cmddom.NewCmd()
 	domainCmd.AddCommand(cmddom.NewCreateCmd(cmddom.NewCreateOptions(o.ui, o.depsFactory), flagsFactory))
 	domainCmd.AddCommand(cmddom.NewListCmd(cmddom.NewListOptions(o.ui, o.depsFactory), flagsFactory))
+	domainCmd.AddCommand(cmddom.NewWaitCmd(cmddom.NewWaitOptions(o.ui, o.depsFactory), flagsFactory))
+	domainCmd.AddCommand(cmddom.NewUseMagicDNSCmd(cmddom.NewUseMagicDNSOptions(o.ui, o.depsFactory), flagsFactory))
 	cmd.AddCommand(domainCmd)
 
+	namespaceCmd := cmdns.NewCmd()
+	namespaceCmd.AddCommand(cmdns.NewCreateCmd(cmdns.NewCreateOptions(o.ui, o.depsFactory), flagsFactory))
+	namespaceCmd.AddCommand(cmdns.NewListCmd(cmdns.NewListOptions(o.ui, o.depsFactory), flagsFactory))
+	namespaceCmd.AddCommand(cmdns.NewDeleteCmd(cmdns.NewDeleteOptions(o.ui, o.depsFactory), flagsFactory))
+	cmd.AddCommand(namespaceCmd)
+
+	configCmd := cmdconf.NewCmd()
+	configCmd.AddCommand(cmdconf.NewUseContextCmd(cmdconf.NewUseContextOptions(o.ui, o.configFactory), flagsFactory))
+	cmd.AddCommand(configCmd)
+
 	cmd.AddCommand(cmddom.NewDNSMapCmd(cmddom.NewDNSMapOptions(o.ui, o.depsFactory), flagsFactory))
 
 	ingressCmd := cmding.NewCmd()
 	ingressCmd.AddCommand(cmding.NewListCmd(cmding.NewListOptions(o.ui, o.depsFactory), flagsFactory))
 	cmd.AddCommand(ingressCmd)
 
+	istioCmd := cmdistio.NewCmd()
+	istioCmd.AddCommand(cmdistio.NewStatusCmd(cmdistio.NewStatusOptions(o.ui, o.depsFactory), flagsFactory))
+	cmd.AddCommand(istioCmd)
+
+	knativeConfigCmd := cmdknconfig.NewCmd()
+	knativeConfigCmd.AddCommand(cmdknconfig.NewSetCmd(cmdknconfig.NewSetOptions(o.ui, o.depsFactory), flagsFactory))
+	knativeConfigCmd.AddCommand(cmdknconfig.NewFeaturesCmd(cmdknconfig.NewFeaturesOptions(o.ui, o.depsFactory), flagsFactory))
+	cmd.AddCommand(knativeConfigCmd)
+
 	podCmd := cmdpod.NewCmd()
 	podCmd.AddCommand(cmdpod.NewListCmd(cmdpod.NewListOptions(o.ui, o.depsFactory), flagsFactory))
 	cmd.AddCommand(podCmd)
 
 	serviceAccountCmd := cmdsa.NewCmd()
 	serviceAccountCmd.AddCommand(cmdsa.NewCreateCmd(cmdsa.NewCreateOptions(o.ui, o.depsFactory), flagsFactory))
+	serviceAccountCmd.AddCommand(cmdsa.NewListCmd(cmdsa.NewListOptions(o.ui, o.depsFactory), flagsFactory))
+	serviceAccountCmd.AddCommand(cmdsa.NewBindCmd(cmdsa.NewBindOptions(o.ui, o.depsFactory), flagsFactory))
 	cmd.AddCommand(serviceAccountCmd)
 
 	basicAuthSecretCmd := cmdbas.NewCmd()
@@ -170,6 +310,20 @@ Knative docs: https://github.com/knative/docs.`,
 	sshAuthSecretCmd.AddCommand(cmdsas.NewCreateCmd(cmdsas.NewCreateOptions(o.ui, o.depsFactory), flagsFactory))
 	cmd.AddCommand(sshAuthSecretCmd)
 
+	cmd.AddCommand(cmdacc.NewCanICmd(cmdacc.NewCanIOptions(o.ui, o.depsFactory), flagsFactory))
+	cmd.AddCommand(cmdinv.NewInventoryCmd(cmdinv.NewInventoryOptions(o.ui, o.depsFactory), flagsFactory))
+	cmd.AddCommand(cmdjob.NewJobCmd(cmdjob.NewJobOptions(o.ui, o.depsFactory), flagsFactory))
+
+	cacheCmd := cmdcache.NewCmd()
+	cacheCmd.AddCommand(cmdcache.NewClearCmd(cmdcache.NewClearOptions(o.ui), flagsFactory))
+	cmd.AddCommand(cacheCmd)
+
+	telemetryCmd := cmdtelemetry.NewCmd()
+	telemetryCmd.AddCommand(cmdtelemetry.NewStatusCmd(cmdtelemetry.NewStatusOptions(o.ui), flagsFactory))
+	telemetryCmd.AddCommand(cmdtelemetry.NewOnCmd(cmdtelemetry.NewOnOptions(o.ui), flagsFactory))
+	telemetryCmd.AddCommand(cmdtelemetry.NewOffCmd(cmdtelemetry.NewOffOptions(o.ui), flagsFactory))
+	cmd.AddCommand(telemetryCmd)
+
 	// Last one runs first
 	cobrautil.VisitCommands(cmd, reconfigureCmdWithSubcmd)
 	cobrautil.VisitCommands(cmd, reconfigureLeafCmd)
@@ -179,11 +333,39 @@ Knative docs: https://github.com/knative/docs.`,
 		return nil
 	}))
 
+	cobrautil.VisitCommands(cmd, cobrautil.WrapRunEForCmd(func(cmd *cobra.Command, _ []string) error {
+		o.commandPath = cmd.CommandPath()
+		return nil
+	}))
+
 	cobrautil.VisitCommands(cmd, cobrautil.WrapRunEForCmd(cobrautil.ResolveFlagsForCmd))
 
+	// Stops SSH tunnel (if any) after command finishes
+	cobrautil.VisitCommands(cmd, cobrautil.DeferForCmd(o.TransportFlags.Close))
+
+	// Records usage only if user opted in via 'knctl telemetry on'
+	cobrautil.VisitCommands(cmd, cobrautil.ObserveForCmd(o.recordTelemetry))
+
+	// Suggests similar names when resource is not found (e.g. due to a typo)
+	cobrautil.VisitCommands(cmd, cobrautil.MapErrorForCmd(NotFoundSuggestions{o.depsFactory}.Annotate))
+
 	return cmd
 }
 
+func (o *KnctlOptions) recordTelemetry(cmd *cobra.Command, duration time.Duration, cmdErr error) {
+	log, _ := o.DebugFlags.Logger()
+
+	configPath, err := telemetry.DefaultConfigPath()
+	if err == nil {
+		recorder := telemetry.NewRecorder(configPath, Version, o.depsFactory.HTTPClient)
+		err = recorder.Record(telemetry.NewEvent(cmd.CommandPath(), duration, cmdErr))
+	}
+
+	if err != nil {
+		log.WithTag("telemetry").Debug("skipped", logger.Fields{"err": err})
+	}
+}
+
 func reconfigureCmdWithSubcmd(cmd *cobra.Command) {
 	if len(cmd.Commands()) == 0 {
 		return
@@ -230,12 +412,30 @@ func ShowSubcommands(cmd *cobra.Command, args []string) error {
 	for _, subcmd := range cmd.Commands() {
 		strs = append(strs, subcmd.Use)
 	}
-	return fmt.Errorf("Use one of available subcommands: %s", strings.Join(strs, ", "))
+	return fmt.Errorf("Use one of available subcommands: %s%s",
+		strings.Join(strs, ", "), suggestSubcommands(cmd, args))
 }
 
 func ShowHelp(cmd *cobra.Command, args []string) error {
 	cmd.Help()
-	return fmt.Errorf("Invalid command - see available commands/subcommands above")
+	return fmt.Errorf("Invalid command - see available commands/subcommands above%s", suggestSubcommands(cmd, args))
+}
+
+// suggestSubcommands returns names of subcommands close to the first
+// argument, which is most likely a mistyped subcommand name
+func suggestSubcommands(cmd *cobra.Command, args []string) string {
+	if len(args) == 0 {
+		return ""
+	}
+
+	var names []string
+	for _, subcmd := range cmd.Commands() {
+		if subcmd.IsAvailableCommand() {
+			names = append(names, subcmd.Name())
+		}
+	}
+
+	return util.DidYouMean(util.ClosestMatches(args[0], names))
 }
 
 type uiBlockWriter struct {
@@ -145,6 +145,27 @@ func TestNewKnctlCmd_ValidateAllCommandArgs(t *testing.T) {
 	})
 }
 
+func TestNewKnctlCmd_RolloutRejectsUnknownSubcommand(t *testing.T) {
+	noopUI := ui.NewWrappingConfUI(ui.NewNoopUI(), ui.NewNoopLogger())
+	cmd := NewTestCmd(t, NewDefaultKnctlCmd(noopUI))
+	cmd.Execute([]string{"rollout", "bogus", "--route", "rt1", "-p", "svc1:latest=100%", "-n", "ns1"})
+	cmd.ExpectErr(`unknown command "bogus" for "knctl rollout"`)
+}
+
+func TestNewKnctlCmd_RolloutVerifyTypoDoesNotRollOut(t *testing.T) {
+	noopUI := ui.NewWrappingConfUI(ui.NewNoopUI(), ui.NewNoopLogger())
+	cmd := NewTestCmd(t, NewDefaultKnctlCmd(noopUI))
+	cmd.Execute([]string{"rollout", "verfy", "--route", "rt1", "-p", "svc1:latest=100%", "-n", "ns1"})
+	cmd.ExpectErr(`unknown command "verfy" for "knctl rollout"`)
+}
+
+func TestNewKnctlCmd_RolloutVerify(t *testing.T) {
+	noopUI := ui.NewWrappingConfUI(ui.NewNoopUI(), ui.NewNoopLogger())
+	cmd := NewTestCmd(t, NewDefaultKnctlCmd(noopUI))
+	cmd.Execute([]string{"rollout", "verify", "-s", "svc1", "-n", "ns1"})
+	cmd.ExpectReachesExecution()
+}
+
 func TestNewKnctlCmd_ValidateAllDocsCommandExamples(t *testing.T) {
 	const beginningDollar = "$ "
 	const trailingSlash = " \\"
@@ -0,0 +1,180 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package limits
+
+import (
+	"fmt"
+
+	"github.com/knative/serving/pkg/apis/serving/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+)
+
+const (
+	limitsServiceLabelKey  = "limits.cli.knative.dev/service"
+	limitsRevisionLabelKey = "limits.cli.knative.dev/revision"
+)
+
+var (
+	destinationRuleGVR = schema.GroupVersionResource{
+		Group:    "networking.istio.io",
+		Version:  "v1alpha3",
+		Resource: "destinationrules",
+	}
+)
+
+// Limits represent Istio connection pool and outlier detection settings;
+// zero values are left to Istio defaults
+type Limits struct {
+	MaxConnections           int64
+	MaxPendingRequests       int64
+	MaxRequestsPerConnection int64
+	ConsecutiveErrors        int64
+}
+
+// RevisionLimits manages Istio destination rules for revisions' Kubernetes services
+type RevisionLimits struct {
+	dynamicClient dynamic.Interface
+}
+
+func NewRevisionLimits(dynamicClient dynamic.Interface) RevisionLimits {
+	return RevisionLimits{dynamicClient}
+}
+
+// Set creates or replaces destination rule for a revision
+func (l RevisionLimits) Set(revision v1alpha1.Revision, serviceName string, limits Limits) error {
+	if len(revision.Status.ServiceName) == 0 {
+		return fmt.Errorf("Expected revision '%s' to have Kubernetes service", revision.Name)
+	}
+
+	rule := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "networking.istio.io/v1alpha3",
+		"kind":       "DestinationRule",
+		"metadata": map[string]interface{}{
+			"name":      l.ruleName(revision),
+			"namespace": revision.Namespace,
+			"labels": map[string]interface{}{
+				limitsServiceLabelKey:  serviceName,
+				limitsRevisionLabelKey: revision.Name,
+			},
+		},
+		"spec": map[string]interface{}{
+			"host":          fmt.Sprintf("%s.%s.svc.cluster.local", revision.Status.ServiceName, revision.Namespace),
+			"trafficPolicy": limits.trafficPolicy(),
+		},
+	}}
+
+	rules := l.dynamicClient.Resource(destinationRuleGVR).Namespace(revision.Namespace)
+
+	_, err := rules.Create(rule)
+	if err != nil {
+		if !errors.IsAlreadyExists(err) {
+			return fmt.Errorf("Creating destination rule '%s': %s", rule.GetName(), err)
+		}
+
+		existing, err := rules.Get(rule.GetName(), metav1.GetOptions{})
+		if err != nil {
+			return fmt.Errorf("Getting destination rule '%s': %s", rule.GetName(), err)
+		}
+
+		existing.SetLabels(rule.GetLabels())
+		existing.Object["spec"] = rule.Object["spec"]
+
+		_, err = rules.Update(existing)
+		if err != nil {
+			return fmt.Errorf("Updating destination rule '%s': %s", rule.GetName(), err)
+		}
+	}
+
+	return nil
+}
+
+// Get returns limits for a revision; revisions without destination rule have zero limits
+func (l RevisionLimits) Get(revision v1alpha1.Revision) (Limits, error) {
+	rule, err := l.dynamicClient.Resource(destinationRuleGVR).Namespace(revision.Namespace).Get(l.ruleName(revision), metav1.GetOptions{})
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return Limits{}, nil
+		}
+		return Limits{}, fmt.Errorf("Getting destination rule: %s", err)
+	}
+
+	policy, _, err := unstructured.NestedMap(rule.Object, "spec", "trafficPolicy")
+	if err != nil {
+		return Limits{}, fmt.Errorf("Reading destination rule '%s': %s", rule.GetName(), err)
+	}
+
+	return newLimitsFromTrafficPolicy(policy), nil
+}
+
+func (l RevisionLimits) ruleName(revision v1alpha1.Revision) string {
+	return revision.Name + "-limits"
+}
+
+func (l Limits) trafficPolicy() map[string]interface{} {
+	tcp := map[string]interface{}{}
+	http := map[string]interface{}{}
+	outlierDetection := map[string]interface{}{}
+
+	if l.MaxConnections > 0 {
+		tcp["maxConnections"] = l.MaxConnections
+	}
+	if l.MaxPendingRequests > 0 {
+		http["http1MaxPendingRequests"] = l.MaxPendingRequests
+	}
+	if l.MaxRequestsPerConnection > 0 {
+		http["maxRequestsPerConnection"] = l.MaxRequestsPerConnection
+	}
+	if l.ConsecutiveErrors > 0 {
+		outlierDetection["consecutiveErrors"] = l.ConsecutiveErrors
+	}
+
+	policy := map[string]interface{}{}
+	connectionPool := map[string]interface{}{}
+
+	if len(tcp) > 0 {
+		connectionPool["tcp"] = tcp
+	}
+	if len(http) > 0 {
+		connectionPool["http"] = http
+	}
+	if len(connectionPool) > 0 {
+		policy["connectionPool"] = connectionPool
+	}
+	if len(outlierDetection) > 0 {
+		policy["outlierDetection"] = outlierDetection
+	}
+
+	return policy
+}
+
+func newLimitsFromTrafficPolicy(policy map[string]interface{}) Limits {
+	int64Field := func(fields ...string) int64 {
+		val, _, _ := unstructured.NestedInt64(policy, fields...)
+		return val
+	}
+
+	return Limits{
+		MaxConnections:           int64Field("connectionPool", "tcp", "maxConnections"),
+		MaxPendingRequests:       int64Field("connectionPool", "http", "http1MaxPendingRequests"),
+		MaxRequestsPerConnection: int64Field("connectionPool", "http", "maxRequestsPerConnection"),
+		ConsecutiveErrors:        int64Field("outlierDetection", "consecutiveErrors"),
+	}
+}
@@ -0,0 +1,118 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package limits
+
+import (
+	"fmt"
+
+	"github.com/cppforlife/go-cli-ui/ui"
+	cmdcore "github.com/cppforlife/knctl/pkg/knctl/cmd/core"
+	cmdflags "github.com/cppforlife/knctl/pkg/knctl/cmd/flags"
+	"github.com/knative/serving/pkg/apis/serving"
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type SetOptions struct {
+	ui          ui.UI
+	depsFactory cmdcore.DepsFactory
+
+	ServiceFlags cmdflags.ServiceFlags
+
+	MaxConnections           int64
+	MaxPendingRequests       int64
+	MaxRequestsPerConnection int64
+	ConsecutiveErrors        int64
+}
+
+func NewSetOptions(ui ui.UI, depsFactory cmdcore.DepsFactory) *SetOptions {
+	return &SetOptions{ui: ui, depsFactory: depsFactory}
+}
+
+func NewSetCmd(o *SetOptions, flagsFactory cmdcore.FlagsFactory) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "set",
+		Short: "Set connection limits for service",
+		Long: `Set connection limits and circuit breaking for service's revisions via Istio destination rules.
+
+Previously set limits are replaced; unspecified limits use Istio defaults.
+Revisions created afterwards are not affected; re-run command if necessary.`,
+		Example: `
+  # Limit connections to revisions of service 'svc1' in namespace 'ns1'
+  knctl limits set -s svc1 --max-connections 100 --max-requests-per-connection 10 -n ns1
+
+  # Eject misbehaving containers after 5 consecutive errors
+  knctl limits set -s svc1 --consecutive-errors 5 -n ns1`,
+		RunE: func(_ *cobra.Command, _ []string) error { return o.Run() },
+	}
+	o.ServiceFlags.Set(cmd, flagsFactory)
+	cmd.Flags().Int64Var(&o.MaxConnections, "max-connections", 0, "Set maximum number of connections to a revision")
+	cmd.Flags().Int64Var(&o.MaxPendingRequests, "max-pending-requests", 0, "Set maximum number of pending requests to a revision")
+	cmd.Flags().Int64Var(&o.MaxRequestsPerConnection, "max-requests-per-connection", 0, "Set maximum number of requests per connection")
+	cmd.Flags().Int64Var(&o.ConsecutiveErrors, "consecutive-errors", 0, "Set number of consecutive errors before container is ejected from load balancing")
+	return cmd
+}
+
+func (o *SetOptions) Run() error {
+	limits := Limits{
+		MaxConnections:           o.MaxConnections,
+		MaxPendingRequests:       o.MaxPendingRequests,
+		MaxRequestsPerConnection: o.MaxRequestsPerConnection,
+		ConsecutiveErrors:        o.ConsecutiveErrors,
+	}
+
+	if limits.MaxConnections < 0 || limits.MaxPendingRequests < 0 ||
+		limits.MaxRequestsPerConnection < 0 || limits.ConsecutiveErrors < 0 {
+		return fmt.Errorf("Expected limits to be positive")
+	}
+
+	servingClient, err := o.depsFactory.ServingClient()
+	if err != nil {
+		return err
+	}
+
+	dynamicClient, err := o.depsFactory.DynamicClient()
+	if err != nil {
+		return err
+	}
+
+	listOpts := metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("%s=%s", serving.ServiceLabelKey, o.ServiceFlags.Name),
+	}
+
+	revisions, err := servingClient.ServingV1alpha1().Revisions(o.ServiceFlags.NamespaceFlags.Name).List(listOpts)
+	if err != nil {
+		return fmt.Errorf("Listing revisions: %s", err)
+	}
+
+	if len(revisions.Items) == 0 {
+		return fmt.Errorf("Expected service '%s' to have at least one revision", o.ServiceFlags.Name)
+	}
+
+	revisionLimits := NewRevisionLimits(dynamicClient)
+
+	for _, revision := range revisions.Items {
+		o.ui.PrintLinef("Setting limits for revision '%s'", revision.Name)
+
+		err := revisionLimits.Set(revision, o.ServiceFlags.Name, limits)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,137 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package limits_test
+
+import (
+	"testing"
+
+	"github.com/cppforlife/go-cli-ui/ui"
+	. "github.com/cppforlife/knctl/pkg/knctl/cmd"
+	cmdcore "github.com/cppforlife/knctl/pkg/knctl/cmd/core"
+	cmdflags "github.com/cppforlife/knctl/pkg/knctl/cmd/flags"
+	. "github.com/cppforlife/knctl/pkg/knctl/cmd/limits"
+	"github.com/cppforlife/knctl/pkg/knctl/testkit"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestNewSetCmd_Ok(t *testing.T) {
+	realCmd := NewSetOptions(nil, cmdcore.NewDepsFactory())
+	cmd := NewTestCmd(t, NewSetCmd(realCmd, cmdcore.FlagsFactory{}))
+	cmd.ExpectBasicConfig()
+	cmd.Execute([]string{
+		"-n", "test-namespace",
+		"-s", "test-service",
+		"--max-connections", "100",
+		"--max-pending-requests", "20",
+		"--max-requests-per-connection", "10",
+		"--consecutive-errors", "5",
+	})
+	cmd.ExpectReachesExecution()
+
+	DeepEqual(t, realCmd.ServiceFlags,
+		cmdflags.ServiceFlags{cmdcore.NamespaceFlags{"test-namespace"}, "test-service"})
+	DeepEqual(t, realCmd.MaxConnections, int64(100))
+	DeepEqual(t, realCmd.MaxPendingRequests, int64(20))
+	DeepEqual(t, realCmd.MaxRequestsPerConnection, int64(10))
+	DeepEqual(t, realCmd.ConsecutiveErrors, int64(5))
+}
+
+func TestNewSetCmd_RequiredFlags(t *testing.T) {
+	realCmd := NewSetOptions(nil, cmdcore.NewDepsFactory())
+	cmd := NewTestCmd(t, NewSetCmd(realCmd, cmdcore.FlagsFactory{}))
+	cmd.Execute([]string{})
+	cmd.ExpectRequiredFlags([]string{"service"})
+}
+
+func TestSetOptions_CreatesAndReplacesDestinationRules(t *testing.T) {
+	cluster := testkit.NewCluster(t,
+		testkit.NewRevision("ns1", "svc1", "svc1-00001").ServiceName("svc1-00001-service").Build(),
+		testkit.NewRevision("ns1", "svc2", "svc2-00001").ServiceName("svc2-00001-service").Build(),
+	)
+
+	opts := NewSetOptions(ui.NewNoopUI(), cluster.DepsFactory())
+	opts.ServiceFlags = cmdflags.ServiceFlags{cmdcore.NamespaceFlags{"ns1"}, "svc1"}
+	opts.MaxConnections = 100
+	opts.MaxRequestsPerConnection = 10
+
+	err := opts.Run()
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	dynamicClient, err := cluster.DepsFactory().DynamicClient()
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	rules := dynamicClient.Resource(schema.GroupVersionResource{
+		Group: "networking.istio.io", Version: "v1alpha3", Resource: "destinationrules"}).Namespace("ns1")
+
+	rule, err := rules.Get("svc1-00001-limits", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	DeepEqual(t, rule.Object["spec"], map[string]interface{}{
+		"host": "svc1-00001-service.ns1.svc.cluster.local",
+		"trafficPolicy": map[string]interface{}{
+			"connectionPool": map[string]interface{}{
+				"tcp":  map[string]interface{}{"maxConnections": int64(100)},
+				"http": map[string]interface{}{"maxRequestsPerConnection": int64(10)},
+			},
+		},
+	})
+
+	_, err = rules.Get("svc2-00001-limits", metav1.GetOptions{})
+	if err == nil {
+		t.Fatalf("Expected other service's revisions to not have limits")
+	}
+
+	opts.MaxConnections = 0
+	opts.MaxRequestsPerConnection = 0
+	opts.ConsecutiveErrors = 5
+
+	err = opts.Run()
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	revision, err := cluster.ServingClient().ServingV1alpha1().Revisions("ns1").Get("svc1-00001", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	limits, err := NewRevisionLimits(dynamicClient).Get(*revision)
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	DeepEqual(t, limits, Limits{ConsecutiveErrors: 5})
+}
+
+func TestSetOptions_RequiresRevisions(t *testing.T) {
+	cluster := testkit.NewCluster(t)
+
+	opts := NewSetOptions(ui.NewNoopUI(), cluster.DepsFactory())
+	opts.ServiceFlags = cmdflags.ServiceFlags{cmdcore.NamespaceFlags{"ns1"}, "svc1"}
+
+	err := opts.Run()
+	if err == nil || err.Error() != "Expected service 'svc1' to have at least one revision" {
+		t.Fatalf("Expected error, but was: %v", err)
+	}
+}
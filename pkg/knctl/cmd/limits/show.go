@@ -0,0 +1,122 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package limits
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/cppforlife/go-cli-ui/ui"
+	uitable "github.com/cppforlife/go-cli-ui/ui/table"
+	cmdcore "github.com/cppforlife/knctl/pkg/knctl/cmd/core"
+	cmdflags "github.com/cppforlife/knctl/pkg/knctl/cmd/flags"
+	"github.com/knative/serving/pkg/apis/serving"
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type ShowOptions struct {
+	ui          ui.UI
+	depsFactory cmdcore.DepsFactory
+
+	ServiceFlags cmdflags.ServiceFlags
+}
+
+func NewShowOptions(ui ui.UI, depsFactory cmdcore.DepsFactory) *ShowOptions {
+	return &ShowOptions{ui: ui, depsFactory: depsFactory}
+}
+
+func NewShowCmd(o *ShowOptions, flagsFactory cmdcore.FlagsFactory) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "show",
+		Short: "Show connection limits for service",
+		Example: `
+  # Show connection limits for revisions of service 'svc1' in namespace 'ns1'
+  knctl limits show -s svc1 -n ns1`,
+		RunE: func(_ *cobra.Command, _ []string) error { return o.Run() },
+	}
+	o.ServiceFlags.Set(cmd, flagsFactory)
+	return cmd
+}
+
+func (o *ShowOptions) Run() error {
+	servingClient, err := o.depsFactory.ServingClient()
+	if err != nil {
+		return err
+	}
+
+	dynamicClient, err := o.depsFactory.DynamicClient()
+	if err != nil {
+		return err
+	}
+
+	listOpts := metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("%s=%s", serving.ServiceLabelKey, o.ServiceFlags.Name),
+	}
+
+	revisions, err := servingClient.ServingV1alpha1().Revisions(o.ServiceFlags.NamespaceFlags.Name).List(listOpts)
+	if err != nil {
+		return fmt.Errorf("Listing revisions: %s", err)
+	}
+
+	table := uitable.Table{
+		Title: fmt.Sprintf("Connection limits for service '%s' in namespace '%s'",
+			o.ServiceFlags.Name, o.ServiceFlags.NamespaceFlags.Name),
+
+		Content: "revisions",
+
+		Header: []uitable.Header{
+			uitable.NewHeader("Revision"),
+			uitable.NewHeader("Max connections"),
+			uitable.NewHeader("Max pending requests"),
+			uitable.NewHeader("Max requests per connection"),
+			uitable.NewHeader("Consecutive errors"),
+		},
+
+		SortBy: []uitable.ColumnSort{
+			{Column: 0, Asc: true},
+		},
+	}
+
+	revisionLimits := NewRevisionLimits(dynamicClient)
+
+	for _, revision := range revisions.Items {
+		limits, err := revisionLimits.Get(revision)
+		if err != nil {
+			return err
+		}
+
+		table.Rows = append(table.Rows, []uitable.Value{
+			uitable.NewValueString(revision.Name),
+			o.limitValue(limits.MaxConnections),
+			o.limitValue(limits.MaxPendingRequests),
+			o.limitValue(limits.MaxRequestsPerConnection),
+			o.limitValue(limits.ConsecutiveErrors),
+		})
+	}
+
+	o.ui.PrintTable(table)
+
+	return nil
+}
+
+func (*ShowOptions) limitValue(val int64) uitable.ValueString {
+	if val == 0 {
+		return uitable.NewValueString("default")
+	}
+	return uitable.NewValueString(strconv.FormatInt(val, 10))
+}
@@ -0,0 +1,164 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mtls
+
+import (
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+)
+
+const (
+	MTLSModeStrict     = "STRICT"
+	MTLSModePermissive = "PERMISSIVE"
+	MTLSModeDisable    = "DISABLE"
+	mtlsModeUnset      = "UNSET"
+)
+
+var (
+	peerAuthenticationGVR = schema.GroupVersionResource{
+		Group:    "security.istio.io",
+		Version:  "v1beta1",
+		Resource: "peerauthentications",
+	}
+)
+
+type PeerAuthentication struct {
+	Namespace string
+	Name      string
+	Selector  map[string]string // nil for namespace (or mesh) wide policies
+	Mode      string
+}
+
+func (a PeerAuthentication) Description() string {
+	return fmt.Sprintf("%s/%s", a.Namespace, a.Name)
+}
+
+func (a PeerAuthentication) Matches(podLabels map[string]string) bool {
+	for k, v := range a.Selector {
+		if podLabels[k] != v {
+			return false
+		}
+	}
+	return true
+}
+
+// PeerAuthentications finds Istio peer authentication policies
+// that apply to workloads in a namespace
+type PeerAuthentications struct {
+	dynamicClient dynamic.Interface
+	rootNamespace string
+
+	namespacePolicies []PeerAuthentication
+	rootPolicies      []PeerAuthentication
+}
+
+func NewPeerAuthentications(dynamicClient dynamic.Interface, rootNamespace, namespace string) (*PeerAuthentications, bool, error) {
+	pas := &PeerAuthentications{dynamicClient: dynamicClient, rootNamespace: rootNamespace}
+
+	var err error
+
+	pas.namespacePolicies, err = pas.list(namespace)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			// Istio is not installed or is too old to support peer authentication
+			return pas, false, nil
+		}
+		return nil, false, fmt.Errorf("Listing peer authentications: %s", err)
+	}
+
+	if namespace != rootNamespace {
+		pas.rootPolicies, err = pas.list(rootNamespace)
+		if err != nil {
+			return nil, false, fmt.Errorf("Listing peer authentications: %s", err)
+		}
+	}
+
+	return pas, true, nil
+}
+
+// EffectiveMode returns mTLS mode for workload with given labels and policy that
+// determines it. Workload specific policies take precedence over namespace wide ones,
+// which take precedence over mesh wide ones; UNSET mode inherits from the parent.
+func (a *PeerAuthentications) EffectiveMode(podLabels map[string]string) (string, string) {
+	var workloadPolicies, namespacePolicies, meshPolicies []PeerAuthentication
+
+	for _, pa := range a.namespacePolicies {
+		if len(pa.Selector) > 0 {
+			if pa.Matches(podLabels) {
+				workloadPolicies = append(workloadPolicies, pa)
+			}
+		} else {
+			namespacePolicies = append(namespacePolicies, pa)
+		}
+	}
+
+	for _, pa := range a.rootPolicies {
+		if len(pa.Selector) == 0 {
+			meshPolicies = append(meshPolicies, pa)
+		}
+	}
+
+	for _, levelPolicies := range [][]PeerAuthentication{workloadPolicies, namespacePolicies, meshPolicies} {
+		for _, pa := range levelPolicies {
+			if pa.Mode != mtlsModeUnset {
+				return pa.Mode, pa.Description()
+			}
+		}
+	}
+
+	return MTLSModePermissive, "Istio default"
+}
+
+func (a *PeerAuthentications) list(namespace string) ([]PeerAuthentication, error) {
+	list, err := a.dynamicClient.Resource(peerAuthenticationGVR).Namespace(namespace).List(metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	var result []PeerAuthentication
+
+	for _, item := range list.Items {
+		selector, _, err := unstructured.NestedStringMap(item.Object, "spec", "selector", "matchLabels")
+		if err != nil {
+			return nil, fmt.Errorf("Reading peer authentication '%s': %s", item.GetName(), err)
+		}
+
+		mode, _, err := unstructured.NestedString(item.Object, "spec", "mtls", "mode")
+		if err != nil {
+			return nil, fmt.Errorf("Reading peer authentication '%s': %s", item.GetName(), err)
+		}
+
+		if len(mode) == 0 {
+			mode = mtlsModeUnset
+		}
+
+		result = append(result, PeerAuthentication{
+			Namespace: item.GetNamespace(),
+			Name:      item.GetName(),
+			Selector:  selector,
+			Mode:      strings.ToUpper(mode),
+		})
+	}
+
+	return result, nil
+}
@@ -0,0 +1,136 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mtls
+
+import (
+	"fmt"
+
+	cmdns "github.com/cppforlife/knctl/pkg/knctl/cmd/namespace"
+	"github.com/knative/serving/pkg/apis/serving"
+	servingclientset "github.com/knative/serving/pkg/client/clientset/versioned"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	servingNs              = "knative-serving"
+	activatorLabelSelector = "app=activator"
+	istioProxyContainer    = "istio-proxy"
+)
+
+type RevisionMTLS struct {
+	Revision        string
+	Mode            string
+	Source          string
+	Pods            int
+	PodsWithSidecar int
+	Warnings        []string
+}
+
+// RevisionMTLSStatuses determines effective mTLS mode of revisions' pods
+// and detects configurations that commonly break activator to pod traffic
+type RevisionMTLSStatuses struct {
+	coreClient    kubernetes.Interface
+	servingClient servingclientset.Interface
+	pas           *PeerAuthentications
+}
+
+func NewRevisionMTLSStatuses(coreClient kubernetes.Interface, servingClient servingclientset.Interface, pas *PeerAuthentications) RevisionMTLSStatuses {
+	return RevisionMTLSStatuses{coreClient, servingClient, pas}
+}
+
+func (s RevisionMTLSStatuses) List(namespace string) ([]RevisionMTLS, error) {
+	ns, err := s.coreClient.CoreV1().Namespaces().Get(namespace, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("Getting namespace: %s", err)
+	}
+
+	revisions, err := s.servingClient.ServingV1alpha1().Revisions(namespace).List(metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("Listing revisions: %s", err)
+	}
+
+	activatorPods, err := s.coreClient.CoreV1().Pods(servingNs).List(metav1.ListOptions{LabelSelector: activatorLabelSelector})
+	if err != nil {
+		return nil, fmt.Errorf("Listing activator pods: %s", err)
+	}
+
+	var result []RevisionMTLS
+
+	for _, revision := range revisions.Items {
+		listOpts := metav1.ListOptions{
+			LabelSelector: fmt.Sprintf("%s=%s", serving.RevisionLabelKey, revision.Name),
+		}
+
+		pods, err := s.coreClient.CoreV1().Pods(namespace).List(listOpts)
+		if err != nil {
+			return nil, fmt.Errorf("Listing pods: %s", err)
+		}
+
+		// Pods carry revision's labels; use them when revision is scaled to zero
+		podLabels := map[string]string{serving.RevisionLabelKey: revision.Name}
+		for k, v := range revision.Labels {
+			podLabels[k] = v
+		}
+		if len(pods.Items) > 0 {
+			podLabels = pods.Items[0].Labels
+		}
+
+		status := RevisionMTLS{Revision: revision.Name, Pods: len(pods.Items)}
+		status.Mode, status.Source = s.pas.EffectiveMode(podLabels)
+
+		for _, pod := range pods.Items {
+			if s.hasSidecar(pod) {
+				status.PodsWithSidecar++
+			}
+		}
+
+		if status.Mode == MTLSModeStrict {
+			if !cmdns.IstioInjectionEnabled(*ns) {
+				status.Warnings = append(status.Warnings,
+					"Namespace does not have Istio sidecar injection enabled")
+			}
+
+			if status.PodsWithSidecar < status.Pods {
+				status.Warnings = append(status.Warnings, fmt.Sprintf(
+					"%d pod(s) without Istio sidecar cannot accept mTLS traffic", status.Pods-status.PodsWithSidecar))
+			}
+
+			for _, pod := range activatorPods.Items {
+				if !s.hasSidecar(pod) {
+					status.Warnings = append(status.Warnings,
+						"Activator pods do not have Istio sidecar; requests proxied by activator will be rejected")
+					break
+				}
+			}
+		}
+
+		result = append(result, status)
+	}
+
+	return result, nil
+}
+
+func (RevisionMTLSStatuses) hasSidecar(pod corev1.Pod) bool {
+	for _, cont := range pod.Spec.Containers {
+		if cont.Name == istioProxyContainer {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,161 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mtls_test
+
+import (
+	"testing"
+
+	. "github.com/cppforlife/knctl/pkg/knctl/cmd"
+	. "github.com/cppforlife/knctl/pkg/knctl/cmd/mtls"
+	"github.com/cppforlife/knctl/pkg/knctl/testkit"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func TestRevisionMTLSStatuses_EffectiveModes(t *testing.T) {
+	cluster := testkit.NewCluster(t,
+		newTestNamespace("ns1", map[string]string{"istio-injection": "enabled"}),
+		newTestPeerAuthentication("istio-system", "default", nil, "STRICT"),
+		newTestPeerAuthentication("ns1", "default", nil, "PERMISSIVE"),
+		newTestPeerAuthentication("ns1", "svc2", map[string]string{"serving.knative.dev/service": "svc2"}, "DISABLE"),
+		newTestPeerAuthentication("ns1", "svc3", map[string]string{"serving.knative.dev/service": "svc3"}, ""),
+		testkit.NewRevision("ns1", "svc1", "svc1-00001").Build(),
+		testkit.NewRevision("ns1", "svc2", "svc2-00001").Build(),
+		testkit.NewRevision("ns1", "svc3", "svc3-00001").Build(),
+	)
+
+	statuses := listTestRevisionMTLS(t, cluster, "ns1")
+
+	DeepEqual(t, statuses, []RevisionMTLS{
+		{Revision: "svc1-00001", Mode: "PERMISSIVE", Source: "ns1/default"},
+		{Revision: "svc2-00001", Mode: "DISABLE", Source: "ns1/svc2"},
+		{Revision: "svc3-00001", Mode: "PERMISSIVE", Source: "ns1/default"},
+	})
+}
+
+func TestRevisionMTLSStatuses_MeshWideAndDefault(t *testing.T) {
+	cluster := testkit.NewCluster(t,
+		newTestNamespace("ns1", map[string]string{"istio-injection": "enabled"}),
+		newTestNamespace("ns2", map[string]string{"istio-injection": "enabled"}),
+		newTestPeerAuthentication("istio-system", "default", nil, "STRICT"),
+		testkit.NewRevision("ns1", "svc1", "svc1-00001").Build(),
+		testkit.NewRevision("ns2", "svc1", "svc1-00001").Build(),
+	)
+
+	DeepEqual(t, listTestRevisionMTLS(t, cluster, "ns1"), []RevisionMTLS{
+		{Revision: "svc1-00001", Mode: "STRICT", Source: "istio-system/default"},
+	})
+
+	cluster = testkit.NewCluster(t,
+		newTestNamespace("ns1", map[string]string{"istio-injection": "enabled"}),
+		testkit.NewRevision("ns1", "svc1", "svc1-00001").Build(),
+	)
+
+	DeepEqual(t, listTestRevisionMTLS(t, cluster, "ns1"), []RevisionMTLS{
+		{Revision: "svc1-00001", Mode: "PERMISSIVE", Source: "Istio default"},
+	})
+}
+
+func TestRevisionMTLSStatuses_StrictWarnings(t *testing.T) {
+	cluster := testkit.NewCluster(t,
+		newTestNamespace("ns1", nil),
+		newTestPeerAuthentication("ns1", "default", nil, "STRICT"),
+		testkit.NewRevision("ns1", "svc1", "svc1-00001").Build(),
+		newTestPod("ns1", "svc1-00001-pod1", map[string]string{"serving.knative.dev/revision": "svc1-00001"}, true),
+		newTestPod("ns1", "svc1-00001-pod2", map[string]string{"serving.knative.dev/revision": "svc1-00001"}, false),
+		newTestPod("knative-serving", "activator-pod1", map[string]string{"app": "activator"}, false),
+	)
+
+	DeepEqual(t, listTestRevisionMTLS(t, cluster, "ns1"), []RevisionMTLS{
+		{
+			Revision:        "svc1-00001",
+			Mode:            "STRICT",
+			Source:          "ns1/default",
+			Pods:            2,
+			PodsWithSidecar: 1,
+			Warnings: []string{
+				"Namespace does not have Istio sidecar injection enabled",
+				"1 pod(s) without Istio sidecar cannot accept mTLS traffic",
+				"Activator pods do not have Istio sidecar; requests proxied by activator will be rejected",
+			},
+		},
+	})
+}
+
+func listTestRevisionMTLS(t *testing.T, cluster *testkit.Cluster, namespace string) []RevisionMTLS {
+	dynamicClient, err := cluster.DepsFactory().DynamicClient()
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	pas, supported, err := NewPeerAuthentications(dynamicClient, "istio-system", namespace)
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+	if !supported {
+		t.Fatalf("Expected peer authentications to be supported")
+	}
+
+	statuses, err := NewRevisionMTLSStatuses(cluster.CoreClient(), cluster.ServingClient(), pas).List(namespace)
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	return statuses
+}
+
+func newTestNamespace(name string, labels map[string]string) runtime.Object {
+	return &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels}}
+}
+
+func newTestPod(namespace, name string, labels map[string]string, sidecar bool) runtime.Object {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name, Labels: labels},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{Name: "user-container"}},
+		},
+	}
+	if sidecar {
+		pod.Spec.Containers = append(pod.Spec.Containers, corev1.Container{Name: "istio-proxy"})
+	}
+	return pod
+}
+
+func newTestPeerAuthentication(namespace, name string, selector map[string]string, mode string) runtime.Object {
+	spec := map[string]interface{}{}
+
+	if len(selector) > 0 {
+		matchLabels := map[string]interface{}{}
+		for k, v := range selector {
+			matchLabels[k] = v
+		}
+		spec["selector"] = map[string]interface{}{"matchLabels": matchLabels}
+	}
+
+	if len(mode) > 0 {
+		spec["mtls"] = map[string]interface{}{"mode": mode}
+	}
+
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "security.istio.io/v1beta1",
+		"kind":       "PeerAuthentication",
+		"metadata":   map[string]interface{}{"name": name, "namespace": namespace},
+		"spec":       spec,
+	}}
+}
@@ -0,0 +1,122 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mtls
+
+import (
+	"fmt"
+
+	"github.com/cppforlife/go-cli-ui/ui"
+	uitable "github.com/cppforlife/go-cli-ui/ui/table"
+	cmdcore "github.com/cppforlife/knctl/pkg/knctl/cmd/core"
+	"github.com/spf13/cobra"
+)
+
+type StatusOptions struct {
+	ui          ui.UI
+	depsFactory cmdcore.DepsFactory
+
+	NamespaceFlags cmdcore.NamespaceFlags
+	IstioNamespace string
+}
+
+func NewStatusOptions(ui ui.UI, depsFactory cmdcore.DepsFactory) *StatusOptions {
+	return &StatusOptions{ui: ui, depsFactory: depsFactory}
+}
+
+func NewStatusCmd(o *StatusOptions, flagsFactory cmdcore.FlagsFactory) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "status",
+		Short: "Show mutual TLS status of revisions",
+		Long: `Show mutual TLS status of revisions based on Istio peer authentication policies.
+
+Reports effective mode (STRICT, PERMISSIVE or DISABLE) for each revision's pods
+and policy that determines it. Warns about configurations that commonly break
+traffic from activator to revision's pods in STRICT mode.`,
+		Example: `
+  # Show mutual TLS status of revisions in namespace 'ns1'
+  knctl mtls status -n ns1`,
+		RunE: func(_ *cobra.Command, _ []string) error { return o.Run() },
+	}
+	o.NamespaceFlags.Set(cmd, flagsFactory)
+	cmd.Flags().StringVar(&o.IstioNamespace, "istio-namespace", "istio-system", "Set Istio root namespace that holds mesh wide policies")
+	return cmd
+}
+
+func (o *StatusOptions) Run() error {
+	coreClient, err := o.depsFactory.CoreClient()
+	if err != nil {
+		return err
+	}
+
+	servingClient, err := o.depsFactory.ServingClient()
+	if err != nil {
+		return err
+	}
+
+	dynamicClient, err := o.depsFactory.DynamicClient()
+	if err != nil {
+		return err
+	}
+
+	pas, supported, err := NewPeerAuthentications(dynamicClient, o.IstioNamespace, o.NamespaceFlags.Name)
+	if err != nil {
+		return err
+	}
+
+	if !supported {
+		o.ui.PrintLinef("Peer authentication policies are not available in this cluster (requires Istio 1.5+)")
+	}
+
+	statuses, err := NewRevisionMTLSStatuses(coreClient, servingClient, pas).List(o.NamespaceFlags.Name)
+	if err != nil {
+		return err
+	}
+
+	table := uitable.Table{
+		Title:   fmt.Sprintf("Mutual TLS status of revisions in namespace '%s'", o.NamespaceFlags.Name),
+		Content: "revisions",
+
+		Header: []uitable.Header{
+			uitable.NewHeader("Revision"),
+			uitable.NewHeader("Mode"),
+			uitable.NewHeader("Policy"),
+			uitable.NewHeader("Pods with sidecar"),
+			uitable.NewHeader("Warnings"),
+		},
+
+		SortBy: []uitable.ColumnSort{
+			{Column: 0, Asc: true},
+		},
+	}
+
+	for _, status := range statuses {
+		table.Rows = append(table.Rows, []uitable.Value{
+			uitable.NewValueString(status.Revision),
+			uitable.NewValueString(status.Mode),
+			uitable.NewValueString(status.Source),
+			uitable.NewValueString(fmt.Sprintf("%d/%d", status.PodsWithSidecar, status.Pods)),
+			uitable.ValueFmt{
+				V:     uitable.NewValueStrings(status.Warnings),
+				Error: len(status.Warnings) > 0,
+			},
+		})
+	}
+
+	o.ui.PrintTable(table)
+
+	return nil
+}
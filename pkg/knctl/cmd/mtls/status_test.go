@@ -0,0 +1,46 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mtls_test
+
+import (
+	"testing"
+
+	. "github.com/cppforlife/knctl/pkg/knctl/cmd"
+	cmdcore "github.com/cppforlife/knctl/pkg/knctl/cmd/core"
+	. "github.com/cppforlife/knctl/pkg/knctl/cmd/mtls"
+)
+
+func TestNewStatusCmd_Ok(t *testing.T) {
+	realCmd := NewStatusOptions(nil, cmdcore.NewDepsFactory())
+	cmd := NewTestCmd(t, NewStatusCmd(realCmd, cmdcore.FlagsFactory{}))
+	cmd.ExpectBasicConfig()
+	cmd.Execute([]string{"-n", "test-namespace", "--istio-namespace", "istio-root"})
+	cmd.ExpectReachesExecution()
+
+	DeepEqual(t, realCmd.NamespaceFlags, cmdcore.NamespaceFlags{"test-namespace"})
+	DeepEqual(t, realCmd.IstioNamespace, "istio-root")
+}
+
+func TestNewStatusCmd_Defaults(t *testing.T) {
+	realCmd := NewStatusOptions(nil, cmdcore.NewDepsFactory())
+	cmd := NewTestCmd(t, NewStatusCmd(realCmd, cmdcore.FlagsFactory{}))
+	cmd.ExpectBasicConfig()
+	cmd.Execute([]string{"-n", "test-namespace"})
+	cmd.ExpectReachesExecution()
+
+	DeepEqual(t, realCmd.IstioNamespace, "istio-system")
+}
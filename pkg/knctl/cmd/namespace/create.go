@@ -0,0 +1,151 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package namespace
+
+import (
+	"fmt"
+
+	"github.com/cppforlife/go-cli-ui/ui"
+	cmdcore "github.com/cppforlife/knctl/pkg/knctl/cmd/core"
+	cmddom "github.com/cppforlife/knctl/pkg/knctl/cmd/domain"
+	ctlservice "github.com/cppforlife/knctl/pkg/knctl/service"
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type CreateOptions struct {
+	ui          ui.UI
+	depsFactory cmdcore.DepsFactory
+
+	Name        string
+	CreateFlags CreateFlags
+}
+
+func NewCreateOptions(ui ui.UI, depsFactory cmdcore.DepsFactory) *CreateOptions {
+	return &CreateOptions{ui: ui, depsFactory: depsFactory}
+}
+
+func NewCreateCmd(o *CreateOptions, flagsFactory cmdcore.FlagsFactory) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "create",
+		Short: "Create namespace",
+		Long: `Create namespace and verify that it's ready for Knative workloads.
+
+Istio sidecar injection is enabled by default.`,
+		Example: `
+  # Create namespace 'ns1'
+  knctl namespace create ns1
+
+  # Create namespace 'ns1' with Knative Eventing injection enabled
+  knctl namespace create ns1 --eventing-injection
+
+  # Create namespace 'ns1' with services using 'sub.example.com' domain
+  knctl namespace create ns1 --default-domain sub.example.com`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(_ *cobra.Command, args []string) error {
+			o.Name = args[0]
+			return o.Run()
+		},
+	}
+	o.CreateFlags.Set(cmd, flagsFactory)
+	return cmd
+}
+
+func (o *CreateOptions) Run() error {
+	coreClient, err := o.depsFactory.CoreClient()
+	if err != nil {
+		return err
+	}
+
+	ns := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   o.Name,
+			Labels: map[string]string{},
+		},
+	}
+
+	if o.CreateFlags.IstioInjection {
+		ns.Labels[istioInjectionLabelKey] = injectionEnabledValue
+	}
+	if o.CreateFlags.EventingInjection {
+		ns.Labels[eventingInjectionLabelKey] = injectionEnabledValue
+	}
+
+	domains := cmddom.NewDomains(coreClient)
+
+	var domain *cmddom.Domain
+
+	if len(o.CreateFlags.DefaultDomain) > 0 {
+		domain = &cmddom.Domain{
+			Name:     o.CreateFlags.DefaultDomain,
+			Selector: map[string]string{ctlservice.NamespaceLabelKey: o.Name},
+		}
+
+		// Check before creating namespace so that it's not left behind
+		if !o.CreateFlags.OverwriteDomain {
+			err := domains.CheckOverwrite(*domain)
+			if err != nil {
+				return fmt.Errorf("%s (use --overwrite-domain to replace it)", err)
+			}
+		}
+	}
+
+	createdNs, err := coreClient.CoreV1().Namespaces().Create(ns)
+	if err != nil {
+		return fmt.Errorf("Creating namespace: %s", err)
+	}
+
+	err = o.invalidateCache()
+	if err != nil {
+		return err
+	}
+
+	if domain != nil {
+		if o.CreateFlags.OverwriteDomain {
+			err = domains.Create(*domain)
+		} else {
+			err = domains.CreateWithoutOverwrite(*domain)
+		}
+		if err != nil {
+			return fmt.Errorf("Setting default domain: %s", err)
+		}
+	}
+
+	checksObj := NewNamespaceChecks(coreClient)
+
+	checks, err := checksObj.Run(*createdNs)
+	if err != nil {
+		return err
+	}
+
+	checksObj.Print(*createdNs, checks, o.ui)
+
+	if !checksObj.AllOK(checks) {
+		return fmt.Errorf("Expected namespace '%s' to be ready for Knative workloads", createdNs.Name)
+	}
+
+	return nil
+}
+
+func (o *CreateOptions) invalidateCache() error {
+	cache, err := o.depsFactory.Cache()
+	if err != nil {
+		return err
+	}
+	return cache.Delete(NamespacesCacheKey)
+}
@@ -0,0 +1,36 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package namespace
+
+import (
+	cmdcore "github.com/cppforlife/knctl/pkg/knctl/cmd/core"
+	"github.com/spf13/cobra"
+)
+
+type CreateFlags struct {
+	IstioInjection    bool
+	EventingInjection bool
+	DefaultDomain     string
+	OverwriteDomain   bool
+}
+
+func (s *CreateFlags) Set(cmd *cobra.Command, flagsFactory cmdcore.FlagsFactory) {
+	cmd.Flags().BoolVar(&s.IstioInjection, "istio-injection", true, "Enable Istio sidecar injection")
+	cmd.Flags().BoolVar(&s.EventingInjection, "eventing-injection", false, "Enable Knative Eventing broker injection")
+	cmd.Flags().StringVar(&s.DefaultDomain, "default-domain", "", "Set default domain for services in this namespace (example: sub.example.com)")
+	cmd.Flags().BoolVar(&s.OverwriteDomain, "overwrite-domain", false, "Replace existing domain entry (including cluster default domain) with the same name")
+}
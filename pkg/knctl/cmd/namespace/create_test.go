@@ -0,0 +1,62 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package namespace_test
+
+import (
+	"testing"
+
+	. "github.com/cppforlife/knctl/pkg/knctl/cmd"
+	cmdcore "github.com/cppforlife/knctl/pkg/knctl/cmd/core"
+	. "github.com/cppforlife/knctl/pkg/knctl/cmd/namespace"
+)
+
+func TestNewCreateCmd_Ok(t *testing.T) {
+	realCmd := NewCreateOptions(nil, cmdcore.NewDepsFactory())
+	cmd := NewTestCmd(t, NewCreateCmd(realCmd, cmdcore.FlagsFactory{}))
+	cmd.ExpectBasicConfig()
+	cmd.Execute([]string{
+		"test-namespace",
+		"--istio-injection=false",
+		"--eventing-injection",
+		"--default-domain", "sub.example.com",
+		"--overwrite-domain",
+	})
+	cmd.ExpectReachesExecution()
+
+	DeepEqual(t, realCmd.CreateFlags, CreateFlags{
+		IstioInjection:    false,
+		EventingInjection: true,
+		DefaultDomain:     "sub.example.com",
+		OverwriteDomain:   true,
+	})
+}
+
+func TestNewCreateCmd_OkMinimum(t *testing.T) {
+	realCmd := NewCreateOptions(nil, cmdcore.NewDepsFactory())
+	cmd := NewTestCmd(t, NewCreateCmd(realCmd, cmdcore.FlagsFactory{}))
+	cmd.Execute([]string{"test-namespace"})
+	cmd.ExpectReachesExecution()
+
+	DeepEqual(t, realCmd.CreateFlags, CreateFlags{IstioInjection: true})
+}
+
+func TestNewCreateCmd_RequiresName(t *testing.T) {
+	realCmd := NewCreateOptions(nil, cmdcore.NewDepsFactory())
+	cmd := NewTestCmd(t, NewCreateCmd(realCmd, cmdcore.FlagsFactory{}))
+	cmd.Execute([]string{})
+	cmd.ExpectErr("accepts 1 arg(s), received 0")
+}
@@ -0,0 +1,95 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package namespace
+
+import (
+	"fmt"
+
+	"github.com/cppforlife/go-cli-ui/ui"
+	cmdcore "github.com/cppforlife/knctl/pkg/knctl/cmd/core"
+	cmddom "github.com/cppforlife/knctl/pkg/knctl/cmd/domain"
+	ctlservice "github.com/cppforlife/knctl/pkg/knctl/service"
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type DeleteOptions struct {
+	ui          ui.UI
+	depsFactory cmdcore.DepsFactory
+
+	Name string
+}
+
+func NewDeleteOptions(ui ui.UI, depsFactory cmdcore.DepsFactory) *DeleteOptions {
+	return &DeleteOptions{ui: ui, depsFactory: depsFactory}
+}
+
+func NewDeleteCmd(o *DeleteOptions, flagsFactory cmdcore.FlagsFactory) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "delete",
+		Short: "Delete namespace",
+		Long:  "Delete namespace with all of its resources, and domains selecting it",
+		Example: `
+  # Delete namespace 'ns1'
+  knctl namespace delete ns1`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(_ *cobra.Command, args []string) error {
+			o.Name = args[0]
+			return o.Run()
+		},
+	}
+	return cmd
+}
+
+func (o *DeleteOptions) Run() error {
+	coreClient, err := o.depsFactory.CoreClient()
+	if err != nil {
+		return err
+	}
+
+	domains := cmddom.NewDomains(coreClient)
+
+	domainNames, err := domains.FindBySelector(map[string]string{ctlservice.NamespaceLabelKey: o.Name})
+	if err != nil {
+		return fmt.Errorf("Finding namespace domains: %s", err)
+	}
+
+	for _, name := range domainNames {
+		o.ui.PrintLinef("Deleting domain '%s'", name)
+
+		err := domains.Delete(name)
+		if err != nil {
+			return err
+		}
+	}
+
+	o.ui.PrintLinef("Deleting namespace '%s'", o.Name)
+
+	err = coreClient.CoreV1().Namespaces().Delete(o.Name, &metav1.DeleteOptions{})
+	if err != nil {
+		return fmt.Errorf("Deleting namespace: %s", err)
+	}
+
+	return o.invalidateCache()
+}
+func (o *DeleteOptions) invalidateCache() error {
+	cache, err := o.depsFactory.Cache()
+	if err != nil {
+		return err
+	}
+	return cache.Delete(NamespacesCacheKey)
+}
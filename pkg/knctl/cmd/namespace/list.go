@@ -0,0 +1,112 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package namespace
+
+import (
+	"github.com/cppforlife/go-cli-ui/ui"
+	uitable "github.com/cppforlife/go-cli-ui/ui/table"
+	cmdcore "github.com/cppforlife/knctl/pkg/knctl/cmd/core"
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	NamespacesCacheKey = "namespaces"
+)
+
+type ListOptions struct {
+	ui          ui.UI
+	depsFactory cmdcore.DepsFactory
+}
+
+func NewListOptions(ui ui.UI, depsFactory cmdcore.DepsFactory) *ListOptions {
+	return &ListOptions{ui: ui, depsFactory: depsFactory}
+}
+
+func NewListCmd(o *ListOptions, flagsFactory cmdcore.FlagsFactory) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "list",
+		Aliases: cmdcore.ListAliases,
+		Short:   "List namespaces",
+		Long:    "List all namespaces",
+		Example: `
+  # List all namespaces
+  knctl namespace list`,
+		RunE: func(_ *cobra.Command, _ []string) error { return o.Run() },
+	}
+	return cmd
+}
+
+func (o *ListOptions) Run() error {
+	coreClient, err := o.depsFactory.CoreClient()
+	if err != nil {
+		return err
+	}
+
+	cache, err := o.depsFactory.Cache()
+	if err != nil {
+		return err
+	}
+
+	var namespaces corev1.NamespaceList
+
+	if !cache.Get(NamespacesCacheKey, &namespaces) {
+		nsList, err := coreClient.CoreV1().Namespaces().List(metav1.ListOptions{})
+		if err != nil {
+			return err
+		}
+
+		namespaces = *nsList
+
+		err = cache.Set(NamespacesCacheKey, namespaces)
+		if err != nil {
+			return err
+		}
+	}
+
+	table := uitable.Table{
+		Title:   "Namespaces",
+		Content: "namespaces",
+
+		Header: []uitable.Header{
+			uitable.NewHeader("Name"),
+			uitable.NewHeader("Status"),
+			uitable.NewHeader("Istio injection"),
+			uitable.NewHeader("Eventing injection"),
+			uitable.NewHeader("Age"),
+		},
+
+		SortBy: []uitable.ColumnSort{
+			{Column: 0, Asc: true},
+		},
+	}
+
+	for _, ns := range namespaces.Items {
+		table.Rows = append(table.Rows, []uitable.Value{
+			uitable.NewValueString(ns.Name),
+			uitable.NewValueString(string(ns.Status.Phase)),
+			uitable.NewValueBool(IstioInjectionEnabled(ns)),
+			uitable.NewValueBool(EventingInjectionEnabled(ns)),
+			cmdcore.NewValueAge(ns.CreationTimestamp.Time),
+		})
+	}
+
+	o.ui.PrintTable(table)
+
+	return nil
+}
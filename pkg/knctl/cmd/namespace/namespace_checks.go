@@ -0,0 +1,116 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package namespace
+
+import (
+	"fmt"
+
+	"github.com/cppforlife/go-cli-ui/ui"
+	uitable "github.com/cppforlife/go-cli-ui/ui/table"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	servingNs = "knative-serving"
+)
+
+var (
+	servingRequiredConfigMaps = []string{"config-domain", "config-network", "config-autoscaler"}
+)
+
+type NamespaceCheck struct {
+	Name    string
+	OK      bool
+	Details string
+}
+
+// NamespaceChecks verifies that namespace is ready for Knative workloads
+type NamespaceChecks struct {
+	coreClient kubernetes.Interface
+}
+
+func NewNamespaceChecks(coreClient kubernetes.Interface) NamespaceChecks {
+	return NamespaceChecks{coreClient}
+}
+
+func (c NamespaceChecks) Run(ns corev1.Namespace) ([]NamespaceCheck, error) {
+	var checks []NamespaceCheck
+
+	for _, name := range servingRequiredConfigMaps {
+		check := NamespaceCheck{Name: fmt.Sprintf("Config map '%s/%s' exists", servingNs, name)}
+
+		_, err := c.coreClient.CoreV1().ConfigMaps(servingNs).Get(name, metav1.GetOptions{})
+		if err != nil {
+			if !errors.IsNotFound(err) {
+				return nil, fmt.Errorf("Getting config map '%s/%s': %s", servingNs, name, err)
+			}
+			check.Details = "Install Knative Serving via 'knctl install'"
+		} else {
+			check.OK = true
+		}
+
+		checks = append(checks, check)
+	}
+
+	istioCheck := NamespaceCheck{
+		Name: "Istio sidecar injection enabled",
+		OK:   IstioInjectionEnabled(ns),
+	}
+	if !istioCheck.OK {
+		istioCheck.Details = fmt.Sprintf("Label namespace with '%s=%s'", istioInjectionLabelKey, injectionEnabledValue)
+	}
+
+	return append(checks, istioCheck), nil
+}
+
+func (c NamespaceChecks) Print(ns corev1.Namespace, checks []NamespaceCheck, ui ui.UI) {
+	table := uitable.Table{
+		Title:   fmt.Sprintf("Knative readiness of namespace '%s'", ns.Name),
+		Content: "checks",
+
+		Header: []uitable.Header{
+			uitable.NewHeader("Check"),
+			uitable.NewHeader("OK"),
+			uitable.NewHeader("Details"),
+		},
+	}
+
+	for _, check := range checks {
+		table.Rows = append(table.Rows, []uitable.Value{
+			uitable.NewValueString(check.Name),
+			uitable.ValueFmt{
+				V:     uitable.NewValueBool(check.OK),
+				Error: !check.OK,
+			},
+			uitable.NewValueString(check.Details),
+		})
+	}
+
+	ui.PrintTable(table)
+}
+
+func (c NamespaceChecks) AllOK(checks []NamespaceCheck) bool {
+	for _, check := range checks {
+		if !check.OK {
+			return false
+		}
+	}
+	return true
+}
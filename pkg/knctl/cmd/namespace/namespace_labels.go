@@ -0,0 +1,35 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package namespace
+
+import (
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	istioInjectionLabelKey    = "istio-injection"
+	eventingInjectionLabelKey = "knative-eventing-injection"
+	injectionEnabledValue     = "enabled"
+)
+
+func IstioInjectionEnabled(ns corev1.Namespace) bool {
+	return ns.Labels[istioInjectionLabelKey] == injectionEnabledValue
+}
+
+func EventingInjectionEnabled(ns corev1.Namespace) bool {
+	return ns.Labels[eventingInjectionLabelKey] == injectionEnabledValue
+}
@@ -0,0 +1,127 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+
+	cmdcore "github.com/cppforlife/knctl/pkg/knctl/cmd/core"
+	"github.com/cppforlife/knctl/pkg/knctl/util"
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+var (
+	// Resources that are not namespaced among the ones knctl commands look up by name
+	clusterScopedResources = map[schema.GroupResource]struct{}{
+		{Resource: "namespaces"}: {},
+		{Resource: "nodes"}:      {},
+	}
+)
+
+// NotFoundSuggestions adds names of existing resources that are close
+// to the one that was not found (e.g. 'knctl service show -s helo')
+type NotFoundSuggestions struct {
+	depsFactory cmdcore.DepsFactory
+}
+
+func (s NotFoundSuggestions) Annotate(cmd *cobra.Command, err error) error {
+	statusErr, ok := err.(errors.APIStatus)
+	if !ok || !errors.IsNotFound(err) {
+		return err
+	}
+
+	details := statusErr.Status().Details
+	if details == nil || len(details.Name) == 0 || len(details.Kind) == 0 {
+		return err
+	}
+
+	names, listErr := s.listNames(cmd, schema.GroupResource{Group: details.Group, Resource: details.Kind})
+	if listErr != nil {
+		// Suggestions are best effort; original error is more important
+		return err
+	}
+
+	suggestions := util.DidYouMean(util.ClosestMatches(details.Name, names))
+	if len(suggestions) == 0 {
+		return err
+	}
+
+	return fmt.Errorf("%s%s", err, suggestions)
+}
+
+func (s NotFoundSuggestions) listNames(cmd *cobra.Command, groupResource schema.GroupResource) ([]string, error) {
+	version, err := s.resourceVersion(groupResource.Group)
+	if err != nil {
+		return nil, err
+	}
+
+	dynamicClient, err := s.depsFactory.DynamicClient()
+	if err != nil {
+		return nil, err
+	}
+
+	resClient := dynamicClient.Resource(groupResource.WithVersion(version))
+
+	var list *unstructured.UnstructuredList
+
+	if _, found := clusterScopedResources[groupResource]; found {
+		list, err = resClient.List(metav1.ListOptions{})
+	} else {
+		namespace := commandNamespace(cmd)
+		if len(namespace) == 0 {
+			return nil, fmt.Errorf("Expected command to have namespace")
+		}
+		list, err = resClient.Namespace(namespace).List(metav1.ListOptions{})
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for _, item := range list.Items {
+		names = append(names, item.GetName())
+	}
+
+	return names, nil
+}
+
+// resourceVersion avoids discovery requests by relying on API
+// versions this client is compiled against
+func (NotFoundSuggestions) resourceVersion(group string) (string, error) {
+	if len(group) == 0 {
+		return "v1", nil
+	}
+	if versions := SupportedAPIVersions[group]; len(versions) > 0 {
+		return versions[0], nil
+	}
+	return "", fmt.Errorf("Unknown API group '%s'", group)
+}
+
+func commandNamespace(cmd *cobra.Command) string {
+	flag := cmd.Flags().Lookup("namespace")
+	if flag == nil {
+		return ""
+	}
+	if nsFlag, ok := flag.Value.(*cmdcore.NamespaceNameFlag); ok {
+		return nsFlag.Value()
+	}
+	return flag.Value.String()
+}
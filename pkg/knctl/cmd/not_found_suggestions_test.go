@@ -0,0 +1,83 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd_test
+
+import (
+	"strings"
+	"testing"
+
+	. "github.com/cppforlife/knctl/pkg/knctl/cmd"
+	"github.com/cppforlife/knctl/pkg/knctl/testkit"
+)
+
+func TestNotFoundSuggestions(t *testing.T) {
+	cluster := testkit.NewCluster(t,
+		testkit.NewService("ns1", "hello").Build(),
+		testkit.NewService("ns1", "help-desk").Build(),
+		testkit.NewService("ns2", "helo2").Build(),
+	)
+
+	cases := []struct {
+		Args     []string
+		Expected string
+	}{
+		{
+			[]string{"service", "show", "-s", "helo"},
+			`services.serving.knative.dev "helo" not found (did you mean 'hello'?)`,
+		},
+		{
+			// Does not suggest anything when nothing is close
+			[]string{"service", "show", "-s", "unrelated"},
+			`services.serving.knative.dev "unrelated" not found`,
+		},
+	}
+
+	for _, c := range cases {
+		args := append(c.Args, "-n", "ns1", "--kubeconfig", cluster.KubeconfigPath())
+
+		out, err := ExecuteWithOutput(args)
+		if err == nil {
+			t.Fatalf("Expected command to fail:\n%s", out)
+		}
+
+		if err.Error() != c.Expected {
+			t.Fatalf("Expected error '%s', but was '%s'", c.Expected, err)
+		}
+	}
+}
+
+func TestSubcommandSuggestions(t *testing.T) {
+	cases := []struct {
+		Args     []string
+		Expected string
+	}{
+		{[]string{"servce"}, "(did you mean 'serve' or 'service'?)"},
+		{[]string{"service", "lst"}, "Use one of available subcommands: "},
+		{[]string{"service", "lst"}, "(did you mean 'list'?)"},
+	}
+
+	for _, c := range cases {
+		out, err := ExecuteWithOutput(c.Args)
+		if err == nil {
+			t.Fatalf("Expected command to fail:\n%s", out)
+		}
+
+		if !strings.Contains(err.Error(), c.Expected) {
+			t.Fatalf("Expected error to include '%s', but was '%s'", c.Expected, err)
+		}
+	}
+}
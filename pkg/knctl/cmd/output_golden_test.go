@@ -0,0 +1,69 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd_test
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/cppforlife/knctl/pkg/knctl/cmd"
+	"github.com/cppforlife/knctl/pkg/knctl/testkit"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// Golden files live in ./testdata; run `go test ./pkg/knctl/cmd/ -run Golden -update`
+// after intentional changes to output and review the diff of golden files.
+func TestOutputGolden(t *testing.T) {
+	createdAt := time.Now().Add(-2 * time.Hour)
+
+	objs := []runtime.Object{
+		testkit.NewService("ns1", "svc1").Domain("svc1.ns1.example.com").
+			LatestRevision("svc1-00002", "svc1-00002").Ready().Build(),
+		testkit.NewService("ns1", "svc2").Manual().Build(),
+		testkit.NewRevision("ns1", "svc1", "svc1-00001").CreatedAt(createdAt).Ready().Build(),
+		testkit.NewRevision("ns1", "svc1", "svc1-00002").CreatedAt(createdAt).Tag("latest").Ready().Build(),
+		testkit.NewRoute("ns1", "svc1").Domain("svc1.ns1.example.com").
+			Traffic("svc1-00001", 20).Traffic("svc1-00002", 80).Ready().Build(),
+	}
+
+	cases := []struct {
+		Name string
+		Args []string
+	}{
+		{"service-list", []string{"service", "list"}},
+		{"service-list-json", []string{"service", "list", "--json"}},
+		{"revision-list", []string{"revision", "list", "-s", "svc1"}},
+		{"revision-list-json", []string{"revision", "list", "-s", "svc1", "--json"}},
+		{"route-list", []string{"route", "list"}},
+		{"route-list-json", []string{"route", "list", "--json"}},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			cluster := testkit.NewCluster(t, objs...)
+
+			args := append(c.Args, "-n", "ns1", "--kubeconfig", cluster.KubeconfigPath(), "--tty", "--no-color")
+
+			out, err := ExecuteWithOutput(args)
+			if err != nil {
+				t.Fatalf("Expected command to succeed: %s\n%s", err, out)
+			}
+
+			testkit.ExpectGolden(t, c.Name, out)
+		})
+	}
+}
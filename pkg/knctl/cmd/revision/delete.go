@@ -32,6 +32,9 @@ type DeleteOptions struct {
 	depsFactory cmdcore.DepsFactory
 
 	RevisionFlags cmdflags.RevisionFlags
+	DrainFlags    cmdflags.DrainFlags
+
+	Force bool
 }
 
 func NewDeleteOptions(ui ui.UI, depsFactory cmdcore.DepsFactory) *DeleteOptions {
@@ -43,12 +46,29 @@ func NewDeleteCmd(o *DeleteOptions, flagsFactory cmdcore.FlagsFactory) *cobra.Co
 		Use:     "delete",
 		Aliases: cmdcore.DeleteAliases,
 		Short:   "Delete revision",
+		Long: `Delete revision.
+
+With --drain, revision is first removed from routes that reference it,
+and deletion waits until routes stop sending traffic to it and its
+in-flight requests finish (observed concurrency reaches zero).
+Routes managed by a service are not modified; deletion is aborted if
+such route still sends traffic to the revision.
+
+Pinned revisions (see 'knctl revision pin') are only deleted with --force.`,
 		Example: `
   # Delete revision 'rev1' in namespace 'ns1'
-  knctl revision delete -r rev1 -n ns1`,
+  knctl revision delete -r rev1 -n ns1
+
+  # Delete revision 'rev1' in namespace 'ns1' after draining its traffic
+  knctl revision delete -r rev1 --drain -n ns1
+
+  # Delete pinned revision 'rev1' in namespace 'ns1'
+  knctl revision delete -r rev1 --force -n ns1`,
 		RunE: func(_ *cobra.Command, _ []string) error { return o.Run() },
 	}
 	o.RevisionFlags.Set(cmd, flagsFactory)
+	o.DrainFlags.Set(cmd, flagsFactory)
+	cmd.Flags().BoolVar(&o.Force, "force", false, "Delete revision even if it's pinned")
 	return cmd
 }
 
@@ -65,6 +85,24 @@ func (o *DeleteOptions) Run() error {
 		return err
 	}
 
+	if !o.Force && ctlservice.NewPins(servingClient).IsPinned(*revision) {
+		return fmt.Errorf("Expected revision '%s' to not be pinned (use --force to delete it anyway)", revision.Name)
+	}
+
+	if o.DrainFlags.Drain {
+		coreClient, err := o.depsFactory.CoreClient()
+		if err != nil {
+			return err
+		}
+
+		drain := NewTrafficDrain(coreClient, servingClient, o.ui, o.DrainFlags.Timeout)
+
+		err = drain.Drain(revision.Namespace, []string{revision.Name}, "")
+		if err != nil {
+			return fmt.Errorf("Draining revision: %s", err)
+		}
+	}
+
 	err = servingClient.ServingV1alpha1().Revisions(revision.Namespace).Delete(revision.Name, &metav1.DeleteOptions{})
 	if err != nil {
 		return fmt.Errorf("Deleting revision: %s", err)
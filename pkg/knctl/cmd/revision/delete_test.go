@@ -18,11 +18,15 @@ package revision_test
 
 import (
 	"testing"
+	"time"
 
+	"github.com/cppforlife/go-cli-ui/ui"
 	. "github.com/cppforlife/knctl/pkg/knctl/cmd"
 	cmdcore "github.com/cppforlife/knctl/pkg/knctl/cmd/core"
 	cmdflags "github.com/cppforlife/knctl/pkg/knctl/cmd/flags"
 	. "github.com/cppforlife/knctl/pkg/knctl/cmd/revision"
+	"github.com/cppforlife/knctl/pkg/knctl/testkit"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 func TestNewDeleteCmd_Ok(t *testing.T) {
@@ -58,3 +62,54 @@ func TestNewDeleteCmd_RequiredFlags(t *testing.T) {
 	cmd.Execute([]string{})
 	cmd.ExpectRequiredFlags([]string{"revision"})
 }
+
+func TestNewDeleteCmd_Drain(t *testing.T) {
+	realCmd := NewDeleteOptions(nil, cmdcore.NewDepsFactory())
+	cmd := NewTestCmd(t, NewDeleteCmd(realCmd, cmdcore.FlagsFactory{}))
+	cmd.ExpectBasicConfig()
+	cmd.Execute([]string{
+		"-n", "test-namespace",
+		"-r", "test-revision",
+		"--drain",
+		"--drain-timeout", "1m",
+	})
+	cmd.ExpectReachesExecution()
+
+	DeepEqual(t, realCmd.DrainFlags, cmdflags.DrainFlags{Drain: true, Timeout: time.Minute})
+}
+
+func TestNewDeleteCmd_DrainDefaults(t *testing.T) {
+	realCmd := NewDeleteOptions(nil, cmdcore.NewDepsFactory())
+	cmd := NewTestCmd(t, NewDeleteCmd(realCmd, cmdcore.FlagsFactory{}))
+	cmd.ExpectBasicConfig()
+	cmd.Execute([]string{"-n", "test-namespace", "-r", "test-revision"})
+	cmd.ExpectReachesExecution()
+
+	DeepEqual(t, realCmd.DrainFlags, cmdflags.DrainFlags{Drain: false, Timeout: 5 * time.Minute})
+}
+
+func TestDeleteOptions_RefusesPinnedRevisionWithoutForce(t *testing.T) {
+	cluster := testkit.NewCluster(t,
+		testkit.NewRevision("ns1", "svc1", "svc1-00001").Annotations(map[string]string{"cli.knative.dev/pinned": "true"}).Build(),
+	)
+
+	opts := NewDeleteOptions(ui.NewNoopUI(), cluster.DepsFactory())
+	opts.RevisionFlags = cmdflags.RevisionFlags{cmdcore.NamespaceFlags{"ns1"}, "svc1-00001"}
+
+	err := opts.Run()
+	if err == nil || err.Error() != "Expected revision 'svc1-00001' to not be pinned (use --force to delete it anyway)" {
+		t.Fatalf("Expected pinned revision error, but was: %v", err)
+	}
+
+	opts.Force = true
+
+	err = opts.Run()
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	_, err = cluster.ServingClient().ServingV1alpha1().Revisions("ns1").Get("svc1-00001", metav1.GetOptions{})
+	if err == nil {
+		t.Fatalf("Expected revision to be deleted")
+	}
+}
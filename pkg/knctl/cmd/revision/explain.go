@@ -0,0 +1,110 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package revision
+
+import (
+	"fmt"
+
+	"github.com/cppforlife/go-cli-ui/ui"
+	uitable "github.com/cppforlife/go-cli-ui/ui/table"
+	cmdcore "github.com/cppforlife/knctl/pkg/knctl/cmd/core"
+	cmdflags "github.com/cppforlife/knctl/pkg/knctl/cmd/flags"
+	ctlservice "github.com/cppforlife/knctl/pkg/knctl/service"
+	"github.com/spf13/cobra"
+)
+
+type ExplainOptions struct {
+	ui          ui.UI
+	depsFactory cmdcore.DepsFactory
+
+	RevisionFlags cmdflags.RevisionFlags
+}
+
+func NewExplainOptions(ui ui.UI, depsFactory cmdcore.DepsFactory) *ExplainOptions {
+	return &ExplainOptions{ui: ui, depsFactory: depsFactory}
+}
+
+func NewExplainCmd(o *ExplainOptions, flagsFactory cmdcore.FlagsFactory) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "explain",
+		Short: "Explain why revision is not ready",
+		Long: `Explain why revision is not ready.
+
+Correlates revision's conditions, its deployment's replica failures,
+pod statuses and warning events (e.g. ImagePullBackOff, OOMKilled, failing probes)
+and queue-proxy errors into a single summary.`,
+		Example: `
+  # Explain why revision 'rev1' in namespace 'ns1' is not ready
+  knctl explain -r rev1 -n ns1
+
+  # Explain latest revision of service 'svc1' in namespace 'ns1'
+  knctl explain -r svc1:latest -n ns1`,
+		Annotations: map[string]string{
+			cmdcore.BasicHelpGroup.Key: cmdcore.BasicHelpGroup.Value,
+		},
+		RunE: func(_ *cobra.Command, _ []string) error { return o.Run() },
+	}
+	o.RevisionFlags.Set(cmd, flagsFactory)
+	return cmd
+}
+
+func (o *ExplainOptions) Run() error {
+	servingClient, err := o.depsFactory.ServingClient()
+	if err != nil {
+		return err
+	}
+
+	coreClient, err := o.depsFactory.CoreClient()
+	if err != nil {
+		return err
+	}
+
+	revision, err := NewReference(o.RevisionFlags, ctlservice.NewTags(servingClient), servingClient).Revision()
+	if err != nil {
+		return err
+	}
+
+	explanation, err := NewRevisionExplainer(coreClient).Explain(revision)
+	if err != nil {
+		return err
+	}
+
+	table := uitable.Table{
+		Title:   fmt.Sprintf("Findings for revision '%s'", revision.Name),
+		Content: "findings",
+
+		Header: []uitable.Header{
+			uitable.NewHeader("Source"),
+			uitable.NewHeader("Reason"),
+			uitable.NewHeader("Message"),
+		},
+	}
+
+	for _, finding := range explanation.Findings {
+		table.Rows = append(table.Rows, []uitable.Value{
+			uitable.NewValueString(finding.Source),
+			uitable.NewValueString(finding.Reason),
+			uitable.NewValueString(finding.Message),
+		})
+	}
+
+	o.ui.PrintTable(table)
+
+	o.ui.PrintLinef("Root cause: %s", explanation.RootCause)
+
+	return nil
+}
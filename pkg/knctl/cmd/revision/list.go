@@ -23,7 +23,9 @@ import (
 	uitable "github.com/cppforlife/go-cli-ui/ui/table"
 	cmdcore "github.com/cppforlife/knctl/pkg/knctl/cmd/core"
 	cmdflags "github.com/cppforlife/knctl/pkg/knctl/cmd/flags"
+	"github.com/cppforlife/knctl/pkg/knctl/gitmeta"
 	ctlservice "github.com/cppforlife/knctl/pkg/knctl/service"
+	"github.com/cppforlife/knctl/pkg/knctl/util"
 	"github.com/knative/serving/pkg/apis/serving"
 	"github.com/knative/serving/pkg/apis/serving/v1alpha1"
 	"github.com/spf13/cobra"
@@ -36,6 +38,7 @@ type ListOptions struct {
 	depsFactory cmdcore.DepsFactory
 
 	ServiceFlags cmdflags.ServiceFlags
+	ShowGit      bool
 }
 
 func NewListOptions(ui ui.UI, depsFactory cmdcore.DepsFactory) *ListOptions {
@@ -50,10 +53,14 @@ func NewListCmd(o *ListOptions, flagsFactory cmdcore.FlagsFactory) *cobra.Comman
 		Long:    "List all revisions for a service",
 		Example: `
   # List all revisions for service 'svc1' in namespace 'ns1' 
-  knctl revision list -s svc1 -n ns1`,
+  knctl revision list -s svc1 -n ns1
+
+  # List all revisions for service 'svc1' with git commit they were deployed from
+  knctl revision list -s svc1 -n ns1 --show-git`,
 		RunE: func(_ *cobra.Command, _ []string) error { return o.Run() },
 	}
 	o.ServiceFlags.SetOptional(cmd, flagsFactory)
+	cmd.Flags().BoolVar(&o.ShowGit, "show-git", false, "Show git commit, branch and author revision was deployed from")
 	return cmd
 }
 
@@ -67,6 +74,15 @@ func (o *ListOptions) Run() error {
 	serviceHeader := uitable.NewHeader("Service")
 	listOpts := metav1.ListOptions{}
 
+	gitHeaders := []uitable.Header{
+		uitable.NewHeader("Commit"),
+		uitable.NewHeader("Branch"),
+		uitable.NewHeader("Author"),
+	}
+	for i := range gitHeaders {
+		gitHeaders[i].Hidden = !o.ShowGit
+	}
+
 	if len(o.ServiceFlags.Name) > 0 {
 		tableTitle += fmt.Sprintf(" for service '%s'", o.ServiceFlags.Name)
 		serviceHeader.Hidden = true
@@ -82,12 +98,19 @@ func (o *ListOptions) Run() error {
 		}
 	}
 
-	revisions, err := servingClient.ServingV1alpha1().Revisions(o.ServiceFlags.NamespaceFlags.Name).List(listOpts)
-	if err != nil {
-		return err
-	}
+	var revisions *v1alpha1.RevisionList
+	var routes *v1alpha1.RouteList
 
-	routes, err := servingClient.ServingV1alpha1().Routes(o.ServiceFlags.NamespaceFlags.Name).List(metav1.ListOptions{})
+	err = util.NewParallel(2).Run(2, func(i int) error {
+		var err error
+		switch i {
+		case 0:
+			revisions, err = servingClient.ServingV1alpha1().Revisions(o.ServiceFlags.NamespaceFlags.Name).List(listOpts)
+		case 1:
+			routes, err = servingClient.ServingV1alpha1().Routes(o.ServiceFlags.NamespaceFlags.Name).List(metav1.ListOptions{})
+		}
+		return err
+	})
 	if err != nil {
 		return err
 	}
@@ -104,6 +127,9 @@ func (o *ListOptions) Run() error {
 			uitable.NewHeader("Conditions"),
 			uitable.NewHeader("Age"),
 			uitable.NewHeader("Traffic"),
+			gitHeaders[0],
+			gitHeaders[1],
+			gitHeaders[2],
 		},
 
 		SortBy: []uitable.ColumnSort{
@@ -113,6 +139,8 @@ func (o *ListOptions) Run() error {
 	}
 
 	for _, rev := range revisions.Items {
+		gitMeta, _ := gitmeta.FromAnnotations(rev.Annotations)
+
 		table.Rows = append(table.Rows, []uitable.Value{
 			uitable.NewValueString(rev.Labels[serving.ConfigurationLabelKey]),
 			uitable.NewValueString(rev.Name),
@@ -121,6 +149,9 @@ func (o *ListOptions) Run() error {
 			cmdcore.NewConditionsValue(rev.Status.Conditions),
 			cmdcore.NewValueAge(rev.CreationTimestamp.Time),
 			NewTrafficValue(rev, routes.Items),
+			uitable.NewValueString(gitMeta.ShortCommit()),
+			uitable.NewValueString(gitMeta.Branch),
+			uitable.NewValueString(gitMeta.Author),
 		})
 	}
 
@@ -60,3 +60,12 @@ func TestNewListCmd_OkMinimum(t *testing.T) {
 
 	DeepEqual(t, realCmd.ServiceFlags, cmdflags.ServiceFlags{})
 }
+
+func TestNewListCmd_OkShowGit(t *testing.T) {
+	realCmd := NewListOptions(nil, cmdcore.NewDepsFactory())
+	cmd := NewTestCmd(t, NewListCmd(realCmd, cmdcore.FlagsFactory{}))
+	cmd.Execute([]string{"--show-git"})
+	cmd.ExpectReachesExecution()
+
+	DeepEqual(t, realCmd.ShowGit, true)
+}
@@ -0,0 +1,69 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package revision
+
+import (
+	"github.com/cppforlife/go-cli-ui/ui"
+	cmdcore "github.com/cppforlife/knctl/pkg/knctl/cmd/core"
+	cmdflags "github.com/cppforlife/knctl/pkg/knctl/cmd/flags"
+	ctlservice "github.com/cppforlife/knctl/pkg/knctl/service"
+	"github.com/spf13/cobra"
+)
+
+type PinOptions struct {
+	ui          ui.UI
+	depsFactory cmdcore.DepsFactory
+
+	RevisionFlags cmdflags.RevisionFlags
+}
+
+func NewPinOptions(ui ui.UI, depsFactory cmdcore.DepsFactory) *PinOptions {
+	return &PinOptions{ui: ui, depsFactory: depsFactory}
+}
+
+func NewPinCmd(o *PinOptions, flagsFactory cmdcore.FlagsFactory) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "pin",
+		Short: "Pin revision to protect it from deletion",
+		Long: `Pin revision to protect it from deletion.
+
+Pinned revisions are not deleted by 'knctl revision delete' and 'knctl service delete'
+unless --force flag is specified.`,
+		Example: `
+  # Pin revision 'rev1' in namespace 'ns1'
+  knctl revision pin -r rev1 -n ns1`,
+		RunE: func(_ *cobra.Command, _ []string) error { return o.Run() },
+	}
+	o.RevisionFlags.Set(cmd, flagsFactory)
+	return cmd
+}
+
+func (o *PinOptions) Run() error {
+	servingClient, err := o.depsFactory.ServingClient()
+	if err != nil {
+		return err
+	}
+
+	tags := ctlservice.NewTags(servingClient)
+
+	revision, err := NewReference(o.RevisionFlags, tags, servingClient).Revision()
+	if err != nil {
+		return err
+	}
+
+	return ctlservice.NewPins(servingClient).Pin(*revision)
+}
@@ -17,14 +17,10 @@ limitations under the License.
 package revision
 
 import (
-	"fmt"
-	"strings"
-
 	cmdflags "github.com/cppforlife/knctl/pkg/knctl/cmd/flags"
 	ctlservice "github.com/cppforlife/knctl/pkg/knctl/service"
 	"github.com/knative/serving/pkg/apis/serving/v1alpha1"
 	servingclientset "github.com/knative/serving/pkg/client/clientset/versioned"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 type Reference struct {
@@ -38,25 +34,5 @@ func NewReference(flags cmdflags.RevisionFlags, tags ctlservice.Tags, servingCli
 }
 
 func (r Reference) Revision() (*v1alpha1.Revision, error) {
-	if len(r.flags.Name) == 0 {
-		return nil, fmt.Errorf("Expected revision reference to not be empty")
-	}
-
-	pieces := strings.Split(r.flags.Name, ":")
-
-	switch len(pieces) {
-	case 1:
-		revision, err := r.servingClient.ServingV1alpha1().Revisions(r.flags.NamespaceFlags.Name).Get(pieces[0], metav1.GetOptions{})
-		if err != nil {
-			return nil, fmt.Errorf("Getting revision: %s", err)
-		}
-		return revision, err
-
-	case 2:
-		context := ctlservice.TagsFindContext{Namespace: r.flags.NamespaceFlags.Name, Service: pieces[0]}
-		return r.tags.Find(context, pieces[1])
-
-	default:
-		return nil, fmt.Errorf("Expected revision reference to be in format 'revision' or 'service:revision'")
-	}
+	return ctlservice.NewRevisionReference(r.tags, r.servingClient).Revision(r.flags.NamespaceFlags.Name, r.flags.Name)
 }
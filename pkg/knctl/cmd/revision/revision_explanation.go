@@ -0,0 +1,279 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package revision
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/knative/serving/pkg/apis/serving"
+	"github.com/knative/serving/pkg/apis/serving/v1alpha1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	queueProxyContainer = "queue-proxy"
+	queueProxyLogLines  = 50
+)
+
+type RevisionFinding struct {
+	Source  string
+	Reason  string
+	Message string
+}
+
+type RevisionExplanation struct {
+	Ready     bool
+	RootCause string
+	Findings  []RevisionFinding
+}
+
+// knownCauses are ordered by how likely they explain all other findings
+var knownCauses = []struct {
+	Reasons     []string
+	Explanation string
+}{
+	{[]string{"ErrImagePull", "ImagePullBackOff", "InvalidImageName"},
+		"Image cannot be pulled; check image name and image pull secrets"},
+	{[]string{"CreateContainerConfigError"},
+		"Container configuration is invalid; check that referenced secrets and config maps exist"},
+	{[]string{"FailedScheduling"},
+		"Pods cannot be scheduled; check cluster capacity and resource requests"},
+	{[]string{"FailedMount"},
+		"Volumes cannot be mounted; check that referenced secrets and config maps exist"},
+	{[]string{"FailedCreate", "ReplicaFailure"},
+		"Pods cannot be created; check resource quotas and admission webhooks"},
+	{[]string{"OOMKilled"},
+		"Container exceeds its memory limit; increase memory limit or reduce memory usage"},
+	{[]string{"CrashLoopBackOff", "Error"},
+		"Container keeps exiting; check its logs via 'knctl logs'"},
+	{[]string{"Unhealthy"},
+		"Probes are failing; check that container listens on $PORT and responds to probes"},
+}
+
+// RevisionExplainer correlates revision conditions, deployment status, pod statuses,
+// pod events and queue-proxy logs to explain why revision is not ready
+type RevisionExplainer struct {
+	coreClient kubernetes.Interface
+}
+
+func NewRevisionExplainer(coreClient kubernetes.Interface) RevisionExplainer {
+	return RevisionExplainer{coreClient}
+}
+
+func (e RevisionExplainer) Explain(revision *v1alpha1.Revision) (RevisionExplanation, error) {
+	explanation := RevisionExplanation{Ready: revision.Status.IsReady()}
+
+	for _, cond := range revision.Status.Conditions {
+		if cond.Status != corev1.ConditionTrue && (len(cond.Reason) > 0 || len(cond.Message) > 0) {
+			explanation.Findings = append(explanation.Findings, RevisionFinding{
+				Source:  fmt.Sprintf("revision condition %s", cond.Type),
+				Reason:  cond.Reason,
+				Message: cond.Message,
+			})
+		}
+	}
+
+	listOpts := metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("%s=%s", serving.RevisionLabelKey, revision.Name),
+	}
+
+	deployments, err := e.coreClient.AppsV1().Deployments(revision.Namespace).List(listOpts)
+	if err != nil {
+		return explanation, fmt.Errorf("Listing deployments: %s", err)
+	}
+
+	for _, dep := range deployments.Items {
+		explanation.Findings = append(explanation.Findings, e.deploymentFindings(dep)...)
+	}
+
+	pods, err := e.coreClient.CoreV1().Pods(revision.Namespace).List(listOpts)
+	if err != nil {
+		return explanation, fmt.Errorf("Listing pods: %s", err)
+	}
+
+	involvedNames := map[string]struct{}{revision.Name: struct{}{}}
+
+	for _, dep := range deployments.Items {
+		involvedNames[dep.Name] = struct{}{}
+	}
+
+	for _, pod := range pods.Items {
+		involvedNames[pod.Name] = struct{}{}
+		explanation.Findings = append(explanation.Findings, e.podFindings(pod)...)
+	}
+
+	eventFindings, err := e.eventFindings(revision.Namespace, involvedNames)
+	if err != nil {
+		return explanation, err
+	}
+
+	explanation.Findings = append(explanation.Findings, eventFindings...)
+
+	for _, pod := range pods.Items {
+		explanation.Findings = append(explanation.Findings, e.queueProxyFindings(pod)...)
+	}
+
+	explanation.RootCause = e.rootCause(explanation)
+
+	return explanation, nil
+}
+
+func (e RevisionExplainer) deploymentFindings(dep appsv1.Deployment) []RevisionFinding {
+	var findings []RevisionFinding
+
+	for _, cond := range dep.Status.Conditions {
+		failing := (cond.Type == appsv1.DeploymentReplicaFailure && cond.Status == corev1.ConditionTrue) ||
+			(cond.Type != appsv1.DeploymentReplicaFailure && cond.Status == corev1.ConditionFalse)
+
+		if failing {
+			reason := cond.Reason
+			if cond.Type == appsv1.DeploymentReplicaFailure && len(reason) == 0 {
+				reason = string(appsv1.DeploymentReplicaFailure)
+			}
+
+			findings = append(findings, RevisionFinding{
+				Source:  fmt.Sprintf("deployment %s", dep.Name),
+				Reason:  reason,
+				Message: cond.Message,
+			})
+		}
+	}
+
+	return findings
+}
+
+func (e RevisionExplainer) podFindings(pod corev1.Pod) []RevisionFinding {
+	var findings []RevisionFinding
+
+	statuses := append(append([]corev1.ContainerStatus{}, pod.Status.InitContainerStatuses...), pod.Status.ContainerStatuses...)
+
+	for _, status := range statuses {
+		source := fmt.Sprintf("pod %s container %s", pod.Name, status.Name)
+
+		if waiting := status.State.Waiting; waiting != nil && len(waiting.Reason) > 0 && waiting.Reason != "ContainerCreating" {
+			findings = append(findings, RevisionFinding{source, waiting.Reason, waiting.Message})
+		}
+
+		if term := status.State.Terminated; term != nil && term.ExitCode != 0 {
+			findings = append(findings, RevisionFinding{source, term.Reason, e.terminatedMessage(term, status.RestartCount)})
+		}
+
+		if term := status.LastTerminationState.Terminated; term != nil && term.ExitCode != 0 {
+			findings = append(findings, RevisionFinding{source, term.Reason, e.terminatedMessage(term, status.RestartCount)})
+		}
+	}
+
+	return findings
+}
+
+func (RevisionExplainer) terminatedMessage(term *corev1.ContainerStateTerminated, restarts int32) string {
+	msg := fmt.Sprintf("Exited with code %d (restarts: %d)", term.ExitCode, restarts)
+	if len(term.Message) > 0 {
+		msg += ": " + strings.TrimSpace(term.Message)
+	}
+	return msg
+}
+
+func (e RevisionExplainer) eventFindings(namespace string, involvedNames map[string]struct{}) ([]RevisionFinding, error) {
+	events, err := e.coreClient.CoreV1().Events(namespace).List(metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("Listing events: %s", err)
+	}
+
+	sort.SliceStable(events.Items, func(i, j int) bool {
+		return events.Items[i].LastTimestamp.Before(&events.Items[j].LastTimestamp)
+	})
+
+	var findings []RevisionFinding
+	seen := map[string]struct{}{}
+
+	for _, event := range events.Items {
+		if event.Type != corev1.EventTypeWarning {
+			continue
+		}
+		if _, found := involvedNames[event.InvolvedObject.Name]; !found {
+			continue
+		}
+
+		// Repeated events (e.g. probe failures) are reported once
+		key := event.InvolvedObject.Name + "/" + event.Reason + "/" + event.Message
+		if _, found := seen[key]; found {
+			continue
+		}
+		seen[key] = struct{}{}
+
+		findings = append(findings, RevisionFinding{
+			Source:  fmt.Sprintf("event for %s %s", strings.ToLower(event.InvolvedObject.Kind), event.InvolvedObject.Name),
+			Reason:  event.Reason,
+			Message: strings.TrimSpace(event.Message),
+		})
+	}
+
+	return findings, nil
+}
+
+func (e RevisionExplainer) queueProxyFindings(pod corev1.Pod) []RevisionFinding {
+	tailLines := int64(queueProxyLogLines)
+
+	logOpts := &corev1.PodLogOptions{Container: queueProxyContainer, TailLines: &tailLines}
+
+	logs, err := e.coreClient.CoreV1().Pods(pod.Namespace).GetLogs(pod.Name, logOpts).DoRaw()
+	if err != nil {
+		return nil // Logs are best effort (e.g. container has not started yet)
+	}
+
+	var findings []RevisionFinding
+
+	for _, line := range strings.Split(string(logs), "\n") {
+		if strings.Contains(strings.ToLower(line), "error") {
+			findings = append(findings, RevisionFinding{
+				Source:  fmt.Sprintf("pod %s container %s log", pod.Name, queueProxyContainer),
+				Message: strings.TrimSpace(line),
+			})
+		}
+	}
+
+	return findings
+}
+
+func (RevisionExplainer) rootCause(explanation RevisionExplanation) string {
+	for _, cause := range knownCauses {
+		for _, reason := range cause.Reasons {
+			for _, finding := range explanation.Findings {
+				if finding.Reason == reason {
+					return cause.Explanation
+				}
+			}
+		}
+	}
+
+	if explanation.Ready {
+		return "Revision is ready"
+	}
+
+	if len(explanation.Findings) > 0 {
+		finding := explanation.Findings[0]
+		return fmt.Sprintf("Revision is not ready: %s %s", finding.Reason, finding.Message)
+	}
+
+	return "Revision is not ready, but no failures were found; it may still be starting"
+}
@@ -0,0 +1,168 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package revision_test
+
+import (
+	"testing"
+
+	. "github.com/cppforlife/knctl/pkg/knctl/cmd"
+	. "github.com/cppforlife/knctl/pkg/knctl/cmd/revision"
+	"github.com/cppforlife/knctl/pkg/knctl/testkit"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func TestRevisionExplainer_ImagePullFailure(t *testing.T) {
+	revisionLabels := map[string]string{"serving.knative.dev/revision": "svc1-00001"}
+
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "svc1-00001-deployment", Namespace: "ns1", Labels: revisionLabels},
+		Status: appsv1.DeploymentStatus{
+			Conditions: []appsv1.DeploymentCondition{
+				{Type: appsv1.DeploymentAvailable, Status: corev1.ConditionFalse,
+					Reason: "MinimumReplicasUnavailable", Message: "Deployment does not have minimum availability."},
+				{Type: appsv1.DeploymentProgressing, Status: corev1.ConditionTrue, Reason: "ReplicaSetUpdated"},
+			},
+		},
+	}
+
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "svc1-00001-pod1", Namespace: "ns1", Labels: revisionLabels},
+		Status: corev1.PodStatus{
+			ContainerStatuses: []corev1.ContainerStatus{
+				{Name: "user-container", State: corev1.ContainerState{
+					Waiting: &corev1.ContainerStateWaiting{Reason: "ImagePullBackOff", Message: "Back-off pulling image"},
+				}},
+				{Name: "queue-proxy", State: corev1.ContainerState{
+					Running: &corev1.ContainerStateRunning{},
+				}},
+			},
+		},
+	}
+
+	cluster := testkit.NewCluster(t,
+		testkit.NewRevision("ns1", "svc1", "svc1-00001").Build(),
+		deployment,
+		pod,
+		newExplainTestEvent("event1", "Pod", "svc1-00001-pod1", corev1.EventTypeWarning, "Failed", "Failed to pull image"),
+		newExplainTestEvent("event2", "Pod", "svc1-00001-pod1", corev1.EventTypeWarning, "Failed", "Failed to pull image"),
+		newExplainTestEvent("event3", "Pod", "svc1-00001-pod1", corev1.EventTypeNormal, "Pulling", "Pulling image"),
+		newExplainTestEvent("event4", "Pod", "other-pod", corev1.EventTypeWarning, "Unhealthy", "Probe failed"),
+	)
+
+	revision, err := cluster.ServingClient().ServingV1alpha1().Revisions("ns1").Get("svc1-00001", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	explanation, err := NewRevisionExplainer(cluster.CoreClient()).Explain(revision)
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	DeepEqual(t, explanation, RevisionExplanation{
+		RootCause: "Image cannot be pulled; check image name and image pull secrets",
+		Findings: []RevisionFinding{
+			{
+				Source:  "deployment svc1-00001-deployment",
+				Reason:  "MinimumReplicasUnavailable",
+				Message: "Deployment does not have minimum availability.",
+			},
+			{
+				Source:  "pod svc1-00001-pod1 container user-container",
+				Reason:  "ImagePullBackOff",
+				Message: "Back-off pulling image",
+			},
+			{
+				Source:  "event for pod svc1-00001-pod1",
+				Reason:  "Failed",
+				Message: "Failed to pull image",
+			},
+		},
+	})
+}
+
+func TestRevisionExplainer_OOMKilled(t *testing.T) {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "svc1-00001-pod1",
+			Namespace: "ns1",
+			Labels:    map[string]string{"serving.knative.dev/revision": "svc1-00001"},
+		},
+		Status: corev1.PodStatus{
+			ContainerStatuses: []corev1.ContainerStatus{
+				{
+					Name:         "user-container",
+					RestartCount: 3,
+					State: corev1.ContainerState{
+						Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"},
+					},
+					LastTerminationState: corev1.ContainerState{
+						Terminated: &corev1.ContainerStateTerminated{Reason: "OOMKilled", ExitCode: 137},
+					},
+				},
+			},
+		},
+	}
+
+	cluster := testkit.NewCluster(t, testkit.NewRevision("ns1", "svc1", "svc1-00001").Build(), pod)
+
+	revision, err := cluster.ServingClient().ServingV1alpha1().Revisions("ns1").Get("svc1-00001", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	explanation, err := NewRevisionExplainer(cluster.CoreClient()).Explain(revision)
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	DeepEqual(t, explanation.RootCause, "Container exceeds its memory limit; increase memory limit or reduce memory usage")
+	DeepEqual(t, explanation.Findings[1], RevisionFinding{
+		Source:  "pod svc1-00001-pod1 container user-container",
+		Reason:  "OOMKilled",
+		Message: "Exited with code 137 (restarts: 3)",
+	})
+}
+
+func TestRevisionExplainer_Ready(t *testing.T) {
+	cluster := testkit.NewCluster(t, testkit.NewRevision("ns1", "svc1", "svc1-00001").Ready().Build())
+
+	revision, err := cluster.ServingClient().ServingV1alpha1().Revisions("ns1").Get("svc1-00001", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	explanation, err := NewRevisionExplainer(cluster.CoreClient()).Explain(revision)
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	DeepEqual(t, explanation, RevisionExplanation{Ready: true, RootCause: "Revision is ready"})
+}
+
+func newExplainTestEvent(name, kind, objName, type_, reason, msg string) runtime.Object {
+	return &corev1.Event{
+		ObjectMeta:     metav1.ObjectMeta{Name: name, Namespace: "ns1"},
+		InvolvedObject: corev1.ObjectReference{Kind: kind, Name: objName, Namespace: "ns1"},
+		Type:           type_,
+		Reason:         reason,
+		Message:        msg,
+	}
+}
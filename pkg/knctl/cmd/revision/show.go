@@ -24,10 +24,12 @@ import (
 	uitable "github.com/cppforlife/go-cli-ui/ui/table"
 	cmdcore "github.com/cppforlife/knctl/pkg/knctl/cmd/core"
 	cmdflags "github.com/cppforlife/knctl/pkg/knctl/cmd/flags"
+	"github.com/cppforlife/knctl/pkg/knctl/redact"
 	ctlservice "github.com/cppforlife/knctl/pkg/knctl/service"
 	"github.com/knative/serving/pkg/apis/serving/v1alpha1"
 	"github.com/spf13/cobra"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
 )
 
 type ShowOptions struct {
@@ -35,6 +37,7 @@ type ShowOptions struct {
 	depsFactory cmdcore.DepsFactory
 
 	RevisionFlags cmdflags.RevisionFlags
+	RedactFlags   cmdflags.RedactFlags
 }
 
 func NewShowOptions(ui ui.UI, depsFactory cmdcore.DepsFactory) *ShowOptions {
@@ -48,10 +51,14 @@ func NewShowCmd(o *ShowOptions, flagsFactory cmdcore.FlagsFactory) *cobra.Comman
 		Long:  "Show revision details in a namespace",
 		Example: `
   # Show details for revison 'rev1' in namespace 'ns1'
-  knctl revision show -r rev1 -n ns1`,
+  knctl revision show -r rev1 -n ns1
+
+  # Show details for revison 'rev1' in namespace 'ns1' including secret values
+  knctl revision show -r rev1 -n ns1 --show-secrets`,
 		RunE: func(_ *cobra.Command, _ []string) error { return o.Run() },
 	}
 	o.RevisionFlags.Set(cmd, flagsFactory)
+	o.RedactFlags.Set(cmd, flagsFactory)
 	return cmd
 }
 
@@ -68,7 +75,17 @@ func (o *ShowOptions) Run() error {
 		return err
 	}
 
-	o.printStatus(revision, tags)
+	coreClient, err := o.depsFactory.CoreClient()
+	if err != nil {
+		return err
+	}
+
+	env, err := o.envValues(revision, coreClient)
+	if err != nil {
+		return err
+	}
+
+	o.printStatus(revision, tags, env)
 
 	cmdcore.NewConditionsTable(revision.Status.Conditions).Print(o.ui)
 
@@ -82,7 +99,7 @@ func (o *ShowOptions) Run() error {
 	return nil
 }
 
-func (o *ShowOptions) printStatus(revision *v1alpha1.Revision, tags ctlservice.Tags) {
+func (o *ShowOptions) printStatus(revision *v1alpha1.Revision, tags ctlservice.Tags, env []string) {
 	table := uitable.Table{
 		Title: fmt.Sprintf("Revision '%s'", o.RevisionFlags.Name),
 		// TODO Content: "revision",
@@ -91,6 +108,7 @@ func (o *ShowOptions) printStatus(revision *v1alpha1.Revision, tags ctlservice.T
 			uitable.NewHeader("Name"),
 			uitable.NewHeader("Tags"),
 			uitable.NewHeader("Image digest"),
+			uitable.NewHeader("Env"),
 			uitable.NewHeader("Log URL"),
 			uitable.NewHeader("Annotations"),
 			uitable.NewHeader("Age"),
@@ -103,6 +121,7 @@ func (o *ShowOptions) printStatus(revision *v1alpha1.Revision, tags ctlservice.T
 		uitable.NewValueString(revision.Name),
 		uitable.NewValueStrings(tags.List(*revision)),
 		uitable.NewValueString(revision.Status.ImageDigest),
+		uitable.NewValueStrings(env),
 		uitable.NewValueString(strings.TrimSpace(revision.Status.LogURL)),
 		cmdcore.NewAnnotationsValue(revision.Annotations),
 		cmdcore.NewValueAge(revision.CreationTimestamp.Time),
@@ -111,6 +130,44 @@ func (o *ShowOptions) printStatus(revision *v1alpha1.Revision, tags ctlservice.T
 	o.ui.PrintTable(table)
 }
 
+func (o *ShowOptions) envValues(revision *v1alpha1.Revision, coreClient kubernetes.Interface) ([]string, error) {
+	var result []string
+
+	secretValues := redact.NewSecretValues(coreClient)
+
+	for _, env := range revision.Spec.Container.Env {
+		switch {
+		case env.ValueFrom == nil:
+			result = append(result, fmt.Sprintf("%s=%s", env.Name, env.Value))
+
+		case env.ValueFrom.SecretKeyRef != nil:
+			ref := env.ValueFrom.SecretKeyRef
+			val := redact.RedactedValue
+
+			if o.RedactFlags.ShowSecrets {
+				secretVal, found, err := secretValues.Value(revision.Namespace, *ref)
+				if err != nil {
+					return nil, err
+				}
+				if found {
+					val = secretVal
+				}
+			}
+
+			result = append(result, fmt.Sprintf("%s=%s (secret '%s' key '%s')", env.Name, val, ref.Name, ref.Key))
+
+		case env.ValueFrom.ConfigMapKeyRef != nil:
+			ref := env.ValueFrom.ConfigMapKeyRef
+			result = append(result, fmt.Sprintf("%s (config map '%s' key '%s')", env.Name, ref.Name, ref.Key))
+
+		default:
+			result = append(result, fmt.Sprintf("%s (reference)", env.Name))
+		}
+	}
+
+	return result, nil
+}
+
 func (o *ShowOptions) setUpPodWatching(revision *v1alpha1.Revision) (chan corev1.Pod, error) {
 	podsToWatchCh := make(chan corev1.Pod)
 	cancelCh := make(chan struct{})
@@ -45,11 +45,14 @@ func TestNewShowCmd_OkLongFlagNames(t *testing.T) {
 	cmd.Execute([]string{
 		"--namespace", "test-namespace",
 		"--revision", "test-revision",
+		"--show-secrets",
 	})
 	cmd.ExpectReachesExecution()
 
 	DeepEqual(t, realCmd.RevisionFlags,
 		cmdflags.RevisionFlags{cmdcore.NamespaceFlags{"test-namespace"}, "test-revision"})
+
+	DeepEqual(t, realCmd.RedactFlags, cmdflags.RedactFlags{ShowSecrets: true})
 }
 
 func TestNewShowCmd_RequiredFlags(t *testing.T) {
@@ -0,0 +1,252 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package revision
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/cppforlife/go-cli-ui/ui"
+	cmdautoscaler "github.com/cppforlife/knctl/pkg/knctl/cmd/autoscaler"
+	"github.com/knative/serving/pkg/apis/serving"
+	"github.com/knative/serving/pkg/apis/serving/v1alpha1"
+	servingclientset "github.com/knative/serving/pkg/client/clientset/versioned"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/util/retry"
+)
+
+// TrafficDrain removes revisions from routes and waits for their
+// in-flight requests to finish so that deleting them does not result
+// in failed requests. Routes managed by a service are not modified
+// (service controller would revert changes); revisions routed by them
+// have to be moved off via service's configuration first.
+type TrafficDrain struct {
+	coreClient    kubernetes.Interface
+	servingClient servingclientset.Interface
+	ui            ui.UI
+
+	Timeout      time.Duration
+	PollInterval time.Duration
+	// IdleWait is used instead of observed concurrency when
+	// autoscaler metrics are not available
+	IdleWait time.Duration
+}
+
+func NewTrafficDrain(coreClient kubernetes.Interface, servingClient servingclientset.Interface, ui ui.UI, timeout time.Duration) TrafficDrain {
+	return TrafficDrain{
+		coreClient:    coreClient,
+		servingClient: servingClient,
+		ui:            ui,
+
+		Timeout:      timeout,
+		PollInterval: 2 * time.Second,
+		IdleWait:     30 * time.Second,
+	}
+}
+
+// Drain drains given revisions; routes managed by owningService
+// are skipped since they are deleted together with the service
+func (d TrafficDrain) Drain(namespace string, revisionNames []string, owningService string) error {
+	if len(revisionNames) == 0 {
+		return nil
+	}
+
+	revisions := map[string]struct{}{}
+	for _, name := range revisionNames {
+		revisions[name] = struct{}{}
+	}
+
+	routes, err := d.servingClient.ServingV1alpha1().Routes(namespace).List(metav1.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("Listing routes: %s", err)
+	}
+
+	// Revisions served by owningService's routes only stop
+	// receiving traffic once service is deleted
+	servedByOwner := map[string]struct{}{}
+
+	for _, route := range routes.Items {
+		serviceName := route.Labels[serving.ServiceLabelKey]
+
+		if len(owningService) > 0 && serviceName == owningService {
+			for _, target := range route.Status.Traffic {
+				servedByOwner[target.RevisionName] = struct{}{}
+			}
+			continue
+		}
+
+		if len(serviceName) > 0 {
+			for _, target := range route.Status.Traffic {
+				if _, found := revisions[target.RevisionName]; found {
+					return fmt.Errorf("Expected revision '%s' to not receive traffic from route '%s' "+
+						"managed by service '%s' (update service's traffic first)", target.RevisionName, route.Name, serviceName)
+				}
+			}
+			continue
+		}
+
+		err := d.removeFromRoute(namespace, route.Name, revisions)
+		if err != nil {
+			return err
+		}
+	}
+
+	deadline := time.Now().Add(d.Timeout)
+
+	err = d.waitForRoutes(namespace, revisions, owningService, deadline)
+	if err != nil {
+		return err
+	}
+
+	idleRevisions := map[string]struct{}{}
+
+	for name := range revisions {
+		if _, found := servedByOwner[name]; found {
+			d.ui.PrintLinef("Skipping waiting for revision '%s' to become idle since it's served by service '%s'", name, owningService)
+		} else {
+			idleRevisions[name] = struct{}{}
+		}
+	}
+
+	if len(idleRevisions) == 0 {
+		return nil
+	}
+
+	return d.waitForIdle(namespace, idleRevisions, deadline)
+}
+
+func (d TrafficDrain) removeFromRoute(namespace, name string, revisions map[string]struct{}) error {
+	routes := d.servingClient.ServingV1alpha1().Routes(namespace)
+
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		route, err := routes.Get(name, metav1.GetOptions{})
+		if err != nil {
+			return fmt.Errorf("Getting route '%s': %s", name, err)
+		}
+
+		var remaining []v1alpha1.TrafficTarget
+		var removedPercent int
+
+		for _, target := range route.Spec.Traffic {
+			if _, found := revisions[target.RevisionName]; found {
+				removedPercent += target.Percent
+			} else {
+				remaining = append(remaining, target)
+			}
+		}
+
+		if len(remaining) == len(route.Spec.Traffic) {
+			return nil
+		}
+
+		if len(remaining) == 0 {
+			return fmt.Errorf("Expected route '%s' to have other traffic targets besides drained revisions", name)
+		}
+
+		// Removed share goes to the first remaining target so that percentages add up
+		remaining[0].Percent += removedPercent
+		route.Spec.Traffic = remaining
+
+		d.ui.PrintLinef("Removing drained revisions from route '%s'", name)
+
+		_, err = routes.Update(route)
+		return err
+	})
+}
+
+func (d TrafficDrain) waitForRoutes(namespace string, revisions map[string]struct{}, owningService string, deadline time.Time) error {
+	d.ui.PrintLinef("Waiting for routes to stop sending traffic")
+
+	var lastReferences []string
+
+	err := wait.PollImmediate(d.PollInterval, time.Until(deadline), func() (bool, error) {
+		routes, err := d.servingClient.ServingV1alpha1().Routes(namespace).List(metav1.ListOptions{})
+		if err != nil {
+			return false, fmt.Errorf("Listing routes: %s", err)
+		}
+
+		lastReferences = nil
+
+		for _, route := range routes.Items {
+			if len(owningService) > 0 && route.Labels[serving.ServiceLabelKey] == owningService {
+				continue
+			}
+			for _, target := range route.Status.Traffic {
+				if _, found := revisions[target.RevisionName]; found {
+					lastReferences = append(lastReferences,
+						fmt.Sprintf("route '%s' -> revision '%s'", route.Name, target.RevisionName))
+				}
+			}
+		}
+
+		return len(lastReferences) == 0, nil
+	})
+	if err == wait.ErrWaitTimeout {
+		return fmt.Errorf("Timed out waiting for routes to stop sending traffic: %s", strings.Join(lastReferences, ", "))
+	}
+
+	return err
+}
+
+func (d TrafficDrain) waitForIdle(namespace string, revisions map[string]struct{}, deadline time.Time) error {
+	metricsSource := cmdautoscaler.NewAutoscalerMetrics(d.coreClient)
+
+	_, err := metricsSource.List(namespace)
+	if err != nil {
+		idleWait := d.IdleWait
+		if remaining := time.Until(deadline); remaining < idleWait {
+			idleWait = remaining
+		}
+
+		d.ui.PrintLinef("Autoscaler metrics are not available (%s); waiting %s for in-flight requests", err, idleWait)
+		time.Sleep(idleWait)
+
+		return nil
+	}
+
+	d.ui.PrintLinef("Waiting for in-flight requests to finish")
+
+	var busy []string
+
+	err = wait.PollImmediate(d.PollInterval, time.Until(deadline), func() (bool, error) {
+		metrics, err := metricsSource.List(namespace)
+		if err != nil {
+			return false, err
+		}
+
+		busy = nil
+
+		// Revisions without metrics are not tracked by autoscaler (e.g. scaled to zero)
+		for name := range revisions {
+			if revMetrics, found := metrics[name]; found {
+				if revMetrics.StableConcurrency > 0 || revMetrics.PanicConcurrency > 0 {
+					busy = append(busy, fmt.Sprintf("%s (concurrency %g)", name, revMetrics.StableConcurrency))
+				}
+			}
+		}
+
+		return len(busy) == 0, nil
+	})
+	if err == wait.ErrWaitTimeout {
+		return fmt.Errorf("Timed out waiting for in-flight requests to finish: %s", strings.Join(busy, ", "))
+	}
+
+	return err
+}
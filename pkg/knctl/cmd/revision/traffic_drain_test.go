@@ -0,0 +1,116 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package revision_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cppforlife/go-cli-ui/ui"
+	. "github.com/cppforlife/knctl/pkg/knctl/cmd"
+	. "github.com/cppforlife/knctl/pkg/knctl/cmd/revision"
+	"github.com/cppforlife/knctl/pkg/knctl/testkit"
+	"github.com/knative/serving/pkg/apis/serving/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestTrafficDrain_RemovesRevisionFromStandaloneRoutes(t *testing.T) {
+	route := testkit.NewRoute("ns1", "route1").Traffic("rev1", 30).Traffic("rev2", 70).Build()
+	// Route controller already observed the change
+	route.Status.Traffic = []v1alpha1.TrafficTarget{{RevisionName: "rev2", Percent: 100}}
+
+	cluster := testkit.NewCluster(t, route)
+
+	err := newTestTrafficDrain(cluster).Drain("ns1", []string{"rev1"}, "")
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	route, err = cluster.ServingClient().ServingV1alpha1().Routes("ns1").Get("route1", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	DeepEqual(t, route.Spec.Traffic, []v1alpha1.TrafficTarget{{RevisionName: "rev2", Percent: 100}})
+}
+
+func TestTrafficDrain_ServiceManagedRoute(t *testing.T) {
+	route := testkit.NewRoute("ns1", "svc1").Traffic("rev1", 100).Build()
+	route.Labels = map[string]string{"serving.knative.dev/service": "svc1"}
+
+	cluster := testkit.NewCluster(t, route)
+
+	err := newTestTrafficDrain(cluster).Drain("ns1", []string{"rev1"}, "")
+	if err == nil || !strings.Contains(err.Error(), "managed by service 'svc1'") {
+		t.Fatalf("Expected service managed route error, but was: %v", err)
+	}
+
+	// Routes of service that is being deleted are skipped
+	err = newTestTrafficDrain(cluster).Drain("ns1", []string{"rev1"}, "svc1")
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+}
+
+func TestTrafficDrain_SkipsIdleWaitForRevisionsServedByOwningService(t *testing.T) {
+	route := testkit.NewRoute("ns1", "svc1").Traffic("rev1", 100).Build()
+	route.Labels = map[string]string{"serving.knative.dev/service": "svc1"}
+
+	cluster := testkit.NewCluster(t, route)
+
+	drain := newTestTrafficDrain(cluster)
+	drain.Timeout = time.Minute
+	drain.IdleWait = time.Minute
+
+	startedAt := time.Now()
+
+	err := drain.Drain("ns1", []string{"rev1"}, "svc1")
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	if time.Since(startedAt) > 10*time.Second {
+		t.Fatalf("Expected to not wait for revision served by service being deleted to become idle")
+	}
+}
+
+func TestTrafficDrain_LastTarget(t *testing.T) {
+	cluster := testkit.NewCluster(t, testkit.NewRoute("ns1", "route1").Traffic("rev1", 100).Build())
+
+	err := newTestTrafficDrain(cluster).Drain("ns1", []string{"rev1"}, "")
+	if err == nil || !strings.Contains(err.Error(), "Expected route 'route1' to have other traffic targets") {
+		t.Fatalf("Expected last target error, but was: %v", err)
+	}
+}
+
+func TestTrafficDrain_TimesOutWaitingForRoutes(t *testing.T) {
+	// Route status keeps referencing revision (e.g. route controller is not running)
+	cluster := testkit.NewCluster(t, testkit.NewRoute("ns1", "route1").Traffic("rev1", 50).Traffic("rev2", 50).Build())
+
+	err := newTestTrafficDrain(cluster).Drain("ns1", []string{"rev1"}, "")
+	if err == nil || !strings.Contains(err.Error(), "Timed out waiting for routes to stop sending traffic: route 'route1' -> revision 'rev1'") {
+		t.Fatalf("Expected timeout error, but was: %v", err)
+	}
+}
+
+func newTestTrafficDrain(cluster *testkit.Cluster) TrafficDrain {
+	drain := NewTrafficDrain(cluster.CoreClient(), cluster.ServingClient(), ui.NewNoopUI(), 100*time.Millisecond)
+	drain.PollInterval = 10 * time.Millisecond
+	drain.IdleWait = time.Millisecond
+	return drain
+}
@@ -32,3 +32,14 @@ func NewCmd() *cobra.Command {
 	}
 	return cmd
 }
+
+func NewFaultCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "fault",
+		Short: "Fault injection management",
+		Annotations: map[string]string{
+			cmdcore.RouteMgmtHelpGroup.Key: cmdcore.RouteMgmtHelpGroup.Value,
+		},
+	}
+	return cmd
+}
@@ -18,180 +18,170 @@ package route
 
 import (
 	"fmt"
-	"strconv"
 	"strings"
-	"time"
+	"sync"
 
 	"github.com/cppforlife/go-cli-ui/ui"
 	cmdcore "github.com/cppforlife/knctl/pkg/knctl/cmd/core"
 	cmdflags "github.com/cppforlife/knctl/pkg/knctl/cmd/flags"
-	cmdrev "github.com/cppforlife/knctl/pkg/knctl/cmd/revision"
+	ctlhistory "github.com/cppforlife/knctl/pkg/knctl/history"
+	ctlroute "github.com/cppforlife/knctl/pkg/knctl/route"
 	ctlservice "github.com/cppforlife/knctl/pkg/knctl/service"
-	"github.com/cppforlife/knctl/pkg/knctl/util"
 	"github.com/knative/serving/pkg/apis/serving/v1alpha1"
 	servingclientset "github.com/knative/serving/pkg/client/clientset/versioned"
 	"github.com/spf13/cobra"
-	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 type CreateOptions struct {
-	ui          ui.UI
-	depsFactory cmdcore.DepsFactory
+	ui            ui.UI
+	depsFactory   cmdcore.DepsFactory
+	cancelWatcher cmdcore.CancelWatcher
 
-	RouteFlags   RouteFlags
-	TrafficFlags TrafficFlags
+	RouteFlags    RouteFlags
+	SelectorFlags cmdflags.SelectorFlags
+	TrafficFlags  TrafficFlags
 }
 
 func NewCreateOptions(ui ui.UI, depsFactory cmdcore.DepsFactory) *CreateOptions {
 	return &CreateOptions{ui: ui, depsFactory: depsFactory}
 }
 
+// WithCancelWatcher returns options that stop updating route once cancelled (used by Go API)
+func (o *CreateOptions) WithCancelWatcher(cancelWatcher cmdcore.CancelWatcher) *CreateOptions {
+	o.cancelWatcher = cancelWatcher
+	return o
+}
+
 func NewCreateCmd(o *CreateOptions, flagsFactory cmdcore.FlagsFactory) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "rollout",
 		Short: "Create or update route",
 		Long: `Create or update route with traffic percentages.
 
-If route was automatically created for a service, service must be deployed with '--managed-route=false' flag on all subsequent deploys.`,
+If route was automatically created for a service, service must be deployed with '--managed-route=false' flag on all subsequent deploys.
+
+With --selector, route named after each matching service is updated and
+revision percentages reference service's revision tags (e.g. latest=20%).`,
 		Example: `
   # Set traffic percentages for service 'svc1' in namespace 'ns1'
   knctl rollout --route rt1 -p svc1:latest=20% -p svc1:previous=80% -n ns1
 
   # Roll back traffic for previous revision of service 'svc1' in namespace 'ns1'
-  knctl rollout --route rt1 -p svc1:previous=100% -n ns1`,
+  knctl rollout --route rt1 -p svc1:previous=100% -n ns1
+
+  # Roll back traffic for all services with label 'team=payments' in namespace 'ns1'
+  knctl rollout --selector team=payments -p previous=100% -n ns1`,
 		Annotations: map[string]string{
 			cmdcore.RouteMgmtHelpGroup.Key: cmdcore.RouteMgmtHelpGroup.Value,
 		},
+		// Subcommands (e.g. mirror) make cobra accept unknown names as args
+		Args: cobra.NoArgs,
 		RunE: func(_ *cobra.Command, _ []string) error { return o.Run() },
 	}
-	o.RouteFlags.Set(cmd, flagsFactory)
+	o.RouteFlags.SetOptional(cmd, flagsFactory)
+	o.SelectorFlags.Set(cmd, flagsFactory)
 	o.TrafficFlags.Set(cmd, flagsFactory)
 	return cmd
 }
 
 func (o *CreateOptions) Run() error {
-	servingClient, err := o.depsFactory.ServingClient()
+	err := o.SelectorFlags.ValidateWithName(o.RouteFlags.Name, "route")
 	if err != nil {
 		return err
 	}
 
-	tags := ctlservice.NewTags(servingClient)
+	servingClient, err := o.depsFactory.ServingClient()
+	if err != nil {
+		return err
+	}
 
-	route := &v1alpha1.Route{
-		ObjectMeta: o.TrafficFlags.GenerateNameFlags.Apply(metav1.ObjectMeta{
-			Name:      o.RouteFlags.Name,
-			Namespace: o.RouteFlags.NamespaceFlags.Name,
-		}),
+	if len(o.SelectorFlags.Selector) == 0 {
+		return o.rollout(servingClient, o.RouteFlags.Name, "")
 	}
 
-	var targets []v1alpha1.TrafficTarget
+	if len(o.TrafficFlags.ServicePercentages) > 0 {
+		return fmt.Errorf("Expected service percentages to not be specified together with --selector")
+	}
 
 	for _, traffic := range o.TrafficFlags.RevisionPercentages {
-		name, percent, err := o.extractNameAndPercentage(traffic)
-		if err != nil {
-			return err
+		if strings.Contains(strings.SplitN(traffic, "=", 2)[0], ":") {
+			return fmt.Errorf("Expected percentage to reference revision tag (example: latest=100%%) when selector is used")
 		}
+	}
 
-		revFlags := cmdflags.RevisionFlags{Name: name, NamespaceFlags: o.RouteFlags.NamespaceFlags}
+	names, err := ctlservice.NewSelection(servingClient).Names(o.RouteFlags.NamespaceFlags.Name, o.SelectorFlags.Selector)
+	if err != nil {
+		return err
+	}
 
-		revision, err := cmdrev.NewReference(revFlags, tags, servingClient).Revision()
-		if err != nil {
-			return err
-		}
+	results := cmdcore.NewBatchResults(fmt.Sprintf("Rolled out services matching '%s'", o.SelectorFlags.Selector))
 
-		targets = append(targets, v1alpha1.TrafficTarget{
-			RevisionName: revision.Name,
-			Percent:      percent,
-		})
+	for _, name := range names {
+		// Assumes that service has the same name as the route
+		results.Add(name, o.rollout(servingClient, name, name))
 	}
 
-	for _, traffic := range o.TrafficFlags.ServicePercentages {
-		name, percent, err := o.extractNameAndPercentage(traffic)
-		if err != nil {
-			return err
-		}
+	results.Print(o.ui)
 
-		targets = append(targets, v1alpha1.TrafficTarget{
-			ConfigurationName: name,
-			Percent:           percent,
-		})
-	}
+	return results.Err()
+}
 
-	route.Spec.Traffic = targets
+// rollout updates route's traffic; when serviceName is specified
+// revision percentages reference tags of that service
+func (o *CreateOptions) rollout(servingClient servingclientset.Interface, routeName, serviceName string) error {
+	rollout := ctlroute.NewRollout(servingClient)
 
-	err = o.ensureUnmanagedRouteOnService(servingClient)
+	targets, err := rollout.Targets(o.RouteFlags.NamespaceFlags.Name, serviceName,
+		o.TrafficFlags.RevisionPercentages, o.TrafficFlags.ServicePercentages)
 	if err != nil {
 		return err
 	}
 
-	return o.createOrUpdate(servingClient, route)
-}
-
-func (o *CreateOptions) extractNameAndPercentage(str string) (string, int, error) {
-	pieces := strings.SplitN(str, "=", 2)
-	if len(pieces) != 2 {
-		return "", 0, fmt.Errorf("Expected percentage to be in format 'service=percentage'")
+	route := &v1alpha1.Route{
+		ObjectMeta: o.TrafficFlags.GenerateNameFlags.Apply(metav1.ObjectMeta{
+			Name:      routeName,
+			Namespace: o.RouteFlags.NamespaceFlags.Name,
+		}),
+		Spec: v1alpha1.RouteSpec{Traffic: targets},
 	}
 
-	percent, err := strconv.Atoi(strings.TrimSuffix(pieces[1], "%"))
-	if err != nil {
-		return "", 0, fmt.Errorf("Expected percentage value to be an integer")
-	}
+	var cancelCh chan struct{}
 
-	if percent < 0 || percent > 100 {
-		return "", 0, fmt.Errorf("Expected percentage value to be between 0%% and 100%%")
+	if o.cancelWatcher != nil {
+		cancelCh = make(chan struct{})
+		var cancelOnce sync.Once
+		o.cancelWatcher.Watch(func() { cancelOnce.Do(func() { close(cancelCh) }) })
 	}
 
-	return pieces[0], percent, nil
-}
-
-func (o *CreateOptions) ensureUnmanagedRouteOnService(servingClient servingclientset.Interface) error {
-	// Assumes that service has the same name as the route
-	// TODO this may not be a proper assumption to make
-	service, err := servingClient.ServingV1alpha1().Services(o.RouteFlags.NamespaceFlags.Name).Get(o.RouteFlags.Name, metav1.GetOptions{})
+	prevTraffic, err := rollout.Apply(route, cancelCh)
 	if err != nil {
-		if errors.IsNotFound(err) {
-			return nil
-		}
-		return fmt.Errorf("Getting associated service: %s", err)
+		return err
 	}
 
-	if service.Spec.Manual == nil {
-		hintMsg := "(use `--managed-route=false` flag when running `deploy` command)"
-		return fmt.Errorf("Expected associated service '%s' to not manage route %s", o.RouteFlags.Name, hintMsg)
+	// Generated route names are not known upfront hence are not recorded
+	if len(routeName) > 0 {
+		o.recordHistory(routeName, prevTraffic, targets)
 	}
 
 	return nil
 }
 
-func (o *CreateOptions) createOrUpdate(servingClient servingclientset.Interface, route *v1alpha1.Route) error {
-	_, createErr := servingClient.ServingV1alpha1().Routes(o.RouteFlags.NamespaceFlags.Name).Create(route)
-	if createErr != nil {
-		if errors.IsAlreadyExists(createErr) {
-			return o.update(servingClient, route)
-		}
-
-		return fmt.Errorf("Creating route: %s", createErr)
+func (o *CreateOptions) recordHistory(routeName string, prevTraffic, traffic []v1alpha1.TrafficTarget) {
+	coreClient, err := o.depsFactory.CoreClient()
+	if err != nil {
+		o.ui.ErrorLinef("Warning: %s", err)
+		return
 	}
 
-	return nil
-}
-
-func (o *CreateOptions) update(servingClient servingclientset.Interface, route *v1alpha1.Route) error {
-	return util.Retry(time.Second, 10*time.Second, func() (bool, error) {
-		origRoute, err := servingClient.ServingV1alpha1().Routes(o.RouteFlags.NamespaceFlags.Name).Get(o.RouteFlags.Name, metav1.GetOptions{})
-		if err != nil {
-			return true, err
-		}
-
-		origRoute.Spec = route.Spec
-
-		_, err = servingClient.ServingV1alpha1().Routes(o.RouteFlags.NamespaceFlags.Name).Update(origRoute)
-		if err != nil {
-			return false, fmt.Errorf("Updating route: %s", err)
-		}
+	// Assumes that service has the same name as the route
+	entry := ctlhistory.NewEntry(ctlhistory.OperationRollout, routeName)
+	entry.Route = routeName
+	entry.Traffic = traffic
+	entry.PreviousTraffic = prevTraffic
 
-		return true, nil
-	})
+	err = ctlhistory.NewHistory(coreClient).Record(o.RouteFlags.NamespaceFlags.Name, entry)
+	if err != nil {
+		o.ui.ErrorLinef("Warning: %s", err)
+	}
 }
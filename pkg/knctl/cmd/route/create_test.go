@@ -17,11 +17,18 @@ limitations under the License.
 package route_test
 
 import (
+	"strings"
 	"testing"
 
+	"github.com/cppforlife/go-cli-ui/ui"
 	. "github.com/cppforlife/knctl/pkg/knctl/cmd"
 	cmdcore "github.com/cppforlife/knctl/pkg/knctl/cmd/core"
+	cmdflags "github.com/cppforlife/knctl/pkg/knctl/cmd/flags"
 	. "github.com/cppforlife/knctl/pkg/knctl/cmd/route"
+	ctlhistory "github.com/cppforlife/knctl/pkg/knctl/history"
+	"github.com/cppforlife/knctl/pkg/knctl/testkit"
+	"github.com/knative/serving/pkg/apis/serving/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 func TestNewCreateCmd_Ok(t *testing.T) {
@@ -85,9 +92,150 @@ func TestNewCreateCmd_OkMinimum(t *testing.T) {
 	DeepEqual(t, realCmd.TrafficFlags, TrafficFlags{RevisionPercentages: nil})
 }
 
-func TestNewCreateCmd_RequiredFlags(t *testing.T) {
+func TestNewCreateCmd_Selector(t *testing.T) {
 	realCmd := NewCreateOptions(nil, cmdcore.NewDepsFactory())
 	cmd := NewTestCmd(t, NewCreateCmd(realCmd, cmdcore.FlagsFactory{}))
-	cmd.Execute([]string{})
-	cmd.ExpectRequiredFlags([]string{"route"})
+	cmd.Execute([]string{"-n", "test-namespace", "--selector", "team=payments", "-p", "latest=100%"})
+	cmd.ExpectReachesExecution()
+
+	DeepEqual(t, realCmd.SelectorFlags, cmdflags.SelectorFlags{"team=payments"})
+}
+
+func TestCreateOptions_RequiresRouteOrSelector(t *testing.T) {
+	opts := NewCreateOptions(ui.NewNoopUI(), testkit.NewCluster(t).DepsFactory())
+	opts.RouteFlags = RouteFlags{cmdcore.NamespaceFlags{"ns1"}, ""}
+
+	err := opts.Run()
+	if err == nil || err.Error() != "Expected either --route or --selector to be specified" {
+		t.Fatalf("Expected route or selector error, but was: %v", err)
+	}
+}
+
+func TestCreateOptions_RollsOutSelectedServices(t *testing.T) {
+	svc1 := testkit.NewService("ns1", "svc1").Manual().Build()
+	svc1.Labels = map[string]string{"team": "payments"}
+
+	svc2 := testkit.NewService("ns1", "svc2").Build()
+	svc2.Labels = map[string]string{"team": "payments"}
+
+	cluster := testkit.NewCluster(t,
+		svc1, svc2,
+		testkit.NewRevision("ns1", "svc1", "svc1-00001").Tag("stable").Build(),
+		testkit.NewRevision("ns1", "svc2", "svc2-00001").Tag("stable").Build(),
+	)
+
+	opts := NewCreateOptions(ui.NewNoopUI(), cluster.DepsFactory())
+	opts.RouteFlags = RouteFlags{cmdcore.NamespaceFlags{"ns1"}, ""}
+	opts.SelectorFlags = cmdflags.SelectorFlags{"team=payments"}
+	opts.TrafficFlags = TrafficFlags{RevisionPercentages: []string{"stable=100%"}}
+
+	// svc2 manages its route hence cannot be rolled out
+	err := opts.Run()
+	if err == nil || err.Error() != "Expected operation to succeed for all 2 resources, but it failed for 1" {
+		t.Fatalf("Expected batch error, but was: %v", err)
+	}
+
+	route, err := cluster.ServingClient().ServingV1alpha1().Routes("ns1").Get("svc1", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	DeepEqual(t, route.Spec.Traffic, []v1alpha1.TrafficTarget{
+		{RevisionName: "svc1-00001", Percent: 100},
+	})
+
+	opts.TrafficFlags = TrafficFlags{RevisionPercentages: []string{"svc1:stable=100%"}}
+
+	err = opts.Run()
+	if err == nil || !strings.Contains(err.Error(), "Expected percentage to reference revision tag") {
+		t.Fatalf("Expected tag reference error, but was: %v", err)
+	}
+}
+
+func TestCreateOptions_CreatesRouteWithTraffic(t *testing.T) {
+	cluster := testkit.NewCluster(t,
+		testkit.NewService("ns1", "svc1").Manual().Build(),
+		testkit.NewRevision("ns1", "svc1", "svc1-00001").Build(),
+		testkit.NewRevision("ns1", "svc1", "svc1-00002").Build(),
+	)
+
+	opts := NewCreateOptions(nil, cluster.DepsFactory())
+	opts.RouteFlags = RouteFlags{cmdcore.NamespaceFlags{"ns1"}, "svc1"}
+	opts.TrafficFlags = TrafficFlags{
+		RevisionPercentages: []string{"svc1-00001=80%", "svc1-00002=10%"},
+		ServicePercentages:  []string{"svc2=10%"},
+	}
+
+	err := opts.Run()
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	route, err := cluster.ServingClient().ServingV1alpha1().Routes("ns1").Get("svc1", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	DeepEqual(t, route.Spec.Traffic, []v1alpha1.TrafficTarget{
+		{RevisionName: "svc1-00001", Percent: 80},
+		{RevisionName: "svc1-00002", Percent: 10},
+		{ConfigurationName: "svc2", Percent: 10},
+	})
+}
+
+func TestCreateOptions_UpdatesExistingRoute(t *testing.T) {
+	cluster := testkit.NewCluster(t,
+		testkit.NewRevision("ns1", "svc1", "svc1-00001").Build(),
+		testkit.NewRevision("ns1", "svc1", "svc1-00002").Build(),
+		testkit.NewRoute("ns1", "rt1").Traffic("svc1-00001", 100).Build(),
+	)
+
+	opts := NewCreateOptions(nil, cluster.DepsFactory())
+	opts.RouteFlags = RouteFlags{cmdcore.NamespaceFlags{"ns1"}, "rt1"}
+	opts.TrafficFlags = TrafficFlags{RevisionPercentages: []string{"svc1-00002=100%"}}
+
+	err := opts.Run()
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	route, err := cluster.ServingClient().ServingV1alpha1().Routes("ns1").Get("rt1", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	DeepEqual(t, route.Spec.Traffic, []v1alpha1.TrafficTarget{
+		{RevisionName: "svc1-00002", Percent: 100},
+	})
+
+	entry, found, err := ctlhistory.NewHistory(cluster.CoreClient()).Last("ns1", "rt1")
+	if err != nil || !found {
+		t.Fatalf("Expected rollout to be recorded: %t %v", found, err)
+	}
+
+	DeepEqual(t, entry.Operation, ctlhistory.OperationRollout)
+	DeepEqual(t, entry.Route, "rt1")
+	DeepEqual(t, entry.Traffic, []v1alpha1.TrafficTarget{{RevisionName: "svc1-00002", Percent: 100}})
+	DeepEqual(t, entry.PreviousTraffic, []v1alpha1.TrafficTarget{{RevisionName: "svc1-00001", Percent: 100}})
+}
+
+func TestCreateOptions_ErrsForServiceWithManagedRoute(t *testing.T) {
+	cluster := testkit.NewCluster(t,
+		testkit.NewService("ns1", "svc1").Build(),
+		testkit.NewRevision("ns1", "svc1", "svc1-00001").Build(),
+	)
+
+	opts := NewCreateOptions(nil, cluster.DepsFactory())
+	opts.RouteFlags = RouteFlags{cmdcore.NamespaceFlags{"ns1"}, "svc1"}
+	opts.TrafficFlags = TrafficFlags{RevisionPercentages: []string{"svc1-00001=100%"}}
+
+	err := opts.Run()
+	if err == nil || !strings.Contains(err.Error(), "Expected associated service 'svc1' to not manage route") {
+		t.Fatalf("Expected error about managed route but was: %v", err)
+	}
+
+	_, err = cluster.ServingClient().ServingV1alpha1().Routes("ns1").Get("svc1", metav1.GetOptions{})
+	if err == nil {
+		t.Fatalf("Expected route to not be created")
+	}
 }
@@ -64,6 +64,11 @@ func (o *CurlOptions) Run() error {
 		return err
 	}
 
+	transportConfig, err := o.depsFactory.TransportConfig()
+	if err != nil {
+		return err
+	}
+
 	cmdName := "curl"
 	cmdArgs := []string{}
 
@@ -71,6 +76,7 @@ func (o *CurlOptions) Run() error {
 		cmdArgs = append(cmdArgs, "-vvv")
 	}
 
+	cmdArgs = append(cmdArgs, transportConfig.CurlArgs()...)
 	cmdArgs = append(cmdArgs, []string{"-sS", "-H", "Host: " + domain, url}...)
 
 	o.ui.PrintLinef("Running: %s '%s'", cmdName, strings.Join(cmdArgs, "' '"))
@@ -101,7 +107,22 @@ func (o *CurlOptions) addr() (string, string, error) {
 		return "", "", err
 	}
 
-	routeAddr := RouteAddress{route, coreClient}
+	cache, err := o.depsFactory.Cache()
+	if err != nil {
+		return "", "", err
+	}
+
+	logger, err := o.depsFactory.Logger()
+	if err != nil {
+		return "", "", err
+	}
+
+	inCluster, err := o.depsFactory.InCluster()
+	if err != nil {
+		return "", "", err
+	}
+
+	routeAddr := RouteAddress{route, coreClient, cache, logger, inCluster}
 
 	domain, err := routeAddr.Domain()
 	if err != nil {
@@ -0,0 +1,79 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package route
+
+import (
+	"fmt"
+
+	"github.com/cppforlife/go-cli-ui/ui"
+	cmdcore "github.com/cppforlife/knctl/pkg/knctl/cmd/core"
+	cmdflags "github.com/cppforlife/knctl/pkg/knctl/cmd/flags"
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type FaultClearOptions struct {
+	ui          ui.UI
+	depsFactory cmdcore.DepsFactory
+
+	ServiceFlags cmdflags.ServiceFlags
+}
+
+func NewFaultClearOptions(ui ui.UI, depsFactory cmdcore.DepsFactory) *FaultClearOptions {
+	return &FaultClearOptions{ui: ui, depsFactory: depsFactory}
+}
+
+func NewFaultClearCmd(o *FaultClearOptions, flagsFactory cmdcore.FlagsFactory) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "clear",
+		Short: "Clear injected faults",
+		Example: `
+  # Stop injecting faults into traffic for service 'svc1' in namespace 'ns1'
+  knctl fault clear -s svc1 -n ns1`,
+		RunE: func(_ *cobra.Command, _ []string) error { return o.Run() },
+	}
+	o.ServiceFlags.Set(cmd, flagsFactory)
+	return cmd
+}
+
+func (o *FaultClearOptions) Run() error {
+	servingClient, err := o.depsFactory.ServingClient()
+	if err != nil {
+		return err
+	}
+
+	dynamicClient, err := o.depsFactory.DynamicClient()
+	if err != nil {
+		return err
+	}
+
+	// Assumes that route has the same name as the service
+	route, err := servingClient.ServingV1alpha1().Routes(o.ServiceFlags.NamespaceFlags.Name).Get(o.ServiceFlags.Name, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("Getting route: %s", err)
+	}
+
+	// Reverting cleared faults results in the same outcome, hence no revert check
+	err = NewRouteFault(dynamicClient, 0).Clear(route)
+	if err != nil {
+		return err
+	}
+
+	o.ui.PrintLinef("Cleared faults for service '%s'", route.Name)
+
+	return nil
+}
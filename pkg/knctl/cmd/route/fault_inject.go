@@ -0,0 +1,129 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package route
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/cppforlife/go-cli-ui/ui"
+	cmdcore "github.com/cppforlife/knctl/pkg/knctl/cmd/core"
+	cmdflags "github.com/cppforlife/knctl/pkg/knctl/cmd/flags"
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type FaultInjectOptions struct {
+	ui          ui.UI
+	depsFactory cmdcore.DepsFactory
+
+	ServiceFlags     cmdflags.ServiceFlags
+	RevertCheckFlags RevertCheckFlags
+	Delay            time.Duration
+	DelayPercent     int
+	Abort            int
+	AbortPercent     int
+}
+
+func NewFaultInjectOptions(ui ui.UI, depsFactory cmdcore.DepsFactory) *FaultInjectOptions {
+	return &FaultInjectOptions{ui: ui, depsFactory: depsFactory}
+}
+
+func NewFaultInjectCmd(o *FaultInjectOptions, flagsFactory cmdcore.FlagsFactory) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "inject",
+		Short: "Inject faults into service's traffic",
+		Long: `Inject delays and/or aborts into service's traffic via Istio virtual service.
+
+Previously injected faults are replaced. Virtual service is owned by Knative which
+resets its configuration when it reconciles service's route, hence command fails
+if fault configuration does not survive reconciliation within --revert-check-duration.`,
+		Example: `
+  # Delay 20% of requests for service 'svc1' by 2s in namespace 'ns1'
+  knctl fault inject -s svc1 --delay 2s --delay-percent 20 -n ns1
+
+  # Additionally abort 5% of requests with 503
+  knctl fault inject -s svc1 --delay 2s --delay-percent 20 --abort 503 --abort-percent 5 -n ns1`,
+		RunE: func(_ *cobra.Command, _ []string) error { return o.Run() },
+	}
+	o.ServiceFlags.Set(cmd, flagsFactory)
+	o.RevertCheckFlags.Set(cmd, flagsFactory)
+	cmd.Flags().DurationVar(&o.Delay, "delay", 0, "Set fixed delay added to requests (e.g. 2s)")
+	cmd.Flags().IntVar(&o.DelayPercent, "delay-percent", 100, "Set percentage of requests to delay")
+	cmd.Flags().IntVar(&o.Abort, "abort", 0, "Set HTTP status returned for aborted requests (e.g. 503)")
+	cmd.Flags().IntVar(&o.AbortPercent, "abort-percent", 100, "Set percentage of requests to abort")
+	return cmd
+}
+
+func (o *FaultInjectOptions) Run() error {
+	var delay *RouteFaultDelay
+	var abort *RouteFaultAbort
+
+	if o.Delay != 0 {
+		if o.Delay < 0 {
+			return fmt.Errorf("Expected delay to be positive")
+		}
+		if o.DelayPercent < 1 || o.DelayPercent > 100 {
+			return fmt.Errorf("Expected delay percentage value to be between 1%% and 100%%")
+		}
+		delay = &RouteFaultDelay{Duration: o.Delay, Percent: o.DelayPercent}
+	}
+
+	if o.Abort != 0 {
+		if o.Abort < 100 || o.Abort > 599 {
+			return fmt.Errorf("Expected abort value to be a valid HTTP status")
+		}
+		if o.AbortPercent < 1 || o.AbortPercent > 100 {
+			return fmt.Errorf("Expected abort percentage value to be between 1%% and 100%%")
+		}
+		abort = &RouteFaultAbort{HTTPStatus: o.Abort, Percent: o.AbortPercent}
+	}
+
+	if delay == nil && abort == nil {
+		return fmt.Errorf("Expected fault to be specified via --delay and/or --abort")
+	}
+
+	servingClient, err := o.depsFactory.ServingClient()
+	if err != nil {
+		return err
+	}
+
+	dynamicClient, err := o.depsFactory.DynamicClient()
+	if err != nil {
+		return err
+	}
+
+	// Assumes that route has the same name as the service
+	route, err := servingClient.ServingV1alpha1().Routes(o.ServiceFlags.NamespaceFlags.Name).Get(o.ServiceFlags.Name, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("Getting route: %s", err)
+	}
+
+	err = NewRouteFault(dynamicClient, o.RevertCheckFlags.Duration).Inject(route, delay, abort)
+	if err != nil {
+		return err
+	}
+
+	if delay != nil {
+		o.ui.PrintLinef("Delaying %d%% of requests for service '%s' by %s", delay.Percent, route.Name, delay.Duration)
+	}
+	if abort != nil {
+		o.ui.PrintLinef("Aborting %d%% of requests for service '%s' with %d", abort.Percent, route.Name, abort.HTTPStatus)
+	}
+
+	return nil
+}
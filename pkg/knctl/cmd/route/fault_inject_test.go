@@ -0,0 +1,108 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package route_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cppforlife/go-cli-ui/ui"
+	. "github.com/cppforlife/knctl/pkg/knctl/cmd"
+	cmdcore "github.com/cppforlife/knctl/pkg/knctl/cmd/core"
+	cmdflags "github.com/cppforlife/knctl/pkg/knctl/cmd/flags"
+	. "github.com/cppforlife/knctl/pkg/knctl/cmd/route"
+	"github.com/cppforlife/knctl/pkg/knctl/testkit"
+)
+
+func TestNewFaultInjectCmd_Ok(t *testing.T) {
+	realCmd := NewFaultInjectOptions(nil, cmdcore.NewDepsFactory())
+	cmd := NewTestCmd(t, NewFaultInjectCmd(realCmd, cmdcore.FlagsFactory{}))
+	cmd.ExpectBasicConfig()
+	cmd.Execute([]string{
+		"-n", "test-namespace",
+		"-s", "test-service",
+		"--delay", "2s",
+		"--delay-percent", "20",
+		"--abort", "503",
+		"--abort-percent", "5",
+	})
+	cmd.ExpectReachesExecution()
+
+	DeepEqual(t, realCmd.ServiceFlags,
+		cmdflags.ServiceFlags{cmdcore.NamespaceFlags{"test-namespace"}, "test-service"})
+	DeepEqual(t, realCmd.Delay, 2*time.Second)
+	DeepEqual(t, realCmd.DelayPercent, 20)
+	DeepEqual(t, realCmd.Abort, 503)
+	DeepEqual(t, realCmd.AbortPercent, 5)
+	DeepEqual(t, realCmd.RevertCheckFlags, RevertCheckFlags{15 * time.Second})
+}
+
+func TestNewFaultInjectCmd_RequiredFlags(t *testing.T) {
+	realCmd := NewFaultInjectOptions(nil, cmdcore.NewDepsFactory())
+	cmd := NewTestCmd(t, NewFaultInjectCmd(realCmd, cmdcore.FlagsFactory{}))
+	cmd.Execute([]string{})
+	cmd.ExpectRequiredFlags([]string{"service"})
+}
+
+func TestFaultInjectOptions_RequiresFault(t *testing.T) {
+	opts := NewFaultInjectOptions(ui.NewNoopUI(), cmdcore.NewDepsFactory())
+
+	err := opts.Run()
+	if err == nil || err.Error() != "Expected fault to be specified via --delay and/or --abort" {
+		t.Fatalf("Expected error, but was: %v", err)
+	}
+}
+
+func TestFaultInjectOptions_InjectsAndClears(t *testing.T) {
+	cluster := testkit.NewCluster(t,
+		newTestVirtualService("svc1", "ns1"),
+		testkit.NewRoute("ns1", "svc1").Traffic("svc1-00001", 100).Build(),
+	)
+
+	opts := NewFaultInjectOptions(ui.NewNoopUI(), cluster.DepsFactory())
+	opts.ServiceFlags = cmdflags.ServiceFlags{cmdcore.NamespaceFlags{"ns1"}, "svc1"}
+	opts.Delay = 90 * time.Second
+	opts.DelayPercent = 20
+	opts.Abort = 503
+	opts.AbortPercent = 5
+
+	err := opts.Run()
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	httpRoute := testVirtualServiceHTTPRoutes(t, cluster, "svc1")[0]
+
+	DeepEqual(t, httpRoute["fault"], map[string]interface{}{
+		"delay": map[string]interface{}{"fixedDelay": "90s", "percent": int64(20)},
+		"abort": map[string]interface{}{"httpStatus": int64(503), "percent": int64(5)},
+	})
+
+	clearOpts := NewFaultClearOptions(ui.NewNoopUI(), cluster.DepsFactory())
+	clearOpts.ServiceFlags = opts.ServiceFlags
+
+	err = clearOpts.Run()
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	httpRoute = testVirtualServiceHTTPRoutes(t, cluster, "svc1")[0]
+
+	if _, found := httpRoute["fault"]; found {
+		t.Fatalf("Expected fault to be removed: %#v", httpRoute)
+	}
+}
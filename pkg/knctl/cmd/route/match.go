@@ -0,0 +1,149 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package route
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cppforlife/go-cli-ui/ui"
+	cmdcore "github.com/cppforlife/knctl/pkg/knctl/cmd/core"
+	cmdflags "github.com/cppforlife/knctl/pkg/knctl/cmd/flags"
+	ctlservice "github.com/cppforlife/knctl/pkg/knctl/service"
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type MatchOptions struct {
+	ui          ui.UI
+	depsFactory cmdcore.DepsFactory
+
+	ServiceFlags     cmdflags.ServiceFlags
+	RevertCheckFlags RevertCheckFlags
+
+	Headers []string
+	Cookie  string
+	ToTag   string
+	Clear   bool
+}
+
+func NewMatchOptions(ui ui.UI, depsFactory cmdcore.DepsFactory) *MatchOptions {
+	return &MatchOptions{ui: ui, depsFactory: depsFactory}
+}
+
+func NewMatchCmd(o *MatchOptions, flagsFactory cmdcore.FlagsFactory) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "match",
+		Short: "Route matching requests to tagged revision",
+		Long: `Route requests that match headers or a cookie to a tagged revision via Istio virtual service.
+
+Rules are added in front of routing configured by Knative. Virtual service is owned
+by Knative which resets its configuration when it reconciles service's route, hence
+command fails if rules do not survive reconciliation within --revert-check-duration.`,
+		Example: `
+  # Route requests with header 'x-beta: true' for service 'svc1' to revision tagged 'beta' in namespace 'ns1'
+  knctl route match -s svc1 --header x-beta=true --to-tag beta -n ns1
+
+  # Route requests with cookie 'user=beta' for service 'svc1' to revision tagged 'beta' in namespace 'ns1'
+  knctl route match -s svc1 --cookie user=beta --to-tag beta -n ns1
+
+  # Remove rules for tag 'beta' for service 'svc1' in namespace 'ns1'
+  knctl route match -s svc1 --to-tag beta --clear -n ns1`,
+		RunE: func(_ *cobra.Command, _ []string) error { return o.Run() },
+	}
+	o.ServiceFlags.Set(cmd, flagsFactory)
+	o.RevertCheckFlags.Set(cmd, flagsFactory)
+	cmd.Flags().StringSliceVar(&o.Headers, "header", nil, "Set header to match (format: name=value) (can be specified multiple times)")
+	cmd.Flags().StringVar(&o.Cookie, "cookie", "", "Set cookie to match (format: name=value)")
+	cmd.Flags().StringVar(&o.ToTag, "to-tag", "", "Set tag of revision that receives matching requests")
+	cmd.Flags().BoolVar(&o.Clear, "clear", false, "Remove rules for tag (or all rules if tag is not specified)")
+	return cmd
+}
+
+func (o *MatchOptions) Run() error {
+	servingClient, err := o.depsFactory.ServingClient()
+	if err != nil {
+		return err
+	}
+
+	dynamicClient, err := o.depsFactory.DynamicClient()
+	if err != nil {
+		return err
+	}
+
+	// Assumes that route has the same name as the service
+	route, err := servingClient.ServingV1alpha1().Routes(o.ServiceFlags.NamespaceFlags.Name).Get(o.ServiceFlags.Name, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("Getting route: %s", err)
+	}
+
+	match := NewRouteMatch(dynamicClient, o.RevertCheckFlags.Duration)
+
+	if o.Clear {
+		return match.Clear(route, o.ToTag)
+	}
+
+	rule, err := o.rule()
+	if err != nil {
+		return err
+	}
+
+	if len(o.ToTag) == 0 {
+		return fmt.Errorf("Expected tag to be specified via --to-tag")
+	}
+
+	context := ctlservice.TagsFindContext{Namespace: o.ServiceFlags.NamespaceFlags.Name, Service: o.ServiceFlags.Name}
+
+	revision, err := ctlservice.NewTags(servingClient).Find(context, o.ToTag)
+	if err != nil {
+		return err
+	}
+
+	err = match.Add(route, rule, o.ToTag, revision)
+	if err != nil {
+		return err
+	}
+
+	o.ui.PrintLinef("Routing matching requests for service '%s' to revision '%s'", route.Name, revision.Name)
+
+	return nil
+}
+
+func (o *MatchOptions) rule() (RouteMatchRule, error) {
+	rule := RouteMatchRule{Headers: map[string]string{}}
+
+	for _, kv := range o.Headers {
+		pieces := strings.SplitN(kv, "=", 2)
+		if len(pieces) != 2 || len(pieces[0]) == 0 {
+			return rule, fmt.Errorf("Expected header to be in format 'name=value'")
+		}
+		rule.Headers[strings.ToLower(pieces[0])] = pieces[1]
+	}
+
+	if len(o.Cookie) > 0 {
+		if !strings.Contains(o.Cookie, "=") {
+			return rule, fmt.Errorf("Expected cookie to be in format 'name=value'")
+		}
+		rule.Cookie = o.Cookie
+	}
+
+	if len(rule.Headers) == 0 && len(rule.Cookie) == 0 {
+		return rule, fmt.Errorf("Expected at least one header or cookie to be specified")
+	}
+
+	return rule, nil
+}
@@ -0,0 +1,163 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package route_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cppforlife/go-cli-ui/ui"
+	. "github.com/cppforlife/knctl/pkg/knctl/cmd"
+	cmdcore "github.com/cppforlife/knctl/pkg/knctl/cmd/core"
+	cmdflags "github.com/cppforlife/knctl/pkg/knctl/cmd/flags"
+	. "github.com/cppforlife/knctl/pkg/knctl/cmd/route"
+	"github.com/cppforlife/knctl/pkg/knctl/testkit"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestNewMatchCmd_Ok(t *testing.T) {
+	realCmd := NewMatchOptions(nil, cmdcore.NewDepsFactory())
+	cmd := NewTestCmd(t, NewMatchCmd(realCmd, cmdcore.FlagsFactory{}))
+	cmd.ExpectBasicConfig()
+	cmd.Execute([]string{
+		"-n", "test-namespace",
+		"-s", "test-service",
+		"--header", "x-beta=true",
+		"--header", "x-user=1",
+		"--cookie", "user=beta",
+		"--to-tag", "beta",
+	})
+	cmd.ExpectReachesExecution()
+
+	DeepEqual(t, realCmd.ServiceFlags,
+		cmdflags.ServiceFlags{cmdcore.NamespaceFlags{"test-namespace"}, "test-service"})
+	DeepEqual(t, realCmd.Headers, []string{"x-beta=true", "x-user=1"})
+	DeepEqual(t, realCmd.Cookie, "user=beta")
+	DeepEqual(t, realCmd.ToTag, "beta")
+	DeepEqual(t, realCmd.RevertCheckFlags, RevertCheckFlags{15 * time.Second})
+}
+
+func TestNewMatchCmd_RequiredFlags(t *testing.T) {
+	realCmd := NewMatchOptions(nil, cmdcore.NewDepsFactory())
+	cmd := NewTestCmd(t, NewMatchCmd(realCmd, cmdcore.FlagsFactory{}))
+	cmd.Execute([]string{})
+	cmd.ExpectRequiredFlags([]string{"service"})
+}
+
+func TestMatchOptions_AddsAndClearsRules(t *testing.T) {
+	cluster := testkit.NewCluster(t,
+		newTestVirtualService("svc1", "ns1"),
+		testkit.NewRevision("ns1", "svc1", "svc1-00002").Tag("beta").ServiceName("svc1-00002-service").Build(),
+		testkit.NewRoute("ns1", "svc1").Traffic("svc1-00001", 100).Build(),
+	)
+
+	opts := NewMatchOptions(ui.NewNoopUI(), cluster.DepsFactory())
+	opts.ServiceFlags = cmdflags.ServiceFlags{cmdcore.NamespaceFlags{"ns1"}, "svc1"}
+	opts.Headers = []string{"X-Beta=true"}
+	opts.ToTag = "beta"
+
+	// Running twice replaces rule for the same tag
+	for i := 0; i < 2; i++ {
+		err := opts.Run()
+		if err != nil {
+			t.Fatalf("Expected no error: %s", err)
+		}
+	}
+
+	httpRoutes := testVirtualServiceHTTPRoutes(t, cluster, "svc1")
+	if len(httpRoutes) != 2 {
+		t.Fatalf("Expected match rule to be added before Knative rule: %#v", httpRoutes)
+	}
+
+	matchRoute := httpRoutes[0]
+
+	DeepEqual(t, matchRoute["route"], []interface{}{
+		map[string]interface{}{
+			"destination": map[string]interface{}{
+				"host": "svc1-00002-service.ns1.svc.cluster.local",
+				"port": map[string]interface{}{"number": int64(80)},
+			},
+			"weight": int64(100),
+		},
+	})
+
+	for _, match := range matchRoute["match"].([]interface{}) {
+		typedMatch := match.(map[string]interface{})
+		DeepEqual(t, typedMatch["headers"], map[string]interface{}{
+			"x-beta": map[string]interface{}{"exact": "true"},
+		})
+		if _, found := typedMatch["authority"]; !found {
+			t.Fatalf("Expected authority match to be kept: %#v", typedMatch)
+		}
+	}
+
+	DeepEqual(t, matchRoute["appendHeaders"], map[string]interface{}{
+		"knative-serving-namespace": "ns1",
+		"knative-serving-revision":  "svc1-00002",
+		"x-knctl-route-match":       "beta",
+	})
+
+	DeepEqual(t, httpRoutes[1], newTestVirtualService("svc1", "ns1").Object["spec"].(map[string]interface{})["http"].([]interface{})[0])
+
+	opts.Clear = true
+
+	err := opts.Run()
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	if len(testVirtualServiceHTTPRoutes(t, cluster, "svc1")) != 1 {
+		t.Fatalf("Expected match rule to be removed")
+	}
+}
+
+func TestMatchOptions_CookieRule(t *testing.T) {
+	rule := RouteMatchRule{Cookie: "user=beta"}
+
+	cluster := testkit.NewCluster(t,
+		newTestVirtualService("svc1", "ns1"),
+		testkit.NewRevision("ns1", "svc1", "svc1-00002").Tag("beta").ServiceName("svc1-00002-service").Build(),
+		testkit.NewRoute("ns1", "svc1").Build(),
+	)
+
+	route, err := cluster.ServingClient().ServingV1alpha1().Routes("ns1").Get("svc1", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	revision, err := cluster.ServingClient().ServingV1alpha1().Revisions("ns1").Get("svc1-00002", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	dynamicClient, err := cluster.DepsFactory().DynamicClient()
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	// Minimal duration checks that applied rules are kept without waiting
+	err = NewRouteMatch(dynamicClient, time.Nanosecond).Add(route, rule, "beta", revision)
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	match := testVirtualServiceHTTPRoutes(t, cluster, "svc1")[0]["match"].([]interface{})[0].(map[string]interface{})
+
+	DeepEqual(t, match["headers"], map[string]interface{}{
+		"cookie": map[string]interface{}{"regex": `^(.*?;\s*)?(user=beta)(;.*)?$`},
+	})
+}
@@ -0,0 +1,119 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package route
+
+import (
+	"fmt"
+
+	"github.com/cppforlife/go-cli-ui/ui"
+	cmdcore "github.com/cppforlife/knctl/pkg/knctl/cmd/core"
+	cmdflags "github.com/cppforlife/knctl/pkg/knctl/cmd/flags"
+	cmdrev "github.com/cppforlife/knctl/pkg/knctl/cmd/revision"
+	ctlservice "github.com/cppforlife/knctl/pkg/knctl/service"
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type MirrorOptions struct {
+	ui          ui.UI
+	depsFactory cmdcore.DepsFactory
+
+	ServiceFlags     cmdflags.ServiceFlags
+	RevertCheckFlags RevertCheckFlags
+	ToRevision       string
+	Percent          int
+	Disable          bool
+}
+
+func NewMirrorOptions(ui ui.UI, depsFactory cmdcore.DepsFactory) *MirrorOptions {
+	return &MirrorOptions{ui: ui, depsFactory: depsFactory}
+}
+
+func NewMirrorCmd(o *MirrorOptions, flagsFactory cmdcore.FlagsFactory) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "mirror",
+		Short: "Mirror traffic to revision",
+		Long: `Mirror (shadow) percentage of service's traffic to a revision via Istio virtual service.
+
+Mirrored requests are sent in addition to regular requests and their responses are discarded.
+Virtual service is owned by Knative which resets its configuration when it reconciles
+service's route, hence command fails if mirroring configuration does not survive
+reconciliation within --revert-check-duration.`,
+		Example: `
+  # Mirror 10% of traffic for service 'svc1' to its latest revision in namespace 'ns1'
+  knctl rollout mirror -s svc1 --to-revision svc1:latest --percent 10 -n ns1
+
+  # Stop mirroring traffic for service 'svc1' in namespace 'ns1'
+  knctl rollout mirror -s svc1 --disable -n ns1`,
+		RunE: func(_ *cobra.Command, _ []string) error { return o.Run() },
+	}
+	o.ServiceFlags.Set(cmd, flagsFactory)
+	o.RevertCheckFlags.Set(cmd, flagsFactory)
+	cmd.Flags().StringVar(&o.ToRevision, "to-revision", "", "Set revision that receives mirrored traffic (format: revision, service:revision or service:tag)")
+	cmd.Flags().IntVar(&o.Percent, "percent", 100, "Set percentage of traffic to mirror")
+	cmd.Flags().BoolVar(&o.Disable, "disable", false, "Stop mirroring traffic")
+	return cmd
+}
+
+func (o *MirrorOptions) Run() error {
+	if !o.Disable {
+		if len(o.ToRevision) == 0 {
+			return fmt.Errorf("Expected revision to be specified via --to-revision")
+		}
+		if o.Percent < 1 || o.Percent > 100 {
+			return fmt.Errorf("Expected percentage value to be between 1%% and 100%%")
+		}
+	}
+
+	servingClient, err := o.depsFactory.ServingClient()
+	if err != nil {
+		return err
+	}
+
+	dynamicClient, err := o.depsFactory.DynamicClient()
+	if err != nil {
+		return err
+	}
+
+	// Assumes that route has the same name as the service
+	route, err := servingClient.ServingV1alpha1().Routes(o.ServiceFlags.NamespaceFlags.Name).Get(o.ServiceFlags.Name, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("Getting route: %s", err)
+	}
+
+	mirror := NewRouteMirror(dynamicClient, o.RevertCheckFlags.Duration)
+
+	if o.Disable {
+		return mirror.Unmirror(route)
+	}
+
+	revFlags := cmdflags.RevisionFlags{Name: o.ToRevision, NamespaceFlags: o.ServiceFlags.NamespaceFlags}
+
+	revision, err := cmdrev.NewReference(revFlags, ctlservice.NewTags(servingClient), servingClient).Revision()
+	if err != nil {
+		return err
+	}
+
+	err = mirror.Mirror(route, revision, o.Percent)
+	if err != nil {
+		return err
+	}
+
+	o.ui.PrintLinef("Mirroring %d%% of traffic for service '%s' to revision '%s'", o.Percent, route.Name, revision.Name)
+
+	return nil
+}
@@ -0,0 +1,95 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package route_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cppforlife/go-cli-ui/ui"
+	. "github.com/cppforlife/knctl/pkg/knctl/cmd"
+	cmdcore "github.com/cppforlife/knctl/pkg/knctl/cmd/core"
+	cmdflags "github.com/cppforlife/knctl/pkg/knctl/cmd/flags"
+	. "github.com/cppforlife/knctl/pkg/knctl/cmd/route"
+	"github.com/cppforlife/knctl/pkg/knctl/testkit"
+)
+
+func TestNewMirrorCmd_Ok(t *testing.T) {
+	realCmd := NewMirrorOptions(nil, cmdcore.NewDepsFactory())
+	cmd := NewTestCmd(t, NewMirrorCmd(realCmd, cmdcore.FlagsFactory{}))
+	cmd.ExpectBasicConfig()
+	cmd.Execute([]string{
+		"-n", "test-namespace",
+		"-s", "test-service",
+		"--to-revision", "test-service:latest",
+		"--percent", "10",
+	})
+	cmd.ExpectReachesExecution()
+
+	DeepEqual(t, realCmd.ServiceFlags,
+		cmdflags.ServiceFlags{cmdcore.NamespaceFlags{"test-namespace"}, "test-service"})
+	DeepEqual(t, realCmd.ToRevision, "test-service:latest")
+	DeepEqual(t, realCmd.Percent, 10)
+	DeepEqual(t, realCmd.Disable, false)
+	DeepEqual(t, realCmd.RevertCheckFlags, RevertCheckFlags{15 * time.Second})
+}
+
+func TestNewMirrorCmd_RequiredFlags(t *testing.T) {
+	realCmd := NewMirrorOptions(nil, cmdcore.NewDepsFactory())
+	cmd := NewTestCmd(t, NewMirrorCmd(realCmd, cmdcore.FlagsFactory{}))
+	cmd.Execute([]string{})
+	cmd.ExpectRequiredFlags([]string{"service"})
+}
+
+func TestMirrorOptions_MirrorsAndUnmirrors(t *testing.T) {
+	cluster := testkit.NewCluster(t,
+		newTestVirtualService("svc1", "ns1"),
+		testkit.NewRevision("ns1", "svc1", "svc1-00002").ServiceName("svc1-00002-service").Build(),
+		testkit.NewRoute("ns1", "svc1").Traffic("svc1-00001", 100).Build(),
+	)
+
+	opts := NewMirrorOptions(ui.NewNoopUI(), cluster.DepsFactory())
+	opts.ServiceFlags = cmdflags.ServiceFlags{cmdcore.NamespaceFlags{"ns1"}, "svc1"}
+	opts.ToRevision = "svc1-00002"
+	opts.Percent = 10
+
+	err := opts.Run()
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	httpRoute := testVirtualServiceHTTPRoutes(t, cluster, "svc1")[0]
+
+	DeepEqual(t, httpRoute["mirror"], map[string]interface{}{
+		"host": "svc1-00002-service.ns1.svc.cluster.local",
+		"port": map[string]interface{}{"number": int64(80)},
+	})
+	DeepEqual(t, httpRoute["mirrorPercent"], int64(10))
+
+	opts.Disable = true
+
+	err = opts.Run()
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	httpRoute = testVirtualServiceHTTPRoutes(t, cluster, "svc1")[0]
+
+	if _, found := httpRoute["mirror"]; found {
+		t.Fatalf("Expected mirror to be removed: %#v", httpRoute)
+	}
+}
@@ -19,7 +19,9 @@ package route
 import (
 	"fmt"
 
+	"github.com/cppforlife/knctl/pkg/knctl/cache"
 	ctling "github.com/cppforlife/knctl/pkg/knctl/ingress"
+	"github.com/cppforlife/knctl/pkg/knctl/logger"
 	"github.com/knative/serving/pkg/apis/serving/v1alpha1"
 	"k8s.io/client-go/kubernetes"
 )
@@ -28,6 +30,9 @@ import (
 type RouteAddress struct {
 	route      *v1alpha1.Route
 	coreClient kubernetes.Interface
+	cache      cache.Cache
+	logger     logger.Logger
+	inCluster  bool
 }
 
 func (o RouteAddress) Domain() (string, error) {
@@ -44,7 +49,7 @@ func (o RouteAddress) URL(port int32, useDomain bool) (string, error) {
 		return "", err
 	}
 
-	ingressAddress, ingressPort, err := ctling.NewIngressServices(o.coreClient).PreferredAddress(port)
+	ingressAddress, ingressPort, err := ctling.NewIngressServices(o.coreClient).WithCache(o.cache).WithLogger(o.logger).WithInCluster(o.inCluster).PreferredAddress(port)
 	if err != nil {
 		return "", err
 	}
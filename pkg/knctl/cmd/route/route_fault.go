@@ -0,0 +1,86 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package route
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/knative/serving/pkg/apis/serving/v1alpha1"
+	"k8s.io/client-go/dynamic"
+)
+
+type RouteFaultDelay struct {
+	Duration time.Duration
+	Percent  int
+}
+
+type RouteFaultAbort struct {
+	HTTPStatus int
+	Percent    int
+}
+
+// RouteFault configures Istio fault injection for a route
+type RouteFault struct {
+	virtualServices RouteVirtualServices
+}
+
+func NewRouteFault(dynamicClient dynamic.Interface, revertCheckDuration time.Duration) RouteFault {
+	return RouteFault{NewRouteVirtualServices(dynamicClient).WithRevertCheck(revertCheckDuration, time.Second)}
+}
+
+// Inject replaces route's fault configuration; nil delay or abort is not included
+func (f RouteFault) Inject(route *v1alpha1.Route, delay *RouteFaultDelay, abort *RouteFaultAbort) error {
+	fault := map[string]interface{}{}
+
+	if delay != nil {
+		fault["delay"] = map[string]interface{}{
+			"fixedDelay": f.protobufDuration(delay.Duration),
+			"percent":    int64(delay.Percent),
+		}
+	}
+
+	if abort != nil {
+		fault["abort"] = map[string]interface{}{
+			"httpStatus": int64(abort.HTTPStatus),
+			"percent":    int64(abort.Percent),
+		}
+	}
+
+	return f.virtualServices.UpdateHTTPRoutes(route, func(httpRoutes []map[string]interface{}) ([]map[string]interface{}, error) {
+		for _, httpRoute := range httpRoutes {
+			httpRoute["fault"] = fault
+		}
+		return httpRoutes, nil
+	})
+}
+
+// Clear removes fault configuration
+func (f RouteFault) Clear(route *v1alpha1.Route) error {
+	return f.virtualServices.UpdateHTTPRoutes(route, func(httpRoutes []map[string]interface{}) ([]map[string]interface{}, error) {
+		for _, httpRoute := range httpRoutes {
+			delete(httpRoute, "fault")
+		}
+		return httpRoutes, nil
+	})
+}
+
+// protobufDuration formats duration as expected by protobuf JSON mapping
+// (e.g. '60s' instead of '1m0s')
+func (RouteFault) protobufDuration(d time.Duration) string {
+	return strconv.FormatFloat(d.Seconds(), 'f', -1, 64) + "s"
+}
@@ -27,8 +27,12 @@ type RouteFlags struct {
 }
 
 func (s *RouteFlags) Set(cmd *cobra.Command, flagsFactory cmdcore.FlagsFactory) {
+	s.SetOptional(cmd, flagsFactory)
+	cmd.MarkFlagRequired("route")
+}
+
+func (s *RouteFlags) SetOptional(cmd *cobra.Command, flagsFactory cmdcore.FlagsFactory) {
 	s.NamespaceFlags.Set(cmd, flagsFactory)
 
 	cmd.Flags().StringVar(&s.Name, "route", "", "Specified route")
-	cmd.MarkFlagRequired("route")
 }
@@ -0,0 +1,164 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package route
+
+import (
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/knative/serving/pkg/apis/serving/v1alpha1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/dynamic"
+)
+
+const (
+	// Marks HTTP routes generated by knctl (value is a tag)
+	routeMatchMarkerHeader = "x-knctl-route-match"
+	revisionHeader         = "knative-serving-revision"
+)
+
+type RouteMatchRule struct {
+	Headers map[string]string // exact header values
+	Cookie  string            // cookie in format name=value
+}
+
+func (r RouteMatchRule) istioHeaders() map[string]interface{} {
+	result := map[string]interface{}{}
+
+	for k, v := range r.Headers {
+		result[k] = map[string]interface{}{"exact": v}
+	}
+
+	if len(r.Cookie) > 0 {
+		result["cookie"] = map[string]interface{}{
+			"regex": "^(.*?;\\s*)?(" + regexp.QuoteMeta(r.Cookie) + ")(;.*)?$",
+		}
+	}
+
+	return result
+}
+
+// RouteMatch routes requests that match headers or a cookie to a particular
+// revision by adding HTTP routes before ones generated by Knative
+type RouteMatch struct {
+	virtualServices RouteVirtualServices
+}
+
+func NewRouteMatch(dynamicClient dynamic.Interface, revertCheckDuration time.Duration) RouteMatch {
+	return RouteMatch{NewRouteVirtualServices(dynamicClient).WithRevertCheck(revertCheckDuration, time.Second)}
+}
+
+// Add replaces previously added rule for the same tag
+func (m RouteMatch) Add(route *v1alpha1.Route, rule RouteMatchRule, tag string, revision *v1alpha1.Revision) error {
+	dest, err := revisionDestination(revision)
+	if err != nil {
+		return err
+	}
+
+	return m.virtualServices.UpdateHTTPRoutes(route, func(httpRoutes []map[string]interface{}) ([]map[string]interface{}, error) {
+		var matchRoutes, otherRoutes []map[string]interface{}
+
+		for _, httpRoute := range m.withoutTag(httpRoutes, tag) {
+			if len(m.marker(httpRoute)) > 0 {
+				otherRoutes = append(otherRoutes, httpRoute)
+				continue
+			}
+
+			matchRoute := runtime.DeepCopyJSONValue(httpRoute).(map[string]interface{})
+			matchRoute["route"] = []interface{}{
+				map[string]interface{}{"destination": dest, "weight": int64(100)},
+			}
+			matchRoute["match"] = m.addHeaders(httpRoute["match"], rule.istioHeaders())
+
+			appendHeaders, _ := matchRoute["appendHeaders"].(map[string]interface{})
+			if appendHeaders == nil {
+				appendHeaders = map[string]interface{}{}
+			}
+			if _, found := appendHeaders[revisionHeader]; found {
+				appendHeaders[revisionHeader] = revision.Name
+			}
+			appendHeaders[routeMatchMarkerHeader] = tag
+			matchRoute["appendHeaders"] = appendHeaders
+
+			matchRoutes = append(matchRoutes, matchRoute)
+			otherRoutes = append(otherRoutes, httpRoute)
+		}
+
+		if len(matchRoutes) == 0 {
+			return nil, fmt.Errorf("Expected to find at least one HTTP route generated by Knative")
+		}
+
+		return append(matchRoutes, otherRoutes...), nil
+	})
+}
+
+// Clear removes rules for a tag (or all rules if tag is empty)
+func (m RouteMatch) Clear(route *v1alpha1.Route, tag string) error {
+	return m.virtualServices.UpdateHTTPRoutes(route, func(httpRoutes []map[string]interface{}) ([]map[string]interface{}, error) {
+		return m.withoutTag(httpRoutes, tag), nil
+	})
+}
+
+func (m RouteMatch) withoutTag(httpRoutes []map[string]interface{}, tag string) []map[string]interface{} {
+	var result []map[string]interface{}
+
+	for _, httpRoute := range httpRoutes {
+		marker := m.marker(httpRoute)
+		if len(marker) > 0 && (len(tag) == 0 || marker == tag) {
+			continue
+		}
+		result = append(result, httpRoute)
+	}
+
+	return result
+}
+
+func (m RouteMatch) marker(httpRoute map[string]interface{}) string {
+	appendHeaders, _ := httpRoute["appendHeaders"].(map[string]interface{})
+	marker, _ := appendHeaders[routeMatchMarkerHeader].(string)
+	return marker
+}
+
+// addHeaders adds header conditions to each match condition
+// (conditions are ORed, while fields within a condition are ANDed)
+func (m RouteMatch) addHeaders(matches interface{}, headers map[string]interface{}) []interface{} {
+	typedMatches, _ := runtime.DeepCopyJSONValue(matches).([]interface{})
+	if len(typedMatches) == 0 {
+		typedMatches = []interface{}{map[string]interface{}{}}
+	}
+
+	for _, match := range typedMatches {
+		typedMatch, ok := match.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		matchHeaders, _ := typedMatch["headers"].(map[string]interface{})
+		if matchHeaders == nil {
+			matchHeaders = map[string]interface{}{}
+		}
+
+		for k, v := range headers {
+			matchHeaders[k] = runtime.DeepCopyJSONValue(v)
+		}
+
+		typedMatch["headers"] = matchHeaders
+	}
+
+	return typedMatches
+}
@@ -0,0 +1,60 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package route
+
+import (
+	"time"
+
+	"github.com/knative/serving/pkg/apis/serving/v1alpha1"
+	"k8s.io/client-go/dynamic"
+)
+
+// RouteMirror configures Istio traffic mirroring for a route
+type RouteMirror struct {
+	virtualServices RouteVirtualServices
+}
+
+func NewRouteMirror(dynamicClient dynamic.Interface, revertCheckDuration time.Duration) RouteMirror {
+	return RouteMirror{NewRouteVirtualServices(dynamicClient).WithRevertCheck(revertCheckDuration, time.Second)}
+}
+
+// Mirror copies given percentage of route's requests to a revision
+func (m RouteMirror) Mirror(route *v1alpha1.Route, revision *v1alpha1.Revision, percent int) error {
+	mirror, err := revisionDestination(revision)
+	if err != nil {
+		return err
+	}
+
+	return m.virtualServices.UpdateHTTPRoutes(route, func(httpRoutes []map[string]interface{}) ([]map[string]interface{}, error) {
+		for _, httpRoute := range httpRoutes {
+			httpRoute["mirror"] = mirror
+			httpRoute["mirrorPercent"] = int64(percent)
+		}
+		return httpRoutes, nil
+	})
+}
+
+// Unmirror removes mirroring configuration
+func (m RouteMirror) Unmirror(route *v1alpha1.Route) error {
+	return m.virtualServices.UpdateHTTPRoutes(route, func(httpRoutes []map[string]interface{}) ([]map[string]interface{}, error) {
+		for _, httpRoute := range httpRoutes {
+			delete(httpRoute, "mirror")
+			delete(httpRoute, "mirrorPercent")
+		}
+		return httpRoutes, nil
+	})
+}
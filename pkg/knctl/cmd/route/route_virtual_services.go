@@ -0,0 +1,200 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package route
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/knative/serving/pkg/apis/serving"
+	"github.com/knative/serving/pkg/apis/serving/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+)
+
+var (
+	virtualServiceGVR = schema.GroupVersionResource{
+		Group:    "networking.istio.io",
+		Version:  "v1alpha3",
+		Resource: "virtualservices",
+	}
+)
+
+// RouteVirtualServices modifies Istio virtual services that Knative
+// generated for a route. Knative owns these virtual services and resets
+// their spec when it reconciles route's ingress, hence modifications
+// are checked to survive reconciliation (see WithRevertCheck).
+type RouteVirtualServices struct {
+	dynamicClient dynamic.Interface
+
+	revertCheckDuration time.Duration
+	revertCheckInterval time.Duration
+}
+
+func NewRouteVirtualServices(dynamicClient dynamic.Interface) RouteVirtualServices {
+	return RouteVirtualServices{dynamicClient: dynamicClient, revertCheckInterval: time.Second}
+}
+
+// WithRevertCheck makes updates wait for given duration and fail
+// if modifications are no longer present (i.e. Knative reverted them)
+func (s RouteVirtualServices) WithRevertCheck(duration, interval time.Duration) RouteVirtualServices {
+	s.revertCheckDuration = duration
+	s.revertCheckInterval = interval
+	return s
+}
+
+type HTTPRoutesUpdateFunc func([]map[string]interface{}) ([]map[string]interface{}, error)
+
+func (s RouteVirtualServices) List(route *v1alpha1.Route) ([]unstructured.Unstructured, error) {
+	// Virtual services may live in a system namespace, hence cluster wide listing
+	listOpts := metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("%s=%s,%s=%s", serving.RouteLabelKey, route.Name,
+			serving.RouteNamespaceLabelKey, route.Namespace),
+	}
+
+	list, err := s.dynamicClient.Resource(virtualServiceGVR).List(listOpts)
+	if err != nil {
+		return nil, fmt.Errorf("Listing virtual services: %s", err)
+	}
+
+	if len(list.Items) == 0 {
+		return nil, fmt.Errorf("Expected to find virtual service for route '%s'", route.Name)
+	}
+
+	return list.Items, nil
+}
+
+func (s RouteVirtualServices) UpdateHTTPRoutes(route *v1alpha1.Route, updateFunc HTTPRoutesUpdateFunc) error {
+	virtualServices, err := s.List(route)
+	if err != nil {
+		return err
+	}
+
+	for _, vs := range virtualServices {
+		httpRoutes, err := s.httpRoutes(vs)
+		if err != nil {
+			return err
+		}
+
+		httpRoutes, err = updateFunc(httpRoutes)
+		if err != nil {
+			return err
+		}
+
+		var untypedHTTPRoutes []interface{}
+
+		for _, httpRoute := range httpRoutes {
+			untypedHTTPRoutes = append(untypedHTTPRoutes, httpRoute)
+		}
+
+		err = unstructured.SetNestedSlice(vs.Object, untypedHTTPRoutes, "spec", "http")
+		if err != nil {
+			return err
+		}
+
+		_, err = s.dynamicClient.Resource(virtualServiceGVR).Namespace(vs.GetNamespace()).Update(&vs)
+		if err != nil {
+			return fmt.Errorf("Updating virtual service '%s': %s", vs.GetName(), err)
+		}
+	}
+
+	return s.checkNotReverted(route, updateFunc)
+}
+
+// checkNotReverted periodically re-reads virtual services and expects that
+// applying update again does not change them (update funcs are idempotent)
+func (s RouteVirtualServices) checkNotReverted(route *v1alpha1.Route, updateFunc HTTPRoutesUpdateFunc) error {
+	if s.revertCheckDuration <= 0 {
+		return nil
+	}
+
+	deadline := time.Now().Add(s.revertCheckDuration)
+
+	for {
+		virtualServices, err := s.List(route)
+		if err != nil {
+			return err
+		}
+
+		for _, vs := range virtualServices {
+			httpRoutes, err := s.httpRoutes(vs)
+			if err != nil {
+				return err
+			}
+
+			currentBytes, err := json.Marshal(httpRoutes)
+			if err != nil {
+				return err
+			}
+
+			updatedHTTPRoutes, err := updateFunc(httpRoutes)
+			if err != nil {
+				return err
+			}
+
+			updatedBytes, err := json.Marshal(updatedHTTPRoutes)
+			if err != nil {
+				return err
+			}
+
+			if string(currentBytes) != string(updatedBytes) {
+				return fmt.Errorf("Expected virtual service '%s' to keep changes, but Knative reverted them "+
+					"while reconciling route '%s' (virtual service is owned by Knative)", vs.GetName(), route.Name)
+			}
+		}
+
+		if time.Now().After(deadline) {
+			return nil
+		}
+
+		time.Sleep(s.revertCheckInterval)
+	}
+}
+
+func (s RouteVirtualServices) httpRoutes(vs unstructured.Unstructured) ([]map[string]interface{}, error) {
+	untypedHTTPRoutes, found, err := unstructured.NestedSlice(vs.Object, "spec", "http")
+	if err != nil || !found {
+		return nil, fmt.Errorf("Expected virtual service '%s' to have HTTP routes", vs.GetName())
+	}
+
+	var result []map[string]interface{}
+
+	for _, httpRoute := range untypedHTTPRoutes {
+		typedHTTPRoute, ok := httpRoute.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("Expected virtual service '%s' HTTP route to be a map", vs.GetName())
+		}
+		result = append(result, typedHTTPRoute)
+	}
+
+	return result, nil
+}
+
+// revisionDestination returns destination for revision's Kubernetes service
+func revisionDestination(revision *v1alpha1.Revision) (map[string]interface{}, error) {
+	if len(revision.Status.ServiceName) == 0 {
+		return nil, fmt.Errorf("Expected revision '%s' to have Kubernetes service", revision.Name)
+	}
+
+	return map[string]interface{}{
+		"host": fmt.Sprintf("%s.%s.svc.cluster.local", revision.Status.ServiceName, revision.Namespace),
+		"port": map[string]interface{}{"number": int64(80)},
+	}, nil
+}
@@ -0,0 +1,170 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package route_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/cppforlife/knctl/pkg/knctl/cmd/route"
+	"github.com/cppforlife/knctl/pkg/knctl/testkit"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+var (
+	testVirtualServiceGVR = schema.GroupVersionResource{
+		Group: "networking.istio.io", Version: "v1alpha3", Resource: "virtualservices"}
+)
+
+// newTestVirtualService builds virtual service similar to one generated
+// by Knative for a route (lives in knative-serving namespace)
+func newTestVirtualService(routeName, routeNamespace string) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "networking.istio.io/v1alpha3",
+		"kind":       "VirtualService",
+		"metadata": map[string]interface{}{
+			"name":      routeName,
+			"namespace": "knative-serving",
+			"labels": map[string]interface{}{
+				"serving.knative.dev/route":          routeName,
+				"serving.knative.dev/routeNamespace": routeNamespace,
+			},
+		},
+		"spec": map[string]interface{}{
+			"http": []interface{}{
+				map[string]interface{}{
+					"match": []interface{}{
+						map[string]interface{}{"authority": map[string]interface{}{"regex": "^svc1\\.ns1\\.example\\.com(?::\\d{1,5})?$"}},
+						map[string]interface{}{"authority": map[string]interface{}{"regex": "^svc1\\.ns1\\.svc\\.cluster\\.local(?::\\d{1,5})?$"}},
+					},
+					"route": []interface{}{
+						map[string]interface{}{
+							"destination": map[string]interface{}{
+								"host": "svc1-00001-service.ns1.svc.cluster.local",
+								"port": map[string]interface{}{"number": int64(80)},
+							},
+							"weight": int64(100),
+						},
+					},
+					"appendHeaders": map[string]interface{}{
+						"knative-serving-namespace": routeNamespace,
+						"knative-serving-revision":  "svc1-00001",
+					},
+				},
+			},
+		},
+	}}
+}
+
+func testVirtualServiceHTTPRoutes(t *testing.T, cluster *testkit.Cluster, name string) []map[string]interface{} {
+	dynamicClient, err := cluster.DepsFactory().DynamicClient()
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	vs, err := dynamicClient.Resource(testVirtualServiceGVR).Namespace("knative-serving").Get(name, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	httpRoutes, _, err := unstructured.NestedSlice(vs.Object, "spec", "http")
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	var result []map[string]interface{}
+	for _, httpRoute := range httpRoutes {
+		result = append(result, httpRoute.(map[string]interface{}))
+	}
+	return result
+}
+
+func TestRouteVirtualServices_UpdateHTTPRoutesFailsWhenReverted(t *testing.T) {
+	route := testkit.NewRoute("ns1", "svc1").Build()
+	cluster := testkit.NewCluster(t, newTestVirtualService("svc1", "ns1"), route)
+
+	dynamicClient, err := cluster.DepsFactory().DynamicClient()
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	addMarker := func(httpRoutes []map[string]interface{}) ([]map[string]interface{}, error) {
+		for _, httpRoute := range httpRoutes {
+			httpRoute["mirrorPercent"] = int64(10)
+		}
+		return httpRoutes, nil
+	}
+
+	virtualServices := NewRouteVirtualServices(dynamicClient).WithRevertCheck(5*time.Second, 10*time.Millisecond)
+	stopReverting := make(chan struct{})
+	defer close(stopReverting)
+
+	// Simulate Knative resetting virtual service to desired state
+	go func() {
+		vsClient := dynamicClient.Resource(testVirtualServiceGVR).Namespace("knative-serving")
+
+		for {
+			select {
+			case <-stopReverting:
+				return
+			case <-time.After(5 * time.Millisecond):
+			}
+
+			vs, err := vsClient.Get("svc1", metav1.GetOptions{})
+			if err != nil {
+				continue
+			}
+
+			httpRoutes, _, _ := unstructured.NestedSlice(vs.Object, "spec", "http")
+			if _, found := httpRoutes[0].(map[string]interface{})["mirrorPercent"]; found {
+				reverted := newTestVirtualService("svc1", "ns1")
+				reverted.SetResourceVersion(vs.GetResourceVersion())
+				vsClient.Update(reverted)
+			}
+		}
+	}()
+
+	err = virtualServices.UpdateHTTPRoutes(route, addMarker)
+	if err == nil || !strings.Contains(err.Error(), "Knative reverted them") {
+		t.Fatalf("Expected revert to be detected, but was: %v", err)
+	}
+}
+
+func TestRouteVirtualServices_UpdateHTTPRoutesSucceedsWhenKept(t *testing.T) {
+	route := testkit.NewRoute("ns1", "svc1").Build()
+	cluster := testkit.NewCluster(t, newTestVirtualService("svc1", "ns1"), route)
+
+	dynamicClient, err := cluster.DepsFactory().DynamicClient()
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	virtualServices := NewRouteVirtualServices(dynamicClient).WithRevertCheck(30*time.Millisecond, 10*time.Millisecond)
+
+	err = virtualServices.UpdateHTTPRoutes(route, func(httpRoutes []map[string]interface{}) ([]map[string]interface{}, error) {
+		for _, httpRoute := range httpRoutes {
+			httpRoute["mirrorPercent"] = int64(10)
+		}
+		return httpRoutes, nil
+	})
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+}
@@ -0,0 +1,123 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package route
+
+import (
+	"math"
+	"sort"
+
+	"github.com/knative/serving/pkg/apis/serving/v1alpha1"
+)
+
+const (
+	// Matches env variable name set by Knative for each revision container,
+	// hence applications are expected to echo it back as a response header
+	DefaultRevisionHeader = "K-Revision"
+
+	unknownRevision = "<unknown>"
+)
+
+type TrafficVerification struct {
+	expected map[string]float64
+	observed map[string]int
+	samples  int
+	failures int
+}
+
+type TrafficVerificationResult struct {
+	Revision        string
+	ExpectedPercent float64
+	ObservedPercent float64
+	Observed        int
+	Deviation       float64 // in standard deviations
+}
+
+func NewTrafficVerification(route *v1alpha1.Route) *TrafficVerification {
+	expected := map[string]float64{}
+
+	for _, target := range route.Status.Traffic {
+		if len(target.RevisionName) > 0 {
+			expected[target.RevisionName] += float64(target.Percent)
+		}
+	}
+
+	return &TrafficVerification{expected: expected, observed: map[string]int{}}
+}
+
+// Record tracks a single response; empty revision counts as unknown
+func (v *TrafficVerification) Record(revision string) {
+	if len(revision) == 0 {
+		revision = unknownRevision
+	}
+	v.observed[revision]++
+	v.samples++
+}
+
+func (v *TrafficVerification) RecordFailure() { v.failures++ }
+
+func (v *TrafficVerification) Samples() int  { return v.samples }
+func (v *TrafficVerification) Failures() int { return v.failures }
+func (v *TrafficVerification) Unknown() int  { return v.observed[unknownRevision] }
+
+// Results compares observed share of samples served by each revision
+// with configured percentage using binomial standard deviation
+func (v *TrafficVerification) Results() []TrafficVerificationResult {
+	revisions := map[string]struct{}{}
+
+	for rev := range v.expected {
+		revisions[rev] = struct{}{}
+	}
+	for rev := range v.observed {
+		revisions[rev] = struct{}{}
+	}
+
+	var results []TrafficVerificationResult
+
+	for rev := range revisions {
+		result := TrafficVerificationResult{
+			Revision:        rev,
+			ExpectedPercent: v.expected[rev],
+			Observed:        v.observed[rev],
+		}
+
+		if v.samples > 0 {
+			p := result.ExpectedPercent / 100
+			observedP := float64(result.Observed) / float64(v.samples)
+			stdDev := math.Sqrt(p * (1 - p) / float64(v.samples))
+
+			result.ObservedPercent = observedP * 100
+
+			switch {
+			case stdDev > 0:
+				result.Deviation = math.Abs(observedP-p) / stdDev
+			case observedP != p:
+				// Revisions with 0% or 100% must be observed exactly
+				result.Deviation = math.Inf(1)
+			}
+		}
+
+		results = append(results, result)
+	}
+
+	sort.Slice(results, func(i, j int) bool { return results[i].Revision < results[j].Revision })
+
+	return results
+}
+
+func (r TrafficVerificationResult) Skewed(maxDeviation float64) bool {
+	return r.Deviation > maxDeviation
+}
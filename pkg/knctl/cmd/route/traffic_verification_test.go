@@ -0,0 +1,86 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package route_test
+
+import (
+	"math"
+	"testing"
+
+	. "github.com/cppforlife/knctl/pkg/knctl/cmd/route"
+	"github.com/cppforlife/knctl/pkg/knctl/testkit"
+)
+
+func TestTrafficVerification_WithinDeviation(t *testing.T) {
+	route := testkit.NewRoute("ns1", "svc1").Traffic("svc1-00001", 80).Traffic("svc1-00002", 20).Build()
+	verification := NewTrafficVerification(route)
+
+	recordN(verification, "svc1-00001", 410)
+	recordN(verification, "svc1-00002", 90)
+
+	results := verification.Results()
+	if len(results) != 2 {
+		t.Fatalf("Expected two results: %#v", results)
+	}
+
+	for _, result := range results {
+		if result.Skewed(3) {
+			t.Fatalf("Expected result to not be skewed: %#v", result)
+		}
+	}
+
+	if results[1].Revision != "svc1-00002" || results[1].Observed != 90 || results[1].ObservedPercent != 18 {
+		t.Fatalf("Expected second result to be for svc1-00002: %#v", results[1])
+	}
+}
+
+func TestTrafficVerification_Skewed(t *testing.T) {
+	route := testkit.NewRoute("ns1", "svc1").Traffic("svc1-00001", 50).Traffic("svc1-00002", 50).Build()
+	verification := NewTrafficVerification(route)
+
+	recordN(verification, "svc1-00001", 400)
+	recordN(verification, "svc1-00002", 100)
+
+	for _, result := range verification.Results() {
+		if !result.Skewed(3) {
+			t.Fatalf("Expected result to be skewed: %#v", result)
+		}
+	}
+}
+
+func TestTrafficVerification_UnexpectedRevision(t *testing.T) {
+	route := testkit.NewRoute("ns1", "svc1").Traffic("svc1-00001", 100).Build()
+	verification := NewTrafficVerification(route)
+
+	recordN(verification, "svc1-00001", 99)
+	recordN(verification, "", 1)
+
+	if verification.Unknown() != 1 {
+		t.Fatalf("Expected one unknown sample")
+	}
+
+	for _, result := range verification.Results() {
+		if !math.IsInf(result.Deviation, 1) {
+			t.Fatalf("Expected infinite deviation for exact percentages: %#v", result)
+		}
+	}
+}
+
+func recordN(verification *TrafficVerification, revision string, n int) {
+	for i := 0; i < n; i++ {
+		verification.Record(revision)
+	}
+}
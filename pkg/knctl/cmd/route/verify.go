@@ -0,0 +1,227 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package route
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math"
+	"net/http"
+
+	"github.com/cppforlife/go-cli-ui/ui"
+	uitable "github.com/cppforlife/go-cli-ui/ui/table"
+	cmdcore "github.com/cppforlife/knctl/pkg/knctl/cmd/core"
+	cmdflags "github.com/cppforlife/knctl/pkg/knctl/cmd/flags"
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type VerifyOptions struct {
+	ui          ui.UI
+	depsFactory cmdcore.DepsFactory
+
+	ServiceFlags   cmdflags.ServiceFlags
+	CurlFlags      CurlFlags
+	Samples        int
+	Path           string
+	RevisionHeader string
+	MaxDeviation   float64
+}
+
+func NewVerifyOptions(ui ui.UI, depsFactory cmdcore.DepsFactory) *VerifyOptions {
+	return &VerifyOptions{ui: ui, depsFactory: depsFactory}
+}
+
+func NewVerifyCmd(o *VerifyOptions, flagsFactory cmdcore.FlagsFactory) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "verify",
+		Short: "Verify traffic split",
+		Long: `Verify that observed traffic split matches configured traffic percentages.
+
+Sends sampled requests to the service and tallies which revision served each request
+based on a response header (application is expected to return value of K_REVISION env variable).
+Revision is flagged as skewed when its observed share deviates from configured percentage
+by more than --max-deviation standard deviations.`,
+		Example: `
+  # Verify traffic split for service 'svc1' in namespace 'ns1' with 500 requests
+  knctl rollout verify -s svc1 --samples 500 -n ns1
+
+  # Verify traffic split using custom revision response header
+  knctl rollout verify -s svc1 --revision-header X-Revision -n ns1`,
+		RunE: func(_ *cobra.Command, _ []string) error { return o.Run() },
+	}
+	o.ServiceFlags.Set(cmd, flagsFactory)
+	o.CurlFlags.Set(cmd, flagsFactory)
+	cmd.Flags().IntVar(&o.Samples, "samples", 200, "Set number of requests to send")
+	cmd.Flags().StringVar(&o.Path, "path", "/", "Set request path")
+	cmd.Flags().StringVar(&o.RevisionHeader, "revision-header", DefaultRevisionHeader, "Set response header that contains name of revision that served request")
+	cmd.Flags().Float64Var(&o.MaxDeviation, "max-deviation", 3, "Set maximum allowed deviation (in standard deviations) from configured percentage")
+	return cmd
+}
+
+func (o *VerifyOptions) Run() error {
+	if o.Samples < 1 {
+		return fmt.Errorf("Expected number of samples to be greater than 0")
+	}
+	if o.MaxDeviation <= 0 {
+		return fmt.Errorf("Expected maximum deviation to be greater than 0")
+	}
+
+	servingClient, err := o.depsFactory.ServingClient()
+	if err != nil {
+		return err
+	}
+
+	// Assumes that route has the same name as the service
+	route, err := servingClient.ServingV1alpha1().Routes(o.ServiceFlags.NamespaceFlags.Name).Get(o.ServiceFlags.Name, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("Getting route: %s", err)
+	}
+
+	coreClient, err := o.depsFactory.CoreClient()
+	if err != nil {
+		return err
+	}
+
+	cache, err := o.depsFactory.Cache()
+	if err != nil {
+		return err
+	}
+
+	logger, err := o.depsFactory.Logger()
+	if err != nil {
+		return err
+	}
+
+	inCluster, err := o.depsFactory.InCluster()
+	if err != nil {
+		return err
+	}
+
+	routeAddr := RouteAddress{route, coreClient, cache, logger, inCluster}
+
+	domain, err := routeAddr.Domain()
+	if err != nil {
+		return err
+	}
+
+	url, err := routeAddr.URL(o.CurlFlags.Port, false)
+	if err != nil {
+		return err
+	}
+
+	httpClient, err := o.depsFactory.HTTPClient()
+	if err != nil {
+		return err
+	}
+
+	verification := NewTrafficVerification(route)
+
+	o.ui.PrintLinef("Sending %d requests to service '%s'", o.Samples, o.ServiceFlags.Name)
+
+	for i := 0; i < o.Samples; i++ {
+		revision, err := o.sample(httpClient, url+o.Path, domain)
+		if err != nil {
+			verification.RecordFailure()
+			continue
+		}
+		verification.Record(revision)
+	}
+
+	if verification.Samples() == 0 {
+		return fmt.Errorf("Expected at least one request to succeed, but all %d failed", o.Samples)
+	}
+
+	if verification.Unknown() == verification.Samples() {
+		return fmt.Errorf("Expected responses to include '%s' header identifying revision", o.RevisionHeader)
+	}
+
+	return o.report(verification)
+}
+
+func (o *VerifyOptions) sample(httpClient *http.Client, url, domain string) (string, error) {
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return "", err
+	}
+
+	req.Host = domain
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+
+	// Drain body so that connection can be reused by next request
+	io.Copy(ioutil.Discard, resp.Body)
+	resp.Body.Close()
+
+	return resp.Header.Get(o.RevisionHeader), nil
+}
+
+func (o *VerifyOptions) report(verification *TrafficVerification) error {
+	table := uitable.Table{
+		Title:   fmt.Sprintf("Traffic split for service '%s' (%d samples)", o.ServiceFlags.Name, verification.Samples()),
+		Content: "revisions",
+
+		Header: []uitable.Header{
+			uitable.NewHeader("Revision"),
+			uitable.NewHeader("Expected"),
+			uitable.NewHeader("Observed"),
+			uitable.NewHeader("Requests"),
+			uitable.NewHeader("Deviation"),
+			uitable.NewHeader("OK"),
+		},
+	}
+
+	var skewed int
+
+	for _, result := range verification.Results() {
+		ok := !result.Skewed(o.MaxDeviation)
+		if !ok {
+			skewed++
+		}
+
+		deviation := fmt.Sprintf("%.1f", result.Deviation)
+		if math.IsInf(result.Deviation, 1) {
+			deviation = "inf"
+		}
+
+		table.Rows = append(table.Rows, []uitable.Value{
+			uitable.NewValueString(result.Revision),
+			uitable.NewValueString(fmt.Sprintf("%.0f%%", result.ExpectedPercent)),
+			uitable.NewValueString(fmt.Sprintf("%.1f%%", result.ObservedPercent)),
+			uitable.NewValueInt(result.Observed),
+			uitable.NewValueString(deviation),
+			uitable.ValueFmt{V: uitable.NewValueBool(ok), Error: !ok},
+		})
+	}
+
+	o.ui.PrintTable(table)
+
+	if verification.Failures() > 0 {
+		o.ui.ErrorLinef("Warning: %d of %d requests failed and were excluded from samples",
+			verification.Failures(), o.Samples)
+	}
+
+	if skewed > 0 {
+		return fmt.Errorf("Expected observed traffic split to match configured percentages, but %d revision(s) are skewed", skewed)
+	}
+
+	return nil
+}
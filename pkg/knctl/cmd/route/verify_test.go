@@ -0,0 +1,53 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package route_test
+
+import (
+	"testing"
+
+	. "github.com/cppforlife/knctl/pkg/knctl/cmd"
+	cmdcore "github.com/cppforlife/knctl/pkg/knctl/cmd/core"
+	cmdflags "github.com/cppforlife/knctl/pkg/knctl/cmd/flags"
+	. "github.com/cppforlife/knctl/pkg/knctl/cmd/route"
+)
+
+func TestNewVerifyCmd_Ok(t *testing.T) {
+	realCmd := NewVerifyOptions(nil, cmdcore.NewDepsFactory())
+	cmd := NewTestCmd(t, NewVerifyCmd(realCmd, cmdcore.FlagsFactory{}))
+	cmd.ExpectBasicConfig()
+	cmd.Execute([]string{
+		"-n", "test-namespace",
+		"-s", "test-service",
+		"--samples", "500",
+		"--revision-header", "X-Revision",
+	})
+	cmd.ExpectReachesExecution()
+
+	DeepEqual(t, realCmd.ServiceFlags,
+		cmdflags.ServiceFlags{cmdcore.NamespaceFlags{"test-namespace"}, "test-service"})
+	DeepEqual(t, realCmd.Samples, 500)
+	DeepEqual(t, realCmd.Path, "/")
+	DeepEqual(t, realCmd.RevisionHeader, "X-Revision")
+	DeepEqual(t, realCmd.MaxDeviation, float64(3))
+}
+
+func TestNewVerifyCmd_RequiredFlags(t *testing.T) {
+	realCmd := NewVerifyOptions(nil, cmdcore.NewDepsFactory())
+	cmd := NewTestCmd(t, NewVerifyCmd(realCmd, cmdcore.FlagsFactory{}))
+	cmd.Execute([]string{})
+	cmd.ExpectRequiredFlags([]string{"service"})
+}
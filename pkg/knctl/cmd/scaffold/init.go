@@ -0,0 +1,125 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scaffold
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/cppforlife/go-cli-ui/ui"
+	uitable "github.com/cppforlife/go-cli-ui/ui/table"
+	cmdcore "github.com/cppforlife/knctl/pkg/knctl/cmd/core"
+	"github.com/spf13/cobra"
+)
+
+type InitOptions struct {
+	ui ui.UI
+
+	Runtime    string
+	Directory  string
+	Name       string
+	Image      string
+	Buildpacks bool
+	Force      bool
+}
+
+func NewInitOptions(ui ui.UI) *InitOptions {
+	return &InitOptions{ui: ui}
+}
+
+func NewInitCmd(o *InitOptions, flagsFactory cmdcore.FlagsFactory) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "init",
+		Short: "Scaffold a new Knative app",
+		Long: `Scaffold a new Knative app.
+
+Creates a minimal HTTP app, Dockerfile (or project.toml with --buildpacks)
+and Knative service configuration (` + ServiceFileName + `) that could be deployed via 'knctl apply'.
+
+Runtimes: ` + strings.Join(Runtimes(), ", "),
+		Example: `
+  # Scaffold Go app in current directory
+  knctl init --runtime go
+
+  # Scaffold Node.js app built with buildpacks in directory 'app1'
+  knctl init --runtime node -d app1 --buildpacks --image index.docker.io/your-account/app1`,
+		Annotations: map[string]string{
+			cmdcore.BasicHelpGroup.Key: cmdcore.BasicHelpGroup.Value,
+		},
+		RunE: func(_ *cobra.Command, _ []string) error { return o.Run() },
+	}
+	cmd.Flags().StringVar(&o.Runtime, "runtime", "", "Set runtime ("+strings.Join(Runtimes(), ", ")+")")
+	cmd.Flags().StringVarP(&o.Directory, "directory", "d", ".", "Set directory to scaffold app in")
+	cmd.Flags().StringVar(&o.Name, "name", "", "Set service name (default: directory name)")
+	cmd.Flags().StringVar(&o.Image, "image", "", "Set image URL (default: index.docker.io/your-account/NAME)")
+	cmd.Flags().BoolVar(&o.Buildpacks, "buildpacks", false, "Build with Cloud Native Buildpacks instead of Dockerfile")
+	cmd.Flags().BoolVar(&o.Force, "force", false, "Overwrite existing files")
+	cmd.MarkFlagRequired("runtime")
+	return cmd
+}
+
+func (o *InitOptions) Run() error {
+	name := o.Name
+
+	if len(name) == 0 {
+		absDir, err := filepath.Abs(o.Directory)
+		if err != nil {
+			return err
+		}
+		name = strings.ToLower(filepath.Base(absDir))
+	}
+
+	image := o.Image
+	if len(image) == 0 {
+		image = "index.docker.io/your-account/" + name
+	}
+
+	files, err := Scaffold{Runtime: o.Runtime, Name: name, Image: image, Buildpacks: o.Buildpacks}.Write(o.Directory, o.Force)
+	if err != nil {
+		return err
+	}
+
+	table := uitable.Table{
+		Title:   fmt.Sprintf("Scaffolded %s app '%s'", o.Runtime, name),
+		Content: "files",
+
+		Header: []uitable.Header{
+			uitable.NewHeader("File"),
+		},
+	}
+
+	for _, file := range files {
+		table.Rows = append(table.Rows, []uitable.Value{
+			uitable.NewValueString(filepath.Join(o.Directory, file.Path)),
+		})
+	}
+
+	o.ui.PrintTable(table)
+
+	o.ui.PrintLinef("Next steps:")
+
+	if o.Buildpacks {
+		o.ui.PrintLinef("  pack build %s --path %s --publish", image, o.Directory)
+	} else {
+		o.ui.PrintLinef("  docker build -t %s %s && docker push %s", image, o.Directory, image)
+	}
+
+	o.ui.PrintLinef("  knctl apply -f %s -n NAMESPACE", filepath.Join(o.Directory, ServiceFileName))
+
+	return nil
+}
@@ -0,0 +1,56 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scaffold_test
+
+import (
+	"testing"
+
+	. "github.com/cppforlife/knctl/pkg/knctl/cmd"
+	cmdcore "github.com/cppforlife/knctl/pkg/knctl/cmd/core"
+	. "github.com/cppforlife/knctl/pkg/knctl/cmd/scaffold"
+)
+
+func TestNewInitCmd_Ok(t *testing.T) {
+	realCmd := NewInitOptions(nil)
+	cmd := NewTestCmd(t, NewInitCmd(realCmd, cmdcore.FlagsFactory{}))
+	cmd.ExpectBasicConfig()
+	cmd.Execute([]string{
+		"--runtime", "go",
+		"-d", "app1",
+		"--name", "svc1",
+		"--image", "img1",
+		"--buildpacks",
+		"--force",
+	})
+	cmd.ExpectReachesExecution()
+
+	DeepEqual(t, realCmd, &InitOptions{
+		Runtime:    "go",
+		Directory:  "app1",
+		Name:       "svc1",
+		Image:      "img1",
+		Buildpacks: true,
+		Force:      true,
+	})
+}
+
+func TestNewInitCmd_RequiredFlags(t *testing.T) {
+	realCmd := NewInitOptions(nil)
+	cmd := NewTestCmd(t, NewInitCmd(realCmd, cmdcore.FlagsFactory{}))
+	cmd.Execute([]string{})
+	cmd.ExpectRequiredFlags([]string{"runtime"})
+}
@@ -0,0 +1,166 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scaffold
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+)
+
+const (
+	ServiceFileName = "service.yml"
+)
+
+type runtimeFiles struct {
+	App        map[string]string
+	Buildpacks map[string]string
+	Dockerfile string
+}
+
+var (
+	runtimes = map[string]runtimeFiles{
+		"go": {
+			App:        map[string]string{"main.go": goMainTpl, "go.mod": goModTpl},
+			Dockerfile: goDockerfileTpl,
+		},
+		"node": {
+			App:        map[string]string{"index.js": nodeIndexTpl, "package.json": nodePackageTpl},
+			Dockerfile: nodeDockerfileTpl,
+		},
+		"python": {
+			App:        map[string]string{"app.py": pythonAppTpl, "requirements.txt": pythonRequirementsTpl},
+			Buildpacks: map[string]string{"Procfile": pythonProcfileTpl},
+			Dockerfile: pythonDockerfileTpl,
+		},
+	}
+)
+
+// Runtimes returns names of supported runtimes
+func Runtimes() []string {
+	var names []string
+	for name := range runtimes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+type Scaffold struct {
+	Runtime    string
+	Name       string
+	Image      string
+	Buildpacks bool
+}
+
+type File struct {
+	Path    string
+	Content []byte
+}
+
+// Files returns rendered files sorted by path
+func (s Scaffold) Files() ([]File, error) {
+	rt, found := runtimes[s.Runtime]
+	if !found {
+		return nil, fmt.Errorf("Expected runtime to be one of: %s", strings.Join(Runtimes(), ", "))
+	}
+
+	tpls := map[string]string{ServiceFileName: serviceTpl}
+
+	for path, tpl := range rt.App {
+		tpls[path] = tpl
+	}
+
+	if s.Buildpacks {
+		tpls["project.toml"] = projectTomlTpl
+		for path, tpl := range rt.Buildpacks {
+			tpls[path] = tpl
+		}
+	} else {
+		tpls["Dockerfile"] = rt.Dockerfile
+	}
+
+	var result []File
+
+	for path, tpl := range tpls {
+		content, err := s.render(path, tpl)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, File{Path: path, Content: content})
+	}
+
+	sort.Slice(result, func(i, j int) bool { return result[i].Path < result[j].Path })
+
+	return result, nil
+}
+
+// Write writes files into directory; existing files are only overwritten when forced
+func (s Scaffold) Write(dir string, force bool) ([]File, error) {
+	files, err := s.Files()
+	if err != nil {
+		return nil, err
+	}
+
+	if !force {
+		for _, file := range files {
+			path := filepath.Join(dir, file.Path)
+
+			_, err := os.Stat(path)
+			if err == nil {
+				return nil, fmt.Errorf("Expected file '%s' to not exist (use --force to overwrite it)", path)
+			}
+		}
+	}
+
+	err = os.MkdirAll(dir, 0755)
+	if err != nil {
+		return nil, fmt.Errorf("Creating directory '%s': %s", dir, err)
+	}
+
+	for _, file := range files {
+		path := filepath.Join(dir, file.Path)
+
+		err := ioutil.WriteFile(path, file.Content, 0644)
+		if err != nil {
+			return nil, fmt.Errorf("Writing file '%s': %s", path, err)
+		}
+	}
+
+	return files, nil
+}
+
+func (s Scaffold) render(path, tpl string) ([]byte, error) {
+	t, err := template.New(path).Parse(tpl)
+	if err != nil {
+		return nil, fmt.Errorf("Parsing template for '%s': %s", path, err)
+	}
+
+	var buf bytes.Buffer
+
+	err = t.Execute(&buf, s)
+	if err != nil {
+		return nil, fmt.Errorf("Rendering template for '%s': %s", path, err)
+	}
+
+	return buf.Bytes(), nil
+}
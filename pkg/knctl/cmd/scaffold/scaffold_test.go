@@ -0,0 +1,107 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scaffold_test
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	. "github.com/cppforlife/knctl/pkg/knctl/cmd/scaffold"
+)
+
+func TestScaffoldFiles(t *testing.T) {
+	exs := []struct {
+		Scaffold Scaffold
+		Paths    []string
+	}{
+		{Scaffold{Runtime: "go"}, []string{"Dockerfile", "go.mod", "main.go", "service.yml"}},
+		{Scaffold{Runtime: "go", Buildpacks: true}, []string{"go.mod", "main.go", "project.toml", "service.yml"}},
+		{Scaffold{Runtime: "node"}, []string{"Dockerfile", "index.js", "package.json", "service.yml"}},
+		{Scaffold{Runtime: "python"}, []string{"Dockerfile", "app.py", "requirements.txt", "service.yml"}},
+		{Scaffold{Runtime: "python", Buildpacks: true}, []string{"Procfile", "app.py", "project.toml", "requirements.txt", "service.yml"}},
+	}
+
+	for _, ex := range exs {
+		ex.Scaffold.Name = "app1"
+		ex.Scaffold.Image = "img1"
+
+		files, err := ex.Scaffold.Files()
+		if err != nil {
+			t.Fatalf("Expected no error: %s", err)
+		}
+
+		var paths []string
+		for _, file := range files {
+			paths = append(paths, file.Path)
+		}
+
+		if !reflect.DeepEqual(paths, ex.Paths) {
+			t.Fatalf("Expected files for %#v to match: %#v", ex.Scaffold, paths)
+		}
+	}
+}
+
+func TestScaffoldFilesRendersService(t *testing.T) {
+	files, err := Scaffold{Runtime: "go", Name: "app1", Image: "img1"}.Files()
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	for _, file := range files {
+		if file.Path == ServiceFileName {
+			content := string(file.Content)
+			if !strings.Contains(content, "name: app1\n") || !strings.Contains(content, "image: img1\n") {
+				t.Fatalf("Expected service to include name and image: %s", content)
+			}
+		}
+	}
+}
+
+func TestScaffoldFilesUnknownRuntime(t *testing.T) {
+	_, err := Scaffold{Runtime: "ruby"}.Files()
+	if err == nil || err.Error() != "Expected runtime to be one of: go, node, python" {
+		t.Fatalf("Expected unknown runtime error, but was: %v", err)
+	}
+}
+
+func TestScaffoldWrite(t *testing.T) {
+	dir := t.TempDir()
+	scaffold := Scaffold{Runtime: "python", Name: "app1", Image: "img1"}
+
+	_, err := scaffold.Write(dir, false)
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	content, err := ioutil.ReadFile(filepath.Join(dir, "app.py"))
+	if err != nil || !strings.Contains(string(content), "HTTPServer") {
+		t.Fatalf("Expected app to be written: %v", err)
+	}
+
+	_, err = scaffold.Write(dir, false)
+	if err == nil || !strings.Contains(err.Error(), "to not exist (use --force to overwrite it)") {
+		t.Fatalf("Expected existing files to not be overwritten, but was: %v", err)
+	}
+
+	_, err = scaffold.Write(dir, true)
+	if err != nil {
+		t.Fatalf("Expected forced write to succeed: %s", err)
+	}
+}
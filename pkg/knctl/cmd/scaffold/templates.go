@@ -0,0 +1,154 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scaffold
+
+const (
+	goMainTpl = `package main
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+)
+
+func main() {
+	target := os.Getenv("TARGET")
+	if len(target) == 0 {
+		target = "World"
+	}
+
+	http.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, "Hello %s!\n", target)
+	})
+
+	port := os.Getenv("PORT")
+	if len(port) == 0 {
+		port = "8080"
+	}
+
+	log.Printf("Listening on port %s", port)
+	log.Fatal(http.ListenAndServe(":"+port, nil))
+}
+`
+
+	goModTpl = `module {{.Name}}
+
+go 1.21
+`
+
+	goDockerfileTpl = `FROM golang:1.21 AS build
+WORKDIR /src
+COPY . .
+RUN CGO_ENABLED=0 go build -o /app .
+
+FROM gcr.io/distroless/static
+COPY --from=build /app /app
+ENTRYPOINT ["/app"]
+`
+
+	nodeIndexTpl = `const http = require('http');
+
+const target = process.env.TARGET || 'World';
+const port = process.env.PORT || 8080;
+
+const server = http.createServer((req, res) => {
+  res.end('Hello ' + target + '!\n');
+});
+
+server.listen(port, () => {
+  console.log('Listening on port ' + port);
+});
+`
+
+	nodePackageTpl = `{
+  "name": "{{.Name}}",
+  "version": "0.1.0",
+  "private": true,
+  "main": "index.js",
+  "scripts": {
+    "start": "node index.js"
+  }
+}
+`
+
+	nodeDockerfileTpl = `FROM node:20-slim
+WORKDIR /app
+COPY package*.json ./
+RUN npm install --omit=dev
+COPY . .
+CMD ["npm", "start"]
+`
+
+	pythonAppTpl = `import os
+from http.server import BaseHTTPRequestHandler, HTTPServer
+
+TARGET = os.environ.get('TARGET', 'World')
+PORT = int(os.environ.get('PORT', '8080'))
+
+
+class Handler(BaseHTTPRequestHandler):
+    def do_GET(self):
+        self.send_response(200)
+        self.end_headers()
+        self.wfile.write(('Hello %s!\n' % TARGET).encode())
+
+
+if __name__ == '__main__':
+    print('Listening on port %d' % PORT)
+    HTTPServer(('', PORT), Handler).serve_forever()
+`
+
+	pythonRequirementsTpl = `# Add application dependencies here
+`
+
+	pythonProcfileTpl = `web: python app.py
+`
+
+	pythonDockerfileTpl = `FROM python:3.12-slim
+WORKDIR /app
+COPY requirements.txt .
+RUN pip install --no-cache-dir -r requirements.txt
+COPY . .
+CMD ["python", "app.py"]
+`
+
+	projectTomlTpl = `[_]
+schema-version = "0.2"
+id = "{{.Name}}"
+
+[io.buildpacks]
+builder = "paketobuildpacks/builder-jammy-base"
+`
+
+	serviceTpl = `# Deploy with: knctl apply -f service.yml -n NAMESPACE
+apiVersion: serving.knative.dev/v1alpha1
+kind: Service
+metadata:
+  name: {{.Name}}
+spec:
+  runLatest:
+    configuration:
+      revisionTemplate:
+        spec:
+          container:
+            image: {{.Image}}
+            env:
+            - name: TARGET
+              value: World
+`
+)
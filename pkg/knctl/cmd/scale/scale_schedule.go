@@ -0,0 +1,138 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scale
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/knative/serving/pkg/apis/autoscaling"
+	"github.com/knative/serving/pkg/apis/serving"
+	batchv1 "k8s.io/api/batch/v1"
+	batchv1beta1 "k8s.io/api/batch/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	scheduleServiceLabelKey  = "scale.cli.knative.dev/service"
+	scheduleResourceBaseName = "knctl-scale-scheduler"
+)
+
+type ScheduleEntry struct {
+	Cron     string
+	MinScale int
+}
+
+// ScaleSchedule produces cron jobs (and RBAC resources for them) that
+// update minimum scale annotation of service's pod autoscalers on schedule.
+// Pod autoscalers are annotated directly since changing revision template
+// would result in a new revision.
+type ScaleSchedule struct {
+	Namespace    string
+	Service      string
+	KubectlImage string
+	Entries      []ScheduleEntry
+}
+
+func (s ScaleSchedule) ServiceAccount() *corev1.ServiceAccount {
+	return &corev1.ServiceAccount{ObjectMeta: s.sharedMeta()}
+}
+
+func (s ScaleSchedule) Role() *rbacv1.Role {
+	return &rbacv1.Role{
+		ObjectMeta: s.sharedMeta(),
+		Rules: []rbacv1.PolicyRule{{
+			APIGroups: []string{autoscaling.InternalGroupName},
+			Resources: []string{"podautoscalers"},
+			Verbs:     []string{"get", "list", "patch"},
+		}},
+	}
+}
+
+func (s ScaleSchedule) RoleBinding() *rbacv1.RoleBinding {
+	return &rbacv1.RoleBinding{
+		ObjectMeta: s.sharedMeta(),
+		Subjects: []rbacv1.Subject{{
+			Kind:      rbacv1.ServiceAccountKind,
+			Name:      scheduleResourceBaseName,
+			Namespace: s.Namespace,
+		}},
+		RoleRef: rbacv1.RoleRef{
+			APIGroup: rbacv1.GroupName,
+			Kind:     "Role",
+			Name:     scheduleResourceBaseName,
+		},
+	}
+}
+
+func (s ScaleSchedule) CronJobs() []*batchv1beta1.CronJob {
+	var result []*batchv1beta1.CronJob
+
+	for i, entry := range s.Entries {
+		result = append(result, &batchv1beta1.CronJob{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      fmt.Sprintf("%s-scale-%d", s.Service, i),
+				Namespace: s.Namespace,
+				Labels:    s.CronJobLabels(),
+			},
+			Spec: batchv1beta1.CronJobSpec{
+				Schedule:          entry.Cron,
+				ConcurrencyPolicy: batchv1beta1.ReplaceConcurrent,
+				JobTemplate: batchv1beta1.JobTemplateSpec{
+					Spec: batchv1.JobSpec{
+						Template: corev1.PodTemplateSpec{
+							Spec: corev1.PodSpec{
+								ServiceAccountName: scheduleResourceBaseName,
+								RestartPolicy:      corev1.RestartPolicyOnFailure,
+								Containers: []corev1.Container{{
+									Name:    "scale",
+									Image:   s.KubectlImage,
+									Command: s.annotateCmd(entry),
+								}},
+							},
+						},
+					},
+				},
+			},
+		})
+	}
+
+	return result
+}
+
+func (s ScaleSchedule) CronJobLabels() map[string]string {
+	return map[string]string{scheduleServiceLabelKey: s.Service}
+}
+
+func (s ScaleSchedule) annotateCmd(entry ScheduleEntry) []string {
+	ann := autoscaling.MinScaleAnnotationKey + "-"
+	if entry.MinScale > 0 {
+		ann = autoscaling.MinScaleAnnotationKey + "=" + strconv.Itoa(entry.MinScale)
+	}
+
+	return []string{
+		"kubectl", "annotate", "podautoscalers." + autoscaling.InternalGroupName,
+		"-n", s.Namespace, "-l", serving.ConfigurationLabelKey + "=" + s.Service,
+		ann, "--overwrite",
+	}
+}
+
+func (s ScaleSchedule) sharedMeta() metav1.ObjectMeta {
+	return metav1.ObjectMeta{Name: scheduleResourceBaseName, Namespace: s.Namespace}
+}
@@ -0,0 +1,201 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scale
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/cppforlife/go-cli-ui/ui"
+	uitable "github.com/cppforlife/go-cli-ui/ui/table"
+	cmdcore "github.com/cppforlife/knctl/pkg/knctl/cmd/core"
+	cmdflags "github.com/cppforlife/knctl/pkg/knctl/cmd/flags"
+	"github.com/spf13/cobra"
+	batchv1beta1 "k8s.io/api/batch/v1beta1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes"
+)
+
+type ScheduleOptions struct {
+	ui          ui.UI
+	depsFactory cmdcore.DepsFactory
+
+	ServiceFlags  cmdflags.ServiceFlags
+	ScheduleFlags ScheduleFlags
+}
+
+func NewScheduleOptions(ui ui.UI, depsFactory cmdcore.DepsFactory) *ScheduleOptions {
+	return &ScheduleOptions{ui: ui, depsFactory: depsFactory}
+}
+
+func NewScheduleCmd(o *ScheduleOptions, flagsFactory cmdcore.FlagsFactory) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "schedule",
+		Short: "Schedule minimum scale changes for service",
+		Long: `Schedule minimum scale changes for service.
+
+Creates a cron job per schedule that updates autoscaling rules of service's revisions.
+Previously scheduled cron jobs for the service that are no longer specified are deleted.
+
+Use 'kubectl delete cronjobs -l scale.cli.knative.dev/service=<name> -n <namespace>' to delete all schedules.`,
+		Example: `
+  # Keep 3 containers of service 'svc1' warm during business hours in namespace 'ns1'
+  knctl scale schedule -s svc1 --cron '0 8 * * 1-5' --min-scale 3 --cron '0 20 * * *' --min-scale 0 -n ns1`,
+		RunE: func(_ *cobra.Command, _ []string) error { return o.Run() },
+	}
+	o.ServiceFlags.Set(cmd, flagsFactory)
+	o.ScheduleFlags.Set(cmd, flagsFactory)
+	return cmd
+}
+
+func (o *ScheduleOptions) Run() error {
+	entries, err := o.ScheduleFlags.Entries()
+	if err != nil {
+		return err
+	}
+
+	coreClient, err := o.depsFactory.CoreClient()
+	if err != nil {
+		return err
+	}
+
+	schedule := ScaleSchedule{
+		Namespace:    o.ServiceFlags.NamespaceFlags.Name,
+		Service:      o.ServiceFlags.Name,
+		KubectlImage: o.ScheduleFlags.KubectlImage,
+		Entries:      entries,
+	}
+
+	err = o.applyRBAC(coreClient, schedule)
+	if err != nil {
+		return err
+	}
+
+	cronJobs := coreClient.BatchV1beta1().CronJobs(schedule.Namespace)
+	cronJobNames := map[string]struct{}{}
+
+	for _, cronJob := range schedule.CronJobs() {
+		cronJobNames[cronJob.Name] = struct{}{}
+
+		_, err := cronJobs.Create(cronJob)
+		if err != nil {
+			if !errors.IsAlreadyExists(err) {
+				return fmt.Errorf("Creating cron job '%s': %s", cronJob.Name, err)
+			}
+
+			existing, err := cronJobs.Get(cronJob.Name, metav1.GetOptions{})
+			if err != nil {
+				return fmt.Errorf("Getting cron job '%s': %s", cronJob.Name, err)
+			}
+
+			existing.Labels = cronJob.Labels
+			existing.Spec = cronJob.Spec
+
+			_, err = cronJobs.Update(existing)
+			if err != nil {
+				return fmt.Errorf("Updating cron job '%s': %s", cronJob.Name, err)
+			}
+		}
+	}
+
+	listOpts := metav1.ListOptions{LabelSelector: labels.Set(schedule.CronJobLabels()).String()}
+
+	cronJobList, err := cronJobs.List(listOpts)
+	if err != nil {
+		return fmt.Errorf("Listing cron jobs: %s", err)
+	}
+
+	for _, cronJob := range cronJobList.Items {
+		if _, found := cronJobNames[cronJob.Name]; !found {
+			o.ui.PrintLinef("Deleting cron job '%s'", cronJob.Name)
+
+			err := cronJobs.Delete(cronJob.Name, &metav1.DeleteOptions{})
+			if err != nil && !errors.IsNotFound(err) {
+				return fmt.Errorf("Deleting cron job '%s': %s", cronJob.Name, err)
+			}
+		}
+	}
+
+	o.printTable(schedule.CronJobs(), entries)
+
+	return nil
+}
+
+func (o *ScheduleOptions) applyRBAC(coreClient kubernetes.Interface, schedule ScaleSchedule) error {
+	sa := schedule.ServiceAccount()
+
+	_, err := coreClient.CoreV1().ServiceAccounts(sa.Namespace).Create(sa)
+	if err != nil && !errors.IsAlreadyExists(err) {
+		return fmt.Errorf("Creating service account '%s': %s", sa.Name, err)
+	}
+
+	roles := coreClient.RbacV1().Roles(schedule.Namespace)
+	role := schedule.Role()
+
+	_, err = roles.Create(role)
+	if err != nil {
+		if !errors.IsAlreadyExists(err) {
+			return fmt.Errorf("Creating role '%s': %s", role.Name, err)
+		}
+
+		_, err = roles.Update(role)
+		if err != nil {
+			return fmt.Errorf("Updating role '%s': %s", role.Name, err)
+		}
+	}
+
+	roleBinding := schedule.RoleBinding()
+
+	_, err = coreClient.RbacV1().RoleBindings(schedule.Namespace).Create(roleBinding)
+	if err != nil && !errors.IsAlreadyExists(err) {
+		return fmt.Errorf("Creating role binding '%s': %s", roleBinding.Name, err)
+	}
+
+	return nil
+}
+
+func (o *ScheduleOptions) printTable(cronJobs []*batchv1beta1.CronJob, entries []ScheduleEntry) {
+	table := uitable.Table{
+		Title: fmt.Sprintf("Scale schedule for service '%s' in namespace '%s'",
+			o.ServiceFlags.Name, o.ServiceFlags.NamespaceFlags.Name),
+
+		Content: "schedules",
+
+		Header: []uitable.Header{
+			uitable.NewHeader("Cron job"),
+			uitable.NewHeader("Schedule"),
+			uitable.NewHeader("Min scale"),
+		},
+	}
+
+	for i, cronJob := range cronJobs {
+		minScale := uitable.NewValueString(strconv.Itoa(entries[i].MinScale))
+		if entries[i].MinScale == 0 {
+			minScale = uitable.NewValueString("unset")
+		}
+
+		table.Rows = append(table.Rows, []uitable.Value{
+			uitable.NewValueString(cronJob.Name),
+			uitable.NewValueString(cronJob.Spec.Schedule),
+			minScale,
+		})
+	}
+
+	o.ui.PrintTable(table)
+}
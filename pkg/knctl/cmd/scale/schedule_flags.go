@@ -0,0 +1,66 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scale
+
+import (
+	"fmt"
+	"strings"
+
+	cmdcore "github.com/cppforlife/knctl/pkg/knctl/cmd/core"
+	"github.com/spf13/cobra"
+)
+
+type ScheduleFlags struct {
+	Crons     []string
+	MinScales []int
+
+	KubectlImage string
+}
+
+func (s *ScheduleFlags) Set(cmd *cobra.Command, flagsFactory cmdcore.FlagsFactory) {
+	cmd.Flags().StringArrayVar(&s.Crons, "cron", nil, "Set cron schedule (e.g. '0 8 * * 1-5') (can be specified multiple times; paired with --min-scale in order)")
+	cmd.Flags().IntSliceVar(&s.MinScales, "min-scale", nil, "Set minimum number of containers for preceding cron schedule (0 removes minimum) (can be specified multiple times)")
+
+	cmd.Flags().StringVar(&s.KubectlImage, "kubectl-image", "bitnami/kubectl:latest", "Set image used by cron jobs to update autoscaling rules")
+}
+
+func (s *ScheduleFlags) Entries() ([]ScheduleEntry, error) {
+	if len(s.Crons) == 0 {
+		return nil, fmt.Errorf("Expected at least one cron schedule to be specified")
+	}
+
+	if len(s.Crons) != len(s.MinScales) {
+		return nil, fmt.Errorf("Expected each cron schedule to have a matching min scale (got %d crons and %d min scales)",
+			len(s.Crons), len(s.MinScales))
+	}
+
+	var entries []ScheduleEntry
+
+	for i, cron := range s.Crons {
+		if len(strings.Fields(cron)) != 5 {
+			return nil, fmt.Errorf("Expected cron schedule '%s' to have 5 fields (minute hour day-of-month month day-of-week)", cron)
+		}
+
+		if s.MinScales[i] < 0 {
+			return nil, fmt.Errorf("Expected min scale for cron schedule '%s' to be non-negative", cron)
+		}
+
+		entries = append(entries, ScheduleEntry{Cron: cron, MinScale: s.MinScales[i]})
+	}
+
+	return entries, nil
+}
@@ -0,0 +1,144 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scale_test
+
+import (
+	"testing"
+
+	"github.com/cppforlife/go-cli-ui/ui"
+	. "github.com/cppforlife/knctl/pkg/knctl/cmd"
+	cmdcore "github.com/cppforlife/knctl/pkg/knctl/cmd/core"
+	cmdflags "github.com/cppforlife/knctl/pkg/knctl/cmd/flags"
+	. "github.com/cppforlife/knctl/pkg/knctl/cmd/scale"
+	"github.com/cppforlife/knctl/pkg/knctl/testkit"
+	batchv1beta1 "k8s.io/api/batch/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestNewScheduleCmd_Ok(t *testing.T) {
+	realCmd := NewScheduleOptions(nil, cmdcore.NewDepsFactory())
+	cmd := NewTestCmd(t, NewScheduleCmd(realCmd, cmdcore.FlagsFactory{}))
+	cmd.ExpectBasicConfig()
+	cmd.Execute([]string{
+		"-n", "test-namespace",
+		"-s", "test-service",
+		"--cron", "0 8 * * 1-5",
+		"--min-scale", "3",
+		"--cron", "0 20 * * *",
+		"--min-scale", "0",
+	})
+	cmd.ExpectReachesExecution()
+
+	DeepEqual(t, realCmd.ServiceFlags,
+		cmdflags.ServiceFlags{cmdcore.NamespaceFlags{"test-namespace"}, "test-service"})
+
+	DeepEqual(t, realCmd.ScheduleFlags, ScheduleFlags{
+		Crons:        []string{"0 8 * * 1-5", "0 20 * * *"},
+		MinScales:    []int{3, 0},
+		KubectlImage: "bitnami/kubectl:latest",
+	})
+}
+
+func TestNewScheduleCmd_RequiredFlags(t *testing.T) {
+	realCmd := NewScheduleOptions(nil, cmdcore.NewDepsFactory())
+	cmd := NewTestCmd(t, NewScheduleCmd(realCmd, cmdcore.FlagsFactory{}))
+	cmd.Execute([]string{})
+	cmd.ExpectRequiredFlags([]string{"service"})
+}
+
+func TestScheduleFlags_Entries(t *testing.T) {
+	flags := ScheduleFlags{Crons: []string{"0 8 * * 1-5"}, MinScales: []int{3}}
+
+	entries, err := flags.Entries()
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	DeepEqual(t, entries, []ScheduleEntry{{Cron: "0 8 * * 1-5", MinScale: 3}})
+
+	invalidFlags := []ScheduleFlags{
+		{},
+		{Crons: []string{"0 8 * * 1-5"}},
+		{Crons: []string{"0 8 * *"}, MinScales: []int{3}},
+		{Crons: []string{"0 8 * * 1-5"}, MinScales: []int{-1}},
+	}
+
+	for _, flags := range invalidFlags {
+		_, err := flags.Entries()
+		if err == nil {
+			t.Fatalf("Expected error for flags: %#v", flags)
+		}
+	}
+}
+
+func TestScheduleOptions_CreatesAndPrunesCronJobs(t *testing.T) {
+	cluster := testkit.NewCluster(t, &batchv1beta1.CronJob{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "svc1-scale-5",
+			Namespace: "ns1",
+			Labels:    map[string]string{"scale.cli.knative.dev/service": "svc1"},
+		},
+	}, &batchv1beta1.CronJob{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "svc2-scale-0",
+			Namespace: "ns1",
+			Labels:    map[string]string{"scale.cli.knative.dev/service": "svc2"},
+		},
+	})
+
+	opts := NewScheduleOptions(ui.NewNoopUI(), cluster.DepsFactory())
+	opts.ServiceFlags = cmdflags.ServiceFlags{cmdcore.NamespaceFlags{"ns1"}, "svc1"}
+	opts.ScheduleFlags = ScheduleFlags{
+		Crons:        []string{"0 8 * * 1-5", "0 20 * * *"},
+		MinScales:    []int{3, 0},
+		KubectlImage: "kubectl",
+	}
+
+	for i := 0; i < 2; i++ {
+		err := opts.Run()
+		if err != nil {
+			t.Fatalf("Expected no error: %s", err)
+		}
+	}
+
+	cronJobs, err := cluster.CoreClient().BatchV1beta1().CronJobs("ns1").List(metav1.ListOptions{})
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	var names []string
+	for _, cronJob := range cronJobs.Items {
+		names = append(names, cronJob.Name)
+	}
+
+	DeepEqual(t, names, []string{"svc1-scale-0", "svc1-scale-1", "svc2-scale-0"})
+
+	cmd := cronJobs.Items[0].Spec.JobTemplate.Spec.Template.Spec.Containers[0].Command
+	DeepEqual(t, cmd, []string{
+		"kubectl", "annotate", "podautoscalers.autoscaling.internal.knative.dev",
+		"-n", "ns1", "-l", "serving.knative.dev/configuration=svc1",
+		"autoscaling.knative.dev/minScale=3", "--overwrite",
+	})
+
+	cmd = cronJobs.Items[1].Spec.JobTemplate.Spec.Template.Spec.Containers[0].Command
+	DeepEqual(t, cmd[len(cmd)-2], "autoscaling.knative.dev/minScale-")
+
+	_, err = cluster.CoreClient().RbacV1().RoleBindings("ns1").Get("knctl-scale-scheduler", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Expected role binding to be created: %s", err)
+	}
+}
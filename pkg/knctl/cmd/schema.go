@@ -0,0 +1,214 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/cppforlife/go-cli-ui/ui"
+	cmdbulk "github.com/cppforlife/knctl/pkg/knctl/cmd/bulk"
+	cmdcore "github.com/cppforlife/knctl/pkg/knctl/cmd/core"
+	cmdsvc "github.com/cppforlife/knctl/pkg/knctl/cmd/service"
+	"github.com/cppforlife/knctl/pkg/knctl/jsonschema"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+const (
+	// Bump when fields are renamed or removed from schema document
+	SchemaDocVersion = "1"
+
+	uiOutputSchemaName = "ui"
+)
+
+var (
+	// Commands that print JSON documents of their own (e.g. with '-o json')
+	// in addition to table output that every command can print as JSON via --json
+	documentOutputSchemas = map[string]struct {
+		Name string
+		Type interface{}
+	}{
+		"knctl status": {"service-status", cmdsvc.ServiceStatus{}},
+		"knctl bulk":   {"bulk-result", cmdbulk.Result{}},
+	}
+)
+
+type SchemaOptions struct {
+	ui ui.UI
+}
+
+func NewSchemaOptions(ui ui.UI) *SchemaOptions {
+	return &SchemaOptions{ui: ui}
+}
+
+func NewSchemaCmd(o *SchemaOptions, flagsFactory cmdcore.FlagsFactory) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "schema",
+		Short: "Print machine-readable description of all commands",
+		Long: `Print machine-readable description of all commands.
+
+Includes commands with their flags (name, type, default, whether it's required)
+and JSON Schema of output printed with --json or by commands that
+print JSON documents (e.g. 'knctl status -o json').`,
+		Example: `
+  # Print schema of all commands
+  knctl schema`,
+		Annotations: map[string]string{
+			cmdcore.SystemHelpGroup.Key: cmdcore.SystemHelpGroup.Value,
+		},
+		RunE: func(cmd *cobra.Command, _ []string) error { return o.Run(cmd.Root()) },
+	}
+	return cmd
+}
+
+type SchemaDoc struct {
+	SchemaVersion string `json:"schemaVersion"`
+	Version       string `json:"version"`
+
+	Commands      []CommandSchema              `json:"commands"`
+	OutputSchemas map[string]jsonschema.Schema `json:"outputSchemas"`
+}
+
+type CommandSchema struct {
+	Path    string   `json:"path"`
+	Use     string   `json:"use"`
+	Aliases []string `json:"aliases,omitempty"`
+	Group   string   `json:"group,omitempty"`
+
+	Short   string `json:"short"`
+	Long    string `json:"long,omitempty"`
+	Example string `json:"example,omitempty"`
+
+	// Commands with subcommands only list available subcommands
+	HasSubcommands bool   `json:"hasSubcommands"`
+	Hidden         bool   `json:"hidden,omitempty"`
+	Deprecated     string `json:"deprecated,omitempty"`
+
+	Flags          []FlagSchema `json:"flags"`
+	InheritedFlags []string     `json:"inheritedFlags"`
+
+	// Names of schemas in SchemaDoc.OutputSchemas
+	Outputs []string `json:"outputs"`
+}
+
+type FlagSchema struct {
+	Name      string `json:"name"`
+	Shorthand string `json:"shorthand,omitempty"`
+	Type      string `json:"type"`
+	Default   string `json:"default"`
+	Usage     string `json:"usage"`
+
+	Required   bool `json:"required"`
+	Persistent bool `json:"persistent"`
+	Hidden     bool `json:"hidden,omitempty"`
+}
+
+func (o *SchemaOptions) Run(rootCmd *cobra.Command) error {
+	bs, err := json.MarshalIndent(NewSchemaDoc(rootCmd), "", "  ")
+	if err != nil {
+		return fmt.Errorf("Marshaling schema: %s", err)
+	}
+
+	o.ui.PrintBlock(append(bs, '\n'))
+
+	return nil
+}
+
+func NewSchemaDoc(rootCmd *cobra.Command) SchemaDoc {
+	doc := SchemaDoc{
+		SchemaVersion: SchemaDocVersion,
+		Version:       Version,
+		OutputSchemas: map[string]jsonschema.Schema{
+			uiOutputSchemaName: jsonschema.For(ui.JSONUIResp{}),
+		},
+	}
+
+	var visit func(*cobra.Command)
+
+	visit = func(cmd *cobra.Command) {
+		if cmd.Name() == "help" {
+			return
+		}
+
+		cmdSchema := newCommandSchema(cmd)
+
+		if docOutput, found := documentOutputSchemas[cmd.CommandPath()]; found {
+			doc.OutputSchemas[docOutput.Name] = jsonschema.For(docOutput.Type)
+			cmdSchema.Outputs = append(cmdSchema.Outputs, docOutput.Name)
+		}
+
+		doc.Commands = append(doc.Commands, cmdSchema)
+
+		for _, subcmd := range cmd.Commands() {
+			visit(subcmd)
+		}
+	}
+
+	visit(rootCmd)
+
+	return doc
+}
+
+func newCommandSchema(cmd *cobra.Command) CommandSchema {
+	cmdSchema := CommandSchema{
+		Path:    cmd.CommandPath(),
+		Use:     cmd.Use,
+		Aliases: cmd.Aliases,
+		Group:   cmd.Annotations[cmdcore.SystemHelpGroup.Key],
+
+		Short:   cmd.Short,
+		Long:    cmd.Long,
+		Example: cmd.Example,
+
+		HasSubcommands: cmd.HasSubCommands(),
+		Hidden:         cmd.Hidden,
+		Deprecated:     cmd.Deprecated,
+
+		Flags:          []FlagSchema{},
+		InheritedFlags: []string{},
+		Outputs:        []string{uiOutputSchemaName},
+	}
+
+	cmd.LocalFlags().VisitAll(func(flag *pflag.Flag) {
+		if flag.Name == "help" {
+			return
+		}
+
+		_, required := flag.Annotations[cobra.BashCompOneRequiredFlag]
+
+		cmdSchema.Flags = append(cmdSchema.Flags, FlagSchema{
+			Name:      flag.Name,
+			Shorthand: flag.Shorthand,
+			Type:      flag.Value.Type(),
+			Default:   flag.DefValue,
+			Usage:     flag.Usage,
+
+			Required:   required,
+			Persistent: cmd.PersistentFlags().Lookup(flag.Name) != nil,
+			Hidden:     flag.Hidden,
+		})
+	})
+
+	cmd.InheritedFlags().VisitAll(func(flag *pflag.Flag) {
+		if flag.Name != "help" {
+			cmdSchema.InheritedFlags = append(cmdSchema.InheritedFlags, flag.Name)
+		}
+	})
+
+	return cmdSchema
+}
@@ -0,0 +1,86 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	. "github.com/cppforlife/knctl/pkg/knctl/cmd"
+	cmdcore "github.com/cppforlife/knctl/pkg/knctl/cmd/core"
+)
+
+func TestNewSchemaCmd_Ok(t *testing.T) {
+	realCmd := NewSchemaOptions(nil)
+	cmd := NewTestCmd(t, NewSchemaCmd(realCmd, cmdcore.FlagsFactory{}))
+	cmd.ExpectBasicConfig()
+	cmd.Execute([]string{})
+	cmd.ExpectReachesExecution()
+}
+
+func TestNewSchemaDoc(t *testing.T) {
+	doc := NewSchemaDoc(NewDefaultKnctlCmd(nil))
+
+	cmds := map[string]CommandSchema{}
+
+	for _, cmdSchema := range doc.Commands {
+		if _, found := cmds[cmdSchema.Path]; found {
+			t.Fatalf("Expected command '%s' to be described once", cmdSchema.Path)
+		}
+		cmds[cmdSchema.Path] = cmdSchema
+
+		for _, output := range cmdSchema.Outputs {
+			if _, found := doc.OutputSchemas[output]; !found {
+				t.Fatalf("Expected output schema '%s' of command '%s' to be included", output, cmdSchema.Path)
+			}
+		}
+	}
+
+	if _, found := cmds["knctl help"]; found {
+		t.Fatalf("Expected help command to be excluded")
+	}
+
+	rootFlag := findFlagSchema(t, cmds["knctl"], "json")
+	DeepEqual(t, rootFlag, FlagSchema{Name: "json", Type: "bool", Default: "false", Usage: "Output as JSON", Persistent: true})
+
+	listCmd := cmds["knctl service list"]
+	DeepEqual(t, listCmd.Group, "")
+	DeepEqual(t, listCmd.HasSubcommands, false)
+	DeepEqual(t, listCmd.Outputs, []string{"ui"})
+	DeepEqual(t, findFlagSchema(t, listCmd, "namespace").Shorthand, "n")
+
+	statusCmd := cmds["knctl status"]
+	DeepEqual(t, statusCmd.Group, "basic")
+	DeepEqual(t, statusCmd.Outputs, []string{"ui", "service-status"})
+	DeepEqual(t, findFlagSchema(t, statusCmd, "service").Required, true)
+	DeepEqual(t, findFlagSchema(t, statusCmd, "output").Default, "table")
+
+	_, err := json.Marshal(doc)
+	if err != nil {
+		t.Fatalf("Expected schema to be serializable: %s", err)
+	}
+}
+
+func findFlagSchema(t *testing.T, cmdSchema CommandSchema, name string) FlagSchema {
+	for _, flag := range cmdSchema.Flags {
+		if flag.Name == name {
+			return flag
+		}
+	}
+	t.Fatalf("Expected command '%s' to have flag '%s'", cmdSchema.Path, name)
+	return FlagSchema{}
+}
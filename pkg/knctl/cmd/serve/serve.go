@@ -0,0 +1,145 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package serve
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+
+	"github.com/cppforlife/go-cli-ui/ui"
+	cmdcore "github.com/cppforlife/knctl/pkg/knctl/cmd/core"
+	"github.com/spf13/cobra"
+)
+
+const (
+	apiTokenEnvVar = "KNCTL_API_TOKEN"
+)
+
+type ServeOptions struct {
+	ui            ui.UI
+	configFactory cmdcore.ConfigFactory
+	depsFactory   cmdcore.DepsFactory
+	cancelSignals cmdcore.CancelWatcher
+
+	Listen string
+}
+
+func NewServeOptions(ui ui.UI, configFactory cmdcore.ConfigFactory, depsFactory cmdcore.DepsFactory, cancelSignals cmdcore.CancelWatcher) *ServeOptions {
+	return &ServeOptions{ui: ui, configFactory: configFactory, depsFactory: depsFactory, cancelSignals: cancelSignals}
+}
+
+func NewServeCmd(o *ServeOptions, flagsFactory cmdcore.FlagsFactory) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "serve",
+		Short: "Serve knctl operations over local HTTP API",
+		Long: `Serve knctl operations over local HTTP API.
+
+Exposes following endpoints (JSON bodies correspond to options of Go API operations; see docs/go-api.md):
+
+  GET  /v1/services?namespace=ns1
+  POST /v1/deploy    (DeployOpts)
+  POST /v1/rollout   (RolloutOpts)
+  POST /v1/delete    (DeleteOpts)
+  GET  /v1/logs?namespace=ns1&service=svc1&follow=true&lines=10 (streamed as plain text)
+
+Requests must include 'Authorization: Bearer TOKEN' header. Token is read from $` + apiTokenEnvVar + `;
+if it's not set, random token is generated and printed on start.
+Only loopback addresses are allowed to be listened on.`,
+		Example: `
+  # Serve API on default address
+  knctl serve
+
+  # Serve API on port 9000
+  knctl serve --listen 127.0.0.1:9000`,
+		Annotations: map[string]string{
+			cmdcore.OtherHelpGroup.Key: cmdcore.OtherHelpGroup.Value,
+		},
+		RunE: func(_ *cobra.Command, _ []string) error { return o.Run() },
+	}
+	cmd.Flags().StringVar(&o.Listen, "listen", "127.0.0.1:8765", "Set address to listen on")
+	return cmd
+}
+
+func (o *ServeOptions) Run() error {
+	err := o.validateListen()
+	if err != nil {
+		return err
+	}
+
+	token := os.Getenv(apiTokenEnvVar)
+
+	if len(token) == 0 {
+		token, err = o.generateToken()
+		if err != nil {
+			return err
+		}
+
+		o.ui.PrintLinef("Generated API token: %s", token)
+	}
+
+	listener, err := net.Listen("tcp", o.Listen)
+	if err != nil {
+		return fmt.Errorf("Listening on '%s': %s", o.Listen, err)
+	}
+
+	server := &http.Server{Handler: NewServer(o.configFactory, o.depsFactory, token)}
+
+	o.cancelSignals.Watch(func() { server.Shutdown(context.Background()) })
+
+	o.ui.PrintLinef("Serving API on 'http://%s'", listener.Addr())
+
+	err = server.Serve(listener)
+	if err != nil && err != http.ErrServerClosed {
+		return fmt.Errorf("Serving API: %s", err)
+	}
+
+	return nil
+}
+
+func (o *ServeOptions) validateListen() error {
+	host, _, err := net.SplitHostPort(o.Listen)
+	if err != nil {
+		return fmt.Errorf("Expected listen address to be in format 'host:port': %s", err)
+	}
+
+	if host == "localhost" {
+		return nil
+	}
+
+	ip := net.ParseIP(host)
+	if ip == nil || !ip.IsLoopback() {
+		return fmt.Errorf("Expected listen address host '%s' to be a loopback address", host)
+	}
+
+	return nil
+}
+
+func (o *ServeOptions) generateToken() (string, error) {
+	bs := make([]byte, 32)
+
+	_, err := rand.Read(bs)
+	if err != nil {
+		return "", fmt.Errorf("Generating API token: %s", err)
+	}
+
+	return hex.EncodeToString(bs), nil
+}
@@ -0,0 +1,45 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package serve_test
+
+import (
+	"testing"
+
+	. "github.com/cppforlife/knctl/pkg/knctl/cmd"
+	cmdcore "github.com/cppforlife/knctl/pkg/knctl/cmd/core"
+	. "github.com/cppforlife/knctl/pkg/knctl/cmd/serve"
+)
+
+func TestNewServeCmd_Ok(t *testing.T) {
+	realCmd := NewServeOptions(nil, cmdcore.NewConfigFactoryImpl(), cmdcore.NewDepsFactory(), cmdcore.CancelSignals{})
+	cmd := NewTestCmd(t, NewServeCmd(realCmd, cmdcore.FlagsFactory{}))
+	cmd.ExpectBasicConfig()
+	cmd.Execute([]string{"--listen", "127.0.0.1:9000"})
+	cmd.ExpectReachesExecution()
+
+	DeepEqual(t, realCmd.Listen, "127.0.0.1:9000")
+}
+
+func TestNewServeCmd_NonLoopbackListen(t *testing.T) {
+	realCmd := NewServeOptions(nil, cmdcore.NewConfigFactoryImpl(), cmdcore.NewDepsFactory(), cmdcore.CancelSignals{})
+	realCmd.Listen = "0.0.0.0:9000"
+
+	err := realCmd.Run()
+	if err == nil || err.Error() != "Expected listen address host '0.0.0.0' to be a loopback address" {
+		t.Fatalf("Expected loopback error, but was: %v", err)
+	}
+}
@@ -0,0 +1,279 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package serve
+
+import (
+	"bytes"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/cppforlife/go-cli-ui/ui"
+	"github.com/cppforlife/knctl/pkg/knctl/api"
+	cmdcore "github.com/cppforlife/knctl/pkg/knctl/cmd/core"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	maxRequestBodySize = 1024 * 1024
+)
+
+type ServiceItem struct {
+	Name                      string `json:"name"`
+	Namespace                 string `json:"namespace"`
+	Domain                    string `json:"domain"`
+	LatestCreatedRevisionName string `json:"latestCreatedRevisionName"`
+	LatestReadyRevisionName   string `json:"latestReadyRevisionName"`
+	Ready                     bool   `json:"ready"`
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+type okResponse struct {
+	OK bool `json:"ok"`
+}
+
+// Server exposes knctl operations over HTTP. Each request runs operation
+// via Go API with its own output, and request context is used to cancel
+// operations (e.g. following logs) once client disconnects.
+type Server struct {
+	configFactory cmdcore.ConfigFactory
+	depsFactory   cmdcore.DepsFactory
+	token         string
+}
+
+var _ http.Handler = Server{}
+
+func NewServer(configFactory cmdcore.ConfigFactory, depsFactory cmdcore.DepsFactory, token string) Server {
+	return Server{configFactory, depsFactory, token}
+}
+
+func (s Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !s.authorized(r) {
+		s.writeErr(w, http.StatusUnauthorized, fmt.Errorf("Expected valid bearer token"))
+		return
+	}
+
+	type route struct {
+		method  string
+		handler func(http.ResponseWriter, *http.Request)
+	}
+
+	routes := map[string]route{
+		"/v1/services": {http.MethodGet, s.listServices},
+		"/v1/deploy":   {http.MethodPost, s.deploy},
+		"/v1/rollout":  {http.MethodPost, s.rollout},
+		"/v1/delete":   {http.MethodPost, s.delete},
+		"/v1/logs":     {http.MethodGet, s.logs},
+	}
+
+	rt, found := routes[r.URL.Path]
+	if !found {
+		s.writeErr(w, http.StatusNotFound, fmt.Errorf("Expected path to be one of known API paths"))
+		return
+	}
+
+	if r.Method != rt.method {
+		s.writeErr(w, http.StatusMethodNotAllowed, fmt.Errorf("Expected method to be '%s'", rt.method))
+		return
+	}
+
+	rt.handler(w, r)
+}
+
+func (s Server) authorized(r *http.Request) bool {
+	const prefix = "Bearer "
+
+	header := r.Header.Get("Authorization")
+	if !strings.HasPrefix(header, prefix) {
+		return false
+	}
+
+	return subtle.ConstantTimeCompare([]byte(strings.TrimPrefix(header, prefix)), []byte(s.token)) == 1
+}
+
+func (s Server) listServices(w http.ResponseWriter, r *http.Request) {
+	servingClient, err := s.depsFactory.ServingClient()
+	if err != nil {
+		s.writeErr(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	namespace, err := s.namespace(r.URL.Query().Get("namespace"))
+	if err != nil {
+		s.writeErr(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	services, err := servingClient.ServingV1alpha1().Services(namespace).List(metav1.ListOptions{})
+	if err != nil {
+		s.writeErr(w, http.StatusUnprocessableEntity, fmt.Errorf("Listing services: %s", err))
+		return
+	}
+
+	items := []ServiceItem{}
+
+	for _, svc := range services.Items {
+		items = append(items, ServiceItem{
+			Name:                      svc.Name,
+			Namespace:                 svc.Namespace,
+			Domain:                    svc.Status.Domain,
+			LatestCreatedRevisionName: svc.Status.LatestCreatedRevisionName,
+			LatestReadyRevisionName:   svc.Status.LatestReadyRevisionName,
+			Ready:                     svc.Status.IsReady(),
+		})
+	}
+
+	s.writeJSON(w, http.StatusOK, items)
+}
+
+func (s Server) deploy(w http.ResponseWriter, r *http.Request) {
+	var opts api.DeployOpts
+
+	if s.decodeBody(w, r, &opts) {
+		s.writeOpResult(w, api.NewDeploy(s.client(ui.NewNoopUI()), opts).Run(r.Context()))
+	}
+}
+
+func (s Server) rollout(w http.ResponseWriter, r *http.Request) {
+	var opts api.RolloutOpts
+
+	if s.decodeBody(w, r, &opts) {
+		s.writeOpResult(w, api.NewRollout(s.client(ui.NewNoopUI()), opts).Run(r.Context()))
+	}
+}
+
+func (s Server) delete(w http.ResponseWriter, r *http.Request) {
+	var opts api.DeleteOpts
+
+	if s.decodeBody(w, r, &opts) {
+		s.writeOpResult(w, api.NewDelete(s.client(ui.NewNoopUI()), opts).Run(r.Context()))
+	}
+}
+
+// logs streams plain text logs; errors that happen after
+// streaming started are appended to the stream since
+// response status has already been sent
+func (s Server) logs(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	opts := api.LogsOpts{
+		Namespace: query.Get("namespace"),
+		Service:   query.Get("service"),
+		Follow:    query.Get("follow") == "true",
+	}
+
+	if lines := query.Get("lines"); len(lines) > 0 {
+		var err error
+
+		opts.Lines, err = strconv.ParseInt(lines, 10, 64)
+		if err != nil {
+			s.writeErr(w, http.StatusBadRequest, fmt.Errorf("Expected lines to be an integer"))
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+
+	writer := flushingWriter{w}
+	logsUI := ui.NewWriterUI(writer, writer, ui.NewNoopLogger())
+
+	err := api.NewLogs(s.client(logsUI), opts).Run(r.Context())
+	if err != nil {
+		fmt.Fprintf(writer, "Error: %s\n", err)
+	}
+}
+
+func (s Server) client(output ui.UI) *api.Client {
+	return api.NewClientWithFactories(output, s.configFactory, s.depsFactory)
+}
+
+func (s Server) namespace(name string) (string, error) {
+	if len(name) > 0 {
+		return name, nil
+	}
+
+	name, err := s.configFactory.DefaultNamespace()
+	if err != nil {
+		return "", fmt.Errorf("Determining default namespace: %s", err)
+	}
+
+	return name, nil
+}
+
+func (s Server) decodeBody(w http.ResponseWriter, r *http.Request, val interface{}) bool {
+	bs, err := ioutil.ReadAll(io.LimitReader(r.Body, maxRequestBodySize))
+	if err != nil {
+		s.writeErr(w, http.StatusBadRequest, fmt.Errorf("Reading request body: %s", err))
+		return false
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(bs))
+	decoder.DisallowUnknownFields()
+
+	err = decoder.Decode(val)
+	if err != nil {
+		s.writeErr(w, http.StatusBadRequest, fmt.Errorf("Unmarshaling request body: %s", err))
+		return false
+	}
+
+	return true
+}
+
+func (s Server) writeOpResult(w http.ResponseWriter, err error) {
+	if err != nil {
+		s.writeErr(w, http.StatusUnprocessableEntity, err)
+		return
+	}
+	s.writeJSON(w, http.StatusOK, okResponse{OK: true})
+}
+
+func (s Server) writeErr(w http.ResponseWriter, status int, err error) {
+	s.writeJSON(w, status, errorResponse{Error: err.Error()})
+}
+
+func (Server) writeJSON(w http.ResponseWriter, status int, val interface{}) {
+	bs, err := json.Marshal(val)
+	if err != nil {
+		status = http.StatusInternalServerError
+		bs = []byte(`{"error":"Marshaling response"}`)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	w.Write(append(bs, '\n'))
+}
+
+type flushingWriter struct {
+	w http.ResponseWriter
+}
+
+func (w flushingWriter) Write(data []byte) (int, error) {
+	n, err := w.w.Write(data)
+	if flusher, ok := w.w.(http.Flusher); ok {
+		flusher.Flush()
+	}
+	return n, err
+}
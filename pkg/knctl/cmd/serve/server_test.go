@@ -0,0 +1,122 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package serve_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	. "github.com/cppforlife/knctl/pkg/knctl/cmd/serve"
+	"github.com/cppforlife/knctl/pkg/knctl/testkit"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestServer(t *testing.T) {
+	cluster := testkit.NewCluster(t,
+		testkit.NewService("ns1", "svc1").Build(),
+		testkit.NewService("ns1", "svc2").Build(),
+	)
+
+	server := httptest.NewServer(NewServer(cluster.ConfigFactory(), cluster.DepsFactory(), "token1"))
+	defer server.Close()
+
+	do := func(method, path, token, body string) (int, string) {
+		req, err := http.NewRequest(method, server.URL+path, strings.NewReader(body))
+		if err != nil {
+			t.Fatalf("Expected no error: %s", err)
+		}
+
+		req.Header.Set("Authorization", "Bearer "+token)
+
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("Expected no error: %s", err)
+		}
+
+		defer resp.Body.Close()
+
+		bs, err := ioutil.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatalf("Expected no error: %s", err)
+		}
+
+		return resp.StatusCode, strings.TrimSpace(string(bs))
+	}
+
+	t.Run("rejects invalid token", func(t *testing.T) {
+		status, body := do("GET", "/v1/services?namespace=ns1", "wrong", "")
+		if status != http.StatusUnauthorized || body != `{"error":"Expected valid bearer token"}` {
+			t.Fatalf("Expected unauthorized response, but was: %d %s", status, body)
+		}
+	})
+
+	t.Run("lists services", func(t *testing.T) {
+		status, body := do("GET", "/v1/services?namespace=ns1", "token1", "")
+		if status != http.StatusOK {
+			t.Fatalf("Expected OK response, but was: %d %s", status, body)
+		}
+
+		var items []ServiceItem
+
+		err := json.Unmarshal([]byte(body), &items)
+		if err != nil || len(items) != 2 || items[0].Name != "svc1" || items[1].Namespace != "ns1" {
+			t.Fatalf("Expected services to be listed, but was: %s", body)
+		}
+	})
+
+	t.Run("rejects wrong method and unknown path", func(t *testing.T) {
+		status, _ := do("GET", "/v1/deploy", "token1", "")
+		if status != http.StatusMethodNotAllowed {
+			t.Fatalf("Expected method not allowed, but was: %d", status)
+		}
+
+		status, _ = do("GET", "/v1/unknown", "token1", "")
+		if status != http.StatusNotFound {
+			t.Fatalf("Expected not found, but was: %d", status)
+		}
+	})
+
+	t.Run("rejects unknown params", func(t *testing.T) {
+		status, body := do("POST", "/v1/deploy", "token1", `{"service": "svc1", "img": "img1"}`)
+		if status != http.StatusBadRequest || body != `{"error":"Unmarshaling request body: json: unknown field \"img\""}` {
+			t.Fatalf("Expected bad request, but was: %d %s", status, body)
+		}
+	})
+
+	t.Run("reports operation errors", func(t *testing.T) {
+		status, body := do("POST", "/v1/deploy", "token1", `{"service": "svc1"}`)
+		if status != http.StatusUnprocessableEntity || body != `{"error":"Expected image to be non-empty"}` {
+			t.Fatalf("Expected unprocessable entity, but was: %d %s", status, body)
+		}
+	})
+
+	t.Run("deletes service", func(t *testing.T) {
+		status, body := do("POST", "/v1/delete", "token1", `{"namespace": "ns1", "service": "svc2"}`)
+		if status != http.StatusOK || body != `{"ok":true}` {
+			t.Fatalf("Expected OK response, but was: %d %s", status, body)
+		}
+
+		_, err := cluster.ServingClient().ServingV1alpha1().Services("ns1").Get("svc2", metav1.GetOptions{})
+		if err == nil {
+			t.Fatalf("Expected service to be deleted")
+		}
+	})
+}
@@ -17,10 +17,14 @@ limitations under the License.
 package service
 
 import (
+	"fmt"
+
 	"github.com/cppforlife/go-cli-ui/ui"
 	cmdcore "github.com/cppforlife/knctl/pkg/knctl/cmd/core"
 	cmdflags "github.com/cppforlife/knctl/pkg/knctl/cmd/flags"
 	ctlkube "github.com/cppforlife/knctl/pkg/knctl/kube"
+	ctlservice "github.com/cppforlife/knctl/pkg/knctl/service"
+	servingclientset "github.com/knative/serving/pkg/client/clientset/versioned"
 	"github.com/spf13/cobra"
 	"k8s.io/apimachinery/pkg/types"
 )
@@ -30,6 +34,7 @@ type AnnotateOptions struct {
 	depsFactory cmdcore.DepsFactory
 
 	ServiceFlags  cmdflags.ServiceFlags
+	SelectorFlags cmdflags.SelectorFlags
 	AnnotateFlags cmdflags.AnnotateFlags
 }
 
@@ -43,29 +48,59 @@ func NewAnnotateCmd(o *AnnotateOptions, flagsFactory cmdcore.FlagsFactory) *cobr
 		Short: "Annotate service",
 		Example: `
   # Annotate service 'srv1' in namespace 'ns1' with key and value
-  knctl service annotate -s srv1 -a key=value -n ns1`,
+  knctl service annotate -s srv1 -a key=value -n ns1
+
+  # Annotate all services with label 'team=payments' in namespace 'ns1'
+  knctl service annotate --selector team=payments -a key=value -n ns1`,
 		RunE: func(_ *cobra.Command, _ []string) error { return o.Run() },
 	}
-	o.ServiceFlags.Set(cmd, flagsFactory)
+	o.ServiceFlags.SetOptional(cmd, flagsFactory)
+	o.SelectorFlags.Set(cmd, flagsFactory)
 	o.AnnotateFlags.Set(cmd, flagsFactory)
 	return cmd
 }
 
 func (o *AnnotateOptions) Run() error {
-	servingClient, err := o.depsFactory.ServingClient()
+	err := o.SelectorFlags.ValidateWithName(o.ServiceFlags.Name, "service")
 	if err != nil {
 		return err
 	}
 
-	anns := ctlkube.NewAnnotations(func(type_ types.PatchType, data []byte) error {
-		_, err := servingClient.ServingV1alpha1().Services(o.ServiceFlags.NamespaceFlags.Name).Patch(o.ServiceFlags.Name, type_, data)
+	servingClient, err := o.depsFactory.ServingClient()
+	if err != nil {
 		return err
-	})
+	}
 
 	annotations, err := o.AnnotateFlags.AsMap()
 	if err != nil {
 		return err
 	}
 
+	if len(o.SelectorFlags.Selector) == 0 {
+		return o.annotate(servingClient, o.ServiceFlags.Name, annotations)
+	}
+
+	names, err := ctlservice.NewSelection(servingClient).Names(o.ServiceFlags.NamespaceFlags.Name, o.SelectorFlags.Selector)
+	if err != nil {
+		return err
+	}
+
+	results := cmdcore.NewBatchResults(fmt.Sprintf("Annotated services matching '%s'", o.SelectorFlags.Selector))
+
+	for _, name := range names {
+		results.Add(name, o.annotate(servingClient, name, annotations))
+	}
+
+	results.Print(o.ui)
+
+	return results.Err()
+}
+
+func (o *AnnotateOptions) annotate(servingClient servingclientset.Interface, name string, annotations map[string]interface{}) error {
+	anns := ctlkube.NewAnnotations(func(type_ types.PatchType, data []byte) error {
+		_, err := servingClient.ServingV1alpha1().Services(o.ServiceFlags.NamespaceFlags.Name).Patch(name, type_, data)
+		return err
+	})
+
 	return anns.Add(annotations)
 }
@@ -19,10 +19,13 @@ package service_test
 import (
 	"testing"
 
+	"github.com/cppforlife/go-cli-ui/ui"
 	. "github.com/cppforlife/knctl/pkg/knctl/cmd"
 	cmdcore "github.com/cppforlife/knctl/pkg/knctl/cmd/core"
 	cmdflags "github.com/cppforlife/knctl/pkg/knctl/cmd/flags"
 	. "github.com/cppforlife/knctl/pkg/knctl/cmd/service"
+	"github.com/cppforlife/knctl/pkg/knctl/testkit"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 func TestNewAnnotateCmd_Ok(t *testing.T) {
@@ -52,9 +55,49 @@ func TestNewAnnotateCmd_OkLongFlagNames(t *testing.T) {
 		cmdflags.ServiceFlags{cmdcore.NamespaceFlags{"test-namespace"}, "test-service"})
 }
 
-func TestNewAnnotateCmd_RequiredFlags(t *testing.T) {
+func TestNewAnnotateCmd_Selector(t *testing.T) {
 	realCmd := NewAnnotateOptions(nil, cmdcore.NewDepsFactory())
 	cmd := NewTestCmd(t, NewAnnotateCmd(realCmd, cmdcore.FlagsFactory{}))
-	cmd.Execute([]string{})
-	cmd.ExpectRequiredFlags([]string{"service"})
+	cmd.Execute([]string{"-n", "test-namespace", "--selector", "team=payments", "-a", "key=value"})
+	cmd.ExpectReachesExecution()
+
+	DeepEqual(t, realCmd.SelectorFlags, cmdflags.SelectorFlags{"team=payments"})
+}
+
+func TestAnnotateOptions_RequiresServiceOrSelector(t *testing.T) {
+	opts := NewAnnotateOptions(ui.NewNoopUI(), testkit.NewCluster(t).DepsFactory())
+	opts.ServiceFlags = cmdflags.ServiceFlags{cmdcore.NamespaceFlags{"ns1"}, ""}
+
+	err := opts.Run()
+	if err == nil || err.Error() != "Expected either --service or --selector to be specified" {
+		t.Fatalf("Expected service or selector error, but was: %v", err)
+	}
+}
+
+func TestAnnotateOptions_AnnotatesSelectedServices(t *testing.T) {
+	svc1 := testkit.NewService("ns1", "svc1").Build()
+	svc1.Labels = map[string]string{"team": "payments"}
+
+	svc2 := testkit.NewService("ns1", "svc2").Build()
+	svc2.Labels = map[string]string{"team": "payments"}
+
+	cluster := testkit.NewCluster(t, svc1, svc2, testkit.NewService("ns1", "svc3").Build())
+
+	opts := NewAnnotateOptions(ui.NewNoopUI(), cluster.DepsFactory())
+	opts.ServiceFlags = cmdflags.ServiceFlags{cmdcore.NamespaceFlags{"ns1"}, ""}
+	opts.SelectorFlags = cmdflags.SelectorFlags{"team=payments"}
+	opts.AnnotateFlags = cmdflags.AnnotateFlags{Annotations: []string{"key=value"}}
+
+	err := opts.Run()
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	for name, expectedVal := range map[string]string{"svc1": "value", "svc2": "value", "svc3": ""} {
+		service, err := cluster.ServingClient().ServingV1alpha1().Services("ns1").Get(name, metav1.GetOptions{})
+		if err != nil {
+			t.Fatalf("Expected no error: %s", err)
+		}
+		DeepEqual(t, service.Annotations["key"], expectedVal)
+	}
 }
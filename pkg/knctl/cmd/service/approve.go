@@ -0,0 +1,118 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"fmt"
+
+	"github.com/cppforlife/go-cli-ui/ui"
+	"github.com/cppforlife/knctl/pkg/knctl/approval"
+	cmdcore "github.com/cppforlife/knctl/pkg/knctl/cmd/core"
+	cmdflags "github.com/cppforlife/knctl/pkg/knctl/cmd/flags"
+	ctlhistory "github.com/cppforlife/knctl/pkg/knctl/history"
+	"github.com/spf13/cobra"
+)
+
+type ApproveOptions struct {
+	ui          ui.UI
+	depsFactory cmdcore.DepsFactory
+
+	ServiceFlags      cmdflags.ServiceFlags
+	AllowSelfApproval bool
+}
+
+func NewApproveOptions(ui ui.UI, depsFactory cmdcore.DepsFactory) *ApproveOptions {
+	return &ApproveOptions{ui: ui, depsFactory: depsFactory}
+}
+
+func NewApproveCmd(o *ApproveOptions, flagsFactory cmdcore.FlagsFactory) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "approve",
+		Short: "Approve pending deploy of service",
+		Long: `Approve pending deploy of service made with 'knctl deploy --require-approval'.
+
+All of service's traffic is shifted to the pending revision and service manages
+its route again (unless it was deployed with '--managed-route=false').
+Approval is recorded in history, hence it can be reverted via 'knctl undo'.
+
+By default deploy has to be approved by a different user than the one who
+deployed it. User names are taken from local OS user (same as in 'knctl history')
+and are not verified by Kubernetes, hence this check is advisory. Use RBAC
+to restrict who can update routes to enforce separation of duties.`,
+		Example: `
+  # Approve pending deploy of service 'svc1' in namespace 'ns1'
+  knctl approve -s svc1 -n ns1`,
+		Annotations: map[string]string{
+			cmdcore.BasicHelpGroup.Key: cmdcore.BasicHelpGroup.Value,
+		},
+		RunE: func(_ *cobra.Command, _ []string) error { return o.Run() },
+	}
+	o.ServiceFlags.Set(cmd, flagsFactory)
+	cmd.Flags().BoolVar(&o.AllowSelfApproval, "allow-self-approval", false, "Allow approving deploy made by the same user")
+	return cmd
+}
+
+func (o *ApproveOptions) Run() error {
+	servingClient, err := o.depsFactory.ServingClient()
+	if err != nil {
+		return err
+	}
+
+	coreClient, err := o.depsFactory.CoreClient()
+	if err != nil {
+		return err
+	}
+
+	namespace := o.ServiceFlags.NamespaceFlags.Name
+
+	pending, found, err := approval.NewApprovals(coreClient).Get(namespace, o.ServiceFlags.Name)
+	if err != nil {
+		return err
+	}
+
+	if !found {
+		return fmt.Errorf("Expected service '%s' to have pending approval", o.ServiceFlags.Name)
+	}
+
+	entry := ctlhistory.NewEntry(ctlhistory.OperationApprove, o.ServiceFlags.Name)
+
+	// Advisory only: user names are not verified identities
+	if entry.User == pending.RequestedBy && !o.AllowSelfApproval {
+		return fmt.Errorf("Expected deploy to be approved by a different user than '%s' who deployed it", pending.RequestedBy)
+	}
+
+	o.ui.PrintLinef("Shifting all traffic of service '%s' from revision '%s' to revision '%s' deployed by '%s' at %s",
+		pending.Service, pending.PreviousRevision, pending.Revision, pending.RequestedBy,
+		pending.RequestedAt.Format("2006-01-02 15:04:05 MST"))
+
+	err = o.ui.AskForConfirmation()
+	if err != nil {
+		return err
+	}
+
+	entry, err = NewServiceApproval(servingClient, coreClient).Approve(namespace, pending, entry)
+	if err != nil {
+		return err
+	}
+
+	err = ctlhistory.NewHistory(coreClient).Record(namespace, entry)
+	if err != nil {
+		o.ui.ErrorLinef("Warning: %s", err)
+	}
+
+	return nil
+}
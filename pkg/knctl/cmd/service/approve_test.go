@@ -0,0 +1,87 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/cppforlife/go-cli-ui/ui"
+	"github.com/cppforlife/knctl/pkg/knctl/approval"
+	. "github.com/cppforlife/knctl/pkg/knctl/cmd"
+	cmdcore "github.com/cppforlife/knctl/pkg/knctl/cmd/core"
+	cmdflags "github.com/cppforlife/knctl/pkg/knctl/cmd/flags"
+	. "github.com/cppforlife/knctl/pkg/knctl/cmd/service"
+	ctlhistory "github.com/cppforlife/knctl/pkg/knctl/history"
+	"github.com/cppforlife/knctl/pkg/knctl/testkit"
+)
+
+func TestNewApproveCmd_Ok(t *testing.T) {
+	realCmd := NewApproveOptions(nil, cmdcore.NewDepsFactory())
+	cmd := NewTestCmd(t, NewApproveCmd(realCmd, cmdcore.FlagsFactory{}))
+	cmd.ExpectBasicConfig()
+	cmd.Execute([]string{"-n", "test-namespace", "-s", "test-service", "--allow-self-approval"})
+	cmd.ExpectReachesExecution()
+
+	DeepEqual(t, realCmd.ServiceFlags, cmdflags.ServiceFlags{cmdcore.NamespaceFlags{"test-namespace"}, "test-service"})
+	DeepEqual(t, realCmd.AllowSelfApproval, true)
+}
+
+func TestNewApproveCmd_RequiredFlags(t *testing.T) {
+	realCmd := NewApproveOptions(nil, cmdcore.NewDepsFactory())
+	cmd := NewTestCmd(t, NewApproveCmd(realCmd, cmdcore.FlagsFactory{}))
+	cmd.Execute([]string{})
+	cmd.ExpectRequiredFlags([]string{"service"})
+}
+
+func TestApproveOptions_RejectsSelfApproval(t *testing.T) {
+	cluster := testkit.NewCluster(t,
+		testkit.NewRevision("ns1", "svc1", "svc1-00002").Ready().Build(),
+		testkit.NewRoute("ns1", "svc1").Traffic("svc1-00001", 100).Build(),
+	)
+
+	currentUser := ctlhistory.NewEntry(ctlhistory.OperationDeploy, "svc1").User
+
+	err := approval.NewApprovals(cluster.CoreClient()).Request("ns1", approval.Pending{
+		Service: "svc1", Revision: "svc1-00002", PreviousRevision: "svc1-00001", RequestedBy: currentUser})
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	opts := NewApproveOptions(ui.NewNonInteractiveUI(ui.NewNoopUI()), cluster.DepsFactory())
+	opts.ServiceFlags = cmdflags.ServiceFlags{cmdcore.NamespaceFlags{"ns1"}, "svc1"}
+
+	err = opts.Run()
+	if err == nil || !strings.Contains(err.Error(), "approved by a different user") {
+		t.Fatalf("Expected self approval to be rejected, but was: %v", err)
+	}
+
+	opts.AllowSelfApproval = true
+
+	err = opts.Run()
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	entry, _, err := ctlhistory.NewHistory(cluster.CoreClient()).Last("ns1", "svc1")
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	DeepEqual(t, entry.Operation, ctlhistory.OperationApprove)
+	DeepEqual(t, entry.Revision, "svc1-00002")
+}
@@ -0,0 +1,117 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"fmt"
+
+	"github.com/cppforlife/go-cli-ui/ui"
+	cmdcore "github.com/cppforlife/knctl/pkg/knctl/cmd/core"
+	cmdflags "github.com/cppforlife/knctl/pkg/knctl/cmd/flags"
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type CopyOptions struct {
+	ui            ui.UI
+	configFactory cmdcore.ConfigFactory
+	depsFactory   cmdcore.DepsFactory
+
+	ServiceFlags     cmdflags.ServiceFlags
+	ToContext        string
+	ToNamespace      string
+	CopySecretValues bool
+}
+
+func NewCopyOptions(ui ui.UI, configFactory cmdcore.ConfigFactory, depsFactory cmdcore.DepsFactory) *CopyOptions {
+	return &CopyOptions{ui: ui, configFactory: configFactory, depsFactory: depsFactory}
+}
+
+func NewCopyCmd(o *CopyOptions, flagsFactory cmdcore.FlagsFactory) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "copy",
+		Short: "Copy service to another cluster",
+		Long: `Copy service to another cluster.
+
+Recreates service, its service account and basic auth and image pull secrets
+referenced by the service account in the destination cluster.
+Copied service runs latest ready revision's image (by digest) without its build.
+Passwords are asked for interactively unless --copy-secret-values flag is provided.
+Secrets and service account that already exist in the destination are not modified
+(except for adding missing secret references to the service account).`,
+		Example: `
+  # Copy service 'svc1' in namespace 'ns1' to cluster in context 'other'
+  knctl service copy -s svc1 -n ns1 --to-context other
+
+  # Copy service 'svc1' to namespace 'ns2' in cluster in context 'other' copying secret values as is
+  knctl service copy -s svc1 -n ns1 --to-context other --to-namespace ns2 --copy-secret-values`,
+		RunE: func(_ *cobra.Command, _ []string) error { return o.Run() },
+	}
+	o.ServiceFlags.Set(cmd, flagsFactory)
+	cmd.Flags().StringVar(&o.ToContext, "to-context", "", "Set kubeconfig context of destination cluster")
+	cmd.Flags().StringVar(&o.ToNamespace, "to-namespace", "", "Set destination namespace (default is source namespace)")
+	cmd.Flags().BoolVar(&o.CopySecretValues, "copy-secret-values", false, "Copy secret values as is instead of asking for passwords")
+	cmd.MarkFlagRequired("to-context")
+	return cmd
+}
+
+func (o *CopyOptions) Run() error {
+	toNamespace := o.ToNamespace
+	if len(toNamespace) == 0 {
+		toNamespace = o.ServiceFlags.NamespaceFlags.Name
+	}
+
+	srcServingClient, err := o.depsFactory.ServingClient()
+	if err != nil {
+		return err
+	}
+
+	srcCoreClient, err := o.depsFactory.CoreClient()
+	if err != nil {
+		return err
+	}
+
+	dstDepsFactory := cmdcore.NewDepsFactoryImpl(o.configFactory.ForContext(o.ToContext))
+
+	dstServingClient, err := dstDepsFactory.ServingClient()
+	if err != nil {
+		return err
+	}
+
+	dstCoreClient, err := dstDepsFactory.CoreClient()
+	if err != nil {
+		return err
+	}
+
+	service, err := srcServingClient.ServingV1alpha1().Services(o.ServiceFlags.NamespaceFlags.Name).Get(o.ServiceFlags.Name, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("Getting service: %s", err)
+	}
+
+	serviceCopy := ServiceCopy{
+		SrcServingClient: srcServingClient,
+		SrcCoreClient:    srcCoreClient,
+		DstCoreClient:    dstCoreClient,
+		DstServingClient: dstServingClient,
+		UI:               o.ui,
+
+		ToNamespace:      toNamespace,
+		CopySecretValues: o.CopySecretValues,
+	}
+
+	return serviceCopy.Copy(*service)
+}
@@ -0,0 +1,66 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service_test
+
+import (
+	"testing"
+
+	. "github.com/cppforlife/knctl/pkg/knctl/cmd"
+	cmdcore "github.com/cppforlife/knctl/pkg/knctl/cmd/core"
+	. "github.com/cppforlife/knctl/pkg/knctl/cmd/service"
+)
+
+func TestNewCopyCmd_Ok(t *testing.T) {
+	realCmd := NewCopyOptions(nil, cmdcore.NewConfigFactoryImpl(), cmdcore.NewDepsFactory())
+	cmd := NewTestCmd(t, NewCopyCmd(realCmd, cmdcore.FlagsFactory{}))
+	cmd.ExpectBasicConfig()
+	cmd.Execute([]string{
+		"-s", "test-service",
+		"-n", "test-namespace",
+		"--to-context", "other",
+		"--to-namespace", "other-namespace",
+		"--copy-secret-values",
+	})
+	cmd.ExpectReachesExecution()
+
+	DeepEqual(t, realCmd.ServiceFlags.Name, "test-service")
+	DeepEqual(t, realCmd.ServiceFlags.NamespaceFlags.Name, "test-namespace")
+	DeepEqual(t, realCmd.ToContext, "other")
+	DeepEqual(t, realCmd.ToNamespace, "other-namespace")
+	DeepEqual(t, realCmd.CopySecretValues, true)
+}
+
+func TestNewCopyCmd_OkMinimum(t *testing.T) {
+	realCmd := NewCopyOptions(nil, cmdcore.NewConfigFactoryImpl(), cmdcore.NewDepsFactory())
+	cmd := NewTestCmd(t, NewCopyCmd(realCmd, cmdcore.FlagsFactory{}))
+	cmd.Execute([]string{
+		"--service", "test-service",
+		"--namespace", "test-namespace",
+		"--to-context", "other",
+	})
+	cmd.ExpectReachesExecution()
+
+	DeepEqual(t, realCmd.ToNamespace, "")
+	DeepEqual(t, realCmd.CopySecretValues, false)
+}
+
+func TestNewCopyCmd_RequiredFlags(t *testing.T) {
+	realCmd := NewCopyOptions(nil, cmdcore.NewConfigFactoryImpl(), cmdcore.NewDepsFactory())
+	cmd := NewTestCmd(t, NewCopyCmd(realCmd, cmdcore.FlagsFactory{}))
+	cmd.Execute([]string{})
+	cmd.ExpectRequiredFlags([]string{"service", "to-context"})
+}
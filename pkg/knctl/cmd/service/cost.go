@@ -0,0 +1,193 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/cppforlife/go-cli-ui/ui"
+	uitable "github.com/cppforlife/go-cli-ui/ui/table"
+	cmdcore "github.com/cppforlife/knctl/pkg/knctl/cmd/core"
+	cmdflags "github.com/cppforlife/knctl/pkg/knctl/cmd/flags"
+	ctlkube "github.com/cppforlife/knctl/pkg/knctl/kube"
+	"github.com/knative/serving/pkg/apis/serving"
+	servingclientset "github.com/knative/serving/pkg/client/clientset/versioned"
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const (
+	monthlyCostAnnKey = "cost.cli.knative.dev/monthly-estimate"
+)
+
+type CostOptions struct {
+	ui          ui.UI
+	depsFactory cmdcore.DepsFactory
+
+	ServiceFlags cmdflags.ServiceFlags
+
+	PriceCPU float64
+	PriceMem float64
+
+	SampleDuration time.Duration
+	SampleInterval time.Duration
+
+	Annotate bool
+}
+
+func NewCostOptions(ui ui.UI, depsFactory cmdcore.DepsFactory) *CostOptions {
+	return &CostOptions{ui: ui, depsFactory: depsFactory}
+}
+
+func NewCostCmd(o *CostOptions, flagsFactory cmdcore.FlagsFactory) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "cost",
+		Short: "Estimate monthly cost of service",
+		Long: `Estimate monthly cost of service's revisions.
+
+Estimate multiplies average number of running pods (observed over sampling window)
+by pods' resource requests and given prices. Containers without resource requests
+do not contribute to the estimate.`,
+		Example: `
+  # Estimate cost of service 'srv1' in namespace 'ns1' based on current pods
+  knctl cost -s srv1 --price-cpu 0.03 --price-mem 0.004 -n ns1
+
+  # Estimate cost of service 'srv1' sampling pods for 10 minutes and save estimates on revisions
+  knctl cost -s srv1 --price-cpu 0.03 --price-mem 0.004 --sample-duration 10m --annotate -n ns1`,
+		Annotations: map[string]string{
+			cmdcore.BasicHelpGroup.Key: cmdcore.BasicHelpGroup.Value,
+		},
+		RunE: func(_ *cobra.Command, _ []string) error { return o.Run() },
+	}
+	o.ServiceFlags.Set(cmd, flagsFactory)
+	cmd.Flags().Float64Var(&o.PriceCPU, "price-cpu", 0, "Set price of one vCPU per hour")
+	cmd.Flags().Float64Var(&o.PriceMem, "price-mem", 0, "Set price of one GiB of memory per hour")
+	cmd.Flags().DurationVar(&o.SampleDuration, "sample-duration", 0, "Set duration of sampling window (single sample if zero)")
+	cmd.Flags().DurationVar(&o.SampleInterval, "sample-interval", 10*time.Second, "Set interval between samples")
+	cmd.Flags().BoolVar(&o.Annotate, "annotate", false, "Save monthly estimate as annotation on each revision")
+	return cmd
+}
+
+func (o *CostOptions) Run() error {
+	if o.PriceCPU < 0 || o.PriceMem < 0 {
+		return fmt.Errorf("Expected prices to be non-negative")
+	}
+
+	if o.SampleInterval <= 0 {
+		return fmt.Errorf("Expected sample interval to be positive")
+	}
+
+	coreClient, err := o.depsFactory.CoreClient()
+	if err != nil {
+		return err
+	}
+
+	listOpts := metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("%s=%s", serving.ServiceLabelKey, o.ServiceFlags.Name),
+	}
+
+	cost := NewServiceCost()
+	sampleUntil := time.Now().Add(o.SampleDuration)
+
+	for {
+		pods, err := coreClient.CoreV1().Pods(o.ServiceFlags.NamespaceFlags.Name).List(listOpts)
+		if err != nil {
+			return fmt.Errorf("Listing pods: %s", err)
+		}
+
+		cost.AddSample(pods.Items)
+
+		if !time.Now().Add(o.SampleInterval).Before(sampleUntil) {
+			break
+		}
+
+		time.Sleep(o.SampleInterval)
+	}
+
+	estimates := cost.Estimates(CostPrices{CPUHour: o.PriceCPU, MemGiBHour: o.PriceMem})
+
+	o.printTable(estimates)
+
+	if o.Annotate {
+		servingClient, err := o.depsFactory.ServingClient()
+		if err != nil {
+			return err
+		}
+
+		return o.annotateRevisions(estimates, servingClient)
+	}
+
+	return nil
+}
+
+func (o *CostOptions) annotateRevisions(estimates []RevisionCostEstimate, servingClient servingclientset.Interface) error {
+	revisions := servingClient.ServingV1alpha1().Revisions(o.ServiceFlags.NamespaceFlags.Name)
+
+	for _, estimate := range estimates {
+		revName := estimate.Revision
+
+		anns := ctlkube.NewAnnotations(func(type_ types.PatchType, data []byte) error {
+			_, err := revisions.Patch(revName, type_, data)
+			return err
+		})
+
+		err := anns.Add(map[string]interface{}{
+			monthlyCostAnnKey: fmt.Sprintf("%.2f", estimate.MonthlyCost),
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (o *CostOptions) printTable(estimates []RevisionCostEstimate) {
+	table := uitable.Table{
+		Title:   fmt.Sprintf("Estimated monthly cost of service '%s'", o.ServiceFlags.Name),
+		Content: "revisions",
+
+		Header: []uitable.Header{
+			uitable.NewHeader("Revision"),
+			uitable.NewHeader("Avg replicas"),
+			uitable.NewHeader("CPU request"),
+			uitable.NewHeader("Memory request"),
+			uitable.NewHeader("Monthly cost"),
+		},
+
+		SortBy: []uitable.ColumnSort{{Column: 0, Asc: true}},
+	}
+
+	var total float64
+
+	for _, estimate := range estimates {
+		total += estimate.MonthlyCost
+
+		table.Rows = append(table.Rows, []uitable.Value{
+			uitable.NewValueString(estimate.Revision),
+			uitable.NewValueString(fmt.Sprintf("%.2f", estimate.AvgReplicas)),
+			uitable.NewValueString(estimate.CPURequest.String()),
+			uitable.NewValueString(estimate.MemRequest.String()),
+			uitable.NewValueString(fmt.Sprintf("%.2f", estimate.MonthlyCost)),
+		})
+	}
+
+	o.ui.PrintTable(table)
+	o.ui.PrintLinef("Estimated monthly total: %.2f", total)
+}
@@ -0,0 +1,87 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cppforlife/go-cli-ui/ui"
+	. "github.com/cppforlife/knctl/pkg/knctl/cmd"
+	cmdcore "github.com/cppforlife/knctl/pkg/knctl/cmd/core"
+	cmdflags "github.com/cppforlife/knctl/pkg/knctl/cmd/flags"
+	. "github.com/cppforlife/knctl/pkg/knctl/cmd/service"
+	"github.com/cppforlife/knctl/pkg/knctl/testkit"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestNewCostCmd_Ok(t *testing.T) {
+	realCmd := NewCostOptions(nil, cmdcore.NewDepsFactory())
+	cmd := NewTestCmd(t, NewCostCmd(realCmd, cmdcore.FlagsFactory{}))
+	cmd.ExpectBasicConfig()
+	cmd.Execute([]string{
+		"-n", "test-namespace",
+		"-s", "test-service",
+		"--price-cpu", "0.03",
+		"--price-mem", "0.004",
+		"--sample-duration", "1m",
+		"--annotate",
+	})
+	cmd.ExpectReachesExecution()
+
+	DeepEqual(t, realCmd.ServiceFlags,
+		cmdflags.ServiceFlags{cmdcore.NamespaceFlags{"test-namespace"}, "test-service"})
+	DeepEqual(t, realCmd.PriceCPU, 0.03)
+	DeepEqual(t, realCmd.PriceMem, 0.004)
+	DeepEqual(t, realCmd.SampleDuration, time.Minute)
+	DeepEqual(t, realCmd.SampleInterval, 10*time.Second)
+	DeepEqual(t, realCmd.Annotate, true)
+}
+
+func TestNewCostCmd_RequiredFlags(t *testing.T) {
+	realCmd := NewCostOptions(nil, cmdcore.NewDepsFactory())
+	cmd := NewTestCmd(t, NewCostCmd(realCmd, cmdcore.FlagsFactory{}))
+	cmd.Execute([]string{})
+	cmd.ExpectRequiredFlags([]string{"service"})
+}
+
+func TestCostOptions_AnnotatesRevisions(t *testing.T) {
+	pod1 := newCostTestPod("pod1", "rev1", corev1.PodRunning)
+	pod2 := newCostTestPod("pod2", "rev1", corev1.PodRunning)
+
+	cluster := testkit.NewCluster(t, &pod1, &pod2, testkit.NewRevision("ns1", "svc1", "rev1").Build())
+
+	opts := NewCostOptions(ui.NewNoopUI(), cluster.DepsFactory())
+	opts.ServiceFlags = cmdflags.ServiceFlags{cmdcore.NamespaceFlags{"ns1"}, "svc1"}
+	opts.PriceCPU = 0.03
+	opts.PriceMem = 0.004
+	opts.SampleInterval = time.Second
+	opts.Annotate = true
+
+	err := opts.Run()
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	rev, err := cluster.ServingClient().ServingV1alpha1().Revisions("ns1").Get("rev1", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	DeepEqual(t, rev.Annotations["cost.cli.knative.dev/monthly-estimate"], "49.64")
+}
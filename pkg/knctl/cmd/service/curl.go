@@ -18,27 +18,41 @@ package service
 
 import (
 	"fmt"
+	"net/http"
+	"os"
 	"os/exec"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/cppforlife/go-cli-ui/ui"
+	uitable "github.com/cppforlife/go-cli-ui/ui/table"
+	"github.com/cppforlife/knctl/pkg/knctl/cache"
 	cmdcore "github.com/cppforlife/knctl/pkg/knctl/cmd/core"
 	cmdflags "github.com/cppforlife/knctl/pkg/knctl/cmd/flags"
+	ctlknconfig "github.com/cppforlife/knctl/pkg/knctl/knativeconfig"
+	"github.com/cppforlife/knctl/pkg/knctl/logger"
+	ctlservice "github.com/cppforlife/knctl/pkg/knctl/service"
 	"github.com/spf13/cobra"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 type CurlOptions struct {
-	ui          ui.UI
-	depsFactory cmdcore.DepsFactory
+	ui            ui.UI
+	configFactory cmdcore.ConfigFactory
+	depsFactory   cmdcore.DepsFactory
 
-	ServiceFlags cmdflags.ServiceFlags
-	CurlFlags    CurlFlags
-	Verbose      bool
+	ServiceFlags       cmdflags.ServiceFlags
+	CurlFlags          CurlFlags
+	InClusterCurlFlags InClusterCurlFlags
+	AssertionFlags     CurlAssertionFlags
+	ToRevision         string
+	Replay             string
+	Verbose            bool
 }
 
-func NewCurlOptions(ui ui.UI, depsFactory cmdcore.DepsFactory) *CurlOptions {
-	return &CurlOptions{ui: ui, depsFactory: depsFactory}
+func NewCurlOptions(ui ui.UI, configFactory cmdcore.ConfigFactory, depsFactory cmdcore.DepsFactory) *CurlOptions {
+	return &CurlOptions{ui: ui, configFactory: configFactory, depsFactory: depsFactory}
 }
 
 func NewCurlCmd(o *CurlOptions, flagsFactory cmdcore.FlagsFactory) *cobra.Command {
@@ -47,10 +61,42 @@ func NewCurlCmd(o *CurlOptions, flagsFactory cmdcore.FlagsFactory) *cobra.Comman
 		Short: "Curl service",
 		Long: `Send a HTTP request to the first ingress address with the Host header set to the service's domain.
 
-Requires 'curl' command installed on the system.`,
+Requires 'curl' command installed on the system.
+
+With --in-cluster (or --in-cluster-pod) request is sent from within the cluster
+to the service's internal domain, which is useful when ingress is not reachable
+from the local machine. Port flag is ignored in that case.
+
+With --to-revision request is routed to the revision via route's named traffic target
+(which may receive 0% of traffic), using Knative-Serving-Tag header when
+'tag-header-based-routing' feature is enabled, or target's subdomain otherwise.
+
+With --expect-* flags response is checked against given assertions and command
+fails unless all of them pass (useful for smoke tests in pipelines).
+
+With --replay recorded requests are sent one by one and command fails unless
+each response has recorded status (or, if status was not recorded, is not a server error).
+Requests are read from a HAR file (.har extension) or from JSON lines, for example:
+  {"method": "POST", "path": "/orders", "headers": {"Content-Type": "application/json"}, "body": "{}", "expectStatus": 201}`,
 		Example: `
   # Curl service 'svc1' in namespace 'ns1'
-  knctl curl -s svc1 -n ns1`,
+  knctl curl -s svc1 -n ns1
+
+  # Curl service 'svc1' from a short-lived pod in namespace 'ns1'
+  knctl curl -s svc1 --in-cluster -n ns1
+
+  # Curl service 'svc1' from existing pod 'debug' in namespace 'ns1'
+  knctl curl -s svc1 --in-cluster-pod debug -n ns1
+
+  # Curl revision tagged 'latest' of service 'svc1' without shifting traffic to it in namespace 'ns1'
+  knctl curl -s svc1 --to-revision svc1:latest -n ns1
+
+  # Curl service 'svc1' and fail unless it responds with JSON containing 'ok' in namespace 'ns1'
+  knctl curl -s svc1 -n ns1 --expect-status 200 --expect-body-contains ok \
+      --expect-header 'content-type: application/json'
+
+  # Replay recorded requests against revision tagged 'latest' of service 'svc1' in namespace 'ns1'
+  knctl curl -s svc1 -n ns1 --replay requests.har --to-revision svc1:latest`,
 		Annotations: map[string]string{
 			cmdcore.BasicHelpGroup.Key: cmdcore.BasicHelpGroup.Value,
 		},
@@ -58,16 +104,42 @@ Requires 'curl' command installed on the system.`,
 	}
 	o.ServiceFlags.Set(cmd, flagsFactory)
 	o.CurlFlags.Set(cmd, flagsFactory)
+	o.InClusterCurlFlags.Set(cmd, flagsFactory)
+	o.AssertionFlags.Set(cmd, flagsFactory)
+	cmd.Flags().StringVar(&o.ToRevision, "to-revision", "", "Send request to specific revision via its named traffic target (format: revision or service:tag)")
+	cmd.Flags().StringVar(&o.Replay, "replay", "", "Replay requests from HAR file (.har) or JSON lines file and report per-request result")
 	cmd.Flags().BoolVarP(&o.Verbose, "verbose", "v", false, "Makes curl verbose during the operation")
 	return cmd
 }
 
 func (o *CurlOptions) Run() error {
+	if o.AssertionFlags.IsProvided() && o.Verbose {
+		return fmt.Errorf("Expected --verbose to not be used with response assertions")
+	}
+
+	inCluster := o.InClusterCurlFlags.Enabled || len(o.InClusterCurlFlags.Pod) > 0
+
+	if len(o.Replay) > 0 {
+		if inCluster || o.AssertionFlags.IsProvided() {
+			return fmt.Errorf("Expected --replay to not be used with in-cluster or assertion flags")
+		}
+		return o.runReplay()
+	}
+
+	if inCluster {
+		return o.runInCluster()
+	}
+
 	domain, url, err := o.addr()
 	if err != nil {
 		return err
 	}
 
+	transportConfig, err := o.depsFactory.TransportConfig()
+	if err != nil {
+		return err
+	}
+
 	cmdName := "curl"
 	cmdArgs := []string{}
 
@@ -75,7 +147,15 @@ func (o *CurlOptions) Run() error {
 		cmdArgs = append(cmdArgs, "-vvv")
 	}
 
-	cmdArgs = append(cmdArgs, []string{"-sS", "-H", "Host: " + domain, url}...)
+	hostArgs, err := o.hostArgs(domain)
+	if err != nil {
+		return err
+	}
+
+	cmdArgs = append(cmdArgs, transportConfig.CurlArgs()...)
+	cmdArgs = append(cmdArgs, o.outputArgs()...)
+	cmdArgs = append(cmdArgs, hostArgs...)
+	cmdArgs = append(cmdArgs, url)
 
 	o.ui.PrintLinef("Running: %s '%s'", cmdName, strings.Join(cmdArgs, "' '"))
 
@@ -86,9 +166,254 @@ func (o *CurlOptions) Run() error {
 
 	o.ui.PrintBlock(out)
 
+	return o.checkAssertions(out)
+}
+
+func (o *CurlOptions) runReplay() error {
+	file, err := os.Open(o.Replay)
+	if err != nil {
+		return fmt.Errorf("Opening requests file: %s", err)
+	}
+
+	defer file.Close()
+
+	reqs, err := ReadReplayRequests(file, strings.HasSuffix(strings.ToLower(o.Replay), ".har"))
+	if err != nil {
+		return err
+	}
+
+	if len(reqs) == 0 {
+		return fmt.Errorf("Expected requests file to include at least one request")
+	}
+
+	domain, url, err := o.addr()
+	if err != nil {
+		return err
+	}
+
+	host := domain
+	header := http.Header{}
+
+	if len(o.ToRevision) > 0 {
+		pin, err := o.revisionPin()
+		if err != nil {
+			return err
+		}
+
+		o.ui.PrintLinef("Sending requests to revision '%s' via traffic target '%s'", pin.Revision, pin.TargetName)
+
+		host = pin.Host(domain)
+		header = pin.Header()
+	}
+
+	httpClient, err := o.depsFactory.HTTPClient()
+	if err != nil {
+		return err
+	}
+
+	replayer := NewReplayer(httpClient, url, host, header)
+
+	table := uitable.Table{
+		Title:   fmt.Sprintf("Replayed requests for service '%s'", o.ServiceFlags.Name),
+		Content: "requests",
+
+		Header: []uitable.Header{
+			uitable.NewHeader("#"),
+			uitable.NewHeader("Method"),
+			uitable.NewHeader("Path"),
+			uitable.NewHeader("Expected"),
+			uitable.NewHeader("Status"),
+			uitable.NewHeader("Duration"),
+			uitable.NewHeader("OK"),
+		},
+	}
+
+	var failed int
+
+	for i, req := range reqs {
+		result := replayer.Replay(req)
+
+		expected := "<500"
+		if req.ExpectStatus != 0 {
+			expected = strconv.Itoa(req.ExpectStatus)
+		}
+
+		status := strconv.Itoa(result.Status)
+		if result.Err != nil {
+			status = result.Err.Error()
+		}
+
+		if !result.Passed() {
+			failed++
+		}
+
+		table.Rows = append(table.Rows, []uitable.Value{
+			uitable.NewValueInt(i + 1),
+			uitable.NewValueString(req.Method),
+			uitable.NewValueString(req.Path),
+			uitable.NewValueString(expected),
+			uitable.NewValueString(status),
+			uitable.NewValueString(result.Duration.Round(time.Millisecond).String()),
+			uitable.ValueFmt{
+				V:     uitable.NewValueBool(result.Passed()),
+				Error: !result.Passed(),
+			},
+		})
+	}
+
+	o.ui.PrintTable(table)
+
+	if failed > 0 {
+		return fmt.Errorf("Expected all replayed requests to pass, but %d of %d failed", failed, len(reqs))
+	}
+
 	return nil
 }
 
+func (o *CurlOptions) outputArgs() []string {
+	// Include response status line and headers for assertions
+	if o.AssertionFlags.IsProvided() {
+		return []string{"-sS", "-i"}
+	}
+	return []string{"-sS"}
+}
+
+func (o *CurlOptions) checkAssertions(out []byte) error {
+	if !o.AssertionFlags.IsProvided() {
+		return nil
+	}
+
+	resp, err := ParseCurlResponse(out)
+	if err != nil {
+		return err
+	}
+
+	results, err := o.AssertionFlags.Check(resp)
+	if err != nil {
+		return err
+	}
+
+	var failed int
+
+	for _, result := range results {
+		if result.Passed {
+			o.ui.PrintLinef("PASS: %s", result.Description)
+		} else {
+			failed++
+			o.ui.PrintLinef("FAIL: %s (actual: '%s')", result.Description, result.Actual)
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("Expected response to pass all assertions, but %d of %d failed", failed, len(results))
+	}
+
+	return nil
+}
+
+func (o *CurlOptions) runInCluster() error {
+	servingClient, err := o.depsFactory.ServingClient()
+	if err != nil {
+		return err
+	}
+
+	service, err := servingClient.ServingV1alpha1().Services(o.ServiceFlags.NamespaceFlags.Name).Get(o.ServiceFlags.Name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+
+	url, err := ctlservice.NewServiceAddress(service, nil, cache.Cache{}, logger.NewNoopLogger(), false).InternalURL()
+	if err != nil {
+		return err
+	}
+
+	coreClient, err := o.depsFactory.CoreClient()
+	if err != nil {
+		return err
+	}
+
+	restConfig, err := o.configFactory.RESTConfig()
+	if err != nil {
+		return err
+	}
+
+	cmdArgs := o.outputArgs()
+
+	if o.Verbose {
+		cmdArgs = append(cmdArgs, "-vvv")
+	}
+
+	if len(o.ToRevision) > 0 {
+		pin, err := o.revisionPin()
+		if err != nil {
+			return err
+		}
+
+		if !pin.UseTagHeader {
+			return fmt.Errorf("Expected feature '%s' to be enabled when sending request to specific revision from within the cluster",
+				ctlknconfig.FeatureTagHeaderBasedRouting)
+		}
+
+		cmdArgs = append(cmdArgs, "-H", tagRoutingHeaderName+": "+pin.TargetName)
+	}
+
+	cmdArgs = append(cmdArgs, url)
+
+	inClusterCurl := NewInClusterCurl(coreClient, restConfig, o.ui)
+
+	var out []byte
+
+	if len(o.InClusterCurlFlags.Pod) > 0 {
+		o.ui.PrintLinef("Running in pod '%s': curl '%s'", o.InClusterCurlFlags.Pod, strings.Join(cmdArgs, "' '"))
+
+		out, err = inClusterCurl.RunInPod(o.ServiceFlags.NamespaceFlags.Name,
+			o.InClusterCurlFlags.Pod, o.InClusterCurlFlags.Container, cmdArgs)
+	} else {
+		o.ui.PrintLinef("Running in cluster: curl '%s'", strings.Join(cmdArgs, "' '"))
+
+		out, err = inClusterCurl.RunPod(o.ServiceFlags.NamespaceFlags.Name,
+			o.InClusterCurlFlags.Image, cmdArgs, o.InClusterCurlFlags.Timeout)
+	}
+
+	o.ui.PrintBlock(out)
+
+	if err != nil {
+		return err
+	}
+
+	return o.checkAssertions(out)
+}
+
+func (o *CurlOptions) hostArgs(domain string) ([]string, error) {
+	if len(o.ToRevision) == 0 {
+		return []string{"-H", "Host: " + domain}, nil
+	}
+
+	pin, err := o.revisionPin()
+	if err != nil {
+		return nil, err
+	}
+
+	o.ui.PrintLinef("Sending request to revision '%s' via traffic target '%s'", pin.Revision, pin.TargetName)
+
+	return pin.CurlArgs(domain), nil
+}
+
+func (o *CurlOptions) revisionPin() (RevisionPin, error) {
+	servingClient, err := o.depsFactory.ServingClient()
+	if err != nil {
+		return RevisionPin{}, err
+	}
+
+	coreClient, err := o.depsFactory.CoreClient()
+	if err != nil {
+		return RevisionPin{}, err
+	}
+
+	return NewRevisionPin(servingClient, coreClient, o.ServiceFlags.NamespaceFlags.Name,
+		o.ServiceFlags.Name, o.ToRevision)
+}
+
 func (o *CurlOptions) addr() (string, string, error) {
 	servingClient, err := o.depsFactory.ServingClient()
 	if err != nil {
@@ -105,7 +430,22 @@ func (o *CurlOptions) addr() (string, string, error) {
 		return "", "", err
 	}
 
-	serviceAddr := ServiceAddress{service, coreClient}
+	cache, err := o.depsFactory.Cache()
+	if err != nil {
+		return "", "", err
+	}
+
+	logger, err := o.depsFactory.Logger()
+	if err != nil {
+		return "", "", err
+	}
+
+	inCluster, err := o.depsFactory.InCluster()
+	if err != nil {
+		return "", "", err
+	}
+
+	serviceAddr := ctlservice.NewServiceAddress(service, coreClient, cache, logger, inCluster)
 
 	domain, err := serviceAddr.Domain()
 	if err != nil {
@@ -0,0 +1,149 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/textproto"
+	"strconv"
+	"strings"
+
+	cmdcore "github.com/cppforlife/knctl/pkg/knctl/cmd/core"
+	"github.com/spf13/cobra"
+)
+
+type CurlAssertionFlags struct {
+	ExpectStatus       int
+	ExpectBodyContains []string
+	ExpectHeaders      []string
+}
+
+func (s *CurlAssertionFlags) Set(cmd *cobra.Command, flagsFactory cmdcore.FlagsFactory) {
+	cmd.Flags().IntVar(&s.ExpectStatus, "expect-status", 0, "Fail unless response has given status code")
+	cmd.Flags().StringArrayVar(&s.ExpectBodyContains, "expect-body-contains", nil,
+		"Fail unless response body contains given string (can be specified multiple times)")
+	cmd.Flags().StringArrayVar(&s.ExpectHeaders, "expect-header", nil,
+		"Fail unless response header contains given value (format: 'name: value') (can be specified multiple times)")
+}
+
+func (s CurlAssertionFlags) IsProvided() bool {
+	return s.ExpectStatus != 0 || len(s.ExpectBodyContains) > 0 || len(s.ExpectHeaders) > 0
+}
+
+// CurlAssertionResult describes outcome of a single assertion
+type CurlAssertionResult struct {
+	Description string
+	Passed      bool
+	Actual      string
+}
+
+func (s CurlAssertionFlags) Check(resp CurlResponse) ([]CurlAssertionResult, error) {
+	var results []CurlAssertionResult
+
+	if s.ExpectStatus != 0 {
+		results = append(results, CurlAssertionResult{
+			Description: fmt.Sprintf("status is %d", s.ExpectStatus),
+			Passed:      resp.Status == s.ExpectStatus,
+			Actual:      strconv.Itoa(resp.Status),
+		})
+	}
+
+	for _, str := range s.ExpectBodyContains {
+		results = append(results, CurlAssertionResult{
+			Description: fmt.Sprintf("body contains '%s'", str),
+			Passed:      bytes.Contains(resp.Body, []byte(str)),
+			Actual:      fmt.Sprintf("%d bytes", len(resp.Body)),
+		})
+	}
+
+	for _, header := range s.ExpectHeaders {
+		pieces := strings.SplitN(header, ":", 2)
+		if len(pieces) != 2 || len(strings.TrimSpace(pieces[0])) == 0 {
+			return nil, fmt.Errorf("Expected header '%s' to be in format 'name: value'", header)
+		}
+
+		name := strings.TrimSpace(pieces[0])
+		val := strings.TrimSpace(pieces[1])
+		actualVals := resp.Headers[textproto.CanonicalMIMEHeaderKey(name)]
+
+		var passed bool
+
+		for _, actualVal := range actualVals {
+			if strings.Contains(strings.ToLower(actualVal), strings.ToLower(val)) {
+				passed = true
+			}
+		}
+
+		results = append(results, CurlAssertionResult{
+			Description: fmt.Sprintf("header '%s' contains '%s'", name, val),
+			Passed:      passed,
+			Actual:      strings.Join(actualVals, ", "),
+		})
+	}
+
+	return results, nil
+}
+
+type CurlResponse struct {
+	Status  int
+	Headers http.Header
+	Body    []byte
+}
+
+// ParseCurlResponse parses output of 'curl -i' skipping
+// informational responses (e.g. '100 Continue')
+func ParseCurlResponse(out []byte) (CurlResponse, error) {
+	reader := bufio.NewReader(bytes.NewReader(out))
+
+	for {
+		statusLine, err := reader.ReadString('\n')
+		if err != nil {
+			return CurlResponse{}, fmt.Errorf("Expected curl output to include response status line")
+		}
+
+		pieces := strings.Fields(statusLine)
+		if len(pieces) < 2 || !strings.HasPrefix(pieces[0], "HTTP/") {
+			return CurlResponse{}, fmt.Errorf("Expected curl output to start with response status line, but was '%s'",
+				strings.TrimSpace(statusLine))
+		}
+
+		status, err := strconv.Atoi(pieces[1])
+		if err != nil {
+			return CurlResponse{}, fmt.Errorf("Parsing response status '%s': %s", pieces[1], err)
+		}
+
+		headers, err := textproto.NewReader(reader).ReadMIMEHeader()
+		if err != nil {
+			return CurlResponse{}, fmt.Errorf("Parsing response headers: %s", err)
+		}
+
+		if status >= 100 && status < 200 {
+			continue
+		}
+
+		body, err := ioutil.ReadAll(reader)
+		if err != nil {
+			return CurlResponse{}, err
+		}
+
+		return CurlResponse{Status: status, Headers: http.Header(headers), Body: body}, nil
+	}
+}
@@ -0,0 +1,74 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service_test
+
+import (
+	"testing"
+
+	. "github.com/cppforlife/knctl/pkg/knctl/cmd"
+	. "github.com/cppforlife/knctl/pkg/knctl/cmd/service"
+)
+
+func TestParseCurlResponse(t *testing.T) {
+	out := "HTTP/1.1 100 Continue\r\n\r\n" +
+		"HTTP/1.1 201 Created\r\nContent-Type: application/json; charset=utf-8\r\nX-Id: 1\r\n\r\n" +
+		`{"status":"ok"}`
+
+	resp, err := ParseCurlResponse([]byte(out))
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	DeepEqual(t, resp.Status, 201)
+	DeepEqual(t, resp.Headers.Get("content-type"), "application/json; charset=utf-8")
+	DeepEqual(t, string(resp.Body), `{"status":"ok"}`)
+
+	_, err = ParseCurlResponse([]byte("curl: (6) Could not resolve host"))
+	if err == nil {
+		t.Fatalf("Expected error")
+	}
+}
+
+func TestCurlAssertionFlags_Check(t *testing.T) {
+	resp, err := ParseCurlResponse([]byte("HTTP/2 200\r\ncontent-type: application/json\r\n\r\nall ok"))
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	flags := CurlAssertionFlags{
+		ExpectStatus:       200,
+		ExpectBodyContains: []string{"ok", "missing"},
+		ExpectHeaders:      []string{"Content-Type: application/JSON", "x-id: 1"},
+	}
+
+	results, err := flags.Check(resp)
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	var passed []bool
+	for _, result := range results {
+		passed = append(passed, result.Passed)
+	}
+
+	DeepEqual(t, passed, []bool{true, true, false, true, false})
+
+	_, err = CurlAssertionFlags{ExpectHeaders: []string{"no-colon"}}.Check(resp)
+	if err == nil {
+		t.Fatalf("Expected error")
+	}
+}
@@ -17,6 +17,8 @@ limitations under the License.
 package service
 
 import (
+	"time"
+
 	cmdcore "github.com/cppforlife/knctl/pkg/knctl/cmd/core"
 	"github.com/spf13/cobra"
 )
@@ -28,3 +30,19 @@ type CurlFlags struct {
 func (s *CurlFlags) Set(cmd *cobra.Command, flagsFactory cmdcore.FlagsFactory) {
 	cmd.Flags().Int32VarP(&s.Port, "port", "p", 80, "Set port")
 }
+
+type InClusterCurlFlags struct {
+	Enabled   bool
+	Pod       string
+	Container string
+	Image     string
+	Timeout   time.Duration
+}
+
+func (s *InClusterCurlFlags) Set(cmd *cobra.Command, flagsFactory cmdcore.FlagsFactory) {
+	cmd.Flags().BoolVar(&s.Enabled, "in-cluster", false, "Send request from a short-lived pod within the cluster")
+	cmd.Flags().StringVar(&s.Pod, "in-cluster-pod", "", "Send request from an existing pod (e.g. debug pod) instead of a short-lived pod")
+	cmd.Flags().StringVar(&s.Container, "in-cluster-container", "", "Set container of existing pod to run curl in (default: first container)")
+	cmd.Flags().StringVar(&s.Image, "in-cluster-image", "curlimages/curl:latest", "Set image for short-lived pod")
+	cmd.Flags().DurationVar(&s.Timeout, "in-cluster-timeout", 1*time.Minute, "Set maximum time to wait for short-lived pod to complete")
+}
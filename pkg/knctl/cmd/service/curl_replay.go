@@ -0,0 +1,229 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+var (
+	// Headers that are set by HTTP client based on the request
+	replaySkippedHeaders = map[string]struct{}{
+		"Host": {}, "Content-Length": {}, "Connection": {}, "Transfer-Encoding": {}, "Accept-Encoding": {},
+	}
+)
+
+// ReplayRequest is a recorded request; ExpectStatus is zero when
+// response status was not recorded
+type ReplayRequest struct {
+	Method       string      `json:"method"`
+	Path         string      `json:"path"`
+	Headers      http.Header `json:"headers"`
+	Body         string      `json:"body"`
+	ExpectStatus int         `json:"expectStatus"`
+}
+
+// ReadReplayRequests reads HAR file (e.g. exported from browser dev tools)
+// or JSON lines with method, path, headers, body and expectStatus keys
+func ReadReplayRequests(reader io.Reader, isHAR bool) ([]ReplayRequest, error) {
+	if isHAR {
+		return readHARRequests(reader)
+	}
+
+	var reqs []ReplayRequest
+
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, 64*1024), 10*1024*1024)
+
+	for lineNum := 1; scanner.Scan(); lineNum++ {
+		line := strings.TrimSpace(scanner.Text())
+		if len(line) == 0 {
+			continue
+		}
+
+		var jsonReq struct {
+			ReplayRequest
+			Headers map[string]string `json:"headers"`
+		}
+
+		err := json.Unmarshal([]byte(line), &jsonReq)
+		if err != nil {
+			return nil, fmt.Errorf("Unmarshaling request on line %d: %s", lineNum, err)
+		}
+
+		req := jsonReq.ReplayRequest
+		req.Headers = http.Header{}
+
+		for k, v := range jsonReq.Headers {
+			req.Headers.Add(k, v)
+		}
+
+		reqs = append(reqs, req.withDefaults())
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("Reading requests: %s", err)
+	}
+
+	return reqs, nil
+}
+
+func readHARRequests(reader io.Reader) ([]ReplayRequest, error) {
+	var har struct {
+		Log struct {
+			Entries []struct {
+				Request struct {
+					Method   string
+					URL      string
+					Headers  []struct{ Name, Value string }
+					PostData *struct{ Text string }
+				}
+				Response struct {
+					Status int
+				}
+			}
+		}
+	}
+
+	err := json.NewDecoder(reader).Decode(&har)
+	if err != nil {
+		return nil, fmt.Errorf("Unmarshaling HAR: %s", err)
+	}
+
+	var reqs []ReplayRequest
+
+	for i, entry := range har.Log.Entries {
+		reqURL, err := url.Parse(entry.Request.URL)
+		if err != nil {
+			return nil, fmt.Errorf("Parsing URL of HAR entry %d: %s", i+1, err)
+		}
+
+		req := ReplayRequest{
+			Method:       entry.Request.Method,
+			Path:         reqURL.RequestURI(),
+			Headers:      http.Header{},
+			ExpectStatus: entry.Response.Status,
+		}
+
+		for _, header := range entry.Request.Headers {
+			// Skip HTTP/2 pseudo headers such as ':authority'
+			if !strings.HasPrefix(header.Name, ":") {
+				req.Headers.Add(header.Name, header.Value)
+			}
+		}
+
+		if entry.Request.PostData != nil {
+			req.Body = entry.Request.PostData.Text
+		}
+
+		reqs = append(reqs, req.withDefaults())
+	}
+
+	return reqs, nil
+}
+
+func (r ReplayRequest) withDefaults() ReplayRequest {
+	if len(r.Method) == 0 {
+		r.Method = http.MethodGet
+	}
+	if !strings.HasPrefix(r.Path, "/") {
+		r.Path = "/" + r.Path
+	}
+	return r
+}
+
+type ReplayResult struct {
+	Request  ReplayRequest
+	Status   int
+	Duration time.Duration
+	Err      error
+}
+
+// Passed checks response against recorded status; when status was not
+// recorded, request passes if service did not respond with server error
+func (r ReplayResult) Passed() bool {
+	switch {
+	case r.Err != nil:
+		return false
+	case r.Request.ExpectStatus != 0:
+		return r.Status == r.Request.ExpectStatus
+	default:
+		return r.Status < 500
+	}
+}
+
+// Replayer sends requests to service's address with given Host header
+type Replayer struct {
+	httpClient *http.Client
+	baseURL    string
+	host       string
+	headers    http.Header
+}
+
+func NewReplayer(httpClient *http.Client, baseURL, host string, headers http.Header) Replayer {
+	// Recorded redirects are expected to be replayed as is
+	noRedirectClient := *httpClient
+	noRedirectClient.CheckRedirect = func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }
+
+	return Replayer{&noRedirectClient, strings.TrimSuffix(baseURL, "/"), host, headers}
+}
+
+func (r Replayer) Replay(req ReplayRequest) ReplayResult {
+	result := ReplayResult{Request: req}
+
+	httpReq, err := http.NewRequest(req.Method, r.baseURL+req.Path, strings.NewReader(req.Body))
+	if err != nil {
+		result.Err = fmt.Errorf("Building request: %s", err)
+		return result
+	}
+
+	for k, vals := range req.Headers {
+		if _, found := replaySkippedHeaders[http.CanonicalHeaderKey(k)]; !found {
+			httpReq.Header[http.CanonicalHeaderKey(k)] = vals
+		}
+	}
+
+	for k, vals := range r.headers {
+		httpReq.Header[k] = vals
+	}
+
+	httpReq.Host = r.host
+
+	startTime := time.Now()
+
+	resp, err := r.httpClient.Do(httpReq)
+	if err != nil {
+		result.Err = fmt.Errorf("Sending request: %s", err)
+		return result
+	}
+
+	io.Copy(ioutil.Discard, resp.Body)
+	resp.Body.Close()
+
+	result.Status = resp.StatusCode
+	result.Duration = time.Since(startTime)
+
+	return result
+}
@@ -0,0 +1,110 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service_test
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	. "github.com/cppforlife/knctl/pkg/knctl/cmd"
+	. "github.com/cppforlife/knctl/pkg/knctl/cmd/service"
+)
+
+func TestReadReplayRequests_JSONLines(t *testing.T) {
+	input := `{"method":"POST","path":"orders","headers":{"Content-Type":"application/json"},"body":"{}","expectStatus":201}
+
+{"path":"/health"}`
+
+	reqs, err := ReadReplayRequests(strings.NewReader(input), false)
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	DeepEqual(t, reqs, []ReplayRequest{
+		{Method: "POST", Path: "/orders", Headers: http.Header{"Content-Type": []string{"application/json"}}, Body: "{}", ExpectStatus: 201},
+		{Method: "GET", Path: "/health", Headers: http.Header{}},
+	})
+
+	_, err = ReadReplayRequests(strings.NewReader("not-json"), false)
+	if err == nil || !strings.Contains(err.Error(), "line 1") {
+		t.Fatalf("Expected error with line number, but was: %v", err)
+	}
+}
+
+func TestReadReplayRequests_HAR(t *testing.T) {
+	input := `{"log":{"entries":[{
+		"request":{"method":"PUT","url":"https://app.example.com/items/1?force=true",
+			"headers":[{"name":":authority","value":"app.example.com"},{"name":"X-Id","value":"1"}],
+			"postData":{"text":"payload"}},
+		"response":{"status":204}
+	}]}}`
+
+	reqs, err := ReadReplayRequests(strings.NewReader(input), true)
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	DeepEqual(t, reqs, []ReplayRequest{
+		{Method: "PUT", Path: "/items/1?force=true", Headers: http.Header{"X-Id": []string{"1"}}, Body: "payload", ExpectStatus: 204},
+	})
+}
+
+func TestReplayer_Replay(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+
+		switch {
+		case r.Host != "svc1.ns1.example.com" || r.Header.Get("Knative-Serving-Tag") != "candidate":
+			w.WriteHeader(http.StatusBadRequest)
+		case r.URL.Path == "/redirect":
+			http.Redirect(w, r, "/other", http.StatusFound)
+		case r.Method == "POST" && string(body) == "payload":
+			w.WriteHeader(http.StatusCreated)
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+
+	defer server.Close()
+
+	replayer := NewReplayer(http.DefaultClient, server.URL, "svc1.ns1.example.com",
+		http.Header{"Knative-Serving-Tag": []string{"candidate"}})
+
+	examples := []struct {
+		Req    ReplayRequest
+		Status int
+		Passed bool
+	}{
+		{ReplayRequest{Method: "POST", Path: "/", Body: "payload", ExpectStatus: 201}, 201, true},
+		{ReplayRequest{Method: "GET", Path: "/redirect", ExpectStatus: 302}, 302, true},
+		{ReplayRequest{Method: "GET", Path: "/", ExpectStatus: 200}, 500, false},
+		{ReplayRequest{Method: "GET", Path: "/"}, 500, false},
+	}
+
+	for i, ex := range examples {
+		result := replayer.Replay(ex.Req)
+		if result.Err != nil {
+			t.Fatalf("Expected no error: %s", result.Err)
+		}
+		if result.Status != ex.Status || result.Passed() != ex.Passed {
+			t.Fatalf("Expected example %d to match: %#v", i, result)
+		}
+	}
+}
@@ -18,15 +18,17 @@ package service_test
 
 import (
 	"testing"
+	"time"
 
 	. "github.com/cppforlife/knctl/pkg/knctl/cmd"
 	cmdcore "github.com/cppforlife/knctl/pkg/knctl/cmd/core"
 	cmdflags "github.com/cppforlife/knctl/pkg/knctl/cmd/flags"
 	. "github.com/cppforlife/knctl/pkg/knctl/cmd/service"
+	corev1 "k8s.io/api/core/v1"
 )
 
 func TestNewCurlCmd_Ok(t *testing.T) {
-	realCmd := NewCurlOptions(nil, cmdcore.NewDepsFactory())
+	realCmd := NewCurlOptions(nil, cmdcore.NewConfigFactoryImpl(), cmdcore.NewDepsFactory())
 	cmd := NewTestCmd(t, NewCurlCmd(realCmd, cmdcore.FlagsFactory{}))
 	cmd.ExpectBasicConfig()
 	cmd.Execute([]string{
@@ -44,7 +46,7 @@ func TestNewCurlCmd_Ok(t *testing.T) {
 }
 
 func TestNewCurlCmd_OkLongFlagNames(t *testing.T) {
-	realCmd := NewCurlOptions(nil, cmdcore.NewDepsFactory())
+	realCmd := NewCurlOptions(nil, cmdcore.NewConfigFactoryImpl(), cmdcore.NewDepsFactory())
 	cmd := NewTestCmd(t, NewCurlCmd(realCmd, cmdcore.FlagsFactory{}))
 	cmd.Execute([]string{
 		"--namespace", "test-namespace",
@@ -61,7 +63,7 @@ func TestNewCurlCmd_OkLongFlagNames(t *testing.T) {
 }
 
 func TestNewCurlCmd_OkMinimum(t *testing.T) {
-	realCmd := NewCurlOptions(nil, cmdcore.NewDepsFactory())
+	realCmd := NewCurlOptions(nil, cmdcore.NewConfigFactoryImpl(), cmdcore.NewDepsFactory())
 	cmd := NewTestCmd(t, NewCurlCmd(realCmd, cmdcore.FlagsFactory{}))
 	cmd.Execute([]string{
 		"--namespace", "test-namespace",
@@ -76,8 +78,96 @@ func TestNewCurlCmd_OkMinimum(t *testing.T) {
 }
 
 func TestNewCurlCmd_RequiredFlags(t *testing.T) {
-	realCmd := NewCurlOptions(nil, cmdcore.NewDepsFactory())
+	realCmd := NewCurlOptions(nil, cmdcore.NewConfigFactoryImpl(), cmdcore.NewDepsFactory())
 	cmd := NewTestCmd(t, NewCurlCmd(realCmd, cmdcore.FlagsFactory{}))
 	cmd.Execute([]string{})
 	cmd.ExpectRequiredFlags([]string{"service"})
 }
+
+func TestNewCurlCmd_InCluster(t *testing.T) {
+	realCmd := NewCurlOptions(nil, cmdcore.NewConfigFactoryImpl(), cmdcore.NewDepsFactory())
+	cmd := NewTestCmd(t, NewCurlCmd(realCmd, cmdcore.FlagsFactory{}))
+	cmd.Execute([]string{
+		"--namespace", "test-namespace",
+		"--service", "test-service",
+		"--in-cluster",
+		"--in-cluster-pod", "debug",
+		"--in-cluster-container", "shell",
+		"--in-cluster-image", "curl-image",
+		"--in-cluster-timeout", "10s",
+	})
+	cmd.ExpectReachesExecution()
+
+	DeepEqual(t, realCmd.InClusterCurlFlags, InClusterCurlFlags{
+		Enabled:   true,
+		Pod:       "debug",
+		Container: "shell",
+		Image:     "curl-image",
+		Timeout:   10 * time.Second,
+	})
+}
+
+func TestNewCurlCmd_InClusterDefaults(t *testing.T) {
+	realCmd := NewCurlOptions(nil, cmdcore.NewConfigFactoryImpl(), cmdcore.NewDepsFactory())
+	cmd := NewTestCmd(t, NewCurlCmd(realCmd, cmdcore.FlagsFactory{}))
+	cmd.Execute([]string{"--namespace", "test-namespace", "--service", "test-service"})
+	cmd.ExpectReachesExecution()
+
+	DeepEqual(t, realCmd.InClusterCurlFlags, InClusterCurlFlags{
+		Image:   "curlimages/curl:latest",
+		Timeout: 1 * time.Minute,
+	})
+}
+
+func TestNewCurlCmd_ToRevision(t *testing.T) {
+	realCmd := NewCurlOptions(nil, cmdcore.NewConfigFactoryImpl(), cmdcore.NewDepsFactory())
+	cmd := NewTestCmd(t, NewCurlCmd(realCmd, cmdcore.FlagsFactory{}))
+	cmd.Execute([]string{"-n", "test-namespace", "-s", "test-service", "--to-revision", "test-service:latest"})
+	cmd.ExpectReachesExecution()
+
+	DeepEqual(t, realCmd.ToRevision, "test-service:latest")
+}
+
+func TestNewCurlCmd_Assertions(t *testing.T) {
+	realCmd := NewCurlOptions(nil, cmdcore.NewConfigFactoryImpl(), cmdcore.NewDepsFactory())
+	cmd := NewTestCmd(t, NewCurlCmd(realCmd, cmdcore.FlagsFactory{}))
+	cmd.Execute([]string{
+		"-n", "test-namespace",
+		"-s", "test-service",
+		"--expect-status", "200",
+		"--expect-body-contains", "ok",
+		"--expect-body-contains", "ready",
+		"--expect-header", "content-type: application/json",
+	})
+	cmd.ExpectReachesExecution()
+
+	DeepEqual(t, realCmd.AssertionFlags, CurlAssertionFlags{
+		ExpectStatus:       200,
+		ExpectBodyContains: []string{"ok", "ready"},
+		ExpectHeaders:      []string{"content-type: application/json"},
+	})
+}
+
+func TestNewCurlCmd_Replay(t *testing.T) {
+	realCmd := NewCurlOptions(nil, cmdcore.NewConfigFactoryImpl(), cmdcore.NewDepsFactory())
+	cmd := NewTestCmd(t, NewCurlCmd(realCmd, cmdcore.FlagsFactory{}))
+	cmd.Execute([]string{"-n", "test-namespace", "-s", "test-service", "--replay", "requests.har"})
+	cmd.ExpectReachesExecution()
+
+	DeepEqual(t, realCmd.Replay, "requests.har")
+}
+
+func TestNewInClusterCurlPod(t *testing.T) {
+	pod := NewInClusterCurlPod("ns1", "curl-image", []string{"-sS", "http://svc1.ns1.svc.cluster.local"})
+
+	DeepEqual(t, pod.Namespace, "ns1")
+	DeepEqual(t, pod.GenerateName, "knctl-curl-")
+	DeepEqual(t, pod.Annotations["sidecar.istio.io/inject"], "false")
+	DeepEqual(t, pod.Spec.RestartPolicy, corev1.RestartPolicyNever)
+	DeepEqual(t, pod.Spec.Containers, []corev1.Container{{
+		Name:    "curl",
+		Image:   "curl-image",
+		Command: []string{"curl"},
+		Args:    []string{"-sS", "http://svc1.ns1.svc.cluster.local"},
+	}})
+}
@@ -22,6 +22,10 @@ import (
 	"github.com/cppforlife/go-cli-ui/ui"
 	cmdcore "github.com/cppforlife/knctl/pkg/knctl/cmd/core"
 	cmdflags "github.com/cppforlife/knctl/pkg/knctl/cmd/flags"
+	cmdrev "github.com/cppforlife/knctl/pkg/knctl/cmd/revision"
+	ctlservice "github.com/cppforlife/knctl/pkg/knctl/service"
+	"github.com/knative/serving/pkg/apis/serving"
+	servingclientset "github.com/knative/serving/pkg/client/clientset/versioned"
 	"github.com/spf13/cobra"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
@@ -30,7 +34,11 @@ type DeleteOptions struct {
 	ui          ui.UI
 	depsFactory cmdcore.DepsFactory
 
-	ServiceFlags cmdflags.ServiceFlags
+	ServiceFlags  cmdflags.ServiceFlags
+	SelectorFlags cmdflags.SelectorFlags
+	DrainFlags    cmdflags.DrainFlags
+
+	Force bool
 }
 
 func NewDeleteOptions(ui ui.UI, depsFactory cmdcore.DepsFactory) *DeleteOptions {
@@ -42,27 +50,124 @@ func NewDeleteCmd(o *DeleteOptions, flagsFactory cmdcore.FlagsFactory) *cobra.Co
 		Use:     "delete",
 		Aliases: cmdcore.DeleteAliases,
 		Short:   "Delete service",
+		Long: `Delete service.
+
+With --drain, service's revisions are first removed from other routes
+that reference them, and deletion waits until those routes stop sending
+traffic and in-flight requests finish (observed concurrency reaches zero).
+Revisions still served by service's own route are not waited for since they
+stop receiving traffic only once service is deleted. Deletion is aborted if
+other revisions keep receiving requests until drain timeout.
+
+Deletion is refused if any of service's revisions are pinned (see 'knctl revision pin')
+unless --force is specified.`,
 		Example: `
   # Delete service 'svc1' in namespace 'ns1'
-  knctl service delete -s svc1 -n ns1`,
+  knctl service delete -s svc1 -n ns1
+
+  # Delete service 'svc1' in namespace 'ns1' after draining its traffic
+  knctl service delete -s svc1 --drain -n ns1
+
+  # Delete all services with label 'team=payments' in namespace 'ns1' (asks for confirmation)
+  knctl service delete --selector team=payments -n ns1`,
 		RunE: func(_ *cobra.Command, _ []string) error { return o.Run() },
 	}
-	o.ServiceFlags.Set(cmd, flagsFactory)
+	o.ServiceFlags.SetOptional(cmd, flagsFactory)
+	o.SelectorFlags.Set(cmd, flagsFactory)
+	o.DrainFlags.Set(cmd, flagsFactory)
+	cmd.Flags().BoolVar(&o.Force, "force", false, "Delete service even if some of its revisions are pinned")
 	return cmd
 }
 
 func (o *DeleteOptions) Run() error {
+	err := o.SelectorFlags.ValidateWithName(o.ServiceFlags.Name, "service")
+	if err != nil {
+		return err
+	}
+
 	servingClient, err := o.depsFactory.ServingClient()
 	if err != nil {
 		return err
 	}
 
-	err = servingClient.ServingV1alpha1().Services(o.ServiceFlags.NamespaceFlags.Name).Delete(o.ServiceFlags.Name, &metav1.DeleteOptions{})
+	if len(o.SelectorFlags.Selector) == 0 {
+		return o.delete(servingClient, o.ServiceFlags.Name)
+	}
+
+	names, err := ctlservice.NewSelection(servingClient).Names(o.ServiceFlags.NamespaceFlags.Name, o.SelectorFlags.Selector)
+	if err != nil {
+		return err
+	}
+
+	o.ui.PrintLinef("Services matching '%s' to be deleted in namespace '%s':", o.SelectorFlags.Selector, o.ServiceFlags.NamespaceFlags.Name)
+
+	for _, name := range names {
+		o.ui.PrintLinef("  %s", name)
+	}
+
+	err = o.ui.AskForConfirmation()
 	if err != nil {
-		return fmt.Errorf("Deleting service: %s", err)
+		return err
+	}
+
+	results := cmdcore.NewBatchResults(fmt.Sprintf("Deleted services matching '%s'", o.SelectorFlags.Selector))
+
+	for _, name := range names {
+		results.Add(name, o.delete(servingClient, name))
 	}
 
-	// TODO idempotent?
+	results.Print(o.ui)
+
+	return results.Err()
+}
+
+func (o *DeleteOptions) delete(servingClient servingclientset.Interface, name string) error {
+	deletion := ctlservice.NewDeletion(servingClient)
+
+	if !o.Force {
+		err := deletion.CheckNotPinned(o.ServiceFlags.NamespaceFlags.Name, name)
+		if err != nil {
+			return fmt.Errorf("%s (use --force to delete it anyway)", err)
+		}
+	}
+
+	if o.DrainFlags.Drain {
+		err := o.drain(servingClient, name)
+		if err != nil {
+			return err
+		}
+	}
+
+	return deletion.Delete(o.ServiceFlags.NamespaceFlags.Name, name)
+}
+
+func (o *DeleteOptions) drain(servingClient servingclientset.Interface, name string) error {
+	coreClient, err := o.depsFactory.CoreClient()
+	if err != nil {
+		return err
+	}
+
+	listOpts := metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("%s=%s", serving.ServiceLabelKey, name),
+	}
+
+	revisions, err := servingClient.ServingV1alpha1().Revisions(o.ServiceFlags.NamespaceFlags.Name).List(listOpts)
+	if err != nil {
+		return fmt.Errorf("Listing revisions: %s", err)
+	}
+
+	var revisionNames []string
+
+	for _, rev := range revisions.Items {
+		revisionNames = append(revisionNames, rev.Name)
+	}
+
+	drain := cmdrev.NewTrafficDrain(coreClient, servingClient, o.ui, o.DrainFlags.Timeout)
+
+	err = drain.Drain(o.ServiceFlags.NamespaceFlags.Name, revisionNames, name)
+	if err != nil {
+		return fmt.Errorf("Draining service: %s", err)
+	}
 
 	return nil
 }
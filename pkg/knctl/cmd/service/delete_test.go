@@ -18,11 +18,15 @@ package service_test
 
 import (
 	"testing"
+	"time"
 
+	"github.com/cppforlife/go-cli-ui/ui"
 	. "github.com/cppforlife/knctl/pkg/knctl/cmd"
 	cmdcore "github.com/cppforlife/knctl/pkg/knctl/cmd/core"
 	cmdflags "github.com/cppforlife/knctl/pkg/knctl/cmd/flags"
 	. "github.com/cppforlife/knctl/pkg/knctl/cmd/service"
+	"github.com/cppforlife/knctl/pkg/knctl/testkit"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 func TestNewDeleteCmd_Ok(t *testing.T) {
@@ -52,9 +56,125 @@ func TestNewDeleteCmd_OkLongFlagNames(t *testing.T) {
 		cmdflags.ServiceFlags{cmdcore.NamespaceFlags{"test-namespace"}, "test-service"})
 }
 
-func TestNewDeleteCmd_RequiredFlags(t *testing.T) {
+func TestNewDeleteCmd_Selector(t *testing.T) {
 	realCmd := NewDeleteOptions(nil, cmdcore.NewDepsFactory())
 	cmd := NewTestCmd(t, NewDeleteCmd(realCmd, cmdcore.FlagsFactory{}))
-	cmd.Execute([]string{})
-	cmd.ExpectRequiredFlags([]string{"service"})
+	cmd.Execute([]string{"-n", "test-namespace", "--selector", "team=payments"})
+	cmd.ExpectReachesExecution()
+
+	DeepEqual(t, realCmd.SelectorFlags, cmdflags.SelectorFlags{"team=payments"})
+}
+
+func TestDeleteOptions_RequiresOnlyServiceOrSelector(t *testing.T) {
+	opts := NewDeleteOptions(ui.NewNoopUI(), testkit.NewCluster(t).DepsFactory())
+	opts.ServiceFlags = cmdflags.ServiceFlags{cmdcore.NamespaceFlags{"ns1"}, "svc1"}
+	opts.SelectorFlags = cmdflags.SelectorFlags{"team=payments"}
+
+	err := opts.Run()
+	if err == nil || err.Error() != "Expected only one of --service or --selector to be specified" {
+		t.Fatalf("Expected service or selector error, but was: %v", err)
+	}
+}
+
+func TestDeleteOptions_DeletesSelectedServices(t *testing.T) {
+	svc1 := testkit.NewService("ns1", "svc1").Build()
+	svc1.Labels = map[string]string{"team": "payments"}
+
+	cluster := testkit.NewCluster(t, svc1, testkit.NewService("ns1", "svc2").Build())
+
+	opts := NewDeleteOptions(ui.NewNonInteractiveUI(ui.NewNoopUI()), cluster.DepsFactory())
+	opts.ServiceFlags = cmdflags.ServiceFlags{cmdcore.NamespaceFlags{"ns1"}, ""}
+	opts.SelectorFlags = cmdflags.SelectorFlags{"team=payments"}
+
+	err := opts.Run()
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	services, err := cluster.ServingClient().ServingV1alpha1().Services("ns1").List(metav1.ListOptions{})
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	if len(services.Items) != 1 || services.Items[0].Name != "svc2" {
+		t.Fatalf("Expected only service 'svc2' to remain, but was: %#v", services.Items)
+	}
+
+	opts.SelectorFlags = cmdflags.SelectorFlags{"team=nobody"}
+
+	err = opts.Run()
+	if err == nil || err.Error() != "Expected at least one service to match selector 'team=nobody'" {
+		t.Fatalf("Expected no matching services error, but was: %v", err)
+	}
+}
+
+func TestDeleteOptions_RejectsBlankSelector(t *testing.T) {
+	cluster := testkit.NewCluster(t, testkit.NewService("ns1", "svc1").Build())
+
+	opts := NewDeleteOptions(ui.NewNonInteractiveUI(ui.NewNoopUI()), cluster.DepsFactory())
+	opts.ServiceFlags = cmdflags.ServiceFlags{cmdcore.NamespaceFlags{"ns1"}, ""}
+	opts.SelectorFlags = cmdflags.SelectorFlags{" "}
+
+	err := opts.Run()
+	if err == nil || err.Error() != "Expected --selector to not be blank" {
+		t.Fatalf("Expected blank selector error, but was: %v", err)
+	}
+
+	_, err = cluster.ServingClient().ServingV1alpha1().Services("ns1").Get("svc1", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Expected service to not be deleted: %s", err)
+	}
+}
+
+func TestNewDeleteCmd_Drain(t *testing.T) {
+	realCmd := NewDeleteOptions(nil, cmdcore.NewDepsFactory())
+	cmd := NewTestCmd(t, NewDeleteCmd(realCmd, cmdcore.FlagsFactory{}))
+	cmd.ExpectBasicConfig()
+	cmd.Execute([]string{
+		"-n", "test-namespace",
+		"-s", "test-service",
+		"--drain",
+		"--drain-timeout", "1m",
+	})
+	cmd.ExpectReachesExecution()
+
+	DeepEqual(t, realCmd.DrainFlags, cmdflags.DrainFlags{Drain: true, Timeout: time.Minute})
+}
+
+func TestNewDeleteCmd_DrainDefaults(t *testing.T) {
+	realCmd := NewDeleteOptions(nil, cmdcore.NewDepsFactory())
+	cmd := NewTestCmd(t, NewDeleteCmd(realCmd, cmdcore.FlagsFactory{}))
+	cmd.ExpectBasicConfig()
+	cmd.Execute([]string{"-n", "test-namespace", "-s", "test-service"})
+	cmd.ExpectReachesExecution()
+
+	DeepEqual(t, realCmd.DrainFlags, cmdflags.DrainFlags{Drain: false, Timeout: 5 * time.Minute})
+}
+
+func TestDeleteOptions_RefusesPinnedRevisionsWithoutForce(t *testing.T) {
+	cluster := testkit.NewCluster(t,
+		testkit.NewService("ns1", "svc1").Build(),
+		testkit.NewRevision("ns1", "svc1", "svc1-00001").Build(),
+		testkit.NewRevision("ns1", "svc1", "svc1-00002").Annotations(map[string]string{"cli.knative.dev/pinned": "true"}).Build(),
+	)
+
+	opts := NewDeleteOptions(ui.NewNoopUI(), cluster.DepsFactory())
+	opts.ServiceFlags = cmdflags.ServiceFlags{cmdcore.NamespaceFlags{"ns1"}, "svc1"}
+
+	err := opts.Run()
+	if err == nil || err.Error() != "Expected service 'svc1' to not have pinned revisions, but found: svc1-00002 (use --force to delete it anyway)" {
+		t.Fatalf("Expected pinned revisions error, but was: %v", err)
+	}
+
+	opts.Force = true
+
+	err = opts.Run()
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	_, err = cluster.ServingClient().ServingV1alpha1().Services("ns1").Get("svc1", metav1.GetOptions{})
+	if err == nil {
+		t.Fatalf("Expected service to be deleted")
+	}
 }
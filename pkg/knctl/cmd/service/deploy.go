@@ -17,34 +17,62 @@ limitations under the License.
 package service
 
 import (
+	"fmt"
+	"sync"
 	"time"
 
 	"github.com/cppforlife/go-cli-ui/ui"
 	uitable "github.com/cppforlife/go-cli-ui/ui/table"
+	"github.com/cppforlife/knctl/pkg/knctl/approval"
 	ctlbuild "github.com/cppforlife/knctl/pkg/knctl/build"
 	cmdcore "github.com/cppforlife/knctl/pkg/knctl/cmd/core"
 	cmdflags "github.com/cppforlife/knctl/pkg/knctl/cmd/flags"
+	cmdsa "github.com/cppforlife/knctl/pkg/knctl/cmd/serviceaccount"
+	"github.com/cppforlife/knctl/pkg/knctl/gitmeta"
+	ctlhistory "github.com/cppforlife/knctl/pkg/knctl/history"
+	ctlknconfig "github.com/cppforlife/knctl/pkg/knctl/knativeconfig"
 	ctlkube "github.com/cppforlife/knctl/pkg/knctl/kube"
 	"github.com/cppforlife/knctl/pkg/knctl/logs"
+	"github.com/cppforlife/knctl/pkg/knctl/redact"
+	"github.com/cppforlife/knctl/pkg/knctl/registry"
 	ctlservice "github.com/cppforlife/knctl/pkg/knctl/service"
+	"github.com/cppforlife/knctl/pkg/knctl/signature"
 	"github.com/knative/serving/pkg/apis/serving/v1alpha1"
 	servingclientset "github.com/knative/serving/pkg/client/clientset/versioned"
 	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/util/retry"
+)
+
+const (
+	// Revision pods use namespace's default service account when none is specified
+	defaultServiceAccountName = "default"
 )
 
 type DeployOptions struct {
 	ui            ui.UI
 	configFactory cmdcore.ConfigFactory
 	depsFactory   cmdcore.DepsFactory
+	cancelSignals cmdcore.CancelWatcher
 
 	ServiceFlags cmdflags.ServiceFlags
 	DeployFlags  DeployFlags
+	RedactFlags  cmdflags.RedactFlags
+	NotifyFlags  cmdflags.NotifyFlags
+	ScanFlags    cmdflags.ScanFlags
+
+	SignatureFlags cmdflags.SignatureFlags
+
+	// Used by Go API to fail deploy when new revision does not become ready
+	RequireRevisionReady bool
 }
 
-func NewDeployOptions(ui ui.UI, configFactory cmdcore.ConfigFactory, depsFactory cmdcore.DepsFactory) *DeployOptions {
-	return &DeployOptions{ui: ui, configFactory: configFactory, depsFactory: depsFactory}
+func NewDeployOptions(ui ui.UI, configFactory cmdcore.ConfigFactory, depsFactory cmdcore.DepsFactory, cancelSignals cmdcore.CancelWatcher) *DeployOptions {
+	return &DeployOptions{ui: ui, configFactory: configFactory, depsFactory: depsFactory, cancelSignals: cancelSignals}
 }
 
 func NewDeployCmd(o *DeployOptions, flagsFactory cmdcore.FlagsFactory) *cobra.Command {
@@ -76,7 +104,31 @@ func NewDeployCmd(o *DeployOptions, flagsFactory cmdcore.FlagsFactory) *cobra.Co
   knctl deploy -s srv1 -n ns1 \
       --image gcr.io/knative-samples/helloworld-go \
       --env-secret TARGET=secret/key1 \
-      --env-secret TARGET=secret/key2`,
+      --env-secret TARGET=secret/key2
+
+  # Deploy service 'srv1' keeping 3 containers running while new revision starts in namespace 'ns1'
+  knctl deploy -s srv1 -n ns1 --image gcr.io/your-account/your-jvm-image --startup-cpu-boost 3
+
+  # Deploy service 'srv1' from private registry image in namespace 'ns1'
+  # (image pull secret is added to service account 'serv-acct1')
+  knctl deploy -s srv1 -n ns1 \
+      --image index.docker.io/your-account/your-private-image \
+      --service-account serv-acct1 --image-pull-secret reg-secret
+
+  # Deploy service 'srv1' and post result summary to Slack channel in namespace 'ns1'
+  knctl deploy -s srv1 -n ns1 --image gcr.io/knative-samples/helloworld-go \
+      --notify-slack-webhook https://hooks.slack.com/services/T0/B0/XXX
+
+  # Deploy service 'srv1' only if image has no critical vulnerabilities in namespace 'ns1'
+  knctl deploy -s srv1 -n ns1 --image gcr.io/knative-samples/helloworld-go \
+      --scan --scanner-url https://scanner.example.com/report --fail-on critical
+
+  # Deploy service 'srv1' only if image is signed with cosign key in namespace 'ns1'
+  knctl deploy -s srv1 -n ns1 --image gcr.io/your-account/your-image \
+      --verify-signature --cosign-key cosign.pub
+
+  # Deploy service 'srv1' keeping traffic on current revision until someone runs 'knctl approve'
+  knctl deploy -s srv1 -n ns1 --image gcr.io/knative-samples/helloworld-go --require-approval`,
 		Annotations: map[string]string{
 			cmdcore.BasicHelpGroup.Key: cmdcore.BasicHelpGroup.Value,
 		},
@@ -84,10 +136,39 @@ func NewDeployCmd(o *DeployOptions, flagsFactory cmdcore.FlagsFactory) *cobra.Co
 	}
 	o.ServiceFlags.Set(cmd, flagsFactory)
 	o.DeployFlags.Set(cmd, flagsFactory)
+	o.RedactFlags.Set(cmd, flagsFactory)
+	o.NotifyFlags.Set(cmd, flagsFactory)
+	o.ScanFlags.Set(cmd, flagsFactory)
+	o.SignatureFlags.Set(cmd, flagsFactory)
 	return cmd
 }
 
 func (o *DeployOptions) Run() error {
+	entry := ctlhistory.NewEntry(ctlhistory.OperationDeploy, o.ServiceFlags.Name)
+
+	err := o.run(&entry)
+
+	notifyErr := o.NotifyFlags.Notify(o.depsFactory, entry, o.ServiceFlags.NamespaceFlags.Name, err)
+	if notifyErr != nil {
+		o.ui.ErrorLinef("Warning: %s", notifyErr)
+	}
+
+	return err
+}
+
+func (o *DeployOptions) run(entry *ctlhistory.Entry) error {
+	if o.DeployFlags.StartupCPUBoost != nil && !o.DeployFlags.WatchRevisionReady {
+		return fmt.Errorf("Expected to watch for revision to become ready when using startup CPU boost")
+	}
+
+	if o.DeployFlags.RequireApproval && o.DeployFlags.GenerateNameFlags.GenerateName {
+		return fmt.Errorf("Expected service name to not be generated when requiring approval")
+	}
+
+	if len(o.ScanFlags.FailOn) > 0 && !o.ScanFlags.Scan {
+		return fmt.Errorf("Expected --scan to be specified when using --fail-on")
+	}
+
 	servingClient, err := o.depsFactory.ServingClient()
 	if err != nil {
 		return err
@@ -108,8 +189,39 @@ func (o *DeployOptions) Run() error {
 		return err
 	}
 
+	o.warnDisabledFeatures(coreClient)
+
 	serviceSpec := NewServiceSpec(o.ServiceFlags, o.DeployFlags)
-	buildObjFactory := ctlbuild.NewFactory(buildClient, coreClient, restConfig)
+
+	checkedImage, err := o.checkImage(serviceSpec, coreClient)
+	if err != nil {
+		return err
+	}
+
+	if len(checkedImage) > 0 {
+		// Deploy exactly the image that was checked so that
+		// retagging it afterwards does not bypass checks
+		deployFlags := o.DeployFlags
+		deployFlags.Image = checkedImage
+		serviceSpec = NewServiceSpec(o.ServiceFlags, deployFlags)
+	}
+
+	if o.DeployFlags.Preflight {
+		validated, err := NewDeployPreflight(servingClient, coreClient).Validate(serviceSpec, o.DeployFlags)
+		if err != nil {
+			return err
+		}
+		if !validated {
+			o.ui.ErrorLinef("Warning: Skipping preflight validation since server does not support dry run")
+		}
+	}
+
+	objWaiter, err := o.depsFactory.Waiter()
+	if err != nil {
+		return err
+	}
+
+	buildObjFactory := ctlbuild.NewFactory(coreClient, restConfig, objWaiter)
 	serviceObj := ctlservice.NewService(serviceSpec, servingClient, buildClient, coreClient, buildObjFactory)
 
 	var lastRevision *v1alpha1.Revision
@@ -122,6 +234,24 @@ func (o *DeployOptions) Run() error {
 		}
 	}
 
+	if o.DeployFlags.RequireApproval {
+		if lastRevision == nil {
+			return fmt.Errorf("Expected service '%s' to have existing revision when requiring approval", serviceSpec.Name())
+		}
+
+		err = NewServiceApproval(servingClient, coreClient).Freeze(serviceSpec.Namespace(), serviceSpec.Name(), lastRevision.Name)
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(o.DeployFlags.ImagePullSecrets) > 0 {
+		err = o.addImagePullSecrets(coreClient)
+		if err != nil {
+			return err
+		}
+	}
+
 	createdService, err := serviceObj.CreateOrUpdate()
 	if err != nil {
 		return err
@@ -135,11 +265,42 @@ func (o *DeployOptions) Run() error {
 		o.ui.PrintLinef("Waiting for new revision to be created...")
 	}
 
-	newLastRevision, err := serviceObj.CreatedRevisionSinceRevision(lastRevision)
+	cancelCh := make(chan struct{})
+	var cancelOnce sync.Once
+
+	// Interrupting returns from deploy instead of exiting
+	// so that startup CPU boost is relaxed before exiting
+	o.cancelSignals.Watch(func() { cancelOnce.Do(func() { close(cancelCh) }) })
+
+	newLastRevision, err := serviceObj.CreatedRevisionSinceRevision(lastRevision, cancelCh)
 	if err != nil {
 		return err
 	}
 
+	entry.Service = createdService.Name
+	entry.Revision = newLastRevision.Name
+	entry.Image = newLastRevision.Spec.Container.Image
+
+	if lastRevision != nil {
+		entry.PreviousRevision = lastRevision.Name
+	}
+
+	relaxStartupBoost := func() {}
+
+	if o.DeployFlags.StartupCPUBoost != nil {
+		var relaxOnce sync.Once
+
+		relaxStartupBoost = func() {
+			relaxOnce.Do(func() { o.relaxStartupBoost(newLastRevision, servingClient) })
+		}
+
+		// Boosted minimum scale is relaxed regardless of deploy outcome
+		defer relaxStartupBoost()
+	}
+
+	// Record regardless of deploy outcome once new revision exists
+	defer o.recordHistory(createdService.Name, lastRevision, newLastRevision, servingClient, coreClient)
+
 	err = o.updateRevisionTags(lastRevision, newLastRevision, servingClient)
 	if err != nil {
 		return err
@@ -150,10 +311,15 @@ func (o *DeployOptions) Run() error {
 		return err
 	}
 
+	if o.DeployFlags.RequireApproval {
+		err = o.requestApproval(*entry, coreClient)
+		if err != nil {
+			return err
+		}
+	}
+
 	// TODO support non Knative builders
 	if serviceSpec.HasBuild() {
-		cancelCh := make(chan struct{})
-
 		buildObj, err := serviceObj.CreatedBuildSinceRevision(lastRevision)
 		if err != nil {
 			return err
@@ -166,7 +332,12 @@ func (o *DeployOptions) Run() error {
 			}
 		}
 
-		err = buildObj.TailLogs(o.ui, cancelCh)
+		buildUI, err := o.buildUI(coreClient)
+		if err != nil {
+			return err
+		}
+
+		err = buildObj.TailLogs(buildUI, cancelCh)
 		if err != nil {
 			return err
 		}
@@ -178,12 +349,112 @@ func (o *DeployOptions) Run() error {
 	}
 
 	if o.DeployFlags.WatchRevisionReady {
-		return o.watchRevisionReady(newLastRevision, servingClient, coreClient)
+		return o.watchRevisionReady(newLastRevision, servingClient, coreClient, relaxStartupBoost, cancelCh)
+	}
+
+	return nil
+}
+
+// warnDisabledFeatures notifies about flags that rely on disabled Knative features
+// before service is submitted; lack of access to Knative config is not an error
+func (o *DeployOptions) warnDisabledFeatures(coreClient kubernetes.Interface) {
+	requiredFeatures := o.DeployFlags.RequiredFeatures()
+	if len(requiredFeatures) == 0 {
+		return
+	}
+
+	var featureNames []string
+
+	for _, name := range requiredFeatures {
+		featureNames = append(featureNames, name)
+	}
+
+	disabledFeatures, err := ctlknconfig.NewFeatures(coreClient).Disabled(featureNames)
+	if err != nil {
+		return
+	}
+
+	for flagName, featureName := range requiredFeatures {
+		for _, feature := range disabledFeatures {
+			if feature.Name == featureName {
+				o.ui.ErrorLinef("Warning: Flag '--%s' may require feature '%s' which is disabled in config map '%s/%s' "+
+					"(see 'knctl knative-config features')", flagName, feature.Name, ctlknconfig.ServingNamespace, feature.ConfigMap)
+			}
+		}
+	}
+}
+
+// addImagePullSecrets makes sure that service account used by revision
+// references given image pull secrets so that private images can be pulled
+func (o *DeployOptions) addImagePullSecrets(coreClient kubernetes.Interface) error {
+	namespace := o.ServiceFlags.NamespaceFlags.Name
+	saName := o.DeployFlags.BuildCreateArgsFlags.ServiceAccountName
+
+	if len(saName) == 0 {
+		saName = defaultServiceAccountName
+	}
+
+	for _, secretName := range o.DeployFlags.ImagePullSecrets {
+		_, err := coreClient.CoreV1().Secrets(namespace).Get(secretName, metav1.GetOptions{})
+		if err != nil {
+			return fmt.Errorf("Getting image pull secret '%s': %s", secretName, err)
+		}
+	}
+
+	serviceAccounts := coreClient.CoreV1().ServiceAccounts(namespace)
+	saSecrets := cmdsa.NewServiceAccountSecrets(coreClient)
+
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		sa, err := serviceAccounts.Get(saName, metav1.GetOptions{})
+		if err != nil {
+			if !errors.IsNotFound(err) {
+				return err
+			}
+
+			o.ui.PrintLinef("Creating service account '%s'", saName)
+
+			sa = &corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: saName, Namespace: namespace}}
+
+			err = saSecrets.Add(sa, nil, o.DeployFlags.ImagePullSecrets)
+			if err != nil {
+				return err
+			}
+
+			_, err = serviceAccounts.Create(sa)
+			return err
+		}
+
+		err = saSecrets.Add(sa, nil, o.DeployFlags.ImagePullSecrets)
+		if err != nil {
+			return err
+		}
+
+		_, err = serviceAccounts.Update(sa)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("Adding image pull secrets to service account '%s': %s", saName, err)
 	}
 
 	return nil
 }
 
+// buildUI masks basic auth passwords (e.g. used for Git or registry access)
+// that may show up in build logs
+func (o *DeployOptions) buildUI(coreClient kubernetes.Interface) (ui.UI, error) {
+	if o.RedactFlags.ShowSecrets {
+		return o.ui, nil
+	}
+
+	secretValues, err := redact.NewSecretValues(coreClient).ForServiceAccount(
+		o.ServiceFlags.NamespaceFlags.Name, o.DeployFlags.BuildCreateArgsFlags.ServiceAccountName)
+	if err != nil {
+		return nil, err
+	}
+
+	return redact.NewRedactingUI(o.ui, redact.NewRedactor(secretValues)), nil
+}
+
 func (o *DeployOptions) printTable(svc *v1alpha1.Service) {
 	table := uitable.Table{
 		Header: []uitable.Header{
@@ -242,6 +513,20 @@ func (o *DeployOptions) updateRevisionAnnotations(
 		return err
 	}
 
+	if o.DeployFlags.GitMetadata {
+		gitAnnotations, err := o.gitAnnotations()
+		if err != nil {
+			o.ui.ErrorLinef("Warning: Skipping git metadata: %s", err)
+		}
+
+		for k, v := range gitAnnotations {
+			// Explicitly specified annotations take precedence
+			if _, found := annotations[k]; !found {
+				annotations[k] = v
+			}
+		}
+	}
+
 	if len(annotations) == 0 {
 		return nil
 	}
@@ -256,29 +541,251 @@ func (o *DeployOptions) updateRevisionAnnotations(
 	return anns.Add(annotations)
 }
 
-func (o *DeployOptions) watchRevisionReady(
-	newLastRevision *v1alpha1.Revision, servingClient servingclientset.Interface, coreClient kubernetes.Interface) error {
+func (o *DeployOptions) requestApproval(entry ctlhistory.Entry, coreClient kubernetes.Interface) error {
+	pending := approval.Pending{
+		Service:          entry.Service,
+		Revision:         entry.Revision,
+		PreviousRevision: entry.PreviousRevision,
+		RequestedBy:      entry.User,
+		RequestedAt:      entry.Time,
+
+		// Route is only managed separately until deploy is approved
+		RestoreManagedRoute: o.DeployFlags.ManagedRoute,
+	}
+
+	err := approval.NewApprovals(coreClient).Request(o.ServiceFlags.NamespaceFlags.Name, pending)
+	if err != nil {
+		return err
+	}
+
+	o.ui.PrintLinef("Revision '%s' is not receiving traffic until approved (run 'knctl approve -s %s -n %s')",
+		entry.Revision, entry.Service, o.ServiceFlags.NamespaceFlags.Name)
+
+	return nil
+}
+
+// checkImage scans and verifies image (resolved to digest) before it's deployed.
+// Returns image pinned to verified digest or empty string if image was not checked.
+func (o *DeployOptions) checkImage(serviceSpec ServiceSpec, coreClient kubernetes.Interface) (string, error) {
+	required, err := o.signatureRequired(coreClient)
+	if err != nil {
+		return "", err
+	}
+
+	if required && !o.SignatureFlags.VerifySignature {
+		return "", fmt.Errorf("Expected --verify-signature to be specified since namespace '%s' requires signed images "+
+			"(annotation '%s')", o.ServiceFlags.NamespaceFlags.Name, signature.RequiredAnnotationKey)
+	}
+
+	if !o.ScanFlags.Scan && !o.SignatureFlags.VerifySignature {
+		return "", nil
+	}
+
+	// TODO check images produced by builds once build completes
+	if serviceSpec.HasBuild() {
+		if o.SignatureFlags.VerifySignature {
+			return "", fmt.Errorf("Expected image to not be built from source when verifying signature")
+		}
+		o.ui.ErrorLinef("Warning: Skipping image scan since image is built from source")
+		return "", nil
+	}
+
+	httpClient, err := o.depsFactory.HTTPClient()
+	if err != nil {
+		return "", err
+	}
+
+	keychain, err := o.registryKeychain(coreClient)
+	if err != nil {
+		return "", err
+	}
+
+	registryClient := registry.NewAuthenticatedClient(httpClient, keychain)
+
+	ref, err := registry.ParseReference(o.DeployFlags.Image)
+	if err != nil {
+		return "", err
+	}
+
+	digest, err := registryClient.Digest(ref)
+	if err != nil {
+		return "", fmt.Errorf("Resolving image digest: %s", err)
+	}
+
+	ref = ref.WithDigest(digest)
+
+	if o.SignatureFlags.VerifySignature {
+		err = o.SignatureFlags.VerifyImage(o.ui, registryClient, ref)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	if o.ScanFlags.Scan {
+		err = o.ScanFlags.ScanImage(o.ui, httpClient, ref)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	return ref.String(), nil
+}
+
+// registryKeychain collects registry credentials from image pull secrets
+// given via flags and referenced by service account used by revision
+func (o *DeployOptions) registryKeychain(coreClient kubernetes.Interface) (registry.Keychain, error) {
+	var keychain registry.Keychain
+
+	namespace := o.ServiceFlags.NamespaceFlags.Name
+	secretNames := append([]string{}, o.DeployFlags.ImagePullSecrets...)
+
+	saName := o.DeployFlags.BuildCreateArgsFlags.ServiceAccountName
+	if len(saName) == 0 {
+		saName = defaultServiceAccountName
+	}
+
+	// Lack of access falls back to anonymous registry access
+	sa, err := coreClient.CoreV1().ServiceAccounts(namespace).Get(saName, metav1.GetOptions{})
+	if err != nil {
+		if !errors.IsNotFound(err) && !errors.IsForbidden(err) {
+			return keychain, fmt.Errorf("Getting service account '%s': %s", saName, err)
+		}
+	} else {
+		for _, ref := range sa.ImagePullSecrets {
+			secretNames = append(secretNames, ref.Name)
+		}
+	}
+
+	for _, secretName := range secretNames {
+		secret, err := coreClient.CoreV1().Secrets(namespace).Get(secretName, metav1.GetOptions{})
+		if err != nil {
+			if errors.IsForbidden(err) {
+				continue
+			}
+			return keychain, fmt.Errorf("Getting image pull secret '%s': %s", secretName, err)
+		}
+
+		switch secret.Type {
+		case corev1.SecretTypeDockerConfigJson:
+			err = keychain.AddDockerConfigJSON(secret.Data[corev1.DockerConfigJsonKey])
+		case corev1.SecretTypeDockercfg:
+			err = keychain.AddDockerConfig(secret.Data[corev1.DockerConfigKey])
+		}
+		if err != nil {
+			return keychain, fmt.Errorf("Reading image pull secret '%s': %s", secretName, err)
+		}
+	}
+
+	return keychain, nil
+}
+
+// signatureRequired checks whether namespace is marked to only allow signed images;
+// lack of access to namespace is not an error
+func (o *DeployOptions) signatureRequired(coreClient kubernetes.Interface) (bool, error) {
+	ns, err := coreClient.CoreV1().Namespaces().Get(o.ServiceFlags.NamespaceFlags.Name, metav1.GetOptions{})
+	if err != nil {
+		if errors.IsForbidden(err) || errors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	return ns.Annotations[signature.RequiredAnnotationKey] == "true", nil
+}
+
+func (o *DeployOptions) gitAnnotations() (map[string]string, error) {
+	dir := o.DeployFlags.BuildCreateArgsFlags.SourceDirectory
+	if len(dir) == 0 {
+		dir = "."
+	}
+
+	meta, found, err := gitmeta.Detect(dir)
+	if err != nil || !found {
+		return nil, err
+	}
+
+	return meta.Annotations(), nil
+}
+
+func (o *DeployOptions) recordHistory(serviceName string, lastRevision, newLastRevision *v1alpha1.Revision,
+	servingClient servingclientset.Interface, coreClient kubernetes.Interface) {
+
+	entry := ctlhistory.NewEntry(ctlhistory.OperationDeploy, serviceName)
+	entry.Revision = newLastRevision.Name
+	entry.Image = newLastRevision.Spec.Container.Image
+
+	if lastRevision != nil {
+		entry.PreviousRevision = lastRevision.Name
+	}
+
+	// Image digest is only resolved by the time revision becomes ready
+	revision, err := servingClient.ServingV1alpha1().Revisions(newLastRevision.Namespace).Get(newLastRevision.Name, metav1.GetOptions{})
+	if err == nil {
+		entry.ImageDigest = revision.Status.ImageDigest
+	}
+
+	err = ctlhistory.NewHistory(coreClient).Record(newLastRevision.Namespace, entry)
+	if err != nil {
+		o.ui.ErrorLinef("Warning: %s", err)
+	}
+}
+
+func (o *DeployOptions) relaxStartupBoost(revision *v1alpha1.Revision, servingClient servingclientset.Interface) {
+	err := NewStartupBoost(servingClient).Relax(revision, o.DeployFlags.MinScale)
+	if err != nil {
+		o.ui.ErrorLinef("%s", err)
+	} else {
+		o.ui.PrintLinef("Relaxed startup CPU boost for revision '%s'", revision.Name)
+	}
+}
+
+func (o *DeployOptions) watchRevisionReady(newLastRevision *v1alpha1.Revision, servingClient servingclientset.Interface,
+	coreClient kubernetes.Interface, relaxStartupBoost func(), cancelCh chan struct{}) error {
 
 	totalWaitDur := o.DeployFlags.WatchRevisionReadyTimeout
 	logCollectDur := 5 * time.Second
 
+	objWaiter, err := o.depsFactory.Waiter()
+	if err != nil {
+		return err
+	}
+
 	o.ui.PrintLinef("Waiting for new revision '%s' to be ready for up to %s (logs below)...", newLastRevision.Name, totalWaitDur)
 	cancelWatchCh := make(chan struct{})
 	cancelLogsCh := make(chan struct{})
 
+	var cancelWatchOnce, cancelLogsOnce sync.Once
+	cancelWatch := func() { cancelWatchOnce.Do(func() { close(cancelWatchCh) }) }
+	cancelLogs := func() { cancelLogsOnce.Do(func() { close(cancelLogsCh) }) }
+
+	go func() {
+		select {
+		case <-cancelCh:
+			cancelWatch()
+			cancelLogs()
+		case <-cancelLogsCh:
+		}
+	}()
+
 	go func() {
 		time.Sleep(totalWaitDur)
-		close(cancelWatchCh)
+		cancelWatch()
 	}()
 
+	readyCh := make(chan bool, 1)
+
 	go func() {
-		ready, _ := RevisionReadyStatusWatcher{newLastRevision, servingClient}.Wait(cancelWatchCh)
+		ready, _ := RevisionReadyStatusWatcher{newLastRevision, servingClient, objWaiter}.Wait(cancelWatchCh)
+		readyCh <- ready
+
 		if ready {
 			o.ui.PrintLinef("Revision '%s' became ready", newLastRevision.Name)
 		} else {
 			o.ui.PrintLinef("Revision '%s' did not became ready", newLastRevision.Name)
 		}
 
+		relaxStartupBoost()
+
 		if ready && o.DeployFlags.WatchPodLogsIndefinitely {
 			o.ui.PrintLinef("Continuing to watch logs indefinitely")
 			return
@@ -286,17 +793,21 @@ func (o *DeployOptions) watchRevisionReady(
 
 		if o.DeployFlags.WatchPodLogs {
 			o.ui.PrintLinef("Continuing to watch logs for %s before exiting", logCollectDur)
-			time.Sleep(logCollectDur)
+
+			select {
+			case <-time.After(logCollectDur):
+			case <-cancelLogsCh:
+			}
 		}
 
-		close(cancelLogsCh)
+		cancelLogs()
 	}()
 
 	if o.DeployFlags.WatchPodLogs {
 		tailOpts := logs.PodLogOpts{Follow: true}
 		podWatcher := ctlservice.NewRevisionPodWatcher(newLastRevision, servingClient, coreClient, o.ui)
 
-		err := LogsView{tailOpts, podWatcher, coreClient, o.ui}.Show(cancelLogsCh)
+		err := logs.NewView(tailOpts, podWatcher, coreClient, o.ui, o.RedactFlags.ShowSecrets).Show(cancelLogsCh)
 		if err != nil {
 			return err
 		}
@@ -304,5 +815,9 @@ func (o *DeployOptions) watchRevisionReady(
 		<-cancelLogsCh
 	}
 
+	if o.RequireRevisionReady && !<-readyCh {
+		return fmt.Errorf("Expected revision '%s' to become ready within %s", newLastRevision.Name, totalWaitDur)
+	}
+
 	return nil
 }
@@ -17,12 +17,12 @@ limitations under the License.
 package service
 
 import (
-	"strconv"
 	"time"
 
 	cmdbld "github.com/cppforlife/knctl/pkg/knctl/cmd/build"
 	cmdcore "github.com/cppforlife/knctl/pkg/knctl/cmd/core"
 	cmdflags "github.com/cppforlife/knctl/pkg/knctl/cmd/flags"
+	ctlknconfig "github.com/cppforlife/knctl/pkg/knctl/knativeconfig"
 	"github.com/spf13/cobra"
 )
 
@@ -37,9 +37,16 @@ type DeployFlags struct {
 	EnvSecrets    []string
 	EnvConfigMaps []string
 
+	ImagePullSecrets []string
+
 	ContainerConcurrency *int
 	MinScale             *int
 	MaxScale             *int
+	StartupCPUBoost      *int
+
+	RunAsUser        *int
+	ReadOnlyRootFS   bool
+	DropCapabilities []string
 
 	WatchRevisionReady        bool
 	WatchRevisionReadyTimeout time.Duration
@@ -47,7 +54,10 @@ type DeployFlags struct {
 	WatchPodLogs             bool
 	WatchPodLogsIndefinitely bool
 
-	ManagedRoute bool
+	ManagedRoute    bool
+	Preflight       bool
+	GitMetadata     bool
+	RequireApproval bool
 
 	RemoveKnctlDeployEnvVar bool
 }
@@ -59,6 +69,9 @@ func (s *DeployFlags) Set(cmd *cobra.Command, flagsFactory cmdcore.FlagsFactory)
 	s.AnnotateFlags.Set(cmd, flagsFactory)
 
 	// TODO separate service account for pulling?
+	// Node selector, toleration, affinity and spread flags are not provided:
+	// RevisionSpec of Knative Serving v0.2.1 has no pod level scheduling fields
+	// (only container, service account and concurrency settings)
 
 	cmd.Flags().StringVarP(&s.Image, "image", "i", "", "Set image URL")
 	cmd.MarkFlagRequired("image")
@@ -75,38 +88,34 @@ func (s *DeployFlags) Set(cmd *cobra.Command, flagsFactory cmdcore.FlagsFactory)
 	cmd.Flags().StringSliceVar(&s.EnvSecrets, "env-secret", nil, "Set environment variable from a secret (format: ENV_KEY=secret-name/key) (can be specified multiple times)")
 	cmd.Flags().StringSliceVar(&s.EnvConfigMaps, "env-config-map", nil, "Set environment variable from a config map (format: ENV_KEY=config-map-name/key) (can be specified multiple times)")
 
-	cmd.Flags().Var(newDefaultlessIntValue(&s.ContainerConcurrency), "container-concurrency", "Set container concurrency")
-	cmd.Flags().Var(newDefaultlessIntValue(&s.MinScale), "min-scale", "Set autoscaling rule for minimum number of containers")
-	cmd.Flags().Var(newDefaultlessIntValue(&s.MaxScale), "max-scale", "Set autoscaling rule for maximum number of containers")
-
-	cmd.Flags().BoolVar(&s.ManagedRoute, "managed-route", true, "Custom route configuration")
-}
+	cmd.Flags().StringSliceVar(&s.ImagePullSecrets, "image-pull-secret", nil, "Add image pull secret to service account used by revision (service account is created if necessary) (can be specified multiple times)")
 
-type defaultlessIntValue struct {
-	val **int
-}
+	cmd.Flags().Var(cmdcore.NewDefaultlessIntValue(&s.ContainerConcurrency), "container-concurrency", "Set container concurrency")
+	cmd.Flags().Var(cmdcore.NewDefaultlessIntValue(&s.MinScale), "min-scale", "Set autoscaling rule for minimum number of containers")
+	cmd.Flags().Var(cmdcore.NewDefaultlessIntValue(&s.MaxScale), "max-scale", "Set autoscaling rule for maximum number of containers")
+	cmd.Flags().Var(cmdcore.NewDefaultlessIntValue(&s.StartupCPUBoost), "startup-cpu-boost", "Keep at least this many containers running for new revision until it becomes ready (or fails to), then relax to --min-scale (spreads slow cold start CPU load)")
 
-func newDefaultlessIntValue(p **int) *defaultlessIntValue {
-	return &defaultlessIntValue{p}
-}
+	cmd.Flags().Var(cmdcore.NewDefaultlessIntValue(&s.RunAsUser), "run-as-user", "Set UID to run container process as")
+	cmd.Flags().BoolVar(&s.ReadOnlyRootFS, "read-only-root-fs", false, "Mount container's root filesystem as read-only")
+	cmd.Flags().StringSliceVar(&s.DropCapabilities, "drop-capability", nil, "Drop Linux capability from container (format: NET_RAW or ALL) (can be specified multiple times)")
 
-func (i *defaultlessIntValue) Set(s string) error {
-	v, err := strconv.ParseInt(s, 0, 64)
-	if err != nil {
-		return err
-	}
-	val := int(v)
-	(*i.val) = &val
-	return nil
+	cmd.Flags().BoolVar(&s.ManagedRoute, "managed-route", true, "Custom route configuration")
+	cmd.Flags().BoolVar(&s.Preflight, "preflight", false, "Validate service with server-side dry run before deploying (requires Kubernetes 1.13+ and dry run capable admission webhooks)")
+	cmd.Flags().BoolVar(&s.GitMetadata, "git-metadata", true, "Annotate new revision with git commit, branch and author of source directory (if it's a git repository)")
+	cmd.Flags().BoolVar(&s.RequireApproval, "require-approval", false, "Keep traffic on current revision until new revision is approved via 'knctl approve'")
 }
 
-func (i *defaultlessIntValue) Type() string {
-	return "int"
-}
+// RequiredFeatures returns optional Knative features (keyed by flag name)
+// that may need to be enabled for specified flags to be accepted
+func (s DeployFlags) RequiredFeatures() map[string]string {
+	result := map[string]string{}
 
-func (i *defaultlessIntValue) String() string {
-	if i.val == nil || *i.val == nil {
-		return "unspecified"
+	if s.ReadOnlyRootFS {
+		result["read-only-root-fs"] = ctlknconfig.FeatureSecurityContext
+	}
+	if len(s.DropCapabilities) > 0 {
+		result["drop-capability"] = ctlknconfig.FeatureSecurityContext
 	}
-	return strconv.Itoa(int(**i.val))
+
+	return result
 }
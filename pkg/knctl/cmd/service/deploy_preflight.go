@@ -0,0 +1,221 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"fmt"
+	"strings"
+
+	cmdcore "github.com/cppforlife/knctl/pkg/knctl/cmd/core"
+	ctlknconfig "github.com/cppforlife/knctl/pkg/knctl/knativeconfig"
+	"github.com/knative/serving/pkg/apis/serving/v1alpha1"
+	servingclientset "github.com/knative/serving/pkg/client/clientset/versioned"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	// API server rejects dry run requests when any admission webhook
+	// that intercepts them does not declare sideEffects (e.g. Knative 0.2 webhook)
+	dryRunUnsupportedMsg = "does not support dry run"
+)
+
+type deployFieldFlag struct {
+	Field string
+	Flag  string
+	IsSet func(DeployFlags) bool
+}
+
+var (
+	// deployFieldFlags maps fields mentioned in validation errors to flags that populate them
+	deployFieldFlags = []deployFieldFlag{
+		{"securityContext.runAsUser", "run-as-user", func(f DeployFlags) bool { return f.RunAsUser != nil }},
+		{"securityContext.readOnlyRootFilesystem", "read-only-root-fs", func(f DeployFlags) bool { return f.ReadOnlyRootFS }},
+		{"securityContext.capabilities", "drop-capability", func(f DeployFlags) bool { return len(f.DropCapabilities) > 0 }},
+		{"securityContext", "run-as-user", func(f DeployFlags) bool { return f.RunAsUser != nil }},
+		{"securityContext", "read-only-root-fs", func(f DeployFlags) bool { return f.ReadOnlyRootFS }},
+		{"securityContext", "drop-capability", func(f DeployFlags) bool { return len(f.DropCapabilities) > 0 }},
+		{"container.image", "image", func(f DeployFlags) bool { return len(f.Image) > 0 }},
+		{"container.env", "env", func(f DeployFlags) bool { return len(f.EnvVars) > 0 }},
+		{"container.env", "env-secret", func(f DeployFlags) bool { return len(f.EnvSecrets) > 0 }},
+		{"container.env", "env-config-map", func(f DeployFlags) bool { return len(f.EnvConfigMaps) > 0 }},
+		{"containerConcurrency", "container-concurrency", func(f DeployFlags) bool { return f.ContainerConcurrency != nil }},
+		{"autoscaling.knative.dev/minScale", "min-scale", func(f DeployFlags) bool { return f.MinScale != nil }},
+		{"autoscaling.knative.dev/minScale", "startup-cpu-boost", func(f DeployFlags) bool { return f.StartupCPUBoost != nil }},
+		{"autoscaling.knative.dev/maxScale", "max-scale", func(f DeployFlags) bool { return f.MaxScale != nil }},
+		{"serviceAccountName", "service-account", func(f DeployFlags) bool {
+			return len(f.BuildCreateArgsFlags.ServiceAccountName) > 0
+		}},
+	}
+)
+
+// DeployPreflight submits generated service with server-side dry run
+// so that admission webhooks and CRD schema validate it without persisting it
+type DeployPreflight struct {
+	servingClient servingclientset.Interface
+	coreClient    kubernetes.Interface
+}
+
+func NewDeployPreflight(servingClient servingclientset.Interface, coreClient kubernetes.Interface) DeployPreflight {
+	return DeployPreflight{servingClient, coreClient}
+}
+
+// Validate returns false if server (or one of its admission webhooks) does not support dry run
+func (p DeployPreflight) Validate(serviceSpec ServiceSpec, deployFlags DeployFlags) (bool, error) {
+	supported, err := cmdcore.DryRunSupported(p.coreClient.Discovery())
+	if err != nil || !supported {
+		return false, nil
+	}
+
+	service, err := serviceSpec.Service()
+	if err != nil {
+		return false, err
+	}
+
+	err = p.dryRunService(service)
+	if err != nil {
+		if p.isDryRunUnsupported(err) {
+			return false, nil
+		}
+		return true, p.Explain(err, deployFlags)
+	}
+
+	// Manually routed services keep revision template in separate configuration
+	if serviceSpec.NeedsConfigurationUpdate() {
+		conf, err := serviceSpec.Configuration()
+		if err != nil {
+			return true, err
+		}
+
+		conf.ObjectMeta = metav1.ObjectMeta{Name: service.Name, Namespace: service.Namespace}
+
+		err = p.dryRunConfiguration(conf)
+		if err != nil {
+			if p.isDryRunUnsupported(err) {
+				return false, nil
+			}
+			return true, p.Explain(err, deployFlags)
+		}
+	}
+
+	return true, nil
+}
+
+func (p DeployPreflight) dryRunService(service v1alpha1.Service) error {
+	restClient := p.servingClient.ServingV1alpha1().RESTClient()
+	result := &v1alpha1.Service{}
+
+	err := restClient.Post().Namespace(service.Namespace).Resource("services").
+		Param("dryRun", "All").Body(&service).Do().Into(result)
+	if err == nil || !errors.IsAlreadyExists(err) {
+		return err
+	}
+
+	origService, err := p.servingClient.ServingV1alpha1().Services(service.Namespace).Get(service.Name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+
+	origService.Spec = service.Spec
+
+	if origService.Labels == nil {
+		origService.Labels = map[string]string{}
+	}
+	for k, v := range service.Labels {
+		origService.Labels[k] = v
+	}
+
+	return restClient.Put().Namespace(service.Namespace).Resource("services").Name(service.Name).
+		Param("dryRun", "All").Body(origService).Do().Into(result)
+}
+
+func (p DeployPreflight) dryRunConfiguration(conf v1alpha1.Configuration) error {
+	restClient := p.servingClient.ServingV1alpha1().RESTClient()
+	result := &v1alpha1.Configuration{}
+
+	err := restClient.Post().Namespace(conf.Namespace).Resource("configurations").
+		Param("dryRun", "All").Body(&conf).Do().Into(result)
+	if err == nil || !errors.IsAlreadyExists(err) {
+		return err
+	}
+
+	origConf, err := p.servingClient.ServingV1alpha1().Configurations(conf.Namespace).Get(conf.Name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+
+	conf.Spec.Generation = origConf.Spec.Generation
+	origConf.Spec = conf.Spec
+
+	return restClient.Put().Namespace(conf.Namespace).Resource("configurations").Name(conf.Name).
+		Param("dryRun", "All").Body(origConf).Do().Into(result)
+}
+
+// Explain converts validation error into a message that points at offending flags
+func (p DeployPreflight) Explain(err error, deployFlags DeployFlags) error {
+	msg := err.Error()
+
+	if statusErr, ok := err.(errors.APIStatus); ok {
+		msg = statusErr.Status().Message
+	}
+
+	var hints []string
+	seenFlags := map[string]struct{}{}
+	requiredFeatures := deployFlags.RequiredFeatures()
+
+	var disabledFeatures []ctlknconfig.Feature
+	if len(requiredFeatures) > 0 && p.coreClient != nil {
+		var featureNames []string
+		for _, name := range requiredFeatures {
+			featureNames = append(featureNames, name)
+		}
+		// Feature lookup is best effort
+		disabledFeatures, _ = ctlknconfig.NewFeatures(p.coreClient).Disabled(featureNames)
+	}
+
+	for _, fieldFlag := range deployFieldFlags {
+		if _, found := seenFlags[fieldFlag.Flag]; found {
+			continue
+		}
+		if !strings.Contains(msg, fieldFlag.Field) || !fieldFlag.IsSet(deployFlags) {
+			continue
+		}
+
+		seenFlags[fieldFlag.Flag] = struct{}{}
+		hint := "--" + fieldFlag.Flag
+
+		for _, feature := range disabledFeatures {
+			if requiredFeatures[fieldFlag.Flag] == feature.Name {
+				hint += fmt.Sprintf(" (requires feature '%s' which is disabled in config map '%s/%s')",
+					feature.Name, ctlknconfig.ServingNamespace, feature.ConfigMap)
+			}
+		}
+
+		hints = append(hints, hint)
+	}
+
+	if len(hints) == 0 {
+		return fmt.Errorf("Expected service to pass validation (dry run): %s", msg)
+	}
+
+	return fmt.Errorf("Expected service to pass validation (dry run): %s (check flags: %s)", msg, strings.Join(hints, ", "))
+}
+
+func (p DeployPreflight) isDryRunUnsupported(err error) bool {
+	return strings.Contains(err.Error(), dryRunUnsupportedMsg)
+}
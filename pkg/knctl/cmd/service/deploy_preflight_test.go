@@ -0,0 +1,133 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service_test
+
+import (
+	"strings"
+	"testing"
+
+	cmdcore "github.com/cppforlife/knctl/pkg/knctl/cmd/core"
+	cmdflags "github.com/cppforlife/knctl/pkg/knctl/cmd/flags"
+	. "github.com/cppforlife/knctl/pkg/knctl/cmd/service"
+	"github.com/cppforlife/knctl/pkg/knctl/testkit"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestDeployPreflightValidateDoesNotPersist(t *testing.T) {
+	cluster := testkit.NewCluster(t)
+	preflight := NewDeployPreflight(cluster.ServingClient(), cluster.CoreClient())
+
+	serviceFlags := cmdflags.ServiceFlags{cmdcore.NamespaceFlags{"ns1"}, "svc1"}
+	deployFlags := DeployFlags{Image: "img1", ManagedRoute: true}
+
+	validated, err := preflight.Validate(NewServiceSpec(serviceFlags, deployFlags), deployFlags)
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+	if !validated {
+		t.Fatalf("Expected dry run to be supported")
+	}
+
+	_, err = cluster.ServingClient().ServingV1alpha1().Services("ns1").Get("svc1", metav1.GetOptions{})
+	if !errors.IsNotFound(err) {
+		t.Fatalf("Expected service to not be created, but was: %v", err)
+	}
+}
+
+func TestDeployPreflightValidateSkipsWhenWebhookDoesNotSupportDryRun(t *testing.T) {
+	cluster := testkit.NewCluster(t)
+	cluster.RejectDryRuns(`admission webhook "webhook.serving.knative.dev" does not support dry run`)
+	preflight := NewDeployPreflight(cluster.ServingClient(), cluster.CoreClient())
+
+	serviceFlags := cmdflags.ServiceFlags{cmdcore.NamespaceFlags{"ns1"}, "svc1"}
+	deployFlags := DeployFlags{Image: "img1", ManagedRoute: true}
+
+	validated, err := preflight.Validate(NewServiceSpec(serviceFlags, deployFlags), deployFlags)
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+	if validated {
+		t.Fatalf("Expected dry run to be reported as unsupported")
+	}
+}
+
+func TestDeployPreflightValidateSkipsOnOldServer(t *testing.T) {
+	cluster := testkit.NewCluster(t)
+	cluster.SetMinorVersion("12")
+	preflight := NewDeployPreflight(cluster.ServingClient(), cluster.CoreClient())
+
+	serviceFlags := cmdflags.ServiceFlags{cmdcore.NamespaceFlags{"ns1"}, "svc1"}
+	deployFlags := DeployFlags{Image: "img1", ManagedRoute: true}
+
+	validated, err := preflight.Validate(NewServiceSpec(serviceFlags, deployFlags), deployFlags)
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+	if validated {
+		t.Fatalf("Expected dry run to be reported as unsupported")
+	}
+}
+
+func TestDeployPreflightValidateExistingService(t *testing.T) {
+	cluster := testkit.NewCluster(t, testkit.NewService("ns1", "svc1").Build())
+	preflight := NewDeployPreflight(cluster.ServingClient(), cluster.CoreClient())
+
+	serviceFlags := cmdflags.ServiceFlags{cmdcore.NamespaceFlags{"ns1"}, "svc1"}
+	deployFlags := DeployFlags{Image: "img1", ManagedRoute: true}
+
+	_, err := preflight.Validate(NewServiceSpec(serviceFlags, deployFlags), deployFlags)
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	service, err := cluster.ServingClient().ServingV1alpha1().Services("ns1").Get("svc1", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+	if service.Spec.RunLatest != nil && service.Spec.RunLatest.Configuration.RevisionTemplate.Spec.Container.Image == "img1" {
+		t.Fatalf("Expected service to not be updated")
+	}
+}
+
+func TestDeployPreflightExplainPointsAtFlags(t *testing.T) {
+	preflight := NewDeployPreflight(nil, testkit.NewCluster(t).CoreClient())
+
+	webhookErr := errors.NewBadRequest(`admission webhook "webhook.serving.knative.dev" denied the request: ` +
+		`validation failed: must not set the field(s): spec.runLatest.configuration.revisionTemplate.spec.container.securityContext.capabilities`)
+
+	err := preflight.Explain(webhookErr, DeployFlags{Image: "img1", DropCapabilities: []string{"ALL"}})
+
+	expectedErr := "Expected service to pass validation (dry run): " + webhookErr.Error() +
+		" (check flags: --drop-capability (requires feature 'kubernetes.podspec-securitycontext'" +
+		" which is disabled in config map 'knative-serving/config-features'))"
+
+	if err == nil || err.Error() != expectedErr {
+		t.Fatalf("Expected error to point at flag, but was: %v", err)
+	}
+}
+
+func TestDeployPreflightExplainWithoutMatchingFlags(t *testing.T) {
+	preflight := NewDeployPreflight(nil, testkit.NewCluster(t).CoreClient())
+
+	err := preflight.Explain(errors.NewBadRequest("validation failed: invalid value: 0: spec.generation"),
+		DeployFlags{Image: "img1"})
+
+	if err == nil || !strings.HasSuffix(err.Error(), "spec.generation") {
+		t.Fatalf("Expected error to include original message, but was: %v", err)
+	}
+}
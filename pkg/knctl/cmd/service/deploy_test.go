@@ -17,19 +17,24 @@ limitations under the License.
 package service_test
 
 import (
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/cppforlife/go-cli-ui/ui"
 	ctlbuild "github.com/cppforlife/knctl/pkg/knctl/build"
 	. "github.com/cppforlife/knctl/pkg/knctl/cmd"
 	cmdbld "github.com/cppforlife/knctl/pkg/knctl/cmd/build"
 	cmdcore "github.com/cppforlife/knctl/pkg/knctl/cmd/core"
 	cmdflags "github.com/cppforlife/knctl/pkg/knctl/cmd/flags"
 	. "github.com/cppforlife/knctl/pkg/knctl/cmd/service"
+	"github.com/cppforlife/knctl/pkg/knctl/testkit"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 func TestNewDeployCmd_Ok(t *testing.T) {
-	realCmd := NewDeployOptions(nil, cmdcore.NewConfigFactoryImpl(), cmdcore.NewDepsFactory())
+	realCmd := NewDeployOptions(nil, cmdcore.NewConfigFactoryImpl(), cmdcore.NewDepsFactory(), cmdcore.CancelSignals{})
 	cmd := NewTestCmd(t, NewDeployCmd(realCmd, cmdcore.FlagsFactory{}))
 	cmd.ExpectBasicConfig()
 	cmd.Execute([]string{
@@ -70,6 +75,7 @@ func TestNewDeployCmd_Ok(t *testing.T) {
 		WatchPodLogsIndefinitely: true,
 
 		ManagedRoute: true,
+		GitMetadata:  true,
 
 		TagFlags: cmdflags.TagFlags{
 			Tags: []string{"tag1", "tag2"},
@@ -81,7 +87,7 @@ func TestNewDeployCmd_Ok(t *testing.T) {
 }
 
 func TestNewDeployCmd_OkLongFlagNames(t *testing.T) {
-	realCmd := NewDeployOptions(nil, cmdcore.NewConfigFactoryImpl(), cmdcore.NewDepsFactory())
+	realCmd := NewDeployOptions(nil, cmdcore.NewConfigFactoryImpl(), cmdcore.NewDepsFactory(), cmdcore.CancelSignals{})
 	cmd := NewTestCmd(t, NewDeployCmd(realCmd, cmdcore.FlagsFactory{}))
 	cmd.Execute([]string{
 		"--namespace", "test-namespace",
@@ -99,6 +105,10 @@ func TestNewDeployCmd_OkLongFlagNames(t *testing.T) {
 		"--container-concurrency", "1",
 		"--min-scale", "10",
 		"--max-scale", "100",
+		"--run-as-user", "1000",
+		"--read-only-root-fs",
+		"--drop-capability", "NET_RAW",
+		"--drop-capability", "SYS_ADMIN",
 	})
 	cmd.ExpectReachesExecution()
 
@@ -108,6 +118,7 @@ func TestNewDeployCmd_OkLongFlagNames(t *testing.T) {
 	containerConcurrency := 1
 	minScale := 10
 	maxScale := 100
+	runAsUser := 1000
 
 	DeepEqual(t, realCmd.DeployFlags, DeployFlags{
 		BuildCreateArgsFlags: cmdbld.CreateArgsFlags{
@@ -125,6 +136,10 @@ func TestNewDeployCmd_OkLongFlagNames(t *testing.T) {
 		MinScale:             &minScale,
 		MaxScale:             &maxScale,
 
+		RunAsUser:        &runAsUser,
+		ReadOnlyRootFS:   true,
+		DropCapabilities: []string{"NET_RAW", "SYS_ADMIN"},
+
 		WatchRevisionReady:        true,
 		WatchRevisionReadyTimeout: 5 * time.Minute,
 
@@ -132,6 +147,7 @@ func TestNewDeployCmd_OkLongFlagNames(t *testing.T) {
 		WatchPodLogsIndefinitely: true,
 
 		ManagedRoute: true,
+		GitMetadata:  true,
 
 		TagFlags: cmdflags.TagFlags{
 			Tags: []string{"tag1", "tag2"},
@@ -143,7 +159,7 @@ func TestNewDeployCmd_OkLongFlagNames(t *testing.T) {
 }
 
 func TestNewDeployCmd_OkMinimum(t *testing.T) {
-	realCmd := NewDeployOptions(nil, cmdcore.NewConfigFactoryImpl(), cmdcore.NewDepsFactory())
+	realCmd := NewDeployOptions(nil, cmdcore.NewConfigFactoryImpl(), cmdcore.NewDepsFactory(), cmdcore.CancelSignals{})
 	cmd := NewTestCmd(t, NewDeployCmd(realCmd, cmdcore.FlagsFactory{}))
 	cmd.Execute([]string{
 		"--namespace", "test-namespace",
@@ -161,11 +177,35 @@ func TestNewDeployCmd_OkMinimum(t *testing.T) {
 		WatchRevisionReadyTimeout: 5 * time.Minute,
 		WatchPodLogs:              true,
 		ManagedRoute:              true,
+		GitMetadata:               true,
+	})
+}
+
+func TestNewDeployCmd_ImagePullSecrets(t *testing.T) {
+	realCmd := NewDeployOptions(nil, cmdcore.NewConfigFactoryImpl(), cmdcore.NewDepsFactory(), cmdcore.CancelSignals{})
+	cmd := NewTestCmd(t, NewDeployCmd(realCmd, cmdcore.FlagsFactory{}))
+	cmd.Execute([]string{
+		"--namespace", "test-namespace",
+		"--service", "test-service",
+		"--image", "test-image",
+		"--image-pull-secret", "secret1",
+		"--image-pull-secret", "secret2",
+	})
+	cmd.ExpectReachesExecution()
+
+	DeepEqual(t, realCmd.DeployFlags, DeployFlags{
+		Image:                     "test-image",
+		ImagePullSecrets:          []string{"secret1", "secret2"},
+		WatchRevisionReady:        true,
+		WatchRevisionReadyTimeout: 5 * time.Minute,
+		WatchPodLogs:              true,
+		ManagedRoute:              true,
+		GitMetadata:               true,
 	})
 }
 
 func TestNewDeployCmd_WatchingDisabled(t *testing.T) {
-	realCmd := NewDeployOptions(nil, cmdcore.NewConfigFactoryImpl(), cmdcore.NewDepsFactory())
+	realCmd := NewDeployOptions(nil, cmdcore.NewConfigFactoryImpl(), cmdcore.NewDepsFactory(), cmdcore.CancelSignals{})
 	cmd := NewTestCmd(t, NewDeployCmd(realCmd, cmdcore.FlagsFactory{}))
 	cmd.Execute([]string{
 		"--namespace", "test-namespace",
@@ -185,11 +225,12 @@ func TestNewDeployCmd_WatchingDisabled(t *testing.T) {
 		WatchRevisionReadyTimeout: 5 * time.Minute,
 		WatchPodLogs:              false,
 		ManagedRoute:              true,
+		GitMetadata:               true,
 	})
 }
 
 func TestNewDeployCmd_ManagedRouteDisabled(t *testing.T) {
-	realCmd := NewDeployOptions(nil, cmdcore.NewConfigFactoryImpl(), cmdcore.NewDepsFactory())
+	realCmd := NewDeployOptions(nil, cmdcore.NewConfigFactoryImpl(), cmdcore.NewDepsFactory(), cmdcore.CancelSignals{})
 	cmd := NewTestCmd(t, NewDeployCmd(realCmd, cmdcore.FlagsFactory{}))
 	cmd.Execute([]string{
 		"--namespace", "test-namespace",
@@ -208,11 +249,12 @@ func TestNewDeployCmd_ManagedRouteDisabled(t *testing.T) {
 		WatchRevisionReadyTimeout: 5 * time.Minute,
 		WatchPodLogs:              true,
 		ManagedRoute:              false,
+		GitMetadata:               true,
 	})
 }
 
 func TestNewDeployCmd_EnvValueWithCommas(t *testing.T) {
-	realCmd := NewDeployOptions(nil, cmdcore.NewConfigFactoryImpl(), cmdcore.NewDepsFactory())
+	realCmd := NewDeployOptions(nil, cmdcore.NewConfigFactoryImpl(), cmdcore.NewDepsFactory(), cmdcore.CancelSignals{})
 	cmd := NewTestCmd(t, NewDeployCmd(realCmd, cmdcore.FlagsFactory{}))
 	cmd.Execute([]string{
 		"--namespace", "test-namespace",
@@ -233,12 +275,70 @@ func TestNewDeployCmd_EnvValueWithCommas(t *testing.T) {
 		WatchRevisionReadyTimeout: 5 * time.Minute,
 		WatchPodLogs:              true,
 		ManagedRoute:              true,
+		GitMetadata:               true,
+	})
+}
+
+func TestNewDeployCmd_NotifyFlags(t *testing.T) {
+	realCmd := NewDeployOptions(nil, cmdcore.NewConfigFactoryImpl(), cmdcore.NewDepsFactory(), cmdcore.CancelSignals{})
+	cmd := NewTestCmd(t, NewDeployCmd(realCmd, cmdcore.FlagsFactory{}))
+	cmd.Execute([]string{
+		"-n", "test-namespace",
+		"-s", "test-service",
+		"--image", "test-image",
+		"--notify-url", "https://hooks.example.com/a",
+		"--notify-url", "https://hooks.example.com/b",
+		"--notify-slack-webhook", "https://hooks.slack.com/services/x",
+	})
+	cmd.ExpectReachesExecution()
+
+	DeepEqual(t, realCmd.NotifyFlags, cmdflags.NotifyFlags{
+		URLs:          []string{"https://hooks.example.com/a", "https://hooks.example.com/b"},
+		SlackWebhooks: []string{"https://hooks.slack.com/services/x"},
+	})
+}
+
+func TestNewDeployCmd_ScanFlags(t *testing.T) {
+	realCmd := NewDeployOptions(nil, cmdcore.NewConfigFactoryImpl(), cmdcore.NewDepsFactory(), cmdcore.CancelSignals{})
+	cmd := NewTestCmd(t, NewDeployCmd(realCmd, cmdcore.FlagsFactory{}))
+	cmd.Execute([]string{
+		"-n", "test-namespace",
+		"-s", "test-service",
+		"--image", "test-image",
+		"--scan",
+		"--scanner-url", "https://scanner.example.com/report",
+		"--fail-on", "critical",
+	})
+	cmd.ExpectReachesExecution()
+
+	DeepEqual(t, realCmd.ScanFlags, cmdflags.ScanFlags{
+		Scan:       true,
+		ScannerURL: "https://scanner.example.com/report",
+		FailOn:     "critical",
 	})
 }
 
 func TestNewDeployCmd_RequiredFlags(t *testing.T) {
-	realCmd := NewDeployOptions(nil, cmdcore.NewConfigFactoryImpl(), cmdcore.NewDepsFactory())
+	realCmd := NewDeployOptions(nil, cmdcore.NewConfigFactoryImpl(), cmdcore.NewDepsFactory(), cmdcore.CancelSignals{})
 	cmd := NewTestCmd(t, NewDeployCmd(realCmd, cmdcore.FlagsFactory{}))
 	cmd.Execute([]string{})
 	cmd.ExpectRequiredFlags([]string{"image", "service"})
 }
+
+func TestDeployOptions_RequiresSignatureVerificationInAnnotatedNamespace(t *testing.T) {
+	cluster := testkit.NewCluster(t, &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "ns1",
+			Annotations: map[string]string{"cli.knative.dev/require-image-signature": "true"},
+		},
+	})
+
+	opts := NewDeployOptions(ui.NewNoopUI(), cluster.ConfigFactory(), cluster.DepsFactory(), cmdcore.CancelSignals{})
+	opts.ServiceFlags = cmdflags.ServiceFlags{cmdcore.NamespaceFlags{"ns1"}, "svc1"}
+	opts.DeployFlags.Image = "gcr.io/proj/app"
+
+	err := opts.Run()
+	if err == nil || !strings.Contains(err.Error(), "Expected --verify-signature to be specified") {
+		t.Fatalf("Expected signature verification to be required, but was: %v", err)
+	}
+}
@@ -0,0 +1,201 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/cppforlife/go-cli-ui/ui"
+	cmdcore "github.com/cppforlife/knctl/pkg/knctl/cmd/core"
+	cmdflags "github.com/cppforlife/knctl/pkg/knctl/cmd/flags"
+	"github.com/cppforlife/knctl/pkg/knctl/logs"
+	ctlservice "github.com/cppforlife/knctl/pkg/knctl/service"
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type DevOptions struct {
+	ui            ui.UI
+	configFactory cmdcore.ConfigFactory
+	depsFactory   cmdcore.DepsFactory
+	cancelSignals cmdcore.CancelWatcher
+
+	ServiceFlags cmdflags.ServiceFlags
+	DeployFlags  DeployFlags
+	RedactFlags  cmdflags.RedactFlags
+
+	Builder      string
+	PollInterval time.Duration
+}
+
+func NewDevOptions(ui ui.UI, configFactory cmdcore.ConfigFactory, depsFactory cmdcore.DepsFactory, cancelSignals cmdcore.CancelWatcher) *DevOptions {
+	return &DevOptions{ui: ui, configFactory: configFactory, depsFactory: depsFactory, cancelSignals: cancelSignals}
+}
+
+func NewDevCmd(o *DevOptions, flagsFactory cmdcore.FlagsFactory) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "dev",
+		Short: "Continuously build and deploy service from local source code",
+		Long: `Continuously build and deploy service from local source code.
+
+Watches source directory for changes, rebuilds image (locally via Docker or
+Cloud Native Buildpacks, or in the cluster via Knative Build) and redeploys service.
+Logs of service pods are printed until command is stopped.
+
+Accepts same flags as 'knctl deploy'.`,
+		Example: `
+  # Build with Docker and redeploy service 'srv1' on every change in namespace 'ns1'
+  knctl dev -s srv1 -d . --image index.docker.io/your-account/your-image -n ns1
+
+  # Build with buildpacks instead of Dockerfile
+  knctl dev -s srv1 -d . --image index.docker.io/your-account/your-image --builder pack -n ns1
+
+  # Build in the cluster with custom build template
+  knctl dev -s srv1 -d . --image index.docker.io/your-account/your-image --builder cluster \
+      --template buildpack --service-account serv-acct1 -n ns1`,
+		Annotations: map[string]string{
+			cmdcore.BasicHelpGroup.Key: cmdcore.BasicHelpGroup.Value,
+		},
+		RunE: func(_ *cobra.Command, _ []string) error { return o.Run() },
+	}
+	o.ServiceFlags.Set(cmd, flagsFactory)
+	o.DeployFlags.Set(cmd, flagsFactory)
+	o.RedactFlags.Set(cmd, flagsFactory)
+	cmd.Flags().StringVar(&o.Builder, "builder", DevBuilderDocker, "Set builder ("+strings.Join(DevBuilders, ", ")+")")
+	cmd.Flags().DurationVar(&o.PollInterval, "poll-interval", 1*time.Second, "Set interval for checking source directory for changes")
+	cmd.MarkFlagRequired("directory")
+	return cmd
+}
+
+func (o *DevOptions) Run() error {
+	dir := o.DeployFlags.BuildCreateArgsFlags.SourceDirectory
+	builder := NewDevBuilder(o.Builder, dir, o.DeployFlags.Image, o.ui)
+
+	err := builder.Validate()
+	if err != nil {
+		return err
+	}
+
+	if o.DeployFlags.GenerateNameFlags.GenerateName {
+		return fmt.Errorf("Expected service name to not be generated since service is redeployed")
+	}
+
+	cancelCh := make(chan struct{})
+
+	o.cancelSignals.Watch(func() {
+		close(cancelCh)
+	})
+
+	snapshot, err := NewDevSnapshot(dir)
+	if err != nil {
+		return fmt.Errorf("Checking source directory '%s': %s", dir, err)
+	}
+
+	err = o.deploy(builder)
+	if err != nil {
+		return err
+	}
+
+	logsErrCh := make(chan error, 1)
+
+	go func() {
+		logsErrCh <- o.showLogs(cancelCh)
+	}()
+
+	o.ui.PrintLinef("Watching directory '%s' for changes...", dir)
+
+	for {
+		select {
+		case <-cancelCh:
+			return <-logsErrCh
+
+		case err := <-logsErrCh:
+			return err
+
+		case <-time.After(o.PollInterval):
+			newSnapshot, err := NewDevSnapshot(dir)
+			if err != nil {
+				o.ui.ErrorLinef("Checking source directory '%s': %s", dir, err)
+				continue
+			}
+
+			changes := newSnapshot.Changes(snapshot)
+			if len(changes) == 0 {
+				continue
+			}
+
+			snapshot = newSnapshot
+
+			o.ui.PrintLinef("Detected changes in %d file(s) (e.g. '%s'); redeploying...", len(changes), changes[0])
+
+			// Failed builds or deploys are reported, but do not stop watching
+			err = o.deploy(builder)
+			if err != nil {
+				o.ui.ErrorLinef("%s", err)
+			}
+		}
+	}
+}
+
+func (o *DevOptions) deploy(builder DevBuilder) error {
+	deployFlags := o.DeployFlags
+
+	// Logs are streamed for the whole service (not just new revision)
+	deployFlags.WatchPodLogs = false
+	deployFlags.WatchPodLogsIndefinitely = false
+
+	if builder.IsLocal() {
+		err := builder.Build()
+		if err != nil {
+			return err
+		}
+
+		// Locally built image is referenced directly
+		deployFlags.BuildCreateArgsFlags.SourceDirectory = ""
+	}
+
+	deployOpts := NewDeployOptions(o.ui, o.configFactory, o.depsFactory, o.cancelSignals)
+	deployOpts.ServiceFlags = o.ServiceFlags
+	deployOpts.DeployFlags = deployFlags
+	deployOpts.RedactFlags = o.RedactFlags
+
+	return deployOpts.Run()
+}
+
+func (o *DevOptions) showLogs(cancelCh chan struct{}) error {
+	servingClient, err := o.depsFactory.ServingClient()
+	if err != nil {
+		return err
+	}
+
+	coreClient, err := o.depsFactory.CoreClient()
+	if err != nil {
+		return err
+	}
+
+	service, err := servingClient.ServingV1alpha1().Services(o.ServiceFlags.NamespaceFlags.Name).Get(o.ServiceFlags.Name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+
+	tailOpts := logs.PodLogOpts{Follow: true}
+	podWatcher := ctlservice.NewServicePodWatcher(service, servingClient, coreClient, o.ui)
+
+	return logs.NewView(tailOpts, podWatcher, coreClient, o.ui, o.RedactFlags.ShowSecrets).Show(cancelCh)
+}
@@ -0,0 +1,102 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/cppforlife/go-cli-ui/ui"
+)
+
+const (
+	DevBuilderDocker  = "docker"
+	DevBuilderPack    = "pack"
+	DevBuilderCluster = "cluster"
+)
+
+var (
+	DevBuilders = []string{DevBuilderDocker, DevBuilderPack, DevBuilderCluster}
+)
+
+// DevBuilder builds and pushes image from local source code.
+// Cluster builder does not build locally; source is uploaded during deploy instead.
+type DevBuilder struct {
+	Builder   string
+	Directory string
+	Image     string
+
+	ui ui.UI
+}
+
+func NewDevBuilder(builder, directory, image string, ui ui.UI) DevBuilder {
+	return DevBuilder{Builder: builder, Directory: directory, Image: image, ui: ui}
+}
+
+func (b DevBuilder) Validate() error {
+	for _, builder := range DevBuilders {
+		if b.Builder == builder {
+			return nil
+		}
+	}
+	return fmt.Errorf("Expected builder to be one of: %s", strings.Join(DevBuilders, ", "))
+}
+
+func (b DevBuilder) IsLocal() bool { return b.Builder != DevBuilderCluster }
+
+func (b DevBuilder) Build() error {
+	for _, args := range b.commands() {
+		b.ui.PrintLinef("Running '%s'", strings.Join(args, " "))
+
+		cmd := exec.Command(args[0], args[1:]...)
+		cmd.Stdout = uiBlockWriter{b.ui}
+		cmd.Stderr = uiBlockWriter{b.ui}
+
+		err := cmd.Run()
+		if err != nil {
+			return fmt.Errorf("Running '%s': %s", args[0], err)
+		}
+	}
+
+	return nil
+}
+
+func (b DevBuilder) commands() [][]string {
+	switch b.Builder {
+	case DevBuilderDocker:
+		return [][]string{
+			{"docker", "build", "-t", b.Image, b.Directory},
+			{"docker", "push", b.Image},
+		}
+	case DevBuilderPack:
+		return [][]string{
+			{"pack", "build", b.Image, "--path", b.Directory, "--publish"},
+		}
+	default:
+		return nil
+	}
+}
+
+type uiBlockWriter struct {
+	ui ui.UI
+}
+
+func (w uiBlockWriter) Write(p []byte) (int, error) {
+	w.ui.PrintBlock(p)
+	return len(p), nil
+}
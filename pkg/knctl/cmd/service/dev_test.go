@@ -0,0 +1,126 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	ctlbuild "github.com/cppforlife/knctl/pkg/knctl/build"
+	. "github.com/cppforlife/knctl/pkg/knctl/cmd"
+	cmdbld "github.com/cppforlife/knctl/pkg/knctl/cmd/build"
+	cmdcore "github.com/cppforlife/knctl/pkg/knctl/cmd/core"
+	cmdflags "github.com/cppforlife/knctl/pkg/knctl/cmd/flags"
+	. "github.com/cppforlife/knctl/pkg/knctl/cmd/service"
+)
+
+func TestNewDevCmd_Ok(t *testing.T) {
+	realCmd := NewDevOptions(nil, cmdcore.NewConfigFactoryImpl(), cmdcore.NewDepsFactory(), cmdcore.CancelSignals{})
+	cmd := NewTestCmd(t, NewDevCmd(realCmd, cmdcore.FlagsFactory{}))
+	cmd.ExpectBasicConfig()
+	cmd.Execute([]string{
+		"-n", "test-namespace",
+		"-s", "test-service",
+		"-d", "test-dir",
+		"-i", "test-image",
+		"--builder", "pack",
+		"--poll-interval", "2s",
+	})
+	cmd.ExpectReachesExecution()
+
+	DeepEqual(t, realCmd.ServiceFlags,
+		cmdflags.ServiceFlags{cmdcore.NamespaceFlags{"test-namespace"}, "test-service"})
+
+	DeepEqual(t, realCmd.DeployFlags.BuildCreateArgsFlags,
+		cmdbld.CreateArgsFlags{ctlbuild.BuildSpecOpts{SourceDirectory: "test-dir"}})
+
+	DeepEqual(t, realCmd.DeployFlags.Image, "test-image")
+	DeepEqual(t, realCmd.Builder, "pack")
+	DeepEqual(t, realCmd.PollInterval, 2*time.Second)
+}
+
+func TestNewDevCmd_RequiredFlags(t *testing.T) {
+	realCmd := NewDevOptions(nil, cmdcore.NewConfigFactoryImpl(), cmdcore.NewDepsFactory(), cmdcore.CancelSignals{})
+	cmd := NewTestCmd(t, NewDevCmd(realCmd, cmdcore.FlagsFactory{}))
+	cmd.Execute([]string{})
+	cmd.ExpectRequiredFlags([]string{"directory", "image", "service"})
+}
+
+func TestDevBuilderValidate(t *testing.T) {
+	for _, builder := range []string{"docker", "pack", "cluster"} {
+		err := NewDevBuilder(builder, ".", "img", nil).Validate()
+		if err != nil {
+			t.Fatalf("Expected builder '%s' to be valid: %s", builder, err)
+		}
+	}
+
+	err := NewDevBuilder("kaniko", ".", "img", nil).Validate()
+	if err == nil || err.Error() != "Expected builder to be one of: docker, pack, cluster" {
+		t.Fatalf("Expected unknown builder error, but was: %v", err)
+	}
+}
+
+func TestDevSnapshotChanges(t *testing.T) {
+	dir := t.TempDir()
+
+	writeFile := func(path, content string) {
+		err := os.MkdirAll(filepath.Dir(filepath.Join(dir, path)), 0755)
+		if err == nil {
+			err = ioutil.WriteFile(filepath.Join(dir, path), []byte(content), 0644)
+		}
+		if err != nil {
+			t.Fatalf("Writing file: %s", err)
+		}
+	}
+
+	writeFile("main.go", "v1")
+	writeFile("old.go", "v1")
+	writeFile(".git/HEAD", "v1")
+
+	snapshot1, err := NewDevSnapshot(dir)
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	writeFile("main.go", "v2-longer")
+	writeFile("new.go", "v1")
+	writeFile(".git/HEAD", "v2-longer")
+	os.Remove(filepath.Join(dir, "old.go"))
+
+	snapshot2, err := NewDevSnapshot(dir)
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	expectedChanges := []string{
+		filepath.Join(dir, "main.go"),
+		filepath.Join(dir, "new.go"),
+		filepath.Join(dir, "old.go"),
+	}
+
+	if changes := snapshot2.Changes(snapshot1); !reflect.DeepEqual(changes, expectedChanges) {
+		t.Fatalf("Expected changes to match: %#v", changes)
+	}
+
+	if changes := snapshot2.Changes(snapshot2); len(changes) != 0 {
+		t.Fatalf("Expected no changes: %#v", changes)
+	}
+}
@@ -0,0 +1,82 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+var (
+	// devIgnoredDirs are not watched since they are modified by tools
+	// or contain dependencies that do not affect built image
+	devIgnoredDirs = map[string]struct{}{".git": {}, "node_modules": {}, "__pycache__": {}, ".venv": {}}
+)
+
+type devFileState struct {
+	ModTime time.Time
+	Size    int64
+}
+
+// DevSnapshot captures modification state of files in a directory
+type DevSnapshot map[string]devFileState
+
+func NewDevSnapshot(dir string) (DevSnapshot, error) {
+	snapshot := DevSnapshot{}
+
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if info.IsDir() {
+			if _, found := devIgnoredDirs[info.Name()]; found && path != dir {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		snapshot[path] = devFileState{ModTime: info.ModTime(), Size: info.Size()}
+
+		return nil
+	})
+
+	return snapshot, err
+}
+
+// Changes returns sorted paths of files that were added, modified or removed since previous snapshot
+func (s DevSnapshot) Changes(prev DevSnapshot) []string {
+	var result []string
+
+	for path, state := range s {
+		if prevState, found := prev[path]; !found || prevState != state {
+			result = append(result, path)
+		}
+	}
+
+	for path := range prev {
+		if _, found := s[path]; !found {
+			result = append(result, path)
+		}
+	}
+
+	sort.Strings(result)
+
+	return result
+}
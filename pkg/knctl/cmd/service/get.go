@@ -0,0 +1,198 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"fmt"
+
+	"github.com/cppforlife/go-cli-ui/ui"
+	uitable "github.com/cppforlife/go-cli-ui/ui/table"
+	cmdbld "github.com/cppforlife/knctl/pkg/knctl/cmd/build"
+	cmdcore "github.com/cppforlife/knctl/pkg/knctl/cmd/core"
+	cmdflags "github.com/cppforlife/knctl/pkg/knctl/cmd/flags"
+	cmdrev "github.com/cppforlife/knctl/pkg/knctl/cmd/revision"
+	ctlservice "github.com/cppforlife/knctl/pkg/knctl/service"
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+)
+
+type GetOptions struct {
+	ui          ui.UI
+	depsFactory cmdcore.DepsFactory
+
+	ServiceFlags cmdflags.ServiceFlags
+}
+
+func NewGetOptions(ui ui.UI, depsFactory cmdcore.DepsFactory) *GetOptions {
+	return &GetOptions{ui: ui, depsFactory: depsFactory}
+}
+
+func NewGetCmd(o *GetOptions, flagsFactory cmdcore.FlagsFactory) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "get NAME",
+		Short: "Show overview of service",
+		Long: `Show overview of service: its URL and conditions, latest and routed revisions
+with their traffic, recent builds and active pods.`,
+		Example: `
+  # Show overview of service 'svc1' in namespace 'ns1'
+  knctl get svc1 -n ns1`,
+		Args: cobra.ExactArgs(1),
+		Annotations: map[string]string{
+			cmdcore.BasicHelpGroup.Key: cmdcore.BasicHelpGroup.Value,
+		},
+		RunE: func(_ *cobra.Command, args []string) error {
+			o.ServiceFlags.Name = args[0]
+			return o.Run()
+		},
+	}
+	o.ServiceFlags.NamespaceFlags.Set(cmd, flagsFactory)
+	return cmd
+}
+
+func (o *GetOptions) Run() error {
+	servingClient, err := o.depsFactory.ServingClient()
+	if err != nil {
+		return err
+	}
+
+	buildClient, err := o.depsFactory.BuildClient()
+	if err != nil {
+		return err
+	}
+
+	coreClient, err := o.depsFactory.CoreClient()
+	if err != nil {
+		return err
+	}
+
+	overview, err := NewServiceOverviews(servingClient, buildClient).Get(
+		o.ServiceFlags.NamespaceFlags.Name, o.ServiceFlags.Name)
+	if err != nil {
+		return err
+	}
+
+	o.printService(overview)
+
+	cmdcore.NewConditionsTable(overview.Service.Status.Conditions).Print(o.ui)
+
+	o.printRevisions(overview, ctlservice.NewTags(servingClient))
+	o.printBuilds(overview)
+
+	podsToWatchCh := make(chan corev1.Pod)
+	cancelCh := make(chan struct{})
+	close(cancelCh) // Close immediately for just plain listing of pods
+
+	watcher := ctlservice.NewServicePodWatcher(overview.Service, servingClient, coreClient, o.ui)
+
+	go func() {
+		watcher.Watch(podsToWatchCh, cancelCh)
+		close(podsToWatchCh)
+	}()
+
+	cmdrev.NewPodConditionsTable(podsToWatchCh).Print(o.ui)
+
+	return nil
+}
+
+func (o *GetOptions) printService(overview ServiceOverview) {
+	service := overview.Service
+
+	table := uitable.Table{
+		Title: fmt.Sprintf("Service '%s'", service.Name),
+
+		Header: []uitable.Header{
+			uitable.NewHeader("Name"),
+			uitable.NewHeader("URL"),
+			uitable.NewHeader("Internal URL"),
+			uitable.NewHeader("Latest created revision"),
+			uitable.NewHeader("Latest ready revision"),
+			uitable.NewHeader("Conditions"),
+			uitable.NewHeader("Age"),
+		},
+
+		Transpose: true,
+	}
+
+	table.Rows = append(table.Rows, []uitable.Value{
+		uitable.NewValueString(service.Name),
+		uitable.NewValueString(o.url(service.Status.Domain)),
+		uitable.NewValueString(o.url(service.Status.DomainInternal)),
+		uitable.NewValueString(service.Status.LatestCreatedRevisionName),
+		uitable.NewValueString(service.Status.LatestReadyRevisionName),
+		cmdcore.NewConditionsValue(service.Status.Conditions),
+		cmdcore.NewValueAge(service.CreationTimestamp.Time),
+	})
+
+	o.ui.PrintTable(table)
+}
+
+func (o *GetOptions) printRevisions(overview ServiceOverview, tags ctlservice.Tags) {
+	table := uitable.Table{
+		Title:   "Latest and routed revisions",
+		Content: "revisions",
+
+		Header: []uitable.Header{
+			uitable.NewHeader("Name"),
+			uitable.NewHeader("Tags"),
+			uitable.NewHeader("Conditions"),
+			uitable.NewHeader("Age"),
+			uitable.NewHeader("Traffic"),
+		},
+	}
+
+	for _, rev := range overview.Revisions {
+		table.Rows = append(table.Rows, []uitable.Value{
+			uitable.NewValueString(rev.Name),
+			uitable.NewValueStrings(tags.List(rev)),
+			cmdcore.NewConditionsValue(rev.Status.Conditions),
+			cmdcore.NewValueAge(rev.CreationTimestamp.Time),
+			cmdrev.NewTrafficValue(rev, overview.Routes),
+		})
+	}
+
+	o.ui.PrintTable(table)
+}
+
+func (o *GetOptions) printBuilds(overview ServiceOverview) {
+	table := uitable.Table{
+		Title:   "Recent builds",
+		Content: "builds",
+
+		Header: []uitable.Header{
+			uitable.NewHeader("Name"),
+			uitable.NewHeader("Succeeded"),
+			uitable.NewHeader("Age"),
+		},
+	}
+
+	for _, build := range overview.Builds {
+		table.Rows = append(table.Rows, []uitable.Value{
+			uitable.NewValueString(build.Name),
+			cmdbld.NewBuildSucceededValue(build),
+			cmdcore.NewValueAge(build.CreationTimestamp.Time),
+		})
+	}
+
+	o.ui.PrintTable(table)
+}
+
+func (GetOptions) url(domain string) string {
+	if len(domain) == 0 {
+		return ""
+	}
+	return "http://" + domain
+}
@@ -0,0 +1,124 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cppforlife/go-cli-ui/ui"
+	uitable "github.com/cppforlife/go-cli-ui/ui/table"
+	cmdcore "github.com/cppforlife/knctl/pkg/knctl/cmd/core"
+	cmdflags "github.com/cppforlife/knctl/pkg/knctl/cmd/flags"
+	ctlhistory "github.com/cppforlife/knctl/pkg/knctl/history"
+	"github.com/knative/serving/pkg/apis/serving/v1alpha1"
+	"github.com/spf13/cobra"
+)
+
+type HistoryOptions struct {
+	ui          ui.UI
+	depsFactory cmdcore.DepsFactory
+
+	ServiceFlags cmdflags.ServiceFlags
+}
+
+func NewHistoryOptions(ui ui.UI, depsFactory cmdcore.DepsFactory) *HistoryOptions {
+	return &HistoryOptions{ui: ui, depsFactory: depsFactory}
+}
+
+func NewHistoryCmd(o *HistoryOptions, flagsFactory cmdcore.FlagsFactory) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "history",
+		Short: "Show deploy and rollout history of service",
+		Long: `Show deploy and rollout history of service.
+
+History is recorded by 'knctl deploy', 'knctl rollout' and 'knctl undo' commands
+in a config map (named 'knctl-history-<service>') in service's namespace.
+Up to 100 most recent entries are kept.`,
+		Example: `
+  # Show history of service 'svc1' in namespace 'ns1'
+  knctl history -s svc1 -n ns1`,
+		Annotations: map[string]string{
+			cmdcore.BasicHelpGroup.Key: cmdcore.BasicHelpGroup.Value,
+		},
+		RunE: func(_ *cobra.Command, _ []string) error { return o.Run() },
+	}
+	o.ServiceFlags.Set(cmd, flagsFactory)
+	return cmd
+}
+
+func (o *HistoryOptions) Run() error {
+	coreClient, err := o.depsFactory.CoreClient()
+	if err != nil {
+		return err
+	}
+
+	entries, err := ctlhistory.NewHistory(coreClient).List(o.ServiceFlags.NamespaceFlags.Name, o.ServiceFlags.Name)
+	if err != nil {
+		return err
+	}
+
+	table := uitable.Table{
+		Title:   fmt.Sprintf("History of service '%s'", o.ServiceFlags.Name),
+		Content: "entries",
+
+		Header: []uitable.Header{
+			uitable.NewHeader("Time"),
+			uitable.NewHeader("User"),
+			uitable.NewHeader("Operation"),
+			uitable.NewHeader("Revision"),
+			uitable.NewHeader("Image"),
+			uitable.NewHeader("Traffic"),
+			uitable.NewHeader("Args"),
+		},
+	}
+
+	for _, entry := range entries {
+		image := entry.ImageDigest
+		if len(image) == 0 {
+			image = entry.Image
+		}
+
+		table.Rows = append(table.Rows, []uitable.Value{
+			uitable.NewValueTime(entry.Time),
+			uitable.NewValueString(entry.User),
+			uitable.NewValueString(string(entry.Operation)),
+			uitable.NewValueString(entry.Revision),
+			uitable.NewValueString(image),
+			uitable.NewValueStrings(trafficTargetLines(entry.Traffic)),
+			uitable.NewValueString(strings.Join(entry.Args, " ")),
+		})
+	}
+
+	o.ui.PrintTable(table)
+
+	return nil
+}
+
+func trafficTargetLines(targets []v1alpha1.TrafficTarget) []string {
+	var lines []string
+
+	for _, target := range targets {
+		name := target.RevisionName
+		if len(name) == 0 {
+			name = "service:" + target.ConfigurationName
+		}
+		lines = append(lines, fmt.Sprintf("%d%% -> %s", target.Percent, name))
+	}
+
+	return lines
+}
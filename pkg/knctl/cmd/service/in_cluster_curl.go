@@ -0,0 +1,143 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/cppforlife/go-cli-ui/ui"
+	ctlkube "github.com/cppforlife/knctl/pkg/knctl/kube"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+)
+
+const (
+	inClusterCurlContainer = "curl"
+	inClusterCurlLabelKey  = "cli.knative.dev/curl"
+)
+
+// InClusterCurl runs curl from within the cluster, either
+// in a short-lived pod or in an existing (debug) pod
+type InClusterCurl struct {
+	coreClient kubernetes.Interface
+	restConfig *rest.Config
+	ui         ui.UI
+}
+
+func NewInClusterCurl(coreClient kubernetes.Interface, restConfig *rest.Config, ui ui.UI) InClusterCurl {
+	return InClusterCurl{coreClient, restConfig, ui}
+}
+
+// NewInClusterCurlPod returns pod that runs curl once. Istio sidecar
+// is not injected since it would prevent pod from ever completing.
+func NewInClusterCurlPod(namespace, image string, args []string) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: "knctl-curl-",
+			Namespace:    namespace,
+			Labels: map[string]string{
+				inClusterCurlLabelKey: "true",
+			},
+			Annotations: map[string]string{
+				"sidecar.istio.io/inject": "false",
+			},
+		},
+		Spec: corev1.PodSpec{
+			RestartPolicy: corev1.RestartPolicyNever,
+			Containers: []corev1.Container{{
+				Name:    inClusterCurlContainer,
+				Image:   image,
+				Command: []string{"curl"},
+				Args:    args,
+			}},
+		},
+	}
+}
+
+func (c InClusterCurl) RunPod(namespace, image string, args []string, timeout time.Duration) ([]byte, error) {
+	podsClient := c.coreClient.CoreV1().Pods(namespace)
+
+	pod, err := podsClient.Create(NewInClusterCurlPod(namespace, image, args))
+	if err != nil {
+		return nil, fmt.Errorf("Creating curl pod: %s", err)
+	}
+
+	c.ui.PrintLinef("Started pod '%s'", pod.Name)
+
+	defer func() {
+		err := podsClient.Delete(pod.Name, &metav1.DeleteOptions{})
+		if err != nil {
+			c.ui.PrintLinef("Failed to delete pod '%s': %s", pod.Name, err)
+		}
+	}()
+
+	phase, err := c.waitForCompletion(pod, timeout)
+	if err != nil {
+		return nil, err
+	}
+
+	out, err := podsClient.GetLogs(pod.Name, &corev1.PodLogOptions{Container: inClusterCurlContainer}).DoRaw()
+	if err != nil {
+		return nil, fmt.Errorf("Getting curl pod logs: %s", err)
+	}
+
+	if phase == corev1.PodFailed {
+		return out, fmt.Errorf("Expected curl pod to succeed, but it failed")
+	}
+
+	return out, nil
+}
+
+func (c InClusterCurl) RunInPod(namespace, podName, container string, args []string) ([]byte, error) {
+	pod, err := c.coreClient.CoreV1().Pods(namespace).Get(podName, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("Getting pod '%s': %s", podName, err)
+	}
+
+	out, err := ctlkube.NewExec(*pod, container, c.coreClient, c.restConfig).Output(append([]string{"curl"}, args...))
+	if err != nil {
+		return nil, fmt.Errorf("Running curl in pod '%s': %s", podName, err)
+	}
+
+	return out, nil
+}
+
+func (c InClusterCurl) waitForCompletion(pod *corev1.Pod, timeout time.Duration) (corev1.PodPhase, error) {
+	podsClient := c.coreClient.CoreV1().Pods(pod.Namespace)
+	deadline := time.Now().Add(timeout)
+
+	for {
+		currPod, err := podsClient.Get(pod.Name, metav1.GetOptions{})
+		if err != nil {
+			return "", fmt.Errorf("Getting curl pod: %s", err)
+		}
+
+		switch currPod.Status.Phase {
+		case corev1.PodSucceeded, corev1.PodFailed:
+			return currPod.Status.Phase, nil
+		}
+
+		if time.Now().After(deadline) {
+			return "", fmt.Errorf("Timed out waiting for curl pod '%s' to complete", pod.Name)
+		}
+
+		time.Sleep(1 * time.Second)
+	}
+}
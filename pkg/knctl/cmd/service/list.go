@@ -18,23 +18,28 @@ package service
 
 import (
 	"fmt"
-	cmdcore "github.com/cppforlife/knctl/pkg/knctl/cmd/core"
+	"strings"
 
 	"github.com/cppforlife/go-cli-ui/ui"
 	uitable "github.com/cppforlife/go-cli-ui/ui/table"
+	cmdcore "github.com/cppforlife/knctl/pkg/knctl/cmd/core"
+	"github.com/cppforlife/knctl/pkg/knctl/util"
+	"github.com/knative/serving/pkg/apis/serving/v1alpha1"
 	"github.com/spf13/cobra"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 type ListOptions struct {
-	ui          ui.UI
-	depsFactory cmdcore.DepsFactory
+	ui            ui.UI
+	configFactory cmdcore.ConfigFactory
+	depsFactory   cmdcore.DepsFactory
 
 	NamespaceFlags cmdcore.NamespaceFlags
+	AllContexts    bool
 }
 
-func NewListOptions(ui ui.UI, depsFactory cmdcore.DepsFactory) *ListOptions {
-	return &ListOptions{ui: ui, depsFactory: depsFactory}
+func NewListOptions(ui ui.UI, configFactory cmdcore.ConfigFactory, depsFactory cmdcore.DepsFactory) *ListOptions {
+	return &ListOptions{ui: ui, configFactory: configFactory, depsFactory: depsFactory}
 }
 
 func NewListCmd(o *ListOptions, flagsFactory cmdcore.FlagsFactory) *cobra.Command {
@@ -45,24 +50,92 @@ func NewListCmd(o *ListOptions, flagsFactory cmdcore.FlagsFactory) *cobra.Comman
 		Long:    "List all services in a namespace",
 		Example: `
   # List all services in namespace 'ns1'
-  knctl service list -n ns1`,
+  knctl service list -n ns1
+
+  # List all services in namespace 'ns1' across all kubeconfig contexts
+  knctl service list -n ns1 --all-contexts`,
 		RunE: func(_ *cobra.Command, _ []string) error { return o.Run() },
 	}
 	o.NamespaceFlags.Set(cmd, flagsFactory)
+	cmd.Flags().BoolVar(&o.AllContexts, "all-contexts", false, "List services in all kubeconfig contexts")
 	return cmd
 }
 
+type contextServices struct {
+	Context  string
+	Services []v1alpha1.Service
+	Err      error
+}
+
 func (o *ListOptions) Run() error {
-	servingClient, err := o.depsFactory.ServingClient()
+	var results []contextServices
+
+	if o.AllContexts {
+		contexts, err := o.configFactory.Contexts()
+		if err != nil {
+			return err
+		}
+
+		results = o.listInContexts(contexts)
+	} else {
+		services, err := o.list(o.depsFactory)
+		if err != nil {
+			return err
+		}
+
+		results = []contextServices{{Services: services}}
+	}
+
+	o.printTable(results)
+
+	var errs []string
+
+	for _, result := range results {
+		if result.Err != nil {
+			errs = append(errs, fmt.Sprintf("context '%s': %s", result.Context, result.Err))
+		}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("Listing services: %s", strings.Join(errs, "; "))
+	}
+
+	return nil
+}
+
+func (o *ListOptions) listInContexts(contexts []string) []contextServices {
+	results := make([]contextServices, len(contexts))
+
+	// Errors are reported per context
+	util.NewParallel(util.DefaultParallelism).Run(len(contexts), func(i int) error {
+		depsFactory := cmdcore.NewDepsFactoryImpl(o.configFactory.ForContext(contexts[i]))
+		services, err := o.list(depsFactory)
+
+		results[i] = contextServices{Context: contexts[i], Services: services, Err: err}
+		return nil
+	})
+
+	return results
+}
+
+func (o *ListOptions) list(depsFactory cmdcore.DepsFactory) ([]v1alpha1.Service, error) {
+	servingClient, err := depsFactory.ServingClient()
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	services, err := servingClient.ServingV1alpha1().Services(o.NamespaceFlags.Name).List(metav1.ListOptions{})
 	if err != nil {
-		return err
+		return nil, err
 	}
 
+	return services.Items, nil
+}
+
+func (o *ListOptions) printTable(results []contextServices) {
+	contextHeader := uitable.NewHeader("Context")
+	contextHeader.Hidden = !o.AllContexts
+
 	internalDomainHeader := uitable.NewHeader("Internal Domain")
 	internalDomainHeader.Hidden = true
 
@@ -71,6 +144,7 @@ func (o *ListOptions) Run() error {
 		Content: "services",
 
 		Header: []uitable.Header{
+			contextHeader,
 			uitable.NewHeader("Name"),
 			uitable.NewHeader("Domain"),
 			internalDomainHeader,
@@ -81,21 +155,23 @@ func (o *ListOptions) Run() error {
 
 		SortBy: []uitable.ColumnSort{
 			{Column: 0, Asc: true},
+			{Column: 1, Asc: true},
 		},
 	}
 
-	for _, svc := range services.Items {
-		table.Rows = append(table.Rows, []uitable.Value{
-			uitable.NewValueString(svc.Name),
-			uitable.NewValueString(svc.Status.Domain),
-			uitable.NewValueString(svc.Status.DomainInternal),
-			cmdcore.NewAnnotationsValue(svc.Annotations),
-			cmdcore.NewConditionsValue(svc.Status.Conditions),
-			cmdcore.NewValueAge(svc.CreationTimestamp.Time),
-		})
+	for _, result := range results {
+		for _, svc := range result.Services {
+			table.Rows = append(table.Rows, []uitable.Value{
+				uitable.NewValueString(result.Context),
+				uitable.NewValueString(svc.Name),
+				uitable.NewValueString(svc.Status.Domain),
+				uitable.NewValueString(svc.Status.DomainInternal),
+				cmdcore.NewAnnotationsValue(svc.Annotations),
+				cmdcore.NewConditionsValue(svc.Status.Conditions),
+				cmdcore.NewValueAge(svc.CreationTimestamp.Time),
+			})
+		}
 	}
 
 	o.ui.PrintTable(table)
-
-	return nil
 }
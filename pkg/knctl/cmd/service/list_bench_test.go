@@ -0,0 +1,57 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/cppforlife/go-cli-ui/ui"
+	cmdcore "github.com/cppforlife/knctl/pkg/knctl/cmd/core"
+	. "github.com/cppforlife/knctl/pkg/knctl/cmd/service"
+	"github.com/cppforlife/knctl/pkg/knctl/testkit"
+)
+
+const benchServicesCount = 1000
+
+func BenchmarkListOptions_Run(b *testing.B) {
+	cluster := testkit.NewCluster(b)
+
+	for i := 0; i < benchServicesCount; i++ {
+		cluster.Add(testkit.NewService("ns1", fmt.Sprintf("svc%d", i)).
+			Domain(fmt.Sprintf("svc%d.ns1.example.com", i)).Ready().Build())
+	}
+
+	configFactory := cluster.ConfigFactory()
+
+	opts := NewListOptions(ui.NewNoopUI(), configFactory, cmdcore.NewDepsFactoryImpl(configFactory))
+	opts.NamespaceFlags = cmdcore.NamespaceFlags{"ns1"}
+
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		err := opts.Run()
+		if err != nil {
+			b.Fatalf("Expected no error: %s", err)
+		}
+	}
+}
+
+func TestListOptions_RunBenchmarkThreshold(t *testing.T) {
+	testkit.RequireBenchmarkThreshold(t, BenchmarkListOptions_Run, 250*time.Millisecond)
+}
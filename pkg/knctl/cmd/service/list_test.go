@@ -25,7 +25,7 @@ import (
 )
 
 func TestNewListCmd_Ok(t *testing.T) {
-	realCmd := NewListOptions(nil, cmdcore.NewDepsFactory())
+	realCmd := NewListOptions(nil, cmdcore.NewConfigFactoryImpl(), cmdcore.NewDepsFactory())
 	cmd := NewTestCmd(t, NewListCmd(realCmd, cmdcore.FlagsFactory{}))
 	cmd.ExpectBasicConfig()
 	cmd.Execute([]string{
@@ -37,18 +37,20 @@ func TestNewListCmd_Ok(t *testing.T) {
 }
 
 func TestNewListCmd_OkLongFlagNames(t *testing.T) {
-	realCmd := NewListOptions(nil, cmdcore.NewDepsFactory())
+	realCmd := NewListOptions(nil, cmdcore.NewConfigFactoryImpl(), cmdcore.NewDepsFactory())
 	cmd := NewTestCmd(t, NewListCmd(realCmd, cmdcore.FlagsFactory{}))
 	cmd.Execute([]string{
 		"--namespace", "test-namespace",
+		"--all-contexts",
 	})
 	cmd.ExpectReachesExecution()
 
 	DeepEqual(t, realCmd.NamespaceFlags, cmdcore.NamespaceFlags{"test-namespace"})
+	DeepEqual(t, realCmd.AllContexts, true)
 }
 
 func TestNewListCmd_OkMinimum(t *testing.T) {
-	realCmd := NewListOptions(nil, cmdcore.NewDepsFactory())
+	realCmd := NewListOptions(nil, cmdcore.NewConfigFactoryImpl(), cmdcore.NewDepsFactory())
 	cmd := NewTestCmd(t, NewListCmd(realCmd, cmdcore.FlagsFactory{}))
 	cmd.Execute([]string{})
 	cmd.ExpectReachesExecution()
@@ -0,0 +1,203 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/knative/serving/pkg/apis/serving"
+	"github.com/knative/serving/pkg/apis/serving/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	// Knative runtime contract defaults to port 8080
+	localRunDefaultPort = 8080
+)
+
+// LocalRun reproduces revision's container configuration for running it locally
+type LocalRun struct {
+	coreClient kubernetes.Interface
+}
+
+func NewLocalRun(coreClient kubernetes.Interface) LocalRun {
+	return LocalRun{coreClient}
+}
+
+// ContainerPort returns port that container listens on
+func (r LocalRun) ContainerPort(revision v1alpha1.Revision) int32 {
+	ports := revision.Spec.Container.Ports
+	if len(ports) > 0 && ports[0].ContainerPort > 0 {
+		return ports[0].ContainerPort
+	}
+	return localRunDefaultPort
+}
+
+// Image prefers resolved digest so that exactly same image runs locally
+func (r LocalRun) Image(revision v1alpha1.Revision) string {
+	if len(revision.Status.ImageDigest) > 0 {
+		return revision.Status.ImageDigest
+	}
+	return revision.Spec.Container.Image
+}
+
+// Env returns environment variables (in KEY=VALUE format) as they would be set by Knative;
+// variables that cannot be resolved outside of the cluster are returned as warnings
+func (r LocalRun) Env(revision v1alpha1.Revision) ([]string, []string, error) {
+	cont := revision.Spec.Container
+	namespace := revision.Namespace
+
+	var result, warnings []string
+
+	for _, envFrom := range cont.EnvFrom {
+		vals, err := r.envFrom(namespace, envFrom)
+		if err != nil {
+			return nil, nil, err
+		}
+		result = append(result, vals...)
+	}
+
+	for _, envVar := range cont.Env {
+		if envVar.ValueFrom == nil {
+			result = append(result, envVar.Name+"="+envVar.Value)
+			continue
+		}
+
+		val, supported, err := r.envValueFrom(namespace, *envVar.ValueFrom)
+		if err != nil {
+			return nil, nil, fmt.Errorf("Resolving environment variable '%s': %s", envVar.Name, err)
+		}
+		if !supported {
+			warnings = append(warnings, fmt.Sprintf("Skipping environment variable '%s' since its value is only known within the cluster", envVar.Name))
+			continue
+		}
+
+		result = append(result, envVar.Name+"="+val)
+	}
+
+	result = append(result,
+		"PORT="+strconv.Itoa(int(r.ContainerPort(revision))),
+		"K_REVISION="+revision.Name,
+		"K_CONFIGURATION="+revision.Labels[serving.ConfigurationLabelKey],
+		"K_SERVICE="+revision.Labels[serving.ServiceLabelKey],
+	)
+
+	return result, warnings, nil
+}
+
+func (r LocalRun) envFrom(namespace string, envFrom corev1.EnvFromSource) ([]string, error) {
+	var data map[string]string
+
+	switch {
+	case envFrom.SecretRef != nil:
+		secret, err := r.coreClient.CoreV1().Secrets(namespace).Get(envFrom.SecretRef.Name, metav1.GetOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("Getting secret '%s': %s", envFrom.SecretRef.Name, err)
+		}
+		data = map[string]string{}
+		for k, v := range secret.Data {
+			data[k] = string(v)
+		}
+
+	case envFrom.ConfigMapRef != nil:
+		configMap, err := r.coreClient.CoreV1().ConfigMaps(namespace).Get(envFrom.ConfigMapRef.Name, metav1.GetOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("Getting config map '%s': %s", envFrom.ConfigMapRef.Name, err)
+		}
+		data = configMap.Data
+	}
+
+	var result []string
+
+	for k, v := range data {
+		result = append(result, envFrom.Prefix+k+"="+v)
+	}
+
+	sort.Strings(result)
+
+	return result, nil
+}
+
+func (r LocalRun) envValueFrom(namespace string, src corev1.EnvVarSource) (string, bool, error) {
+	switch {
+	case src.SecretKeyRef != nil:
+		secret, err := r.coreClient.CoreV1().Secrets(namespace).Get(src.SecretKeyRef.Name, metav1.GetOptions{})
+		if err != nil {
+			return "", true, fmt.Errorf("Getting secret '%s': %s", src.SecretKeyRef.Name, err)
+		}
+		val, found := secret.Data[src.SecretKeyRef.Key]
+		if !found {
+			return "", true, fmt.Errorf("Expected secret '%s' to have key '%s'", src.SecretKeyRef.Name, src.SecretKeyRef.Key)
+		}
+		return string(val), true, nil
+
+	case src.ConfigMapKeyRef != nil:
+		configMap, err := r.coreClient.CoreV1().ConfigMaps(namespace).Get(src.ConfigMapKeyRef.Name, metav1.GetOptions{})
+		if err != nil {
+			return "", true, fmt.Errorf("Getting config map '%s': %s", src.ConfigMapKeyRef.Name, err)
+		}
+		val, found := configMap.Data[src.ConfigMapKeyRef.Key]
+		if !found {
+			return "", true, fmt.Errorf("Expected config map '%s' to have key '%s'", src.ConfigMapKeyRef.Name, src.ConfigMapKeyRef.Key)
+		}
+		return val, true, nil
+
+	default:
+		return "", false, nil
+	}
+}
+
+// DockerArgs returns arguments for 'docker run'; environment is passed via file
+// so that secret values do not show up in process list. Container (which may
+// have access to production secrets) is only reachable from local host.
+func (r LocalRun) DockerArgs(revision v1alpha1.Revision, localPort int, envFilePath string) []string {
+	args := []string{"run", "--rm", "-i",
+		"-p", fmt.Sprintf("127.0.0.1:%d:%d", localPort, r.ContainerPort(revision)),
+		"--env-file", envFilePath,
+	}
+
+	cont := revision.Spec.Container
+
+	if len(cont.Command) > 0 {
+		args = append(args, "--entrypoint", cont.Command[0])
+	}
+
+	args = append(args, r.Image(revision))
+
+	if len(cont.Command) > 1 {
+		args = append(args, cont.Command[1:]...)
+	}
+
+	return append(args, cont.Args...)
+}
+
+// EnvFileContent formats environment variables for 'docker run --env-file'
+// (multiline values are not supported by the format)
+func (r LocalRun) EnvFileContent(env []string) (string, error) {
+	for _, kv := range env {
+		if strings.Contains(kv, "\n") {
+			return "", fmt.Errorf("Expected environment variable '%s' to not have multiline value",
+				strings.SplitN(kv, "=", 2)[0])
+		}
+	}
+	return strings.Join(env, "\n") + "\n", nil
+}
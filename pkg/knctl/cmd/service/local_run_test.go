@@ -0,0 +1,124 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service_test
+
+import (
+	"reflect"
+	"testing"
+
+	. "github.com/cppforlife/knctl/pkg/knctl/cmd/service"
+	"github.com/cppforlife/knctl/pkg/knctl/testkit"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestLocalRunEnv(t *testing.T) {
+	cluster := testkit.NewCluster(t,
+		&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "secret1"},
+			Data: map[string][]byte{"key1": []byte("secret-val1"), "key2": []byte("secret-val2")}},
+		&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "cm1"},
+			Data: map[string]string{"key1": "cm-val1"}},
+	)
+
+	revision := testkit.NewRevision("ns1", "svc1", "svc1-00001").Build()
+	revision.Spec.Container = corev1.Container{
+		Image: "img1",
+		EnvFrom: []corev1.EnvFromSource{
+			{Prefix: "S_", SecretRef: &corev1.SecretEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: "secret1"}}},
+		},
+		Env: []corev1.EnvVar{
+			{Name: "PLAIN", Value: "val"},
+			{Name: "FROM_SECRET", ValueFrom: &corev1.EnvVarSource{SecretKeyRef: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: "secret1"}, Key: "key2"}}},
+			{Name: "FROM_CM", ValueFrom: &corev1.EnvVarSource{ConfigMapKeyRef: &corev1.ConfigMapKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: "cm1"}, Key: "key1"}}},
+			{Name: "POD_NAME", ValueFrom: &corev1.EnvVarSource{FieldRef: &corev1.ObjectFieldSelector{FieldPath: "metadata.name"}}},
+		},
+	}
+
+	env, warnings, err := NewLocalRun(cluster.CoreClient()).Env(*revision)
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	expectedEnv := []string{
+		"S_key1=secret-val1",
+		"S_key2=secret-val2",
+		"PLAIN=val",
+		"FROM_SECRET=secret-val2",
+		"FROM_CM=cm-val1",
+		"PORT=8080",
+		"K_REVISION=svc1-00001",
+		"K_CONFIGURATION=svc1",
+		"K_SERVICE=svc1",
+	}
+
+	if !reflect.DeepEqual(env, expectedEnv) {
+		t.Fatalf("Expected env to match: %#v", env)
+	}
+
+	if !reflect.DeepEqual(warnings, []string{"Skipping environment variable 'POD_NAME' since its value is only known within the cluster"}) {
+		t.Fatalf("Expected warnings to match: %#v", warnings)
+	}
+}
+
+func TestLocalRunEnvMissingSecretKey(t *testing.T) {
+	cluster := testkit.NewCluster(t, &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "secret1"}})
+
+	revision := testkit.NewRevision("ns1", "svc1", "svc1-00001").Build()
+	revision.Spec.Container.Env = []corev1.EnvVar{
+		{Name: "FROM_SECRET", ValueFrom: &corev1.EnvVarSource{SecretKeyRef: &corev1.SecretKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{Name: "secret1"}, Key: "key1"}}},
+	}
+
+	_, _, err := NewLocalRun(cluster.CoreClient()).Env(*revision)
+	if err == nil || err.Error() != "Resolving environment variable 'FROM_SECRET': Expected secret 'secret1' to have key 'key1'" {
+		t.Fatalf("Expected missing key error, but was: %v", err)
+	}
+}
+
+func TestLocalRunDockerArgs(t *testing.T) {
+	revision := testkit.NewRevision("ns1", "svc1", "svc1-00001").Build()
+	revision.Spec.Container = corev1.Container{
+		Image:   "img1",
+		Command: []string{"/app", "serve"},
+		Args:    []string{"--verbose"},
+		Ports:   []corev1.ContainerPort{{ContainerPort: 9000}},
+	}
+	revision.Status.ImageDigest = "img1@sha256:abc"
+
+	args := NewLocalRun(nil).DockerArgs(*revision, 8081, "/tmp/env")
+
+	expectedArgs := []string{"run", "--rm", "-i", "-p", "127.0.0.1:8081:9000", "--env-file", "/tmp/env",
+		"--entrypoint", "/app", "img1@sha256:abc", "serve", "--verbose"}
+
+	if !reflect.DeepEqual(args, expectedArgs) {
+		t.Fatalf("Expected docker args to match: %#v", args)
+	}
+}
+
+func TestLocalRunEnvFileContent(t *testing.T) {
+	content, err := NewLocalRun(nil).EnvFileContent([]string{"A=1", "B=2"})
+	if err != nil || content != "A=1\nB=2\n" {
+		t.Fatalf("Expected env file content to match: %q %v", content, err)
+	}
+
+	_, err = NewLocalRun(nil).EnvFileContent([]string{"A=multi\nline"})
+	if err == nil || err.Error() != "Expected environment variable 'A' to not have multiline value" {
+		t.Fatalf("Expected multiline error, but was: %v", err)
+	}
+}
@@ -31,15 +31,16 @@ import (
 type LogsOptions struct {
 	ui            ui.UI
 	depsFactory   cmdcore.DepsFactory
-	cancelSignals cmdcore.CancelSignals
+	cancelSignals cmdcore.CancelWatcher
 
 	ServiceFlags cmdflags.ServiceFlags
+	RedactFlags  cmdflags.RedactFlags
 
 	Follow bool
 	Lines  int64
 }
 
-func NewLogsOptions(ui ui.UI, depsFactory cmdcore.DepsFactory, cancelSignals cmdcore.CancelSignals) *LogsOptions {
+func NewLogsOptions(ui ui.UI, depsFactory cmdcore.DepsFactory, cancelSignals cmdcore.CancelWatcher) *LogsOptions {
 	return &LogsOptions{ui: ui, depsFactory: depsFactory, cancelSignals: cancelSignals}
 }
 
@@ -60,6 +61,7 @@ func NewLogsCmd(o *LogsOptions, flagsFactory cmdcore.FlagsFactory) *cobra.Comman
 		RunE: func(_ *cobra.Command, _ []string) error { return o.Run() },
 	}
 	o.ServiceFlags.Set(cmd, flagsFactory)
+	o.RedactFlags.Set(cmd, flagsFactory)
 
 	cmd.Flags().BoolVarP(&o.Follow, "follow", "f", false, "As new revisions are added, new pod logs will be printed")
 	cmd.Flags().Int64VarP(&o.Lines, "lines", "l", 10, "Number of lines")
@@ -100,5 +102,5 @@ func (o *LogsOptions) Run() error {
 		close(cancelCh)
 	})
 
-	return LogsView{tailOpts, podWatcher, coreClient, o.ui}.Show(cancelCh)
+	return logs.NewView(tailOpts, podWatcher, coreClient, o.ui, o.RedactFlags.ShowSecrets).Show(cancelCh)
 }
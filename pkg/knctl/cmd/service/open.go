@@ -23,6 +23,7 @@ import (
 	"github.com/cppforlife/go-cli-ui/ui"
 	cmdcore "github.com/cppforlife/knctl/pkg/knctl/cmd/core"
 	cmdflags "github.com/cppforlife/knctl/pkg/knctl/cmd/flags"
+	ctlservice "github.com/cppforlife/knctl/pkg/knctl/service"
 	"github.com/spf13/cobra"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
@@ -90,7 +91,22 @@ func (o *OpenOptions) addr() (string, error) {
 		return "", err
 	}
 
-	url, err := ServiceAddress{service, coreClient}.URL(o.CurlFlags.Port, true)
+	cache, err := o.depsFactory.Cache()
+	if err != nil {
+		return "", err
+	}
+
+	logger, err := o.depsFactory.Logger()
+	if err != nil {
+		return "", err
+	}
+
+	inCluster, err := o.depsFactory.InCluster()
+	if err != nil {
+		return "", err
+	}
+
+	url, err := ctlservice.NewServiceAddress(service, coreClient, cache, logger, inCluster).URL(o.CurlFlags.Port, true)
 	if err != nil {
 		return "", err
 	}
@@ -0,0 +1,100 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"fmt"
+
+	"github.com/cppforlife/go-cli-ui/ui"
+	cmdcore "github.com/cppforlife/knctl/pkg/knctl/cmd/core"
+	cmdflags "github.com/cppforlife/knctl/pkg/knctl/cmd/flags"
+	ctlservice "github.com/cppforlife/knctl/pkg/knctl/service"
+	"github.com/spf13/cobra"
+)
+
+type PauseOptions struct {
+	ui          ui.UI
+	depsFactory cmdcore.DepsFactory
+
+	ServiceFlags       cmdflags.ServiceFlags
+	SelectorFlags      cmdflags.SelectorFlags
+	MaintenanceService string
+}
+
+func NewPauseOptions(ui ui.UI, depsFactory cmdcore.DepsFactory) *PauseOptions {
+	return &PauseOptions{ui: ui, depsFactory: depsFactory}
+}
+
+func NewPauseCmd(o *PauseOptions, flagsFactory cmdcore.FlagsFactory) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "pause",
+		Short: "Pause service",
+		Long: `Pause service by routing its traffic to a maintenance service.
+
+Service's revisions are left without traffic and are scaled down by the autoscaler
+(unless minimum scale is configured). Original service configuration is saved
+in an annotation and restored by 'knctl service resume'.`,
+		Example: `
+  # Pause service 'srv1' sending its traffic to service 'maintenance' in namespace 'ns1'
+  knctl service pause -s srv1 --maintenance-service maintenance -n ns1
+
+  # Pause all services with label 'team=payments' in namespace 'ns1'
+  knctl service pause --selector team=payments --maintenance-service maintenance -n ns1`,
+		RunE: func(_ *cobra.Command, _ []string) error { return o.Run() },
+	}
+	o.ServiceFlags.SetOptional(cmd, flagsFactory)
+	o.SelectorFlags.Set(cmd, flagsFactory)
+	cmd.Flags().StringVar(&o.MaintenanceService, "maintenance-service", "", "Set service that receives traffic while service is paused")
+	cmd.MarkFlagRequired("maintenance-service")
+	return cmd
+}
+
+func (o *PauseOptions) Run() error {
+	err := o.SelectorFlags.ValidateWithName(o.ServiceFlags.Name, "service")
+	if err != nil {
+		return err
+	}
+
+	servingClient, err := o.depsFactory.ServingClient()
+	if err != nil {
+		return err
+	}
+
+	pause := ServicePause{servingClient, o.ui}
+
+	if len(o.SelectorFlags.Selector) == 0 {
+		return pause.Pause(o.ServiceFlags.NamespaceFlags.Name, o.ServiceFlags.Name, o.MaintenanceService)
+	}
+
+	names, err := ctlservice.NewSelection(servingClient).Names(o.ServiceFlags.NamespaceFlags.Name, o.SelectorFlags.Selector)
+	if err != nil {
+		return err
+	}
+
+	results := cmdcore.NewBatchResults(fmt.Sprintf("Paused services matching '%s'", o.SelectorFlags.Selector))
+
+	for _, name := range names {
+		if name == o.MaintenanceService {
+			continue
+		}
+		results.Add(name, pause.Pause(o.ServiceFlags.NamespaceFlags.Name, name, o.MaintenanceService))
+	}
+
+	results.Print(o.ui)
+
+	return results.Err()
+}
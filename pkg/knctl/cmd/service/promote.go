@@ -0,0 +1,148 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/cppforlife/go-cli-ui/ui"
+	cmdcore "github.com/cppforlife/knctl/pkg/knctl/cmd/core"
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type PromoteOptions struct {
+	ui            ui.UI
+	configFactory cmdcore.ConfigFactory
+	depsFactory   cmdcore.DepsFactory
+
+	ServiceName   string
+	FromNamespace string
+	ToNamespace   string
+	ToContext     string
+
+	MinScale *int
+	MaxScale *int
+
+	RolloutPercentages []int
+	RolloutInterval    time.Duration
+	ReadyTimeout       time.Duration
+}
+
+func NewPromoteOptions(ui ui.UI, configFactory cmdcore.ConfigFactory, depsFactory cmdcore.DepsFactory) *PromoteOptions {
+	return &PromoteOptions{ui: ui, configFactory: configFactory, depsFactory: depsFactory}
+}
+
+func NewPromoteCmd(o *PromoteOptions, flagsFactory cmdcore.FlagsFactory) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "promote",
+		Short: "Promote service to another namespace or cluster",
+		Long: `Promote service to another namespace or cluster.
+
+Copies configuration of the latest ready revision of a service,
+pinning container image to its resolved digest (builds are not re-run).
+
+If service already exists in the destination, traffic can be gradually shifted
+to the new revision via --rollout-percentage flag. Otherwise all traffic is
+routed to the new revision once it's ready.`,
+		Example: `
+  # Promote service 'srv1' from namespace 'staging' to namespace 'prod'
+  knctl promote -s srv1 --from staging --to prod
+
+  # Promote service 'srv1' to namespace 'prod' in another cluster shifting traffic gradually
+  knctl promote -s srv1 --from staging --to prod --to-context prod-cluster --rollout-percentage 10,50 --rollout-interval 5m
+
+  # Promote service 'srv1' with different scaling bounds
+  knctl promote -s srv1 --from staging --to prod --min-scale 3 --max-scale 10`,
+		Annotations: map[string]string{
+			cmdcore.BasicHelpGroup.Key: cmdcore.BasicHelpGroup.Value,
+		},
+		RunE: func(_ *cobra.Command, _ []string) error { return o.Run() },
+	}
+
+	cmd.Flags().StringVarP(&o.ServiceName, "service", "s", "", "Specified service")
+	cmd.Flags().StringVar(&o.FromNamespace, "from", "", "Set source namespace")
+	cmd.Flags().StringVar(&o.ToNamespace, "to", "", "Set destination namespace")
+	cmd.Flags().StringVar(&o.ToContext, "to-context", "", "Set kubeconfig context of destination cluster (default is current cluster)")
+	cmd.MarkFlagRequired("service")
+	cmd.MarkFlagRequired("from")
+	cmd.MarkFlagRequired("to")
+
+	cmd.Flags().Var(cmdcore.NewDefaultlessIntValue(&o.MinScale), "min-scale", "Override autoscaling rule for minimum number of containers")
+	cmd.Flags().Var(cmdcore.NewDefaultlessIntValue(&o.MaxScale), "max-scale", "Override autoscaling rule for maximum number of containers")
+
+	cmd.Flags().IntSliceVar(&o.RolloutPercentages, "rollout-percentage", nil, "Set percentage of traffic for new revision during rollout (example: 10,50) (can be specified multiple times)")
+	cmd.Flags().DurationVar(&o.RolloutInterval, "rollout-interval", time.Minute, "Set time to wait between rollout steps")
+	cmd.Flags().DurationVar(&o.ReadyTimeout, "watch-revision-ready-timeout", 5*time.Minute, "Set timeout for waiting for new revision to become ready")
+
+	return cmd
+}
+
+func (o *PromoteOptions) Run() error {
+	if o.FromNamespace == o.ToNamespace && len(o.ToContext) == 0 {
+		return fmt.Errorf("Expected source and destination to differ")
+	}
+
+	for _, percent := range o.RolloutPercentages {
+		if percent < 1 || percent > 99 {
+			return fmt.Errorf("Expected rollout percentage to be between 1 and 99, but was %d", percent)
+		}
+	}
+
+	srcServingClient, err := o.depsFactory.ServingClient()
+	if err != nil {
+		return err
+	}
+
+	dstDepsFactory := o.depsFactory
+	if len(o.ToContext) > 0 {
+		dstDepsFactory = cmdcore.NewDepsFactoryImpl(o.configFactory.ForContext(o.ToContext))
+	}
+
+	dstServingClient, err := dstDepsFactory.ServingClient()
+	if err != nil {
+		return err
+	}
+
+	dstWaiter, err := dstDepsFactory.Waiter()
+	if err != nil {
+		return err
+	}
+
+	service, err := srcServingClient.ServingV1alpha1().Services(o.FromNamespace).Get(o.ServiceName, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("Getting service '%s' in namespace '%s': %s", o.ServiceName, o.FromNamespace, err)
+	}
+
+	promotion := ServicePromotion{
+		SrcClient: srcServingClient,
+		DstClient: dstServingClient,
+		DstWaiter: dstWaiter,
+		UI:        o.ui,
+
+		ToNamespace: o.ToNamespace,
+		MinScale:    o.MinScale,
+		MaxScale:    o.MaxScale,
+
+		RolloutPercentages: o.RolloutPercentages,
+		RolloutInterval:    o.RolloutInterval,
+		ReadyTimeout:       o.ReadyTimeout,
+	}
+
+	return promotion.Promote(*service)
+}
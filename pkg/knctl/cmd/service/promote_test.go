@@ -0,0 +1,81 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service_test
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/cppforlife/knctl/pkg/knctl/cmd"
+	cmdcore "github.com/cppforlife/knctl/pkg/knctl/cmd/core"
+	. "github.com/cppforlife/knctl/pkg/knctl/cmd/service"
+)
+
+func TestNewPromoteCmd_Ok(t *testing.T) {
+	realCmd := NewPromoteOptions(nil, cmdcore.NewConfigFactoryImpl(), cmdcore.NewDepsFactory())
+	cmd := NewTestCmd(t, NewPromoteCmd(realCmd, cmdcore.FlagsFactory{}))
+	cmd.ExpectBasicConfig()
+	cmd.Execute([]string{
+		"-s", "test-service",
+		"--from", "staging",
+		"--to", "prod",
+		"--to-context", "prod-cluster",
+		"--min-scale", "2",
+		"--max-scale", "5",
+		"--rollout-percentage", "10,50",
+		"--rollout-interval", "5m",
+		"--watch-revision-ready-timeout", "1m",
+	})
+	cmd.ExpectReachesExecution()
+
+	minScale := 2
+	maxScale := 5
+
+	DeepEqual(t, realCmd.ServiceName, "test-service")
+	DeepEqual(t, realCmd.FromNamespace, "staging")
+	DeepEqual(t, realCmd.ToNamespace, "prod")
+	DeepEqual(t, realCmd.ToContext, "prod-cluster")
+	DeepEqual(t, realCmd.MinScale, &minScale)
+	DeepEqual(t, realCmd.MaxScale, &maxScale)
+	DeepEqual(t, realCmd.RolloutPercentages, []int{10, 50})
+	DeepEqual(t, realCmd.RolloutInterval, 5*time.Minute)
+	DeepEqual(t, realCmd.ReadyTimeout, time.Minute)
+}
+
+func TestNewPromoteCmd_OkMinimum(t *testing.T) {
+	realCmd := NewPromoteOptions(nil, cmdcore.NewConfigFactoryImpl(), cmdcore.NewDepsFactory())
+	cmd := NewTestCmd(t, NewPromoteCmd(realCmd, cmdcore.FlagsFactory{}))
+	cmd.Execute([]string{
+		"--service", "test-service",
+		"--from", "staging",
+		"--to", "prod",
+	})
+	cmd.ExpectReachesExecution()
+
+	DeepEqual(t, realCmd.ToContext, "")
+	DeepEqual(t, realCmd.MinScale, (*int)(nil))
+	DeepEqual(t, realCmd.RolloutPercentages, []int(nil))
+	DeepEqual(t, realCmd.RolloutInterval, time.Minute)
+	DeepEqual(t, realCmd.ReadyTimeout, 5*time.Minute)
+}
+
+func TestNewPromoteCmd_RequiredFlags(t *testing.T) {
+	realCmd := NewPromoteOptions(nil, cmdcore.NewConfigFactoryImpl(), cmdcore.NewDepsFactory())
+	cmd := NewTestCmd(t, NewPromoteCmd(realCmd, cmdcore.FlagsFactory{}))
+	cmd.Execute([]string{})
+	cmd.ExpectRequiredFlags([]string{"from", "service", "to"})
+}
@@ -0,0 +1,110 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"fmt"
+	"net/http"
+
+	cmdcore "github.com/cppforlife/knctl/pkg/knctl/cmd/core"
+	cmdflags "github.com/cppforlife/knctl/pkg/knctl/cmd/flags"
+	cmdrev "github.com/cppforlife/knctl/pkg/knctl/cmd/revision"
+	ctlknconfig "github.com/cppforlife/knctl/pkg/knctl/knativeconfig"
+	ctlservice "github.com/cppforlife/knctl/pkg/knctl/service"
+	servingclientset "github.com/knative/serving/pkg/client/clientset/versioned"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	tagRoutingHeaderName = "Knative-Serving-Tag"
+)
+
+// RevisionPin describes how to force request to a particular revision
+// via route's named traffic target (which may receive 0% of traffic)
+type RevisionPin struct {
+	Revision   string
+	TargetName string
+
+	// Set when Knative is configured to route based on tag header;
+	// otherwise named target's subdomain is used as a Host header
+	UseTagHeader bool
+}
+
+func NewRevisionPin(servingClient servingclientset.Interface, coreClient kubernetes.Interface,
+	namespace, serviceName, revisionRef string) (RevisionPin, error) {
+
+	revFlags := cmdflags.RevisionFlags{NamespaceFlags: cmdcore.NamespaceFlags{namespace}, Name: revisionRef}
+
+	revision, err := cmdrev.NewReference(revFlags, ctlservice.NewTags(servingClient), servingClient).Revision()
+	if err != nil {
+		return RevisionPin{}, err
+	}
+
+	// Assumes that service has the same name as the route
+	route, err := servingClient.ServingV1alpha1().Routes(namespace).Get(serviceName, metav1.GetOptions{})
+	if err != nil {
+		return RevisionPin{}, fmt.Errorf("Getting route: %s", err)
+	}
+
+	pin := RevisionPin{Revision: revision.Name, UseTagHeader: true}
+
+	// Check actual configuration of targets, not desired one
+	for _, target := range route.Status.Traffic {
+		if target.RevisionName == revision.Name && len(target.Name) > 0 {
+			pin.TargetName = target.Name
+			break
+		}
+	}
+
+	if len(pin.TargetName) == 0 {
+		return RevisionPin{}, fmt.Errorf("Expected route '%s' to have named traffic target for revision '%s' "+
+			"(target may receive 0%% of traffic)", route.Name, revision.Name)
+	}
+
+	disabledFeatures, err := ctlknconfig.NewFeatures(coreClient).Disabled([]string{ctlknconfig.FeatureTagHeaderBasedRouting})
+	if err == nil && len(disabledFeatures) > 0 {
+		pin.UseTagHeader = false
+	}
+
+	return pin, nil
+}
+
+// CurlArgs returns curl headers for given service domain
+func (p RevisionPin) CurlArgs(domain string) []string {
+	var args []string
+	for k, vals := range p.Header() {
+		args = append(args, "-H", k+": "+vals[0])
+	}
+	return append(args, "-H", "Host: "+p.Host(domain))
+}
+
+// Host returns Host header value for given service domain
+func (p RevisionPin) Host(domain string) string {
+	if p.UseTagHeader {
+		return domain
+	}
+	return p.TargetName + "." + domain
+}
+
+func (p RevisionPin) Header() http.Header {
+	header := http.Header{}
+	if p.UseTagHeader {
+		header.Set(tagRoutingHeaderName, p.TargetName)
+	}
+	return header
+}
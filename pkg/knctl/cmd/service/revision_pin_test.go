@@ -0,0 +1,82 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service_test
+
+import (
+	"testing"
+
+	. "github.com/cppforlife/knctl/pkg/knctl/cmd"
+	. "github.com/cppforlife/knctl/pkg/knctl/cmd/service"
+	"github.com/cppforlife/knctl/pkg/knctl/testkit"
+	"github.com/knative/serving/pkg/apis/serving/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func newPinnedRoute() *v1alpha1.Route {
+	route := testkit.NewRoute("ns1", "svc1").Traffic("svc1-00001", 100).Build()
+	route.Status.Traffic = append(route.Status.Traffic,
+		v1alpha1.TrafficTarget{Name: "candidate", RevisionName: "svc1-00002", Percent: 0})
+	return route
+}
+
+func TestNewRevisionPin_TagHeader(t *testing.T) {
+	cluster := testkit.NewCluster(t,
+		testkit.NewRevision("ns1", "svc1", "svc1-00002").Build(),
+		newPinnedRoute(),
+		&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "config-features", Namespace: "knative-serving"},
+			Data:       map[string]string{"tag-header-based-routing": "enabled"},
+		},
+	)
+
+	pin, err := NewRevisionPin(cluster.ServingClient(), cluster.CoreClient(), "ns1", "svc1", "svc1-00002")
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	DeepEqual(t, pin, RevisionPin{Revision: "svc1-00002", TargetName: "candidate", UseTagHeader: true})
+	DeepEqual(t, pin.CurlArgs("svc1.ns1.example.com"), []string{
+		"-H", "Knative-Serving-Tag: candidate", "-H", "Host: svc1.ns1.example.com"})
+}
+
+func TestNewRevisionPin_Subdomain(t *testing.T) {
+	cluster := testkit.NewCluster(t,
+		testkit.NewRevision("ns1", "svc1", "svc1-00002").Tag("beta").Build(),
+		newPinnedRoute(),
+	)
+
+	pin, err := NewRevisionPin(cluster.ServingClient(), cluster.CoreClient(), "ns1", "svc1", "svc1:beta")
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	DeepEqual(t, pin.UseTagHeader, false)
+	DeepEqual(t, pin.CurlArgs("svc1.ns1.example.com"), []string{"-H", "Host: candidate.svc1.ns1.example.com"})
+}
+
+func TestNewRevisionPin_RequiresNamedTarget(t *testing.T) {
+	cluster := testkit.NewCluster(t,
+		testkit.NewRevision("ns1", "svc1", "svc1-00001").Build(),
+		newPinnedRoute(),
+	)
+
+	_, err := NewRevisionPin(cluster.ServingClient(), cluster.CoreClient(), "ns1", "svc1", "svc1-00001")
+	if err == nil || err.Error() != "Expected route 'svc1' to have named traffic target for revision 'svc1-00001' (target may receive 0% of traffic)" {
+		t.Fatalf("Expected error, but was: %v", err)
+	}
+}
@@ -17,8 +17,7 @@ limitations under the License.
 package service
 
 import (
-	"time"
-
+	"github.com/cppforlife/knctl/pkg/knctl/waiter"
 	"github.com/knative/serving/pkg/apis/serving/v1alpha1"
 	servingclientset "github.com/knative/serving/pkg/client/clientset/versioned"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -27,6 +26,7 @@ import (
 type RevisionReadyStatusWatcher struct {
 	revision      *v1alpha1.Revision
 	servingClient servingclientset.Interface
+	objWaiter     *waiter.Waiter
 }
 
 func (l RevisionReadyStatusWatcher) IsReady() (bool, error) {
@@ -39,23 +39,7 @@ func (l RevisionReadyStatusWatcher) IsReady() (bool, error) {
 }
 
 func (l RevisionReadyStatusWatcher) Wait(cancelCh chan struct{}) (bool, error) {
-	for {
-		// TODO infinite retry
-
-		ready, err := l.IsReady()
-		if err != nil {
-			return false, err
-		}
-
-		if ready {
-			return true, nil
-		}
-
-		select {
-		case <-cancelCh:
-			return false, nil
-		default:
-			time.Sleep(1 * time.Second)
-		}
-	}
+	_, ready, err := l.objWaiter.RevisionReady(l.revision.Namespace, l.revision.Name, cancelCh)
+
+	return ready, err
 }
@@ -0,0 +1,164 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+
+	"github.com/cppforlife/go-cli-ui/ui"
+	cmdcore "github.com/cppforlife/knctl/pkg/knctl/cmd/core"
+	cmdflags "github.com/cppforlife/knctl/pkg/knctl/cmd/flags"
+	"github.com/cppforlife/knctl/pkg/knctl/redact"
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type RunLocalOptions struct {
+	ui            ui.UI
+	depsFactory   cmdcore.DepsFactory
+	cancelSignals cmdcore.CancelWatcher
+
+	ServiceFlags cmdflags.ServiceFlags
+	RedactFlags  cmdflags.RedactFlags
+
+	Revision string
+	Port     int
+}
+
+func NewRunLocalOptions(ui ui.UI, depsFactory cmdcore.DepsFactory, cancelSignals cmdcore.CancelWatcher) *RunLocalOptions {
+	return &RunLocalOptions{ui: ui, depsFactory: depsFactory, cancelSignals: cancelSignals}
+}
+
+func NewRunLocalCmd(o *RunLocalOptions, flagsFactory cmdcore.FlagsFactory) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "run-local",
+		Short: "Run service container locally",
+		Long: `Run service container locally via Docker.
+
+Uses same image, command, environment variables (including values from secrets
+and config maps) and port as revision's container. Defaults to latest ready revision.
+Container port is only published on 127.0.0.1.`,
+		Example: `
+  # Run latest ready revision of service 'srv1' from namespace 'ns1' on port 8080
+  knctl run-local -s srv1 -n ns1
+
+  # Run specific revision on port 9000
+  knctl run-local -s srv1 --revision srv1-00002 --port 9000 -n ns1`,
+		Annotations: map[string]string{
+			cmdcore.BasicHelpGroup.Key: cmdcore.BasicHelpGroup.Value,
+		},
+		RunE: func(_ *cobra.Command, _ []string) error { return o.Run() },
+	}
+	o.ServiceFlags.Set(cmd, flagsFactory)
+	o.RedactFlags.Set(cmd, flagsFactory)
+	cmd.Flags().StringVar(&o.Revision, "revision", "", "Set revision to run (default: latest ready revision)")
+	cmd.Flags().IntVarP(&o.Port, "port", "p", 8080, "Set local port")
+	return cmd
+}
+
+func (o *RunLocalOptions) Run() error {
+	servingClient, err := o.depsFactory.ServingClient()
+	if err != nil {
+		return err
+	}
+
+	coreClient, err := o.depsFactory.CoreClient()
+	if err != nil {
+		return err
+	}
+
+	namespace := o.ServiceFlags.NamespaceFlags.Name
+	revisionName := o.Revision
+
+	if len(revisionName) == 0 {
+		service, err := servingClient.ServingV1alpha1().Services(namespace).Get(o.ServiceFlags.Name, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+
+		revisionName = service.Status.LatestReadyRevisionName
+		if len(revisionName) == 0 {
+			return fmt.Errorf("Expected service '%s' to have ready revision", o.ServiceFlags.Name)
+		}
+	}
+
+	revision, err := servingClient.ServingV1alpha1().Revisions(namespace).Get(revisionName, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+
+	localRun := NewLocalRun(coreClient)
+
+	env, warnings, err := localRun.Env(*revision)
+	if err != nil {
+		return err
+	}
+
+	for _, warning := range warnings {
+		o.ui.ErrorLinef("Warning: %s", warning)
+	}
+
+	envFileContent, err := localRun.EnvFileContent(env)
+	if err != nil {
+		return err
+	}
+
+	envFile, err := ioutil.TempFile("", "knctl-run-local-env")
+	if err != nil {
+		return fmt.Errorf("Creating env file: %s", err)
+	}
+
+	defer os.Remove(envFile.Name())
+
+	// Env file contains secret values; deferred removal does not happen
+	// when process is interrupted, hence explicit removal on interrupt
+	// (docker has already read env file by the time it's interrupted)
+	o.cancelSignals.Watch(func() { os.Remove(envFile.Name()) })
+
+	_, err = envFile.Write([]byte(envFileContent))
+	envFile.Close()
+	if err != nil {
+		return fmt.Errorf("Writing env file: %s", err)
+	}
+
+	outUI := o.ui
+
+	if !o.RedactFlags.ShowSecrets {
+		secretValues, err := redact.NewSecretValues(coreClient).ForContainer(namespace, revision.Spec.Container)
+		if err != nil {
+			return err
+		}
+		outUI = redact.NewRedactingUI(o.ui, redact.NewRedactor(secretValues))
+	}
+
+	o.ui.PrintLinef("Running revision '%s' (image '%s')", revision.Name, localRun.Image(*revision))
+	o.ui.PrintLinef("Service will be available at http://localhost:%d", o.Port)
+
+	cmd := exec.Command("docker", localRun.DockerArgs(*revision, o.Port, envFile.Name())...)
+	cmd.Stdout = uiBlockWriter{outUI}
+	cmd.Stderr = uiBlockWriter{outUI}
+
+	err = cmd.Run()
+	if err != nil {
+		return fmt.Errorf("Running docker: %s", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,189 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"fmt"
+
+	"github.com/cppforlife/knctl/pkg/knctl/approval"
+	ctlhistory "github.com/cppforlife/knctl/pkg/knctl/history"
+	"github.com/knative/serving/pkg/apis/serving/v1alpha1"
+	servingclientset "github.com/knative/serving/pkg/client/clientset/versioned"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/util/retry"
+)
+
+// ServiceApproval keeps traffic on current revision while deploying
+// new one (service is switched to manual mode) and later shifts traffic
+// to new revision once someone approves it (service manages its route
+// again afterwards unless it was deployed with unmanaged route)
+type ServiceApproval struct {
+	servingClient servingclientset.Interface
+	coreClient    kubernetes.Interface
+}
+
+func NewServiceApproval(servingClient servingclientset.Interface, coreClient kubernetes.Interface) ServiceApproval {
+	return ServiceApproval{servingClient, coreClient}
+}
+
+// Freeze pins route's configuration based traffic to given revision
+// so that newly created revisions do not receive any traffic
+func (a ServiceApproval) Freeze(namespace, serviceName, revisionName string) error {
+	err := a.switchToManual(namespace, serviceName)
+	if err != nil {
+		return err
+	}
+
+	// Assumes that service has the same name as the route
+	routes := a.servingClient.ServingV1alpha1().Routes(namespace)
+
+	err = retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		route, err := routes.Get(serviceName, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+
+		for i, target := range route.Spec.Traffic {
+			if target.ConfigurationName == serviceName {
+				route.Spec.Traffic[i].ConfigurationName = ""
+				route.Spec.Traffic[i].RevisionName = revisionName
+			}
+		}
+
+		_, err = routes.Update(route)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("Pinning route traffic: %s", err)
+	}
+
+	return nil
+}
+
+// Approve shifts all of service's traffic to pending revision
+// and returns history entry describing the change
+func (a ServiceApproval) Approve(namespace string, pending approval.Pending, entry ctlhistory.Entry) (ctlhistory.Entry, error) {
+	revision, err := a.servingClient.ServingV1alpha1().Revisions(namespace).Get(pending.Revision, metav1.GetOptions{})
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return ctlhistory.Entry{}, fmt.Errorf("Expected pending revision '%s' to exist", pending.Revision)
+		}
+		return ctlhistory.Entry{}, fmt.Errorf("Getting revision: %s", err)
+	}
+
+	if !revision.Status.IsReady() {
+		return ctlhistory.Entry{}, fmt.Errorf("Expected pending revision '%s' to be ready", pending.Revision)
+	}
+
+	routes := a.servingClient.ServingV1alpha1().Routes(namespace)
+
+	entry.Route = pending.Service
+	entry.Revision = pending.Revision
+	entry.PreviousRevision = pending.PreviousRevision
+	entry.Traffic = []v1alpha1.TrafficTarget{{RevisionName: pending.Revision, Percent: 100}}
+
+	err = retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		route, err := routes.Get(pending.Service, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+
+		entry.PreviousTraffic = route.Spec.Traffic
+		route.Spec.Traffic = entry.Traffic
+
+		_, err = routes.Update(route)
+		return err
+	})
+	if err != nil {
+		return ctlhistory.Entry{}, fmt.Errorf("Updating route: %s", err)
+	}
+
+	if pending.RestoreManagedRoute {
+		err = a.switchToRunLatest(namespace, pending.Service)
+		if err != nil {
+			return ctlhistory.Entry{}, err
+		}
+	}
+
+	err = approval.NewApprovals(a.coreClient).Delete(namespace, pending.Service)
+	if err != nil {
+		return ctlhistory.Entry{}, err
+	}
+
+	return entry, nil
+}
+
+func (a ServiceApproval) switchToManual(namespace, serviceName string) error {
+	services := a.servingClient.ServingV1alpha1().Services(namespace)
+
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		service, err := services.Get(serviceName, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+
+		if service.Spec.Manual != nil {
+			return nil
+		}
+
+		service.Spec = v1alpha1.ServiceSpec{Manual: &v1alpha1.ManualType{}}
+
+		_, err = services.Update(service)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("Switching service to manual mode: %s", err)
+	}
+
+	return nil
+}
+
+// switchToRunLatest lets service manage its route again; configuration
+// (updated separately while in manual mode) already describes approved revision
+func (a ServiceApproval) switchToRunLatest(namespace, serviceName string) error {
+	services := a.servingClient.ServingV1alpha1().Services(namespace)
+
+	conf, err := a.servingClient.ServingV1alpha1().Configurations(namespace).Get(serviceName, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("Getting configuration: %s", err)
+	}
+
+	err = retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		service, err := services.Get(serviceName, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+
+		if service.Spec.Manual == nil {
+			return nil
+		}
+
+		service.Spec = v1alpha1.ServiceSpec{
+			RunLatest: &v1alpha1.RunLatestType{Configuration: conf.Spec},
+		}
+
+		_, err = services.Update(service)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("Switching service to run latest mode: %s", err)
+	}
+
+	return nil
+}
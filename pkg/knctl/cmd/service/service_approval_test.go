@@ -0,0 +1,144 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service_test
+
+import (
+	"testing"
+
+	"github.com/cppforlife/knctl/pkg/knctl/approval"
+	. "github.com/cppforlife/knctl/pkg/knctl/cmd"
+	. "github.com/cppforlife/knctl/pkg/knctl/cmd/service"
+	ctlhistory "github.com/cppforlife/knctl/pkg/knctl/history"
+	"github.com/cppforlife/knctl/pkg/knctl/testkit"
+	"github.com/knative/serving/pkg/apis/serving/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestServiceApproval_Freeze(t *testing.T) {
+	route := testkit.NewRoute("ns1", "svc1").Build()
+	route.Spec.Traffic = []v1alpha1.TrafficTarget{{ConfigurationName: "svc1", Percent: 100}}
+
+	cluster := testkit.NewCluster(t, testkit.NewService("ns1", "svc1").Build(), route)
+
+	err := NewServiceApproval(cluster.ServingClient(), cluster.CoreClient()).Freeze("ns1", "svc1", "svc1-00001")
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	service, err := cluster.ServingClient().ServingV1alpha1().Services("ns1").Get("svc1", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	if service.Spec.Manual == nil {
+		t.Fatalf("Expected service to be switched to manual mode")
+	}
+
+	route, err = cluster.ServingClient().ServingV1alpha1().Routes("ns1").Get("svc1", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	DeepEqual(t, route.Spec.Traffic, []v1alpha1.TrafficTarget{{RevisionName: "svc1-00001", Percent: 100}})
+}
+
+func TestServiceApproval_Approve(t *testing.T) {
+	cluster := testkit.NewCluster(t,
+		testkit.NewRevision("ns1", "svc1", "svc1-00001").Ready().Build(),
+		testkit.NewRevision("ns1", "svc1", "svc1-00002").Ready().Build(),
+		testkit.NewRoute("ns1", "svc1").Traffic("svc1-00001", 100).Build(),
+	)
+
+	pending := approval.Pending{Service: "svc1", Revision: "svc1-00002", PreviousRevision: "svc1-00001", RequestedBy: "user1"}
+
+	err := approval.NewApprovals(cluster.CoreClient()).Request("ns1", pending)
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	entry, err := NewServiceApproval(cluster.ServingClient(), cluster.CoreClient()).Approve(
+		"ns1", pending, ctlhistory.Entry{Operation: ctlhistory.OperationApprove, Service: "svc1"})
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	DeepEqual(t, entry.Route, "svc1")
+	DeepEqual(t, entry.PreviousTraffic, []v1alpha1.TrafficTarget{{RevisionName: "svc1-00001", Percent: 100}})
+
+	route, err := cluster.ServingClient().ServingV1alpha1().Routes("ns1").Get("svc1", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	DeepEqual(t, route.Spec.Traffic, []v1alpha1.TrafficTarget{{RevisionName: "svc1-00002", Percent: 100}})
+
+	_, found, err := approval.NewApprovals(cluster.CoreClient()).Get("ns1", "svc1")
+	if err != nil || found {
+		t.Fatalf("Expected pending approval to be removed: %v", err)
+	}
+}
+
+func TestServiceApproval_ApproveRestoresManagedRoute(t *testing.T) {
+	conf := &v1alpha1.Configuration{
+		ObjectMeta: metav1.ObjectMeta{Name: "svc1", Namespace: "ns1"},
+		Spec: v1alpha1.ConfigurationSpec{
+			RevisionTemplate: v1alpha1.RevisionTemplateSpec{
+				Spec: v1alpha1.RevisionSpec{Container: corev1.Container{Image: "img2"}},
+			},
+		},
+	}
+
+	cluster := testkit.NewCluster(t,
+		testkit.NewService("ns1", "svc1").Manual().Build(),
+		testkit.NewRevision("ns1", "svc1", "svc1-00002").Ready().Build(),
+		testkit.NewRoute("ns1", "svc1").Traffic("svc1-00001", 100).Build(),
+		conf,
+	)
+
+	pending := approval.Pending{Service: "svc1", Revision: "svc1-00002", RestoreManagedRoute: true}
+
+	_, err := NewServiceApproval(cluster.ServingClient(), cluster.CoreClient()).Approve("ns1", pending, ctlhistory.Entry{})
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	service, err := cluster.ServingClient().ServingV1alpha1().Services("ns1").Get("svc1", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	if service.Spec.Manual != nil || service.Spec.RunLatest == nil {
+		t.Fatalf("Expected service to be switched to run latest mode: %#v", service.Spec)
+	}
+
+	DeepEqual(t, service.Spec.RunLatest.Configuration.RevisionTemplate.Spec.Container.Image, "img2")
+}
+
+func TestServiceApproval_ApproveRequiresReadyRevision(t *testing.T) {
+	cluster := testkit.NewCluster(t,
+		testkit.NewRevision("ns1", "svc1", "svc1-00002").Build(),
+		testkit.NewRoute("ns1", "svc1").Traffic("svc1-00001", 100).Build(),
+	)
+
+	pending := approval.Pending{Service: "svc1", Revision: "svc1-00002"}
+
+	_, err := NewServiceApproval(cluster.ServingClient(), cluster.CoreClient()).Approve("ns1", pending, ctlhistory.Entry{})
+	if err == nil || err.Error() != "Expected pending revision 'svc1-00002' to be ready" {
+		t.Fatalf("Expected error, but was: %v", err)
+	}
+}
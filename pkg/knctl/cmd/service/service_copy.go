@@ -0,0 +1,286 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/cppforlife/go-cli-ui/ui"
+	cmdsa "github.com/cppforlife/knctl/pkg/knctl/cmd/serviceaccount"
+	ctlservice "github.com/cppforlife/knctl/pkg/knctl/service"
+	"github.com/knative/serving/pkg/apis/serving/v1alpha1"
+	servingclientset "github.com/knative/serving/pkg/client/clientset/versioned"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// ServiceCopy recreates service with its service account
+// and credentials secrets in another cluster. Copied service
+// runs latest ready revision of source service (by image digest).
+type ServiceCopy struct {
+	SrcServingClient servingclientset.Interface
+	SrcCoreClient    kubernetes.Interface
+	DstCoreClient    kubernetes.Interface
+	DstServingClient servingclientset.Interface
+	UI               ui.UI
+
+	ToNamespace      string
+	CopySecretValues bool
+}
+
+func (c ServiceCopy) Copy(service v1alpha1.Service) error {
+	// Source spec may reference mutable image tags or builds
+	// which would not produce the same revision in another cluster
+	confSpec, err := ServicePromotion{SrcClient: c.SrcServingClient}.PromotedConfigurationSpec(service)
+	if err != nil {
+		return err
+	}
+
+	saName := confSpec.RevisionTemplate.Spec.ServiceAccountName
+
+	if len(saName) > 0 {
+		err := c.copyServiceAccount(service.Namespace, saName)
+		if err != nil {
+			return err
+		}
+	}
+
+	newService := &v1alpha1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        service.Name,
+			Namespace:   c.ToNamespace,
+			Labels:      ServicePromotion{}.userLabels(service.Labels),
+			Annotations: ServicePromotion{}.userAnnotations(service.Annotations),
+		},
+		Spec: v1alpha1.ServiceSpec{
+			RunLatest: &v1alpha1.RunLatestType{Configuration: confSpec},
+		},
+	}
+
+	newService.Labels[ctlservice.NamespaceLabelKey] = c.ToNamespace
+
+	c.UI.PrintLinef("Creating service '%s' in namespace '%s'", service.Name, c.ToNamespace)
+
+	_, err = c.DstServingClient.ServingV1alpha1().Services(c.ToNamespace).Create(newService)
+	if err != nil {
+		return fmt.Errorf("Creating service: %s", err)
+	}
+
+	return nil
+}
+
+func (c ServiceCopy) copyServiceAccount(srcNamespace, name string) error {
+	sa, err := c.SrcCoreClient.CoreV1().ServiceAccounts(srcNamespace).Get(name, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("Getting service account '%s': %s", name, err)
+	}
+
+	var secretNames, pullSecretNames []string
+
+	for _, ref := range sa.Secrets {
+		copied, err := c.copySecret(srcNamespace, ref.Name)
+		if err != nil {
+			return err
+		}
+		if copied {
+			secretNames = append(secretNames, ref.Name)
+		}
+	}
+
+	for _, ref := range sa.ImagePullSecrets {
+		copied, err := c.copySecret(srcNamespace, ref.Name)
+		if err != nil {
+			return err
+		}
+		if copied {
+			pullSecretNames = append(pullSecretNames, ref.Name)
+		}
+	}
+
+	dstSAs := c.DstCoreClient.CoreV1().ServiceAccounts(c.ToNamespace)
+
+	dstSA, err := dstSAs.Get(name, metav1.GetOptions{})
+	if err != nil {
+		if !errors.IsNotFound(err) {
+			return fmt.Errorf("Getting destination service account '%s': %s", name, err)
+		}
+
+		c.UI.PrintLinef("Creating service account '%s'", name)
+
+		dstSA = &corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: c.ToNamespace}}
+
+		err = cmdsa.NewServiceAccountSecrets(c.DstCoreClient).Add(dstSA, secretNames, pullSecretNames)
+		if err != nil {
+			return err
+		}
+
+		_, err = dstSAs.Create(dstSA)
+		if err != nil {
+			return fmt.Errorf("Creating service account '%s': %s", name, err)
+		}
+
+		return nil
+	}
+
+	c.UI.PrintLinef("Updating existing service account '%s'", name)
+
+	err = cmdsa.NewServiceAccountSecrets(c.DstCoreClient).Add(dstSA, secretNames, pullSecretNames)
+	if err != nil {
+		return err
+	}
+
+	_, err = dstSAs.Update(dstSA)
+	if err != nil {
+		return fmt.Errorf("Updating service account '%s': %s", name, err)
+	}
+
+	return nil
+}
+
+// copySecret returns true if secret exists in the destination after copying
+func (c ServiceCopy) copySecret(srcNamespace, name string) (bool, error) {
+	secret, err := c.SrcCoreClient.CoreV1().Secrets(srcNamespace).Get(name, metav1.GetOptions{})
+	if err != nil {
+		return false, fmt.Errorf("Getting secret '%s': %s", name, err)
+	}
+
+	switch secret.Type {
+	case corev1.SecretTypeBasicAuth, corev1.SecretTypeDockerConfigJson:
+		// supported below
+	case corev1.SecretTypeServiceAccountToken:
+		return false, nil // generated per cluster
+	default:
+		c.UI.PrintLinef("Skipping secret '%s' of unsupported type '%s'", name, secret.Type)
+		return false, nil
+	}
+
+	dstSecrets := c.DstCoreClient.CoreV1().Secrets(c.ToNamespace)
+
+	_, err = dstSecrets.Get(name, metav1.GetOptions{})
+	if err == nil {
+		c.UI.PrintLinef("Secret '%s' already exists, skipping", name)
+		return true, nil
+	} else if !errors.IsNotFound(err) {
+		return false, fmt.Errorf("Getting destination secret '%s': %s", name, err)
+	}
+
+	newSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Namespace:   c.ToNamespace,
+			Labels:      secret.Labels,
+			Annotations: secret.Annotations,
+		},
+		Type: secret.Type,
+		Data: map[string][]byte{},
+	}
+
+	for k, v := range secret.Data {
+		newSecret.Data[k] = v
+	}
+
+	if !c.CopySecretValues {
+		switch secret.Type {
+		case corev1.SecretTypeBasicAuth:
+			err = c.askForBasicAuthPassword(newSecret)
+		case corev1.SecretTypeDockerConfigJson:
+			err = c.askForDockerConfigPasswords(newSecret)
+		}
+		if err != nil {
+			return false, err
+		}
+	}
+
+	c.UI.PrintLinef("Creating secret '%s'", name)
+
+	_, err = dstSecrets.Create(newSecret)
+	if err != nil {
+		return false, fmt.Errorf("Creating secret '%s': %s", name, err)
+	}
+
+	return true, nil
+}
+
+func (c ServiceCopy) askForBasicAuthPassword(secret *corev1.Secret) error {
+	username := string(secret.Data[corev1.BasicAuthUsernameKey])
+
+	password, err := c.UI.AskForPassword(fmt.Sprintf(
+		"Password for secret '%s' (username '%s')", secret.Name, username))
+	if err != nil {
+		return err
+	}
+
+	secret.Data[corev1.BasicAuthPasswordKey] = []byte(password)
+
+	return nil
+}
+
+func (c ServiceCopy) askForDockerConfigPasswords(secret *corev1.Secret) error {
+	var config map[string]map[string]map[string]interface{}
+
+	err := json.Unmarshal(secret.Data[corev1.DockerConfigJsonKey], &config)
+	if err != nil {
+		return fmt.Errorf("Unmarshaling docker config of secret '%s': %s", secret.Name, err)
+	}
+
+	auths := config["auths"]
+
+	var registries []string
+	for registry, _ := range auths {
+		registries = append(registries, registry)
+	}
+
+	sort.Strings(registries)
+
+	for _, registry := range registries {
+		auth := auths[registry]
+		username, _ := auth["username"].(string)
+
+		if len(username) == 0 {
+			// Credentials may only be stored in encoded 'auth' field
+			decoded, err := base64.StdEncoding.DecodeString(fmt.Sprintf("%s", auth["auth"]))
+			if err == nil {
+				username = strings.SplitN(string(decoded), ":", 2)[0]
+			}
+		}
+
+		password, err := c.UI.AskForPassword(fmt.Sprintf(
+			"Password for registry '%s' in secret '%s' (username '%s')", registry, secret.Name, username))
+		if err != nil {
+			return err
+		}
+
+		auth["username"] = username
+		auth["password"] = password
+		auth["auth"] = base64.StdEncoding.EncodeToString([]byte(username + ":" + password))
+	}
+
+	contentBytes, err := json.Marshal(config)
+	if err != nil {
+		return err
+	}
+
+	secret.Data[corev1.DockerConfigJsonKey] = contentBytes
+
+	return nil
+}
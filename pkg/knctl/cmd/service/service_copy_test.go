@@ -0,0 +1,89 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service_test
+
+import (
+	"testing"
+
+	"github.com/cppforlife/go-cli-ui/ui"
+	. "github.com/cppforlife/knctl/pkg/knctl/cmd/service"
+	"github.com/cppforlife/knctl/pkg/knctl/testkit"
+	"github.com/knative/serving/pkg/apis/serving/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestServiceCopyPinsLatestReadyRevisionDigest(t *testing.T) {
+	service := testkit.NewService("ns1", "svc1").LatestRevision("rev2", "rev1").Build()
+	service.Spec.RunLatest = nil
+	service.Spec.Release = &v1alpha1.ReleaseType{
+		Revisions: []string{"rev1"},
+		Configuration: v1alpha1.ConfigurationSpec{
+			RevisionTemplate: v1alpha1.RevisionTemplateSpec{
+				Spec: v1alpha1.RevisionSpec{Container: corev1.Container{Image: "img:latest"}},
+			},
+		},
+	}
+
+	revision := testkit.NewRevision("ns1", "svc1", "rev1").Ready().Build()
+	revision.Spec.Container = corev1.Container{Image: "img:latest"}
+	revision.Status.ImageDigest = "img@sha256:abc"
+
+	srcCluster := testkit.NewCluster(t, service, revision)
+	dstCluster := testkit.NewCluster(t)
+
+	serviceCopy := ServiceCopy{
+		SrcServingClient: srcCluster.ServingClient(),
+		SrcCoreClient:    srcCluster.CoreClient(),
+		DstCoreClient:    dstCluster.CoreClient(),
+		DstServingClient: dstCluster.ServingClient(),
+		UI:               ui.NewNoopUI(),
+
+		ToNamespace: "ns2",
+	}
+
+	err := serviceCopy.Copy(*service)
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	copied, err := dstCluster.ServingClient().ServingV1alpha1().Services("ns2").Get("svc1", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	if copied.Spec.Release != nil || copied.Spec.RunLatest == nil {
+		t.Fatalf("Expected copied service to run latest configuration, but was: %#v", copied.Spec)
+	}
+
+	image := copied.Spec.RunLatest.Configuration.RevisionTemplate.Spec.Container.Image
+	if image != "img@sha256:abc" {
+		t.Fatalf("Expected copied service to use image digest, but was: %s", image)
+	}
+}
+
+func TestServiceCopyRequiresReadyRevision(t *testing.T) {
+	service := testkit.NewService("ns1", "svc1").LatestRevision("rev1", "").Build()
+	srcCluster := testkit.NewCluster(t, service)
+
+	serviceCopy := ServiceCopy{SrcServingClient: srcCluster.ServingClient(), UI: ui.NewNoopUI(), ToNamespace: "ns2"}
+
+	err := serviceCopy.Copy(*service)
+	if err == nil || err.Error() != "Expected service 'svc1' to have ready revision" {
+		t.Fatalf("Expected error, but was: %v", err)
+	}
+}
@@ -0,0 +1,113 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"sort"
+
+	"github.com/knative/serving/pkg/apis/serving"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+const (
+	hoursPerMonth = 730
+	bytesPerGiB   = 1024 * 1024 * 1024
+)
+
+type CostPrices struct {
+	CPUHour    float64 // per vCPU per hour
+	MemGiBHour float64 // per GiB of memory per hour
+}
+
+type RevisionCostEstimate struct {
+	Revision    string
+	AvgReplicas float64
+	CPURequest  resource.Quantity // per pod
+	MemRequest  resource.Quantity // per pod
+	MonthlyCost float64
+}
+
+// ServiceCost estimates monthly cost of service's revisions based on
+// number of running pods observed in samples and their resource requests
+type ServiceCost struct {
+	samples  int
+	replicas map[string]int
+	requests map[string]corev1.ResourceList
+}
+
+func NewServiceCost() *ServiceCost {
+	return &ServiceCost{
+		replicas: map[string]int{},
+		requests: map[string]corev1.ResourceList{},
+	}
+}
+
+func (c *ServiceCost) AddSample(pods []corev1.Pod) {
+	c.samples++
+
+	for _, pod := range pods {
+		if pod.Status.Phase != corev1.PodRunning {
+			continue
+		}
+
+		revName := pod.Labels[serving.RevisionLabelKey]
+		c.replicas[revName]++
+		c.requests[revName] = c.podRequests(pod)
+	}
+}
+
+func (c *ServiceCost) Estimates(prices CostPrices) []RevisionCostEstimate {
+	var result []RevisionCostEstimate
+
+	for revName, replicas := range c.replicas {
+		requests := c.requests[revName]
+		avgReplicas := float64(replicas) / float64(c.samples)
+
+		cpu := requests[corev1.ResourceCPU]
+		mem := requests[corev1.ResourceMemory]
+
+		hourlyPodCost := float64(cpu.MilliValue())/1000*prices.CPUHour +
+			float64(mem.Value())/bytesPerGiB*prices.MemGiBHour
+
+		result = append(result, RevisionCostEstimate{
+			Revision:    revName,
+			AvgReplicas: avgReplicas,
+			CPURequest:  cpu,
+			MemRequest:  mem,
+			MonthlyCost: avgReplicas * hourlyPodCost * hoursPerMonth,
+		})
+	}
+
+	sort.Slice(result, func(i, j int) bool { return result[i].Revision < result[j].Revision })
+
+	return result
+}
+
+func (c *ServiceCost) podRequests(pod corev1.Pod) corev1.ResourceList {
+	result := corev1.ResourceList{}
+
+	for _, cont := range pod.Spec.Containers {
+		for name, quantity := range cont.Resources.Requests {
+			total := result[name]
+			total.Add(quantity)
+			result[name] = total
+		}
+	}
+
+	return result
+}
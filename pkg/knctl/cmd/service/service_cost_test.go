@@ -0,0 +1,96 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service_test
+
+import (
+	"fmt"
+	"testing"
+
+	. "github.com/cppforlife/knctl/pkg/knctl/cmd"
+	. "github.com/cppforlife/knctl/pkg/knctl/cmd/service"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func newCostTestPod(name, revision string, phase corev1.PodPhase) corev1.Pod {
+	return corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "ns1",
+			Labels: map[string]string{
+				"serving.knative.dev/service":  "svc1",
+				"serving.knative.dev/revision": revision,
+			},
+		},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{
+				Name: "user-container",
+				Resources: corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{
+						corev1.ResourceCPU:    resource.MustParse("500m"),
+						corev1.ResourceMemory: resource.MustParse("1Gi"),
+					},
+				},
+			}, {
+				Name: "queue-proxy",
+				Resources: corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{
+						corev1.ResourceCPU: resource.MustParse("500m"),
+					},
+				},
+			}},
+		},
+		Status: corev1.PodStatus{Phase: phase},
+	}
+}
+
+func TestServiceCost_Estimates(t *testing.T) {
+	cost := NewServiceCost()
+
+	cost.AddSample([]corev1.Pod{
+		newCostTestPod("pod1", "rev1", corev1.PodRunning),
+		newCostTestPod("pod2", "rev1", corev1.PodRunning),
+		newCostTestPod("pod3", "rev2", corev1.PodPending),
+	})
+	cost.AddSample([]corev1.Pod{
+		newCostTestPod("pod1", "rev1", corev1.PodRunning),
+		newCostTestPod("pod3", "rev2", corev1.PodRunning),
+	})
+
+	estimates := cost.Estimates(CostPrices{CPUHour: 0.03, MemGiBHour: 0.004})
+
+	if len(estimates) != 2 {
+		t.Fatalf("Expected two revisions but was: %#v", estimates)
+	}
+
+	rev1 := estimates[0]
+
+	DeepEqual(t, rev1.Revision, "rev1")
+	DeepEqual(t, rev1.AvgReplicas, 1.5)
+	DeepEqual(t, rev1.CPURequest.String(), "1")
+	DeepEqual(t, rev1.MemRequest.String(), "1Gi")
+
+	// 1.5 replicas * (1 vCPU * 0.03 + 1 GiB * 0.004) * 730h
+	DeepEqual(t, fmt.Sprintf("%.2f", rev1.MonthlyCost), "37.23")
+
+	rev2 := estimates[1]
+
+	DeepEqual(t, rev2.Revision, "rev2")
+	DeepEqual(t, rev2.AvgReplicas, 0.5)
+	DeepEqual(t, fmt.Sprintf("%.2f", rev2.MonthlyCost), "12.41")
+}
@@ -0,0 +1,125 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"fmt"
+	"sort"
+
+	buildv1alpha1 "github.com/knative/build/pkg/apis/build/v1alpha1"
+	buildclientset "github.com/knative/build/pkg/client/clientset/versioned"
+	"github.com/knative/serving/pkg/apis/serving"
+	"github.com/knative/serving/pkg/apis/serving/v1alpha1"
+	servingclientset "github.com/knative/serving/pkg/client/clientset/versioned"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	serviceOverviewMaxBuilds = 3
+)
+
+// ServiceOverview combines service with its relevant revisions and builds
+type ServiceOverview struct {
+	Service *v1alpha1.Service
+	Routes  []v1alpha1.Route
+
+	// Revisions that are either latest or receive traffic, latest first
+	Revisions []v1alpha1.Revision
+
+	// Builds of service's revisions, latest first
+	Builds []buildv1alpha1.Build
+}
+
+type ServiceOverviews struct {
+	servingClient servingclientset.Interface
+	buildClient   buildclientset.Interface
+}
+
+func NewServiceOverviews(servingClient servingclientset.Interface, buildClient buildclientset.Interface) ServiceOverviews {
+	return ServiceOverviews{servingClient, buildClient}
+}
+
+func (s ServiceOverviews) Get(namespace, name string) (ServiceOverview, error) {
+	service, err := s.servingClient.ServingV1alpha1().Services(namespace).Get(name, metav1.GetOptions{})
+	if err != nil {
+		return ServiceOverview{}, fmt.Errorf("Getting service: %s", err)
+	}
+
+	listOpts := metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("%s=%s", serving.ServiceLabelKey, name),
+	}
+
+	revisions, err := s.servingClient.ServingV1alpha1().Revisions(namespace).List(listOpts)
+	if err != nil {
+		return ServiceOverview{}, fmt.Errorf("Listing revisions: %s", err)
+	}
+
+	routes, err := s.servingClient.ServingV1alpha1().Routes(namespace).List(metav1.ListOptions{})
+	if err != nil {
+		return ServiceOverview{}, fmt.Errorf("Listing routes: %s", err)
+	}
+
+	overview := ServiceOverview{Service: service, Routes: routes.Items}
+
+	routedRevisions := map[string]struct{}{
+		service.Status.LatestCreatedRevisionName: struct{}{},
+		service.Status.LatestReadyRevisionName:   struct{}{},
+	}
+
+	for _, route := range routes.Items {
+		for _, target := range route.Status.Traffic {
+			routedRevisions[target.RevisionName] = struct{}{}
+		}
+	}
+
+	allRevisions := revisions.Items
+
+	sort.Slice(allRevisions, func(i, j int) bool {
+		return allRevisions[j].CreationTimestamp.Before(&allRevisions[i].CreationTimestamp)
+	})
+
+	for _, rev := range allRevisions {
+		if _, found := routedRevisions[rev.Name]; found {
+			overview.Revisions = append(overview.Revisions, rev)
+		}
+	}
+
+	// Revisions are already sorted hence builds are as well
+	for _, rev := range allRevisions {
+		if len(overview.Builds) == serviceOverviewMaxBuilds {
+			break
+		}
+
+		buildRef := rev.BuildRef()
+		if buildRef == nil || buildRef.Kind != "Build" {
+			continue
+		}
+
+		build, err := s.buildClient.BuildV1alpha1().Builds(namespace).Get(buildRef.Name, metav1.GetOptions{})
+		if err != nil {
+			if errors.IsNotFound(err) {
+				continue // builds may be cleaned up independently
+			}
+			return ServiceOverview{}, fmt.Errorf("Getting build '%s': %s", buildRef.Name, err)
+		}
+
+		overview.Builds = append(overview.Builds, *build)
+	}
+
+	return overview, nil
+}
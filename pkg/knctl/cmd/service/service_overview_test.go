@@ -0,0 +1,87 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service_test
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/cppforlife/knctl/pkg/knctl/cmd"
+	. "github.com/cppforlife/knctl/pkg/knctl/cmd/service"
+	"github.com/cppforlife/knctl/pkg/knctl/testkit"
+	buildv1alpha1 "github.com/knative/build/pkg/apis/build/v1alpha1"
+	"github.com/knative/serving/pkg/apis/serving/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestServiceOverviews_Get(t *testing.T) {
+	now := time.Now()
+
+	var revisions []*v1alpha1.Revision
+
+	for i, name := range []string{"svc1-00001", "svc1-00002", "svc1-00003", "svc1-00004", "svc1-00005"} {
+		rev := testkit.NewRevision("ns1", "svc1", name).CreatedAt(now.Add(time.Duration(i) * time.Minute)).Build()
+		rev.Spec.BuildName = name + "-build"
+		revisions = append(revisions, rev)
+	}
+
+	cluster := testkit.NewCluster(t,
+		testkit.NewService("ns1", "svc1").LatestRevision("svc1-00005", "svc1-00004").Build(),
+		revisions[0], revisions[1], revisions[2], revisions[3], revisions[4],
+		testkit.NewRevision("ns1", "svc2", "svc2-00001").Build(),
+		testkit.NewRoute("ns1", "svc1").Traffic("svc1-00002", 100).Build(),
+		newTestBuild("ns1", "svc1-00001-build"),
+		newTestBuild("ns1", "svc1-00002-build"),
+		newTestBuild("ns1", "svc1-00003-build"),
+		newTestBuild("ns1", "svc1-00005-build"),
+	)
+
+	overview, err := NewServiceOverviews(cluster.ServingClient(), cluster.BuildClient()).Get("ns1", "svc1")
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	DeepEqual(t, overview.Service.Name, "svc1")
+
+	var revisionNames []string
+	for _, rev := range overview.Revisions {
+		revisionNames = append(revisionNames, rev.Name)
+	}
+
+	DeepEqual(t, revisionNames, []string{"svc1-00005", "svc1-00004", "svc1-00002"})
+
+	var buildNames []string
+	for _, build := range overview.Builds {
+		buildNames = append(buildNames, build.Name)
+	}
+
+	// Build of svc1-00004 was deleted
+	DeepEqual(t, buildNames, []string{"svc1-00005-build", "svc1-00003-build", "svc1-00002-build"})
+}
+
+func TestServiceOverviews_GetMissingService(t *testing.T) {
+	cluster := testkit.NewCluster(t)
+
+	_, err := NewServiceOverviews(cluster.ServingClient(), cluster.BuildClient()).Get("ns1", "svc1")
+	if err == nil {
+		t.Fatalf("Expected error")
+	}
+}
+
+func newTestBuild(namespace, name string) *buildv1alpha1.Build {
+	return &buildv1alpha1.Build{ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name}}
+}
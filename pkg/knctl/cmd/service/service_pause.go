@@ -0,0 +1,146 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/cppforlife/go-cli-ui/ui"
+	"github.com/knative/serving/pkg/apis/serving/v1alpha1"
+	servingclientset "github.com/knative/serving/pkg/client/clientset/versioned"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
+)
+
+const (
+	pausedSpecAnnKey = "pause.cli.knative.dev/spec"
+)
+
+// ServicePause routes traffic of a service to a maintenance service
+// (so that service's revisions scale down) and later restores
+// service's original spec that was saved in an annotation
+type ServicePause struct {
+	ServingClient servingclientset.Interface
+	UI            ui.UI
+}
+
+func (p ServicePause) Pause(namespace, name, maintenanceServiceName string) error {
+	services := p.ServingClient.ServingV1alpha1().Services(namespace)
+
+	_, err := services.Get(maintenanceServiceName, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("Getting maintenance service '%s': %s", maintenanceServiceName, err)
+	}
+
+	err = retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		service, err := services.Get(name, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+
+		if _, found := service.Annotations[pausedSpecAnnKey]; found {
+			return fmt.Errorf("Expected service '%s' to not be already paused", name)
+		}
+
+		if service.Spec.Manual != nil {
+			return fmt.Errorf("Expected service '%s' to manage its route (manual mode is not supported)", name)
+		}
+
+		specBytes, err := json.Marshal(service.Spec)
+		if err != nil {
+			return err
+		}
+
+		if service.Annotations == nil {
+			service.Annotations = map[string]string{}
+		}
+
+		service.Annotations[pausedSpecAnnKey] = string(specBytes)
+		service.Spec = v1alpha1.ServiceSpec{Manual: &v1alpha1.ManualType{}}
+
+		_, err = services.Update(service)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("Pausing service: %s", err)
+	}
+
+	p.UI.PrintLinef("Routing traffic of service '%s' to maintenance service '%s'", name, maintenanceServiceName)
+
+	// Assumes that service has the same name as the route
+	routes := p.ServingClient.ServingV1alpha1().Routes(namespace)
+
+	err = retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		route, err := routes.Get(name, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+
+		route.Spec.Traffic = []v1alpha1.TrafficTarget{{
+			ConfigurationName: maintenanceServiceName,
+			Percent:           100,
+		}}
+
+		_, err = routes.Update(route)
+		return err
+	})
+	if err != nil {
+		// Roll back so that service is not left in manual mode without routing to maintenance service
+		rollbackErr := p.Resume(namespace, name)
+		if rollbackErr != nil {
+			return fmt.Errorf("Updating route: %s (restoring service spec also failed: %s)", err, rollbackErr)
+		}
+		return fmt.Errorf("Updating route: %s", err)
+	}
+
+	return nil
+}
+
+func (p ServicePause) Resume(namespace, name string) error {
+	services := p.ServingClient.ServingV1alpha1().Services(namespace)
+
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		service, err := services.Get(name, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+
+		specStr, found := service.Annotations[pausedSpecAnnKey]
+		if !found {
+			return fmt.Errorf("Expected service '%s' to be paused", name)
+		}
+
+		var spec v1alpha1.ServiceSpec
+
+		err = json.Unmarshal([]byte(specStr), &spec)
+		if err != nil {
+			return fmt.Errorf("Unmarshaling paused service spec: %s", err)
+		}
+
+		delete(service.Annotations, pausedSpecAnnKey)
+		service.Spec = spec
+
+		_, err = services.Update(service)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("Resuming service: %s", err)
+	}
+
+	return nil
+}
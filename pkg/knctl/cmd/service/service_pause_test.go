@@ -0,0 +1,124 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/cppforlife/go-cli-ui/ui"
+	. "github.com/cppforlife/knctl/pkg/knctl/cmd"
+	. "github.com/cppforlife/knctl/pkg/knctl/cmd/service"
+	"github.com/cppforlife/knctl/pkg/knctl/testkit"
+	"github.com/knative/serving/pkg/apis/serving/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestServicePause_PauseAndResume(t *testing.T) {
+	svc := testkit.NewService("ns1", "svc1").Build()
+	svc.Spec.RunLatest.Configuration.RevisionTemplate.Annotations = map[string]string{
+		"autoscaling.knative.dev/minScale": "2",
+	}
+
+	cluster := testkit.NewCluster(t,
+		svc,
+		testkit.NewService("ns1", "maintenance").Build(),
+		testkit.NewRoute("ns1", "svc1").Traffic("svc1-00001", 100).Build(),
+	)
+
+	servingClient := cluster.ServingClient()
+	pause := ServicePause{servingClient, ui.NewNoopUI()}
+
+	err := pause.Pause("ns1", "svc1", "maintenance")
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	pausedSvc, err := servingClient.ServingV1alpha1().Services("ns1").Get("svc1", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	if pausedSvc.Spec.Manual == nil || pausedSvc.Spec.RunLatest != nil {
+		t.Fatalf("Expected service to be switched to manual mode: %#v", pausedSvc.Spec)
+	}
+
+	route, err := servingClient.ServingV1alpha1().Routes("ns1").Get("svc1", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	DeepEqual(t, route.Spec.Traffic, []v1alpha1.TrafficTarget{{ConfigurationName: "maintenance", Percent: 100}})
+
+	err = pause.Pause("ns1", "svc1", "maintenance")
+	if err == nil {
+		t.Fatalf("Expected error pausing already paused service")
+	}
+
+	err = pause.Resume("ns1", "svc1")
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	resumedSvc, err := servingClient.ServingV1alpha1().Services("ns1").Get("svc1", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	DeepEqual(t, resumedSvc.Spec, svc.Spec)
+
+	if _, found := resumedSvc.Annotations["pause.cli.knative.dev/spec"]; found {
+		t.Fatalf("Expected paused spec annotation to be removed")
+	}
+
+	err = pause.Resume("ns1", "svc1")
+	if err == nil {
+		t.Fatalf("Expected error resuming service that is not paused")
+	}
+}
+
+func TestServicePause_MissingMaintenanceService(t *testing.T) {
+	cluster := testkit.NewCluster(t, testkit.NewService("ns1", "svc1").Build())
+
+	err := ServicePause{cluster.ServingClient(), ui.NewNoopUI()}.Pause("ns1", "svc1", "maintenance")
+	if err == nil {
+		t.Fatalf("Expected error for missing maintenance service")
+	}
+}
+
+func TestServicePause_MissingRouteRestoresService(t *testing.T) {
+	svc := testkit.NewService("ns1", "svc1").Build()
+
+	cluster := testkit.NewCluster(t, svc, testkit.NewService("ns1", "maintenance").Build())
+	servingClient := cluster.ServingClient()
+
+	err := ServicePause{servingClient, ui.NewNoopUI()}.Pause("ns1", "svc1", "maintenance")
+	if err == nil || !strings.HasPrefix(err.Error(), "Updating route: ") {
+		t.Fatalf("Expected route update error, but was: %v", err)
+	}
+
+	restoredSvc, err := servingClient.ServingV1alpha1().Services("ns1").Get("svc1", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	DeepEqual(t, restoredSvc.Spec, svc.Spec)
+
+	if _, found := restoredSvc.Annotations["pause.cli.knative.dev/spec"]; found {
+		t.Fatalf("Expected paused spec annotation to be removed")
+	}
+}
@@ -0,0 +1,292 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cppforlife/go-cli-ui/ui"
+	ctlservice "github.com/cppforlife/knctl/pkg/knctl/service"
+	"github.com/cppforlife/knctl/pkg/knctl/util"
+	"github.com/cppforlife/knctl/pkg/knctl/waiter"
+	"github.com/knative/serving/pkg/apis/serving/v1alpha1"
+	servingclientset "github.com/knative/serving/pkg/client/clientset/versioned"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	apirand "k8s.io/apimachinery/pkg/util/rand"
+)
+
+// ServicePromotion copies configuration of service's latest ready revision
+// into (possibly) another cluster and rolls it out
+type ServicePromotion struct {
+	SrcClient servingclientset.Interface
+	DstClient servingclientset.Interface
+	DstWaiter *waiter.Waiter
+	UI        ui.UI
+
+	ToNamespace string
+	MinScale    *int
+	MaxScale    *int
+
+	RolloutPercentages []int
+	RolloutInterval    time.Duration
+	ReadyTimeout       time.Duration
+}
+
+func (p ServicePromotion) Promote(service v1alpha1.Service) error {
+	confSpec, err := p.PromotedConfigurationSpec(service)
+	if err != nil {
+		return err
+	}
+
+	dstServices := p.DstClient.ServingV1alpha1().Services(p.ToNamespace)
+
+	dstService, err := dstServices.Get(service.Name, metav1.GetOptions{})
+	if err != nil {
+		if !errors.IsNotFound(err) {
+			return fmt.Errorf("Getting destination service: %s", err)
+		}
+
+		p.UI.PrintLinef("Creating service '%s' in namespace '%s'", service.Name, p.ToNamespace)
+
+		dstService = &v1alpha1.Service{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      service.Name,
+				Namespace: p.ToNamespace,
+			},
+		}
+
+		p.setSpec(dstService, confSpec, nil, 0)
+
+		_, err := dstServices.Create(dstService)
+		if err != nil {
+			return fmt.Errorf("Creating destination service: %s", err)
+		}
+
+		_, err = p.waitForNewRevision(service.Name, "")
+		return err
+	}
+
+	currRevisionName := dstService.Status.LatestReadyRevisionName
+	prevCreatedRevisionName := dstService.Status.LatestCreatedRevisionName
+
+	if len(p.RolloutPercentages) == 0 || len(currRevisionName) == 0 {
+		p.UI.PrintLinef("Updating service '%s' in namespace '%s'", service.Name, p.ToNamespace)
+
+		err = p.updateService(service.Name, confSpec, nil, 0)
+		if err != nil {
+			return err
+		}
+
+		_, err = p.waitForNewRevision(service.Name, prevCreatedRevisionName)
+		return err
+	}
+
+	p.UI.PrintLinef("Updating service '%s' in namespace '%s' keeping traffic on revision '%s'",
+		service.Name, p.ToNamespace, currRevisionName)
+
+	err = p.updateService(service.Name, confSpec, []string{currRevisionName}, 0)
+	if err != nil {
+		return err
+	}
+
+	candidateRevisionName, err := p.waitForNewRevision(service.Name, prevCreatedRevisionName)
+	if err != nil {
+		return err
+	}
+
+	for _, percent := range p.RolloutPercentages {
+		p.UI.PrintLinef("Routing %d%% of traffic to revision '%s'", percent, candidateRevisionName)
+
+		err = p.updateService(service.Name, confSpec, []string{currRevisionName, candidateRevisionName}, percent)
+		if err != nil {
+			return err
+		}
+
+		p.UI.PrintLinef("Waiting %s before next rollout step", p.RolloutInterval)
+		time.Sleep(p.RolloutInterval)
+	}
+
+	p.UI.PrintLinef("Routing all traffic to revision '%s'", candidateRevisionName)
+
+	return p.updateService(service.Name, confSpec, nil, 0)
+}
+
+// PromotedConfigurationSpec returns configuration of latest ready revision
+// with image pinned to digest and overrides applied
+func (p ServicePromotion) PromotedConfigurationSpec(service v1alpha1.Service) (v1alpha1.ConfigurationSpec, error) {
+	revisionName := service.Status.LatestReadyRevisionName
+	if len(revisionName) == 0 {
+		return v1alpha1.ConfigurationSpec{}, fmt.Errorf("Expected service '%s' to have ready revision", service.Name)
+	}
+
+	revision, err := p.SrcClient.ServingV1alpha1().Revisions(service.Namespace).Get(revisionName, metav1.GetOptions{})
+	if err != nil {
+		return v1alpha1.ConfigurationSpec{}, fmt.Errorf("Getting revision '%s': %s", revisionName, err)
+	}
+
+	if len(revision.Status.ImageDigest) == 0 {
+		return v1alpha1.ConfigurationSpec{}, fmt.Errorf("Expected revision '%s' to have resolved image digest", revisionName)
+	}
+
+	spec := v1alpha1.ConfigurationSpec{
+		RevisionTemplate: v1alpha1.RevisionTemplateSpec{
+			ObjectMeta: metav1.ObjectMeta{
+				Labels:      p.userLabels(revision.Labels),
+				Annotations: p.userAnnotations(revision.Annotations),
+			},
+			Spec: *revision.Spec.DeepCopy(),
+		},
+	}
+
+	// Image is already built; build is not copied
+	spec.RevisionTemplate.Spec.Container.Image = revision.Status.ImageDigest
+	spec.RevisionTemplate.Spec.BuildName = ""
+	spec.RevisionTemplate.Spec.BuildRef = nil
+	spec.RevisionTemplate.Spec.Generation = 0
+	spec.RevisionTemplate.Spec.DeprecatedServingState = ""
+
+	if p.MinScale != nil {
+		spec.RevisionTemplate.Annotations["autoscaling.knative.dev/minScale"] = strconv.Itoa(*p.MinScale)
+	}
+	if p.MaxScale != nil {
+		spec.RevisionTemplate.Annotations["autoscaling.knative.dev/maxScale"] = strconv.Itoa(*p.MaxScale)
+	}
+
+	// Force new revision even if destination already runs same configuration
+	env := []corev1.EnvVar{{Name: "KNCTL_DEPLOY", Value: apirand.String(10)}}
+
+	for _, envVar := range spec.RevisionTemplate.Spec.Container.Env {
+		if envVar.Name != "KNCTL_DEPLOY" {
+			env = append(env, envVar)
+		}
+	}
+
+	spec.RevisionTemplate.Spec.Container.Env = env
+
+	return spec, nil
+}
+
+func (p ServicePromotion) updateService(name string, confSpec v1alpha1.ConfigurationSpec, revisions []string, percent int) error {
+	return util.Retry(time.Second, 10*time.Second, func() (bool, error) {
+		service, err := p.DstClient.ServingV1alpha1().Services(p.ToNamespace).Get(name, metav1.GetOptions{})
+		if err != nil {
+			return false, fmt.Errorf("Getting destination service: %s", err)
+		}
+
+		p.setSpec(service, confSpec, revisions, percent)
+
+		_, err = p.DstClient.ServingV1alpha1().Services(p.ToNamespace).Update(service)
+		if err != nil {
+			return false, fmt.Errorf("Updating destination service: %s", err)
+		}
+
+		return true, nil
+	})
+}
+
+// setSpec configures service to run latest revision when no revisions
+// are specified, otherwise uses release mode to split traffic.
+// Namespace label is reset so that destination namespace's default domain applies.
+func (p ServicePromotion) setSpec(service *v1alpha1.Service, confSpec v1alpha1.ConfigurationSpec, revisions []string, percent int) {
+	if service.Labels == nil {
+		service.Labels = map[string]string{}
+	}
+
+	service.Labels[ctlservice.NamespaceLabelKey] = p.ToNamespace
+
+	generation := service.Spec.Generation
+
+	if len(revisions) == 0 {
+		service.Spec = v1alpha1.ServiceSpec{RunLatest: &v1alpha1.RunLatestType{Configuration: confSpec}}
+	} else {
+		service.Spec = v1alpha1.ServiceSpec{Release: &v1alpha1.ReleaseType{
+			Revisions:      revisions,
+			RolloutPercent: percent,
+			Configuration:  confSpec,
+		}}
+	}
+
+	service.Spec.Generation = generation
+}
+
+func (p ServicePromotion) waitForNewRevision(name, prevRevisionName string) (string, error) {
+	var revisionName string
+
+	p.UI.PrintLinef("Waiting for new revision to be created...")
+
+	cancelCh := make(chan struct{})
+	timer := time.AfterFunc(p.ReadyTimeout, func() { close(cancelCh) })
+	defer timer.Stop()
+
+	newRevisionCreated := func(obj runtime.Object) (bool, error) {
+		revisionName = obj.(*v1alpha1.Service).Status.LatestCreatedRevisionName
+		return len(revisionName) > 0 && revisionName != prevRevisionName, nil
+	}
+
+	_, created, err := p.DstWaiter.Wait(waiter.ServiceKind, p.ToNamespace, name, newRevisionCreated, cancelCh)
+	if err != nil {
+		return "", fmt.Errorf("Waiting for new revision: %s", err)
+	}
+
+	if !created {
+		return "", fmt.Errorf("Waiting for new revision: Timed out after %s", p.ReadyTimeout)
+	}
+
+	p.UI.PrintLinef("Waiting for new revision '%s' to be ready for up to %s...", revisionName, p.ReadyTimeout)
+
+	_, ready, err := p.DstWaiter.RevisionReady(p.ToNamespace, revisionName, cancelCh)
+	if err != nil {
+		return "", err
+	}
+
+	if !ready {
+		return "", fmt.Errorf("Expected revision '%s' to become ready", revisionName)
+	}
+
+	p.UI.PrintLinef("Revision '%s' became ready", revisionName)
+
+	return revisionName, nil
+}
+
+// userLabels excludes labels populated by Knative controllers
+func (ServicePromotion) userLabels(labels map[string]string) map[string]string {
+	result := map[string]string{}
+	for k, v := range labels {
+		if !strings.Contains(k, "knative.dev/") {
+			result[k] = v
+		}
+	}
+	return result
+}
+
+// userAnnotations excludes annotations populated by Knative controllers
+// except for autoscaling configuration
+func (ServicePromotion) userAnnotations(anns map[string]string) map[string]string {
+	result := map[string]string{}
+	for k, v := range anns {
+		if !strings.Contains(k, "knative.dev/") || strings.HasPrefix(k, "autoscaling.knative.dev/") {
+			result[k] = v
+		}
+	}
+	return result
+}
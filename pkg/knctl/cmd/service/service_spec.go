@@ -23,6 +23,7 @@ import (
 
 	ctlbuild "github.com/cppforlife/knctl/pkg/knctl/build"
 	cmdflags "github.com/cppforlife/knctl/pkg/knctl/cmd/flags"
+	ctlservice "github.com/cppforlife/knctl/pkg/knctl/service"
 	buildv1alpha1 "github.com/knative/build/pkg/apis/build/v1alpha1"
 	"github.com/knative/serving/pkg/apis/serving/v1alpha1"
 	corev1 "k8s.io/api/core/v1"
@@ -47,7 +48,8 @@ func (s ServiceSpec) HasBuild() bool {
 }
 
 func (s ServiceSpec) NeedsConfigurationUpdate() bool {
-	return !s.deployFlags.ManagedRoute
+	// Route is managed separately when traffic waits for approval
+	return !s.deployFlags.ManagedRoute || s.deployFlags.RequireApproval
 }
 
 func (s ServiceSpec) Service() (v1alpha1.Service, error) {
@@ -55,6 +57,9 @@ func (s ServiceSpec) Service() (v1alpha1.Service, error) {
 		ObjectMeta: s.deployFlags.GenerateNameFlags.Apply(metav1.ObjectMeta{
 			Name:      s.serviceFlags.Name,
 			Namespace: s.serviceFlags.NamespaceFlags.Name,
+			Labels: map[string]string{
+				ctlservice.NamespaceLabelKey: s.serviceFlags.NamespaceFlags.Name,
+			},
 		}),
 	}
 
@@ -115,6 +120,8 @@ func (s ServiceSpec) Configuration() (v1alpha1.Configuration, error) {
 
 	serviceCont.Env = append(serviceCont.Env, envVars...)
 
+	serviceCont.SecurityContext = s.securityContext()
+
 	// TODO it's convenient to force redeploy anytime deploy is issued
 	if !s.deployFlags.RemoveKnctlDeployEnvVar {
 		serviceCont.Env = append(serviceCont.Env, corev1.EnvVar{
@@ -131,6 +138,12 @@ func (s ServiceSpec) Configuration() (v1alpha1.Configuration, error) {
 	if s.deployFlags.MaxScale != nil {
 		revisionAnns["autoscaling.knative.dev/maxScale"] = strconv.Itoa(*s.deployFlags.MaxScale)
 	}
+	if s.deployFlags.StartupCPUBoost != nil {
+		// Boosted minimum is relaxed on revision's autoscaler once revision is ready (or fails to)
+		if s.deployFlags.MinScale == nil || *s.deployFlags.MinScale < *s.deployFlags.StartupCPUBoost {
+			revisionAnns["autoscaling.knative.dev/minScale"] = strconv.Itoa(*s.deployFlags.StartupCPUBoost)
+		}
+	}
 
 	conf := v1alpha1.Configuration{
 		// ObjectMeta is populated when object is being created
@@ -156,6 +169,37 @@ func (s ServiceSpec) Configuration() (v1alpha1.Configuration, error) {
 	return conf, nil
 }
 
+func (s ServiceSpec) securityContext() *corev1.SecurityContext {
+	result := &corev1.SecurityContext{}
+	populated := false
+
+	if s.deployFlags.RunAsUser != nil {
+		runAsUser := int64(*s.deployFlags.RunAsUser)
+		result.RunAsUser = &runAsUser
+		populated = true
+	}
+
+	if s.deployFlags.ReadOnlyRootFS {
+		readOnlyRootFS := true
+		result.ReadOnlyRootFilesystem = &readOnlyRootFS
+		populated = true
+	}
+
+	if len(s.deployFlags.DropCapabilities) > 0 {
+		result.Capabilities = &corev1.Capabilities{}
+		for _, capability := range s.deployFlags.DropCapabilities {
+			result.Capabilities.Drop = append(result.Capabilities.Drop, corev1.Capability(capability))
+		}
+		populated = true
+	}
+
+	if !populated {
+		return nil
+	}
+
+	return result
+}
+
 func (s ServiceSpec) buildEnvFromSecrets(deployFlags DeployFlags) ([]corev1.EnvVar, error) {
 	var result []corev1.EnvVar
 
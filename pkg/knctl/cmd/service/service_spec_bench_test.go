@@ -0,0 +1,69 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	cmdcore "github.com/cppforlife/knctl/pkg/knctl/cmd/core"
+	cmdflags "github.com/cppforlife/knctl/pkg/knctl/cmd/flags"
+	. "github.com/cppforlife/knctl/pkg/knctl/cmd/service"
+	"github.com/cppforlife/knctl/pkg/knctl/testkit"
+)
+
+// Measures building of deploy resources without contacting API server
+func BenchmarkServiceSpec_Deploy(b *testing.B) {
+	serviceFlags := cmdflags.ServiceFlags{
+		NamespaceFlags: cmdcore.NamespaceFlags{Name: "ns1"},
+		Name:           "svc1",
+	}
+
+	var envVars []string
+	for i := 0; i < 50; i++ {
+		envVars = append(envVars, fmt.Sprintf("KEY%d=val%d", i, i))
+	}
+
+	deployFlags := DeployFlags{
+		Image:         "gcr.io/knative-samples/helloworld-go",
+		EnvVars:       envVars,
+		EnvSecrets:    []string{"SECRET1=secret1/key1"},
+		EnvConfigMaps: []string{"CONFIG1=config1/key1"},
+		ManagedRoute:  true,
+	}
+
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		spec := NewServiceSpec(serviceFlags, deployFlags)
+
+		_, err := spec.Service()
+		if err != nil {
+			b.Fatalf("Expected no error: %s", err)
+		}
+
+		_, err = spec.Configuration()
+		if err != nil {
+			b.Fatalf("Expected no error: %s", err)
+		}
+	}
+}
+
+func TestServiceSpec_DeployBenchmarkThreshold(t *testing.T) {
+	testkit.RequireBenchmarkThreshold(t, BenchmarkServiceSpec_Deploy, 5*time.Millisecond)
+}
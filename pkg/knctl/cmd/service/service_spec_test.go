@@ -64,6 +64,9 @@ func TestServiceSpecWithConcurrency(t *testing.T) {
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      "test-service",
 			Namespace: "test-namespace",
+			Labels: map[string]string{
+				"namespace.cli.knative.dev/name": "test-namespace",
+			},
 		},
 		Spec: v1alpha1.ServiceSpec{
 			RunLatest: &v1alpha1.RunLatestType{
@@ -126,6 +129,9 @@ func TestServiceSpecWithBuildConfiguration(t *testing.T) {
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      "test-service",
 			Namespace: "test-namespace",
+			Labels: map[string]string{
+				"namespace.cli.knative.dev/name": "test-namespace",
+			},
 		},
 		Spec: v1alpha1.ServiceSpec{
 			RunLatest: &v1alpha1.RunLatestType{
@@ -198,6 +204,9 @@ func TestServiceSpecWithoutBuildConfiguration(t *testing.T) {
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      "test-service",
 			Namespace: "test-namespace",
+			Labels: map[string]string{
+				"namespace.cli.knative.dev/name": "test-namespace",
+			},
 		},
 		Spec: v1alpha1.ServiceSpec{
 			RunLatest: &v1alpha1.RunLatestType{
@@ -224,6 +233,50 @@ func TestServiceSpecWithoutBuildConfiguration(t *testing.T) {
 	}
 }
 
+func TestServiceSpecWithSecurityContext(t *testing.T) {
+	serviceFlags := cmdflags.ServiceFlags{
+		NamespaceFlags: cmdcore.NamespaceFlags{
+			Name: "test-namespace",
+		},
+		Name: "test-service",
+	}
+
+	runAsUser := 1000
+
+	deployFlags := DeployFlags{
+		Image:        "test-image",
+		ManagedRoute: true,
+
+		RunAsUser:        &runAsUser,
+		ReadOnlyRootFS:   true,
+		DropCapabilities: []string{"NET_RAW", "SYS_ADMIN"},
+
+		RemoveKnctlDeployEnvVar: true,
+	}
+
+	spec, err := NewServiceSpec(serviceFlags, deployFlags).Service()
+	if err != nil {
+		t.Fatalf("Expected error to not happen: %s", err)
+	}
+
+	expectedRunAsUser := int64(1000)
+	expectedReadOnlyRootFS := true
+
+	expectedSecurityContext := &corev1.SecurityContext{
+		RunAsUser:              &expectedRunAsUser,
+		ReadOnlyRootFilesystem: &expectedReadOnlyRootFS,
+		Capabilities: &corev1.Capabilities{
+			Drop: []corev1.Capability{"NET_RAW", "SYS_ADMIN"},
+		},
+	}
+
+	securityContext := spec.Spec.RunLatest.Configuration.RevisionTemplate.Spec.Container.SecurityContext
+
+	if !reflect.DeepEqual(securityContext, expectedSecurityContext) {
+		t.Fatalf("Expected security context '%#v' to equal '%#v'", securityContext, expectedSecurityContext)
+	}
+}
+
 func TestServiceSpecWithInvalidEnv(t *testing.T) {
 	serviceFlags := cmdflags.ServiceFlags{
 		NamespaceFlags: cmdcore.NamespaceFlags{
@@ -277,6 +330,9 @@ func TestServiceSpecWithMultipleEnv(t *testing.T) {
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      "test-service",
 			Namespace: "test-namespace",
+			Labels: map[string]string{
+				"namespace.cli.knative.dev/name": "test-namespace",
+			},
 		},
 		Spec: v1alpha1.ServiceSpec{
 			RunLatest: &v1alpha1.RunLatestType{
@@ -389,3 +445,39 @@ func TestServiceSpecWithInvalidEnvConfigMap(t *testing.T) {
 		t.Fatalf("Expected error to happen, but was '%s'", err)
 	}
 }
+
+func TestServiceSpecWithStartupCPUBoost(t *testing.T) {
+	serviceFlags := cmdflags.ServiceFlags{cmdcore.NamespaceFlags{"test-namespace"}, "test-service"}
+
+	intPtr := func(i int) *int { return &i }
+
+	examples := []struct {
+		MinScale         *int
+		StartupCPUBoost  int
+		ExpectedMinScale string
+	}{
+		{nil, 3, "3"},
+		{intPtr(1), 3, "3"},
+		{intPtr(3), 3, "3"},
+		{intPtr(5), 2, "5"},
+	}
+
+	for _, ex := range examples {
+		deployFlags := DeployFlags{
+			Image:           "test-image",
+			ManagedRoute:    true,
+			MinScale:        ex.MinScale,
+			StartupCPUBoost: intPtr(ex.StartupCPUBoost),
+		}
+
+		conf, err := NewServiceSpec(serviceFlags, deployFlags).Configuration()
+		if err != nil {
+			t.Fatalf("Expected error to not happen: %s", err)
+		}
+
+		actualMinScale := conf.Spec.RevisionTemplate.Annotations["autoscaling.knative.dev/minScale"]
+		if actualMinScale != ex.ExpectedMinScale {
+			t.Fatalf("Expected min scale '%s' but was '%s'", ex.ExpectedMinScale, actualMinScale)
+		}
+	}
+}
@@ -0,0 +1,132 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/knative/serving/pkg/apis/serving/v1alpha1"
+	servingclientset "github.com/knative/serving/pkg/client/clientset/versioned"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ServiceStatus is a machine readable summary of service health
+// meant to be consumed by CI pipelines
+type ServiceStatus struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+
+	Ready   bool   `json:"ready"`
+	Reason  string `json:"reason,omitempty"`
+	Message string `json:"message,omitempty"`
+
+	URL string `json:"url,omitempty"`
+
+	LatestCreatedRevision string `json:"latestCreatedRevision,omitempty"`
+	LatestReadyRevision   string `json:"latestReadyRevision,omitempty"`
+
+	// ImageDigest is only set when all routed traffic goes to the same digest
+	ImageDigest string                 `json:"imageDigest,omitempty"`
+	Traffic     []ServiceStatusTraffic `json:"traffic"`
+}
+
+type ServiceStatusTraffic struct {
+	Revision    string `json:"revision"`
+	Percent     int    `json:"percent"`
+	ImageDigest string `json:"imageDigest,omitempty"`
+}
+
+type ServiceStatuses struct {
+	servingClient servingclientset.Interface
+}
+
+func NewServiceStatuses(servingClient servingclientset.Interface) ServiceStatuses {
+	return ServiceStatuses{servingClient}
+}
+
+func (s ServiceStatuses) Get(namespace, name string) (ServiceStatus, error) {
+	service, err := s.servingClient.ServingV1alpha1().Services(namespace).Get(name, metav1.GetOptions{})
+	if err != nil {
+		return ServiceStatus{}, fmt.Errorf("Getting service: %s", err)
+	}
+
+	return s.FromService(service)
+}
+
+func (s ServiceStatuses) FromService(service *v1alpha1.Service) (ServiceStatus, error) {
+	status := ServiceStatus{
+		Namespace: service.Namespace,
+		Name:      service.Name,
+
+		LatestCreatedRevision: service.Status.LatestCreatedRevisionName,
+		LatestReadyRevision:   service.Status.LatestReadyRevisionName,
+
+		Traffic: []ServiceStatusTraffic{},
+	}
+
+	if len(service.Status.Domain) > 0 {
+		status.URL = "http://" + service.Status.Domain
+	}
+
+	// Status of previous generation does not describe current spec
+	if service.Status.ObservedGeneration >= service.Spec.Generation {
+		cond := service.Status.GetCondition(v1alpha1.ServiceConditionReady)
+		if cond != nil {
+			status.Ready = cond.Status == corev1.ConditionTrue
+			status.Reason = cond.Reason
+			status.Message = cond.Message
+		}
+	} else {
+		status.Reason = "NotObserved"
+		status.Message = "Latest service spec has not been observed yet"
+	}
+
+	digests := map[string]struct{}{}
+
+	for _, target := range service.Status.Traffic {
+		if target.Percent == 0 || len(target.RevisionName) == 0 {
+			continue
+		}
+
+		rev, err := s.servingClient.ServingV1alpha1().Revisions(service.Namespace).Get(target.RevisionName, metav1.GetOptions{})
+		if err != nil {
+			return ServiceStatus{}, fmt.Errorf("Getting revision '%s': %s", target.RevisionName, err)
+		}
+
+		status.Traffic = append(status.Traffic, ServiceStatusTraffic{
+			Revision:    target.RevisionName,
+			Percent:     target.Percent,
+			ImageDigest: rev.Status.ImageDigest,
+		})
+
+		digests[rev.Status.ImageDigest] = struct{}{}
+	}
+
+	sort.SliceStable(status.Traffic, func(i, j int) bool {
+		return status.Traffic[i].Percent > status.Traffic[j].Percent
+	})
+
+	if len(digests) == 1 {
+		for digest := range digests {
+			status.ImageDigest = digest
+		}
+	}
+
+	return status, nil
+}
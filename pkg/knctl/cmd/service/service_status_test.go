@@ -0,0 +1,93 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service_test
+
+import (
+	"testing"
+
+	. "github.com/cppforlife/knctl/pkg/knctl/cmd"
+	. "github.com/cppforlife/knctl/pkg/knctl/cmd/service"
+	"github.com/cppforlife/knctl/pkg/knctl/testkit"
+	"github.com/knative/serving/pkg/apis/serving/v1alpha1"
+)
+
+func TestServiceStatuses_Get(t *testing.T) {
+	service := testkit.NewService("ns1", "svc1").Ready().Domain("svc1.ns1.example.com").
+		LatestRevision("svc1-00002", "svc1-00002").Build()
+	service.Status.Traffic = []v1alpha1.TrafficTarget{
+		{RevisionName: "svc1-00001", Percent: 10},
+		{RevisionName: "svc1-00002", Percent: 90},
+	}
+
+	rev1 := testkit.NewRevision("ns1", "svc1", "svc1-00001").Build()
+	rev1.Status.ImageDigest = "index.docker.io/app@sha256:1"
+
+	rev2 := testkit.NewRevision("ns1", "svc1", "svc1-00002").Build()
+	rev2.Status.ImageDigest = "index.docker.io/app@sha256:2"
+
+	cluster := testkit.NewCluster(t, service, rev1, rev2)
+
+	status, err := NewServiceStatuses(cluster.ServingClient()).Get("ns1", "svc1")
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	DeepEqual(t, status, ServiceStatus{
+		Namespace: "ns1",
+		Name:      "svc1",
+		Ready:     true,
+		URL:       "http://svc1.ns1.example.com",
+
+		LatestCreatedRevision: "svc1-00002",
+		LatestReadyRevision:   "svc1-00002",
+
+		Traffic: []ServiceStatusTraffic{
+			{Revision: "svc1-00002", Percent: 90, ImageDigest: "index.docker.io/app@sha256:2"},
+			{Revision: "svc1-00001", Percent: 10, ImageDigest: "index.docker.io/app@sha256:1"},
+		},
+	})
+}
+
+func TestServiceStatuses_GetSingleDigest(t *testing.T) {
+	service := testkit.NewService("ns1", "svc1").Ready().Build()
+	service.Status.Traffic = []v1alpha1.TrafficTarget{{RevisionName: "svc1-00001", Percent: 100}}
+
+	rev1 := testkit.NewRevision("ns1", "svc1", "svc1-00001").Build()
+	rev1.Status.ImageDigest = "index.docker.io/app@sha256:1"
+
+	status, err := NewServiceStatuses(testkit.NewCluster(t, service, rev1).ServingClient()).Get("ns1", "svc1")
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	DeepEqual(t, status.ImageDigest, "index.docker.io/app@sha256:1")
+}
+
+func TestServiceStatuses_GetNotObserved(t *testing.T) {
+	service := testkit.NewService("ns1", "svc1").Ready().Build()
+	service.Spec.Generation = 2
+	service.Status.ObservedGeneration = 1
+
+	status, err := NewServiceStatuses(testkit.NewCluster(t, service).ServingClient()).Get("ns1", "svc1")
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	DeepEqual(t, status.Ready, false)
+	DeepEqual(t, status.Reason, "NotObserved")
+	DeepEqual(t, status.Traffic, []ServiceStatusTraffic{})
+}
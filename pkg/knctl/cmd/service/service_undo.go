@@ -0,0 +1,273 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"fmt"
+
+	ctlhistory "github.com/cppforlife/knctl/pkg/knctl/history"
+	"github.com/knative/serving/pkg/apis/serving/v1alpha1"
+	servingclientset "github.com/knative/serving/pkg/client/clientset/versioned"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/util/retry"
+)
+
+// UndoPlan describes how last recorded operation will be reversed
+type UndoPlan struct {
+	Namespace string
+	Service   string
+	Entry     ctlhistory.Entry
+
+	// Set when reversing a deploy: service's configuration
+	// is restored from the revision that preceded the deploy
+	RestoredRevision string
+	CurrentImage     string
+	RestoredImage    string
+
+	// Set when reversing a rollout: route's traffic is restored
+	Route           string
+	CurrentTraffic  []v1alpha1.TrafficTarget
+	RestoredTraffic []v1alpha1.TrafficTarget
+}
+
+type ServiceUndo struct {
+	servingClient servingclientset.Interface
+	coreClient    kubernetes.Interface
+}
+
+func NewServiceUndo(servingClient servingclientset.Interface, coreClient kubernetes.Interface) ServiceUndo {
+	return ServiceUndo{servingClient, coreClient}
+}
+
+func (u ServiceUndo) Plan(namespace, serviceName string) (UndoPlan, error) {
+	entry, found, err := ctlhistory.NewHistory(u.coreClient).Last(namespace, serviceName)
+	if err != nil {
+		return UndoPlan{}, err
+	}
+
+	if !found {
+		return UndoPlan{}, fmt.Errorf("Expected service '%s' to have recorded history", serviceName)
+	}
+
+	plan := UndoPlan{Namespace: namespace, Service: serviceName, Entry: entry}
+
+	if len(entry.Route) > 0 {
+		return u.planRollout(plan)
+	}
+
+	return u.planDeploy(plan)
+}
+
+func (u ServiceUndo) planRollout(plan UndoPlan) (UndoPlan, error) {
+	if len(plan.Entry.PreviousTraffic) == 0 {
+		return UndoPlan{}, fmt.Errorf("Expected previous traffic of route '%s' to be recorded "+
+			"(route was likely created by the last rollout)", plan.Entry.Route)
+	}
+
+	route, err := u.servingClient.ServingV1alpha1().Routes(plan.Namespace).Get(plan.Entry.Route, metav1.GetOptions{})
+	if err != nil {
+		return UndoPlan{}, fmt.Errorf("Getting route: %s", err)
+	}
+
+	plan.Route = route.Name
+	plan.CurrentTraffic = route.Spec.Traffic
+	plan.RestoredTraffic = plan.Entry.PreviousTraffic
+
+	return plan, nil
+}
+
+func (u ServiceUndo) planDeploy(plan UndoPlan) (UndoPlan, error) {
+	if len(plan.Entry.PreviousRevision) == 0 {
+		return UndoPlan{}, fmt.Errorf("Expected previous revision to be recorded " +
+			"(service was likely created by the last deploy)")
+	}
+
+	revision, err := u.servingClient.ServingV1alpha1().Revisions(plan.Namespace).Get(plan.Entry.PreviousRevision, metav1.GetOptions{})
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return UndoPlan{}, fmt.Errorf("Expected previous revision '%s' to exist", plan.Entry.PreviousRevision)
+		}
+		return UndoPlan{}, fmt.Errorf("Getting revision: %s", err)
+	}
+
+	confSpec, err := u.configurationSpec(plan.Namespace, plan.Service)
+	if err != nil {
+		return UndoPlan{}, err
+	}
+
+	plan.RestoredRevision = revision.Name
+	plan.CurrentImage = confSpec.RevisionTemplate.Spec.Container.Image
+	plan.RestoredImage = u.revisionImage(*revision)
+
+	return plan, nil
+}
+
+func (u ServiceUndo) Apply(plan UndoPlan) error {
+	var err error
+
+	if len(plan.Route) > 0 {
+		err = u.restoreTraffic(plan)
+	} else {
+		err = u.restoreRevision(plan)
+	}
+	if err != nil {
+		return err
+	}
+
+	return ctlhistory.NewHistory(u.coreClient).Record(plan.Namespace, u.Entry(plan))
+}
+
+// Entry returns history entry describing undo of given plan
+func (ServiceUndo) Entry(plan UndoPlan) ctlhistory.Entry {
+	entry := ctlhistory.NewEntry(ctlhistory.OperationUndo, plan.Service)
+
+	// Recorded in reverse so that undo can be undone
+	if len(plan.Route) > 0 {
+		entry.Route = plan.Route
+		entry.Traffic = plan.RestoredTraffic
+		entry.PreviousTraffic = plan.CurrentTraffic
+	} else {
+		entry.Revision = plan.RestoredRevision
+		entry.PreviousRevision = plan.Entry.Revision
+		entry.Image = plan.RestoredImage
+	}
+
+	return entry
+}
+
+func (u ServiceUndo) restoreTraffic(plan UndoPlan) error {
+	// Approved service may manage its route again, which would override restored traffic
+	if plan.Entry.Operation == ctlhistory.OperationApprove {
+		err := NewServiceApproval(u.servingClient, u.coreClient).switchToManual(plan.Namespace, plan.Service)
+		if err != nil {
+			return err
+		}
+	}
+
+	routes := u.servingClient.ServingV1alpha1().Routes(plan.Namespace)
+
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		route, err := routes.Get(plan.Route, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+
+		route.Spec.Traffic = plan.RestoredTraffic
+
+		_, err = routes.Update(route)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("Updating route: %s", err)
+	}
+
+	return nil
+}
+
+func (u ServiceUndo) restoreRevision(plan UndoPlan) error {
+	revision, err := u.servingClient.ServingV1alpha1().Revisions(plan.Namespace).Get(plan.RestoredRevision, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("Getting revision: %s", err)
+	}
+
+	revSpec := *revision.Spec.DeepCopy()
+
+	// Image is already built; build is not repeated
+	revSpec.Container.Image = u.revisionImage(*revision)
+	revSpec.BuildName = ""
+	revSpec.BuildRef = nil
+	revSpec.Generation = 0
+	revSpec.DeprecatedServingState = ""
+
+	services := u.servingClient.ServingV1alpha1().Services(plan.Namespace)
+	confs := u.servingClient.ServingV1alpha1().Configurations(plan.Namespace)
+
+	err = retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		service, err := services.Get(plan.Service, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+
+		// Services with manual mode keep configuration separately (see ServiceSpec)
+		if service.Spec.Manual != nil {
+			conf, err := confs.Get(plan.Service, metav1.GetOptions{})
+			if err != nil {
+				return err
+			}
+
+			conf.Spec.RevisionTemplate.Spec = revSpec
+
+			_, err = confs.Update(conf)
+			return err
+		}
+
+		confSpec, err := u.serviceConfigurationSpec(service)
+		if err != nil {
+			return err
+		}
+
+		confSpec.RevisionTemplate.Spec = revSpec
+
+		_, err = services.Update(service)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("Updating service configuration: %s", err)
+	}
+
+	return nil
+}
+
+func (u ServiceUndo) configurationSpec(namespace, serviceName string) (*v1alpha1.ConfigurationSpec, error) {
+	service, err := u.servingClient.ServingV1alpha1().Services(namespace).Get(serviceName, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("Getting service: %s", err)
+	}
+
+	if service.Spec.Manual != nil {
+		conf, err := u.servingClient.ServingV1alpha1().Configurations(namespace).Get(serviceName, metav1.GetOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("Getting configuration: %s", err)
+		}
+		return &conf.Spec, nil
+	}
+
+	return u.serviceConfigurationSpec(service)
+}
+
+func (ServiceUndo) serviceConfigurationSpec(service *v1alpha1.Service) (*v1alpha1.ConfigurationSpec, error) {
+	switch {
+	case service.Spec.RunLatest != nil:
+		return &service.Spec.RunLatest.Configuration, nil
+	case service.Spec.Release != nil:
+		return &service.Spec.Release.Configuration, nil
+	case service.Spec.Pinned != nil:
+		return &service.Spec.Pinned.Configuration, nil
+	default:
+		return nil, fmt.Errorf("Expected service '%s' to have configuration", service.Name)
+	}
+}
+
+// revisionImage prefers resolved digest so that exactly the same image is restored
+func (ServiceUndo) revisionImage(revision v1alpha1.Revision) string {
+	if len(revision.Status.ImageDigest) > 0 {
+		return revision.Status.ImageDigest
+	}
+	return revision.Spec.Container.Image
+}
@@ -0,0 +1,191 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service_test
+
+import (
+	"testing"
+
+	. "github.com/cppforlife/knctl/pkg/knctl/cmd"
+	. "github.com/cppforlife/knctl/pkg/knctl/cmd/service"
+	ctlhistory "github.com/cppforlife/knctl/pkg/knctl/history"
+	"github.com/cppforlife/knctl/pkg/knctl/testkit"
+	"github.com/knative/serving/pkg/apis/serving/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestServiceUndo_Rollout(t *testing.T) {
+	cluster := testkit.NewCluster(t,
+		testkit.NewRoute("ns1", "svc1").Traffic("svc1-00002", 100).Build(),
+	)
+
+	history := ctlhistory.NewHistory(cluster.CoreClient())
+
+	err := history.Record("ns1", ctlhistory.Entry{
+		Operation:       ctlhistory.OperationRollout,
+		Service:         "svc1",
+		Route:           "svc1",
+		Traffic:         []v1alpha1.TrafficTarget{{RevisionName: "svc1-00002", Percent: 100}},
+		PreviousTraffic: []v1alpha1.TrafficTarget{{RevisionName: "svc1-00001", Percent: 100}},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	undo := NewServiceUndo(cluster.ServingClient(), cluster.CoreClient())
+
+	plan, err := undo.Plan("ns1", "svc1")
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	DeepEqual(t, plan.Route, "svc1")
+	DeepEqual(t, plan.CurrentTraffic, []v1alpha1.TrafficTarget{{RevisionName: "svc1-00002", Percent: 100}})
+	DeepEqual(t, plan.RestoredTraffic, []v1alpha1.TrafficTarget{{RevisionName: "svc1-00001", Percent: 100}})
+
+	err = undo.Apply(plan)
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	route, err := cluster.ServingClient().ServingV1alpha1().Routes("ns1").Get("svc1", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	DeepEqual(t, route.Spec.Traffic, []v1alpha1.TrafficTarget{{RevisionName: "svc1-00001", Percent: 100}})
+
+	// Undo is recorded in reverse so it can be undone itself
+	plan, err = undo.Plan("ns1", "svc1")
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	DeepEqual(t, plan.Entry.Operation, ctlhistory.OperationUndo)
+	DeepEqual(t, plan.RestoredTraffic, []v1alpha1.TrafficTarget{{RevisionName: "svc1-00002", Percent: 100}})
+}
+
+func TestServiceUndo_Approve(t *testing.T) {
+	cluster := testkit.NewCluster(t,
+		testkit.NewService("ns1", "svc1").Build(),
+		testkit.NewRoute("ns1", "svc1").Traffic("svc1-00002", 100).Build(),
+	)
+
+	err := ctlhistory.NewHistory(cluster.CoreClient()).Record("ns1", ctlhistory.Entry{
+		Operation:       ctlhistory.OperationApprove,
+		Service:         "svc1",
+		Route:           "svc1",
+		Traffic:         []v1alpha1.TrafficTarget{{RevisionName: "svc1-00002", Percent: 100}},
+		PreviousTraffic: []v1alpha1.TrafficTarget{{RevisionName: "svc1-00001", Percent: 100}},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	undo := NewServiceUndo(cluster.ServingClient(), cluster.CoreClient())
+
+	plan, err := undo.Plan("ns1", "svc1")
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	err = undo.Apply(plan)
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	// Service would otherwise override restored traffic
+	service, err := cluster.ServingClient().ServingV1alpha1().Services("ns1").Get("svc1", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	if service.Spec.Manual == nil {
+		t.Fatalf("Expected service to be switched to manual mode")
+	}
+
+	route, err := cluster.ServingClient().ServingV1alpha1().Routes("ns1").Get("svc1", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	DeepEqual(t, route.Spec.Traffic, []v1alpha1.TrafficTarget{{RevisionName: "svc1-00001", Percent: 100}})
+}
+
+func TestServiceUndo_Deploy(t *testing.T) {
+	service := testkit.NewService("ns1", "svc1").Build()
+	service.Spec.RunLatest.Configuration.RevisionTemplate.Spec.Container.Image = "app:v2"
+
+	prevRev := testkit.NewRevision("ns1", "svc1", "svc1-00001").Build()
+	prevRev.Spec.Container.Image = "app:v1"
+	prevRev.Spec.BuildName = "svc1-00001-build"
+	prevRev.Status.ImageDigest = "index.docker.io/app@sha256:1"
+
+	cluster := testkit.NewCluster(t, service, prevRev)
+
+	err := ctlhistory.NewHistory(cluster.CoreClient()).Record("ns1", ctlhistory.Entry{
+		Operation:        ctlhistory.OperationDeploy,
+		Service:          "svc1",
+		Revision:         "svc1-00002",
+		PreviousRevision: "svc1-00001",
+	})
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	undo := NewServiceUndo(cluster.ServingClient(), cluster.CoreClient())
+
+	plan, err := undo.Plan("ns1", "svc1")
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	DeepEqual(t, plan.CurrentImage, "app:v2")
+	DeepEqual(t, plan.RestoredImage, "index.docker.io/app@sha256:1")
+	DeepEqual(t, plan.RestoredRevision, "svc1-00001")
+
+	err = undo.Apply(plan)
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	service, err = cluster.ServingClient().ServingV1alpha1().Services("ns1").Get("svc1", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	revSpec := service.Spec.RunLatest.Configuration.RevisionTemplate.Spec
+	DeepEqual(t, revSpec.Container.Image, "index.docker.io/app@sha256:1")
+	DeepEqual(t, revSpec.BuildName, "")
+
+	entry, _, err := ctlhistory.NewHistory(cluster.CoreClient()).Last("ns1", "svc1")
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	DeepEqual(t, entry.Operation, ctlhistory.OperationUndo)
+	DeepEqual(t, entry.Revision, "svc1-00001")
+	DeepEqual(t, entry.PreviousRevision, "svc1-00002")
+}
+
+func TestServiceUndo_RequiresHistory(t *testing.T) {
+	cluster := testkit.NewCluster(t, testkit.NewService("ns1", "svc1").Build())
+
+	_, err := NewServiceUndo(cluster.ServingClient(), cluster.CoreClient()).Plan("ns1", "svc1")
+	if err == nil || err.Error() != "Expected service 'svc1' to have recorded history" {
+		t.Fatalf("Expected history error, but was: %v", err)
+	}
+}
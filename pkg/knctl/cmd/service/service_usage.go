@@ -0,0 +1,85 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"sort"
+
+	ctlmetrics "github.com/cppforlife/knctl/pkg/knctl/metrics"
+	"github.com/knative/serving/pkg/apis/serving"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+type ServiceUsage struct {
+	Service   string
+	Revisions []string
+	Pods      int
+	CPU       resource.Quantity
+	Memory    resource.Quantity
+}
+
+// NewServiceUsages groups pod usage by Knative service. Pods that
+// do not belong to a service are ignored; pods without metrics
+// (e.g. just started) are counted but do not contribute usage.
+func NewServiceUsages(pods []corev1.Pod, podMetrics []ctlmetrics.PodMetrics) []ServiceUsage {
+	metricsByPod := map[string]ctlmetrics.PodMetrics{}
+
+	for _, m := range podMetrics {
+		metricsByPod[m.Name] = m
+	}
+
+	usagesByService := map[string]*ServiceUsage{}
+	revisionsByService := map[string]map[string]struct{}{}
+
+	for _, pod := range pods {
+		serviceName := pod.Labels[serving.ServiceLabelKey]
+		if len(serviceName) == 0 {
+			continue
+		}
+
+		usage, found := usagesByService[serviceName]
+		if !found {
+			usage = &ServiceUsage{Service: serviceName}
+			usagesByService[serviceName] = usage
+			revisionsByService[serviceName] = map[string]struct{}{}
+		}
+
+		usage.Pods++
+		revisionsByService[serviceName][pod.Labels[serving.RevisionLabelKey]] = struct{}{}
+
+		if m, found := metricsByPod[pod.Name]; found {
+			podUsage := m.Usage()
+			usage.CPU.Add(podUsage[corev1.ResourceCPU])
+			usage.Memory.Add(podUsage[corev1.ResourceMemory])
+		}
+	}
+
+	var result []ServiceUsage
+
+	for serviceName, usage := range usagesByService {
+		for revName := range revisionsByService[serviceName] {
+			usage.Revisions = append(usage.Revisions, revName)
+		}
+		sort.Strings(usage.Revisions)
+		result = append(result, *usage)
+	}
+
+	sort.Slice(result, func(i, j int) bool { return result[i].Service < result[j].Service })
+
+	return result
+}
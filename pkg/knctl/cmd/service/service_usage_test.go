@@ -0,0 +1,89 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service_test
+
+import (
+	"testing"
+
+	. "github.com/cppforlife/knctl/pkg/knctl/cmd"
+	. "github.com/cppforlife/knctl/pkg/knctl/cmd/service"
+	ctlmetrics "github.com/cppforlife/knctl/pkg/knctl/metrics"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestNewServiceUsages(t *testing.T) {
+	pod := func(name, service, revision string) corev1.Pod {
+		labels := map[string]string{}
+		if len(service) > 0 {
+			labels["serving.knative.dev/service"] = service
+			labels["serving.knative.dev/revision"] = revision
+		}
+		return corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels}}
+	}
+
+	podMetrics := func(name, cpu, mem string) ctlmetrics.PodMetrics {
+		return ctlmetrics.PodMetrics{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Containers: []ctlmetrics.ContainerMetrics{{
+				Name: "user-container",
+				Usage: corev1.ResourceList{
+					corev1.ResourceCPU:    resource.MustParse(cpu),
+					corev1.ResourceMemory: resource.MustParse(mem),
+				},
+			}, {
+				Name: "queue-proxy",
+				Usage: corev1.ResourceList{
+					corev1.ResourceCPU:    resource.MustParse("10m"),
+					corev1.ResourceMemory: resource.MustParse("10Mi"),
+				},
+			}},
+		}
+	}
+
+	usages := NewServiceUsages([]corev1.Pod{
+		pod("svc2-pod1", "svc2", "svc2-00001"),
+		pod("svc1-pod1", "svc1", "svc1-00001"),
+		pod("svc1-pod2", "svc1", "svc1-00002"),
+		pod("svc1-pod3", "svc1", "svc1-00002"),
+		pod("other-pod", "", ""),
+	}, []ctlmetrics.PodMetrics{
+		podMetrics("svc1-pod1", "100m", "100Mi"),
+		podMetrics("svc1-pod2", "200m", "200Mi"),
+		podMetrics("svc2-pod1", "1", "1Gi"),
+		podMetrics("other-pod", "1", "1Gi"),
+	})
+
+	if len(usages) != 2 {
+		t.Fatalf("Expected two services but was: %#v", usages)
+	}
+
+	svc1 := usages[0]
+
+	DeepEqual(t, svc1.Service, "svc1")
+	DeepEqual(t, svc1.Revisions, []string{"svc1-00001", "svc1-00002"})
+	DeepEqual(t, svc1.Pods, 3)
+	DeepEqual(t, svc1.CPU.MilliValue(), int64(320))
+	DeepEqual(t, svc1.Memory.Value(), int64(320*1024*1024))
+
+	svc2 := usages[1]
+
+	DeepEqual(t, svc2.Service, "svc2")
+	DeepEqual(t, svc2.Pods, 1)
+	DeepEqual(t, svc2.CPU.MilliValue(), int64(1010))
+}
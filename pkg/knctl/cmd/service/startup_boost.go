@@ -0,0 +1,69 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/knative/serving/pkg/apis/autoscaling"
+	"github.com/knative/serving/pkg/apis/serving/v1alpha1"
+	servingclientset "github.com/knative/serving/pkg/client/clientset/versioned"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// StartupBoost relaxes temporarily raised minimum scale of a revision
+// after it became ready (or failed to). Revision's pod autoscaler (named after revision)
+// is patched directly since changing revision template would result
+// in a new revision.
+type StartupBoost struct {
+	servingClient servingclientset.Interface
+}
+
+func NewStartupBoost(servingClient servingclientset.Interface) StartupBoost {
+	return StartupBoost{servingClient}
+}
+
+func (b StartupBoost) Relax(revision *v1alpha1.Revision, minScale *int) error {
+	var minScaleVal interface{} // nil removes annotation
+
+	if minScale != nil {
+		minScaleVal = strconv.Itoa(*minScale)
+	}
+
+	mergePatch := map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]interface{}{
+				autoscaling.MinScaleAnnotationKey: minScaleVal,
+			},
+		},
+	}
+
+	patchJSON, err := json.Marshal(mergePatch)
+	if err != nil {
+		return err
+	}
+
+	_, err = b.servingClient.AutoscalingV1alpha1().PodAutoscalers(revision.Namespace).Patch(
+		revision.Name, types.MergePatchType, patchJSON)
+	if err != nil {
+		return fmt.Errorf("Relaxing minimum scale of revision '%s': %s", revision.Name, err)
+	}
+
+	return nil
+}
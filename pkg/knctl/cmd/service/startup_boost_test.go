@@ -0,0 +1,79 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service_test
+
+import (
+	"testing"
+
+	. "github.com/cppforlife/knctl/pkg/knctl/cmd/service"
+	"github.com/cppforlife/knctl/pkg/knctl/testkit"
+	kpav1alpha1 "github.com/knative/serving/pkg/apis/autoscaling/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestStartupBoost_Relax(t *testing.T) {
+	cluster := testkit.NewCluster(t, &kpav1alpha1.PodAutoscaler{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "rev1",
+			Namespace: "ns1",
+			Annotations: map[string]string{
+				"autoscaling.knative.dev/minScale": "3",
+				"autoscaling.knative.dev/maxScale": "10",
+			},
+		},
+	})
+
+	revision := testkit.NewRevision("ns1", "svc1", "rev1").Build()
+	boost := NewStartupBoost(cluster.ServingClient())
+	kpas := cluster.ServingClient().AutoscalingV1alpha1().PodAutoscalers("ns1")
+
+	minScale := 1
+
+	err := boost.Relax(revision, &minScale)
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	kpa, err := kpas.Get("rev1", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	min, max := kpa.ScaleBounds()
+	if min != 1 || max != 10 {
+		t.Fatalf("Expected min scale to be relaxed to 1 but was %d (max %d)", min, max)
+	}
+
+	err = boost.Relax(revision, nil)
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	kpa, err = kpas.Get("rev1", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	if _, found := kpa.Annotations["autoscaling.knative.dev/minScale"]; found {
+		t.Fatalf("Expected min scale annotation to be removed: %#v", kpa.Annotations)
+	}
+
+	err = boost.Relax(testkit.NewRevision("ns1", "svc1", "rev2").Build(), nil)
+	if err == nil {
+		t.Fatalf("Expected error for missing pod autoscaler")
+	}
+}
@@ -0,0 +1,206 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/cppforlife/go-cli-ui/ui"
+	uitable "github.com/cppforlife/go-cli-ui/ui/table"
+	cmdcore "github.com/cppforlife/knctl/pkg/knctl/cmd/core"
+	cmdflags "github.com/cppforlife/knctl/pkg/knctl/cmd/flags"
+	"github.com/cppforlife/knctl/pkg/knctl/waiter"
+	"github.com/knative/serving/pkg/apis/serving/v1alpha1"
+	servingclientset "github.com/knative/serving/pkg/client/clientset/versioned"
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	StatusOutputTable = "table"
+	StatusOutputJSON  = "json"
+
+	StatusWaitForReady = "ready"
+)
+
+type StatusOptions struct {
+	ui          ui.UI
+	depsFactory cmdcore.DepsFactory
+
+	ServiceFlags cmdflags.ServiceFlags
+
+	Output      string
+	WaitFor     string
+	WaitTimeout time.Duration
+}
+
+func NewStatusOptions(ui ui.UI, depsFactory cmdcore.DepsFactory) *StatusOptions {
+	return &StatusOptions{ui: ui, depsFactory: depsFactory}
+}
+
+func NewStatusCmd(o *StatusOptions, flagsFactory cmdcore.FlagsFactory) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "status",
+		Short: "Show service readiness, routed image digest and URL",
+		Long: `Show service readiness, routed image digest and URL.
+
+With '-o json' a single JSON document is printed to stdout, suitable for consumption in CI pipelines.
+With '--wait-for ready' command waits until service either becomes ready or fails (or timeout is reached),
+prints its status and exits with non-zero code if service is not ready.`,
+		Example: `
+  # Show status of service 'svc1' in namespace 'ns1'
+  knctl status --service svc1 -n ns1
+
+  # Wait for service 'svc1' to become ready and print its status as JSON
+  knctl status --service svc1 -n ns1 -o json --wait-for ready --wait-timeout 10m`,
+		Annotations: map[string]string{
+			cmdcore.BasicHelpGroup.Key: cmdcore.BasicHelpGroup.Value,
+		},
+		RunE: func(_ *cobra.Command, _ []string) error { return o.Run() },
+	}
+	o.ServiceFlags.Set(cmd, flagsFactory)
+	cmd.Flags().StringVarP(&o.Output, "output", "o", StatusOutputTable, "Set output format (table or json)")
+	cmd.Flags().StringVar(&o.WaitFor, "wait-for", "", "Set state to wait for before printing status (ready)")
+	cmd.Flags().DurationVar(&o.WaitTimeout, "wait-timeout", 5*time.Minute, "Set maximum time to wait for state specified by --wait-for")
+	return cmd
+}
+
+func (o *StatusOptions) Run() error {
+	switch o.Output {
+	case StatusOutputTable, StatusOutputJSON:
+	default:
+		return fmt.Errorf("Expected --output to be one of: %s",
+			strings.Join([]string{StatusOutputTable, StatusOutputJSON}, ", "))
+	}
+
+	switch o.WaitFor {
+	case "", StatusWaitForReady:
+	default:
+		return fmt.Errorf("Expected --wait-for to be one of: %s", StatusWaitForReady)
+	}
+
+	servingClient, err := o.depsFactory.ServingClient()
+	if err != nil {
+		return err
+	}
+
+	statuses := NewServiceStatuses(servingClient)
+
+	var status ServiceStatus
+
+	if o.WaitFor == StatusWaitForReady {
+		service, err := o.waitForSettled(servingClient)
+		if err != nil {
+			return err
+		}
+
+		status, err = statuses.FromService(service)
+		if err != nil {
+			return err
+		}
+	} else {
+		status, err = statuses.Get(o.ServiceFlags.NamespaceFlags.Name, o.ServiceFlags.Name)
+		if err != nil {
+			return err
+		}
+	}
+
+	if o.Output == StatusOutputJSON {
+		bs, err := json.MarshalIndent(status, "", "  ")
+		if err != nil {
+			return fmt.Errorf("Marshaling status: %s", err)
+		}
+		o.ui.PrintBlock(append(bs, '\n'))
+	} else {
+		o.printTable(status)
+	}
+
+	if o.WaitFor == StatusWaitForReady && !status.Ready {
+		return fmt.Errorf("Expected service '%s' to become ready", status.Name)
+	}
+
+	return nil
+}
+
+func (o *StatusOptions) waitForSettled(servingClient servingclientset.Interface) (*v1alpha1.Service, error) {
+	w, err := o.depsFactory.Waiter()
+	if err != nil {
+		return nil, err
+	}
+
+	cancelCh := make(chan struct{})
+	timer := time.AfterFunc(o.WaitTimeout, func() { close(cancelCh) })
+	defer timer.Stop()
+
+	obj, settled, err := w.Wait(waiter.ServiceKind, o.ServiceFlags.NamespaceFlags.Name,
+		o.ServiceFlags.Name, waiter.IsServiceSettled, cancelCh)
+	if err != nil {
+		return nil, fmt.Errorf("Waiting for service: %s", err)
+	}
+
+	if !settled {
+		// Report last known status even when timed out
+		return servingClient.ServingV1alpha1().Services(o.ServiceFlags.NamespaceFlags.Name).Get(o.ServiceFlags.Name, metav1.GetOptions{})
+	}
+
+	return obj.(*v1alpha1.Service), nil
+}
+
+func (o *StatusOptions) printTable(status ServiceStatus) {
+	var trafficLines []string
+
+	for _, target := range status.Traffic {
+		trafficLines = append(trafficLines, fmt.Sprintf("%d%% -> %s", target.Percent, target.Revision))
+	}
+
+	table := uitable.Table{
+		Title: fmt.Sprintf("Service '%s' status", status.Name),
+
+		Header: []uitable.Header{
+			uitable.NewHeader("Name"),
+			uitable.NewHeader("Ready"),
+			uitable.NewHeader("Reason"),
+			uitable.NewHeader("Message"),
+			uitable.NewHeader("URL"),
+			uitable.NewHeader("Latest created revision"),
+			uitable.NewHeader("Latest ready revision"),
+			uitable.NewHeader("Image digest"),
+			uitable.NewHeader("Traffic"),
+		},
+
+		Transpose: true,
+
+		Rows: [][]uitable.Value{
+			{
+				uitable.NewValueString(status.Name),
+				uitable.NewValueBool(status.Ready),
+				uitable.NewValueString(status.Reason),
+				uitable.NewValueString(status.Message),
+				uitable.NewValueString(status.URL),
+				uitable.NewValueString(status.LatestCreatedRevision),
+				uitable.NewValueString(status.LatestReadyRevision),
+				uitable.NewValueString(status.ImageDigest),
+				uitable.NewValueString(strings.Join(trafficLines, "\n")),
+			},
+		},
+	}
+
+	o.ui.PrintTable(table)
+}
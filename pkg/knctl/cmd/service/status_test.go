@@ -0,0 +1,93 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cppforlife/go-cli-ui/ui"
+	. "github.com/cppforlife/knctl/pkg/knctl/cmd"
+	cmdcore "github.com/cppforlife/knctl/pkg/knctl/cmd/core"
+	. "github.com/cppforlife/knctl/pkg/knctl/cmd/service"
+	"github.com/cppforlife/knctl/pkg/knctl/testkit"
+)
+
+func TestNewStatusCmd_Ok(t *testing.T) {
+	realCmd := NewStatusOptions(nil, cmdcore.NewDepsFactory())
+	cmd := NewTestCmd(t, NewStatusCmd(realCmd, cmdcore.FlagsFactory{}))
+	cmd.ExpectBasicConfig()
+	cmd.Execute([]string{
+		"-n", "test-namespace",
+		"-s", "test-service",
+		"-o", "json",
+		"--wait-for", "ready",
+		"--wait-timeout", "10m",
+	})
+	cmd.ExpectReachesExecution()
+
+	DeepEqual(t, realCmd.ServiceFlags.NamespaceFlags, cmdcore.NamespaceFlags{"test-namespace"})
+	DeepEqual(t, realCmd.ServiceFlags.Name, "test-service")
+	DeepEqual(t, realCmd.Output, "json")
+	DeepEqual(t, realCmd.WaitFor, "ready")
+	DeepEqual(t, realCmd.WaitTimeout, 10*time.Minute)
+}
+
+func TestNewStatusCmd_OkMinimum(t *testing.T) {
+	realCmd := NewStatusOptions(nil, cmdcore.NewDepsFactory())
+	cmd := NewTestCmd(t, NewStatusCmd(realCmd, cmdcore.FlagsFactory{}))
+	cmd.Execute([]string{"-n", "test-namespace", "-s", "test-service"})
+	cmd.ExpectReachesExecution()
+
+	DeepEqual(t, realCmd.Output, "table")
+	DeepEqual(t, realCmd.WaitFor, "")
+	DeepEqual(t, realCmd.WaitTimeout, 5*time.Minute)
+}
+
+func TestNewStatusCmd_RequiredFlags(t *testing.T) {
+	realCmd := NewStatusOptions(nil, cmdcore.NewDepsFactory())
+	cmd := NewTestCmd(t, NewStatusCmd(realCmd, cmdcore.FlagsFactory{}))
+	cmd.Execute([]string{})
+	cmd.ExpectRequiredFlags([]string{"service"})
+}
+
+func TestStatusOptions_RunRejectsUnknownValues(t *testing.T) {
+	cluster := testkit.NewCluster(t, testkit.NewService("ns1", "svc1").Ready().Build())
+
+	o := NewStatusOptions(ui.NewNoopUI(), cluster.DepsFactory())
+	o.ServiceFlags.NamespaceFlags.Name = "ns1"
+	o.ServiceFlags.Name = "svc1"
+
+	o.Output = "yaml"
+	err := o.Run()
+	if err == nil || err.Error() != "Expected --output to be one of: table, json" {
+		t.Fatalf("Expected output error, but was: %v", err)
+	}
+
+	o.Output = "json"
+	o.WaitFor = "deleted"
+	err = o.Run()
+	if err == nil || err.Error() != "Expected --wait-for to be one of: ready" {
+		t.Fatalf("Expected wait-for error, but was: %v", err)
+	}
+
+	o.WaitFor = ""
+	err = o.Run()
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+}
@@ -0,0 +1,108 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"fmt"
+
+	"github.com/cppforlife/go-cli-ui/ui"
+	uitable "github.com/cppforlife/go-cli-ui/ui/table"
+	cmdcore "github.com/cppforlife/knctl/pkg/knctl/cmd/core"
+	ctlmetrics "github.com/cppforlife/knctl/pkg/knctl/metrics"
+	"github.com/knative/serving/pkg/apis/serving"
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type TopOptions struct {
+	ui          ui.UI
+	depsFactory cmdcore.DepsFactory
+
+	NamespaceFlags cmdcore.NamespaceFlags
+}
+
+func NewTopOptions(ui ui.UI, depsFactory cmdcore.DepsFactory) *TopOptions {
+	return &TopOptions{ui: ui, depsFactory: depsFactory}
+}
+
+func NewTopCmd(o *TopOptions, flagsFactory cmdcore.FlagsFactory) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "top",
+		Short: "Show resource usage of services",
+		Long:  "Show CPU and memory usage of services summed across their pods (requires metrics-server)",
+		Example: `
+  # Show resource usage of services in namespace 'ns1'
+  knctl top -n ns1`,
+		Annotations: map[string]string{
+			cmdcore.BasicHelpGroup.Key: cmdcore.BasicHelpGroup.Value,
+		},
+		RunE: func(_ *cobra.Command, _ []string) error { return o.Run() },
+	}
+	o.NamespaceFlags.Set(cmd, flagsFactory)
+	return cmd
+}
+
+func (o *TopOptions) Run() error {
+	coreClient, err := o.depsFactory.CoreClient()
+	if err != nil {
+		return err
+	}
+
+	listOpts := metav1.ListOptions{LabelSelector: serving.ServiceLabelKey}
+
+	pods, err := coreClient.CoreV1().Pods(o.NamespaceFlags.Name).List(listOpts)
+	if err != nil {
+		return fmt.Errorf("Listing pods: %s", err)
+	}
+
+	podMetrics, err := ctlmetrics.NewPodMetricsSource(coreClient).List(o.NamespaceFlags.Name)
+	if err != nil {
+		return err
+	}
+
+	table := uitable.Table{
+		Title:   fmt.Sprintf("Resource usage of services in namespace '%s'", o.NamespaceFlags.Name),
+		Content: "services",
+
+		Header: []uitable.Header{
+			uitable.NewHeader("Service"),
+			uitable.NewHeader("Revisions"),
+			uitable.NewHeader("Pods"),
+			uitable.NewHeader("CPU"),
+			uitable.NewHeader("Memory"),
+		},
+
+		SortBy: []uitable.ColumnSort{
+			{Column: 3, Asc: false},
+			{Column: 0, Asc: true},
+		},
+	}
+
+	for _, usage := range NewServiceUsages(pods.Items, podMetrics) {
+		table.Rows = append(table.Rows, []uitable.Value{
+			uitable.NewValueString(usage.Service),
+			uitable.NewValueStrings(usage.Revisions),
+			uitable.NewValueInt(usage.Pods),
+			uitable.NewValueSuffix(uitable.NewValueInt(int(usage.CPU.MilliValue())), "m"),
+			uitable.NewValueSuffix(uitable.NewValueInt(int(usage.Memory.Value()/(1024*1024))), "Mi"),
+		})
+	}
+
+	o.ui.PrintTable(table)
+
+	return nil
+}
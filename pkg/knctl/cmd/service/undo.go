@@ -0,0 +1,137 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"fmt"
+
+	"github.com/cppforlife/go-cli-ui/ui"
+	uitable "github.com/cppforlife/go-cli-ui/ui/table"
+	cmdcore "github.com/cppforlife/knctl/pkg/knctl/cmd/core"
+	cmdflags "github.com/cppforlife/knctl/pkg/knctl/cmd/flags"
+	"github.com/spf13/cobra"
+)
+
+type UndoOptions struct {
+	ui          ui.UI
+	depsFactory cmdcore.DepsFactory
+
+	ServiceFlags cmdflags.ServiceFlags
+	NotifyFlags  cmdflags.NotifyFlags
+}
+
+func NewUndoOptions(ui ui.UI, depsFactory cmdcore.DepsFactory) *UndoOptions {
+	return &UndoOptions{ui: ui, depsFactory: depsFactory}
+}
+
+func NewUndoCmd(o *UndoOptions, flagsFactory cmdcore.FlagsFactory) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "undo",
+		Short: "Undo last deploy or rollout of service",
+		Long: `Undo last deploy or rollout of service based on its history (see 'knctl history').
+
+Reversing a deploy restores service's configuration from the revision that preceded it
+(which results in a new revision). Reversing a rollout restores route's previous traffic.
+Changes are shown for confirmation before being applied. Undo itself is recorded in history,
+hence running undo twice reverts the first undo.`,
+		Example: `
+  # Undo last deploy or rollout of service 'svc1' in namespace 'ns1'
+  knctl undo -s svc1 -n ns1
+
+  # Undo without asking for confirmation
+  knctl undo -s svc1 -n ns1 --non-interactive
+
+  # Undo and POST JSON summary of the result to a webhook
+  knctl undo -s svc1 -n ns1 --notify-url https://ci.example.com/hooks/knctl`,
+		Annotations: map[string]string{
+			cmdcore.BasicHelpGroup.Key: cmdcore.BasicHelpGroup.Value,
+		},
+		RunE: func(_ *cobra.Command, _ []string) error { return o.Run() },
+	}
+	o.ServiceFlags.Set(cmd, flagsFactory)
+	o.NotifyFlags.Set(cmd, flagsFactory)
+	return cmd
+}
+
+func (o *UndoOptions) Run() error {
+	servingClient, err := o.depsFactory.ServingClient()
+	if err != nil {
+		return err
+	}
+
+	coreClient, err := o.depsFactory.CoreClient()
+	if err != nil {
+		return err
+	}
+
+	undo := NewServiceUndo(servingClient, coreClient)
+
+	plan, err := undo.Plan(o.ServiceFlags.NamespaceFlags.Name, o.ServiceFlags.Name)
+	if err != nil {
+		return err
+	}
+
+	o.printPlan(plan)
+
+	err = o.ui.AskForConfirmation()
+	if err != nil {
+		return err
+	}
+
+	err = undo.Apply(plan)
+
+	// Only applied undos are notified about
+	notifyErr := o.NotifyFlags.Notify(o.depsFactory, undo.Entry(plan), plan.Namespace, err)
+	if notifyErr != nil {
+		o.ui.ErrorLinef("Warning: %s", notifyErr)
+	}
+
+	return err
+}
+
+func (o *UndoOptions) printPlan(plan UndoPlan) {
+	table := uitable.Table{
+		Title: fmt.Sprintf("Undoing %s of service '%s' performed by '%s' at %s",
+			plan.Entry.Operation, plan.Service, plan.Entry.User, plan.Entry.Time.Format("2006-01-02 15:04:05 MST")),
+
+		Header: []uitable.Header{
+			uitable.NewHeader(""),
+			uitable.NewHeader("Current"),
+			uitable.NewHeader("After undo"),
+		},
+	}
+
+	if len(plan.Route) > 0 {
+		table.Rows = append(table.Rows, []uitable.Value{
+			uitable.NewValueString(fmt.Sprintf("Route '%s' traffic", plan.Route)),
+			uitable.NewValueStrings(trafficTargetLines(plan.CurrentTraffic)),
+			uitable.NewValueStrings(trafficTargetLines(plan.RestoredTraffic)),
+		})
+	} else {
+		table.Rows = append(table.Rows, []uitable.Value{
+			uitable.NewValueString("Image"),
+			uitable.NewValueString(plan.CurrentImage),
+			uitable.NewValueString(plan.RestoredImage),
+		}, []uitable.Value{
+			uitable.NewValueString("Configuration from revision"),
+			uitable.NewValueString(plan.Entry.Revision),
+			uitable.NewValueString(plan.RestoredRevision),
+		})
+	}
+
+	o.ui.PrintTable(table)
+}
@@ -0,0 +1,53 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service_test
+
+import (
+	"testing"
+
+	. "github.com/cppforlife/knctl/pkg/knctl/cmd"
+	cmdcore "github.com/cppforlife/knctl/pkg/knctl/cmd/core"
+	cmdflags "github.com/cppforlife/knctl/pkg/knctl/cmd/flags"
+	. "github.com/cppforlife/knctl/pkg/knctl/cmd/service"
+)
+
+func TestNewUndoCmd_Ok(t *testing.T) {
+	realCmd := NewUndoOptions(nil, cmdcore.NewDepsFactory())
+	cmd := NewTestCmd(t, NewUndoCmd(realCmd, cmdcore.FlagsFactory{}))
+	cmd.ExpectBasicConfig()
+	cmd.Execute([]string{"-n", "test-namespace", "-s", "test-service"})
+	cmd.ExpectReachesExecution()
+
+	DeepEqual(t, realCmd.ServiceFlags.NamespaceFlags, cmdcore.NamespaceFlags{"test-namespace"})
+	DeepEqual(t, realCmd.ServiceFlags.Name, "test-service")
+}
+
+func TestNewUndoCmd_NotifyFlags(t *testing.T) {
+	realCmd := NewUndoOptions(nil, cmdcore.NewDepsFactory())
+	cmd := NewTestCmd(t, NewUndoCmd(realCmd, cmdcore.FlagsFactory{}))
+	cmd.Execute([]string{"-n", "test-namespace", "-s", "test-service", "--notify-slack-webhook", "https://hooks.slack.com/services/x"})
+	cmd.ExpectReachesExecution()
+
+	DeepEqual(t, realCmd.NotifyFlags, cmdflags.NotifyFlags{SlackWebhooks: []string{"https://hooks.slack.com/services/x"}})
+}
+
+func TestNewUndoCmd_RequiredFlags(t *testing.T) {
+	realCmd := NewUndoOptions(nil, cmdcore.NewDepsFactory())
+	cmd := NewTestCmd(t, NewUndoCmd(realCmd, cmdcore.FlagsFactory{}))
+	cmd.Execute([]string{})
+	cmd.ExpectRequiredFlags([]string{"service"})
+}
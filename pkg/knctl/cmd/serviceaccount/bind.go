@@ -0,0 +1,90 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package serviceaccount
+
+import (
+	"fmt"
+
+	"github.com/cppforlife/go-cli-ui/ui"
+	cmdcore "github.com/cppforlife/knctl/pkg/knctl/cmd/core"
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
+)
+
+type BindOptions struct {
+	ui          ui.UI
+	depsFactory cmdcore.DepsFactory
+
+	ServiceAccountFlags ServiceAccountFlags
+	BindFlags           BindFlags
+}
+
+func NewBindOptions(ui ui.UI, depsFactory cmdcore.DepsFactory) *BindOptions {
+	return &BindOptions{ui: ui, depsFactory: depsFactory}
+}
+
+func NewBindCmd(o *BindOptions, flagsFactory cmdcore.FlagsFactory) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "bind",
+		Short: "Bind secrets to service account",
+		Long:  "Add secrets and image pull secrets to an existing service account",
+		Example: `
+  # Add secret 'reg-secret' to service account 'sa1' in namespace 'ns1'
+  knctl service-account bind -a sa1 --secret reg-secret -n ns1
+
+  # Add image pull secret 'pull-secret' to service account 'sa1' in namespace 'ns1'
+  knctl service-account bind -a sa1 --image-pull-secret pull-secret -n ns1`,
+		RunE: func(_ *cobra.Command, _ []string) error { return o.Run() },
+	}
+	o.ServiceAccountFlags.Set(cmd, flagsFactory)
+	o.BindFlags.Set(cmd, flagsFactory)
+	return cmd
+}
+
+func (o *BindOptions) Run() error {
+	if len(o.BindFlags.Secrets) == 0 && len(o.BindFlags.ImagePullSecrets) == 0 {
+		return fmt.Errorf("Expected at least one secret or image pull secret to be specified")
+	}
+
+	coreClient, err := o.depsFactory.CoreClient()
+	if err != nil {
+		return err
+	}
+
+	serviceAccounts := coreClient.CoreV1().ServiceAccounts(o.ServiceAccountFlags.NamespaceFlags.Name)
+
+	err = retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		sa, err := serviceAccounts.Get(o.ServiceAccountFlags.Name, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+
+		err = NewServiceAccountSecrets(coreClient).Add(sa, o.BindFlags.Secrets, o.BindFlags.ImagePullSecrets)
+		if err != nil {
+			return err
+		}
+
+		_, err = serviceAccounts.Update(sa)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("Updating service account: %s", err)
+	}
+
+	return nil
+}
@@ -25,7 +25,6 @@ import (
 	"github.com/spf13/cobra"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/client-go/kubernetes"
 )
 
 type CreateOptions struct {
@@ -46,11 +45,13 @@ func NewCreateCmd(o *CreateOptions, flagsFactory cmdcore.FlagsFactory) *cobra.Co
 		Short: "Create service account",
 		Long: `Create service account.
 
-Use 'kubectl get serviceaccount -n <namespace>' to list service accounts.
 Use 'kubectl delete serviceaccount <name> -n <namespace>' to delete service account.`,
 		Example: `
   # Create service account 'sa1' with two secrets in namespace 'ns1'
-  knctl service-account create -a sa1 --secret secret1 --secret secret2 -n ns1`,
+  knctl service-account create -a sa1 --secret secret1 --secret secret2 -n ns1
+
+  # Create service account 'sa1' with secret and image pull secret in namespace 'ns1'
+  knctl service-account create -a sa1 --secret reg-secret --image-pull-secret pull-secret -n ns1`,
 		RunE: func(_ *cobra.Command, _ []string) error { return o.Run() },
 	}
 	o.ServiceAccountFlags.Set(cmd, flagsFactory)
@@ -71,7 +72,10 @@ func (o *CreateOptions) Run() error {
 		}),
 	}
 
-	o.populateSecrets(serviceAccount, coreClient)
+	err = NewServiceAccountSecrets(coreClient).Add(serviceAccount, o.CreateFlags.Secrets, o.CreateFlags.ImagePullSecrets)
+	if err != nil {
+		return err
+	}
 
 	created, err := coreClient.CoreV1().ServiceAccounts(o.ServiceAccountFlags.NamespaceFlags.Name).Create(serviceAccount)
 	if err != nil {
@@ -83,28 +87,6 @@ func (o *CreateOptions) Run() error {
 	return nil
 }
 
-func (o *CreateOptions) populateSecrets(sa *corev1.ServiceAccount, coreClient kubernetes.Interface) error {
-	for _, secretName := range o.CreateFlags.Secrets {
-		secret, err := coreClient.CoreV1().Secrets(o.ServiceAccountFlags.NamespaceFlags.Name).Get(secretName, metav1.GetOptions{})
-		if err != nil {
-			return fmt.Errorf("Getting secret '%s': %s", secretName, err)
-		}
-
-		if secret.Type == corev1.SecretTypeDockerConfigJson {
-			sa.ImagePullSecrets = append(sa.ImagePullSecrets, corev1.LocalObjectReference{Name: secretName})
-		} else {
-			sa.Secrets = append(sa.Secrets, corev1.ObjectReference{Name: secretName})
-		}
-	}
-
-	// Explicit image pull secrets
-	for _, secretName := range o.CreateFlags.ImagePullSecrets {
-		sa.ImagePullSecrets = append(sa.ImagePullSecrets, corev1.LocalObjectReference{Name: secretName})
-	}
-
-	return nil
-}
-
 func (o *CreateOptions) printTable(sa *corev1.ServiceAccount) {
 	table := uitable.Table{
 		Header: []uitable.Header{
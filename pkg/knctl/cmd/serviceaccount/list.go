@@ -0,0 +1,103 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package serviceaccount
+
+import (
+	"fmt"
+
+	"github.com/cppforlife/go-cli-ui/ui"
+	uitable "github.com/cppforlife/go-cli-ui/ui/table"
+	cmdcore "github.com/cppforlife/knctl/pkg/knctl/cmd/core"
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type ListOptions struct {
+	ui          ui.UI
+	depsFactory cmdcore.DepsFactory
+
+	NamespaceFlags cmdcore.NamespaceFlags
+}
+
+func NewListOptions(ui ui.UI, depsFactory cmdcore.DepsFactory) *ListOptions {
+	return &ListOptions{ui: ui, depsFactory: depsFactory}
+}
+
+func NewListCmd(o *ListOptions, flagsFactory cmdcore.FlagsFactory) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "list",
+		Aliases: cmdcore.ListAliases,
+		Short:   "List service accounts",
+		Long:    "List all service accounts in a namespace",
+		Example: `
+  # List all service accounts in namespace 'ns1'
+  knctl service-account list -n ns1`,
+		RunE: func(_ *cobra.Command, _ []string) error { return o.Run() },
+	}
+	o.NamespaceFlags.Set(cmd, flagsFactory)
+	return cmd
+}
+
+func (o *ListOptions) Run() error {
+	coreClient, err := o.depsFactory.CoreClient()
+	if err != nil {
+		return err
+	}
+
+	serviceAccounts, err := coreClient.CoreV1().ServiceAccounts(o.NamespaceFlags.Name).List(metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+
+	table := uitable.Table{
+		Title:   fmt.Sprintf("Service accounts in namespace '%s'", o.NamespaceFlags.Name),
+		Content: "service accounts",
+
+		Header: []uitable.Header{
+			uitable.NewHeader("Name"),
+			uitable.NewHeader("Secrets"),
+			uitable.NewHeader("Image pull secrets"),
+			uitable.NewHeader("Age"),
+		},
+
+		SortBy: []uitable.ColumnSort{
+			{Column: 0, Asc: true},
+		},
+	}
+
+	for _, sa := range serviceAccounts.Items {
+		var secretNames, imagePullSecretNames []string
+
+		for _, ref := range sa.Secrets {
+			secretNames = append(secretNames, ref.Name)
+		}
+		for _, ref := range sa.ImagePullSecrets {
+			imagePullSecretNames = append(imagePullSecretNames, ref.Name)
+		}
+
+		table.Rows = append(table.Rows, []uitable.Value{
+			uitable.NewValueString(sa.Name),
+			uitable.NewValueStrings(secretNames),
+			uitable.NewValueStrings(imagePullSecretNames),
+			cmdcore.NewValueAge(sa.CreationTimestamp.Time),
+		})
+	}
+
+	o.ui.PrintTable(table)
+
+	return nil
+}
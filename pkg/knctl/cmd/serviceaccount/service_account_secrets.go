@@ -0,0 +1,75 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package serviceaccount
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+type ServiceAccountSecrets struct {
+	coreClient kubernetes.Interface
+}
+
+func NewServiceAccountSecrets(coreClient kubernetes.Interface) ServiceAccountSecrets {
+	return ServiceAccountSecrets{coreClient}
+}
+
+// Add references given secrets from a service account. Docker config secrets
+// are treated as image pull secrets; already referenced secrets are skipped.
+func (s ServiceAccountSecrets) Add(sa *corev1.ServiceAccount, secretNames, imagePullSecretNames []string) error {
+	for _, secretName := range secretNames {
+		secret, err := s.coreClient.CoreV1().Secrets(sa.Namespace).Get(secretName, metav1.GetOptions{})
+		if err != nil {
+			return fmt.Errorf("Getting secret '%s': %s", secretName, err)
+		}
+
+		if secret.Type == corev1.SecretTypeDockerConfigJson {
+			s.addImagePullSecret(sa, secretName)
+		} else {
+			s.addSecret(sa, secretName)
+		}
+	}
+
+	// Explicit image pull secrets
+	for _, secretName := range imagePullSecretNames {
+		s.addImagePullSecret(sa, secretName)
+	}
+
+	return nil
+}
+
+func (s ServiceAccountSecrets) addSecret(sa *corev1.ServiceAccount, secretName string) {
+	for _, ref := range sa.Secrets {
+		if ref.Name == secretName {
+			return
+		}
+	}
+	sa.Secrets = append(sa.Secrets, corev1.ObjectReference{Name: secretName})
+}
+
+func (s ServiceAccountSecrets) addImagePullSecret(sa *corev1.ServiceAccount, secretName string) {
+	for _, ref := range sa.ImagePullSecrets {
+		if ref.Name == secretName {
+			return
+		}
+	}
+	sa.ImagePullSecrets = append(sa.ImagePullSecrets, corev1.LocalObjectReference{Name: secretName})
+}
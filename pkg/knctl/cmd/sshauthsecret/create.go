@@ -50,7 +50,14 @@ Use 'kubectl get secret -n <namespace>' to list secrets.
 Use 'kubectl delete secret <name> -n <namespace>' to delete secret.`,
 		Example: `
   # Create SSH secret 'secret1' in namespace 'ns1'
-  knctl ssh-auth-secret create -s secret1 --url github.com --private-key ... --known-hosts ... -n ns1`,
+  knctl ssh-auth-secret create -s secret1 --url github.com --private-key ... --known-hosts ... -n ns1
+
+  # Create SSH secret 'secret1' with generated key for Github.com in namespace 'ns1'
+  # (printed public key should be added as a deploy key to Github repository)
+  knctl ssh-auth-secret create -s secret1 --github --generate-key -n ns1
+
+  # Create SSH secret 'secret1' with generated key for self hosted Git server in namespace 'ns1'
+  knctl ssh-auth-secret create -s secret1 --generic --url git.example.com --generate-key -n ns1`,
 		RunE: func(_ *cobra.Command, _ []string) error { return o.Run() },
 	}
 	o.SecretFlags.Set(cmd, flagsFactory)
@@ -64,7 +71,9 @@ func (o *CreateOptions) Run() error {
 		return err
 	}
 
-	err = o.CreateFlags.BackfillPrivateKey()
+	keyComment := fmt.Sprintf("knctl:%s/%s", o.SecretFlags.NamespaceFlags.Name, o.SecretFlags.Name)
+
+	publicKey, err := o.CreateFlags.BackfillPrivateKey(keyComment)
 	if err != nil {
 		return err
 	}
@@ -104,6 +113,11 @@ func (o *CreateOptions) Run() error {
 
 	o.printTable(createdSecret)
 
+	if len(publicKey) > 0 {
+		o.ui.PrintLinef("Add following public key to '%s' (for example as a repository deploy key):", o.CreateFlags.URL)
+		o.ui.PrintBlock([]byte(publicKey + "\n"))
+	}
+
 	return nil
 }
 
@@ -35,10 +35,12 @@ type CreateFlags struct {
 
 	PrivateKey     string
 	PrivateKeyPath string
+	GenerateKey    bool
 
 	KnownHosts string
 
-	Github bool
+	Github  bool
+	Generic bool
 }
 
 func (s *CreateFlags) Set(cmd *cobra.Command, flagsFactory cmdcore.FlagsFactory) {
@@ -50,24 +52,40 @@ func (s *CreateFlags) Set(cmd *cobra.Command, flagsFactory cmdcore.FlagsFactory)
 	defaultKey := os.Getenv("KNCTL_SSH_AUTH_SECRET_PRIVATE_KEY")
 	cmd.Flags().StringVar(&s.PrivateKey, "private-key", defaultKey, "Set private key in PEM format ($KNCTL_SSH_AUTH_SECRET_PRIVATE_KEY)")
 	cmd.Flags().StringVar(&s.PrivateKeyPath, "private-key-path", "", "Set private key in PEM format from file path")
+	cmd.Flags().BoolVar(&s.GenerateKey, "generate-key", false, "Generate ed25519 private key and print its public key")
 
 	cmd.Flags().StringVar(&s.KnownHosts, "known-hosts", "", "Set known hosts")
 
 	cmd.Flags().BoolVar(&s.Github, "github", false, "Preconfigure type and url for Github.com Git access")
+	cmd.Flags().BoolVar(&s.Generic, "generic", false, "Preconfigure type for Git access to host specified via --url")
 }
 
 func (s *CreateFlags) BackfillTypeAndURL() error {
+	if s.Github && s.Generic {
+		return fmt.Errorf("Expected to not specify --github and --generic together")
+	}
+
 	if s.Github {
 		if len(s.Type) != 0 || len(s.URL) != 0 {
 			return fmt.Errorf("Expected to not specify --type or --url when preconfigured flags are used")
 		}
 	}
 
+	if s.Generic && len(s.Type) != 0 {
+		return fmt.Errorf("Expected to not specify --type when --generic flag is used")
+	}
+
 	switch {
 	case s.Github:
 		s.Type = "git"
 		s.URL = "github.com"
 
+	case s.Generic:
+		s.Type = "git"
+		if len(s.URL) == 0 {
+			return fmt.Errorf("Expected --url to be non-empty when --generic flag is used")
+		}
+
 	default:
 		if len(s.Type) == 0 || len(s.URL) == 0 {
 			return fmt.Errorf("Expected --type and --url to be non-empty when preconfigured flags are not used")
@@ -77,13 +95,34 @@ func (s *CreateFlags) BackfillTypeAndURL() error {
 	return nil
 }
 
-func (s *CreateFlags) BackfillPrivateKey() error {
+// BackfillPrivateKey reads or generates private key. Public key is returned
+// only when private key is generated since it needs to be installed on the Git host.
+func (s *CreateFlags) BackfillPrivateKey(keyComment string) (string, error) {
+	if s.GenerateKey {
+		if len(s.PrivateKey) > 0 || len(s.PrivateKeyPath) > 0 {
+			return "", fmt.Errorf("Expected to not find --private-key or --private-key-path specified together with --generate-key")
+		}
+
+		key, err := GenerateSSHKey(keyComment)
+		if err != nil {
+			return "", err
+		}
+
+		s.PrivateKey = key.PrivateKeyPEM
+
+		return key.AuthorizedKey, nil
+	}
+
+	return "", s.readPrivateKey()
+}
+
+func (s *CreateFlags) readPrivateKey() error {
 	if len(s.PrivateKey) > 0 && len(s.PrivateKeyPath) > 0 {
 		return fmt.Errorf("Expected to not find --private-key and --private-key-path specified together")
 	}
 
 	if len(s.PrivateKey) == 0 && len(s.PrivateKeyPath) == 0 {
-		return fmt.Errorf("Expected to find --private-key or --private-key-path specified")
+		return fmt.Errorf("Expected to find --private-key, --private-key-path or --generate-key specified")
 	}
 
 	if len(s.PrivateKeyPath) > 0 {
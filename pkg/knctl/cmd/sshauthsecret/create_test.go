@@ -17,6 +17,7 @@ limitations under the License.
 package sshauthsecret_test
 
 import (
+	"strings"
 	"testing"
 
 	. "github.com/cppforlife/knctl/pkg/knctl/cmd"
@@ -109,6 +110,66 @@ func TestNewCreateCmd_OkGithub(t *testing.T) {
 	})
 }
 
+func TestNewCreateCmd_OkGenerateKey(t *testing.T) {
+	realCmd := NewCreateOptions(nil, cmdcore.NewDepsFactory())
+	cmd := NewTestCmd(t, NewCreateCmd(realCmd, cmdcore.FlagsFactory{}))
+	cmd.Execute([]string{
+		"--namespace", "test-namespace",
+		"--secret", "test-secret",
+		"--url", "git.example.com",
+		"--generic",
+		"--generate-key",
+	})
+	cmd.ExpectReachesExecution()
+
+	DeepEqual(t, realCmd.CreateFlags, CreateFlags{
+		URL:         "git.example.com",
+		GenerateKey: true,
+		Generic:     true,
+	})
+
+	err := realCmd.CreateFlags.BackfillTypeAndURL()
+	if err != nil {
+		t.Fatalf("Error: %s", err)
+	}
+
+	publicKey, err := realCmd.CreateFlags.BackfillPrivateKey("test-comment")
+	if err != nil {
+		t.Fatalf("Error: %s", err)
+	}
+
+	if realCmd.CreateFlags.Type != "git" || realCmd.CreateFlags.URL != "git.example.com" {
+		t.Fatalf("Expected type and url to be backfilled: %#v", realCmd.CreateFlags)
+	}
+
+	if !strings.HasPrefix(publicKey, "ssh-ed25519 ") || len(realCmd.CreateFlags.PrivateKey) == 0 {
+		t.Fatalf("Expected key to be generated: %s", publicKey)
+	}
+
+	err = realCmd.CreateFlags.Validate()
+	if err != nil {
+		t.Fatalf("Expected generated key to be valid: %s", err)
+	}
+}
+
+func TestCreateFlags_GenerateKeyWithPrivateKey(t *testing.T) {
+	flags := CreateFlags{GenerateKey: true, PrivateKeyPath: "/tmp/key"}
+
+	_, err := flags.BackfillPrivateKey("")
+	if err == nil {
+		t.Fatalf("Expected error when private key is given together with --generate-key")
+	}
+}
+
+func TestCreateFlags_GenericRequiresURL(t *testing.T) {
+	flags := CreateFlags{Generic: true}
+
+	err := flags.BackfillTypeAndURL()
+	if err == nil || err.Error() != "Expected --url to be non-empty when --generic flag is used" {
+		t.Fatalf("Expected error about missing url but was: %v", err)
+	}
+}
+
 func TestNewCreateCmd_RequiredFlags(t *testing.T) {
 	realCmd := NewCreateOptions(nil, cmdcore.NewDepsFactory())
 	cmd := NewTestCmd(t, NewCreateCmd(realCmd, cmdcore.FlagsFactory{}))
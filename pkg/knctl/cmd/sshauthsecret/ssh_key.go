@@ -0,0 +1,104 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sshauthsecret
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/pem"
+	"fmt"
+)
+
+const (
+	sshKeyTypeED25519 = "ssh-ed25519"
+)
+
+// SSHKey is an ed25519 key pair encoded the same way as ssh-keygen does
+type SSHKey struct {
+	PrivateKeyPEM string // unencrypted OpenSSH private key
+	AuthorizedKey string // single authorized_keys line
+}
+
+func GenerateSSHKey(comment string) (SSHKey, error) {
+	pubKey, privKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return SSHKey{}, fmt.Errorf("Generating ed25519 key: %s", err)
+	}
+
+	pubKeyBlob := sshString(nil, []byte(sshKeyTypeED25519))
+	pubKeyBlob = sshString(pubKeyBlob, pubKey)
+
+	privKeyBytes, err := marshalOpenSSHPrivateKey(pubKeyBlob, pubKey, privKey, comment)
+	if err != nil {
+		return SSHKey{}, err
+	}
+
+	authorizedKey := sshKeyTypeED25519 + " " + base64.StdEncoding.EncodeToString(pubKeyBlob)
+	if len(comment) > 0 {
+		authorizedKey += " " + comment
+	}
+
+	return SSHKey{
+		PrivateKeyPEM: string(pem.EncodeToMemory(&pem.Block{Type: "OPENSSH PRIVATE KEY", Bytes: privKeyBytes})),
+		AuthorizedKey: authorizedKey,
+	}, nil
+}
+
+// Format is described in https://github.com/openssh/openssh-portable/blob/master/PROTOCOL.key
+func marshalOpenSSHPrivateKey(pubKeyBlob []byte, pubKey ed25519.PublicKey, privKey ed25519.PrivateKey, comment string) ([]byte, error) {
+	var checkBytes [4]byte
+
+	_, err := rand.Read(checkBytes[:])
+	if err != nil {
+		return nil, fmt.Errorf("Generating check bytes: %s", err)
+	}
+
+	var priv []byte
+	priv = append(priv, checkBytes[:]...)
+	priv = append(priv, checkBytes[:]...)
+	priv = sshString(priv, []byte(sshKeyTypeED25519))
+	priv = sshString(priv, pubKey)
+	priv = sshString(priv, privKey) // seed followed by public key
+	priv = sshString(priv, []byte(comment))
+
+	// Unencrypted keys are padded to block size of 8
+	for i := byte(1); len(priv)%8 != 0; i++ {
+		priv = append(priv, i)
+	}
+
+	result := []byte("openssh-key-v1\x00")
+	result = sshString(result, []byte("none")) // cipher
+	result = sshString(result, []byte("none")) // kdf
+	result = sshString(result, nil)            // kdf options
+	result = sshUint32(result, 1)              // number of keys
+	result = sshString(result, pubKeyBlob)
+	result = sshString(result, priv)
+
+	return result, nil
+}
+
+func sshString(buf []byte, val []byte) []byte {
+	return append(sshUint32(buf, uint32(len(val))), val...)
+}
+
+func sshUint32(buf []byte, val uint32) []byte {
+	var bs [4]byte
+	binary.BigEndian.PutUint32(bs[:], val)
+	return append(buf, bs[:]...)
+}
@@ -0,0 +1,65 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sshauthsecret_test
+
+import (
+	"encoding/pem"
+	"io/ioutil"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	. "github.com/cppforlife/knctl/pkg/knctl/cmd/sshauthsecret"
+)
+
+func TestGenerateSSHKey(t *testing.T) {
+	key, err := GenerateSSHKey("knctl:ns1/secret1")
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	block, _ := pem.Decode([]byte(key.PrivateKeyPEM))
+	if block == nil || block.Type != "OPENSSH PRIVATE KEY" {
+		t.Fatalf("Expected private key to be OpenSSH PEM block: %s", key.PrivateKeyPEM)
+	}
+
+	if !strings.HasPrefix(key.AuthorizedKey, "ssh-ed25519 AAAAC3NzaC1lZDI1NTE5") || !strings.HasSuffix(key.AuthorizedKey, " knctl:ns1/secret1") {
+		t.Fatalf("Expected authorized key to be ed25519 key with comment: %s", key.AuthorizedKey)
+	}
+
+	if _, err := exec.LookPath("ssh-keygen"); err != nil {
+		t.Skip("Skipping verification since ssh-keygen is not installed")
+	}
+
+	path := filepath.Join(t.TempDir(), "key")
+
+	err = ioutil.WriteFile(path, []byte(key.PrivateKeyPEM), 0600)
+	if err != nil {
+		t.Fatalf("Writing key: %s", err)
+	}
+
+	// Derives public key from private key
+	out, err := exec.Command("ssh-keygen", "-y", "-f", path).CombinedOutput()
+	if err != nil {
+		t.Fatalf("Expected ssh-keygen to read private key: %s (output: %s)", err, out)
+	}
+
+	if strings.TrimSpace(string(out)) != key.AuthorizedKey {
+		t.Fatalf("Expected derived public key '%s' to match '%s'", out, key.AuthorizedKey)
+	}
+}
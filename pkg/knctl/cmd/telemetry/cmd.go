@@ -0,0 +1,41 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package telemetry
+
+import (
+	cmdcore "github.com/cppforlife/knctl/pkg/knctl/cmd/core"
+	"github.com/spf13/cobra"
+)
+
+func NewCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "telemetry",
+		Short: "Anonymous usage metrics (opt-in)",
+		Long: `Anonymous usage metrics (opt-in).
+
+When turned on, knctl records name of executed command (e.g. 'knctl service list'),
+its duration, error class (e.g. 'NotFound') and client version/platform.
+Arguments, flag values, resource names and error messages are never recorded.
+
+Telemetry is off by default and can be disabled regardless of saved
+configuration via $KNCTL_TELEMETRY_DISABLED or $DO_NOT_TRACK.`,
+		Annotations: map[string]string{
+			cmdcore.SystemHelpGroup.Key: cmdcore.SystemHelpGroup.Value,
+		},
+	}
+	return cmd
+}
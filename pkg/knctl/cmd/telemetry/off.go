@@ -0,0 +1,68 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package telemetry
+
+import (
+	"github.com/cppforlife/go-cli-ui/ui"
+	cmdcore "github.com/cppforlife/knctl/pkg/knctl/cmd/core"
+	"github.com/cppforlife/knctl/pkg/knctl/telemetry"
+	"github.com/spf13/cobra"
+)
+
+type OffOptions struct {
+	ui ui.UI
+}
+
+func NewOffOptions(ui ui.UI) *OffOptions {
+	return &OffOptions{ui: ui}
+}
+
+func NewOffCmd(o *OffOptions, flagsFactory cmdcore.FlagsFactory) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "off",
+		Short: "Turn off telemetry",
+		Example: `
+  # Stop sending usage metrics
+  knctl telemetry off`,
+		RunE: func(_ *cobra.Command, _ []string) error { return o.Run() },
+	}
+	return cmd
+}
+
+func (o *OffOptions) Run() error {
+	configPath, err := telemetry.DefaultConfigPath()
+	if err != nil {
+		return err
+	}
+
+	config, err := telemetry.ReadConfig(configPath)
+	if err != nil {
+		return err
+	}
+
+	// Keep endpoint so that turning telemetry back on uses the same collector
+	config.Enabled = false
+
+	err = telemetry.WriteConfig(configPath, config)
+	if err != nil {
+		return err
+	}
+
+	o.ui.PrintLinef("Turned off telemetry")
+
+	return nil
+}
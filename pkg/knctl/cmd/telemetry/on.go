@@ -0,0 +1,96 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package telemetry
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/cppforlife/go-cli-ui/ui"
+	cmdcore "github.com/cppforlife/knctl/pkg/knctl/cmd/core"
+	"github.com/cppforlife/knctl/pkg/knctl/telemetry"
+	"github.com/spf13/cobra"
+)
+
+type OnOptions struct {
+	ui ui.UI
+
+	Endpoint string
+}
+
+func NewOnOptions(ui ui.UI) *OnOptions {
+	return &OnOptions{ui: ui}
+}
+
+func NewOnCmd(o *OnOptions, flagsFactory cmdcore.FlagsFactory) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "on",
+		Short: "Turn on telemetry",
+		Long: `Turn on telemetry.
+
+There is no default endpoint, hence --endpoint has to be specified
+(unless it was saved before or set via $` + telemetry.EndpointEnvVar + `).`,
+		Example: `
+  # Send anonymous usage metrics to internal collector
+  knctl telemetry on --endpoint https://metrics.corp.example.com/knctl`,
+		RunE: func(_ *cobra.Command, _ []string) error { return o.Run() },
+	}
+	cmd.Flags().StringVar(&o.Endpoint, "endpoint", "", "Set URL that receives events")
+	return cmd
+}
+
+func (o *OnOptions) Run() error {
+	if len(o.Endpoint) > 0 {
+		parsedURL, err := url.Parse(o.Endpoint)
+		if err != nil || (parsedURL.Scheme != "http" && parsedURL.Scheme != "https") || len(parsedURL.Host) == 0 {
+			return fmt.Errorf("Expected endpoint to be an HTTP(S) URL, but was '%s'", o.Endpoint)
+		}
+	}
+
+	configPath, err := telemetry.DefaultConfigPath()
+	if err != nil {
+		return err
+	}
+
+	config, err := telemetry.ReadConfig(configPath)
+	if err != nil {
+		return err
+	}
+
+	if len(o.Endpoint) > 0 {
+		config.Endpoint = o.Endpoint
+	}
+
+	if len(config.EffectiveEndpoint()) == 0 {
+		return fmt.Errorf("Expected --endpoint to be specified since there is no default telemetry endpoint")
+	}
+
+	config.Enabled = true
+
+	err = telemetry.WriteConfig(configPath, config)
+	if err != nil {
+		return err
+	}
+
+	o.ui.PrintLinef("Turned on telemetry (events are sent to '%s')", config.EffectiveEndpoint())
+
+	if telemetry.DisabledByEnv() {
+		o.ui.ErrorLinef("Warning: Telemetry remains disabled by environment ($%s or $DO_NOT_TRACK)", telemetry.DisableEnvVar)
+	}
+
+	return nil
+}
@@ -0,0 +1,115 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package telemetry_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/cppforlife/go-cli-ui/ui"
+	. "github.com/cppforlife/knctl/pkg/knctl/cmd"
+	cmdcore "github.com/cppforlife/knctl/pkg/knctl/cmd/core"
+	. "github.com/cppforlife/knctl/pkg/knctl/cmd/telemetry"
+	"github.com/cppforlife/knctl/pkg/knctl/telemetry"
+)
+
+func TestNewOnCmd_Ok(t *testing.T) {
+	realCmd := NewOnOptions(nil)
+	cmd := NewTestCmd(t, NewOnCmd(realCmd, cmdcore.FlagsFactory{}))
+	cmd.ExpectBasicConfig()
+	cmd.Execute([]string{"--endpoint", "https://collector.example.com"})
+	cmd.ExpectReachesExecution()
+
+	DeepEqual(t, realCmd.Endpoint, "https://collector.example.com")
+}
+
+func TestOnOptions_RequiresEndpoint(t *testing.T) {
+	homeDir, err := ioutil.TempDir("", "knctl-home")
+	if err != nil {
+		t.Fatalf("Creating temp dir: %s", err)
+	}
+	defer os.RemoveAll(homeDir)
+
+	origHomeDir := os.Getenv("HOME")
+	os.Setenv("HOME", homeDir)
+	defer os.Setenv("HOME", origHomeDir)
+
+	err = NewOnOptions(ui.NewNoopUI()).Run()
+	if err == nil || err.Error() != "Expected --endpoint to be specified since there is no default telemetry endpoint" {
+		t.Fatalf("Expected missing endpoint error, but was: %v", err)
+	}
+
+	config, err := telemetry.ReadConfig(filepath.Join(homeDir, ".knctl", "telemetry.json"))
+	if err != nil {
+		t.Fatalf("Reading config: %s", err)
+	}
+
+	DeepEqual(t, config, telemetry.Config{})
+}
+
+func TestOnOffOptions_SaveConfig(t *testing.T) {
+	homeDir, err := ioutil.TempDir("", "knctl-home")
+	if err != nil {
+		t.Fatalf("Creating temp dir: %s", err)
+	}
+	defer os.RemoveAll(homeDir)
+
+	origHomeDir := os.Getenv("HOME")
+	os.Setenv("HOME", homeDir)
+	defer os.Setenv("HOME", origHomeDir)
+
+	configPath := filepath.Join(homeDir, ".knctl", "telemetry.json")
+
+	onOpts := NewOnOptions(ui.NewNoopUI())
+	onOpts.Endpoint = "https://collector.example.com"
+
+	err = onOpts.Run()
+	if err != nil {
+		t.Fatalf("Expected turning on to succeed: %s", err)
+	}
+
+	config, err := telemetry.ReadConfig(configPath)
+	if err != nil {
+		t.Fatalf("Reading config: %s", err)
+	}
+
+	DeepEqual(t, config, telemetry.Config{Enabled: true, Endpoint: "https://collector.example.com"})
+
+	err = NewOffOptions(ui.NewNoopUI()).Run()
+	if err != nil {
+		t.Fatalf("Expected turning off to succeed: %s", err)
+	}
+
+	config, err = telemetry.ReadConfig(configPath)
+	if err != nil {
+		t.Fatalf("Reading config: %s", err)
+	}
+
+	DeepEqual(t, config, telemetry.Config{Enabled: false, Endpoint: "https://collector.example.com"})
+}
+
+func TestOnOptions_RejectsInvalidEndpoint(t *testing.T) {
+	onOpts := NewOnOptions(ui.NewNoopUI())
+	onOpts.Endpoint = "collector.example.com"
+
+	err := onOpts.Run()
+	if err == nil {
+		t.Fatalf("Expected error")
+	}
+}
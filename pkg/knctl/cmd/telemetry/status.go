@@ -0,0 +1,78 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package telemetry
+
+import (
+	"github.com/cppforlife/go-cli-ui/ui"
+	cmdcore "github.com/cppforlife/knctl/pkg/knctl/cmd/core"
+	"github.com/cppforlife/knctl/pkg/knctl/telemetry"
+	"github.com/spf13/cobra"
+)
+
+type StatusOptions struct {
+	ui ui.UI
+}
+
+func NewStatusOptions(ui ui.UI) *StatusOptions {
+	return &StatusOptions{ui: ui}
+}
+
+func NewStatusCmd(o *StatusOptions, flagsFactory cmdcore.FlagsFactory) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "status",
+		Short: "Show telemetry status",
+		Example: `
+  # Show whether telemetry is on and where events are sent
+  knctl telemetry status`,
+		RunE: func(_ *cobra.Command, _ []string) error { return o.Run() },
+	}
+	return cmd
+}
+
+func (o *StatusOptions) Run() error {
+	configPath, err := telemetry.DefaultConfigPath()
+	if err != nil {
+		return err
+	}
+
+	config, err := telemetry.ReadConfig(configPath)
+	if err != nil {
+		return err
+	}
+
+	status := "off"
+
+	switch {
+	case config.Active():
+		status = "on"
+	case config.Enabled && telemetry.DisabledByEnv():
+		status = "off (disabled by environment)"
+	case config.Enabled:
+		status = "off (endpoint is not set)"
+	}
+
+	endpoint := config.EffectiveEndpoint()
+	if len(endpoint) == 0 {
+		endpoint = "(not set)"
+	}
+
+	o.ui.PrintLinef("Telemetry: %s", status)
+	o.ui.PrintLinef("Endpoint: %s", endpoint)
+	o.ui.PrintLinef("Config: %s", configPath)
+
+	return nil
+}
@@ -0,0 +1,93 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package test
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/cppforlife/knctl/pkg/knctl/knativeconfig"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	autoscalerConfigMapName = "config-autoscaler"
+)
+
+// AutoscalerConfig includes config-autoscaler values relevant
+// to scaling to and from zero; missing values use Knative defaults
+type AutoscalerConfig struct {
+	EnableScaleToZero      bool
+	StableWindow           time.Duration
+	ScaleToZeroGracePeriod time.Duration
+	ActivatorCapacity      float64
+	TargetBurstCapacity    float64
+}
+
+func DefaultAutoscalerConfig() AutoscalerConfig {
+	return AutoscalerConfig{
+		EnableScaleToZero:      true,
+		StableWindow:           60 * time.Second,
+		ScaleToZeroGracePeriod: 30 * time.Second,
+		ActivatorCapacity:      100,
+		TargetBurstCapacity:    200,
+	}
+}
+
+// ScaleDownPeriod is the minimum amount of time revision
+// has to be idle before it's expected to have no pods
+func (c AutoscalerConfig) ScaleDownPeriod() time.Duration {
+	return c.StableWindow + c.ScaleToZeroGracePeriod
+}
+
+func ReadAutoscalerConfig(coreClient kubernetes.Interface) (AutoscalerConfig, error) {
+	config := DefaultAutoscalerConfig()
+
+	configMap, err := coreClient.CoreV1().ConfigMaps(knativeconfig.ServingNamespace).Get(autoscalerConfigMapName, metav1.GetOptions{})
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return config, nil
+		}
+		return config, fmt.Errorf("Getting config map '%s/%s': %s", knativeconfig.ServingNamespace, autoscalerConfigMapName, err)
+	}
+
+	for key, val := range configMap.Data {
+		var err error
+
+		switch key {
+		case "enable-scale-to-zero":
+			config.EnableScaleToZero, err = strconv.ParseBool(val)
+		case "stable-window":
+			config.StableWindow, err = time.ParseDuration(val)
+		case "scale-to-zero-grace-period":
+			config.ScaleToZeroGracePeriod, err = time.ParseDuration(val)
+		case "activator-capacity":
+			config.ActivatorCapacity, err = strconv.ParseFloat(val, 64)
+		case "target-burst-capacity":
+			config.TargetBurstCapacity, err = strconv.ParseFloat(val, 64)
+		}
+
+		if err != nil {
+			return config, fmt.Errorf("Parsing config map '%s' key '%s': %s", autoscalerConfigMapName, key, err)
+		}
+	}
+
+	return config, nil
+}
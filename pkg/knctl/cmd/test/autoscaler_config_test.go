@@ -0,0 +1,72 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package test_test
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/cppforlife/knctl/pkg/knctl/cmd"
+	. "github.com/cppforlife/knctl/pkg/knctl/cmd/test"
+	"github.com/cppforlife/knctl/pkg/knctl/testkit"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestReadAutoscalerConfig(t *testing.T) {
+	cluster := testkit.NewCluster(t, &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "config-autoscaler", Namespace: "knative-serving"},
+		Data: map[string]string{
+			"stable-window":      "120s",
+			"activator-capacity": "50",
+			"panic-window":       "6s",
+		},
+	})
+
+	config, err := ReadAutoscalerConfig(cluster.CoreClient())
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	expected := DefaultAutoscalerConfig()
+	expected.StableWindow = 120 * time.Second
+	expected.ActivatorCapacity = 50
+
+	DeepEqual(t, config, expected)
+	DeepEqual(t, config.ScaleDownPeriod(), 150*time.Second)
+}
+
+func TestReadAutoscalerConfig_MissingConfigMap(t *testing.T) {
+	config, err := ReadAutoscalerConfig(testkit.NewCluster(t).CoreClient())
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	DeepEqual(t, config, DefaultAutoscalerConfig())
+}
+
+func TestReadAutoscalerConfig_InvalidValue(t *testing.T) {
+	cluster := testkit.NewCluster(t, &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "config-autoscaler", Namespace: "knative-serving"},
+		Data:       map[string]string{"enable-scale-to-zero": "maybe"},
+	})
+
+	_, err := ReadAutoscalerConfig(cluster.CoreClient())
+	if err == nil {
+		t.Fatalf("Expected error")
+	}
+}
@@ -0,0 +1,291 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package test
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/cppforlife/go-cli-ui/ui"
+	uitable "github.com/cppforlife/go-cli-ui/ui/table"
+	cmdcore "github.com/cppforlife/knctl/pkg/knctl/cmd/core"
+	cmdflags "github.com/cppforlife/knctl/pkg/knctl/cmd/flags"
+	cmdsvc "github.com/cppforlife/knctl/pkg/knctl/cmd/service"
+	ctlservice "github.com/cppforlife/knctl/pkg/knctl/service"
+	"github.com/knative/serving/pkg/apis/serving"
+	"github.com/knative/serving/pkg/apis/serving/v1alpha1"
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+)
+
+type ScaleToZeroOptions struct {
+	ui          ui.UI
+	depsFactory cmdcore.DepsFactory
+
+	ServiceFlags cmdflags.ServiceFlags
+	CurlFlags    cmdsvc.CurlFlags
+	Timeout      time.Duration
+	MaxWakeUp    time.Duration
+
+	PollInterval time.Duration
+}
+
+func NewScaleToZeroOptions(ui ui.UI, depsFactory cmdcore.DepsFactory) *ScaleToZeroOptions {
+	return &ScaleToZeroOptions{ui: ui, depsFactory: depsFactory, PollInterval: 5 * time.Second}
+}
+
+func NewScaleToZeroCmd(o *ScaleToZeroOptions, flagsFactory cmdcore.FlagsFactory) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "scale-to-zero",
+		Short: "Test that service scales to zero and back",
+		Long: `Test that service's latest ready revision scales to zero and back.
+
+Checks that autoscaler and activator settings allow scaling to zero,
+waits (without sending requests) for revision to have no pods after
+stable window and scale to zero grace period, then sends a request
+and measures how long it takes for revision to wake up.
+
+Service should not receive other traffic during the test.`,
+		Example: `
+  # Test scale to zero behavior of service 'svc1' in namespace 'ns1'
+  knctl test scale-to-zero -s svc1 -n ns1
+
+  # Test scale to zero behavior allowing at most 5s for wake up
+  knctl test scale-to-zero -s svc1 --max-wake-up 5s -n ns1`,
+		RunE: func(_ *cobra.Command, _ []string) error { return o.Run() },
+	}
+	o.ServiceFlags.Set(cmd, flagsFactory)
+	o.CurlFlags.Set(cmd, flagsFactory)
+	cmd.Flags().DurationVar(&o.Timeout, "timeout", 0, "Set maximum time to wait for scale down (default: stable window and grace period plus 2m)")
+	cmd.Flags().DurationVar(&o.MaxWakeUp, "max-wake-up", 15*time.Second, "Set maximum allowed latency of first request after scale down")
+	return cmd
+}
+
+func (o *ScaleToZeroOptions) Run() error {
+	if o.MaxWakeUp <= 0 {
+		return fmt.Errorf("Expected maximum wake up latency to be greater than 0")
+	}
+
+	coreClient, err := o.depsFactory.CoreClient()
+	if err != nil {
+		return err
+	}
+
+	servingClient, err := o.depsFactory.ServingClient()
+	if err != nil {
+		return err
+	}
+
+	service, err := servingClient.ServingV1alpha1().Services(o.ServiceFlags.NamespaceFlags.Name).Get(o.ServiceFlags.Name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+
+	revisionName := service.Status.LatestReadyRevisionName
+	if len(revisionName) == 0 {
+		return fmt.Errorf("Expected service '%s' to have latest ready revision", service.Name)
+	}
+
+	revision, err := servingClient.ServingV1alpha1().Revisions(service.Namespace).Get(revisionName, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+
+	config, err := ReadAutoscalerConfig(coreClient)
+	if err != nil {
+		return err
+	}
+
+	checks := ScaleToZeroSettingsChecks(config, revision)
+
+	for _, check := range checks {
+		if !check.OK {
+			o.printChecks(checks)
+			return fmt.Errorf("Expected autoscaler settings to allow scaling to zero")
+		}
+	}
+
+	domain, url, err := o.addr(coreClient, service)
+	if err != nil {
+		return err
+	}
+
+	httpClient, err := o.depsFactory.HTTPClient()
+	if err != nil {
+		return err
+	}
+
+	timeout := o.Timeout
+	if timeout == 0 {
+		timeout = config.ScaleDownPeriod() + 2*time.Minute
+	}
+
+	o.ui.PrintLinef("Waiting up to %s for revision '%s' to scale to zero (expected after %s)",
+		timeout, revisionName, config.ScaleDownPeriod())
+
+	scaleDown, err := o.waitForZero(coreClient, service.Namespace, revisionName, timeout)
+
+	scaleDownCheck := Check{Name: "Scaled to zero", OK: err == nil}
+	if err != nil {
+		scaleDownCheck.Details = err.Error()
+	} else {
+		scaleDownCheck.Details = fmt.Sprintf("after %s", scaleDown.Round(time.Second))
+	}
+
+	checks = append(checks, scaleDownCheck)
+
+	if scaleDownCheck.OK {
+		checks = append(checks, o.wakeUp(httpClient, url, domain))
+	}
+
+	o.printChecks(checks)
+
+	var failed int
+
+	for _, check := range checks {
+		if !check.OK {
+			failed++
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("Expected scale to zero test to pass, but %d of %d checks failed", failed, len(checks))
+	}
+
+	return nil
+}
+
+func (o *ScaleToZeroOptions) waitForZero(coreClient kubernetes.Interface, namespace, revisionName string, timeout time.Duration) (time.Duration, error) {
+	startedAt := time.Now()
+	listOpts := metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("%s=%s", serving.RevisionLabelKey, revisionName),
+	}
+
+	var lastCount int
+
+	err := wait.PollImmediate(o.PollInterval, timeout, func() (bool, error) {
+		pods, err := coreClient.CoreV1().Pods(namespace).List(listOpts)
+		if err != nil {
+			return false, fmt.Errorf("Listing pods: %s", err)
+		}
+
+		lastCount = len(pods.Items)
+
+		return lastCount == 0, nil
+	})
+	if err == wait.ErrWaitTimeout {
+		return 0, fmt.Errorf("Revision still had %d pod(s) after %s", lastCount, timeout)
+	}
+
+	return time.Since(startedAt), err
+}
+
+func (o *ScaleToZeroOptions) wakeUp(httpClient *http.Client, url, domain string) Check {
+	check := Check{Name: "Woke up from zero"}
+
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		check.Details = err.Error()
+		return check
+	}
+
+	req.Host = domain
+
+	startedAt := time.Now()
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		check.Details = fmt.Sprintf("Request failed: %s", err)
+		return check
+	}
+
+	io.Copy(ioutil.Discard, resp.Body)
+	resp.Body.Close()
+
+	latency := time.Since(startedAt)
+
+	switch {
+	case resp.StatusCode >= 500:
+		check.Details = fmt.Sprintf("Request failed with status %d after %s", resp.StatusCode, latency.Round(time.Millisecond))
+	case latency > o.MaxWakeUp:
+		check.Details = fmt.Sprintf("First request took %s (maximum %s)", latency.Round(time.Millisecond), o.MaxWakeUp)
+	default:
+		check.OK = true
+		check.Details = fmt.Sprintf("First request took %s", latency.Round(time.Millisecond))
+	}
+
+	return check
+}
+
+func (o *ScaleToZeroOptions) addr(coreClient kubernetes.Interface, service *v1alpha1.Service) (string, string, error) {
+	cache, err := o.depsFactory.Cache()
+	if err != nil {
+		return "", "", err
+	}
+
+	logger, err := o.depsFactory.Logger()
+	if err != nil {
+		return "", "", err
+	}
+
+	inCluster, err := o.depsFactory.InCluster()
+	if err != nil {
+		return "", "", err
+	}
+
+	serviceAddr := ctlservice.NewServiceAddress(service, coreClient, cache, logger, inCluster)
+
+	domain, err := serviceAddr.Domain()
+	if err != nil {
+		return "", "", err
+	}
+
+	url, err := serviceAddr.URL(o.CurlFlags.Port, false)
+	if err != nil {
+		return "", "", err
+	}
+
+	return domain, url, nil
+}
+
+func (o *ScaleToZeroOptions) printChecks(checks []Check) {
+	table := uitable.Table{
+		Title:   fmt.Sprintf("Scale to zero test for service '%s'", o.ServiceFlags.Name),
+		Content: "checks",
+
+		Header: []uitable.Header{
+			uitable.NewHeader("Check"),
+			uitable.NewHeader("Details"),
+			uitable.NewHeader("OK"),
+		},
+	}
+
+	for _, check := range checks {
+		table.Rows = append(table.Rows, []uitable.Value{
+			uitable.NewValueString(check.Name),
+			uitable.NewValueString(check.Details),
+			uitable.ValueFmt{V: uitable.NewValueBool(check.OK), Error: !check.OK},
+		})
+	}
+
+	o.ui.PrintTable(table)
+}
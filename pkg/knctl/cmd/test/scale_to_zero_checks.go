@@ -0,0 +1,89 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package test
+
+import (
+	"fmt"
+	"strconv"
+
+	cmdautoscaler "github.com/cppforlife/knctl/pkg/knctl/cmd/autoscaler"
+	"github.com/knative/serving/pkg/apis/autoscaling"
+	"github.com/knative/serving/pkg/apis/serving/v1alpha1"
+)
+
+type Check struct {
+	Name    string
+	OK      bool
+	Details string
+}
+
+// ScaleToZeroSettingsChecks verifies that cluster and revision settings
+// allow revision to scale to zero and activator to buffer requests while it scales up
+func ScaleToZeroSettingsChecks(config AutoscalerConfig, revision *v1alpha1.Revision) []Check {
+	var checks []Check
+
+	checks = append(checks, Check{
+		Name:    "Scale to zero enabled",
+		OK:      config.EnableScaleToZero,
+		Details: fmt.Sprintf("enable-scale-to-zero=%t", config.EnableScaleToZero),
+	})
+
+	minScale := revision.Annotations[autoscaling.MinScaleAnnotationKey]
+	minScaleCheck := Check{Name: "Min scale allows zero", OK: true, Details: "minScale is not set"}
+
+	if len(minScale) > 0 {
+		minScaleCheck.Details = fmt.Sprintf("minScale=%s", minScale)
+		if val, err := strconv.Atoi(minScale); err != nil || val > 0 {
+			minScaleCheck.OK = false
+		}
+	}
+
+	checks = append(checks, minScaleCheck)
+
+	capacityCheck := Check{
+		Name:    "Activator capacity",
+		OK:      config.ActivatorCapacity >= 1,
+		Details: fmt.Sprintf("activator-capacity=%g", config.ActivatorCapacity),
+	}
+
+	// Activator must be able to buffer at least as many requests
+	// as a single pod accepts, otherwise requests fail while scaling from zero
+	if cc := revision.Spec.ContainerConcurrency; cc > 0 && config.ActivatorCapacity < float64(cc) {
+		capacityCheck.OK = false
+		capacityCheck.Details += fmt.Sprintf(" is lower than containerConcurrency=%d", cc)
+	}
+
+	checks = append(checks, capacityCheck)
+
+	burstCapacity := config.TargetBurstCapacity
+	burstSource := "target-burst-capacity"
+
+	if val, found := revision.Annotations[cmdautoscaler.TargetBurstCapacityAnnotationKey]; found {
+		if parsed, err := strconv.ParseFloat(val, 64); err == nil {
+			burstCapacity = parsed
+			burstSource = cmdautoscaler.TargetBurstCapacityAnnotationKey
+		}
+	}
+
+	checks = append(checks, Check{
+		Name:    "Target burst capacity",
+		OK:      burstCapacity >= -1,
+		Details: fmt.Sprintf("%s=%g", burstSource, burstCapacity),
+	})
+
+	return checks
+}
@@ -0,0 +1,59 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package test_test
+
+import (
+	"testing"
+
+	. "github.com/cppforlife/knctl/pkg/knctl/cmd"
+	. "github.com/cppforlife/knctl/pkg/knctl/cmd/test"
+	"github.com/cppforlife/knctl/pkg/knctl/testkit"
+)
+
+func TestScaleToZeroSettingsChecks_Ok(t *testing.T) {
+	revision := testkit.NewRevision("ns1", "svc1", "svc1-00001").Build()
+
+	checks := ScaleToZeroSettingsChecks(DefaultAutoscalerConfig(), revision)
+
+	DeepEqual(t, checks, []Check{
+		{Name: "Scale to zero enabled", OK: true, Details: "enable-scale-to-zero=true"},
+		{Name: "Min scale allows zero", OK: true, Details: "minScale is not set"},
+		{Name: "Activator capacity", OK: true, Details: "activator-capacity=100"},
+		{Name: "Target burst capacity", OK: true, Details: "target-burst-capacity=200"},
+	})
+}
+
+func TestScaleToZeroSettingsChecks_Inconsistent(t *testing.T) {
+	revision := testkit.NewRevision("ns1", "svc1", "svc1-00001").Annotations(map[string]string{
+		"autoscaling.knative.dev/minScale":              "1",
+		"autoscaling.knative.dev/target-burst-capacity": "-2",
+	}).Build()
+	revision.Spec.ContainerConcurrency = 10
+
+	config := DefaultAutoscalerConfig()
+	config.EnableScaleToZero = false
+	config.ActivatorCapacity = 5
+
+	checks := ScaleToZeroSettingsChecks(config, revision)
+
+	DeepEqual(t, checks, []Check{
+		{Name: "Scale to zero enabled", OK: false, Details: "enable-scale-to-zero=false"},
+		{Name: "Min scale allows zero", OK: false, Details: "minScale=1"},
+		{Name: "Activator capacity", OK: false, Details: "activator-capacity=5 is lower than containerConcurrency=10"},
+		{Name: "Target burst capacity", OK: false, Details: "autoscaling.knative.dev/target-burst-capacity=-2"},
+	})
+}
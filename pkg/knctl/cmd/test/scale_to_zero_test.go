@@ -0,0 +1,74 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package test_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cppforlife/go-cli-ui/ui"
+	. "github.com/cppforlife/knctl/pkg/knctl/cmd"
+	cmdcore "github.com/cppforlife/knctl/pkg/knctl/cmd/core"
+	cmdflags "github.com/cppforlife/knctl/pkg/knctl/cmd/flags"
+	. "github.com/cppforlife/knctl/pkg/knctl/cmd/test"
+	"github.com/cppforlife/knctl/pkg/knctl/testkit"
+)
+
+func TestNewScaleToZeroCmd_Ok(t *testing.T) {
+	realCmd := NewScaleToZeroOptions(nil, cmdcore.NewDepsFactory())
+	cmd := NewTestCmd(t, NewScaleToZeroCmd(realCmd, cmdcore.FlagsFactory{}))
+	cmd.ExpectBasicConfig()
+	cmd.Execute([]string{
+		"-n", "test-namespace",
+		"-s", "test-service",
+		"--timeout", "5m",
+		"--max-wake-up", "5s",
+	})
+	cmd.ExpectReachesExecution()
+
+	DeepEqual(t, realCmd.ServiceFlags,
+		cmdflags.ServiceFlags{cmdcore.NamespaceFlags{"test-namespace"}, "test-service"})
+	DeepEqual(t, realCmd.CurlFlags.Port, int32(80))
+	DeepEqual(t, realCmd.Timeout, 5*time.Minute)
+	DeepEqual(t, realCmd.MaxWakeUp, 5*time.Second)
+}
+
+func TestNewScaleToZeroCmd_RequiredFlags(t *testing.T) {
+	realCmd := NewScaleToZeroOptions(nil, cmdcore.NewDepsFactory())
+	cmd := NewTestCmd(t, NewScaleToZeroCmd(realCmd, cmdcore.FlagsFactory{}))
+	cmd.Execute([]string{})
+	cmd.ExpectRequiredFlags([]string{"service"})
+}
+
+func TestScaleToZeroOptions_FailsWhenSettingsPreventScaleToZero(t *testing.T) {
+	cluster := testkit.NewCluster(t,
+		testkit.NewService("ns1", "svc1").LatestRevision("svc1-00001", "svc1-00001").Build(),
+		testkit.NewRevision("ns1", "svc1", "svc1-00001").Annotations(map[string]string{
+			"autoscaling.knative.dev/minScale": "2",
+		}).Ready().Build(),
+	)
+
+	opts := NewScaleToZeroOptions(ui.NewNoopUI(), cluster.DepsFactory())
+	opts.ServiceFlags = cmdflags.ServiceFlags{cmdcore.NamespaceFlags{"ns1"}, "svc1"}
+	opts.MaxWakeUp = 5 * time.Second
+
+	err := opts.Run()
+	if err == nil || !strings.Contains(err.Error(), "Expected autoscaler settings to allow scaling to zero") {
+		t.Fatalf("Expected settings error, but was: %v", err)
+	}
+}
@@ -17,11 +17,13 @@ limitations under the License.
 package cmd
 
 import (
+	"bytes"
 	"fmt"
 	"reflect"
 	"strings"
 	"testing"
 
+	"github.com/cppforlife/go-cli-ui/ui"
 	"github.com/cppforlife/knctl/pkg/knctl/cobrautil"
 	"github.com/spf13/cobra"
 )
@@ -105,6 +107,21 @@ func (c *TestCmd) ExpectRequiredFlags(flags []string) {
 	}
 }
 
+func (c *TestCmd) ExpectErr(expectedErrMsg string) {
+	c.expectExecuteCalled()
+
+	if c.executeErr == nil {
+		c.t.Fatalf("Expected execute error")
+	}
+	if c.didReachNoopExecution {
+		c.t.Fatalf("Expected command to not reach execution")
+	}
+
+	if c.executeErr.Error() != expectedErrMsg {
+		c.t.Fatalf("Expected error ('%s'), but was '%s'", expectedErrMsg, c.executeErr)
+	}
+}
+
 func (c *TestCmd) ExpectReachesExecution() {
 	c.expectExecuteCalled()
 
@@ -121,3 +138,27 @@ func DeepEqual(t *testing.T, actual, expected interface{}) {
 		t.Fatalf("Expect obj '%#v' to equal obj '%#v'", actual, expected)
 	}
 }
+
+// ExecuteWithOutput runs real knctl command (unlike TestCmd) including
+// UI configuration via flags and returns everything it printed,
+// ending with the same status line as knctl binary
+func ExecuteWithOutput(args []string) (string, error) {
+	var out bytes.Buffer
+
+	writerUI := ui.NewWriterUI(&out, &out, ui.NewNoopLogger())
+	confUI := ui.NewWrappingConfUI(ui.NewPaddingUI(writerUI), ui.NewNoopLogger())
+
+	cmd := NewDefaultKnctlCmd(confUI)
+	cmd.SetArgs(args)
+
+	err := cmd.Execute()
+	if err != nil {
+		confUI.ErrorLinef("Error: %v", err)
+	} else {
+		confUI.PrintLinef("Succeeded")
+	}
+
+	confUI.Flush()
+
+	return out.String(), err
+}
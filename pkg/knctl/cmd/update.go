@@ -0,0 +1,177 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+
+	"github.com/cppforlife/go-cli-ui/ui"
+	cmdcore "github.com/cppforlife/knctl/pkg/knctl/cmd/core"
+	"github.com/cppforlife/knctl/pkg/knctl/selfupdate"
+	"github.com/spf13/cobra"
+)
+
+type UpdateOptions struct {
+	ui          ui.UI
+	depsFactory cmdcore.DepsFactory
+
+	Channel       string
+	Check         bool
+	Force         bool
+	PublicKeyPath string
+	SkipSignature bool
+	ReleasesURL   string
+}
+
+func NewUpdateOptions(ui ui.UI, depsFactory cmdcore.DepsFactory) *UpdateOptions {
+	return &UpdateOptions{ui: ui, depsFactory: depsFactory}
+}
+
+func NewUpdateCmd(o *UpdateOptions, flagsFactory cmdcore.FlagsFactory) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "update",
+		Short: "Update knctl to the latest release",
+		Long: `Update knctl to the latest release.
+
+Downloads binary for current platform from GitHub release, verifies it
+against release checksums and their signature, and atomically replaces
+currently running executable.
+
+Checksums signature is verified with release public key embedded into knctl
+release binaries (or given via --public-key). Builds without embedded key
+(e.g. built from source) require --public-key or --skip-signature.`,
+		Example: `
+  # Update to the latest stable release
+  knctl update
+
+  # Check if newer pre-release is available without updating
+  knctl update --channel edge --check
+
+  # Verify checksums signature with given cosign key instead of embedded one
+  knctl update --public-key ./knctl-release.pub`,
+		Annotations: map[string]string{
+			cmdcore.SystemHelpGroup.Key: cmdcore.SystemHelpGroup.Value,
+		},
+		RunE: func(_ *cobra.Command, _ []string) error { return o.Run() },
+	}
+	cmd.Flags().StringVar(&o.Channel, "channel", selfupdate.ChannelStable, "Set release channel (stable, edge)")
+	cmd.Flags().BoolVar(&o.Check, "check", false, "Only check if newer release is available")
+	cmd.Flags().BoolVar(&o.Force, "force", false, "Install latest release even if it's not newer")
+	cmd.Flags().StringVar(&o.PublicKeyPath, "public-key", os.Getenv("KNCTL_UPDATE_PUBLIC_KEY"),
+		"Set path to cosign public key used to verify release checksums instead of embedded one ($KNCTL_UPDATE_PUBLIC_KEY)")
+	cmd.Flags().BoolVar(&o.SkipSignature, "skip-signature", false, "Only verify release checksums without their signature")
+	cmd.Flags().StringVar(&o.ReleasesURL, "releases-url", selfupdate.DefaultReleasesURL, "Set GitHub releases API URL")
+	cmd.Flags().MarkHidden("releases-url")
+	return cmd
+}
+
+func (o *UpdateOptions) Run() error {
+	if o.SkipSignature && len(o.PublicKeyPath) > 0 {
+		return fmt.Errorf("Expected --public-key to not be specified together with --skip-signature")
+	}
+
+	httpClient, err := o.depsFactory.HTTPClient()
+	if err != nil {
+		return err
+	}
+
+	updater := selfupdate.NewUpdater(httpClient, o.ReleasesURL)
+
+	release, err := updater.Latest(o.Channel)
+	if err != nil {
+		return err
+	}
+
+	if !o.Force && !selfupdate.IsNewer(Version, release.Version()) {
+		o.ui.PrintLinef("knctl %s is up to date (latest %s release: %s)", Version, o.Channel, release.Version())
+		return nil
+	}
+
+	o.ui.PrintLinef("knctl %s can be updated to %s (%s channel)", Version, release.Version(), o.Channel)
+
+	if o.Check {
+		return nil
+	}
+
+	publicKeyPEM, err := o.publicKey()
+	if err != nil {
+		return err
+	}
+
+	executablePath, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("Finding current executable: %s", err)
+	}
+
+	executablePath, err = filepath.EvalSymlinks(executablePath)
+	if err != nil {
+		return fmt.Errorf("Finding current executable: %s", err)
+	}
+
+	err = o.ui.AskForConfirmation()
+	if err != nil {
+		return err
+	}
+
+	if o.SkipSignature {
+		o.ui.ErrorLinef("Warning: Verifying only checksums since --skip-signature is set")
+	}
+
+	content, err := updater.Download(release, selfupdate.AssetName(runtime.GOOS, runtime.GOARCH), publicKeyPEM)
+	if err != nil {
+		return err
+	}
+
+	err = selfupdate.Replace(executablePath, content)
+	if err != nil {
+		return err
+	}
+
+	o.ui.PrintLinef("Updated '%s' to %s", executablePath, release.Version())
+
+	return nil
+}
+
+func (o *UpdateOptions) publicKey() ([]byte, error) {
+	if o.SkipSignature {
+		return nil, nil
+	}
+
+	if len(o.PublicKeyPath) > 0 {
+		publicKeyPEM, err := ioutil.ReadFile(o.PublicKeyPath)
+		if err != nil {
+			return nil, fmt.Errorf("Reading public key: %s", err)
+		}
+		return publicKeyPEM, nil
+	}
+
+	publicKeyPEM, err := selfupdate.ReleasePublicKeyPEM()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(publicKeyPEM) == 0 {
+		return nil, fmt.Errorf("Expected release public key to be embedded into knctl or specified via --public-key " +
+			"(use --skip-signature to only verify checksums)")
+	}
+
+	return publicKeyPEM, nil
+}
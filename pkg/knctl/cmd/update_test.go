@@ -0,0 +1,66 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd_test
+
+import (
+	"testing"
+
+	. "github.com/cppforlife/knctl/pkg/knctl/cmd"
+	cmdcore "github.com/cppforlife/knctl/pkg/knctl/cmd/core"
+)
+
+func TestNewUpdateCmd_Ok(t *testing.T) {
+	realCmd := NewUpdateOptions(nil, cmdcore.NewDepsFactory())
+	cmd := NewTestCmd(t, NewUpdateCmd(realCmd, cmdcore.FlagsFactory{}))
+	cmd.ExpectBasicConfig()
+	cmd.Execute([]string{
+		"--channel", "edge",
+		"--check",
+		"--force",
+		"--public-key", "test-key.pub",
+	})
+	cmd.ExpectReachesExecution()
+
+	DeepEqual(t, realCmd.Channel, "edge")
+	DeepEqual(t, realCmd.Check, true)
+	DeepEqual(t, realCmd.Force, true)
+	DeepEqual(t, realCmd.PublicKeyPath, "test-key.pub")
+}
+
+func TestNewUpdateCmd_OkMinimum(t *testing.T) {
+	realCmd := NewUpdateOptions(nil, cmdcore.NewDepsFactory())
+	cmd := NewTestCmd(t, NewUpdateCmd(realCmd, cmdcore.FlagsFactory{}))
+	cmd.Execute([]string{})
+	cmd.ExpectReachesExecution()
+
+	DeepEqual(t, realCmd.Channel, "stable")
+	DeepEqual(t, realCmd.Check, false)
+	DeepEqual(t, realCmd.Force, false)
+	DeepEqual(t, realCmd.SkipSignature, false)
+	DeepEqual(t, realCmd.ReleasesURL, "https://api.github.com/repos/cppforlife/knctl/releases")
+}
+
+func TestUpdateOptions_SkipSignatureWithPublicKey(t *testing.T) {
+	realCmd := NewUpdateOptions(nil, cmdcore.NewDepsFactory())
+	realCmd.PublicKeyPath = "test-key.pub"
+	realCmd.SkipSignature = true
+
+	err := realCmd.Run()
+	if err == nil || err.Error() != "Expected --public-key to not be specified together with --skip-signature" {
+		t.Fatalf("Expected conflicting flags error, but was: %v", err)
+	}
+}
@@ -18,38 +18,243 @@ package cmd
 
 import (
 	"fmt"
-	cmdcore "github.com/cppforlife/knctl/pkg/knctl/cmd/core"
+	"sort"
+	"strings"
 
 	"github.com/cppforlife/go-cli-ui/ui"
+	uitable "github.com/cppforlife/go-cli-ui/ui/table"
+	cmdcore "github.com/cppforlife/knctl/pkg/knctl/cmd/core"
 	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 const (
 	Version = "0.3.0"
+
+	knativeGroupSuffix         = ".knative.dev"
+	knativeInternalGroupSuffix = ".internal.knative.dev"
+)
+
+var (
+	// SupportedAPIVersions lists Knative API group versions
+	// that this version of knctl is compiled against
+	SupportedAPIVersions = map[string][]string{
+		"serving.knative.dev": []string{"v1alpha1"},
+		"build.knative.dev":   []string{"v1alpha1"},
+	}
 )
 
 type VersionOptions struct {
-	ui ui.UI
+	ui          ui.UI
+	depsFactory cmdcore.DepsFactory
+
+	Check bool
 }
 
-func NewVersionOptions(ui ui.UI) *VersionOptions {
-	return &VersionOptions{ui}
+func NewVersionOptions(ui ui.UI, depsFactory cmdcore.DepsFactory) *VersionOptions {
+	return &VersionOptions{ui: ui, depsFactory: depsFactory}
 }
 
 func NewVersionCmd(o *VersionOptions, flagsFactory cmdcore.FlagsFactory) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "version",
 		Short: "Print client version",
+		Long: `Print client version.
+
+With --check flag, compares Knative API versions served by the cluster
+with API versions supported by this client and suggests upgrades.`,
+		Example: `
+  # Print client version
+  knctl version
+
+  # Check for version skew between client and cluster
+  knctl version --check`,
 		Annotations: map[string]string{
 			cmdcore.SystemHelpGroup.Key: cmdcore.SystemHelpGroup.Value,
 		},
 		RunE: func(_ *cobra.Command, _ []string) error { return o.Run() },
 	}
+	cmd.Flags().BoolVar(&o.Check, "check", false, "Check for version skew with the cluster")
 	return cmd
 }
 
 func (o *VersionOptions) Run() error {
 	o.ui.PrintBlock([]byte(fmt.Sprintf("Client Version: %s\n", Version)))
 
+	if !o.Check {
+		return nil
+	}
+
+	coreClient, err := o.depsFactory.CoreClient()
+	if err != nil {
+		return err
+	}
+
+	serverVersion, err := coreClient.Discovery().ServerVersion()
+	if err != nil {
+		return fmt.Errorf("Getting server version: %s", err)
+	}
+
+	o.ui.PrintBlock([]byte(fmt.Sprintf("Server Version: %s\n", serverVersion.GitVersion)))
+
+	groups, err := coreClient.Discovery().ServerGroups()
+	if err != nil {
+		return fmt.Errorf("Getting server groups: %s", err)
+	}
+
+	skews := NewVersionSkews(groups.Groups)
+
+	o.printSkews(skews)
+
+	var incompatible []string
+
+	for _, skew := range skews {
+		for _, warning := range skew.Warnings() {
+			o.ui.PrintLinef("Warning: %s", warning)
+		}
+		if !skew.Compatible() {
+			incompatible = append(incompatible, skew.Group)
+		}
+	}
+
+	if len(incompatible) > 0 {
+		return fmt.Errorf("Expected client to support at least one served version of API groups: %s",
+			strings.Join(incompatible, ", "))
+	}
+
 	return nil
 }
+
+func (o *VersionOptions) printSkews(skews []VersionSkew) {
+	table := uitable.Table{
+		Title:   "Knative API versions",
+		Content: "API groups",
+
+		Header: []uitable.Header{
+			uitable.NewHeader("Group"),
+			uitable.NewHeader("Served"),
+			uitable.NewHeader("Preferred"),
+			uitable.NewHeader("Supported by client"),
+			uitable.NewHeader("Compatible"),
+		},
+
+		SortBy: []uitable.ColumnSort{
+			{Column: 0, Asc: true},
+		},
+	}
+
+	for _, skew := range skews {
+		table.Rows = append(table.Rows, []uitable.Value{
+			uitable.NewValueString(skew.Group),
+			uitable.NewValueStrings(skew.Served),
+			uitable.NewValueString(skew.Preferred),
+			uitable.NewValueStrings(skew.Supported),
+			uitable.ValueFmt{
+				V:     uitable.NewValueBool(skew.Compatible()),
+				Error: !skew.Compatible(),
+			},
+		})
+	}
+
+	o.ui.PrintTable(table)
+}
+
+// VersionSkew describes differences between API versions served
+// by the cluster and versions supported by the client for a single group
+type VersionSkew struct {
+	Group     string
+	Served    []string
+	Preferred string
+	Supported []string
+}
+
+func NewVersionSkews(groups []metav1.APIGroup) []VersionSkew {
+	var skews []VersionSkew
+
+	seenGroups := map[string]struct{}{}
+
+	for _, group := range groups {
+		// Internal groups are not used directly by the client
+		if !strings.HasSuffix(group.Name, knativeGroupSuffix) || strings.HasSuffix(group.Name, knativeInternalGroupSuffix) {
+			continue
+		}
+
+		skew := VersionSkew{
+			Group:     group.Name,
+			Preferred: group.PreferredVersion.Version,
+			Supported: SupportedAPIVersions[group.Name],
+		}
+
+		for _, ver := range group.Versions {
+			skew.Served = append(skew.Served, ver.Version)
+		}
+
+		seenGroups[group.Name] = struct{}{}
+		skews = append(skews, skew)
+	}
+
+	for name, versions := range SupportedAPIVersions {
+		if _, found := seenGroups[name]; !found {
+			skews = append(skews, VersionSkew{Group: name, Supported: versions})
+		}
+	}
+
+	sort.Slice(skews, func(i, j int) bool { return skews[i].Group < skews[j].Group })
+
+	return skews
+}
+
+func (s VersionSkew) Installed() bool { return len(s.Served) > 0 }
+
+// Compatible is true when client can talk to at least one served version
+// (groups unknown to the client or not installed are not considered incompatible)
+func (s VersionSkew) Compatible() bool {
+	if !s.Installed() || len(s.Supported) == 0 {
+		return true
+	}
+	return len(s.commonVersions()) > 0
+}
+
+func (s VersionSkew) Warnings() []string {
+	switch {
+	case !s.Installed():
+		return []string{fmt.Sprintf("API group '%s' is not served by the cluster; "+
+			"install it via 'knctl install'", s.Group)}
+
+	case len(s.Supported) == 0:
+		return []string{fmt.Sprintf("API group '%s' is served by the cluster but is not known to the client; "+
+			"upgrade knctl to manage its resources", s.Group)}
+
+	case !s.Compatible():
+		return []string{fmt.Sprintf("API group '%s' is served at versions %s but the client only supports %s; "+
+			"upgrade knctl to a release supporting version '%s' (or install an older Knative release)",
+			s.Group, strings.Join(s.Served, ", "), strings.Join(s.Supported, ", "), s.Preferred)}
+
+	case !s.supports(s.Preferred):
+		return []string{fmt.Sprintf("API group '%s' prefers version '%s' which is not supported by the client; "+
+			"client will continue using '%s' but should be upgraded before that version is removed",
+			s.Group, s.Preferred, s.commonVersions()[0])}
+
+	default:
+		return nil
+	}
+}
+
+func (s VersionSkew) commonVersions() []string {
+	var common []string
+	for _, ver := range s.Served {
+		if s.supports(ver) {
+			common = append(common, ver)
+		}
+	}
+	return common
+}
+
+func (s VersionSkew) supports(version string) bool {
+	for _, ver := range s.Supported {
+		if ver == version {
+			return true
+		}
+	}
+	return false
+}
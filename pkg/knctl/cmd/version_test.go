@@ -17,16 +17,84 @@ limitations under the License.
 package cmd_test
 
 import (
-	cmdcore "github.com/cppforlife/knctl/pkg/knctl/cmd/core"
+	"reflect"
 	"testing"
 
+	cmdcore "github.com/cppforlife/knctl/pkg/knctl/cmd/core"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
 	. "github.com/cppforlife/knctl/pkg/knctl/cmd"
 )
 
+func TestNewVersionCmd_Ok(t *testing.T) {
+	realCmd := NewVersionOptions(nil, cmdcore.NewDepsFactory())
+	cmd := NewTestCmd(t, NewVersionCmd(realCmd, cmdcore.FlagsFactory{}))
+	cmd.Execute([]string{"--check"})
+	cmd.ExpectReachesExecution()
+
+	DeepEqual(t, realCmd.Check, true)
+}
+
 func TestNewVersionCmd_OkMinimum(t *testing.T) {
-	realCmd := NewVersionOptions(nil)
+	realCmd := NewVersionOptions(nil, cmdcore.NewDepsFactory())
 	cmd := NewTestCmd(t, NewVersionCmd(realCmd, cmdcore.FlagsFactory{}))
 	cmd.ExpectBasicConfig()
 	cmd.Execute([]string{})
 	cmd.ExpectReachesExecution()
+
+	DeepEqual(t, realCmd.Check, false)
+}
+
+func TestNewVersionSkews(t *testing.T) {
+	group := func(name string, preferred string, versions ...string) metav1.APIGroup {
+		g := metav1.APIGroup{Name: name, PreferredVersion: metav1.GroupVersionForDiscovery{Version: preferred}}
+		for _, ver := range versions {
+			g.Versions = append(g.Versions, metav1.GroupVersionForDiscovery{Version: ver})
+		}
+		return g
+	}
+
+	skews := NewVersionSkews([]metav1.APIGroup{
+		group("apps", "v1", "v1"),
+		group("serving.knative.dev", "v1", "v1", "v1alpha1"),
+		group("networking.internal.knative.dev", "v1alpha1", "v1alpha1"),
+		group("eventing.knative.dev", "v1", "v1"),
+	})
+
+	var groups []string
+	for _, skew := range skews {
+		groups = append(groups, skew.Group)
+	}
+
+	if !reflect.DeepEqual(groups, []string{"build.knative.dev", "eventing.knative.dev", "serving.knative.dev"}) {
+		t.Fatalf("Expected only public Knative groups, but was %#v", groups)
+	}
+
+	// Not installed
+	if skews[0].Installed() || !skews[0].Compatible() || len(skews[0].Warnings()) != 1 {
+		t.Fatalf("Expected build group to be reported as not installed: %#v", skews[0])
+	}
+
+	// Unknown to client
+	if !skews[1].Compatible() || len(skews[1].Warnings()) != 1 {
+		t.Fatalf("Expected eventing group to be reported as unknown: %#v", skews[1])
+	}
+
+	// Preferred version is newer than supported
+	if !skews[2].Compatible() || len(skews[2].Warnings()) != 1 {
+		t.Fatalf("Expected serving group to be compatible with a warning: %#v", skews[2])
+	}
+
+	skews = NewVersionSkews([]metav1.APIGroup{group("serving.knative.dev", "v1", "v1")})
+
+	if skews[1].Group != "serving.knative.dev" || skews[1].Compatible() {
+		t.Fatalf("Expected serving group to be incompatible: %#v", skews[1])
+	}
+}
+
+func TestVersionCmd_DoesNotLoadKubeconfig(t *testing.T) {
+	out, err := ExecuteWithOutput([]string{"version", "--kubeconfig", "/non-existent/kubeconfig", "--tty"})
+	if err != nil {
+		t.Fatalf("Expected version to not need kubeconfig: %s\n%s", err, out)
+	}
 }
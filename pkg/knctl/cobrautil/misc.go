@@ -17,6 +17,8 @@ limitations under the License.
 package cobrautil
 
 import (
+	"time"
+
 	"github.com/spf13/cobra"
 )
 
@@ -39,3 +41,41 @@ func WrapRunEForCmd(additionalRunE func(*cobra.Command, []string) error) func(cm
 		}
 	}
 }
+
+// DeferForCmd calls deferredFunc after command finishes, even if it fails
+func DeferForCmd(deferredFunc func()) func(cmd *cobra.Command) {
+	return func(cmd *cobra.Command) {
+		origRunE := cmd.RunE
+		cmd.RunE = func(cmd2 *cobra.Command, args []string) error {
+			defer deferredFunc()
+			return origRunE(cmd2, args)
+		}
+	}
+}
+
+// ObserveForCmd calls observeFunc with duration and result of command after it finishes
+func ObserveForCmd(observeFunc func(*cobra.Command, time.Duration, error)) func(cmd *cobra.Command) {
+	return func(cmd *cobra.Command) {
+		origRunE := cmd.RunE
+		cmd.RunE = func(cmd2 *cobra.Command, args []string) error {
+			startTime := time.Now()
+			err := origRunE(cmd2, args)
+			observeFunc(cmd2, time.Since(startTime), err)
+			return err
+		}
+	}
+}
+
+// MapErrorForCmd replaces error returned by command (if any) with result of mapFunc
+func MapErrorForCmd(mapFunc func(*cobra.Command, error) error) func(cmd *cobra.Command) {
+	return func(cmd *cobra.Command) {
+		origRunE := cmd.RunE
+		cmd.RunE = func(cmd2 *cobra.Command, args []string) error {
+			err := origRunE(cmd2, args)
+			if err != nil {
+				return mapFunc(cmd2, err)
+			}
+			return nil
+		}
+	}
+}
@@ -0,0 +1,133 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gitmeta
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+const (
+	CommitAnnotationKey = "git.cli.knative.dev/commit"
+	BranchAnnotationKey = "git.cli.knative.dev/branch"
+	DirtyAnnotationKey  = "git.cli.knative.dev/dirty"
+	AuthorAnnotationKey = "git.cli.knative.dev/author"
+
+	shortCommitLen = 7
+)
+
+// Metadata describes state of git working tree that revision was deployed from
+type Metadata struct {
+	Commit string
+	Branch string // empty when HEAD is detached
+	Dirty  bool
+	Author string // author of the commit
+}
+
+// Detect returns metadata of git repository containing given directory.
+// Lack of git binary or repository is not an error (false is returned).
+func Detect(dir string) (Metadata, bool, error) {
+	out, err := gitOutput(dir, "rev-parse", "--is-inside-work-tree")
+	if err != nil || out != "true" {
+		return Metadata{}, false, nil
+	}
+
+	var meta Metadata
+
+	meta.Commit, err = gitOutput(dir, "rev-parse", "HEAD")
+	if err != nil {
+		// Repository without commits
+		return Metadata{}, false, nil
+	}
+
+	meta.Branch, err = gitOutput(dir, "rev-parse", "--abbrev-ref", "HEAD")
+	if err != nil {
+		return Metadata{}, false, err
+	}
+
+	if meta.Branch == "HEAD" {
+		meta.Branch = ""
+	}
+
+	status, err := gitOutput(dir, "status", "--porcelain")
+	if err != nil {
+		return Metadata{}, false, err
+	}
+
+	meta.Dirty = len(status) > 0
+
+	meta.Author, err = gitOutput(dir, "log", "-1", "--format=%an <%ae>")
+	if err != nil {
+		return Metadata{}, false, err
+	}
+
+	return meta, true, nil
+}
+
+// FromAnnotations returns metadata stamped onto revision (false if there is none)
+func FromAnnotations(anns map[string]string) (Metadata, bool) {
+	commit, found := anns[CommitAnnotationKey]
+	if !found {
+		return Metadata{}, false
+	}
+
+	return Metadata{
+		Commit: commit,
+		Branch: anns[BranchAnnotationKey],
+		Dirty:  anns[DirtyAnnotationKey] == "true",
+		Author: anns[AuthorAnnotationKey],
+	}, true
+}
+
+func (m Metadata) Annotations() map[string]string {
+	return map[string]string{
+		CommitAnnotationKey: m.Commit,
+		BranchAnnotationKey: m.Branch,
+		DirtyAnnotationKey:  fmt.Sprintf("%t", m.Dirty),
+		AuthorAnnotationKey: m.Author,
+	}
+}
+
+// ShortCommit returns abbreviated commit with '-dirty' suffix
+// when working tree had uncommitted changes
+func (m Metadata) ShortCommit() string {
+	commit := m.Commit
+	if len(commit) > shortCommitLen {
+		commit = commit[:shortCommitLen]
+	}
+	if m.Dirty {
+		commit += "-dirty"
+	}
+	return commit
+}
+
+func gitOutput(dir string, args ...string) (string, error) {
+	var stdout, stderr bytes.Buffer
+
+	cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	err := cmd.Run()
+	if err != nil {
+		return "", fmt.Errorf("Running git %s: %s (stderr: %s)", strings.Join(args, " "), err, strings.TrimSpace(stderr.String()))
+	}
+
+	return strings.TrimSpace(stdout.String()), nil
+}
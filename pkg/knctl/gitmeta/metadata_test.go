@@ -0,0 +1,105 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gitmeta_test
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/cppforlife/knctl/pkg/knctl/gitmeta"
+)
+
+func TestDetect(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("Skipping since git is not installed")
+	}
+
+	dir, err := ioutil.TempDir("", "knctl-gitmeta")
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	defer os.RemoveAll(dir)
+
+	_, found, err := gitmeta.Detect(dir)
+	if err != nil || found {
+		t.Fatalf("Expected metadata to not be found outside of repository: %v", err)
+	}
+
+	git := func(args ...string) {
+		cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("Expected git %v to succeed: %s (output: %s)", args, err, out)
+		}
+	}
+
+	git("init", "-q")
+	git("checkout", "-q", "-b", "feature1")
+	git("config", "user.name", "Author1")
+	git("config", "user.email", "author1@example.com")
+
+	err = ioutil.WriteFile(filepath.Join(dir, "file"), []byte("content"), 0600)
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	git("add", "file")
+	git("commit", "-q", "-m", "msg")
+
+	meta, found, err := gitmeta.Detect(dir)
+	if err != nil || !found {
+		t.Fatalf("Expected metadata to be found: %v", err)
+	}
+
+	if len(meta.Commit) != 40 || meta.Branch != "feature1" || meta.Dirty || meta.Author != "Author1 <author1@example.com>" {
+		t.Fatalf("Expected metadata to match: %#v", meta)
+	}
+
+	err = ioutil.WriteFile(filepath.Join(dir, "file"), []byte("changed"), 0600)
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	git("checkout", "-q", "--detach")
+
+	meta, _, err = gitmeta.Detect(dir)
+	if err != nil || !meta.Dirty || meta.Branch != "" {
+		t.Fatalf("Expected dirty detached metadata: %#v %v", meta, err)
+	}
+}
+
+func TestAnnotationsRoundTrip(t *testing.T) {
+	meta := gitmeta.Metadata{Commit: "0123456789abcdef", Branch: "main", Dirty: true, Author: "a <a@b>"}
+
+	parsedMeta, found := gitmeta.FromAnnotations(meta.Annotations())
+	if !found || !reflect.DeepEqual(parsedMeta, meta) {
+		t.Fatalf("Expected metadata to round trip: %#v", parsedMeta)
+	}
+
+	if meta.ShortCommit() != "0123456-dirty" {
+		t.Fatalf("Expected short commit to match: %s", meta.ShortCommit())
+	}
+
+	_, found = gitmeta.FromAnnotations(map[string]string{"other": "val"})
+	if found {
+		t.Fatalf("Expected metadata to not be found")
+	}
+}
@@ -0,0 +1,133 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package history
+
+import (
+	"os"
+	"os/user"
+	"strings"
+	"time"
+
+	"github.com/knative/serving/pkg/apis/serving/v1alpha1"
+)
+
+type Operation string
+
+const (
+	OperationDeploy  Operation = "deploy"
+	OperationRollout Operation = "rollout"
+	OperationUndo    Operation = "undo"
+	OperationApprove Operation = "approve"
+
+	redactedValue = "<redacted>"
+)
+
+var (
+	// Flags which values may contain sensitive information
+	redactedFlags = map[string]func(string) string{
+		"-e":    redactEnvValue,
+		"--env": redactEnvValue,
+
+		// Notification URLs (e.g. Slack webhooks) embed secrets
+		"--notify-url":           redactValue,
+		"--notify-slack-webhook": redactValue,
+	}
+)
+
+type Entry struct {
+	Time      time.Time `json:"time"`
+	User      string    `json:"user"`
+	Operation Operation `json:"operation"`
+	Service   string    `json:"service"`
+
+	// Deploy specific details
+	Revision         string `json:"revision,omitempty"`
+	PreviousRevision string `json:"previousRevision,omitempty"`
+	Image            string `json:"image,omitempty"`
+	ImageDigest      string `json:"imageDigest,omitempty"`
+
+	// Rollout specific details
+	Route           string                   `json:"route,omitempty"`
+	Traffic         []v1alpha1.TrafficTarget `json:"traffic,omitempty"`
+	PreviousTraffic []v1alpha1.TrafficTarget `json:"previousTraffic,omitempty"`
+
+	// Args are command line arguments with env variable values
+	// and notification URLs redacted
+	Args []string `json:"args,omitempty"`
+}
+
+// NewEntry returns entry for current user and command line invocation
+func NewEntry(op Operation, serviceName string) Entry {
+	return Entry{
+		Time:      time.Now().UTC(),
+		User:      currentUser(),
+		Operation: op,
+		Service:   serviceName,
+		Args:      RedactArgs(os.Args[1:]),
+	}
+}
+
+// RedactArgs hides values of env variables (e.g. '-e KEY=val' becomes '-e KEY=<redacted>')
+// and notification URLs (e.g. '--notify-url=https://...' becomes '--notify-url=<redacted>')
+func RedactArgs(args []string) []string {
+	var result []string
+	var redactNext func(string) string
+
+	for _, arg := range args {
+		if redactNext != nil {
+			result = append(result, redactNext(arg))
+			redactNext = nil
+			continue
+		}
+
+		if redactFunc, found := redactedFlags[arg]; found {
+			redactNext = redactFunc
+			result = append(result, arg)
+			continue
+		}
+
+		if pieces := strings.SplitN(arg, "=", 2); len(pieces) == 2 && strings.HasPrefix(arg, "--") {
+			if redactFunc, found := redactedFlags[pieces[0]]; found {
+				arg = pieces[0] + "=" + redactFunc(pieces[1])
+			}
+		} else if strings.HasPrefix(arg, "-e") && !strings.HasPrefix(arg, "--") && len(arg) > len("-e") {
+			arg = "-e" + redactEnvValue(strings.TrimPrefix(strings.TrimPrefix(arg, "-e"), "="))
+		}
+
+		result = append(result, arg)
+	}
+
+	return result
+}
+
+func redactValue(string) string { return redactedValue }
+
+func redactEnvValue(val string) string {
+	pieces := strings.SplitN(val, "=", 2)
+	if len(pieces) != 2 {
+		return val
+	}
+	return pieces[0] + "=" + redactedValue
+}
+
+func currentUser() string {
+	u, err := user.Current()
+	if err != nil || len(u.Username) == 0 {
+		return "unknown"
+	}
+	return u.Username
+}
@@ -0,0 +1,169 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package history
+
+import (
+	"encoding/json"
+	"fmt"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/util/retry"
+)
+
+const (
+	configMapNamePrefix = "knctl-history-"
+	configMapEntriesKey = "entries"
+
+	// ServiceLabelKey labels history config maps with service name
+	ServiceLabelKey = "cli.knative.dev/history-service"
+
+	// MaxEntries limits number of entries kept per service,
+	// oldest entries are dropped first
+	MaxEntries = 100
+)
+
+// History stores operations performed by knctl against a service
+// in a config map next to that service, so that it's shared by all users
+type History struct {
+	coreClient kubernetes.Interface
+}
+
+func NewHistory(coreClient kubernetes.Interface) History {
+	return History{coreClient}
+}
+
+// List returns service's entries, oldest first
+func (h History) List(namespace, serviceName string) ([]Entry, error) {
+	configMap, err := h.coreClient.CoreV1().ConfigMaps(namespace).Get(h.configMapName(serviceName), metav1.GetOptions{})
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("Getting history: %s", err)
+	}
+
+	return h.entries(configMap)
+}
+
+func (h History) Last(namespace, serviceName string) (Entry, bool, error) {
+	entries, err := h.List(namespace, serviceName)
+	if err != nil {
+		return Entry{}, false, err
+	}
+
+	if len(entries) == 0 {
+		return Entry{}, false, nil
+	}
+
+	return entries[len(entries)-1], true, nil
+}
+
+func (h History) Record(namespace string, entry Entry) error {
+	configMaps := h.coreClient.CoreV1().ConfigMaps(namespace)
+
+	// Update relies on resource version to avoid losing concurrently recorded entries
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		configMap, err := configMaps.Get(h.configMapName(entry.Service), metav1.GetOptions{})
+		if err != nil {
+			if !errors.IsNotFound(err) {
+				return fmt.Errorf("Getting history: %s", err)
+			}
+
+			configMap = &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      h.configMapName(entry.Service),
+					Namespace: namespace,
+					Labels:    map[string]string{ServiceLabelKey: entry.Service},
+				},
+			}
+
+			err = h.append(configMap, entry)
+			if err != nil {
+				return err
+			}
+
+			_, err = configMaps.Create(configMap)
+			if errors.IsAlreadyExists(err) {
+				// Retry as an update since someone else recorded first
+				return errors.NewConflict(corev1.Resource("configmaps"), configMap.Name, err)
+			}
+
+			return err
+		}
+
+		err = h.append(configMap, entry)
+		if err != nil {
+			return err
+		}
+
+		_, err = configMaps.Update(configMap)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("Recording history: %s", err)
+	}
+
+	return nil
+}
+
+func (h History) append(configMap *corev1.ConfigMap, entry Entry) error {
+	entries, err := h.entries(configMap)
+	if err != nil {
+		return err
+	}
+
+	entries = append(entries, entry)
+
+	if len(entries) > MaxEntries {
+		entries = entries[len(entries)-MaxEntries:]
+	}
+
+	entriesBytes, err := json.Marshal(entries)
+	if err != nil {
+		return fmt.Errorf("Marshaling history: %s", err)
+	}
+
+	if configMap.Data == nil {
+		configMap.Data = map[string]string{}
+	}
+
+	configMap.Data[configMapEntriesKey] = string(entriesBytes)
+
+	return nil
+}
+
+func (h History) entries(configMap *corev1.ConfigMap) ([]Entry, error) {
+	var entries []Entry
+
+	data := configMap.Data[configMapEntriesKey]
+	if len(data) == 0 {
+		return nil, nil
+	}
+
+	err := json.Unmarshal([]byte(data), &entries)
+	if err != nil {
+		return nil, fmt.Errorf("Unmarshaling history: %s", err)
+	}
+
+	return entries, nil
+}
+
+func (h History) configMapName(serviceName string) string {
+	return configMapNamePrefix + serviceName
+}
@@ -0,0 +1,128 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package history_test
+
+import (
+	"reflect"
+	"strconv"
+	"testing"
+
+	. "github.com/cppforlife/knctl/pkg/knctl/history"
+	"github.com/cppforlife/knctl/pkg/knctl/testkit"
+)
+
+func TestHistory_RecordList(t *testing.T) {
+	cluster := testkit.NewCluster(t)
+	history := NewHistory(cluster.CoreClient())
+
+	entries, err := history.List("ns1", "svc1")
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	if len(entries) != 0 {
+		t.Fatalf("Expected no entries, but was: %#v", entries)
+	}
+
+	for _, rev := range []string{"svc1-00001", "svc1-00002"} {
+		err := history.Record("ns1", Entry{Operation: OperationDeploy, Service: "svc1", Revision: rev})
+		if err != nil {
+			t.Fatalf("Expected no error: %s", err)
+		}
+	}
+
+	err = history.Record("ns1", Entry{Operation: OperationDeploy, Service: "svc2", Revision: "svc2-00001"})
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	entries, err = history.List("ns1", "svc1")
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	var revisions []string
+
+	for _, entry := range entries {
+		revisions = append(revisions, entry.Revision)
+	}
+
+	if !reflect.DeepEqual(revisions, []string{"svc1-00001", "svc1-00002"}) {
+		t.Fatalf("Expected entries in recorded order, but was: %#v", revisions)
+	}
+
+	last, found, err := history.Last("ns1", "svc1")
+	if err != nil || !found || last.Revision != "svc1-00002" {
+		t.Fatalf("Expected last entry to be found: %#v %t %v", last, found, err)
+	}
+}
+
+func TestHistory_RecordDropsOldestEntries(t *testing.T) {
+	history := NewHistory(testkit.NewCluster(t).CoreClient())
+
+	for i := 0; i < MaxEntries+5; i++ {
+		err := history.Record("ns1", Entry{Operation: OperationRollout, Service: "svc1", Route: strconv.Itoa(i)})
+		if err != nil {
+			t.Fatalf("Expected no error: %s", err)
+		}
+	}
+
+	entries, err := history.List("ns1", "svc1")
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	if len(entries) != MaxEntries || entries[0].Route != "5" {
+		t.Fatalf("Expected oldest entries to be dropped, but was: %d entries starting with %#v", len(entries), entries[0])
+	}
+}
+
+func TestRedactArgs(t *testing.T) {
+	args := RedactArgs([]string{
+		"deploy", "-s", "svc1", "-e", "KEY1=val1", "--env", "KEY2=val=2",
+		"--env=KEY3=val3", "-eKEY4=val4", "-e=KEY5=val5", "--image", "img",
+	})
+
+	expectedArgs := []string{
+		"deploy", "-s", "svc1", "-e", "KEY1=<redacted>", "--env", "KEY2=<redacted>",
+		"--env=KEY3=<redacted>", "-eKEY4=<redacted>", "-eKEY5=<redacted>", "--image", "img",
+	}
+
+	if !reflect.DeepEqual(args, expectedArgs) {
+		t.Fatalf("Expected args to be redacted, but was: %#v", args)
+	}
+}
+
+func TestRedactArgsNotificationURLs(t *testing.T) {
+	args := RedactArgs([]string{
+		"deploy", "-s", "svc1", "--notify-slack-webhook", "https://hooks.slack.com/services/T0/B0/secret",
+		"--notify-slack-webhook=https://hooks.slack.com/services/T1/B1/secret",
+		"--notify-url", "https://example.com/hook?token=secret", "--notify-url=https://example.com/hook?token=secret",
+		"--image", "img",
+	})
+
+	expectedArgs := []string{
+		"deploy", "-s", "svc1", "--notify-slack-webhook", "<redacted>",
+		"--notify-slack-webhook=<redacted>",
+		"--notify-url", "<redacted>", "--notify-url=<redacted>",
+		"--image", "img",
+	}
+
+	if !reflect.DeepEqual(args, expectedArgs) {
+		t.Fatalf("Expected notification URLs to be redacted, but was: %#v", args)
+	}
+}
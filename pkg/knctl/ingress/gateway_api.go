@@ -0,0 +1,128 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingress
+
+import (
+	"fmt"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+)
+
+type GatewayAPI struct{}
+
+func NewGatewayAPI() GatewayAPI {
+	return GatewayAPI{}
+}
+
+func (g GatewayAPI) IngressClass() string { return "gateway-api.ingress.networking.knative.dev" }
+
+// GatewayResources lists supported Gateway API versions, newest first
+func (g GatewayAPI) GatewayResources() []schema.GroupVersionResource {
+	return []schema.GroupVersionResource{
+		{Group: "gateway.networking.k8s.io", Version: "v1", Resource: "gateways"},
+		{Group: "gateway.networking.k8s.io", Version: "v1beta1", Resource: "gateways"},
+	}
+}
+
+// IngressServiceGateway exposes Gateway API gateway (used by net-gateway-api) as an ingress
+type IngressServiceGateway struct {
+	unstructured.Unstructured
+}
+
+var _ IngressService = IngressServiceGateway{}
+
+// listGateways returns gateways across all namespaces; empty when Gateway API is not installed
+func listGateways(dynamicClient dynamic.Interface) ([]IngressService, error) {
+	for _, gvr := range NewGatewayAPI().GatewayResources() {
+		list, err := dynamicClient.Resource(gvr).List(metav1.ListOptions{})
+		if err != nil {
+			if errors.IsNotFound(err) {
+				continue
+			}
+			return nil, fmt.Errorf("Listing gateways: %s", err)
+		}
+
+		var ingSvcs []IngressService
+
+		for _, item := range list.Items {
+			ingSvcs = append(ingSvcs, IngressServiceGateway{item})
+		}
+
+		return ingSvcs, nil
+	}
+
+	return nil, nil
+}
+
+func (g IngressServiceGateway) Name() string { return g.GetName() }
+
+func (g IngressServiceGateway) CreationTime() time.Time {
+	return g.GetCreationTimestamp().Time
+}
+
+func (g IngressServiceGateway) Addresses() []string {
+	addrs := []string{}
+
+	for _, addr := range g.nestedMaps("status", "addresses") {
+		if val, ok := addr["value"].(string); ok && len(val) > 0 {
+			addrs = append(addrs, val)
+		}
+	}
+
+	return addrs
+}
+
+func (g IngressServiceGateway) Ports() []int32 {
+	ports := []int32{}
+
+	for _, listener := range g.nestedMaps("spec", "listeners") {
+		if port, ok := listener["port"].(int64); ok {
+			ports = append(ports, int32(port))
+		}
+	}
+
+	return ports
+}
+
+// MappedPort returns the same port since gateway listeners are exposed directly
+func (g IngressServiceGateway) MappedPort(port int32) int32 {
+	for _, p := range g.Ports() {
+		if p == port {
+			return port
+		}
+	}
+	return 0
+}
+
+func (g IngressServiceGateway) nestedMaps(fields ...string) []map[string]interface{} {
+	items, _, _ := unstructured.NestedSlice(g.Object, fields...)
+
+	var result []map[string]interface{}
+
+	for _, item := range items {
+		if typedItem, ok := item.(map[string]interface{}); ok {
+			result = append(result, typedItem)
+		}
+	}
+
+	return result
+}
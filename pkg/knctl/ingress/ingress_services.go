@@ -21,16 +21,24 @@ import (
 	"net"
 	"os/exec"
 	"strings"
+	"sync"
 	"time"
 
+	"github.com/cppforlife/knctl/pkg/knctl/cache"
+	"github.com/cppforlife/knctl/pkg/knctl/logger"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 )
 
 type IngressServices struct {
-	coreClient kubernetes.Interface
+	coreClient    kubernetes.Interface
+	dynamicClient dynamic.Interface
+	cache         cache.Cache
+	logger        logger.Logger
+	inCluster     bool
 }
 
 type IngressService interface {
@@ -48,39 +56,93 @@ type IngressServiceLoadBalancer struct {
 var _ IngressService = IngressServiceLoadBalancer{}
 
 type IngressServiceNodePort struct {
-	coreClient kubernetes.Interface
+	nodeAddrs *nodeAddresses
 	corev1.Service
 }
 
 var _ IngressService = IngressServiceNodePort{}
 
 func NewIngressServices(coreClient kubernetes.Interface) IngressServices {
-	return IngressServices{coreClient}
+	return IngressServices{coreClient: coreClient}
+}
+
+// WithCache returns ingress services that remember preferred addresses
+func (s IngressServices) WithCache(c cache.Cache) IngressServices {
+	s.cache = c
+	return s
+}
+
+// WithLogger returns ingress services that explain which address was chosen
+func (s IngressServices) WithLogger(l logger.Logger) IngressServices {
+	s.logger = l.WithTag("ingress")
+	return s
+}
+
+// WithDynamicClient returns ingress services that include Gateway API gateways
+func (s IngressServices) WithDynamicClient(c dynamic.Interface) IngressServices {
+	s.dynamicClient = c
+	return s
+}
+
+// WithInCluster returns ingress services that resolve addresses via
+// cluster DNS (useful when running inside a pod) instead of using
+// load balancer or node addresses
+func (s IngressServices) WithInCluster(inCluster bool) IngressServices {
+	s.inCluster = inCluster
+	return s
 }
 
 func (s IngressServices) List() ([]IngressService, error) {
-	listOpts := metav1.ListOptions{
-		LabelSelector: labels.Set(map[string]string{
-			"knative": "ingressgateway",
-		}).String(),
+	var ingSvcs []IngressService
+
+	istioSvcs, err := s.list(NewIstio().SystemNamespaceName(), map[string]string{"knative": "ingressgateway"})
+	if err != nil {
+		return nil, fmt.Errorf("Listing services in istio namespace: %s", err)
 	}
 
-	istioNsName := NewIstio().SystemNamespaceName()
+	ingSvcs = append(ingSvcs, istioSvcs...)
 
-	services, err := s.coreClient.CoreV1().Services(istioNsName).List(listOpts)
+	kourierSvcs, err := s.list(NewKourier().SystemNamespaceName(), NewKourier().IngressServiceLabels())
 	if err != nil {
-		return nil, fmt.Errorf("Listing services in istio namespace: %s", err)
+		return nil, fmt.Errorf("Listing services in kourier namespace: %s", err)
+	}
+
+	ingSvcs = append(ingSvcs, kourierSvcs...)
+
+	if s.dynamicClient != nil {
+		gateways, err := listGateways(s.dynamicClient)
+		if err != nil {
+			return nil, err
+		}
+
+		ingSvcs = append(ingSvcs, gateways...)
+	}
+
+	return ingSvcs, nil
+}
+
+func (s IngressServices) list(nsName string, selector map[string]string) ([]IngressService, error) {
+	listOpts := metav1.ListOptions{
+		LabelSelector: labels.Set(selector).String(),
+	}
+
+	services, err := s.coreClient.CoreV1().Services(nsName).List(listOpts)
+	if err != nil {
+		return nil, err
 	}
 
 	var ingSvcs []IngressService
 
+	// Node addresses are shared by all NodePort services
+	nodeAddrs := &nodeAddresses{coreClient: s.coreClient}
+
 	for _, svc := range services.Items {
 		switch svc.Spec.Type {
 		case corev1.ServiceTypeLoadBalancer:
 			ingSvcs = append(ingSvcs, IngressServiceLoadBalancer{svc})
 
 		case corev1.ServiceTypeNodePort:
-			ingSvcs = append(ingSvcs, IngressServiceNodePort{s.coreClient, svc})
+			ingSvcs = append(ingSvcs, IngressServiceNodePort{nodeAddrs, svc})
 
 		case corev1.ServiceTypeClusterIP, corev1.ServiceTypeExternalName:
 			// TODO ing service
@@ -90,7 +152,42 @@ func (s IngressServices) List() ([]IngressService, error) {
 	return ingSvcs, nil
 }
 
+type preferredAddress struct {
+	Address string
+	Port    string
+}
+
 func (s IngressServices) PreferredAddress(port int32) (string, string, error) {
+	if s.inCluster {
+		return s.clusterAddress(port)
+	}
+
+	cacheKey := fmt.Sprintf("ingress-address-%d", port)
+
+	var addr preferredAddress
+
+	if s.cache.Get(cacheKey, &addr) {
+		s.logger.Debug("chose address", logger.Fields{
+			"address": addr.Address, "port": addr.Port, "reason": "found in cache"})
+		return addr.Address, addr.Port, nil
+	}
+
+	var err error
+
+	addr.Address, addr.Port, err = s.preferredAddress(port)
+	if err != nil {
+		return "", "", err
+	}
+
+	err = s.cache.Set(cacheKey, addr)
+	if err != nil {
+		return "", "", err
+	}
+
+	return addr.Address, addr.Port, nil
+}
+
+func (s IngressServices) preferredAddress(port int32) (string, string, error) {
 	ingSvcs, err := s.List()
 	if err != nil {
 		return "", "", err
@@ -98,16 +195,59 @@ func (s IngressServices) PreferredAddress(port int32) (string, string, error) {
 
 	for _, svc := range ingSvcs {
 		addrs := svc.Addresses()
-		port = svc.MappedPort(port)
+		mappedPort := svc.MappedPort(port)
 
-		if len(addrs) > 0 && port != 0 {
-			return addrs[0], fmt.Sprintf("%d", port), nil
+		if len(addrs) > 0 && mappedPort != 0 {
+			s.logger.Debug("chose address", logger.Fields{
+				"service": svc.Name(), "address": addrs[0], "port": mappedPort,
+				"reason": "first ingress service with address and exposed port"})
+			return addrs[0], fmt.Sprintf("%d", mappedPort), nil
 		}
+
+		s.logger.Debug("skipped service", logger.Fields{
+			"service": svc.Name(), "addresses": len(addrs), "port": port, "mappedPort": mappedPort})
 	}
 
 	return "", "", fmt.Errorf("Expected to find at least one ingress address")
 }
 
+// clusterAddress returns DNS name of the first ingress service exposing
+// given port. Service type does not matter since service port is
+// reachable from within the cluster; client-side heuristics for
+// load balancers, node ports and minikube are skipped.
+func (s IngressServices) clusterAddress(port int32) (string, string, error) {
+	sources := []struct {
+		nsName   string
+		selector map[string]string
+	}{
+		{NewIstio().SystemNamespaceName(), map[string]string{"knative": "ingressgateway"}},
+		{NewKourier().SystemNamespaceName(), NewKourier().IngressServiceLabels()},
+	}
+
+	for _, src := range sources {
+		listOpts := metav1.ListOptions{LabelSelector: labels.Set(src.selector).String()}
+
+		services, err := s.coreClient.CoreV1().Services(src.nsName).List(listOpts)
+		if err != nil {
+			return "", "", fmt.Errorf("Listing services in namespace '%s': %s", src.nsName, err)
+		}
+
+		for _, svc := range services.Items {
+			for _, svcPort := range svc.Spec.Ports {
+				if svcPort.Port == port {
+					addr := fmt.Sprintf("%s.%s.svc.cluster.local", svc.Name, svc.Namespace)
+					s.logger.Debug("chose address", logger.Fields{
+						"service": svc.Name, "address": addr, "port": port,
+						"reason": "first ingress service exposing port (in-cluster)"})
+					return addr, fmt.Sprintf("%d", port), nil
+				}
+			}
+		}
+	}
+
+	return "", "", fmt.Errorf("Expected to find at least one ingress service exposing port %d", port)
+}
+
 func (s IngressServiceLoadBalancer) Name() string { return s.Service.Name }
 
 func (s IngressServiceLoadBalancer) CreationTime() time.Time {
@@ -155,9 +295,26 @@ func (s IngressServiceNodePort) CreationTime() time.Time {
 }
 
 func (s IngressServiceNodePort) Addresses() []string {
+	return s.nodeAddrs.Addresses()
+}
+
+// nodeAddresses lists nodes at most once
+type nodeAddresses struct {
+	coreClient kubernetes.Interface
+
+	once  sync.Once
+	addrs []string
+}
+
+func (a *nodeAddresses) Addresses() []string {
+	a.once.Do(func() { a.addrs = a.fetch() })
+	return a.addrs
+}
+
+func (a *nodeAddresses) fetch() []string {
 	addrs := []string{}
 
-	nodes, err := s.coreClient.CoreV1().Nodes().List(metav1.ListOptions{})
+	nodes, err := a.coreClient.CoreV1().Nodes().List(metav1.ListOptions{})
 	if err != nil {
 		return nil // TODO propagate error
 	}
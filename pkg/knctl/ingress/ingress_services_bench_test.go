@@ -0,0 +1,53 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingress_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	. "github.com/cppforlife/knctl/pkg/knctl/ingress"
+	"github.com/cppforlife/knctl/pkg/knctl/testkit"
+)
+
+func BenchmarkIngressServices_PreferredAddress(b *testing.B) {
+	cluster := testkit.NewCluster(b,
+		testkit.NewLoadBalancerService("istio-system", "istio-ingressgateway", istioIngressLabels, "1.2.3.4", 80),
+	)
+
+	// Simulates cluster with many applications
+	for i := 0; i < 1000; i++ {
+		cluster.Add(testkit.NewLoadBalancerService("istio-system",
+			fmt.Sprintf("svc%d", i), map[string]string{"app": "other"}, "5.6.7.8", 80))
+	}
+
+	ingressSvcs := NewIngressServices(cluster.CoreClient())
+
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		_, _, err := ingressSvcs.PreferredAddress(80)
+		if err != nil {
+			b.Fatalf("Expected no error: %s", err)
+		}
+	}
+}
+
+func TestIngressServices_PreferredAddressBenchmarkThreshold(t *testing.T) {
+	testkit.RequireBenchmarkThreshold(t, BenchmarkIngressServices_PreferredAddress, 100*time.Millisecond)
+}
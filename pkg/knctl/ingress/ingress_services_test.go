@@ -0,0 +1,179 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingress_test
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	. "github.com/cppforlife/knctl/pkg/knctl/ingress"
+	"github.com/cppforlife/knctl/pkg/knctl/logger"
+	"github.com/cppforlife/knctl/pkg/knctl/testkit"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+var (
+	istioIngressLabels = map[string]string{"knative": "ingressgateway"}
+)
+
+func TestIngressServices_PreferredAddress_LoadBalancer(t *testing.T) {
+	cluster := testkit.NewCluster(t,
+		testkit.NewLoadBalancerService("istio-system", "istio-ingressgateway", istioIngressLabels, "1.2.3.4", 80, 443),
+		testkit.NewLoadBalancerService("istio-system", "other", map[string]string{"app": "other"}, "5.6.7.8", 80),
+	)
+
+	addr, port, err := NewIngressServices(cluster.CoreClient()).PreferredAddress(443)
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	if addr != "1.2.3.4" || port != "443" {
+		t.Fatalf("Expected address to be '1.2.3.4:443' but was '%s:%s'", addr, port)
+	}
+}
+
+func TestIngressServices_PreferredAddress_NodePort(t *testing.T) {
+	cluster := testkit.NewCluster(t,
+		testkit.NewNodePortService("istio-system", "istio-ingressgateway", istioIngressLabels, map[int32]int32{80: 31380}),
+		testkit.NewNode("node1", "10.0.0.1"),
+	)
+
+	addr, port, err := NewIngressServices(cluster.CoreClient()).PreferredAddress(80)
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	if addr != "10.0.0.1" || port != "31380" {
+		t.Fatalf("Expected address to be '10.0.0.1:31380' but was '%s:%s'", addr, port)
+	}
+}
+
+func TestIngressServices_PreferredAddress_InCluster(t *testing.T) {
+	cluster := testkit.NewCluster(t,
+		testkit.NewNodePortService("istio-system", "istio-ingressgateway", istioIngressLabels, map[int32]int32{80: 31380}),
+		testkit.NewNode("node1", "10.0.0.1"),
+	)
+
+	addr, port, err := NewIngressServices(cluster.CoreClient()).WithInCluster(true).PreferredAddress(80)
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	if addr != "istio-ingressgateway.istio-system.svc.cluster.local" || port != "80" {
+		t.Fatalf("Expected address to use cluster DNS but was '%s:%s'", addr, port)
+	}
+
+	_, _, err = NewIngressServices(cluster.CoreClient()).WithInCluster(true).PreferredAddress(443)
+	if err == nil || err.Error() != "Expected to find at least one ingress service exposing port 443" {
+		t.Fatalf("Expected error about missing ingress service but was: %v", err)
+	}
+}
+
+func TestIngressServices_PreferredAddress_NoIngress(t *testing.T) {
+	cluster := testkit.NewCluster(t)
+
+	_, _, err := NewIngressServices(cluster.CoreClient()).PreferredAddress(80)
+	if err == nil || err.Error() != "Expected to find at least one ingress address" {
+		t.Fatalf("Expected error about missing ingress address but was: %v", err)
+	}
+}
+
+func TestIngressServices_PreferredAddress_LogsDecision(t *testing.T) {
+	cluster := testkit.NewCluster(t,
+		testkit.NewLoadBalancerService("istio-system", "istio-ingressgateway", istioIngressLabels, "1.2.3.4", 80),
+	)
+
+	var buf bytes.Buffer
+
+	_, _, err := NewIngressServices(cluster.CoreClient()).WithLogger(logger.NewLogger(&buf)).PreferredAddress(80)
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	for _, expected := range []string{"tag=ingress", `msg="chose address"`, "service=istio-ingressgateway", "address=1.2.3.4"} {
+		if !strings.Contains(buf.String(), expected) {
+			t.Fatalf("Expected log '%s' to include '%s'", buf.String(), expected)
+		}
+	}
+}
+
+func TestIngressServices_List_GatewayAPI(t *testing.T) {
+	gateway := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "gateway.networking.k8s.io/v1beta1",
+		"kind":       "Gateway",
+		"metadata":   map[string]interface{}{"name": "knative-gateway", "namespace": "istio-system"},
+		"spec": map[string]interface{}{
+			"gatewayClassName": "istio",
+			"listeners": []interface{}{
+				map[string]interface{}{"name": "http", "port": int64(80), "protocol": "HTTP"},
+			},
+		},
+		"status": map[string]interface{}{
+			"addresses": []interface{}{
+				map[string]interface{}{"type": "IPAddress", "value": "9.9.9.9"},
+			},
+		},
+	}}
+
+	cluster := testkit.NewCluster(t, gateway)
+
+	dynamicClient, err := cluster.DepsFactory().DynamicClient()
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	ingSvcs, err := NewIngressServices(cluster.CoreClient()).List()
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	if len(ingSvcs) != 0 {
+		t.Fatalf("Expected gateways to be ignored without dynamic client")
+	}
+
+	ingSvcs, err = NewIngressServices(cluster.CoreClient()).WithDynamicClient(dynamicClient).List()
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	if len(ingSvcs) != 1 {
+		t.Fatalf("Expected one gateway, but was: %#v", ingSvcs)
+	}
+
+	ingSvc := ingSvcs[0]
+
+	if ingSvc.Name() != "knative-gateway" {
+		t.Fatalf("Expected name to be 'knative-gateway' but was '%s'", ingSvc.Name())
+	}
+	if !reflect.DeepEqual(ingSvc.Addresses(), []string{"9.9.9.9"}) {
+		t.Fatalf("Expected addresses to be '9.9.9.9' but were %#v", ingSvc.Addresses())
+	}
+	if !reflect.DeepEqual(ingSvc.Ports(), []int32{80}) {
+		t.Fatalf("Expected ports to be '80' but were %#v", ingSvc.Ports())
+	}
+
+	addr, port, err := NewIngressServices(cluster.CoreClient()).WithDynamicClient(dynamicClient).PreferredAddress(80)
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	if addr != "9.9.9.9" || port != "80" {
+		t.Fatalf("Expected address to be '9.9.9.9:80' but was '%s:%s'", addr, port)
+	}
+}
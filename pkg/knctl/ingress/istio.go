@@ -23,3 +23,19 @@ func NewIstio() Istio {
 }
 
 func (i Istio) SystemNamespaceName() string { return "istio-system" }
+
+func (i Istio) SidecarInjectorWebhookName() string { return "istio-sidecar-injector" }
+
+func (i Istio) ControlPlaneLabels() map[string]string {
+	return map[string]string{"istio": "pilot"}
+}
+
+// GatewayLabels returns labels of gateways used by Knative (cluster-local gateway included)
+// and of default Istio ingress gateway
+func (i Istio) GatewayLabels() []map[string]string {
+	return []map[string]string{
+		{"knative": "ingressgateway"},
+		{"istio": "ingressgateway"},
+		{"istio": "cluster-local-gateway"},
+	}
+}
@@ -0,0 +1,280 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingress
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes"
+)
+
+var (
+	// istioCompatibility lists Istio minor versions tested with Knative Serving minor versions
+	istioCompatibility = map[string][]string{
+		"0.2": {"1.0"},
+		"0.3": {"1.0"},
+		"0.4": {"1.0"},
+		"0.5": {"1.0", "1.1"},
+		"0.6": {"1.0", "1.1"},
+		"0.7": {"1.1", "1.2"},
+	}
+)
+
+type IstioStatus struct {
+	Installed bool
+	Version   string
+
+	ControlPlane     []IstioDeployment
+	InjectionWebhook IstioInjectionWebhook
+	GatewayPods      []IstioGatewayPod
+}
+
+type IstioDeployment struct {
+	Name              string
+	Image             string
+	DesiredReplicas   int32
+	AvailableReplicas int32
+}
+
+type IstioInjectionWebhook struct {
+	Found          bool
+	Service        string
+	ReadyEndpoints int
+	FailurePolicy  string
+}
+
+func (w IstioInjectionWebhook) Healthy() bool { return w.Found && w.ReadyEndpoints > 0 }
+
+type IstioGatewayPod struct {
+	Name  string
+	Phase corev1.PodPhase
+	Ready bool
+}
+
+type IstioStatuses struct {
+	coreClient kubernetes.Interface
+}
+
+func NewIstioStatuses(coreClient kubernetes.Interface) IstioStatuses {
+	return IstioStatuses{coreClient}
+}
+
+func (s IstioStatuses) Get() (IstioStatus, error) {
+	istio := NewIstio()
+	nsName := istio.SystemNamespaceName()
+
+	deployments, err := s.coreClient.AppsV1().Deployments(nsName).List(metav1.ListOptions{})
+	if err != nil {
+		return IstioStatus{}, fmt.Errorf("Listing deployments in namespace '%s': %s", nsName, err)
+	}
+
+	var status IstioStatus
+
+	controlPlaneSel := labels.SelectorFromSet(istio.ControlPlaneLabels())
+
+	for _, dep := range deployments.Items {
+		status.Installed = true
+
+		istioDep := IstioDeployment{
+			Name:              dep.Name,
+			AvailableReplicas: dep.Status.AvailableReplicas,
+			DesiredReplicas:   1,
+		}
+
+		if dep.Spec.Replicas != nil {
+			istioDep.DesiredReplicas = *dep.Spec.Replicas
+		}
+
+		if len(dep.Spec.Template.Spec.Containers) > 0 {
+			istioDep.Image = dep.Spec.Template.Spec.Containers[0].Image
+		}
+
+		// Pilot's version is considered to be control plane's version
+		if controlPlaneSel.Matches(labels.Set(dep.Labels)) && len(status.Version) == 0 {
+			status.Version = s.imageTag(istioDep.Image)
+		}
+
+		status.ControlPlane = append(status.ControlPlane, istioDep)
+	}
+
+	sort.Slice(status.ControlPlane, func(i, j int) bool {
+		return status.ControlPlane[i].Name < status.ControlPlane[j].Name
+	})
+
+	status.InjectionWebhook, err = s.injectionWebhook()
+	if err != nil {
+		return IstioStatus{}, err
+	}
+
+	status.GatewayPods, err = s.gatewayPods()
+	if err != nil {
+		return IstioStatus{}, err
+	}
+
+	return status, nil
+}
+
+// Incompatibilities returns known problems with running Istio together with given Knative Serving version
+func (s IstioStatus) Incompatibilities(knativeVersion string) []string {
+	var problems []string
+
+	if !s.Installed {
+		return []string{"Istio is not installed; install it via 'knctl install'"}
+	}
+
+	if !s.InjectionWebhook.Healthy() {
+		problems = append(problems, "Sidecar injection webhook is not healthy; "+
+			"new revision pods will not get Istio sidecars")
+	}
+
+	var readyGateways int
+
+	for _, pod := range s.GatewayPods {
+		if pod.Ready {
+			readyGateways++
+		}
+	}
+
+	if readyGateways == 0 {
+		problems = append(problems, "No ready gateway pods; services will not be reachable")
+	}
+
+	knativeMinor := s.minorVersion(knativeVersion)
+	istioMinor := s.minorVersion(s.Version)
+
+	if supported, found := istioCompatibility[knativeMinor]; found && len(istioMinor) > 0 {
+		var compatible bool
+
+		for _, minor := range supported {
+			if minor == istioMinor {
+				compatible = true
+			}
+		}
+
+		if !compatible {
+			problems = append(problems, fmt.Sprintf("Knative Serving %s is tested with Istio %s.x, but found Istio %s",
+				knativeVersion, strings.Join(supported, ".x, "), s.Version))
+		}
+	}
+
+	return problems
+}
+
+func (s IstioStatuses) injectionWebhook() (IstioInjectionWebhook, error) {
+	name := NewIstio().SidecarInjectorWebhookName()
+
+	config, err := s.coreClient.AdmissionregistrationV1beta1().MutatingWebhookConfigurations().Get(name, metav1.GetOptions{})
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return IstioInjectionWebhook{}, nil
+		}
+		return IstioInjectionWebhook{}, fmt.Errorf("Getting mutating webhook configuration '%s': %s", name, err)
+	}
+
+	webhook := IstioInjectionWebhook{Found: true}
+
+	if len(config.Webhooks) == 0 || config.Webhooks[0].ClientConfig.Service == nil {
+		return webhook, nil
+	}
+
+	if config.Webhooks[0].FailurePolicy != nil {
+		webhook.FailurePolicy = string(*config.Webhooks[0].FailurePolicy)
+	}
+
+	svcRef := config.Webhooks[0].ClientConfig.Service
+	webhook.Service = svcRef.Namespace + "/" + svcRef.Name
+
+	endpoints, err := s.coreClient.CoreV1().Endpoints(svcRef.Namespace).Get(svcRef.Name, metav1.GetOptions{})
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return webhook, nil
+		}
+		return IstioInjectionWebhook{}, fmt.Errorf("Getting endpoints '%s': %s", webhook.Service, err)
+	}
+
+	for _, subset := range endpoints.Subsets {
+		webhook.ReadyEndpoints += len(subset.Addresses)
+	}
+
+	return webhook, nil
+}
+
+func (s IstioStatuses) gatewayPods() ([]IstioGatewayPod, error) {
+	var result []IstioGatewayPod
+
+	seen := map[string]struct{}{}
+	nsName := NewIstio().SystemNamespaceName()
+
+	for _, lbls := range NewIstio().GatewayLabels() {
+		listOpts := metav1.ListOptions{LabelSelector: labels.Set(lbls).String()}
+
+		pods, err := s.coreClient.CoreV1().Pods(nsName).List(listOpts)
+		if err != nil {
+			return nil, fmt.Errorf("Listing gateway pods: %s", err)
+		}
+
+		for _, pod := range pods.Items {
+			if _, found := seen[pod.Name]; found {
+				continue
+			}
+
+			seen[pod.Name] = struct{}{}
+
+			result = append(result, IstioGatewayPod{
+				Name:  pod.Name,
+				Phase: pod.Status.Phase,
+				Ready: s.podReady(pod),
+			})
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+
+	return result, nil
+}
+
+func (IstioStatuses) podReady(pod corev1.Pod) bool {
+	for _, cond := range pod.Status.Conditions {
+		if cond.Type == corev1.PodReady {
+			return cond.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+func (IstioStatuses) imageTag(image string) string {
+	pieces := strings.Split(image, ":")
+	if len(pieces) > 1 && !strings.Contains(pieces[len(pieces)-1], "/") {
+		return pieces[len(pieces)-1]
+	}
+	return ""
+}
+
+// minorVersion converts versions such as 'v0.2.1' to '0.2'
+func (IstioStatus) minorVersion(version string) string {
+	pieces := strings.Split(strings.TrimPrefix(version, "v"), ".")
+	if len(pieces) < 2 {
+		return ""
+	}
+	return pieces[0] + "." + pieces[1]
+}
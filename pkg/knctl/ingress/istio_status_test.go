@@ -0,0 +1,152 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingress_test
+
+import (
+	"reflect"
+	"testing"
+
+	. "github.com/cppforlife/knctl/pkg/knctl/ingress"
+	"github.com/cppforlife/knctl/pkg/knctl/testkit"
+	admregv1beta1 "k8s.io/api/admissionregistration/v1beta1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestIstioStatuses_Get(t *testing.T) {
+	cluster := testkit.NewCluster(t,
+		newTestIstioDeployment("istio-pilot", map[string]string{"istio": "pilot"}, "docker.io/istio/pilot:1.0.2", 1),
+		newTestIstioDeployment("istio-ingressgateway", map[string]string{"istio": "ingressgateway"}, "docker.io/istio/proxyv2:1.0.2", 0),
+		newTestIstioWebhook(),
+		&corev1.Endpoints{
+			ObjectMeta: metav1.ObjectMeta{Name: "istio-sidecar-injector", Namespace: "istio-system"},
+			Subsets:    []corev1.EndpointSubset{{Addresses: []corev1.EndpointAddress{{IP: "10.0.0.1"}}}},
+		},
+		newTestGatewayPod("knative-ingressgateway-1", map[string]string{"knative": "ingressgateway"}, true),
+		newTestGatewayPod("istio-ingressgateway-1", map[string]string{"istio": "ingressgateway"}, false),
+	)
+
+	status, err := NewIstioStatuses(cluster.CoreClient()).Get()
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	if !status.Installed || status.Version != "1.0.2" {
+		t.Fatalf("Expected Istio 1.0.2 to be installed, but was: %#v", status)
+	}
+
+	expectedControlPlane := []IstioDeployment{
+		{Name: "istio-ingressgateway", Image: "docker.io/istio/proxyv2:1.0.2", DesiredReplicas: 1, AvailableReplicas: 0},
+		{Name: "istio-pilot", Image: "docker.io/istio/pilot:1.0.2", DesiredReplicas: 1, AvailableReplicas: 1},
+	}
+
+	if !reflect.DeepEqual(status.ControlPlane, expectedControlPlane) {
+		t.Fatalf("Expected control plane to match, but was: %#v", status.ControlPlane)
+	}
+
+	expectedWebhook := IstioInjectionWebhook{
+		Found:          true,
+		Service:        "istio-system/istio-sidecar-injector",
+		ReadyEndpoints: 1,
+		FailurePolicy:  "Fail",
+	}
+
+	if !reflect.DeepEqual(status.InjectionWebhook, expectedWebhook) {
+		t.Fatalf("Expected webhook to match, but was: %#v", status.InjectionWebhook)
+	}
+
+	expectedPods := []IstioGatewayPod{
+		{Name: "istio-ingressgateway-1", Phase: corev1.PodRunning, Ready: false},
+		{Name: "knative-ingressgateway-1", Phase: corev1.PodRunning, Ready: true},
+	}
+
+	if !reflect.DeepEqual(status.GatewayPods, expectedPods) {
+		t.Fatalf("Expected gateway pods to match, but was: %#v", status.GatewayPods)
+	}
+
+	if problems := status.Incompatibilities("v0.2.1"); len(problems) != 0 {
+		t.Fatalf("Expected no problems, but was: %#v", problems)
+	}
+
+	expectedProblems := []string{"Knative Serving v0.7.0 is tested with Istio 1.1.x, 1.2.x, but found Istio 1.0.2"}
+
+	if problems := status.Incompatibilities("v0.7.0"); !reflect.DeepEqual(problems, expectedProblems) {
+		t.Fatalf("Expected version problem, but was: %#v", problems)
+	}
+}
+
+func TestIstioStatus_IncompatibilitiesWithUnhealthyMesh(t *testing.T) {
+	status := IstioStatus{Installed: true, Version: "1.0.2"}
+
+	expectedProblems := []string{
+		"Sidecar injection webhook is not healthy; new revision pods will not get Istio sidecars",
+		"No ready gateway pods; services will not be reachable",
+	}
+
+	if problems := status.Incompatibilities(""); !reflect.DeepEqual(problems, expectedProblems) {
+		t.Fatalf("Expected mesh problems, but was: %#v", problems)
+	}
+
+	status = IstioStatus{}
+
+	if problems := status.Incompatibilities(""); len(problems) != 1 {
+		t.Fatalf("Expected not installed problem, but was: %#v", problems)
+	}
+}
+
+func newTestIstioDeployment(name string, labels map[string]string, image string, available int32) *appsv1.Deployment {
+	replicas := int32(1)
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "istio-system", Labels: labels},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: &replicas,
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "main", Image: image}}},
+			},
+		},
+		Status: appsv1.DeploymentStatus{AvailableReplicas: available},
+	}
+}
+
+func newTestIstioWebhook() *admregv1beta1.MutatingWebhookConfiguration {
+	failurePolicy := admregv1beta1.Fail
+	return &admregv1beta1.MutatingWebhookConfiguration{
+		ObjectMeta: metav1.ObjectMeta{Name: "istio-sidecar-injector"},
+		Webhooks: []admregv1beta1.Webhook{{
+			Name:          "sidecar-injector.istio.io",
+			FailurePolicy: &failurePolicy,
+			ClientConfig: admregv1beta1.WebhookClientConfig{
+				Service: &admregv1beta1.ServiceReference{Namespace: "istio-system", Name: "istio-sidecar-injector"},
+			},
+		}},
+	}
+}
+
+func newTestGatewayPod(name string, labels map[string]string, ready bool) *corev1.Pod {
+	readyStatus := corev1.ConditionFalse
+	if ready {
+		readyStatus = corev1.ConditionTrue
+	}
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "istio-system", Labels: labels},
+		Status: corev1.PodStatus{
+			Phase:      corev1.PodRunning,
+			Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: readyStatus}},
+		},
+	}
+}
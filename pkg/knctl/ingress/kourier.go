@@ -0,0 +1,31 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingress
+
+type Kourier struct{}
+
+func NewKourier() Kourier {
+	return Kourier{}
+}
+
+func (k Kourier) SystemNamespaceName() string { return "kourier-system" }
+
+func (k Kourier) IngressClass() string { return "kourier.ingress.networking.knative.dev" }
+
+func (k Kourier) IngressServiceLabels() map[string]string {
+	return map[string]string{"networking.knative.dev/ingress-provider": "kourier"}
+}
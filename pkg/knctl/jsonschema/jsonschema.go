@@ -0,0 +1,160 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package jsonschema derives JSON Schema documents from Go types
+// following encoding/json rules, so that documented output
+// cannot drift from what commands actually print.
+package jsonschema
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"time"
+)
+
+const (
+	Draft = "http://json-schema.org/draft-07/schema#"
+)
+
+// Schema is a JSON Schema document (keys are serialized in sorted order)
+type Schema map[string]interface{}
+
+var (
+	durationType   = reflect.TypeOf(time.Duration(0))
+	timeType       = reflect.TypeOf(time.Time{})
+	rawMessageType = reflect.TypeOf(json.RawMessage{})
+)
+
+// For returns schema describing JSON encoding of given value's type
+func For(val interface{}) Schema {
+	schema := forType(reflect.TypeOf(val))
+	schema["$schema"] = Draft
+	return schema
+}
+
+func forType(t reflect.Type) Schema {
+	switch t {
+	case durationType:
+		return Schema{"type": "integer", "description": "Duration in nanoseconds"}
+	case timeType:
+		return Schema{"type": "string", "format": "date-time"}
+	case rawMessageType:
+		return Schema{}
+	}
+
+	switch t.Kind() {
+	case reflect.Ptr:
+		return nullable(forType(t.Elem()))
+
+	case reflect.Bool:
+		return Schema{"type": "boolean"}
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return Schema{"type": "integer"}
+
+	case reflect.Float32, reflect.Float64:
+		return Schema{"type": "number"}
+
+	case reflect.String:
+		return Schema{"type": "string"}
+
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return Schema{"type": "string", "contentEncoding": "base64"}
+		}
+		// Nil slices are encoded as null
+		return nullable(Schema{"type": "array", "items": forType(t.Elem())})
+
+	case reflect.Array:
+		return Schema{"type": "array", "items": forType(t.Elem())}
+
+	case reflect.Map:
+		return nullable(Schema{"type": "object", "additionalProperties": forType(t.Elem())})
+
+	case reflect.Struct:
+		return forStruct(t)
+
+	default:
+		// Interfaces may hold any value
+		return Schema{}
+	}
+}
+
+func forStruct(t reflect.Type) Schema {
+	properties := Schema{}
+	required := []string{}
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if len(field.PkgPath) > 0 { // unexported
+			continue
+		}
+
+		name, omitEmpty, skip := jsonFieldName(field)
+		if skip {
+			continue
+		}
+
+		properties[name] = forType(field.Type)
+
+		if !omitEmpty {
+			required = append(required, name)
+		}
+	}
+
+	schema := Schema{
+		"type":                 "object",
+		"properties":           properties,
+		"additionalProperties": false,
+	}
+
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+
+	return schema
+}
+
+func jsonFieldName(field reflect.StructField) (string, bool, bool) {
+	tag := field.Tag.Get("json")
+	if tag == "-" {
+		return "", false, true
+	}
+
+	pieces := strings.Split(tag, ",")
+	name := pieces[0]
+	if len(name) == 0 {
+		name = field.Name
+	}
+
+	var omitEmpty bool
+	for _, opt := range pieces[1:] {
+		if opt == "omitempty" {
+			omitEmpty = true
+		}
+	}
+
+	return name, omitEmpty, false
+}
+
+func nullable(schema Schema) Schema {
+	if typ, ok := schema["type"].(string); ok {
+		schema["type"] = []string{typ, "null"}
+	}
+	return schema
+}
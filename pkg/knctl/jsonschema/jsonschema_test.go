@@ -0,0 +1,62 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jsonschema_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	. "github.com/cppforlife/knctl/pkg/knctl/jsonschema"
+)
+
+type exampleDoc struct {
+	Name     string            `json:"name"`
+	Note     string            `json:"note,omitempty"`
+	Count    int               `json:"count"`
+	Timeout  time.Duration     `json:"timeout"`
+	Tags     []string          `json:"tags"`
+	Labels   map[string]string `json:"labels,omitempty"`
+	Nested   *exampleNested    `json:"nested,omitempty"`
+	Internal string            `json:"-"`
+	Untagged bool
+
+	unexported string
+}
+
+type exampleNested struct {
+	OK bool `json:"ok"`
+}
+
+func TestFor(t *testing.T) {
+	bs, err := json.Marshal(For(exampleDoc{}))
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	expected := `{"$schema":"http://json-schema.org/draft-07/schema#","additionalProperties":false,` +
+		`"properties":{"Untagged":{"type":"boolean"},"count":{"type":"integer"},` +
+		`"labels":{"additionalProperties":{"type":"string"},"type":["object","null"]},"name":{"type":"string"},` +
+		`"nested":{"additionalProperties":false,"properties":{"ok":{"type":"boolean"}},"required":["ok"],"type":["object","null"]},` +
+		`"note":{"type":"string"},"tags":{"items":{"type":"string"},"type":["array","null"]},` +
+		`"timeout":{"description":"Duration in nanoseconds","type":"integer"}},` +
+		`"required":["name","count","timeout","tags","Untagged"],"type":"object"}`
+
+	if string(bs) != expected {
+		t.Fatalf("Expected schema:\n%s\nbut was:\n%s", expected, bs)
+	}
+}
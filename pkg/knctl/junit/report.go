@@ -0,0 +1,122 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package junit writes test results in JUnit XML format
+// understood by most CI systems.
+package junit
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"time"
+)
+
+type TestSuites struct {
+	XMLName xml.Name    `xml:"testsuites"`
+	Suites  []TestSuite `xml:"testsuite"`
+}
+
+type TestSuite struct {
+	Name     string     `xml:"name,attr"`
+	Tests    int        `xml:"tests,attr"`
+	Failures int        `xml:"failures,attr"`
+	Skipped  int        `xml:"skipped,attr"`
+	Time     string     `xml:"time,attr"`
+	Cases    []TestCase `xml:"testcase"`
+}
+
+type TestCase struct {
+	Name      string       `xml:"name,attr"`
+	ClassName string       `xml:"classname,attr"`
+	Time      string       `xml:"time,attr"`
+	Failure   *TestFailure `xml:"failure,omitempty"`
+	Skipped   *TestSkipped `xml:"skipped,omitempty"`
+	SystemOut string       `xml:"system-out,omitempty"`
+}
+
+type TestFailure struct {
+	Message string `xml:"message,attr"`
+	Content string `xml:",chardata"`
+}
+
+type TestSkipped struct {
+	Message string `xml:"message,attr"`
+}
+
+// Result is an outcome of a single test case
+type Result struct {
+	Name string
+	// Defaults to suite name
+	ClassName  string
+	Duration   time.Duration
+	Err        error
+	SkipReason string
+	Output     string
+}
+
+func NewTestSuite(name string, results []Result) TestSuite {
+	suite := TestSuite{Name: name, Tests: len(results)}
+
+	var total time.Duration
+
+	for _, result := range results {
+		tc := TestCase{
+			Name:      result.Name,
+			ClassName: result.ClassName,
+			Time:      Seconds(result.Duration),
+			SystemOut: result.Output,
+		}
+
+		if len(tc.ClassName) == 0 {
+			tc.ClassName = name
+		}
+
+		switch {
+		case len(result.SkipReason) > 0:
+			tc.Skipped = &TestSkipped{Message: result.SkipReason}
+			suite.Skipped++
+		case result.Err != nil:
+			tc.Failure = &TestFailure{Message: result.Err.Error(), Content: result.Err.Error()}
+			suite.Failures++
+		}
+
+		total += result.Duration
+		suite.Cases = append(suite.Cases, tc)
+	}
+
+	suite.Time = Seconds(total)
+
+	return suite
+}
+
+func Write(w io.Writer, suites ...TestSuite) error {
+	bs, err := xml.MarshalIndent(TestSuites{Suites: suites}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("Marshaling JUnit report: %s", err)
+	}
+
+	_, err = fmt.Fprintf(w, "%s%s\n", xml.Header, bs)
+	if err != nil {
+		return fmt.Errorf("Writing JUnit report: %s", err)
+	}
+
+	return nil
+}
+
+func Seconds(d time.Duration) string {
+	return fmt.Sprintf("%.3f", d.Seconds())
+}
@@ -0,0 +1,59 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package junit_test
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/cppforlife/knctl/pkg/knctl/junit"
+)
+
+func TestWrite(t *testing.T) {
+	suite := junit.NewTestSuite("conformance", []junit.Result{
+		{Name: "probing", Duration: 1500 * time.Millisecond},
+		{Name: "grpc", SkipReason: "no image"},
+		{Name: "scale-bounds", Duration: 2 * time.Second, Err: fmt.Errorf("Expected 2 pods <ready>")},
+	})
+
+	buf := bytes.NewBuffer(nil)
+
+	err := junit.Write(buf, suite)
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	expected := `<?xml version="1.0" encoding="UTF-8"?>
+<testsuites>
+  <testsuite name="conformance" tests="3" failures="1" skipped="1" time="3.500">
+    <testcase name="probing" classname="conformance" time="1.500"></testcase>
+    <testcase name="grpc" classname="conformance" time="0.000">
+      <skipped message="no image"></skipped>
+    </testcase>
+    <testcase name="scale-bounds" classname="conformance" time="2.000">
+      <failure message="Expected 2 pods &lt;ready&gt;">Expected 2 pods &lt;ready&gt;</failure>
+    </testcase>
+  </testsuite>
+</testsuites>
+`
+
+	if buf.String() != expected {
+		t.Fatalf("Expected report to match:\n%s\nbut was:\n%s", expected, buf.String())
+	}
+}
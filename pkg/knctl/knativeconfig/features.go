@@ -0,0 +1,149 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package knativeconfig
+
+import (
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	ServingNamespace = "knative-serving"
+
+	FeatureMultiContainer   = "multi-container"
+	FeatureEmptyDir         = "kubernetes.podspec-volumes-emptydir"
+	FeatureAffinity         = "kubernetes.podspec-affinity"
+	FeatureFieldRef         = "kubernetes.podspec-fieldref"
+	FeatureNodeSelector     = "kubernetes.podspec-nodeselector"
+	FeatureTolerations      = "kubernetes.podspec-tolerations"
+	FeatureRuntimeClassName = "kubernetes.podspec-runtimeclassname"
+	FeatureSecurityContext  = "kubernetes.podspec-securitycontext"
+	FeatureInitContainers   = "kubernetes.podspec-init-containers"
+	FeatureAutoTLS          = "autoTLS"
+
+	FeatureTagHeaderBasedRouting = "tag-header-based-routing"
+
+	featuresConfigMapName = "config-features"
+	networkConfigMapName  = "config-network"
+)
+
+type FeatureDefinition struct {
+	Name        string
+	Description string
+	ConfigMap   string
+	Default     string
+}
+
+var (
+	// KnownFeatures lists optional capabilities of Knative Serving that
+	// are controlled by operators via config maps
+	KnownFeatures = []FeatureDefinition{
+		{FeatureMultiContainer, "Multiple containers per revision", featuresConfigMapName, "enabled"},
+		{FeatureEmptyDir, "emptyDir volumes", featuresConfigMapName, "disabled"},
+		{FeatureAffinity, "PodSpec affinity", featuresConfigMapName, "disabled"},
+		{FeatureFieldRef, "Downward API field references", featuresConfigMapName, "disabled"},
+		{FeatureNodeSelector, "PodSpec node selector", featuresConfigMapName, "disabled"},
+		{FeatureTolerations, "PodSpec tolerations", featuresConfigMapName, "disabled"},
+		{FeatureRuntimeClassName, "PodSpec runtime class name", featuresConfigMapName, "disabled"},
+		{FeatureSecurityContext, "PodSpec and extended container security context", featuresConfigMapName, "disabled"},
+		{FeatureInitContainers, "Init containers", featuresConfigMapName, "disabled"},
+		{FeatureAutoTLS, "Automatic TLS certificates", networkConfigMapName, "Disabled"},
+		{FeatureTagHeaderBasedRouting, "Routing to named traffic targets via Knative-Serving-Tag header", featuresConfigMapName, "disabled"},
+	}
+)
+
+type Feature struct {
+	FeatureDefinition
+
+	Value   string
+	Enabled bool
+	// Default indicates that value was not explicitly configured
+	IsDefault bool
+}
+
+type Features struct {
+	coreClient kubernetes.Interface
+}
+
+func NewFeatures(coreClient kubernetes.Interface) Features {
+	return Features{coreClient}
+}
+
+func (f Features) List() ([]Feature, error) {
+	configMapsData := map[string]map[string]string{}
+
+	for _, def := range KnownFeatures {
+		if _, found := configMapsData[def.ConfigMap]; found {
+			continue
+		}
+
+		configMap, err := f.coreClient.CoreV1().ConfigMaps(ServingNamespace).Get(def.ConfigMap, metav1.GetOptions{})
+		if err != nil {
+			if !errors.IsNotFound(err) {
+				return nil, fmt.Errorf("Getting config map '%s/%s': %s", ServingNamespace, def.ConfigMap, err)
+			}
+			configMapsData[def.ConfigMap] = nil
+			continue
+		}
+
+		configMapsData[def.ConfigMap] = configMap.Data
+	}
+
+	var result []Feature
+
+	for _, def := range KnownFeatures {
+		feature := Feature{FeatureDefinition: def, Value: def.Default, IsDefault: true}
+
+		if val, found := configMapsData[def.ConfigMap][def.Name]; found {
+			feature.Value = val
+			feature.IsDefault = false
+		}
+
+		switch strings.ToLower(feature.Value) {
+		case "enabled", "allowed":
+			feature.Enabled = true
+		}
+
+		result = append(result, feature)
+	}
+
+	return result, nil
+}
+
+// Disabled returns subset of given feature names that are disabled
+func (f Features) Disabled(names []string) ([]Feature, error) {
+	features, err := f.List()
+	if err != nil {
+		return nil, err
+	}
+
+	var result []Feature
+
+	for _, feature := range features {
+		for _, name := range names {
+			if feature.Name == name && !feature.Enabled {
+				result = append(result, feature)
+			}
+		}
+	}
+
+	return result, nil
+}
@@ -0,0 +1,88 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package knativeconfig_test
+
+import (
+	"testing"
+
+	. "github.com/cppforlife/knctl/pkg/knctl/knativeconfig"
+	"github.com/cppforlife/knctl/pkg/knctl/testkit"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestFeaturesList(t *testing.T) {
+	cluster := testkit.NewCluster(t,
+		&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "knative-serving", Name: "config-features"},
+			Data: map[string]string{
+				FeatureEmptyDir:       "enabled",
+				FeatureNodeSelector:   "allowed",
+				FeatureMultiContainer: "disabled",
+			},
+		},
+	)
+
+	features, err := NewFeatures(cluster.CoreClient()).List()
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	if len(features) != len(KnownFeatures) {
+		t.Fatalf("Expected all known features to be listed: %#v", features)
+	}
+
+	expected := map[string]struct {
+		Value     string
+		Enabled   bool
+		IsDefault bool
+	}{
+		FeatureEmptyDir:       {"enabled", true, false},
+		FeatureNodeSelector:   {"allowed", true, false},
+		FeatureMultiContainer: {"disabled", false, false},
+		FeatureAffinity:       {"disabled", false, true},
+		FeatureAutoTLS:        {"Disabled", false, true},
+	}
+
+	for _, feature := range features {
+		ex, found := expected[feature.Name]
+		if !found {
+			continue
+		}
+		if feature.Value != ex.Value || feature.Enabled != ex.Enabled || feature.IsDefault != ex.IsDefault {
+			t.Fatalf("Expected feature '%s' to match: %#v", feature.Name, feature)
+		}
+	}
+}
+
+func TestFeaturesDisabled(t *testing.T) {
+	cluster := testkit.NewCluster(t,
+		&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "knative-serving", Name: "config-network"},
+			Data:       map[string]string{FeatureAutoTLS: "Enabled"},
+		},
+	)
+
+	features, err := NewFeatures(cluster.CoreClient()).Disabled([]string{FeatureAutoTLS, FeatureSecurityContext})
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	if len(features) != 1 || features[0].Name != FeatureSecurityContext {
+		t.Fatalf("Expected only security context feature to be disabled: %#v", features)
+	}
+}
@@ -40,6 +40,22 @@ func NewExec(pod corev1.Pod, container string, coreClient kubernetes.Interface,
 }
 
 func (s Exec) Execute(cmd []string, stdin io.Reader) error {
+	return s.execute(cmd, stdin, nil)
+}
+
+// Output executes command and returns its stdout
+func (s Exec) Output(cmd []string) ([]byte, error) {
+	var stdout bytes.Buffer
+
+	err := s.execute(cmd, nil, &stdout)
+	if err != nil {
+		return nil, err
+	}
+
+	return stdout.Bytes(), nil
+}
+
+func (s Exec) execute(cmd []string, stdin io.Reader, stdout io.Writer) error {
 	req := s.coreClient.CoreV1().RESTClient().Post().
 		Resource("pods").
 		Name(s.pod.Name).
@@ -47,6 +63,7 @@ func (s Exec) Execute(cmd []string, stdin io.Reader) error {
 		SubResource("exec")
 
 	req.VersionedParams(&corev1.PodExecOptions{
+		Stdout:    stdout != nil,
 		Stderr:    true,
 		Stdin:     stdin != nil,
 		TTY:       false,
@@ -63,6 +80,7 @@ func (s Exec) Execute(cmd []string, stdin io.Reader) error {
 
 	err = executor.Stream(remotecommand.StreamOptions{
 		Stdin:  stdin,
+		Stdout: stdout,
 		Stderr: &stderr,
 		Tty:    false,
 	})
@@ -0,0 +1,104 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package logger
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Fields are key-value pairs attached to a log message
+type Fields map[string]interface{}
+
+// Logger writes debug messages in logfmt format, for example:
+//
+//	time=2018-09-01T10:00:00.000Z tag=api msg=request verb=GET status=200
+//
+// Zero value of Logger discards all messages.
+type Logger struct {
+	writer io.Writer
+	lock   *sync.Mutex
+	tag    string
+
+	nowFunc func() time.Time
+}
+
+func NewLogger(writer io.Writer) Logger {
+	return Logger{writer: writer, lock: &sync.Mutex{}, nowFunc: time.Now}
+}
+
+func NewNoopLogger() Logger { return Logger{} }
+
+// WithTag returns logger that marks messages with given tag (e.g. 'api')
+func (l Logger) WithTag(tag string) Logger {
+	l.tag = tag
+	return l
+}
+
+// WithNow returns logger that uses given clock (useful for tests)
+func (l Logger) WithNow(nowFunc func() time.Time) Logger {
+	l.nowFunc = nowFunc
+	return l
+}
+
+// Enabled indicates whether messages are written anywhere,
+// so that callers can avoid computing expensive fields
+func (l Logger) Enabled() bool { return l.writer != nil }
+
+func (l Logger) Debug(msg string, fields Fields) {
+	if !l.Enabled() {
+		return
+	}
+
+	pieces := []string{
+		"time=" + l.nowFunc().UTC().Format("2006-01-02T15:04:05.000Z07:00"),
+		"level=debug",
+	}
+
+	if len(l.tag) > 0 {
+		pieces = append(pieces, "tag="+l.value(l.tag))
+	}
+
+	pieces = append(pieces, "msg="+l.value(msg))
+
+	var keys []string
+	for k := range fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		pieces = append(pieces, k+"="+l.value(fields[k]))
+	}
+
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	fmt.Fprintln(l.writer, strings.Join(pieces, " "))
+}
+
+func (Logger) value(val interface{}) string {
+	str := fmt.Sprintf("%v", val)
+	if len(str) == 0 || strings.ContainsAny(str, " \"=\t\n") {
+		return fmt.Sprintf("%q", str)
+	}
+	return str
+}
@@ -0,0 +1,56 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package logger_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	. "github.com/cppforlife/knctl/pkg/knctl/logger"
+)
+
+func TestLogger_Debug(t *testing.T) {
+	var buf bytes.Buffer
+
+	now := func() time.Time { return time.Date(2018, 9, 1, 10, 0, 0, 0, time.UTC) }
+
+	NewLogger(&buf).WithNow(now).WithTag("api").Debug("request", Fields{
+		"verb":   "GET",
+		"path":   "/api/v1/namespaces",
+		"status": 200,
+		"reason": "has address",
+		"empty":  "",
+	})
+
+	expectedOutput := `time=2018-09-01T10:00:00.000Z level=debug tag=api msg=request empty="" path=/api/v1/namespaces reason="has address" status=200 verb=GET` + "\n"
+
+	if buf.String() != expectedOutput {
+		t.Fatalf("Expected output to be '%s' but was '%s'", expectedOutput, buf.String())
+	}
+}
+
+func TestLogger_NoopLogger(t *testing.T) {
+	logger := NewNoopLogger().WithTag("api")
+
+	if logger.Enabled() {
+		t.Fatalf("Expected noop logger to not be enabled")
+	}
+
+	// Should not panic
+	logger.Debug("request", Fields{"verb": "GET"})
+}
@@ -0,0 +1,63 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package logs
+
+import (
+	"bufio"
+	"io"
+)
+
+const (
+	truncatedLineSuffix = "... (line truncated)\n"
+)
+
+// BoundedLineReader reads lines without allocating more than maxLen bytes
+// per line so that a container printing huge lines (or no newlines at all)
+// cannot balloon memory; rest of a longer line is discarded
+type BoundedLineReader struct {
+	reader *bufio.Reader
+}
+
+func NewBoundedLineReader(reader io.Reader, maxLen int) BoundedLineReader {
+	return BoundedLineReader{bufio.NewReaderSize(reader, maxLen)}
+}
+
+// ReadLine returns next line always ending with a newline
+func (r BoundedLineReader) ReadLine() ([]byte, error) {
+	slice, err := r.reader.ReadSlice('\n')
+	if err == nil {
+		return append([]byte{}, slice...), nil
+	}
+
+	if err != bufio.ErrBufferFull {
+		if err == io.EOF && len(slice) > 0 {
+			return append(append([]byte{}, slice...), '\n'), nil
+		}
+		return nil, err
+	}
+
+	line := append(append([]byte{}, slice...), []byte(truncatedLineSuffix)...)
+
+	for err == bufio.ErrBufferFull {
+		_, err = r.reader.ReadSlice('\n')
+	}
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+
+	return line, nil
+}
@@ -0,0 +1,45 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package logs_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/cppforlife/knctl/pkg/knctl/logs"
+)
+
+func TestBoundedLineReader(t *testing.T) {
+	long := strings.Repeat("x", 100)
+	reader := logs.NewBoundedLineReader(strings.NewReader("short\n"+long+"\nlast"), 16)
+
+	var lines []string
+
+	for {
+		line, err := reader.ReadLine()
+		if err != nil {
+			break
+		}
+		lines = append(lines, string(line))
+	}
+
+	expected := []string{"short\n", strings.Repeat("x", 16) + "... (line truncated)\n", "last\n"}
+
+	if strings.Join(lines, "|") != strings.Join(expected, "|") {
+		t.Fatalf("Expected lines '%#v', but was '%#v'", expected, lines)
+	}
+}
@@ -0,0 +1,133 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package logs
+
+import (
+	"sync"
+
+	"github.com/cppforlife/go-cli-ui/ui"
+)
+
+const (
+	DefaultLineBufferLines = 1000
+	DefaultLineBufferBytes = 1024 * 1024
+)
+
+type lineBufferEntry struct {
+	ui   ui.UI
+	line []byte
+}
+
+// LineBuffer is a bounded FIFO of log lines shared by multiple tails
+// so that memory use does not depend on the number of pods or on how fast
+// logs are produced. Once it's full, Put blocks which stops reading from
+// log streams until UI catches up (i.e. slow terminal applies backpressure).
+type LineBuffer struct {
+	maxLines int
+	maxBytes int
+
+	lock     sync.Mutex
+	notFull  *sync.Cond
+	notEmpty *sync.Cond
+
+	entries []lineBufferEntry // ring of maxLines entries
+	start   int
+	count   int
+	bytes   int
+	closed  bool
+}
+
+func NewLineBuffer(maxLines, maxBytes int) *LineBuffer {
+	b := &LineBuffer{
+		maxLines: maxLines,
+		maxBytes: maxBytes,
+		entries:  make([]lineBufferEntry, maxLines),
+	}
+	b.notFull = sync.NewCond(&b.lock)
+	b.notEmpty = sync.NewCond(&b.lock)
+	return b
+}
+
+// Put blocks until there is room for the line; returns false if buffer was closed.
+// Line that is bigger than the whole buffer is accepted once buffer is empty.
+func (b *LineBuffer) Put(ui ui.UI, line []byte) bool {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	for !b.closed && b.count > 0 && (b.count == b.maxLines || b.bytes+len(line) > b.maxBytes) {
+		b.notFull.Wait()
+	}
+
+	if b.closed {
+		return false
+	}
+
+	b.entries[(b.start+b.count)%b.maxLines] = lineBufferEntry{ui, line}
+	b.count++
+	b.bytes += len(line)
+
+	b.notEmpty.Signal()
+
+	return true
+}
+
+// Close makes pending and future Put calls return false;
+// already buffered lines are still printed by Drain
+func (b *LineBuffer) Close() {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	b.closed = true
+
+	b.notFull.Broadcast()
+	b.notEmpty.Broadcast()
+}
+
+// Drain prints buffered lines in order until buffer is closed and empty
+func (b *LineBuffer) Drain() {
+	for {
+		entry, ok := b.take()
+		if !ok {
+			return
+		}
+		entry.ui.PrintBlock(entry.line)
+	}
+}
+
+func (b *LineBuffer) take() (lineBufferEntry, bool) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	for !b.closed && b.count == 0 {
+		b.notEmpty.Wait()
+	}
+
+	if b.count == 0 {
+		return lineBufferEntry{}, false
+	}
+
+	entry := b.entries[b.start]
+	b.entries[b.start] = lineBufferEntry{} // release line memory
+
+	b.start = (b.start + 1) % b.maxLines
+	b.count--
+	b.bytes -= len(entry.line)
+
+	b.notFull.Broadcast()
+
+	return entry, true
+}
@@ -0,0 +1,110 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package logs_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cppforlife/go-cli-ui/ui"
+	"github.com/cppforlife/knctl/pkg/knctl/logs"
+)
+
+type recordingUI struct {
+	ui.UI
+	blocksCh chan string
+}
+
+func (u recordingUI) PrintBlock(block []byte) { u.blocksCh <- string(block) }
+
+func TestLineBuffer_PreservesOrder(t *testing.T) {
+	buf := logs.NewLineBuffer(3, 1024)
+	ui := recordingUI{ui.NewNoopUI(), make(chan string, 10)}
+
+	go func() {
+		for _, line := range []string{"a\n", "b\n", "c\n", "d\n", "e\n"} {
+			buf.Put(ui, []byte(line))
+		}
+		buf.Close()
+	}()
+
+	buf.Drain()
+	close(ui.blocksCh)
+
+	var result []string
+	for block := range ui.blocksCh {
+		result = append(result, block)
+	}
+
+	if strings.Join(result, "") != "a\nb\nc\nd\ne\n" {
+		t.Fatalf("Expected lines to be printed in order, but was: %#v", result)
+	}
+}
+
+func TestLineBuffer_BlocksWhenFull(t *testing.T) {
+	for _, limits := range [][2]int{{2, 1024}, {100, 4}} {
+		buf := logs.NewLineBuffer(limits[0], limits[1])
+		ui := recordingUI{ui.NewNoopUI(), make(chan string)}
+
+		buf.Put(ui, []byte("a\n"))
+		buf.Put(ui, []byte("b\n"))
+
+		putCh := make(chan bool)
+
+		go func() { putCh <- buf.Put(ui, []byte("c\n")) }()
+
+		select {
+		case <-putCh:
+			t.Fatalf("Expected put to block when buffer is full (limits: %v)", limits)
+		case <-time.After(50 * time.Millisecond):
+		}
+
+		go buf.Drain()
+
+		if block := <-ui.blocksCh; block != "a\n" {
+			t.Fatalf("Expected first line, but was '%s'", block)
+		}
+		if !<-putCh {
+			t.Fatalf("Expected put to succeed once there is room")
+		}
+
+		buf.Close()
+	}
+}
+
+func TestLineBuffer_CloseUnblocksPut(t *testing.T) {
+	buf := logs.NewLineBuffer(1, 1024)
+	ui := recordingUI{ui.NewNoopUI(), make(chan string, 10)}
+
+	buf.Put(ui, []byte("a\n"))
+
+	putCh := make(chan bool)
+	go func() { putCh <- buf.Put(ui, []byte("b\n")) }()
+
+	buf.Close()
+
+	if <-putCh {
+		t.Fatalf("Expected put to fail after close")
+	}
+
+	buf.Drain()
+
+	if block := <-ui.blocksCh; block != "a\n" {
+		t.Fatalf("Expected buffered line to be drained after close, but was '%s'", block)
+	}
+}
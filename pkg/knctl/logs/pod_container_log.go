@@ -17,7 +17,6 @@ limitations under the License.
 package logs
 
 import (
-	"bufio"
 	"fmt"
 	"io"
 	"sync/atomic"
@@ -29,6 +28,10 @@ import (
 	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
 )
 
+const (
+	maxLineLen = 64 * 1024
+)
+
 type PodContainerLog struct {
 	pod        corev1.Pod
 	container  string
@@ -75,10 +78,10 @@ func (l PodContainerLog) Tail(ui ui.UI, cancelCh chan struct{}) error {
 		stream.Close()
 	}()
 
-	reader := bufio.NewReader(stream)
+	reader := NewBoundedLineReader(stream, maxLineLen)
 
 	for {
-		line, err := reader.ReadBytes('\n')
+		line, err := reader.ReadLine()
 		if err != nil {
 			if err == io.EOF {
 				return nil
@@ -90,7 +93,13 @@ func (l PodContainerLog) Tail(ui ui.UI, cancelCh chan struct{}) error {
 			return err
 		}
 
-		ui.PrintBlock([]byte(fmt.Sprintf("%s | %s", l.tag, line)))
+		block := []byte(fmt.Sprintf("%s | %s", l.tag, line))
+
+		if l.opts.Buffer == nil {
+			ui.PrintBlock(block)
+		} else if !l.opts.Buffer.Put(ui, block) {
+			return nil
+		}
 	}
 }
 
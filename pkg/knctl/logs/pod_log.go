@@ -27,6 +27,10 @@ import (
 type PodLogOpts struct {
 	Follow bool
 	Lines  *int64
+
+	// Buffer is shared by all tails to bound memory use;
+	// lines are printed directly to UI when it's not set
+	Buffer *LineBuffer
 }
 
 type PodLog struct {
@@ -65,6 +69,23 @@ func (l PodLog) TailAll(ui ui.UI, cancelCh chan struct{}) error {
 		}
 	}
 
+	opts := l.opts
+
+	if opts.Buffer == nil {
+		opts.Buffer = NewLineBuffer(DefaultLineBufferLines, DefaultLineBufferBytes)
+		drainedCh := make(chan struct{})
+
+		go func() {
+			opts.Buffer.Drain()
+			close(drainedCh)
+		}()
+
+		defer func() {
+			opts.Buffer.Close()
+			<-drainedCh
+		}()
+	}
+
 	var wg sync.WaitGroup
 
 	for _, cont := range conts {
@@ -72,7 +93,7 @@ func (l PodLog) TailAll(ui ui.UI, cancelCh chan struct{}) error {
 		wg.Add(1)
 
 		go func() {
-			NewPodContainerLog(l.pod, cont.Name, l.podsClient, l.tagFunc(cont), l.opts).Tail(ui, cancelCh) // TODO err?
+			NewPodContainerLog(l.pod, cont.Name, l.podsClient, l.tagFunc(cont), opts).Tail(ui, cancelCh) // TODO err?
 			wg.Done()
 		}()
 	}
@@ -14,31 +14,37 @@ See the License for the specific language governing permissions and
 limitations under the License.
 */
 
-package service
+package logs
 
 import (
 	"fmt"
 	"sync"
 
 	"github.com/cppforlife/go-cli-ui/ui"
-	"github.com/cppforlife/knctl/pkg/knctl/logs"
+	"github.com/cppforlife/knctl/pkg/knctl/redact"
 	"github.com/knative/serving/pkg/apis/serving"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/client-go/kubernetes"
 )
 
-type podWatcher interface {
+type PodWatcher interface {
 	Watch(podsToWatchCh chan corev1.Pod, cancelCh chan struct{}) error
 }
 
-type LogsView struct {
-	tailOpts   logs.PodLogOpts
-	podWatcher podWatcher
-	coreClient kubernetes.Interface
-	ui         ui.UI
+// View tails user container logs of pods as they are found by pod watcher
+type View struct {
+	tailOpts    PodLogOpts
+	podWatcher  PodWatcher
+	coreClient  kubernetes.Interface
+	ui          ui.UI
+	showSecrets bool
 }
 
-func (v LogsView) Show(cancelCh chan struct{}) error {
+func NewView(tailOpts PodLogOpts, podWatcher PodWatcher, coreClient kubernetes.Interface, ui ui.UI, showSecrets bool) View {
+	return View{tailOpts, podWatcher, coreClient, ui, showSecrets}
+}
+
+func (v View) Show(cancelCh chan struct{}) error {
 	podsToWatchCh := make(chan corev1.Pod)
 	cancelPodTailCh := make(chan struct{})
 	cancelPodWatcherCh := make(chan struct{})
@@ -62,6 +68,18 @@ func (v LogsView) Show(cancelCh chan struct{}) error {
 		close(podsToWatchCh)
 	}()
 
+	// Single bounded buffer for all pods keeps memory use flat
+	// regardless of number of pods and speed of the terminal
+	tailOpts := v.tailOpts
+	tailOpts.Buffer = NewLineBuffer(DefaultLineBufferLines, DefaultLineBufferBytes)
+
+	drainedCh := make(chan struct{})
+
+	go func() {
+		tailOpts.Buffer.Drain()
+		close(drainedCh)
+	}()
+
 	var wg sync.WaitGroup
 
 	for pod := range podsToWatchCh {
@@ -72,16 +90,38 @@ func (v LogsView) Show(cancelCh chan struct{}) error {
 			podsClient := v.coreClient.CoreV1().Pods(pod.Namespace)
 			tag := fmt.Sprintf("%s > %s", pod.Labels[serving.RevisionLabelKey], pod.Name)
 
-			err := logs.NewPodContainerLog(pod, "user-container", podsClient, tag, v.tailOpts).Tail(v.ui, cancelPodTailCh)
+			defer wg.Done()
+
+			podUI, err := v.podUI(pod)
 			if err != nil {
-				v.ui.BeginLinef("Pod logs tailing error: %s\n", err)
+				v.ui.BeginLinef("Pod logs redaction error: %s\n", err)
+				return
 			}
 
-			wg.Done()
+			err = NewPodContainerLog(pod, "user-container", podsClient, tag, tailOpts).Tail(podUI, cancelPodTailCh)
+			if err != nil {
+				v.ui.BeginLinef("Pod logs tailing error: %s\n", err)
+			}
 		}()
 	}
 
 	wg.Wait()
 
+	tailOpts.Buffer.Close()
+	<-drainedCh
+
 	return nil
 }
+
+func (v View) podUI(pod corev1.Pod) (ui.UI, error) {
+	if v.showSecrets {
+		return v.ui, nil
+	}
+
+	secretValues, err := redact.NewSecretValues(v.coreClient).ForPodSpec(pod.Namespace, pod.Spec)
+	if err != nil {
+		return nil, err
+	}
+
+	return redact.NewRedactingUI(v.ui, redact.NewRedactor(secretValues)), nil
+}
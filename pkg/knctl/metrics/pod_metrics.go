@@ -0,0 +1,85 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"encoding/json"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// PodMetrics mirrors subset of metrics.k8s.io/v1beta1 PodMetrics
+// (metrics API client is not vendored)
+type PodMetrics struct {
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Containers []ContainerMetrics `json:"containers"`
+}
+
+type ContainerMetrics struct {
+	Name  string              `json:"name"`
+	Usage corev1.ResourceList `json:"usage"`
+}
+
+type podMetricsList struct {
+	Items []PodMetrics `json:"items"`
+}
+
+// Usage sums up usage across all containers
+func (m PodMetrics) Usage() corev1.ResourceList {
+	result := corev1.ResourceList{}
+
+	for _, cont := range m.Containers {
+		for name, quantity := range cont.Usage {
+			total := result[name]
+			total.Add(quantity)
+			result[name] = total
+		}
+	}
+
+	return result
+}
+
+// PodMetricsSource retrieves pod usage from metrics server
+type PodMetricsSource struct {
+	coreClient kubernetes.Interface
+}
+
+func NewPodMetricsSource(coreClient kubernetes.Interface) PodMetricsSource {
+	return PodMetricsSource{coreClient}
+}
+
+func (s PodMetricsSource) List(namespace string) ([]PodMetrics, error) {
+	path := fmt.Sprintf("/apis/metrics.k8s.io/v1beta1/namespaces/%s/pods", namespace)
+
+	bs, err := s.coreClient.Discovery().RESTClient().Get().AbsPath(path).DoRaw()
+	if err != nil {
+		return nil, fmt.Errorf("Getting pod metrics (is metrics-server installed?): %s", err)
+	}
+
+	var list podMetricsList
+
+	err = json.Unmarshal(bs, &list)
+	if err != nil {
+		return nil, fmt.Errorf("Unmarshaling pod metrics: %s", err)
+	}
+
+	return list.Items, nil
+}
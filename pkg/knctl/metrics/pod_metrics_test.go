@@ -0,0 +1,69 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	. "github.com/cppforlife/knctl/pkg/knctl/metrics"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+)
+
+func TestPodMetricsSource_List(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/apis/metrics.k8s.io/v1beta1/namespaces/ns1/pods" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"kind":"PodMetricsList","items":[{"metadata":{"name":"pod1"},"containers":[
+			{"name":"c1","usage":{"cpu":"150m","memory":"10Mi"}},
+			{"name":"c2","usage":{"cpu":"50m","memory":"6Mi"}}]}]}`))
+	}))
+	defer server.Close()
+
+	coreClient, err := kubernetes.NewForConfig(&rest.Config{Host: server.URL})
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	podMetrics, err := NewPodMetricsSource(coreClient).List("ns1")
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	if len(podMetrics) != 1 || podMetrics[0].Name != "pod1" {
+		t.Fatalf("Expected one pod metrics but was: %#v", podMetrics)
+	}
+
+	usage := podMetrics[0].Usage()
+	cpu := usage[corev1.ResourceCPU]
+	mem := usage[corev1.ResourceMemory]
+
+	if cpu.MilliValue() != 200 || mem.Value() != 16*1024*1024 {
+		t.Fatalf("Expected usage to be summed across containers but was: %#v", usage)
+	}
+
+	_, err = NewPodMetricsSource(coreClient).List("ns2")
+	if err == nil {
+		t.Fatalf("Expected error when metrics are not available")
+	}
+}
@@ -0,0 +1,182 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package notify
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	ctlhistory "github.com/cppforlife/knctl/pkg/knctl/history"
+)
+
+const (
+	requestTimeout = 10 * time.Second
+)
+
+// Summary describes outcome of an operation (e.g. deploy or undo)
+// and is sent as JSON body to notification URLs
+type Summary struct {
+	Time      time.Time `json:"time"`
+	User      string    `json:"user"`
+	Operation string    `json:"operation"`
+	Namespace string    `json:"namespace"`
+	Service   string    `json:"service"`
+
+	Revision         string `json:"revision,omitempty"`
+	PreviousRevision string `json:"previousRevision,omitempty"`
+	Image            string `json:"image,omitempty"`
+	Route            string `json:"route,omitempty"`
+
+	Succeeded bool   `json:"succeeded"`
+	Error     string `json:"error,omitempty"`
+}
+
+func NewSummary(entry ctlhistory.Entry, namespace string, err error) Summary {
+	summary := Summary{
+		Time:      entry.Time,
+		User:      entry.User,
+		Operation: string(entry.Operation),
+		Namespace: namespace,
+		Service:   entry.Service,
+
+		Revision:         entry.Revision,
+		PreviousRevision: entry.PreviousRevision,
+		Image:            entry.Image,
+		Route:            entry.Route,
+
+		Succeeded: err == nil,
+	}
+
+	if err != nil {
+		summary.Error = err.Error()
+	}
+
+	return summary
+}
+
+// Text returns single line description suitable for chat messages
+func (s Summary) Text() string {
+	result := "succeeded"
+	if !s.Succeeded {
+		result = "failed: " + s.Error
+	}
+
+	desc := fmt.Sprintf("knctl %s of service '%s' in namespace '%s' by '%s' %s",
+		s.Operation, s.Service, s.Namespace, s.User, result)
+
+	var details []string
+
+	if len(s.Revision) > 0 {
+		details = append(details, "revision: "+s.Revision)
+	}
+	if len(s.Image) > 0 {
+		details = append(details, "image: "+s.Image)
+	}
+	if len(s.Route) > 0 {
+		details = append(details, "route: "+s.Route)
+	}
+
+	if len(details) > 0 {
+		desc += " (" + strings.Join(details, ", ") + ")"
+	}
+
+	return desc
+}
+
+// Notifier posts summaries to generic webhook URLs (summary as JSON body)
+// and to Slack incoming webhooks (summary as message text)
+type Notifier struct {
+	httpClient    *http.Client
+	urls          []string
+	slackWebhooks []string
+}
+
+func NewNotifier(httpClient *http.Client, urls, slackWebhooks []string) Notifier {
+	// Copied so that timeout does not affect other users of the client
+	client := *httpClient
+	client.Timeout = requestTimeout
+
+	return Notifier{&client, urls, slackWebhooks}
+}
+
+// Notify attempts to post to all URLs. Returned errors do not include
+// full URLs since they commonly contain secrets (e.g. Slack webhooks).
+func (n Notifier) Notify(summary Summary) error {
+	var errs []string
+
+	body, err := json.Marshal(summary)
+	if err != nil {
+		return fmt.Errorf("Marshaling notification: %s", err)
+	}
+
+	for _, u := range n.urls {
+		err := n.post(u, body)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("Notifying '%s': %s", n.host(u), err))
+		}
+	}
+
+	slackBody, err := json.Marshal(map[string]string{"text": summary.Text()})
+	if err != nil {
+		return fmt.Errorf("Marshaling Slack notification: %s", err)
+	}
+
+	for _, u := range n.slackWebhooks {
+		err := n.post(u, slackBody)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("Notifying Slack webhook '%s': %s", n.host(u), err))
+		}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("%s", strings.Join(errs, "; "))
+	}
+
+	return nil
+}
+
+func (n Notifier) post(u string, body []byte) error {
+	resp, err := n.httpClient.Post(u, "application/json", bytes.NewReader(body))
+	if err != nil {
+		// Error includes full URL
+		if urlErr, ok := err.(*url.Error); ok {
+			return urlErr.Err
+		}
+		return err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("Expected successful response status, but was '%s'", resp.Status)
+	}
+
+	return nil
+}
+
+func (Notifier) host(u string) string {
+	parsedURL, err := url.Parse(u)
+	if err != nil {
+		return "<invalid URL>"
+	}
+	return parsedURL.Host
+}
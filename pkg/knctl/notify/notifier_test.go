@@ -0,0 +1,80 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package notify_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	ctlhistory "github.com/cppforlife/knctl/pkg/knctl/history"
+	"github.com/cppforlife/knctl/pkg/knctl/notify"
+)
+
+func TestNotifier(t *testing.T) {
+	var bodies []string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		bs, _ := ioutil.ReadAll(r.Body)
+		bodies = append(bodies, r.URL.Path+" "+string(bs))
+		if r.URL.Path == "/fail/secret" {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer server.Close()
+
+	entry := ctlhistory.Entry{
+		Time:      time.Date(2019, 1, 2, 3, 4, 5, 0, time.UTC),
+		User:      "user1",
+		Operation: ctlhistory.OperationDeploy,
+		Service:   "svc1",
+		Revision:  "svc1-00002",
+		Image:     "img1",
+	}
+
+	summary := notify.NewSummary(entry, "ns1", fmt.Errorf("revision failed"))
+
+	notifier := notify.NewNotifier(http.DefaultClient,
+		[]string{server.URL + "/generic"}, []string{server.URL + "/slack", server.URL + "/fail/secret"})
+
+	err := notifier.Notify(summary)
+	if err == nil || strings.Contains(err.Error(), "secret") ||
+		err.Error() != fmt.Sprintf("Notifying Slack webhook '%s': Expected successful response status, but was '500 Internal Server Error'", server.Listener.Addr()) {
+		t.Fatalf("Expected error without full URL, but was: %v", err)
+	}
+
+	if len(bodies) != 3 {
+		t.Fatalf("Expected 3 requests, but was: %#v", bodies)
+	}
+
+	var genericSummary notify.Summary
+
+	err = json.Unmarshal([]byte(strings.TrimPrefix(bodies[0], "/generic ")), &genericSummary)
+	if err != nil || genericSummary != summary {
+		t.Fatalf("Expected generic webhook to receive summary, but was: %s", bodies[0])
+	}
+
+	expectedSlack := `/slack {"text":"knctl deploy of service 'svc1' in namespace 'ns1' by 'user1' failed: revision failed (revision: svc1-00002, image: img1)"}`
+	if bodies[1] != expectedSlack {
+		t.Fatalf("Expected Slack message to match, but was: %s", bodies[1])
+	}
+}
@@ -0,0 +1,55 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package redact
+
+import (
+	"sort"
+	"strings"
+)
+
+const (
+	RedactedValue = "<redacted>"
+
+	// Very short values (e.g. "1", "on") would mangle unrelated output
+	minRedactedValueLen = 4
+)
+
+type Redactor struct {
+	values []string
+}
+
+func NewRedactor(values []string) Redactor {
+	var result []string
+
+	for _, val := range values {
+		if len(val) >= minRedactedValueLen {
+			result = append(result, val)
+		}
+	}
+
+	// Replace longest values first so that overlapping values are fully masked
+	sort.Slice(result, func(i, j int) bool { return len(result[i]) > len(result[j]) })
+
+	return Redactor{result}
+}
+
+func (r Redactor) Redact(str string) string {
+	for _, val := range r.values {
+		str = strings.Replace(str, val, RedactedValue, -1)
+	}
+	return str
+}
@@ -0,0 +1,34 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package redact_test
+
+import (
+	"testing"
+
+	"github.com/cppforlife/knctl/pkg/knctl/redact"
+)
+
+func TestRedactor(t *testing.T) {
+	redactor := redact.NewRedactor([]string{"", "on", "secret", "secret-longer"})
+
+	result := redactor.Redact("value secret-longer and secret, but not on")
+	expected := "value <redacted> and <redacted>, but not on"
+
+	if result != expected {
+		t.Fatalf("Expected redacted string '%s' to equal '%s'", result, expected)
+	}
+}
@@ -0,0 +1,207 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package redact
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/cppforlife/knctl/pkg/knctl/util"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// SecretValues collects values of Secrets referenced by pods and
+// service accounts so that they could be masked in output.
+// Secrets that cannot be read (missing or not permitted) are skipped.
+type SecretValues struct {
+	coreClient kubernetes.Interface
+	cache      *secretsCache
+}
+
+type secretsCache struct {
+	lock    sync.Mutex
+	secrets map[string]*corev1.Secret // nil value for unreadable secrets
+}
+
+func NewSecretValues(coreClient kubernetes.Interface) SecretValues {
+	return SecretValues{coreClient, &secretsCache{secrets: map[string]*corev1.Secret{}}}
+}
+
+func (v SecretValues) ForPodSpec(namespace string, spec corev1.PodSpec) ([]string, error) {
+	var result []string
+
+	conts := append(spec.InitContainers, spec.Containers...)
+
+	err := v.prefetch(namespace, v.containersSecretNames(conts))
+	if err != nil {
+		return nil, err
+	}
+
+	for _, cont := range conts {
+		vals, err := v.ForContainer(namespace, cont)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, vals...)
+	}
+
+	return result, nil
+}
+
+func (v SecretValues) ForContainer(namespace string, cont corev1.Container) ([]string, error) {
+	var result []string
+
+	for _, env := range cont.Env {
+		if env.ValueFrom == nil || env.ValueFrom.SecretKeyRef == nil {
+			continue
+		}
+
+		val, found, err := v.Value(namespace, *env.ValueFrom.SecretKeyRef)
+		if err != nil {
+			return nil, err
+		}
+		if found {
+			result = append(result, val)
+		}
+	}
+
+	for _, envFrom := range cont.EnvFrom {
+		if envFrom.SecretRef == nil {
+			continue
+		}
+
+		secret, found, err := v.secret(namespace, envFrom.SecretRef.Name)
+		if err != nil {
+			return nil, err
+		}
+		if found {
+			for _, val := range secret.Data {
+				result = append(result, strings.TrimSpace(string(val)))
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// ForServiceAccount returns passwords of basic auth secrets
+// associated with a service account (e.g. used by builds)
+func (v SecretValues) ForServiceAccount(namespace, name string) ([]string, error) {
+	if len(name) == 0 {
+		return nil, nil
+	}
+
+	sa, err := v.coreClient.CoreV1().ServiceAccounts(namespace).Get(name, metav1.GetOptions{})
+	if err != nil {
+		if errors.IsNotFound(err) || errors.IsForbidden(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("Getting service account '%s': %s", name, err)
+	}
+
+	var result []string
+
+	for _, ref := range sa.Secrets {
+		secret, found, err := v.secret(namespace, ref.Name)
+		if err != nil {
+			return nil, err
+		}
+		if found && secret.Type == corev1.SecretTypeBasicAuth {
+			result = append(result, strings.TrimSpace(string(secret.Data[corev1.BasicAuthPasswordKey])))
+		}
+	}
+
+	return result, nil
+}
+
+func (v SecretValues) Value(namespace string, ref corev1.SecretKeySelector) (string, bool, error) {
+	secret, found, err := v.secret(namespace, ref.Name)
+	if err != nil || !found {
+		return "", false, err
+	}
+
+	val, found := secret.Data[ref.Key]
+
+	return strings.TrimSpace(string(val)), found, nil
+}
+
+func (v SecretValues) containersSecretNames(conts []corev1.Container) []string {
+	var names []string
+
+	for _, cont := range conts {
+		for _, env := range cont.Env {
+			if env.ValueFrom != nil && env.ValueFrom.SecretKeyRef != nil {
+				names = append(names, env.ValueFrom.SecretKeyRef.Name)
+			}
+		}
+		for _, envFrom := range cont.EnvFrom {
+			if envFrom.SecretRef != nil {
+				names = append(names, envFrom.SecretRef.Name)
+			}
+		}
+	}
+
+	return names
+}
+
+// prefetch concurrently fetches secrets that were not fetched before
+func (v SecretValues) prefetch(namespace string, names []string) error {
+	var uniqNames []string
+	seen := map[string]struct{}{}
+
+	for _, name := range names {
+		if _, found := seen[name]; !found {
+			seen[name] = struct{}{}
+			uniqNames = append(uniqNames, name)
+		}
+	}
+
+	return util.NewParallel(util.DefaultParallelism).Run(len(uniqNames), func(i int) error {
+		_, _, err := v.secret(namespace, uniqNames[i])
+		return err
+	})
+}
+
+func (v SecretValues) secret(namespace, name string) (*corev1.Secret, bool, error) {
+	cacheKey := namespace + "/" + name
+
+	v.cache.lock.Lock()
+	secret, found := v.cache.secrets[cacheKey]
+	v.cache.lock.Unlock()
+
+	if found {
+		return secret, secret != nil, nil
+	}
+
+	secret, err := v.coreClient.CoreV1().Secrets(namespace).Get(name, metav1.GetOptions{})
+	if err != nil {
+		if !errors.IsNotFound(err) && !errors.IsForbidden(err) {
+			return nil, false, fmt.Errorf("Getting secret '%s': %s", name, err)
+		}
+		secret = nil
+	}
+
+	v.cache.lock.Lock()
+	v.cache.secrets[cacheKey] = secret
+	v.cache.lock.Unlock()
+
+	return secret, secret != nil, nil
+}
@@ -0,0 +1,140 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package redact
+
+import (
+	"fmt"
+
+	"github.com/cppforlife/go-cli-ui/ui"
+	. "github.com/cppforlife/go-cli-ui/ui/table"
+)
+
+// RedactingUI masks redacted values in printed lines, blocks and tables
+type RedactingUI struct {
+	parent   ui.UI
+	redactor Redactor
+}
+
+var _ ui.UI = &RedactingUI{}
+
+func NewRedactingUI(parent ui.UI, redactor Redactor) *RedactingUI {
+	return &RedactingUI{parent: parent, redactor: redactor}
+}
+
+func (ui *RedactingUI) ErrorLinef(pattern string, args ...interface{}) {
+	ui.parent.ErrorLinef("%s", ui.redactor.Redact(fmt.Sprintf(pattern, args...)))
+}
+
+func (ui *RedactingUI) PrintLinef(pattern string, args ...interface{}) {
+	ui.parent.PrintLinef("%s", ui.redactor.Redact(fmt.Sprintf(pattern, args...)))
+}
+
+func (ui *RedactingUI) BeginLinef(pattern string, args ...interface{}) {
+	ui.parent.BeginLinef("%s", ui.redactor.Redact(fmt.Sprintf(pattern, args...)))
+}
+
+func (ui *RedactingUI) EndLinef(pattern string, args ...interface{}) {
+	ui.parent.EndLinef("%s", ui.redactor.Redact(fmt.Sprintf(pattern, args...)))
+}
+
+func (ui *RedactingUI) PrintBlock(block []byte) {
+	ui.parent.PrintBlock([]byte(ui.redactor.Redact(string(block))))
+}
+
+func (ui *RedactingUI) PrintErrorBlock(block string) {
+	ui.parent.PrintErrorBlock(ui.redactor.Redact(block))
+}
+
+func (ui *RedactingUI) PrintTable(table Table) {
+	table.Title = ui.redactor.Redact(table.Title)
+	table.Content = ui.redactor.Redact(table.Content)
+	table.Rows = ui.redactRows(table.Rows)
+
+	var sections []Section
+
+	for _, section := range table.Sections {
+		if section.FirstColumn != nil {
+			section.FirstColumn = ui.redactValue(section.FirstColumn)
+		}
+		section.Rows = ui.redactRows(section.Rows)
+		sections = append(sections, section)
+	}
+
+	table.Sections = sections
+
+	var notes []string
+
+	for _, note := range table.Notes {
+		notes = append(notes, ui.redactor.Redact(note))
+	}
+
+	table.Notes = notes
+
+	ui.parent.PrintTable(table)
+}
+
+// redactRows copies rows so that caller's table is not modified
+func (ui *RedactingUI) redactRows(rows [][]Value) [][]Value {
+	var result [][]Value
+
+	for _, row := range rows {
+		var redactedRow []Value
+		for _, val := range row {
+			redactedRow = append(redactedRow, ui.redactValue(val))
+		}
+		result = append(result, redactedRow)
+	}
+
+	return result
+}
+
+// redactValue keeps original value (and hence its sorting and formatting)
+// unless its string representation includes redacted values
+func (ui *RedactingUI) redactValue(val Value) Value {
+	str := val.String()
+	redactedStr := ui.redactor.Redact(str)
+
+	if redactedStr == str {
+		return val
+	}
+
+	return NewValueString(redactedStr)
+}
+
+func (ui *RedactingUI) AskForText(label string) (string, error) {
+	return ui.parent.AskForText(label)
+}
+
+func (ui *RedactingUI) AskForChoice(label string, options []string) (int, error) {
+	return ui.parent.AskForChoice(label, options)
+}
+
+func (ui *RedactingUI) AskForPassword(label string) (string, error) {
+	return ui.parent.AskForPassword(label)
+}
+
+func (ui *RedactingUI) AskForConfirmation() error {
+	return ui.parent.AskForConfirmation()
+}
+
+func (ui *RedactingUI) IsInteractive() bool {
+	return ui.parent.IsInteractive()
+}
+
+func (ui *RedactingUI) Flush() {
+	ui.parent.Flush()
+}
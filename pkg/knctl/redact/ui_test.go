@@ -0,0 +1,64 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package redact_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/cppforlife/go-cli-ui/ui"
+	uitable "github.com/cppforlife/go-cli-ui/ui/table"
+	"github.com/cppforlife/knctl/pkg/knctl/redact"
+)
+
+func TestRedactingUI_PrintTable(t *testing.T) {
+	var out bytes.Buffer
+
+	writerUI := ui.NewWriterUI(&out, &out, ui.NewNoopLogger())
+	redactingUI := redact.NewRedactingUI(writerUI, redact.NewRedactor([]string{"secret1"}))
+
+	rows := [][]uitable.Value{
+		{uitable.NewValueString("pod1"), uitable.NewValueString("password=secret1")},
+		{uitable.NewValueString("pod2"), uitable.NewValueInt(1)},
+	}
+
+	redactingUI.PrintTable(uitable.Table{
+		Content: "logs",
+		Header:  []uitable.Header{uitable.NewHeader("Name"), uitable.NewHeader("Value")},
+		Rows:    rows,
+		Sections: []uitable.Section{{
+			FirstColumn: uitable.NewValueString("section-secret1"),
+			Rows:        [][]uitable.Value{{uitable.NewValueString("pod3"), uitable.NewValueString("token secret1")}},
+		}},
+		Notes: []string{"note secret1"},
+	})
+
+	if strings.Contains(out.String(), "secret1") {
+		t.Fatalf("Expected table to not include secret value, but was: %s", out.String())
+	}
+
+	for _, expected := range []string{"password=<redacted>", "section-<redacted>", "token <redacted>", "note <redacted>", "pod2"} {
+		if !strings.Contains(out.String(), expected) {
+			t.Fatalf("Expected table to include '%s', but was: %s", expected, out.String())
+		}
+	}
+
+	if rows[0][1].String() != "password=secret1" {
+		t.Fatalf("Expected original table rows to not be modified")
+	}
+}
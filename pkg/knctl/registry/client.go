@@ -0,0 +1,258 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package registry
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+const (
+	maxManifestSize = 4 * 1024 * 1024
+	maxBlobSize     = 16 * 1024 * 1024
+)
+
+var (
+	manifestMediaTypes = []string{
+		"application/vnd.oci.image.index.v1+json",
+		"application/vnd.oci.image.manifest.v1+json",
+		"application/vnd.docker.distribution.manifest.list.v2+json",
+		"application/vnd.docker.distribution.manifest.v2+json",
+	}
+
+	challengeParamRegexp = regexp.MustCompile(`(\w+)="([^"]*)"`)
+)
+
+// Client talks to registries via Docker Registry HTTP API V2.
+// Credentials from keychain are used for basic and bearer token authentication;
+// anonymous access is used for registries without credentials.
+type Client struct {
+	httpClient *http.Client
+	keychain   Keychain
+}
+
+func NewClient(httpClient *http.Client) Client {
+	return Client{httpClient: httpClient}
+}
+
+func NewAuthenticatedClient(httpClient *http.Client, keychain Keychain) Client {
+	return Client{httpClient, keychain}
+}
+
+// Digest resolves reference to manifest digest without downloading the manifest
+func (c Client) Digest(ref Reference) (string, error) {
+	if len(ref.Digest) > 0 {
+		return ref.Digest, nil
+	}
+
+	resp, err := c.request("HEAD", ref, ref.apiURL("manifests/"+ref.Tag), manifestMediaTypes)
+	if err != nil {
+		return "", err
+	}
+
+	resp.Body.Close()
+
+	digest := resp.Header.Get("Docker-Content-Digest")
+	if len(digest) == 0 {
+		return "", fmt.Errorf("Expected registry to return digest for image '%s'", ref)
+	}
+
+	return digest, nil
+}
+
+// Manifest returns manifest for a tag or a digest
+func (c Client) Manifest(ref Reference, tagOrDigest string) ([]byte, error) {
+	return c.get(ref, ref.apiURL("manifests/"+tagOrDigest), manifestMediaTypes, maxManifestSize)
+}
+
+func (c Client) Blob(ref Reference, digest string) ([]byte, error) {
+	return c.get(ref, ref.apiURL("blobs/"+digest), nil, maxBlobSize)
+}
+
+// NotFoundError is returned when manifest or blob does not exist
+type NotFoundError struct {
+	URL string
+}
+
+func (e NotFoundError) Error() string {
+	return fmt.Sprintf("Expected '%s' to exist in registry", e.URL)
+}
+
+func (c Client) get(ref Reference, url string, accept []string, maxSize int64) ([]byte, error) {
+	resp, err := c.request("GET", ref, url, accept)
+	if err != nil {
+		return nil, err
+	}
+
+	defer resp.Body.Close()
+
+	bs, err := ioutil.ReadAll(io.LimitReader(resp.Body, maxSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("Reading '%s': %s", url, err)
+	}
+
+	if int64(len(bs)) > maxSize {
+		return nil, fmt.Errorf("Expected '%s' to be at most %d bytes", url, maxSize)
+	}
+
+	return bs, nil
+}
+
+func (c Client) request(method string, ref Reference, url string, accept []string) (*http.Response, error) {
+	resp, err := c.do(method, url, accept, "")
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode == http.StatusUnauthorized {
+		challenge := resp.Header.Get("WWW-Authenticate")
+		resp.Body.Close()
+
+		authorization, err := c.authorization(challenge, ref)
+		if err != nil {
+			return nil, err
+		}
+
+		resp, err = c.do(method, url, accept, authorization)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		resp.Body.Close()
+		return nil, NotFoundError{url}
+
+	case resp.StatusCode < 200 || resp.StatusCode >= 300:
+		resp.Body.Close()
+		return nil, fmt.Errorf("Requesting '%s': registry responded with '%s'", url, resp.Status)
+	}
+
+	return resp, nil
+}
+
+func (c Client) do(method, url string, accept []string, authorization string) (*http.Response, error) {
+	req, err := http.NewRequest(method, url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(accept) > 0 {
+		req.Header.Set("Accept", strings.Join(accept, ", "))
+	}
+	if len(authorization) > 0 {
+		req.Header.Set("Authorization", authorization)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("Requesting '%s': %s", url, err)
+	}
+
+	return resp, nil
+}
+
+// authorization returns Authorization header value that satisfies challenge
+func (c Client) authorization(challenge string, ref Reference) (string, error) {
+	auth, hasAuth := c.keychain.Resolve(ref.Registry)
+
+	switch {
+	case strings.HasPrefix(strings.ToLower(challenge), "bearer "):
+		token, err := c.token(challenge, ref, auth, hasAuth)
+		if err != nil {
+			return "", err
+		}
+		return "Bearer " + token, nil
+
+	case strings.HasPrefix(strings.ToLower(challenge), "basic") && hasAuth:
+		return "Basic " + c.basicAuth(auth), nil
+
+	default:
+		return "", fmt.Errorf("Expected registry '%s' to allow anonymous access or to have credentials "+
+			"in image pull secrets (only basic and bearer token authentication is supported)", ref.Registry)
+	}
+}
+
+// token obtains pull token (anonymous unless credentials are available) as described by
+// https://docs.docker.com/registry/spec/auth/token/
+func (c Client) token(challenge string, ref Reference, auth Auth, hasAuth bool) (string, error) {
+	params := map[string]string{}
+
+	for _, match := range challengeParamRegexp.FindAllStringSubmatch(challenge, -1) {
+		params[match[1]] = match[2]
+	}
+
+	realm, err := url.Parse(params["realm"])
+	if err != nil || len(params["realm"]) == 0 {
+		return "", fmt.Errorf("Expected registry '%s' to provide token realm", ref.Registry)
+	}
+
+	query := realm.Query()
+	if len(params["service"]) > 0 {
+		query.Set("service", params["service"])
+	}
+	query.Set("scope", fmt.Sprintf("repository:%s:pull", ref.Repository))
+	realm.RawQuery = query.Encode()
+
+	req, err := http.NewRequest("GET", realm.String(), nil)
+	if err != nil {
+		return "", err
+	}
+
+	if hasAuth {
+		req.Header.Set("Authorization", "Basic "+c.basicAuth(auth))
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("Requesting registry token: %s", err)
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("Requesting registry token: token service responded with '%s'", resp.Status)
+	}
+
+	var tokenResp struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}
+
+	err = json.NewDecoder(resp.Body).Decode(&tokenResp)
+	if err != nil {
+		return "", fmt.Errorf("Unmarshaling registry token: %s", err)
+	}
+
+	if len(tokenResp.Token) > 0 {
+		return tokenResp.Token, nil
+	}
+
+	return tokenResp.AccessToken, nil
+}
+
+func (Client) basicAuth(auth Auth) string {
+	return base64.StdEncoding.EncodeToString([]byte(auth.Username + ":" + auth.Password))
+}
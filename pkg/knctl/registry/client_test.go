@@ -0,0 +1,163 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package registry_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/cppforlife/knctl/pkg/knctl/registry"
+)
+
+func TestClientDigestWithAnonymousToken(t *testing.T) {
+	var server *httptest.Server
+
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/token":
+			if r.URL.Query().Get("scope") != "repository:proj/app:pull" {
+				t.Fatalf("Expected pull scope, but was '%s'", r.URL.Query().Get("scope"))
+			}
+			fmt.Fprintf(w, `{"token":"tok1"}`)
+
+		case r.Header.Get("Authorization") != "Bearer tok1":
+			w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer realm="%s/token",service="reg"`, server.URL))
+			w.WriteHeader(http.StatusUnauthorized)
+
+		case r.Method == "HEAD" && r.URL.Path == "/v2/proj/app/manifests/v1":
+			w.Header().Set("Docker-Content-Digest", "sha256:abc")
+
+		case r.URL.Path == "/v2/proj/app/blobs/sha256:def":
+			fmt.Fprintf(w, "blob-content")
+
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+
+	defer server.Close()
+
+	ref, err := registry.ParseReference(strings.TrimPrefix(server.URL, "http://") + "/proj/app:v1")
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	client := registry.NewClient(http.DefaultClient)
+
+	digest, err := client.Digest(ref)
+	if err != nil || digest != "sha256:abc" {
+		t.Fatalf("Expected digest to resolve: '%s' %v", digest, err)
+	}
+
+	blob, err := client.Blob(ref, "sha256:def")
+	if err != nil || string(blob) != "blob-content" {
+		t.Fatalf("Expected blob to be fetched: '%s' %v", blob, err)
+	}
+
+	_, err = client.Manifest(ref, "missing")
+	if _, ok := err.(registry.NotFoundError); !ok {
+		t.Fatalf("Expected not found error, but was: %v", err)
+	}
+}
+
+func TestClientDigestWithCredentials(t *testing.T) {
+	var server *httptest.Server
+
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/token":
+			if user, pass, _ := r.BasicAuth(); user != "user1" || pass != "pass1" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			fmt.Fprintf(w, `{"token":"tok1"}`)
+
+		case r.Header.Get("Authorization") != "Bearer tok1":
+			w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer realm="%s/token",service="reg"`, server.URL))
+			w.WriteHeader(http.StatusUnauthorized)
+
+		case r.Method == "HEAD" && r.URL.Path == "/v2/proj/app/manifests/v1":
+			w.Header().Set("Docker-Content-Digest", "sha256:abc")
+
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+
+	defer server.Close()
+
+	host := strings.TrimPrefix(server.URL, "http://")
+
+	ref, err := registry.ParseReference(host + "/proj/app:v1")
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	_, err = registry.NewClient(http.DefaultClient).Digest(ref)
+	if err == nil {
+		t.Fatalf("Expected anonymous access to fail")
+	}
+
+	var keychain registry.Keychain
+
+	err = keychain.AddDockerConfigJSON([]byte(fmt.Sprintf(
+		`{"auths":{"%s":{"username":"user1","password":"pass1"}}}`, host)))
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	digest, err := registry.NewAuthenticatedClient(http.DefaultClient, keychain).Digest(ref)
+	if err != nil || digest != "sha256:abc" {
+		t.Fatalf("Expected digest to resolve: '%s' %v", digest, err)
+	}
+}
+
+func TestClientDigestWithBasicAuth(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if user, pass, _ := r.BasicAuth(); user != "user1" || pass != "pass1" {
+			w.Header().Set("WWW-Authenticate", `Basic realm="reg"`)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("Docker-Content-Digest", "sha256:abc")
+	}))
+
+	defer server.Close()
+
+	host := strings.TrimPrefix(server.URL, "http://")
+
+	ref, err := registry.ParseReference(host + "/proj/app:v1")
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	var keychain registry.Keychain
+
+	err = keychain.AddDockerConfigJSON([]byte(fmt.Sprintf(
+		`{"auths":{"http://%s":{"auth":"dXNlcjE6cGFzczE="}}}`, host)))
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	digest, err := registry.NewAuthenticatedClient(http.DefaultClient, keychain).Digest(ref)
+	if err != nil || digest != "sha256:abc" {
+		t.Fatalf("Expected digest to resolve: '%s' %v", digest, err)
+	}
+}
@@ -0,0 +1,118 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package registry
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+type Auth struct {
+	Username string
+	Password string
+}
+
+// Keychain holds registry credentials (e.g. found in image pull secrets)
+type Keychain struct {
+	auths map[string]Auth
+}
+
+type dockerConfigAuth struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+	Auth     string `json:"auth"`
+}
+
+// AddDockerConfigJSON adds credentials from 'kubernetes.io/dockerconfigjson' secret content
+func (k *Keychain) AddDockerConfigJSON(bs []byte) error {
+	var config struct {
+		Auths map[string]dockerConfigAuth `json:"auths"`
+	}
+
+	err := json.Unmarshal(bs, &config)
+	if err != nil {
+		return fmt.Errorf("Unmarshaling docker config: %s", err)
+	}
+
+	return k.add(config.Auths)
+}
+
+// AddDockerConfig adds credentials from legacy 'kubernetes.io/dockercfg' secret content
+func (k *Keychain) AddDockerConfig(bs []byte) error {
+	var auths map[string]dockerConfigAuth
+
+	err := json.Unmarshal(bs, &auths)
+	if err != nil {
+		return fmt.Errorf("Unmarshaling docker config: %s", err)
+	}
+
+	return k.add(auths)
+}
+
+// Resolve returns credentials for a registry (first added credentials win)
+func (k Keychain) Resolve(registry string) (Auth, bool) {
+	auth, found := k.auths[k.normalizeRegistry(registry)]
+	return auth, found
+}
+
+func (k *Keychain) add(auths map[string]dockerConfigAuth) error {
+	if k.auths == nil {
+		k.auths = map[string]Auth{}
+	}
+
+	for registry, configAuth := range auths {
+		auth := Auth{Username: configAuth.Username, Password: configAuth.Password}
+
+		if len(auth.Username) == 0 && len(configAuth.Auth) > 0 {
+			decoded, err := base64.StdEncoding.DecodeString(configAuth.Auth)
+			if err != nil {
+				return fmt.Errorf("Decoding auth for registry '%s': %s", registry, err)
+			}
+
+			pieces := strings.SplitN(string(decoded), ":", 2)
+			if len(pieces) != 2 {
+				return fmt.Errorf("Expected auth for registry '%s' to be in 'username:password' format", registry)
+			}
+
+			auth = Auth{Username: pieces[0], Password: pieces[1]}
+		}
+
+		registry = k.normalizeRegistry(registry)
+
+		if _, found := k.auths[registry]; !found {
+			k.auths[registry] = auth
+		}
+	}
+
+	return nil
+}
+
+// normalizeRegistry converts docker config keys (e.g. 'https://index.docker.io/v1/')
+// to registry hosts used in references
+func (Keychain) normalizeRegistry(registry string) string {
+	registry = strings.TrimPrefix(registry, "https://")
+	registry = strings.TrimPrefix(registry, "http://")
+	registry = strings.SplitN(registry, "/", 2)[0]
+
+	if registry == "docker.io" || registry == dockerHubAPIRegistry {
+		registry = dockerHubRegistry
+	}
+
+	return registry
+}
@@ -0,0 +1,64 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package registry_test
+
+import (
+	"testing"
+
+	"github.com/cppforlife/knctl/pkg/knctl/registry"
+)
+
+func TestKeychainResolve(t *testing.T) {
+	var keychain registry.Keychain
+
+	err := keychain.AddDockerConfigJSON([]byte(`{"auths":{
+		"https://index.docker.io/v1/": {"auth": "dXNlcjE6cGFzczE="},
+		"gcr.io": {"username": "user2", "password": "pass2"}
+	}}`))
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	err = keychain.AddDockerConfig([]byte(`{"gcr.io": {"username": "user3", "password": "pass3"}}`))
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	auth, found := keychain.Resolve("index.docker.io")
+	if !found || auth != (registry.Auth{Username: "user1", Password: "pass1"}) {
+		t.Fatalf("Expected Docker Hub auth to be found, but was: %#v", auth)
+	}
+
+	auth, found = keychain.Resolve("gcr.io")
+	if !found || auth != (registry.Auth{Username: "user2", Password: "pass2"}) {
+		t.Fatalf("Expected first added auth to win, but was: %#v", auth)
+	}
+
+	_, found = keychain.Resolve("quay.io")
+	if found {
+		t.Fatalf("Expected auth to not be found")
+	}
+}
+
+func TestKeychainInvalidAuth(t *testing.T) {
+	var keychain registry.Keychain
+
+	err := keychain.AddDockerConfigJSON([]byte(`{"auths":{"gcr.io": {"auth": "bm8tY29sb24="}}}`))
+	if err == nil {
+		t.Fatalf("Expected error for auth without password")
+	}
+}
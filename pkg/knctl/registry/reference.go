@@ -0,0 +1,116 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package registry
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	dockerHubRegistry    = "index.docker.io"
+	dockerHubAPIRegistry = "registry-1.docker.io"
+	defaultTag           = "latest"
+)
+
+// Reference identifies image in a registry (e.g. gcr.io/project/app:v1)
+type Reference struct {
+	Registry   string
+	Repository string
+	Tag        string
+	Digest     string
+}
+
+func ParseReference(image string) (Reference, error) {
+	var ref Reference
+
+	name := image
+
+	if pieces := strings.SplitN(name, "@", 2); len(pieces) == 2 {
+		name = pieces[0]
+		ref.Digest = pieces[1]
+
+		if !strings.HasPrefix(ref.Digest, "sha256:") {
+			return Reference{}, fmt.Errorf("Expected image '%s' digest to start with 'sha256:'", image)
+		}
+	}
+
+	if idx := strings.LastIndex(name, ":"); idx > strings.LastIndex(name, "/") {
+		ref.Tag = name[idx+1:]
+		name = name[:idx]
+	}
+
+	pieces := strings.SplitN(name, "/", 2)
+
+	if len(pieces) == 2 && (strings.ContainsAny(pieces[0], ".:") || pieces[0] == "localhost") {
+		ref.Registry = pieces[0]
+		ref.Repository = pieces[1]
+	} else {
+		ref.Registry = dockerHubRegistry
+		ref.Repository = name
+	}
+
+	if ref.Registry == "docker.io" {
+		ref.Registry = dockerHubRegistry
+	}
+
+	if ref.Registry == dockerHubRegistry && !strings.Contains(ref.Repository, "/") {
+		ref.Repository = "library/" + ref.Repository
+	}
+
+	if len(ref.Repository) == 0 {
+		return Reference{}, fmt.Errorf("Expected image '%s' to include repository", image)
+	}
+
+	if len(ref.Tag) == 0 && len(ref.Digest) == 0 {
+		ref.Tag = defaultTag
+	}
+
+	return ref, nil
+}
+
+// WithDigest returns reference pinned to given digest (tag is dropped)
+func (r Reference) WithDigest(digest string) Reference {
+	return Reference{Registry: r.Registry, Repository: r.Repository, Digest: digest}
+}
+
+func (r Reference) String() string {
+	result := r.Registry + "/" + r.Repository
+	if len(r.Tag) > 0 {
+		result += ":" + r.Tag
+	}
+	if len(r.Digest) > 0 {
+		result += "@" + r.Digest
+	}
+	return result
+}
+
+func (r Reference) apiURL(path string) string {
+	scheme := "https"
+	host := r.Registry
+
+	if host == dockerHubRegistry {
+		host = dockerHubAPIRegistry
+	}
+
+	// Local registries are typically not served over TLS
+	if strings.HasPrefix(host, "localhost") || strings.HasPrefix(host, "127.0.0.1") {
+		scheme = "http"
+	}
+
+	return fmt.Sprintf("%s://%s/v2/%s/%s", scheme, host, r.Repository, path)
+}
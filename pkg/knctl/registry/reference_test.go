@@ -0,0 +1,60 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package registry_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/cppforlife/knctl/pkg/knctl/registry"
+)
+
+func TestParseReference(t *testing.T) {
+	examples := map[string]registry.Reference{
+		"nginx":                        {Registry: "index.docker.io", Repository: "library/nginx", Tag: "latest"},
+		"docker.io/user/app:v1":        {Registry: "index.docker.io", Repository: "user/app", Tag: "v1"},
+		"gcr.io/proj/app@sha256:abc":   {Registry: "gcr.io", Repository: "proj/app", Digest: "sha256:abc"},
+		"localhost:5000/app:v1":        {Registry: "localhost:5000", Repository: "app", Tag: "v1"},
+		"reg.io:443/a/b:v2@sha256:abc": {Registry: "reg.io:443", Repository: "a/b", Tag: "v2", Digest: "sha256:abc"},
+	}
+
+	for image, expectedRef := range examples {
+		ref, err := registry.ParseReference(image)
+		if err != nil {
+			t.Fatalf("Expected parsing '%s' to succeed: %s", image, err)
+		}
+		if !reflect.DeepEqual(ref, expectedRef) {
+			t.Fatalf("Expected '%s' to parse as %#v, but was %#v", image, expectedRef, ref)
+		}
+	}
+
+	_, err := registry.ParseReference("gcr.io/proj/app@md5:abc")
+	if err == nil {
+		t.Fatalf("Expected non-sha256 digest to fail")
+	}
+}
+
+func TestReferenceWithDigest(t *testing.T) {
+	ref, err := registry.ParseReference("gcr.io/proj/app:v1")
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	if ref.WithDigest("sha256:abc").String() != "gcr.io/proj/app@sha256:abc" {
+		t.Fatalf("Expected digest reference, but was '%s'", ref.WithDigest("sha256:abc"))
+	}
+}
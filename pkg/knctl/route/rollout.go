@@ -0,0 +1,188 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package route
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	ctlservice "github.com/cppforlife/knctl/pkg/knctl/service"
+	"github.com/cppforlife/knctl/pkg/knctl/util"
+	"github.com/knative/serving/pkg/apis/serving/v1alpha1"
+	servingclientset "github.com/knative/serving/pkg/client/clientset/versioned"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type Rollout struct {
+	servingClient servingclientset.Interface
+}
+
+func NewRollout(servingClient servingclientset.Interface) Rollout {
+	return Rollout{servingClient}
+}
+
+// Targets converts 'name=percentage' pairs into traffic targets;
+// when serviceName is specified revision names reference tags of that service
+func (r Rollout) Targets(namespace, serviceName string, revisionPercentages, servicePercentages []string) ([]v1alpha1.TrafficTarget, error) {
+	revRef := ctlservice.NewRevisionReference(ctlservice.NewTags(r.servingClient), r.servingClient)
+
+	var targets []v1alpha1.TrafficTarget
+
+	for _, traffic := range revisionPercentages {
+		name, percent, err := ExtractNameAndPercentage(traffic)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(serviceName) > 0 {
+			name = serviceName + ":" + name
+		}
+
+		revision, err := revRef.Revision(namespace, name)
+		if err != nil {
+			return nil, err
+		}
+
+		targets = append(targets, v1alpha1.TrafficTarget{
+			RevisionName: revision.Name,
+			Percent:      percent,
+		})
+	}
+
+	for _, traffic := range servicePercentages {
+		name, percent, err := ExtractNameAndPercentage(traffic)
+		if err != nil {
+			return nil, err
+		}
+
+		targets = append(targets, v1alpha1.TrafficTarget{
+			ConfigurationName: name,
+			Percent:           percent,
+		})
+	}
+
+	return targets, nil
+}
+
+// Apply creates or updates route and returns its previous traffic
+func (r Rollout) Apply(route *v1alpha1.Route, cancelCh chan struct{}) ([]v1alpha1.TrafficTarget, error) {
+	err := r.ensureUnmanagedRouteOnService(route.Namespace, route.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	var prevTraffic []v1alpha1.TrafficTarget
+
+	if len(route.Name) > 0 {
+		prevRoute, err := r.servingClient.ServingV1alpha1().Routes(route.Namespace).Get(route.Name, metav1.GetOptions{})
+		if err == nil {
+			prevTraffic = prevRoute.Spec.Traffic
+		} else if !errors.IsNotFound(err) {
+			return nil, fmt.Errorf("Getting route: %s", err)
+		}
+	}
+
+	err = r.createOrUpdate(route, cancelCh)
+	if err != nil {
+		return nil, err
+	}
+
+	return prevTraffic, nil
+}
+
+func ExtractNameAndPercentage(str string) (string, int, error) {
+	pieces := strings.SplitN(str, "=", 2)
+	if len(pieces) != 2 {
+		return "", 0, fmt.Errorf("Expected percentage to be in format 'service=percentage'")
+	}
+
+	percent, err := strconv.Atoi(strings.TrimSuffix(pieces[1], "%"))
+	if err != nil {
+		return "", 0, fmt.Errorf("Expected percentage value to be an integer")
+	}
+
+	if percent < 0 || percent > 100 {
+		return "", 0, fmt.Errorf("Expected percentage value to be between 0%% and 100%%")
+	}
+
+	return pieces[0], percent, nil
+}
+
+func (r Rollout) ensureUnmanagedRouteOnService(namespace, routeName string) error {
+	if len(routeName) == 0 {
+		return nil
+	}
+
+	// Assumes that service has the same name as the route
+	// TODO this may not be a proper assumption to make
+	service, err := r.servingClient.ServingV1alpha1().Services(namespace).Get(routeName, metav1.GetOptions{})
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		return fmt.Errorf("Getting associated service: %s", err)
+	}
+
+	if service.Spec.Manual == nil {
+		hintMsg := "(use `--managed-route=false` flag when running `deploy` command)"
+		return fmt.Errorf("Expected associated service '%s' to not manage route %s", routeName, hintMsg)
+	}
+
+	return nil
+}
+
+func (r Rollout) createOrUpdate(route *v1alpha1.Route, cancelCh chan struct{}) error {
+	_, createErr := r.servingClient.ServingV1alpha1().Routes(route.Namespace).Create(route)
+	if createErr != nil {
+		if errors.IsAlreadyExists(createErr) {
+			return r.update(route, cancelCh)
+		}
+
+		return fmt.Errorf("Creating route: %s", createErr)
+	}
+
+	return nil
+}
+
+func (r Rollout) update(route *v1alpha1.Route, cancelCh chan struct{}) error {
+	routesClient := r.servingClient.ServingV1alpha1().Routes(route.Namespace)
+
+	return util.Retry(time.Second, 10*time.Second, func() (bool, error) {
+		select {
+		case <-cancelCh:
+			return true, fmt.Errorf("Expected route update to not be cancelled")
+		default:
+		}
+
+		origRoute, err := routesClient.Get(route.Name, metav1.GetOptions{})
+		if err != nil {
+			return true, err
+		}
+
+		origRoute.Spec = route.Spec
+
+		_, err = routesClient.Update(origRoute)
+		if err != nil {
+			return false, fmt.Errorf("Updating route: %s", err)
+		}
+
+		return true, nil
+	})
+}
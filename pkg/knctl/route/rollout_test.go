@@ -0,0 +1,101 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package route_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	. "github.com/cppforlife/knctl/pkg/knctl/route"
+	"github.com/cppforlife/knctl/pkg/knctl/testkit"
+	"github.com/knative/serving/pkg/apis/serving/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestRollout_TargetsAndApply(t *testing.T) {
+	cluster := testkit.NewCluster(t,
+		testkit.NewService("ns1", "svc1").Manual().Build(),
+		testkit.NewRevision("ns1", "svc1", "svc1-00001").Tag("previous").Build(),
+		testkit.NewRevision("ns1", "svc1", "svc1-00002").Tag("latest").Build(),
+		testkit.NewRoute("ns1", "svc1").Traffic("svc1-00001", 100).Build(),
+	)
+
+	rollout := NewRollout(cluster.ServingClient())
+
+	targets, err := rollout.Targets("ns1", "svc1", []string{"latest=20%", "previous=80%"}, nil)
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	expectedTargets := []v1alpha1.TrafficTarget{
+		{RevisionName: "svc1-00002", Percent: 20},
+		{RevisionName: "svc1-00001", Percent: 80},
+	}
+
+	if !reflect.DeepEqual(targets, expectedTargets) {
+		t.Fatalf("Expected targets to reference tagged revisions, but was: %#v", targets)
+	}
+
+	route := &v1alpha1.Route{
+		ObjectMeta: metav1.ObjectMeta{Name: "svc1", Namespace: "ns1"},
+		Spec:       v1alpha1.RouteSpec{Traffic: targets},
+	}
+
+	prevTraffic, err := rollout.Apply(route, nil)
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	if len(prevTraffic) != 1 || prevTraffic[0].RevisionName != "svc1-00001" || prevTraffic[0].Percent != 100 {
+		t.Fatalf("Expected previous traffic to be returned, but was: %#v", prevTraffic)
+	}
+
+	updatedRoute, err := cluster.ServingClient().ServingV1alpha1().Routes("ns1").Get("svc1", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	if !reflect.DeepEqual(updatedRoute.Spec.Traffic, expectedTargets) {
+		t.Fatalf("Expected route traffic to be updated, but was: %#v", updatedRoute.Spec.Traffic)
+	}
+}
+
+func TestRollout_ApplyManagedRoute(t *testing.T) {
+	cluster := testkit.NewCluster(t, testkit.NewService("ns1", "svc1").Build())
+
+	route := &v1alpha1.Route{ObjectMeta: metav1.ObjectMeta{Name: "svc1", Namespace: "ns1"}}
+
+	_, err := NewRollout(cluster.ServingClient()).Apply(route, nil)
+	if err == nil || !strings.Contains(err.Error(), "Expected associated service 'svc1' to not manage route") {
+		t.Fatalf("Expected error for managed route, but was: %v", err)
+	}
+}
+
+func TestExtractNameAndPercentage(t *testing.T) {
+	name, percent, err := ExtractNameAndPercentage("svc1:latest=25%")
+	if err != nil || name != "svc1:latest" || percent != 25 {
+		t.Fatalf("Expected name and percentage to be parsed, but was: %s %d %v", name, percent, err)
+	}
+
+	for _, str := range []string{"svc1", "svc1=abc", "svc1=101%"} {
+		_, _, err := ExtractNameAndPercentage(str)
+		if err == nil {
+			t.Fatalf("Expected error for '%s'", str)
+		}
+	}
+}
@@ -0,0 +1,158 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scan
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+const (
+	maxReportSize = 32 * 1024 * 1024
+)
+
+type Severity string
+
+const (
+	SeverityUnknown  Severity = "UNKNOWN"
+	SeverityLow      Severity = "LOW"
+	SeverityMedium   Severity = "MEDIUM"
+	SeverityHigh     Severity = "HIGH"
+	SeverityCritical Severity = "CRITICAL"
+)
+
+var (
+	// Severities are ordered from least to most severe
+	Severities = []Severity{SeverityUnknown, SeverityLow, SeverityMedium, SeverityHigh, SeverityCritical}
+)
+
+func ParseSeverity(str string) (Severity, error) {
+	for _, sev := range Severities {
+		if strings.EqualFold(string(sev), str) {
+			return sev, nil
+		}
+	}
+	return "", fmt.Errorf("Expected severity '%s' to be one of: unknown, low, medium, high, critical", str)
+}
+
+func (s Severity) rank() int {
+	for i, sev := range Severities {
+		if sev == s {
+			return i
+		}
+	}
+	return 0
+}
+
+// Scanner queries HTTP scanning endpoint which responds
+// with a Trivy JSON report for an image given in 'image' query param
+// (e.g. GET https://scanner/report?image=gcr.io/proj/app@sha256:...)
+type Scanner struct {
+	httpClient *http.Client
+	url        string
+}
+
+func NewScanner(httpClient *http.Client, url string) Scanner {
+	return Scanner{httpClient, url}
+}
+
+func (s Scanner) Scan(image string) (Report, error) {
+	scanURL, err := url.Parse(s.url)
+	if err != nil {
+		return Report{}, fmt.Errorf("Parsing scanner URL: %s", err)
+	}
+
+	query := scanURL.Query()
+	query.Set("image", image)
+	scanURL.RawQuery = query.Encode()
+
+	resp, err := s.httpClient.Get(scanURL.String())
+	if err != nil {
+		// Error includes URL which may embed credentials
+		return Report{}, fmt.Errorf("Requesting scan from '%s' failed", scanURL.Host)
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return Report{}, fmt.Errorf("Requesting scan from '%s': scanner responded with '%s'", scanURL.Host, resp.Status)
+	}
+
+	var trivyReport struct {
+		Results []struct {
+			Vulnerabilities []Vulnerability
+		}
+	}
+
+	err = json.NewDecoder(io.LimitReader(resp.Body, maxReportSize)).Decode(&trivyReport)
+	if err != nil {
+		return Report{}, fmt.Errorf("Unmarshaling scan report: %s", err)
+	}
+
+	report := Report{Image: image}
+
+	for _, result := range trivyReport.Results {
+		report.Vulnerabilities = append(report.Vulnerabilities, result.Vulnerabilities...)
+	}
+
+	return report, nil
+}
+
+type Vulnerability struct {
+	VulnerabilityID string
+	PkgName         string
+	Severity        Severity
+}
+
+type Report struct {
+	Image           string
+	Vulnerabilities []Vulnerability
+}
+
+func (r Report) Count(sev Severity) int {
+	var count int
+	for _, vuln := range r.Vulnerabilities {
+		if vuln.Severity == sev {
+			count++
+		}
+	}
+	return count
+}
+
+// AtLeast returns vulnerabilities with given or higher severity
+func (r Report) AtLeast(sev Severity) []Vulnerability {
+	var result []Vulnerability
+	for _, vuln := range r.Vulnerabilities {
+		if vuln.Severity.rank() >= sev.rank() {
+			result = append(result, vuln)
+		}
+	}
+	return result
+}
+
+// Summary returns counts per severity from most to least severe
+func (r Report) Summary() string {
+	var pieces []string
+	for i := len(Severities) - 1; i >= 0; i-- {
+		pieces = append(pieces, fmt.Sprintf("%s %d", Severities[i], r.Count(Severities[i])))
+	}
+	return strings.Join(pieces, ", ")
+}
@@ -0,0 +1,79 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scan_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/cppforlife/knctl/pkg/knctl/scan"
+)
+
+func TestScannerScan(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("image") != "gcr.io/proj/app@sha256:abc" {
+			t.Fatalf("Expected image query param, but was '%s'", r.URL.RawQuery)
+		}
+		fmt.Fprintf(w, `{"Results":[
+			{"Vulnerabilities":[{"VulnerabilityID":"CVE-1","Severity":"CRITICAL"},{"VulnerabilityID":"CVE-2","Severity":"LOW"}]},
+			{"Vulnerabilities":[{"VulnerabilityID":"CVE-3","Severity":"HIGH"}]},
+			{}
+		]}`)
+	}))
+
+	defer server.Close()
+
+	report, err := scan.NewScanner(http.DefaultClient, server.URL+"/report").Scan("gcr.io/proj/app@sha256:abc")
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	if report.Summary() != "CRITICAL 1, HIGH 1, MEDIUM 0, LOW 1, UNKNOWN 0" {
+		t.Fatalf("Expected summary to match, but was '%s'", report.Summary())
+	}
+
+	if len(report.AtLeast(scan.SeverityHigh)) != 2 || len(report.AtLeast(scan.SeverityCritical)) != 1 {
+		t.Fatalf("Expected vulnerabilities to be filtered by severity")
+	}
+}
+
+func TestScannerScanFailure(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+
+	defer server.Close()
+
+	_, err := scan.NewScanner(http.DefaultClient, server.URL).Scan("img")
+	if err == nil {
+		t.Fatalf("Expected error")
+	}
+}
+
+func TestParseSeverity(t *testing.T) {
+	sev, err := scan.ParseSeverity("critical")
+	if err != nil || sev != scan.SeverityCritical {
+		t.Fatalf("Expected severity to parse: %s %v", sev, err)
+	}
+
+	_, err = scan.ParseSeverity("urgent")
+	if err == nil {
+		t.Fatalf("Expected error")
+	}
+}
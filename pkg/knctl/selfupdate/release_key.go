@@ -0,0 +1,41 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package selfupdate
+
+import (
+	"encoding/base64"
+	"fmt"
+)
+
+// ReleasePublicKey is base64 encoded cosign public key that signs release
+// checksums; it's set by ./hack/build-binaries.sh via -ldflags '-X'
+var ReleasePublicKey = ""
+
+// ReleasePublicKeyPEM returns embedded release public key
+// or nil if binary was built without it (e.g. development build)
+func ReleasePublicKeyPEM() ([]byte, error) {
+	if len(ReleasePublicKey) == 0 {
+		return nil, nil
+	}
+
+	keyPEM, err := base64.StdEncoding.DecodeString(ReleasePublicKey)
+	if err != nil {
+		return nil, fmt.Errorf("Expected embedded release public key to be base64 encoded: %s", err)
+	}
+
+	return keyPEM, nil
+}
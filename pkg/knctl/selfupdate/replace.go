@@ -0,0 +1,75 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package selfupdate
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+)
+
+// Replace atomically swaps executable with new content: content is written
+// to a temporary file next to executable (same filesystem) and renamed over it,
+// so that interrupted update never leaves a partially written binary behind
+func Replace(executablePath string, content []byte) error {
+	dir, name := filepath.Split(executablePath)
+
+	tmpFile, err := ioutil.TempFile(dir, "."+name+".new-")
+	if err != nil {
+		return fmt.Errorf("Creating temporary file: %s", err)
+	}
+
+	tmpPath := tmpFile.Name()
+
+	defer os.Remove(tmpPath) // no-op once renamed
+
+	_, err = tmpFile.Write(content)
+	if err != nil {
+		tmpFile.Close()
+		return fmt.Errorf("Writing temporary file: %s", err)
+	}
+
+	err = tmpFile.Close()
+	if err != nil {
+		return fmt.Errorf("Closing temporary file: %s", err)
+	}
+
+	err = os.Chmod(tmpPath, 0755)
+	if err != nil {
+		return fmt.Errorf("Making temporary file executable: %s", err)
+	}
+
+	// Running executable cannot be overwritten on Windows, but can be renamed
+	if runtime.GOOS == "windows" {
+		oldPath := executablePath + ".old"
+		os.Remove(oldPath)
+
+		err = os.Rename(executablePath, oldPath)
+		if err != nil {
+			return fmt.Errorf("Moving current executable aside: %s", err)
+		}
+	}
+
+	err = os.Rename(tmpPath, executablePath)
+	if err != nil {
+		return fmt.Errorf("Replacing executable: %s", err)
+	}
+
+	return nil
+}
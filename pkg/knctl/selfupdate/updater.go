@@ -0,0 +1,308 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package selfupdate finds knctl releases on GitHub and replaces
+// running executable with a verified release binary.
+package selfupdate
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/cppforlife/knctl/pkg/knctl/signature"
+)
+
+const (
+	ChannelStable = "stable" // latest release
+	ChannelEdge   = "edge"   // latest release including pre-releases
+
+	DefaultReleasesURL = "https://api.github.com/repos/cppforlife/knctl/releases"
+
+	ChecksumsAssetName   = "checksums.txt"
+	SignatureAssetSuffix = ".sig"
+)
+
+type Release struct {
+	TagName    string         `json:"tag_name"`
+	Draft      bool           `json:"draft"`
+	Prerelease bool           `json:"prerelease"`
+	Assets     []ReleaseAsset `json:"assets"`
+}
+
+type ReleaseAsset struct {
+	Name string `json:"name"`
+	URL  string `json:"browser_download_url"`
+}
+
+func (r Release) Version() string { return strings.TrimPrefix(r.TagName, "v") }
+
+func (r Release) Asset(name string) (ReleaseAsset, error) {
+	for _, asset := range r.Assets {
+		if asset.Name == name {
+			return asset, nil
+		}
+	}
+	return ReleaseAsset{}, fmt.Errorf("Expected release '%s' to include asset '%s'", r.TagName, name)
+}
+
+// AssetName follows naming of ./hack/build-binaries.sh
+func AssetName(goos, goarch string) string {
+	name := fmt.Sprintf("knctl-%s-%s", goos, goarch)
+	if goos == "windows" {
+		name += ".exe"
+	}
+	return name
+}
+
+type Updater struct {
+	httpClient  *http.Client
+	releasesURL string
+}
+
+func NewUpdater(httpClient *http.Client, releasesURL string) Updater {
+	return Updater{httpClient, releasesURL}
+}
+
+// Latest returns newest release for given channel
+// (GitHub lists releases from newest to oldest)
+func (u Updater) Latest(channel string) (Release, error) {
+	if channel != ChannelStable && channel != ChannelEdge {
+		return Release{}, fmt.Errorf("Expected channel to be either '%s' or '%s' but was '%s'", ChannelStable, ChannelEdge, channel)
+	}
+
+	body, err := u.get(u.releasesURL)
+	if err != nil {
+		return Release{}, fmt.Errorf("Listing releases: %s", err)
+	}
+
+	var releases []Release
+
+	err = json.Unmarshal(body, &releases)
+	if err != nil {
+		return Release{}, fmt.Errorf("Unmarshaling releases: %s", err)
+	}
+
+	for _, release := range releases {
+		if release.Draft || (release.Prerelease && channel == ChannelStable) {
+			continue
+		}
+		return release, nil
+	}
+
+	return Release{}, fmt.Errorf("Expected to find at least one release in '%s' channel", channel)
+}
+
+// Download fetches release asset and verifies it against release checksums.
+// When public key is given, checksums must be signed with 'cosign sign-blob'
+// (see ./hack/build-binaries.sh); nil key skips signature verification.
+func (u Updater) Download(release Release, assetName string, publicKeyPEM []byte) ([]byte, error) {
+	checksums, err := u.downloadAsset(release, ChecksumsAssetName)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(publicKeyPEM) > 0 {
+		sig, err := u.downloadAsset(release, ChecksumsAssetName+SignatureAssetSuffix)
+		if err != nil {
+			return nil, err
+		}
+
+		err = signature.VerifyBlob(publicKeyPEM, checksums, string(sig))
+		if err != nil {
+			return nil, fmt.Errorf("Verifying signature of '%s': %s", ChecksumsAssetName, err)
+		}
+	}
+
+	expectedSHA256, err := FindChecksum(checksums, assetName)
+	if err != nil {
+		return nil, err
+	}
+
+	content, err := u.downloadAsset(release, assetName)
+	if err != nil {
+		return nil, err
+	}
+
+	if fmt.Sprintf("%x", sha256.Sum256(content)) != expectedSHA256 {
+		return nil, fmt.Errorf("Expected asset '%s' to match SHA256 '%s' but did not", assetName, expectedSHA256)
+	}
+
+	return content, nil
+}
+
+func (u Updater) downloadAsset(release Release, name string) ([]byte, error) {
+	asset, err := release.Asset(name)
+	if err != nil {
+		return nil, err
+	}
+
+	content, err := u.get(asset.URL)
+	if err != nil {
+		return nil, fmt.Errorf("Downloading asset '%s': %s", name, err)
+	}
+
+	return content, nil
+}
+
+func (u Updater) get(url string) ([]byte, error) {
+	httpClient := u.httpClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	resp, err := httpClient.Get(url)
+	if err != nil {
+		return nil, err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("Expected response status 200 from '%s' but was %d", url, resp.StatusCode)
+	}
+
+	return ioutil.ReadAll(resp.Body)
+}
+
+// FindChecksum parses output of 'shasum -a 256' (e.g. '<sha256>  ./knctl-linux-amd64')
+func FindChecksum(checksums []byte, assetName string) (string, error) {
+	scanner := bufio.NewScanner(bytes.NewReader(checksums))
+
+	for scanner.Scan() {
+		pieces := strings.Fields(scanner.Text())
+		if len(pieces) != 2 {
+			continue
+		}
+		if strings.TrimPrefix(strings.TrimPrefix(pieces[1], "*"), "./") == assetName {
+			return strings.ToLower(pieces[0]), nil
+		}
+	}
+
+	return "", fmt.Errorf("Expected '%s' to include checksum for asset '%s'", ChecksumsAssetName, assetName)
+}
+
+// IsNewer compares versions by semver precedence (e.g. '0.3.0' and '0.10.0'):
+// pre-release (e.g. '0.3.0-rc.1') is older than its release and
+// pre-release identifiers are compared one by one; build metadata is ignored
+func IsNewer(current, candidate string) bool {
+	currentCore, currentPre := splitVersion(current)
+	candidateCore, candidatePre := splitVersion(candidate)
+
+	if cmp := compareCorePieces(versionPieces(currentCore), versionPieces(candidateCore)); cmp != 0 {
+		return cmp < 0
+	}
+
+	return comparePreRelease(currentPre, candidatePre) < 0
+}
+
+// splitVersion returns version core (e.g. '0.3.0') and pre-release (e.g. 'rc.1')
+func splitVersion(version string) (string, string) {
+	version = strings.SplitN(strings.TrimPrefix(version, "v"), "+", 2)[0]
+
+	pieces := strings.SplitN(version, "-", 2)
+	if len(pieces) == 1 {
+		return pieces[0], ""
+	}
+
+	return pieces[0], pieces[1]
+}
+
+func compareCorePieces(a, b []int) int {
+	for i := 0; i < len(a) || i < len(b); i++ {
+		var aPiece, bPiece int
+		if i < len(a) {
+			aPiece = a[i]
+		}
+		if i < len(b) {
+			bPiece = b[i]
+		}
+		if aPiece != bPiece {
+			return compareInts(aPiece, bPiece)
+		}
+	}
+
+	return 0
+}
+
+func comparePreRelease(a, b string) int {
+	switch {
+	case a == b:
+		return 0
+	case len(a) == 0:
+		return 1 // release has higher precedence than its pre-release
+	case len(b) == 0:
+		return -1
+	}
+
+	aIdents := strings.Split(a, ".")
+	bIdents := strings.Split(b, ".")
+
+	for i := 0; i < len(aIdents) && i < len(bIdents); i++ {
+		if cmp := comparePreReleaseIdent(aIdents[i], bIdents[i]); cmp != 0 {
+			return cmp
+		}
+	}
+
+	// Larger set of identifiers has higher precedence if all preceding ones are equal
+	return compareInts(len(aIdents), len(bIdents))
+}
+
+// comparePreReleaseIdent compares numeric identifiers numerically,
+// alphanumeric ones lexically and orders numeric before alphanumeric
+func comparePreReleaseIdent(a, b string) int {
+	aNum, aErr := strconv.Atoi(a)
+	bNum, bErr := strconv.Atoi(b)
+
+	switch {
+	case aErr == nil && bErr == nil:
+		return compareInts(aNum, bNum)
+	case aErr == nil:
+		return -1
+	case bErr == nil:
+		return 1
+	default:
+		return strings.Compare(a, b)
+	}
+}
+
+func compareInts(a, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
+
+func versionPieces(version string) []int {
+	var result []int
+
+	for _, piece := range strings.Split(version, ".") {
+		num, _ := strconv.Atoi(piece)
+		result = append(result, num)
+	}
+
+	return result
+}
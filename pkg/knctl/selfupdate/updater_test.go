@@ -0,0 +1,270 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package selfupdate_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/cppforlife/knctl/pkg/knctl/selfupdate"
+)
+
+type fakeReleases struct {
+	server *httptest.Server
+	files  map[string][]byte
+}
+
+func newFakeReleases(t *testing.T) *fakeReleases {
+	r := &fakeReleases{files: map[string][]byte{}}
+
+	r.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		content, found := r.files[req.URL.Path]
+		if !found {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write(content)
+	}))
+	t.Cleanup(r.server.Close)
+
+	binary := []byte("new-binary")
+	r.files["/v0.4.0/knctl-linux-amd64"] = binary
+	r.files["/v0.4.0/checksums.txt"] = []byte(fmt.Sprintf(
+		"%x  ./knctl-darwin-amd64\n%x  ./knctl-linux-amd64\n", sha256.Sum256([]byte("other")), sha256.Sum256(binary)))
+
+	r.files["/releases"] = []byte(fmt.Sprintf(`[
+  {"tag_name": "v0.5.0", "draft": true},
+  {"tag_name": "v0.5.0-rc.1", "prerelease": true},
+  {"tag_name": "v0.4.0", "assets": [
+    {"name": "knctl-linux-amd64", "browser_download_url": "%[1]s/v0.4.0/knctl-linux-amd64"},
+    {"name": "checksums.txt", "browser_download_url": "%[1]s/v0.4.0/checksums.txt"},
+    {"name": "checksums.txt.sig", "browser_download_url": "%[1]s/v0.4.0/checksums.txt.sig"}
+  ]}
+]`, r.server.URL))
+
+	return r
+}
+
+func (r *fakeReleases) Updater() selfupdate.Updater {
+	return selfupdate.NewUpdater(nil, r.server.URL+"/releases")
+}
+
+func TestUpdaterLatest(t *testing.T) {
+	updater := newFakeReleases(t).Updater()
+
+	release, err := updater.Latest(selfupdate.ChannelStable)
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+	if release.Version() != "0.4.0" {
+		t.Fatalf("Expected stable channel to skip drafts and pre-releases, but was '%s'", release.TagName)
+	}
+
+	release, err = updater.Latest(selfupdate.ChannelEdge)
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+	if release.Version() != "0.5.0-rc.1" {
+		t.Fatalf("Expected edge channel to include pre-releases, but was '%s'", release.TagName)
+	}
+
+	_, err = updater.Latest("unknown")
+	if err == nil || !strings.Contains(err.Error(), "Expected channel to be either 'stable' or 'edge'") {
+		t.Fatalf("Expected channel error, but was: %v", err)
+	}
+}
+
+func TestUpdaterDownload(t *testing.T) {
+	releases := newFakeReleases(t)
+	updater := releases.Updater()
+
+	release, err := updater.Latest(selfupdate.ChannelStable)
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	content, err := updater.Download(release, "knctl-linux-amd64", nil)
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+	if string(content) != "new-binary" {
+		t.Fatalf("Expected downloaded content, but was '%s'", content)
+	}
+
+	_, err = updater.Download(release, "knctl-windows-amd64.exe", nil)
+	if err == nil || !strings.Contains(err.Error(), "Expected 'checksums.txt' to include checksum for asset 'knctl-windows-amd64.exe'") {
+		t.Fatalf("Expected missing checksum error, but was: %v", err)
+	}
+
+	releases.files["/v0.4.0/knctl-linux-amd64"] = []byte("tampered-binary")
+
+	_, err = updater.Download(release, "knctl-linux-amd64", nil)
+	if err == nil || !strings.Contains(err.Error(), "Expected asset 'knctl-linux-amd64' to match SHA256") {
+		t.Fatalf("Expected checksum mismatch error, but was: %v", err)
+	}
+}
+
+func TestUpdaterDownloadVerifiesSignature(t *testing.T) {
+	releases := newFakeReleases(t)
+	updater := releases.Updater()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	keyBytes, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	publicKeyPEM := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: keyBytes})
+
+	release, err := updater.Latest(selfupdate.ChannelStable)
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	_, err = updater.Download(release, "knctl-linux-amd64", publicKeyPEM)
+	if err == nil || !strings.Contains(err.Error(), "Downloading asset 'checksums.txt.sig'") {
+		t.Fatalf("Expected missing signature error, but was: %v", err)
+	}
+
+	checksumsSHA := sha256.Sum256(releases.files["/v0.4.0/checksums.txt"])
+
+	sig, err := ecdsa.SignASN1(rand.Reader, key, checksumsSHA[:])
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	releases.files["/v0.4.0/checksums.txt.sig"] = []byte(base64.StdEncoding.EncodeToString(sig))
+
+	_, err = updater.Download(release, "knctl-linux-amd64", publicKeyPEM)
+	if err != nil {
+		t.Fatalf("Expected signed checksums to be accepted: %s", err)
+	}
+
+	releases.files["/v0.4.0/checksums.txt"] = append(releases.files["/v0.4.0/checksums.txt"], []byte("extra\n")...)
+
+	_, err = updater.Download(release, "knctl-linux-amd64", publicKeyPEM)
+	if err == nil || !strings.Contains(err.Error(), "Verifying signature of 'checksums.txt'") {
+		t.Fatalf("Expected signature error, but was: %v", err)
+	}
+}
+
+func TestReleasePublicKeyPEM(t *testing.T) {
+	defer func() { selfupdate.ReleasePublicKey = "" }()
+
+	keyPEM, err := selfupdate.ReleasePublicKeyPEM()
+	if err != nil || keyPEM != nil {
+		t.Fatalf("Expected no key for development build, but was: %s %v", keyPEM, err)
+	}
+
+	selfupdate.ReleasePublicKey = base64.StdEncoding.EncodeToString([]byte("key-pem"))
+
+	keyPEM, err = selfupdate.ReleasePublicKeyPEM()
+	if err != nil || string(keyPEM) != "key-pem" {
+		t.Fatalf("Expected embedded key to be decoded, but was: %s %v", keyPEM, err)
+	}
+
+	selfupdate.ReleasePublicKey = "not-base64!"
+
+	_, err = selfupdate.ReleasePublicKeyPEM()
+	if err == nil {
+		t.Fatalf("Expected invalid embedded key error")
+	}
+}
+
+func TestIsNewer(t *testing.T) {
+	exs := []struct {
+		Current   string
+		Candidate string
+		Newer     bool
+	}{
+		{"0.3.0", "0.4.0", true},
+		{"0.3.0", "v0.10.0", true},
+		{"0.3.0", "0.3.0", false},
+		{"0.3.0", "0.2.9", false},
+		{"0.3.0", "0.3.1-rc.1", true},
+		{"0.3", "0.3.0", false},
+		{"1.0.0", "0.9.9", false},
+
+		// Pre-releases
+		{"0.2.0-rc.1", "0.2.0-rc.2", true},
+		{"0.2.0-rc.2", "0.2.0-rc.1", false},
+		{"0.2.0-rc.1", "0.2.0", true},
+		{"0.2.0", "0.2.0-rc.1", false},
+		{"0.2.0-rc.1", "0.2.0-rc.1", false},
+		{"0.2.0-rc.9", "0.2.0-rc.10", true},
+		{"0.2.0-alpha", "0.2.0-alpha.1", true},
+		{"0.2.0-alpha.1", "0.2.0-alpha.beta", true},
+		{"0.2.0-beta", "0.2.0-alpha", false},
+		{"0.2.0-1", "0.2.0-alpha", true},
+		{"0.1.9", "0.2.0-rc.1", true},
+		{"0.2.0-rc.1", "0.2.1-rc.1", true},
+		{"0.2.0+build.1", "0.2.0+build.2", false},
+	}
+
+	for _, ex := range exs {
+		if selfupdate.IsNewer(ex.Current, ex.Candidate) != ex.Newer {
+			t.Fatalf("Expected '%s' newer than '%s' to be %t", ex.Candidate, ex.Current, ex.Newer)
+		}
+	}
+}
+
+func TestReplace(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "knctl")
+
+	err := ioutil.WriteFile(path, []byte("old-binary"), 0755)
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	err = selfupdate.Replace(path, []byte("new-binary"))
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	content, err := ioutil.ReadFile(path)
+	if err != nil || string(content) != "new-binary" {
+		t.Fatalf("Expected executable to be replaced, but was '%s' (err: %v)", content, err)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil || info.Mode().Perm()&0100 == 0 {
+		t.Fatalf("Expected replaced file to be executable (err: %v)", err)
+	}
+
+	files, _ := ioutil.ReadDir(dir)
+	if len(files) != 1 {
+		t.Fatalf("Expected temporary files to be cleaned up, but found %d files", len(files))
+	}
+}
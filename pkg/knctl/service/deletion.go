@@ -0,0 +1,59 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"fmt"
+	"strings"
+
+	servingclientset "github.com/knative/serving/pkg/client/clientset/versioned"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type Deletion struct {
+	servingClient servingclientset.Interface
+}
+
+func NewDeletion(servingClient servingclientset.Interface) Deletion {
+	return Deletion{servingClient}
+}
+
+// CheckNotPinned returns an error if any of service's revisions are pinned
+func (d Deletion) CheckNotPinned(namespace, name string) error {
+	pinned, err := NewPins(d.servingClient).ServicePinned(namespace, name)
+	if err != nil {
+		return err
+	}
+
+	if len(pinned) > 0 {
+		return fmt.Errorf("Expected service '%s' to not have pinned revisions, but found: %s",
+			name, strings.Join(pinned, ", "))
+	}
+
+	return nil
+}
+
+func (d Deletion) Delete(namespace, name string) error {
+	err := d.servingClient.ServingV1alpha1().Services(namespace).Delete(name, &metav1.DeleteOptions{})
+	if err != nil {
+		return fmt.Errorf("Deleting service: %s", err)
+	}
+
+	// TODO idempotent?
+
+	return nil
+}
@@ -0,0 +1,88 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"fmt"
+
+	ctlkube "github.com/cppforlife/knctl/pkg/knctl/kube"
+	"github.com/knative/serving/pkg/apis/serving"
+	"github.com/knative/serving/pkg/apis/serving/v1alpha1"
+	servingclientset "github.com/knative/serving/pkg/client/clientset/versioned"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const (
+	// PinnedAnnotationKey marks revisions that knctl refuses to delete without --force
+	PinnedAnnotationKey   = "cli.knative.dev/pinned"
+	pinnedAnnotationValue = "true"
+)
+
+type Pins struct {
+	servingClient servingclientset.Interface
+}
+
+func NewPins(servingClient servingclientset.Interface) Pins {
+	return Pins{servingClient}
+}
+
+func (p Pins) IsPinned(revision v1alpha1.Revision) bool {
+	return revision.Annotations[PinnedAnnotationKey] == pinnedAnnotationValue
+}
+
+func (p Pins) Pin(revision v1alpha1.Revision) error {
+	return p.annotations(revision).Add(map[string]interface{}{
+		PinnedAnnotationKey: pinnedAnnotationValue,
+	})
+}
+
+func (p Pins) Unpin(revision v1alpha1.Revision) error {
+	// Null value removes annotation in a merge patch
+	return p.annotations(revision).Add(map[string]interface{}{
+		PinnedAnnotationKey: nil,
+	})
+}
+
+// ServicePinned returns names of service's pinned revisions
+func (p Pins) ServicePinned(namespace, serviceName string) ([]string, error) {
+	listOpts := metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("%s=%s", serving.ServiceLabelKey, serviceName),
+	}
+
+	revisions, err := p.servingClient.ServingV1alpha1().Revisions(namespace).List(listOpts)
+	if err != nil {
+		return nil, fmt.Errorf("Listing revisions: %s", err)
+	}
+
+	var names []string
+
+	for _, rev := range revisions.Items {
+		if p.IsPinned(rev) {
+			names = append(names, rev.Name)
+		}
+	}
+
+	return names, nil
+}
+
+func (p Pins) annotations(revision v1alpha1.Revision) ctlkube.Annotations {
+	return ctlkube.NewAnnotations(func(type_ types.PatchType, data []byte) error {
+		_, err := p.servingClient.ServingV1alpha1().Revisions(revision.Namespace).Patch(revision.Name, type_, data)
+		return err
+	})
+}
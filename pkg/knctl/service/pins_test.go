@@ -0,0 +1,81 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service_test
+
+import (
+	"reflect"
+	"testing"
+
+	. "github.com/cppforlife/knctl/pkg/knctl/service"
+	"github.com/cppforlife/knctl/pkg/knctl/testkit"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestPins_PinUnpin(t *testing.T) {
+	cluster := testkit.NewCluster(t,
+		testkit.NewRevision("ns1", "svc1", "svc1-00001").Annotations(map[string]string{"other": "val"}).Build(),
+		testkit.NewRevision("ns1", "svc1", "svc1-00002").Build(),
+		testkit.NewRevision("ns1", "svc2", "svc2-00001").Build(),
+	)
+
+	revisions := cluster.ServingClient().ServingV1alpha1().Revisions("ns1")
+	pins := NewPins(cluster.ServingClient())
+
+	for _, name := range []string{"svc1-00001", "svc2-00001"} {
+		rev, err := revisions.Get(name, metav1.GetOptions{})
+		if err != nil {
+			t.Fatalf("Expected no error: %s", err)
+		}
+
+		err = pins.Pin(*rev)
+		if err != nil {
+			t.Fatalf("Expected no error: %s", err)
+		}
+	}
+
+	rev, err := revisions.Get("svc1-00001", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	if !pins.IsPinned(*rev) || rev.Annotations["other"] != "val" {
+		t.Fatalf("Expected revision to be pinned and keep other annotations: %#v", rev.Annotations)
+	}
+
+	names, err := pins.ServicePinned("ns1", "svc1")
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	if !reflect.DeepEqual(names, []string{"svc1-00001"}) {
+		t.Fatalf("Expected only svc1-00001 to be pinned, but was: %#v", names)
+	}
+
+	err = pins.Unpin(*rev)
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	rev, err = revisions.Get("svc1-00001", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	if pins.IsPinned(*rev) || rev.Annotations["other"] != "val" {
+		t.Fatalf("Expected revision to be unpinned and keep other annotations: %#v", rev.Annotations)
+	}
+}
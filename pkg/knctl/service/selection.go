@@ -0,0 +1,56 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"fmt"
+	"sort"
+
+	servingclientset "github.com/knative/serving/pkg/client/clientset/versioned"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Selection resolves services that an operation applies to
+type Selection struct {
+	servingClient servingclientset.Interface
+}
+
+func NewSelection(servingClient servingclientset.Interface) Selection {
+	return Selection{servingClient}
+}
+
+// Names returns names of services matching label selector sorted by name
+func (s Selection) Names(namespace, selector string) ([]string, error) {
+	services, err := s.servingClient.ServingV1alpha1().Services(namespace).List(metav1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		return nil, fmt.Errorf("Listing services: %s", err)
+	}
+
+	var names []string
+
+	for _, service := range services.Items {
+		names = append(names, service.Name)
+	}
+
+	if len(names) == 0 {
+		return nil, fmt.Errorf("Expected at least one service to match selector '%s'", selector)
+	}
+
+	sort.Strings(names)
+
+	return names, nil
+}
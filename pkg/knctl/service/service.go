@@ -59,7 +59,7 @@ func NewService(
 }
 
 func (l *Service) CreatedBuildSinceRevision(lastRevision *v1alpha1.Revision) (ctlbuild.Build, error) {
-	createdRevision, err := l.CreatedRevisionSinceRevision(lastRevision)
+	createdRevision, err := l.CreatedRevisionSinceRevision(lastRevision, nil)
 	if err != nil {
 		return ctlbuild.Build{}, err
 	}
@@ -98,7 +98,9 @@ func (l *Service) CreatedBuildSinceRevision(lastRevision *v1alpha1.Revision) (ct
 	return ctlbuild.Build{}, fmt.Errorf("Expected to find new build")
 }
 
-func (l *Service) CreatedRevisionSinceRevision(lastRevision *v1alpha1.Revision) (*v1alpha1.Revision, error) {
+// CreatedRevisionSinceRevision waits for revision created after given revision;
+// error is returned if cancelCh (may be nil) is closed before revision is found
+func (l *Service) CreatedRevisionSinceRevision(lastRevision *v1alpha1.Revision, cancelCh chan struct{}) (*v1alpha1.Revision, error) {
 	cancelResWatchCh := make(chan struct{})
 	revisionsToWatchCh := make(chan v1alpha1.Revision)
 
@@ -122,23 +124,32 @@ func (l *Service) CreatedRevisionSinceRevision(lastRevision *v1alpha1.Revision)
 		close(revisionsToWatchCh)
 	}()
 
-	var createdRevision *v1alpha1.Revision
+	defer func() {
+		close(cancelResWatchCh)
 
-	for revision := range revisionsToWatchCh {
-		// TODO comparing based on time causes problems for revisions with same creation timestamp
-		if lastRevision == nil || revision.CreationTimestamp.Time.After(lastRevision.CreationTimestamp.Time) {
-			createdRevision = &revision
-			break
-		}
-	}
+		// Unblock watcher in case it's sending revisions
+		go func() {
+			for range revisionsToWatchCh {
+			}
+		}()
+	}()
 
-	close(cancelResWatchCh)
+	for {
+		select {
+		case revision, ok := <-revisionsToWatchCh:
+			if !ok {
+				return nil, fmt.Errorf("Expected to find created revision")
+			}
 
-	if createdRevision == nil {
-		return nil, fmt.Errorf("Expected to find created revision")
-	}
+			// TODO comparing based on time causes problems for revisions with same creation timestamp
+			if lastRevision == nil || revision.CreationTimestamp.Time.After(lastRevision.CreationTimestamp.Time) {
+				return &revision, nil
+			}
 
-	return createdRevision, nil
+		case <-cancelCh:
+			return nil, fmt.Errorf("Expected to find created revision before cancellation")
+		}
+	}
 }
 
 func (l *Service) LastRevision() (*v1alpha1.Revision, error) {
@@ -19,7 +19,9 @@ package service
 import (
 	"fmt"
 
+	"github.com/cppforlife/knctl/pkg/knctl/cache"
 	ctling "github.com/cppforlife/knctl/pkg/knctl/ingress"
+	"github.com/cppforlife/knctl/pkg/knctl/logger"
 	"github.com/knative/serving/pkg/apis/serving/v1alpha1"
 	"k8s.io/client-go/kubernetes"
 )
@@ -27,6 +29,13 @@ import (
 type ServiceAddress struct {
 	service    *v1alpha1.Service
 	coreClient kubernetes.Interface
+	cache      cache.Cache
+	logger     logger.Logger
+	inCluster  bool
+}
+
+func NewServiceAddress(service *v1alpha1.Service, coreClient kubernetes.Interface, cache cache.Cache, logger logger.Logger, inCluster bool) ServiceAddress {
+	return ServiceAddress{service, coreClient, cache, logger, inCluster}
 }
 
 func (o ServiceAddress) Domain() (string, error) {
@@ -43,7 +52,7 @@ func (o ServiceAddress) URL(port int32, useDomain bool) (string, error) {
 		return "", err
 	}
 
-	ingressAddress, ingressPort, err := ctling.NewIngressServices(o.coreClient).PreferredAddress(port)
+	ingressAddress, ingressPort, err := ctling.NewIngressServices(o.coreClient).WithCache(o.cache).WithLogger(o.logger).WithInCluster(o.inCluster).PreferredAddress(port)
 	if err != nil {
 		return "", err
 	}
@@ -57,6 +66,15 @@ func (o ServiceAddress) URL(port int32, useDomain bool) (string, error) {
 	return o.schema(port) + "://" + host + ":" + ingressPort, nil
 }
 
+// InternalURL returns URL reachable only from within the cluster
+func (o ServiceAddress) InternalURL() (string, error) {
+	if len(o.service.Status.DomainInternal) == 0 {
+		return "", fmt.Errorf("Expected service '%s' to have non-empty internal domain", o.service.Name)
+	}
+
+	return "http://" + o.service.Status.DomainInternal, nil
+}
+
 func (o ServiceAddress) schema(port int32) string {
 	if port == 443 {
 		return "https"
@@ -77,6 +77,13 @@ func (s *Service) updateService(service v1alpha1.Service) (*v1alpha1.Service, er
 
 		origService.Spec = service.Spec
 
+		if origService.Labels == nil {
+			origService.Labels = map[string]string{}
+		}
+		for k, v := range service.Labels {
+			origService.Labels[k] = v
+		}
+
 		service, err := s.servingClient.ServingV1alpha1().Services(s.serviceSpec.Namespace()).Update(origService)
 		if err != nil {
 			return false, fmt.Errorf("Updating service: %s", err)
@@ -0,0 +1,81 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service_test
+
+import (
+	"testing"
+	"time"
+
+	ctlbuild "github.com/cppforlife/knctl/pkg/knctl/build"
+	. "github.com/cppforlife/knctl/pkg/knctl/service"
+	"github.com/cppforlife/knctl/pkg/knctl/testkit"
+	"github.com/knative/serving/pkg/apis/serving/v1alpha1"
+)
+
+type testServiceSpec struct {
+	namespace, name string
+}
+
+var _ ServiceSpec = testServiceSpec{}
+
+func (s testServiceSpec) Namespace() string                  { return s.namespace }
+func (s testServiceSpec) Name() string                       { return s.name }
+func (s testServiceSpec) Service() (v1alpha1.Service, error) { return v1alpha1.Service{}, nil }
+func (s testServiceSpec) NeedsConfigurationUpdate() bool     { return false }
+func (s testServiceSpec) Configuration() (v1alpha1.Configuration, error) {
+	return v1alpha1.Configuration{}, nil
+}
+
+func TestService_LastRevision(t *testing.T) {
+	now := time.Now()
+
+	cluster := testkit.NewCluster(t,
+		testkit.NewRevision("ns1", "svc1", "svc1-00001").CreatedAt(now.Add(-2*time.Hour)).Build(),
+		testkit.NewRevision("ns1", "svc1", "svc1-00003").CreatedAt(now.Add(-1*time.Hour)).Build(),
+		testkit.NewRevision("ns1", "svc1", "svc1-00002").CreatedAt(now.Add(-90*time.Minute)).Build(),
+		testkit.NewRevision("ns1", "svc2", "svc2-00001").CreatedAt(now).Build(),
+		testkit.NewRevision("ns2", "svc1", "svc1-00004").CreatedAt(now).Build(),
+	)
+
+	service := NewService(testServiceSpec{"ns1", "svc1"}, cluster.ServingClient(), nil, nil, ctlbuild.Factory{})
+
+	revision, err := service.LastRevision()
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	if revision == nil || revision.Name != "svc1-00003" {
+		t.Fatalf("Expected last revision to be 'svc1-00003' but was '%#v'", revision)
+	}
+}
+
+func TestService_LastRevision_NoRevisions(t *testing.T) {
+	cluster := testkit.NewCluster(t,
+		testkit.NewRevision("ns1", "svc2", "svc2-00001").Build(),
+	)
+
+	service := NewService(testServiceSpec{"ns1", "svc1"}, cluster.ServingClient(), nil, nil, ctlbuild.Factory{})
+
+	revision, err := service.LastRevision()
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	if revision != nil {
+		t.Fatalf("Expected no revision but was '%s'", revision.Name)
+	}
+}
@@ -0,0 +1,73 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service_test
+
+import (
+	"testing"
+
+	. "github.com/cppforlife/knctl/pkg/knctl/service"
+	"github.com/cppforlife/knctl/pkg/knctl/testkit"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestTags_RepointMovesTag(t *testing.T) {
+	rev1 := testkit.NewRevision("ns1", "svc1", "svc1-00001").Build()
+	rev2 := testkit.NewRevision("ns1", "svc1", "svc1-00002").Build()
+
+	cluster := testkit.NewCluster(t, rev1, rev2)
+	servingClient := cluster.ServingClient()
+	tags := NewTags(servingClient)
+	findCtx := TagsFindContext{Namespace: "ns1", Service: "svc1"}
+
+	err := tags.Repoint(rev1, TagsLatest)
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	found, err := tags.Find(findCtx, TagsLatest)
+	if err != nil || found.Name != "svc1-00001" {
+		t.Fatalf("Expected to find 'svc1-00001' but was '%#v' (err: %v)", found, err)
+	}
+
+	err = tags.Repoint(rev2, TagsLatest)
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	found, err = tags.Find(findCtx, TagsLatest)
+	if err != nil || found.Name != "svc1-00002" {
+		t.Fatalf("Expected to find 'svc1-00002' but was '%#v' (err: %v)", found, err)
+	}
+
+	untagged, err := servingClient.ServingV1alpha1().Revisions("ns1").Get("svc1-00001", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	if len(tags.List(*untagged)) != 0 {
+		t.Fatalf("Expected previous revision to not have tags but was '%#v'", tags.List(*untagged))
+	}
+}
+
+func TestTags_FindMissingTag(t *testing.T) {
+	cluster := testkit.NewCluster(t, testkit.NewRevision("ns1", "svc1", "svc1-00001").Build())
+
+	_, err := NewTags(cluster.ServingClient()).Find(TagsFindContext{Namespace: "ns1", Service: "svc1"}, TagsLatest)
+	if err == nil {
+		t.Fatalf("Expected error when tag is not found")
+	}
+}
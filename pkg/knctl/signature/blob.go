@@ -0,0 +1,47 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package signature
+
+import (
+	"crypto/ecdsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"strings"
+)
+
+// VerifyBlob checks signature produced by 'cosign sign-blob --key'
+// (base64 encoded ECDSA signature of blob's SHA256)
+func VerifyBlob(publicKeyPEM, blob []byte, encodedSig string) error {
+	key, err := parsePublicKey(publicKeyPEM)
+	if err != nil {
+		return err
+	}
+
+	sig, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encodedSig))
+	if err != nil || len(sig) == 0 {
+		return fmt.Errorf("Expected signature to be base64 encoded")
+	}
+
+	blobSHA := sha256.Sum256(blob)
+
+	if !ecdsa.VerifyASN1(key, blobSHA[:], sig) {
+		return fmt.Errorf("Signature does not match public key")
+	}
+
+	return nil
+}
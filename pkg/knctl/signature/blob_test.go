@@ -0,0 +1,67 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package signature_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"testing"
+
+	"github.com/cppforlife/knctl/pkg/knctl/signature"
+)
+
+func TestVerifyBlob(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	blob := []byte("blob-content")
+	blobSHA := sha256.Sum256(blob)
+
+	sig, err := ecdsa.SignASN1(rand.Reader, key, blobSHA[:])
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	encodedSig := base64.StdEncoding.EncodeToString(sig) + "\n"
+
+	err = signature.VerifyBlob(publicKeyPEM(t, key), blob, encodedSig)
+	if err != nil {
+		t.Fatalf("Expected signature to be valid: %s", err)
+	}
+
+	err = signature.VerifyBlob(publicKeyPEM(t, key), []byte("other-content"), encodedSig)
+	if err == nil || err.Error() != "Signature does not match public key" {
+		t.Fatalf("Expected signature mismatch error, but was: %v", err)
+	}
+
+	otherKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+
+	err = signature.VerifyBlob(publicKeyPEM(t, otherKey), blob, encodedSig)
+	if err == nil {
+		t.Fatalf("Expected signature to not match other key")
+	}
+
+	err = signature.VerifyBlob(publicKeyPEM(t, key), blob, "not-base64!")
+	if err == nil || err.Error() != "Expected signature to be base64 encoded" {
+		t.Fatalf("Expected encoding error, but was: %v", err)
+	}
+}
@@ -0,0 +1,172 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package signature
+
+import (
+	"crypto/ecdsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"strings"
+
+	"github.com/cppforlife/knctl/pkg/knctl/registry"
+)
+
+const (
+	// RequiredAnnotationKey marks namespace that should only run signed images
+	RequiredAnnotationKey = "cli.knative.dev/require-image-signature"
+
+	simpleSigningMediaType  = "application/vnd.dev.cosign.simplesigning.v1+json"
+	signatureAnnotationKey  = "dev.cosignproject.cosign/signature"
+	signatureTagSuffix      = ".sig"
+	manifestDigestJSONField = "docker-manifest-digest"
+)
+
+// CosignVerifier checks that image digest was signed with 'cosign sign --key'.
+// Signatures are looked up in the image repository under 'sha256-<hex>.sig' tag.
+type CosignVerifier struct {
+	registryClient registry.Client
+	publicKey      *ecdsa.PublicKey
+}
+
+func NewCosignVerifier(registryClient registry.Client, publicKeyPEM []byte) (CosignVerifier, error) {
+	key, err := parsePublicKey(publicKeyPEM)
+	if err != nil {
+		return CosignVerifier{}, err
+	}
+
+	return CosignVerifier{registryClient, key}, nil
+}
+
+func parsePublicKey(publicKeyPEM []byte) (*ecdsa.PublicKey, error) {
+	block, _ := pem.Decode(publicKeyPEM)
+	if block == nil || block.Type != "PUBLIC KEY" {
+		return nil, fmt.Errorf("Expected cosign key to be a PEM encoded public key")
+	}
+
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("Parsing cosign public key: %s", err)
+	}
+
+	ecdsaKey, ok := key.(*ecdsa.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("Expected cosign public key to be an ECDSA key")
+	}
+
+	return ecdsaKey, nil
+}
+
+type signatureManifest struct {
+	Layers []struct {
+		MediaType   string            `json:"mediaType"`
+		Digest      string            `json:"digest"`
+		Annotations map[string]string `json:"annotations"`
+	} `json:"layers"`
+}
+
+type simpleSigningPayload struct {
+	Critical struct {
+		Image map[string]string `json:"image"`
+	} `json:"critical"`
+}
+
+// Verify succeeds if at least one signature for image digest is valid
+func (v CosignVerifier) Verify(ref registry.Reference) error {
+	if len(ref.Digest) == 0 {
+		return fmt.Errorf("Expected image '%s' to be resolved to digest before verification", ref)
+	}
+
+	sigTag := strings.Replace(ref.Digest, ":", "-", 1) + signatureTagSuffix
+
+	manifestBytes, err := v.registryClient.Manifest(ref, sigTag)
+	if err != nil {
+		if _, ok := err.(registry.NotFoundError); ok {
+			return fmt.Errorf("Expected image '%s' to be signed, but no signatures were found", ref)
+		}
+		return fmt.Errorf("Fetching signatures: %s", err)
+	}
+
+	var manifest signatureManifest
+
+	err = json.Unmarshal(manifestBytes, &manifest)
+	if err != nil {
+		return fmt.Errorf("Unmarshaling signature manifest: %s", err)
+	}
+
+	var errs []string
+
+	for _, layer := range manifest.Layers {
+		if layer.MediaType != simpleSigningMediaType {
+			continue
+		}
+
+		err := v.verifyLayer(ref, layer.Digest, layer.Annotations[signatureAnnotationKey])
+		if err == nil {
+			return nil
+		}
+
+		errs = append(errs, err.Error())
+	}
+
+	if len(errs) == 0 {
+		return fmt.Errorf("Expected image '%s' to be signed, but no signatures were found", ref)
+	}
+
+	return fmt.Errorf("Expected image '%s' to have valid signature:\n- %s", ref, strings.Join(errs, "\n- "))
+}
+
+func (v CosignVerifier) verifyLayer(ref registry.Reference, payloadDigest, encodedSig string) error {
+	payload, err := v.registryClient.Blob(ref, payloadDigest)
+	if err != nil {
+		return fmt.Errorf("Fetching signature payload: %s", err)
+	}
+
+	payloadSHA := sha256.Sum256(payload)
+
+	if "sha256:"+hex.EncodeToString(payloadSHA[:]) != payloadDigest {
+		return fmt.Errorf("Expected signature payload to match digest '%s'", payloadDigest)
+	}
+
+	sig, err := base64.StdEncoding.DecodeString(encodedSig)
+	if err != nil || len(sig) == 0 {
+		return fmt.Errorf("Expected signature to be base64 encoded")
+	}
+
+	if !ecdsa.VerifyASN1(v.publicKey, payloadSHA[:], sig) {
+		return fmt.Errorf("Signature does not match public key")
+	}
+
+	var signedPayload simpleSigningPayload
+
+	err = json.Unmarshal(payload, &signedPayload)
+	if err != nil {
+		return fmt.Errorf("Unmarshaling signature payload: %s", err)
+	}
+
+	signedDigest := signedPayload.Critical.Image[manifestDigestJSONField]
+
+	if signedDigest != ref.Digest {
+		return fmt.Errorf("Expected signed digest '%s' to match image digest", signedDigest)
+	}
+
+	return nil
+}
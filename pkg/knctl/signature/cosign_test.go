@@ -0,0 +1,159 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package signature_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/cppforlife/knctl/pkg/knctl/registry"
+	"github.com/cppforlife/knctl/pkg/knctl/signature"
+)
+
+const (
+	imageDigest = "sha256:1111111111111111111111111111111111111111111111111111111111111111"
+)
+
+type fakeSignedRegistry struct {
+	server  *httptest.Server
+	payload []byte
+	sig     []byte
+}
+
+func newFakeSignedRegistry(t *testing.T, key *ecdsa.PrivateKey, signedDigest string) *fakeSignedRegistry {
+	reg := &fakeSignedRegistry{
+		payload: []byte(fmt.Sprintf(`{"critical":{"identity":{"docker-reference":"app"},`+
+			`"image":{"docker-manifest-digest":"%s"},"type":"cosign container image signature"},"optional":null}`, signedDigest)),
+	}
+
+	payloadSHA := sha256.Sum256(reg.payload)
+	payloadDigest := "sha256:" + hex.EncodeToString(payloadSHA[:])
+
+	sig, err := ecdsa.SignASN1(rand.Reader, key, payloadSHA[:])
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	reg.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v2/proj/app/manifests/sha256-1111111111111111111111111111111111111111111111111111111111111111.sig":
+			fmt.Fprintf(w, `{"schemaVersion":2,"layers":[{"mediaType":"application/vnd.dev.cosign.simplesigning.v1+json",`+
+				`"digest":"%s","annotations":{"dev.cosignproject.cosign/signature":"%s"}}]}`,
+				payloadDigest, base64.StdEncoding.EncodeToString(sig))
+		case "/v2/proj/app/blobs/" + payloadDigest:
+			w.Write(reg.payload)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+
+	return reg
+}
+
+func (r *fakeSignedRegistry) Reference(t *testing.T, repo string) registry.Reference {
+	ref, err := registry.ParseReference(strings.TrimPrefix(r.server.URL, "http://") + "/" + repo + "@" + imageDigest)
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+	return ref
+}
+
+func publicKeyPEM(t *testing.T, key *ecdsa.PrivateKey) []byte {
+	bs, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: bs})
+}
+
+func newKey(t *testing.T) *ecdsa.PrivateKey {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+	return key
+}
+
+func TestCosignVerifierVerify(t *testing.T) {
+	key := newKey(t)
+	otherKey := newKey(t)
+
+	reg := newFakeSignedRegistry(t, key, imageDigest)
+	defer reg.server.Close()
+
+	client := registry.NewClient(http.DefaultClient)
+
+	verifier, err := signature.NewCosignVerifier(client, publicKeyPEM(t, key))
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	err = verifier.Verify(reg.Reference(t, "proj/app"))
+	if err != nil {
+		t.Fatalf("Expected signature to be valid: %s", err)
+	}
+
+	err = verifier.Verify(reg.Reference(t, "proj/unsigned"))
+	if err == nil || !strings.Contains(err.Error(), "no signatures were found") {
+		t.Fatalf("Expected missing signature error, but was: %v", err)
+	}
+
+	otherVerifier, err := signature.NewCosignVerifier(client, publicKeyPEM(t, otherKey))
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	err = otherVerifier.Verify(reg.Reference(t, "proj/app"))
+	if err == nil || !strings.Contains(err.Error(), "does not match public key") {
+		t.Fatalf("Expected key mismatch error, but was: %v", err)
+	}
+}
+
+func TestCosignVerifierVerifyDigestMismatch(t *testing.T) {
+	key := newKey(t)
+
+	reg := newFakeSignedRegistry(t, key, "sha256:2222")
+	defer reg.server.Close()
+
+	verifier, err := signature.NewCosignVerifier(registry.NewClient(http.DefaultClient), publicKeyPEM(t, key))
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	err = verifier.Verify(reg.Reference(t, "proj/app"))
+	if err == nil || !strings.Contains(err.Error(), "to match image digest") {
+		t.Fatalf("Expected digest mismatch error, but was: %v", err)
+	}
+}
+
+func TestNewCosignVerifierInvalidKey(t *testing.T) {
+	_, err := signature.NewCosignVerifier(registry.NewClient(http.DefaultClient), []byte("not-a-key"))
+	if err == nil {
+		t.Fatalf("Expected error")
+	}
+}
@@ -0,0 +1,114 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package telemetry
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/cppforlife/knctl/pkg/knctl/util"
+)
+
+const (
+	// Disables telemetry even if it was turned on (e.g. in CI)
+	DisableEnvVar = "KNCTL_TELEMETRY_DISABLED"
+	// Overrides endpoint saved in config (e.g. for enterprise collectors)
+	EndpointEnvVar = "KNCTL_TELEMETRY_ENDPOINT"
+)
+
+// Config is stored in user's home directory. Zero value
+// (e.g. when config file does not exist) keeps telemetry off.
+type Config struct {
+	Enabled  bool   `json:"enabled"`
+	Endpoint string `json:"endpoint,omitempty"`
+}
+
+func DefaultConfigPath() (string, error) {
+	homeDir := os.Getenv("HOME")
+	if len(homeDir) == 0 {
+		return "", fmt.Errorf("Expected $HOME to be set to determine telemetry config location")
+	}
+	return filepath.Join(homeDir, ".knctl", "telemetry.json"), nil
+}
+
+func ReadConfig(path string) (Config, error) {
+	var config Config
+
+	bs, err := ioutil.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return config, nil
+		}
+		return config, fmt.Errorf("Reading telemetry config: %s", err)
+	}
+
+	err = json.Unmarshal(bs, &config)
+	if err != nil {
+		return config, fmt.Errorf("Unmarshaling telemetry config: %s", err)
+	}
+
+	return config, nil
+}
+
+func WriteConfig(path string, config Config) error {
+	bs, err := json.MarshalIndent(config, "", "  ")
+	if err != nil {
+		return fmt.Errorf("Marshaling telemetry config: %s", err)
+	}
+
+	err = os.MkdirAll(filepath.Dir(path), 0700)
+	if err != nil {
+		return fmt.Errorf("Creating telemetry config directory: %s", err)
+	}
+
+	err = util.WriteFileAtomically(path, bs, 0600)
+	if err != nil {
+		return fmt.Errorf("Writing telemetry config: %s", err)
+	}
+
+	return nil
+}
+
+// DisabledByEnv returns true if environment opts out of telemetry
+// regardless of config ($KNCTL_TELEMETRY_DISABLED or $DO_NOT_TRACK)
+func DisabledByEnv() bool {
+	for _, name := range []string{DisableEnvVar, "DO_NOT_TRACK"} {
+		switch os.Getenv(name) {
+		case "", "0", "false":
+		default:
+			return true
+		}
+	}
+	return false
+}
+
+// Active returns true if events should be sent
+func (c Config) Active() bool {
+	return c.Enabled && !DisabledByEnv() && len(c.EffectiveEndpoint()) > 0
+}
+
+// EffectiveEndpoint returns endpoint events are sent to;
+// there is no default endpoint hence it may be empty
+func (c Config) EffectiveEndpoint() string {
+	if endpoint := os.Getenv(EndpointEnvVar); len(endpoint) > 0 {
+		return endpoint
+	}
+	return c.Endpoint
+}
@@ -0,0 +1,138 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package telemetry
+
+import (
+	"context"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	ErrorClassTimeout = "Timeout"
+	ErrorClassNetwork = "Network"
+	ErrorClassOther   = "Other"
+)
+
+// Event describes single command execution. It intentionally
+// does not include arguments or flag values since they may contain
+// resource names, namespaces, URLs or other user data.
+type Event struct {
+	Command    string `json:"command"`
+	DurationMs int64  `json:"durationMs"`
+	ErrorClass string `json:"errorClass,omitempty"`
+
+	Version string `json:"version"`
+	OS      string `json:"os"`
+	Arch    string `json:"arch"`
+}
+
+func NewEvent(command string, duration time.Duration, err error) Event {
+	return Event{
+		Command:    command,
+		DurationMs: int64(duration / time.Millisecond),
+		ErrorClass: ErrorClass(err),
+	}
+}
+
+// ErrorClass maps error to a coarse category; error messages
+// are never included since they often mention resource names.
+// Since errors are commonly wrapped via fmt.Errorf (losing their type)
+// root cause is also recognized by well known message fragments.
+func ErrorClass(err error) string {
+	if err == nil {
+		return ""
+	}
+
+	for cause := err; cause != nil; cause = unwrapError(cause) {
+		if class := errorClassByType(cause); len(class) > 0 {
+			return class
+		}
+	}
+
+	for _, fragment := range errorClassFragments {
+		if strings.Contains(err.Error(), fragment.Fragment) {
+			return fragment.Class
+		}
+	}
+
+	return ErrorClassOther
+}
+
+func errorClassByType(err error) string {
+	if reason := errors.ReasonForError(err); reason != metav1.StatusReasonUnknown {
+		return string(reason)
+	}
+
+	if urlErr, ok := err.(*url.Error); ok {
+		err = urlErr.Err
+	}
+
+	if err == context.DeadlineExceeded {
+		return ErrorClassTimeout
+	}
+
+	if netErr, ok := err.(net.Error); ok {
+		if netErr.Timeout() {
+			return ErrorClassTimeout
+		}
+		return ErrorClassNetwork
+	}
+
+	return ""
+}
+
+func unwrapError(err error) error {
+	switch typedErr := err.(type) {
+	case interface{ Unwrap() error }:
+		return typedErr.Unwrap()
+	case interface{ Cause() error }:
+		return typedErr.Cause()
+	default:
+		return nil
+	}
+}
+
+// errorClassFragments are checked in order; fragments come from
+// messages of Go networking and Kubernetes API status errors
+var errorClassFragments = []struct {
+	Fragment string
+	Class    string
+}{
+	{"i/o timeout", ErrorClassTimeout},
+	{"context deadline exceeded", ErrorClassTimeout},
+	{"Client.Timeout exceeded", ErrorClassTimeout},
+	{"TLS handshake timeout", ErrorClassTimeout},
+
+	{"connection refused", ErrorClassNetwork},
+	{"connection reset by peer", ErrorClassNetwork},
+	{"no such host", ErrorClassNetwork},
+	{"network is unreachable", ErrorClassNetwork},
+
+	{"\" not found", string(metav1.StatusReasonNotFound)},
+	{"the server could not find the requested resource", string(metav1.StatusReasonNotFound)},
+	{"\" already exists", string(metav1.StatusReasonAlreadyExists)},
+	{"Operation cannot be fulfilled on", string(metav1.StatusReasonConflict)},
+	{"\" is forbidden:", string(metav1.StatusReasonForbidden)},
+	{"\" is invalid:", string(metav1.StatusReasonInvalid)},
+	{"Unauthorized", string(metav1.StatusReasonUnauthorized)},
+}
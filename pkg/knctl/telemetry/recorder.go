@@ -0,0 +1,88 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package telemetry
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"runtime"
+	"time"
+)
+
+const (
+	// Keeps commands snappy if collector is slow or unreachable
+	sendTimeout = 1 * time.Second
+)
+
+type Recorder struct {
+	configPath     string
+	version        string
+	httpClientFunc func() (*http.Client, error)
+}
+
+func NewRecorder(configPath, version string, httpClientFunc func() (*http.Client, error)) Recorder {
+	return Recorder{configPath, version, httpClientFunc}
+}
+
+// Record sends event if user opted in. Telemetry must never
+// affect command outcome hence it only returns errors for debugging.
+func (r Recorder) Record(event Event) error {
+	config, err := ReadConfig(r.configPath)
+	if err != nil || !config.Active() {
+		return err
+	}
+
+	event.Version = r.version
+	event.OS = runtime.GOOS
+	event.Arch = runtime.GOARCH
+
+	bs, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("Marshaling telemetry event: %s", err)
+	}
+
+	httpClient, err := r.httpClientFunc()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), sendTimeout)
+	defer cancel()
+
+	req, err := http.NewRequest("POST", config.EffectiveEndpoint(), bytes.NewReader(bs))
+	if err != nil {
+		return fmt.Errorf("Building telemetry request: %s", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := httpClient.Do(req.WithContext(ctx))
+	if err != nil {
+		return fmt.Errorf("Sending telemetry event: %s", err)
+	}
+
+	resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("Sending telemetry event: Expected 2xx status but was %d", resp.StatusCode)
+	}
+
+	return nil
+}
@@ -0,0 +1,204 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package telemetry_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	. "github.com/cppforlife/knctl/pkg/knctl/telemetry"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestReadConfig_MissingFileIsOff(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+
+	config, err := ReadConfig(filepath.Join(dir, "missing.json"))
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+	if config.Enabled {
+		t.Fatalf("Expected telemetry to be off by default")
+	}
+}
+
+func TestWriteConfig_RoundTrips(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "nested", "telemetry.json")
+
+	err := WriteConfig(path, Config{Enabled: true, Endpoint: "https://collector.example.com"})
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	config, err := ReadConfig(path)
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+	if !config.Enabled || config.EffectiveEndpoint() != "https://collector.example.com" {
+		t.Fatalf("Expected config to round trip, but was %#v", config)
+	}
+}
+
+func TestConfig_DisabledByEnv(t *testing.T) {
+	config := Config{Enabled: true, Endpoint: "https://collector.example.com"}
+
+	for _, name := range []string{DisableEnvVar, "DO_NOT_TRACK"} {
+		os.Setenv(name, "1")
+		if config.Active() {
+			t.Fatalf("Expected $%s to disable telemetry", name)
+		}
+		os.Unsetenv(name)
+	}
+
+	if !config.Active() {
+		t.Fatalf("Expected telemetry to be active")
+	}
+}
+
+func TestConfig_RequiresEndpoint(t *testing.T) {
+	config := Config{Enabled: true}
+
+	if config.Active() {
+		t.Fatalf("Expected telemetry without endpoint to not be active")
+	}
+
+	os.Setenv(EndpointEnvVar, "https://collector.example.com")
+	defer os.Unsetenv(EndpointEnvVar)
+
+	if !config.Active() || config.EffectiveEndpoint() != "https://collector.example.com" {
+		t.Fatalf("Expected endpoint from env to activate telemetry")
+	}
+}
+
+func TestErrorClass(t *testing.T) {
+	notFoundErr := errors.NewNotFound(schema.GroupResource{Resource: "services"}, "secret-svc")
+
+	cases := map[string]error{
+		"":         nil,
+		"NotFound": notFoundErr,
+		"Other":    fmt.Errorf("Getting service 'secret-svc': boom"),
+	}
+
+	for expected, err := range cases {
+		if actual := ErrorClass(err); actual != expected {
+			t.Fatalf("Expected error class '%s', but was '%s'", expected, actual)
+		}
+	}
+}
+
+func TestErrorClass_WrappedErrors(t *testing.T) {
+	notFoundErr := errors.NewNotFound(schema.GroupResource{Resource: "services"}, "secret-svc")
+	forbiddenErr := errors.NewForbidden(schema.GroupResource{Resource: "routes"}, "secret-rt", fmt.Errorf("no access"))
+	conflictErr := errors.NewConflict(schema.GroupResource{Resource: "routes"}, "secret-rt", fmt.Errorf("changed"))
+	dialErr := &url.Error{Op: "Get", URL: "https://10.0.0.1", Err: &net.OpError{Op: "dial", Net: "tcp", Err: fmt.Errorf("connection refused")}}
+
+	cases := []struct {
+		Err      error
+		Expected string
+	}{
+		{fmt.Errorf("Getting service 'secret-svc': %s", notFoundErr), "NotFound"},
+		{fmt.Errorf("Updating route: %s", forbiddenErr), "Forbidden"},
+		{fmt.Errorf("Updating route: %s", conflictErr), "Conflict"},
+		{fmt.Errorf("Getting service: %w", notFoundErr), "NotFound"},
+		{fmt.Errorf("Listing pods: %s", dialErr), "Network"},
+		{fmt.Errorf("Listing pods: %s", context.DeadlineExceeded), "Timeout"},
+		{fmt.Errorf("Watching revision: %w", context.DeadlineExceeded), "Timeout"},
+		{fmt.Errorf("Expected service 'secret-svc' to have revisions"), "Other"},
+	}
+
+	for _, c := range cases {
+		if actual := ErrorClass(c.Err); actual != c.Expected {
+			t.Fatalf("Expected error class '%s' for '%s', but was '%s'", c.Expected, c.Err, actual)
+		}
+	}
+}
+
+func TestRecorder_SendsEventOnlyWhenOn(t *testing.T) {
+	var events []map[string]interface{}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var event map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&event)
+		events = append(events, event)
+	}))
+	defer server.Close()
+
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "telemetry.json")
+	recorder := NewRecorder(path, "1.2.3", func() (*http.Client, error) { return server.Client(), nil })
+	event := NewEvent("knctl service show", 1500*time.Millisecond, fmt.Errorf("service 'secret-svc' failed"))
+
+	err := recorder.Record(event)
+	if err != nil || len(events) != 0 {
+		t.Fatalf("Expected no events to be sent when off: %s, %#v", err, events)
+	}
+
+	err = WriteConfig(path, Config{Enabled: true, Endpoint: server.URL})
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	err = recorder.Record(event)
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	if len(events) != 1 {
+		t.Fatalf("Expected single event, but was %#v", events)
+	}
+
+	expectedEvent := map[string]interface{}{
+		"command":    "knctl service show",
+		"durationMs": float64(1500),
+		"errorClass": "Other",
+		"version":    "1.2.3",
+		"os":         events[0]["os"],
+		"arch":       events[0]["arch"],
+	}
+
+	bs, _ := json.Marshal(events[0])
+	expectedBs, _ := json.Marshal(expectedEvent)
+
+	if string(bs) != string(expectedBs) {
+		t.Fatalf("Expected event '%s', but was '%s'", expectedBs, bs)
+	}
+}
+
+func tempDir(t *testing.T) string {
+	dir, err := ioutil.TempDir("", "knctl-telemetry")
+	if err != nil {
+		t.Fatalf("Creating temp dir: %s", err)
+	}
+	return dir
+}
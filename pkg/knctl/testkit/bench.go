@@ -0,0 +1,42 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testkit
+
+import (
+	"os"
+	"testing"
+	"time"
+)
+
+// RequireBenchmarkThreshold runs benchmark and fails test if single operation
+// takes longer than given threshold on average. Since timings depend on
+// the machine, check is only enabled with KNCTL_BENCHMARK_THRESHOLDS=true.
+func RequireBenchmarkThreshold(t *testing.T, benchFunc func(*testing.B), threshold time.Duration) {
+	if os.Getenv("KNCTL_BENCHMARK_THRESHOLDS") != "true" {
+		t.Skip("Skipping benchmark threshold check (set KNCTL_BENCHMARK_THRESHOLDS=true to enable)")
+	}
+
+	result := testing.Benchmark(benchFunc)
+	if result.N == 0 {
+		t.Fatalf("Expected benchmark to run at least once")
+	}
+
+	perOp := time.Duration(result.NsPerOp())
+	if perOp > threshold {
+		t.Fatalf("Expected operation to take at most %s but took %s (%s)", threshold, perOp, result.String())
+	}
+}
@@ -0,0 +1,196 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testkit
+
+import (
+	"time"
+
+	duckv1alpha1 "github.com/knative/pkg/apis/duck/v1alpha1"
+	"github.com/knative/serving/pkg/apis/serving"
+	"github.com/knative/serving/pkg/apis/serving/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type ServiceBuilder struct {
+	service v1alpha1.Service
+}
+
+func NewService(namespace, name string) *ServiceBuilder {
+	return &ServiceBuilder{v1alpha1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+		Spec: v1alpha1.ServiceSpec{
+			RunLatest: &v1alpha1.RunLatestType{},
+		},
+	}}
+}
+
+func (b *ServiceBuilder) Manual() *ServiceBuilder {
+	b.service.Spec.RunLatest = nil
+	b.service.Spec.Manual = &v1alpha1.ManualType{}
+	return b
+}
+
+func (b *ServiceBuilder) Domain(domain string) *ServiceBuilder {
+	b.service.Status.Domain = domain
+	return b
+}
+
+func (b *ServiceBuilder) LatestRevision(created, ready string) *ServiceBuilder {
+	b.service.Status.LatestCreatedRevisionName = created
+	b.service.Status.LatestReadyRevisionName = ready
+	return b
+}
+
+func (b *ServiceBuilder) Ready() *ServiceBuilder {
+	b.service.Status.Conditions = readyConditions(
+		v1alpha1.ServiceConditionConfigurationsReady, v1alpha1.ServiceConditionRoutesReady)
+	return b
+}
+
+func (b *ServiceBuilder) Build() *v1alpha1.Service {
+	return b.service.DeepCopy()
+}
+
+type RevisionBuilder struct {
+	revision v1alpha1.Revision
+}
+
+// NewRevision builds revision labeled as if it was created for given service
+func NewRevision(namespace, serviceName, name string) *RevisionBuilder {
+	return &RevisionBuilder{v1alpha1.Revision{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+			Labels: map[string]string{
+				serving.ConfigurationLabelKey: serviceName,
+				serving.ServiceLabelKey:       serviceName,
+			},
+		},
+	}}
+}
+
+func (b *RevisionBuilder) CreatedAt(t time.Time) *RevisionBuilder {
+	b.revision.CreationTimestamp = metav1.NewTime(t)
+	return b
+}
+
+func (b *RevisionBuilder) Annotations(anns map[string]string) *RevisionBuilder {
+	b.revision.Annotations = anns
+	return b
+}
+
+// Tag labels revision the same way 'knctl revision tag' does
+func (b *RevisionBuilder) Tag(tag string) *RevisionBuilder {
+	b.revision.Labels["tag.cli.knative.dev/"+tag] = "true"
+	return b
+}
+
+// ServiceName sets name of revision's Kubernetes service
+func (b *RevisionBuilder) ServiceName(name string) *RevisionBuilder {
+	b.revision.Status.ServiceName = name
+	return b
+}
+
+func (b *RevisionBuilder) Ready() *RevisionBuilder {
+	b.revision.Status.Conditions = readyConditions(
+		v1alpha1.RevisionConditionResourcesAvailable, v1alpha1.RevisionConditionContainerHealthy)
+	return b
+}
+
+func (b *RevisionBuilder) Build() *v1alpha1.Revision {
+	return b.revision.DeepCopy()
+}
+
+type RouteBuilder struct {
+	route v1alpha1.Route
+}
+
+func NewRoute(namespace, name string) *RouteBuilder {
+	return &RouteBuilder{v1alpha1.Route{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+	}}
+}
+
+func (b *RouteBuilder) Domain(domain string) *RouteBuilder {
+	b.route.Status.Domain = domain
+	return b
+}
+
+// Traffic sets both desired and observed traffic to given revision
+func (b *RouteBuilder) Traffic(revisionName string, percent int) *RouteBuilder {
+	target := v1alpha1.TrafficTarget{RevisionName: revisionName, Percent: percent}
+	b.route.Spec.Traffic = append(b.route.Spec.Traffic, target)
+	b.route.Status.Traffic = append(b.route.Status.Traffic, target)
+	return b
+}
+
+func (b *RouteBuilder) Ready() *RouteBuilder {
+	b.route.Status.Conditions = readyConditions(
+		v1alpha1.RouteConditionAllTrafficAssigned, v1alpha1.RouteConditionIngressReady)
+	return b
+}
+
+func (b *RouteBuilder) Build() *v1alpha1.Route {
+	return b.route.DeepCopy()
+}
+
+// NewLoadBalancerService builds Kubernetes service with assigned external IP
+func NewLoadBalancerService(namespace, name string, labels map[string]string, ip string, ports ...int32) *corev1.Service {
+	service := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace, Labels: labels},
+		Spec:       corev1.ServiceSpec{Type: corev1.ServiceTypeLoadBalancer},
+		Status: corev1.ServiceStatus{
+			LoadBalancer: corev1.LoadBalancerStatus{
+				Ingress: []corev1.LoadBalancerIngress{{IP: ip}},
+			},
+		},
+	}
+	for _, port := range ports {
+		service.Spec.Ports = append(service.Spec.Ports, corev1.ServicePort{Port: port})
+	}
+	return service
+}
+
+// NewNodePortService builds Kubernetes service with given port to node port mapping
+func NewNodePortService(namespace, name string, labels map[string]string, nodePorts map[int32]int32) *corev1.Service {
+	service := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace, Labels: labels},
+		Spec:       corev1.ServiceSpec{Type: corev1.ServiceTypeNodePort},
+	}
+	for port, nodePort := range nodePorts {
+		service.Spec.Ports = append(service.Spec.Ports, corev1.ServicePort{Port: port, NodePort: nodePort})
+	}
+	return service
+}
+
+func NewNode(name, externalIP string) *corev1.Node {
+	return &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Status: corev1.NodeStatus{
+			Addresses: []corev1.NodeAddress{{Type: corev1.NodeExternalIP, Address: externalIP}},
+		},
+	}
+}
+
+func readyConditions(types ...duckv1alpha1.ConditionType) duckv1alpha1.Conditions {
+	conds := duckv1alpha1.Conditions{{Type: duckv1alpha1.ConditionReady, Status: corev1.ConditionTrue}}
+	for _, t := range types {
+		conds = append(conds, duckv1alpha1.Condition{Type: t, Status: corev1.ConditionTrue})
+	}
+	return conds
+}
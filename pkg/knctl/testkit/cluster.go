@@ -0,0 +1,610 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package testkit provides an in-memory Kubernetes API server
+// and object builders so that packages can be unit tested
+// with real clientsets without a live cluster.
+package testkit
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	cmdcore "github.com/cppforlife/knctl/pkg/knctl/cmd/core"
+	jsonpatch "github.com/evanphx/json-patch"
+	buildv1alpha1 "github.com/knative/build/pkg/apis/build/v1alpha1"
+	buildclientset "github.com/knative/build/pkg/client/clientset/versioned"
+	kpav1alpha1 "github.com/knative/serving/pkg/apis/autoscaling/v1alpha1"
+	"github.com/knative/serving/pkg/apis/serving/v1alpha1"
+	servingclientset "github.com/knative/serving/pkg/client/clientset/versioned"
+	admregv1beta1 "k8s.io/api/admissionregistration/v1beta1"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1beta1 "k8s.io/api/batch/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	apirand "k8s.io/apimachinery/pkg/util/rand"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+)
+
+// Cluster is an in-memory API server that supports get, list (with label selectors),
+// create, update, patch and delete of namespaced and cluster scoped resources.
+// Watches are not supported.
+type Cluster struct {
+	t      testing.TB
+	server *httptest.Server
+
+	lock            sync.Mutex
+	objects         map[string]map[string][]byte // collection -> namespace/name -> object
+	resourceVersion int
+	minorVersion    string
+	dryRunRejection string
+}
+
+func NewCluster(t testing.TB, objs ...runtime.Object) *Cluster {
+	c := &Cluster{t: t, objects: map[string]map[string][]byte{}, minorVersion: "14"}
+	c.server = httptest.NewServer(http.HandlerFunc(c.serveHTTP))
+	t.Cleanup(c.server.Close)
+	c.Add(objs...)
+	return c
+}
+
+func (c *Cluster) RESTConfig() *rest.Config {
+	// Avoid client side throttling since server is in-memory
+	return &rest.Config{Host: c.server.URL, QPS: 1000, Burst: 1000}
+}
+
+func (c *Cluster) CoreClient() kubernetes.Interface {
+	client, err := kubernetes.NewForConfig(c.RESTConfig())
+	if err != nil {
+		c.t.Fatalf("Building Core client: %s", err)
+	}
+	return client
+}
+
+func (c *Cluster) ServingClient() servingclientset.Interface {
+	client, err := servingclientset.NewForConfig(c.RESTConfig())
+	if err != nil {
+		c.t.Fatalf("Building Serving client: %s", err)
+	}
+	return client
+}
+
+func (c *Cluster) BuildClient() buildclientset.Interface {
+	client, err := buildclientset.NewForConfig(c.RESTConfig())
+	if err != nil {
+		c.t.Fatalf("Building Build client: %s", err)
+	}
+	return client
+}
+
+// Add stores objects as if they were created by other API clients
+func (c *Cluster) Add(objs ...runtime.Object) {
+	for _, obj := range objs {
+		res, found := resourceForObject(obj)
+		if !found {
+			c.t.Fatalf("Unsupported object type %T", obj)
+		}
+
+		bs, err := json.Marshal(obj)
+		if err != nil {
+			c.t.Fatalf("Marshaling object: %s", err)
+		}
+
+		_, status := c.create(res, "", bs, false)
+		if status != nil {
+			c.t.Fatalf("Adding object: %s", status.Message)
+		}
+	}
+}
+
+// SetMinorVersion changes Kubernetes 1.x version reported by the server
+func (c *Cluster) SetMinorVersion(minor string) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.minorVersion = minor
+}
+
+// RejectDryRuns makes server fail all dry run requests with given message
+// (e.g. as done when an admission webhook does not declare sideEffects)
+func (c *Cluster) RejectDryRuns(msg string) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.dryRunRejection = msg
+}
+
+func (c *Cluster) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	c.lock.Lock()
+	minorVersion, dryRunRejection := c.minorVersion, c.dryRunRejection
+	c.lock.Unlock()
+
+	if r.URL.Path == "/version" {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"major":"1","minor":"%s","gitVersion":"v1.%s.0"}`, minorVersion, minorVersion)
+		return
+	}
+
+	res, namespace, name, err := parsePath(r.URL.Path)
+	if err != nil {
+		c.writeStatus(w, newStatus(http.StatusNotFound, metav1.StatusReasonNotFound, err.Error()))
+		return
+	}
+
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		c.writeStatus(w, newStatus(http.StatusBadRequest, metav1.StatusReasonBadRequest, err.Error()))
+		return
+	}
+
+	// Core clientset sends protobuf; responses are always JSON
+	// which clients accept as well
+	if len(body) > 0 && cmdcore.IsProtobuf(r.Header.Get("Content-Type")) {
+		body, err = cmdcore.ProtobufToJSON(body)
+		if err != nil {
+			c.writeStatus(w, newStatus(http.StatusBadRequest, metav1.StatusReasonBadRequest, err.Error()))
+			return
+		}
+	}
+
+	var resp []byte
+	var status *metav1.Status
+	successCode := http.StatusOK
+	dryRun := r.URL.Query().Get("dryRun") == "All"
+
+	switch {
+	case dryRun && len(dryRunRejection) > 0:
+		status = newStatus(http.StatusBadRequest, metav1.StatusReasonBadRequest, dryRunRejection)
+	case r.Method == http.MethodGet && r.URL.Query().Get("watch") == "true":
+		status = newStatus(http.StatusMethodNotAllowed, metav1.StatusReasonMethodNotAllowed, "Watches are not supported")
+	case r.Method == http.MethodGet && len(name) == 0:
+		resp, status = c.list(res, namespace, r.URL.Query().Get("labelSelector"))
+	case r.Method == http.MethodGet:
+		resp, status = c.get(res, namespace, name)
+	case r.Method == http.MethodPost:
+		resp, status = c.create(res, namespace, body, dryRun)
+		successCode = http.StatusCreated
+	case r.Method == http.MethodPut:
+		resp, status = c.update(res, namespace, name, body, dryRun)
+	case r.Method == http.MethodPatch:
+		resp, status = c.patch(res, namespace, name, r.Header.Get("Content-Type"), body, dryRun)
+	case r.Method == http.MethodDelete && len(name) > 0:
+		status = c.delete(res, namespace, name)
+	default:
+		status = newStatus(http.StatusMethodNotAllowed, metav1.StatusReasonMethodNotAllowed, "Unsupported request")
+	}
+
+	if status != nil {
+		c.writeStatus(w, status)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(successCode)
+	w.Write(resp)
+}
+
+func (c *Cluster) get(res resource, namespace, name string) ([]byte, *metav1.Status) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	obj, found := c.objects[res.path][namespace+"/"+name]
+	if !found {
+		return nil, newNotFoundStatus(res, name)
+	}
+
+	return obj, nil
+}
+
+func (c *Cluster) list(res resource, namespace, selectorStr string) ([]byte, *metav1.Status) {
+	selector, err := labels.Parse(selectorStr)
+	if err != nil {
+		return nil, newStatus(http.StatusBadRequest, metav1.StatusReasonBadRequest, err.Error())
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	var keys []string
+
+	for key := range c.objects[res.path] {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	items := []json.RawMessage{}
+
+	for _, key := range keys {
+		obj := c.objects[res.path][key]
+
+		meta, err := objectMeta(obj)
+		if err != nil {
+			return nil, newStatus(http.StatusInternalServerError, metav1.StatusReasonInternalError, err.Error())
+		}
+
+		if len(namespace) > 0 && meta.Namespace != namespace {
+			continue
+		}
+		if !selector.Matches(labels.Set(meta.Labels)) {
+			continue
+		}
+
+		items = append(items, obj)
+	}
+
+	bs, err := json.Marshal(map[string]interface{}{
+		"apiVersion": res.apiVersion,
+		"kind":       res.kind + "List",
+		"metadata":   map[string]interface{}{"resourceVersion": strconv.Itoa(c.resourceVersion)},
+		"items":      items,
+	})
+	if err != nil {
+		return nil, newStatus(http.StatusInternalServerError, metav1.StatusReasonInternalError, err.Error())
+	}
+
+	return bs, nil
+}
+
+func (c *Cluster) create(res resource, namespace string, body []byte, dryRun bool) ([]byte, *metav1.Status) {
+	obj, meta, err := decodeObject(body)
+	if err != nil {
+		return nil, newStatus(http.StatusBadRequest, metav1.StatusReasonBadRequest, err.Error())
+	}
+
+	if res.namespaced && len(namespace) > 0 {
+		meta["namespace"] = namespace
+	}
+	if len(metaString(meta, "name")) == 0 && len(metaString(meta, "generateName")) > 0 {
+		meta["name"] = metaString(meta, "generateName") + apirand.String(5)
+	}
+	if len(metaString(meta, "name")) == 0 {
+		return nil, newStatus(http.StatusUnprocessableEntity, metav1.StatusReasonInvalid, "Expected name to be non-empty")
+	}
+	if _, found := meta["creationTimestamp"]; !found || meta["creationTimestamp"] == nil {
+		meta["creationTimestamp"] = time.Now().UTC().Format(time.RFC3339)
+	}
+	meta["uid"] = apirand.String(10)
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	key := metaString(meta, "namespace") + "/" + metaString(meta, "name")
+
+	if _, found := c.objects[res.path][key]; found {
+		return nil, newStatus(http.StatusConflict, metav1.StatusReasonAlreadyExists,
+			fmt.Sprintf("%s \"%s\" already exists", res.name, metaString(meta, "name")))
+	}
+
+	return c.store(res, key, obj, meta, dryRun)
+}
+
+func (c *Cluster) update(res resource, namespace, name string, body []byte, dryRun bool) ([]byte, *metav1.Status) {
+	obj, meta, err := decodeObject(body)
+	if err != nil {
+		return nil, newStatus(http.StatusBadRequest, metav1.StatusReasonBadRequest, err.Error())
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	key := namespace + "/" + name
+
+	if _, found := c.objects[res.path][key]; !found {
+		return nil, newNotFoundStatus(res, name)
+	}
+
+	return c.store(res, key, obj, meta, dryRun)
+}
+
+func (c *Cluster) patch(res resource, namespace, name, contentType string, body []byte, dryRun bool) ([]byte, *metav1.Status) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	key := namespace + "/" + name
+
+	existing, found := c.objects[res.path][key]
+	if !found {
+		return nil, newNotFoundStatus(res, name)
+	}
+
+	var patched []byte
+	var err error
+
+	// Strategic merge patches are treated as merge patches
+	if contentType == "application/json-patch+json" {
+		var patch jsonpatch.Patch
+		patch, err = jsonpatch.DecodePatch(body)
+		if err == nil {
+			patched, err = patch.Apply(existing)
+		}
+	} else {
+		patched, err = jsonpatch.MergePatch(existing, body)
+	}
+	if err != nil {
+		return nil, newStatus(http.StatusUnprocessableEntity, metav1.StatusReasonInvalid, err.Error())
+	}
+
+	obj, meta, err := decodeObject(patched)
+	if err != nil {
+		return nil, newStatus(http.StatusBadRequest, metav1.StatusReasonBadRequest, err.Error())
+	}
+
+	return c.store(res, key, obj, meta, dryRun)
+}
+
+func (c *Cluster) delete(res resource, namespace, name string) *metav1.Status {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	key := namespace + "/" + name
+
+	if _, found := c.objects[res.path][key]; !found {
+		return newNotFoundStatus(res, name)
+	}
+
+	delete(c.objects[res.path], key)
+
+	return nil
+}
+
+// store expects lock to be held; dry run requests return object without persisting it
+func (c *Cluster) store(res resource, key string, obj, meta map[string]interface{}, dryRun bool) ([]byte, *metav1.Status) {
+	if !dryRun {
+		c.resourceVersion++
+	}
+
+	meta["resourceVersion"] = strconv.Itoa(c.resourceVersion)
+	obj["metadata"] = meta
+	obj["apiVersion"] = res.apiVersion
+	obj["kind"] = res.kind
+
+	bs, err := json.Marshal(obj)
+	if err != nil {
+		return nil, newStatus(http.StatusInternalServerError, metav1.StatusReasonInternalError, err.Error())
+	}
+
+	if dryRun {
+		return bs, nil
+	}
+
+	if c.objects[res.path] == nil {
+		c.objects[res.path] = map[string][]byte{}
+	}
+
+	c.objects[res.path][key] = bs
+
+	return bs, nil
+}
+
+func (c *Cluster) writeStatus(w http.ResponseWriter, status *metav1.Status) {
+	status.APIVersion = "v1"
+	status.Kind = "Status"
+	status.Status = metav1.StatusFailure
+
+	bs, err := json.Marshal(status)
+	if err != nil {
+		c.t.Fatalf("Marshaling status: %s", err)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(int(status.Code))
+	w.Write(bs)
+}
+
+type resource struct {
+	path       string // e.g. /apis/serving.knative.dev/v1alpha1/services
+	name       string
+	apiVersion string
+	kind       string
+	namespaced bool
+}
+
+var resources = []resource{
+	{"/api/v1/namespaces", "namespaces", "v1", "Namespace", false},
+	{"/api/v1/nodes", "nodes", "v1", "Node", false},
+	{"/api/v1/services", "services", "v1", "Service", true},
+	{"/api/v1/pods", "pods", "v1", "Pod", true},
+	{"/api/v1/secrets", "secrets", "v1", "Secret", true},
+	{"/api/v1/configmaps", "configmaps", "v1", "ConfigMap", true},
+	{"/api/v1/serviceaccounts", "serviceaccounts", "v1", "ServiceAccount", true},
+	{"/api/v1/events", "events", "v1", "Event", true},
+	{"/api/v1/endpoints", "endpoints", "v1", "Endpoints", true},
+	{"/apis/apps/v1/deployments", "deployments.apps", "apps/v1", "Deployment", true},
+	{"/apis/batch/v1beta1/cronjobs", "cronjobs.batch", "batch/v1beta1", "CronJob", true},
+	{"/apis/rbac.authorization.k8s.io/v1/roles", "roles.rbac.authorization.k8s.io", "rbac.authorization.k8s.io/v1", "Role", true},
+	{"/apis/admissionregistration.k8s.io/v1beta1/mutatingwebhookconfigurations", "mutatingwebhookconfigurations.admissionregistration.k8s.io", "admissionregistration.k8s.io/v1beta1", "MutatingWebhookConfiguration", false},
+	{"/apis/rbac.authorization.k8s.io/v1/rolebindings", "rolebindings.rbac.authorization.k8s.io", "rbac.authorization.k8s.io/v1", "RoleBinding", true},
+	{"/apis/serving.knative.dev/v1alpha1/services", "services.serving.knative.dev", "serving.knative.dev/v1alpha1", "Service", true},
+	{"/apis/serving.knative.dev/v1alpha1/routes", "routes.serving.knative.dev", "serving.knative.dev/v1alpha1", "Route", true},
+	{"/apis/serving.knative.dev/v1alpha1/revisions", "revisions.serving.knative.dev", "serving.knative.dev/v1alpha1", "Revision", true},
+	{"/apis/serving.knative.dev/v1alpha1/configurations", "configurations.serving.knative.dev", "serving.knative.dev/v1alpha1", "Configuration", true},
+	{"/apis/autoscaling.internal.knative.dev/v1alpha1/podautoscalers", "podautoscalers.autoscaling.internal.knative.dev", "autoscaling.internal.knative.dev/v1alpha1", "PodAutoscaler", true},
+	{"/apis/networking.internal.knative.dev/v1alpha1/serverlessservices", "serverlessservices.networking.internal.knative.dev", "networking.internal.knative.dev/v1alpha1", "ServerlessService", true},
+	{"/apis/networking.istio.io/v1alpha3/virtualservices", "virtualservices.networking.istio.io", "networking.istio.io/v1alpha3", "VirtualService", true},
+	{"/apis/networking.istio.io/v1alpha3/destinationrules", "destinationrules.networking.istio.io", "networking.istio.io/v1alpha3", "DestinationRule", true},
+	{"/apis/networking.istio.io/v1alpha3/serviceentries", "serviceentries.networking.istio.io", "networking.istio.io/v1alpha3", "ServiceEntry", true},
+	{"/apis/networking.istio.io/v1alpha3/sidecars", "sidecars.networking.istio.io", "networking.istio.io/v1alpha3", "Sidecar", true},
+	{"/apis/security.istio.io/v1beta1/peerauthentications", "peerauthentications.security.istio.io", "security.istio.io/v1beta1", "PeerAuthentication", true},
+	{"/apis/gateway.networking.k8s.io/v1beta1/gateways", "gateways.gateway.networking.k8s.io", "gateway.networking.k8s.io/v1beta1", "Gateway", true},
+	{"/apis/build.knative.dev/v1alpha1/builds", "builds.build.knative.dev", "build.knative.dev/v1alpha1", "Build", true},
+}
+
+func resourceForObject(obj runtime.Object) (resource, bool) {
+	var kind, apiVersion string
+
+	switch typedObj := obj.(type) {
+	case *corev1.Namespace:
+		kind, apiVersion = "Namespace", "v1"
+	case *corev1.Node:
+		kind, apiVersion = "Node", "v1"
+	case *corev1.Service:
+		kind, apiVersion = "Service", "v1"
+	case *corev1.Pod:
+		kind, apiVersion = "Pod", "v1"
+	case *corev1.Secret:
+		kind, apiVersion = "Secret", "v1"
+	case *corev1.ConfigMap:
+		kind, apiVersion = "ConfigMap", "v1"
+	case *corev1.ServiceAccount:
+		kind, apiVersion = "ServiceAccount", "v1"
+	case *corev1.Event:
+		kind, apiVersion = "Event", "v1"
+	case *corev1.Endpoints:
+		kind, apiVersion = "Endpoints", "v1"
+	case *admregv1beta1.MutatingWebhookConfiguration:
+		kind, apiVersion = "MutatingWebhookConfiguration", "admissionregistration.k8s.io/v1beta1"
+	case *appsv1.Deployment:
+		kind, apiVersion = "Deployment", "apps/v1"
+	case *batchv1beta1.CronJob:
+		kind, apiVersion = "CronJob", "batch/v1beta1"
+	case *rbacv1.Role:
+		kind, apiVersion = "Role", "rbac.authorization.k8s.io/v1"
+	case *rbacv1.RoleBinding:
+		kind, apiVersion = "RoleBinding", "rbac.authorization.k8s.io/v1"
+	case *v1alpha1.Service:
+		kind, apiVersion = "Service", "serving.knative.dev/v1alpha1"
+	case *v1alpha1.Route:
+		kind, apiVersion = "Route", "serving.knative.dev/v1alpha1"
+	case *v1alpha1.Revision:
+		kind, apiVersion = "Revision", "serving.knative.dev/v1alpha1"
+	case *v1alpha1.Configuration:
+		kind, apiVersion = "Configuration", "serving.knative.dev/v1alpha1"
+	case *kpav1alpha1.PodAutoscaler:
+		kind, apiVersion = "PodAutoscaler", "autoscaling.internal.knative.dev/v1alpha1"
+	case *buildv1alpha1.Build:
+		kind, apiVersion = "Build", "build.knative.dev/v1alpha1"
+	case *unstructured.Unstructured:
+		kind, apiVersion = typedObj.GetKind(), typedObj.GetAPIVersion()
+	}
+
+	for _, res := range resources {
+		if res.kind == kind && res.apiVersion == apiVersion {
+			return res, true
+		}
+	}
+
+	return resource{}, false
+}
+
+// parsePath understands paths such as /api/v1/nodes/node1,
+// /api/v1/namespaces/ns1/services and /apis/group/version/namespaces/ns1/res/name
+func parsePath(path string) (resource, string, string, error) {
+	segs := strings.Split(strings.Trim(path, "/"), "/")
+
+	var prefixLen int
+
+	switch {
+	case len(segs) >= 3 && segs[0] == "api":
+		prefixLen = 2
+	case len(segs) >= 4 && segs[0] == "apis":
+		prefixLen = 3
+	default:
+		return resource{}, "", "", fmt.Errorf("Unknown path '%s'", path)
+	}
+
+	prefix := "/" + strings.Join(segs[:prefixLen], "/")
+	segs = segs[prefixLen:]
+
+	var namespace, resName, name string
+
+	switch {
+	case segs[0] == "namespaces" && len(segs) >= 3 && len(segs) <= 4:
+		namespace, resName = segs[1], segs[2]
+		if len(segs) == 4 {
+			name = segs[3]
+		}
+	case len(segs) <= 2:
+		resName = segs[0]
+		if len(segs) == 2 {
+			name = segs[1]
+		}
+	default:
+		return resource{}, "", "", fmt.Errorf("Unsupported path '%s'", path)
+	}
+
+	for _, res := range resources {
+		if res.path == prefix+"/"+resName {
+			return res, namespace, name, nil
+		}
+	}
+
+	return resource{}, "", "", fmt.Errorf("Unknown resource in path '%s'", path)
+}
+
+func decodeObject(bs []byte) (map[string]interface{}, map[string]interface{}, error) {
+	var obj map[string]interface{}
+
+	err := json.Unmarshal(bs, &obj)
+	if err != nil {
+		return nil, nil, fmt.Errorf("Unmarshaling object: %s", err)
+	}
+
+	meta, _ := obj["metadata"].(map[string]interface{})
+	if meta == nil {
+		meta = map[string]interface{}{}
+	}
+
+	return obj, meta, nil
+}
+
+func objectMeta(bs []byte) (metav1.ObjectMeta, error) {
+	var obj struct {
+		Metadata metav1.ObjectMeta `json:"metadata"`
+	}
+
+	err := json.Unmarshal(bs, &obj)
+	if err != nil {
+		return metav1.ObjectMeta{}, fmt.Errorf("Unmarshaling object metadata: %s", err)
+	}
+
+	return obj.Metadata, nil
+}
+
+func metaString(meta map[string]interface{}, key string) string {
+	val, _ := meta[key].(string)
+	return val
+}
+
+func newNotFoundStatus(res resource, name string) *metav1.Status {
+	status := newStatus(http.StatusNotFound, metav1.StatusReasonNotFound,
+		fmt.Sprintf("%s \"%s\" not found", res.name, name))
+
+	// Like API server, details use resource (not kind) name
+	pieces := strings.SplitN(res.name, ".", 2)
+	status.Details = &metav1.StatusDetails{Name: name, Kind: pieces[0]}
+	if len(pieces) == 2 {
+		status.Details.Group = pieces[1]
+	}
+
+	return status
+}
+
+func newStatus(code int, reason metav1.StatusReason, msg string) *metav1.Status {
+	return &metav1.Status{Code: int32(code), Reason: reason, Message: msg}
+}
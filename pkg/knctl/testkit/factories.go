@@ -0,0 +1,68 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testkit
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+
+	cmdcore "github.com/cppforlife/knctl/pkg/knctl/cmd/core"
+)
+
+// ConfigFactory returns config factory that targets cluster
+// via generated kubeconfig (default namespace is 'default')
+func (c *Cluster) ConfigFactory() cmdcore.ConfigFactory {
+	path := c.KubeconfigPath()
+
+	configFactory := cmdcore.NewConfigFactoryImpl()
+	configFactory.ConfigurePathResolver(func() (string, error) { return path, nil })
+	configFactory.ConfigureContextResolver(func() (string, error) { return "", nil })
+
+	return configFactory
+}
+
+// KubeconfigPath writes kubeconfig that targets cluster (e.g. for --kubeconfig flag)
+func (c *Cluster) KubeconfigPath() string {
+	path := filepath.Join(c.t.TempDir(), "kubeconfig")
+
+	kubeconfig := fmt.Sprintf(`
+apiVersion: v1
+kind: Config
+current-context: testkit
+clusters:
+- name: testkit
+  cluster: {server: "%s"}
+users:
+- name: testkit
+  user: {}
+contexts:
+- name: testkit
+  context: {cluster: testkit, user: testkit}
+`, c.server.URL)
+
+	err := ioutil.WriteFile(path, []byte(kubeconfig), 0600)
+	if err != nil {
+		c.t.Fatalf("Writing kubeconfig: %s", err)
+	}
+
+	return path
+}
+
+func (c *Cluster) DepsFactory() cmdcore.DepsFactory {
+	return cmdcore.NewDepsFactoryImpl(c.ConfigFactory())
+}
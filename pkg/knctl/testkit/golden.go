@@ -0,0 +1,119 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testkit
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+var (
+	updateGolden = flag.Bool("update", false, "Update golden files with actual output instead of comparing")
+
+	goldenNameRegexp = regexp.MustCompile(`[^a-zA-Z0-9_.-]+`)
+)
+
+// Normalizer replaces nondeterministic parts of output
+// (e.g. timestamps) with a stable placeholder
+type Normalizer struct {
+	Regexp      *regexp.Regexp
+	Replacement string
+}
+
+// DefaultNormalizers cover values that differ between runs
+// of the same command against the same in-memory cluster
+var DefaultNormalizers = []Normalizer{
+	{regexp.MustCompile(`\d{4}-\d{2}-\d{2}[T ]\d{2}:\d{2}:\d{2}(\.\d+)?(Z|[+-]\d{2}:?\d{2})?`), "<timestamp>"},
+	{regexp.MustCompile(`[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}`), "<uid>"},
+	{regexp.MustCompile(`\b\d+(s|m|h|d|y)\b`), "<age>"},
+}
+
+// NormalizeOutput applies default normalizers followed by given ones
+func NormalizeOutput(out string, extra ...Normalizer) string {
+	for _, n := range append(append([]Normalizer{}, DefaultNormalizers...), extra...) {
+		out = n.Regexp.ReplaceAllString(out, n.Replacement)
+	}
+	return out
+}
+
+// ExpectGolden compares normalized output with testdata/<name>.golden
+// relative to test's package. Run tests with -update to (re)write golden
+// files after intentional output changes and review resulting diff.
+func ExpectGolden(t testing.TB, name, actual string, extra ...Normalizer) {
+	t.Helper()
+
+	path := filepath.Join("testdata", goldenNameRegexp.ReplaceAllString(name, "_")+".golden")
+	actual = NormalizeOutput(actual, extra...)
+
+	if *updateGolden {
+		err := os.MkdirAll(filepath.Dir(path), 0755)
+		if err != nil {
+			t.Fatalf("Creating golden file directory: %s", err)
+		}
+		err = ioutil.WriteFile(path, []byte(actual), 0644)
+		if err != nil {
+			t.Fatalf("Writing golden file: %s", err)
+		}
+		return
+	}
+
+	expected, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Reading golden file (run with -update to create it): %s", err)
+	}
+
+	if string(expected) != actual {
+		t.Fatalf("Expected output to match golden file '%s' (run with -update if change is intentional):\n%s",
+			path, goldenDiff(string(expected), actual))
+	}
+}
+
+// goldenDiff shows mismatching lines by position which is
+// sufficient for spotting changes in tables and JSON
+func goldenDiff(expected, actual string) string {
+	expectedLines := strings.Split(expected, "\n")
+	actualLines := strings.Split(actual, "\n")
+
+	var result []string
+
+	for i := 0; i < len(expectedLines) || i < len(actualLines); i++ {
+		var expectedLine, actualLine string
+		if i < len(expectedLines) {
+			expectedLine = expectedLines[i]
+		}
+		if i < len(actualLines) {
+			actualLine = actualLines[i]
+		}
+		if expectedLine == actualLine {
+			continue
+		}
+		if i < len(expectedLines) {
+			result = append(result, fmt.Sprintf("%4d - %s", i+1, expectedLine))
+		}
+		if i < len(actualLines) {
+			result = append(result, fmt.Sprintf("%4d + %s", i+1, actualLine))
+		}
+	}
+
+	return strings.Join(result, "\n")
+}
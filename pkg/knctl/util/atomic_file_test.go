@@ -0,0 +1,80 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/cppforlife/knctl/pkg/knctl/util"
+)
+
+func TestWriteFileAtomically(t *testing.T) {
+	dir, err := ioutil.TempDir("", "knctl-util")
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "config")
+	linkPath := filepath.Join(dir, "config-link")
+
+	err = ioutil.WriteFile(path, []byte("initial"), 0600)
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	err = os.Symlink(path, linkPath)
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			err := util.WriteFileAtomically(linkPath, []byte(fmt.Sprintf("contents-%02d", i)), 0600)
+			if err != nil {
+				t.Errorf("Expected no error: %s", err)
+			}
+		}(i)
+	}
+
+	wg.Wait()
+
+	contents, err := ioutil.ReadFile(path)
+	if err != nil || len(contents) != len("contents-00") {
+		t.Fatalf("Expected complete contents, but was: %q %v", contents, err)
+	}
+
+	linkInfo, err := os.Lstat(linkPath)
+	if err != nil || linkInfo.Mode()&os.ModeSymlink == 0 {
+		t.Fatalf("Expected symlink to be preserved")
+	}
+
+	fileInfos, err := ioutil.ReadDir(dir)
+	if err != nil || len(fileInfos) != 2 {
+		t.Fatalf("Expected temporary files to be removed, but found: %d", len(fileInfos))
+	}
+}
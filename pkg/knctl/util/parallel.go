@@ -0,0 +1,81 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+const (
+	// DefaultParallelism limits number of concurrent API requests
+	// so that API server is not overwhelmed by a single command
+	DefaultParallelism = 10
+)
+
+// Parallel runs functions concurrently with at most size running at once
+type Parallel struct {
+	size int
+}
+
+func NewParallel(size int) Parallel {
+	if size < 1 {
+		size = 1
+	}
+	return Parallel{size}
+}
+
+// Run calls fn for every index in [0, count) and waits for all calls
+// to finish. Errors from all calls are combined into a single error.
+func (p Parallel) Run(count int, fn func(i int) error) error {
+	errs := make([]error, count)
+	sem := make(chan struct{}, p.size)
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < count; i++ {
+		i := i
+		wg.Add(1)
+		sem <- struct{}{}
+
+		go func() {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+
+			errs[i] = fn(i)
+		}()
+	}
+
+	wg.Wait()
+
+	var msgs []string
+
+	for _, err := range errs {
+		if err != nil {
+			msgs = append(msgs, err.Error())
+		}
+	}
+
+	if len(msgs) > 0 {
+		return fmt.Errorf("%s", strings.Join(msgs, "; "))
+	}
+
+	return nil
+}
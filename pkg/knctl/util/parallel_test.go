@@ -0,0 +1,75 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util_test
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cppforlife/knctl/pkg/knctl/util"
+)
+
+func TestParallelRun(t *testing.T) {
+	var mu sync.Mutex
+	var running, maxRunning int
+
+	results := make([]int, 20)
+
+	err := util.NewParallel(3).Run(len(results), func(i int) error {
+		mu.Lock()
+		running++
+		if running > maxRunning {
+			maxRunning = running
+		}
+		mu.Unlock()
+
+		time.Sleep(5 * time.Millisecond)
+		results[i] = i * 2
+
+		mu.Lock()
+		running--
+		mu.Unlock()
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	if maxRunning > 3 {
+		t.Fatalf("Expected at most 3 concurrent calls, but was %d", maxRunning)
+	}
+
+	for i, result := range results {
+		if result != i*2 {
+			t.Fatalf("Expected result %d to be %d, but was %d", i, i*2, result)
+		}
+	}
+}
+
+func TestParallelRun_Errors(t *testing.T) {
+	err := util.NewParallel(2).Run(3, func(i int) error {
+		if i == 1 {
+			return nil
+		}
+		return fmt.Errorf("err%d", i)
+	})
+	if err == nil || err.Error() != "err0; err2" {
+		t.Fatalf("Expected combined error, but was %#v", err)
+	}
+}
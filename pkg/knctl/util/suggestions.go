@@ -0,0 +1,120 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+const (
+	maxSuggestions = 3
+)
+
+// ClosestMatches returns up to 3 candidates that are likely intended
+// instead of given (mistyped) input, closest ones first
+func ClosestMatches(input string, candidates []string) []string {
+	type match struct {
+		candidate string
+		distance  int
+	}
+
+	var matches []match
+	seen := map[string]struct{}{}
+	lowerInput := strings.ToLower(input)
+	maxDistance := len(input)/3 + 1
+
+	for _, candidate := range candidates {
+		if _, found := seen[candidate]; found || candidate == input {
+			continue
+		}
+		seen[candidate] = struct{}{}
+
+		lowerCandidate := strings.ToLower(candidate)
+		distance := LevenshteinDistance(lowerInput, lowerCandidate)
+
+		if distance <= maxDistance || (len(input) > 1 && strings.HasPrefix(lowerCandidate, lowerInput)) {
+			matches = append(matches, match{candidate, distance})
+		}
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].distance != matches[j].distance {
+			return matches[i].distance < matches[j].distance
+		}
+		return matches[i].candidate < matches[j].candidate
+	})
+
+	var result []string
+
+	for i := 0; i < len(matches) && i < maxSuggestions; i++ {
+		result = append(result, matches[i].candidate)
+	}
+
+	return result
+}
+
+// DidYouMean formats matches for appending to an error message
+// (e.g. " (did you mean 'list'?)"); returns empty string if there are no matches
+func DidYouMean(matches []string) string {
+	if len(matches) == 0 {
+		return ""
+	}
+
+	var quoted []string
+	for _, match := range matches {
+		quoted = append(quoted, fmt.Sprintf("'%s'", match))
+	}
+
+	return fmt.Sprintf(" (did you mean %s?)", strings.Join(quoted, " or "))
+}
+
+// LevenshteinDistance returns number of single character insertions,
+// deletions or substitutions required to change a into b
+func LevenshteinDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = minInt(prev[j]+1, minInt(curr[j-1]+1, prev[j-1]+cost))
+		}
+
+		prev, curr = curr, prev
+	}
+
+	return prev[len(rb)]
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
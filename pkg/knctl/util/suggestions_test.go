@@ -0,0 +1,73 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/cppforlife/knctl/pkg/knctl/util"
+)
+
+func TestLevenshteinDistance(t *testing.T) {
+	cases := []struct {
+		a, b     string
+		expected int
+	}{
+		{"", "", 0},
+		{"", "abc", 3},
+		{"service", "service", 0},
+		{"servce", "service", 1},
+		{"sevrice", "service", 2},
+		{"kitten", "sitting", 3},
+	}
+
+	for _, c := range cases {
+		if actual := util.LevenshteinDistance(c.a, c.b); actual != c.expected {
+			t.Fatalf("Expected distance between '%s' and '%s' to be %d, but was %d", c.a, c.b, c.expected, actual)
+		}
+	}
+}
+
+func TestClosestMatches(t *testing.T) {
+	candidates := []string{"service", "serve", "service-account", "revision", "route", "list", "show"}
+
+	cases := map[string][]string{
+		"servce": []string{"serve", "service"},
+		"serv":   []string{"serve", "service", "service-account"},
+		"lst":    []string{"list"},
+		"rute":   []string{"route"},
+		"xyz":    nil,
+		"list":   nil,
+	}
+
+	for input, expected := range cases {
+		actual := util.ClosestMatches(input, candidates)
+		if !reflect.DeepEqual(actual, expected) {
+			t.Fatalf("Expected matches for '%s' to be %#v, but was %#v", input, expected, actual)
+		}
+	}
+}
+
+func TestDidYouMean(t *testing.T) {
+	if actual := util.DidYouMean(nil); actual != "" {
+		t.Fatalf("Expected empty suffix, but was '%s'", actual)
+	}
+	if actual := util.DidYouMean([]string{"a", "b"}); actual != " (did you mean 'a' or 'b'?)" {
+		t.Fatalf("Expected suffix, but was '%s'", actual)
+	}
+}
@@ -0,0 +1,152 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package waiter
+
+import (
+	"fmt"
+
+	buildv1alpha1 "github.com/knative/build/pkg/apis/build/v1alpha1"
+	"github.com/knative/serving/pkg/apis/serving/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+var _ Condition = IsServiceReady
+var _ Condition = IsServiceSettled
+var _ Condition = IsRouteTrafficAssigned
+var _ Condition = IsRevisionReady
+var _ Condition = IsBuildBuilderAssigned
+var _ Condition = IsBuildCompleted
+var _ Condition = IsBuildClusterPodAssigned
+var _ Condition = Exists
+var _ Condition = IsPodTerminal
+
+// IsServiceReady is satisfied once latest service spec was observed and became ready
+func IsServiceReady(obj runtime.Object) (bool, error) {
+	service, ok := obj.(*v1alpha1.Service)
+	if !ok {
+		return false, fmt.Errorf("Expected object to be a service, but was %T", obj)
+	}
+	if service.Status.ObservedGeneration < service.Spec.Generation {
+		return false, nil
+	}
+	return service.Status.IsReady(), nil
+}
+
+// IsServiceSettled is satisfied once latest service spec was observed
+// and service either became ready or failed
+func IsServiceSettled(obj runtime.Object) (bool, error) {
+	service, ok := obj.(*v1alpha1.Service)
+	if !ok {
+		return false, fmt.Errorf("Expected object to be a service, but was %T", obj)
+	}
+	if service.Status.ObservedGeneration < service.Spec.Generation {
+		return false, nil
+	}
+	cond := service.Status.GetCondition(v1alpha1.ServiceConditionReady)
+	return cond != nil && cond.Status != corev1.ConditionUnknown, nil
+}
+
+func IsRouteTrafficAssigned(obj runtime.Object) (bool, error) {
+	route, ok := obj.(*v1alpha1.Route)
+	if !ok {
+		return false, fmt.Errorf("Expected object to be a route, but was %T", obj)
+	}
+
+	cond := route.Status.GetCondition(v1alpha1.RouteConditionAllTrafficAssigned)
+
+	return cond != nil && cond.Status == corev1.ConditionTrue && len(route.Status.Traffic) > 0, nil
+}
+
+func IsRevisionReady(obj runtime.Object) (bool, error) {
+	revision, ok := obj.(*v1alpha1.Revision)
+	if !ok {
+		return false, fmt.Errorf("Expected object to be a revision, but was %T", obj)
+	}
+	return revision.Status.IsReady(), nil
+}
+
+func IsBuildBuilderAssigned(obj runtime.Object) (bool, error) {
+	build, ok := obj.(*buildv1alpha1.Build)
+	if !ok {
+		return false, fmt.Errorf("Expected object to be a build, but was %T", obj)
+	}
+	return len(build.Status.Builder) > 0, nil
+}
+
+// IsBuildCompleted is satisfied once build either succeeded or failed
+func IsBuildCompleted(obj runtime.Object) (bool, error) {
+	build, ok := obj.(*buildv1alpha1.Build)
+	if !ok {
+		return false, fmt.Errorf("Expected object to be a build, but was %T", obj)
+	}
+
+	cond := build.Status.GetCondition(buildv1alpha1.BuildSucceeded)
+	if cond == nil {
+		return false, nil
+	}
+
+	switch cond.Status {
+	case corev1.ConditionTrue, corev1.ConditionFalse:
+		return true, nil
+	default:
+		return false, nil
+	}
+}
+
+// IsBuildClusterPodAssigned is satisfied once build running
+// on cluster builder was assigned to a pod
+func IsBuildClusterPodAssigned(obj runtime.Object) (bool, error) {
+	build, ok := obj.(*buildv1alpha1.Build)
+	if !ok {
+		return false, fmt.Errorf("Expected object to be a build, but was %T", obj)
+	}
+	cluster := build.Status.Cluster
+	return cluster != nil && len(cluster.Namespace) > 0 && len(cluster.PodName) > 0, nil
+}
+
+// Exists is satisfied once object is observed
+func Exists(obj runtime.Object) (bool, error) {
+	return true, nil
+}
+
+// IsPodTerminal is satisfied once pod either succeeded or failed
+func IsPodTerminal(obj runtime.Object) (bool, error) {
+	pod, ok := obj.(*corev1.Pod)
+	if !ok {
+		return false, fmt.Errorf("Expected object to be a pod, but was %T", obj)
+	}
+	return pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed, nil
+}
+
+// IsPodInitContainerRunning returns condition satisfied
+// once named init container of a pod is running
+func IsPodInitContainerRunning(name string) Condition {
+	return func(obj runtime.Object) (bool, error) {
+		pod, ok := obj.(*corev1.Pod)
+		if !ok {
+			return false, fmt.Errorf("Expected object to be a pod, but was %T", obj)
+		}
+		for _, status := range pod.Status.InitContainerStatuses {
+			if status.Name == name {
+				// TODO what if pod is no longer progressing?
+				return status.State.Running != nil, nil
+			}
+		}
+		return false, nil
+	}
+}
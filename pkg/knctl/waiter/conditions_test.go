@@ -0,0 +1,143 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package waiter_test
+
+import (
+	"testing"
+
+	. "github.com/cppforlife/knctl/pkg/knctl/waiter"
+	buildv1alpha1 "github.com/knative/build/pkg/apis/build/v1alpha1"
+	duckv1alpha1 "github.com/knative/pkg/apis/duck/v1alpha1"
+	"github.com/knative/serving/pkg/apis/serving/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func TestIsServiceReady(t *testing.T) {
+	service := &v1alpha1.Service{}
+	service.Spec.Generation = 2
+	service.Status.ObservedGeneration = 1
+	service.Status.Conditions = duckv1alpha1.Conditions{
+		{Type: duckv1alpha1.ConditionReady, Status: corev1.ConditionTrue},
+	}
+
+	expectCondition(t, IsServiceReady, service, false)
+
+	service.Status.ObservedGeneration = 2
+
+	expectCondition(t, IsServiceReady, service, true)
+}
+
+func TestIsServiceSettled(t *testing.T) {
+	service := &v1alpha1.Service{}
+	service.Spec.Generation = 2
+	service.Status.ObservedGeneration = 2
+
+	expectCondition(t, IsServiceSettled, service, false)
+
+	for _, status := range []corev1.ConditionStatus{corev1.ConditionUnknown, corev1.ConditionFalse, corev1.ConditionTrue} {
+		service.Status.Conditions = duckv1alpha1.Conditions{
+			{Type: duckv1alpha1.ConditionReady, Status: status},
+		}
+		expectCondition(t, IsServiceSettled, service, status != corev1.ConditionUnknown)
+	}
+
+	service.Status.ObservedGeneration = 1
+
+	expectCondition(t, IsServiceSettled, service, false)
+}
+
+func TestIsRouteTrafficAssigned(t *testing.T) {
+	route := &v1alpha1.Route{}
+	route.Status.Conditions = duckv1alpha1.Conditions{
+		{Type: v1alpha1.RouteConditionAllTrafficAssigned, Status: corev1.ConditionTrue},
+	}
+
+	expectCondition(t, IsRouteTrafficAssigned, route, false)
+
+	route.Status.Traffic = []v1alpha1.TrafficTarget{{RevisionName: "rev1", Percent: 100}}
+
+	expectCondition(t, IsRouteTrafficAssigned, route, true)
+}
+
+func TestIsBuildCompleted(t *testing.T) {
+	build := &buildv1alpha1.Build{}
+
+	expectCondition(t, IsBuildCompleted, build, false)
+
+	for _, status := range []corev1.ConditionStatus{corev1.ConditionUnknown, corev1.ConditionFalse, corev1.ConditionTrue} {
+		build.Status.Conditions = duckv1alpha1.Conditions{
+			{Type: buildv1alpha1.BuildSucceeded, Status: status},
+		}
+		expectCondition(t, IsBuildCompleted, build, status != corev1.ConditionUnknown)
+	}
+}
+
+func TestIsBuildClusterPodAssigned(t *testing.T) {
+	build := &buildv1alpha1.Build{}
+
+	expectCondition(t, IsBuildClusterPodAssigned, build, false)
+
+	build.Status.Cluster = &buildv1alpha1.ClusterSpec{Namespace: "ns1"}
+
+	expectCondition(t, IsBuildClusterPodAssigned, build, false)
+
+	build.Status.Cluster.PodName = "pod1"
+
+	expectCondition(t, IsBuildClusterPodAssigned, build, true)
+}
+
+func TestIsPodTerminal(t *testing.T) {
+	for _, phase := range []corev1.PodPhase{corev1.PodPending, corev1.PodRunning, corev1.PodSucceeded, corev1.PodFailed} {
+		pod := &corev1.Pod{Status: corev1.PodStatus{Phase: phase}}
+		expectCondition(t, IsPodTerminal, pod, phase == corev1.PodSucceeded || phase == corev1.PodFailed)
+	}
+}
+
+func TestIsPodInitContainerRunning(t *testing.T) {
+	pod := &corev1.Pod{}
+	pod.Status.InitContainerStatuses = []corev1.ContainerStatus{
+		{Name: "other", State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}}},
+		{Name: "init1"},
+	}
+
+	expectCondition(t, IsPodInitContainerRunning("init1"), pod, false)
+
+	pod.Status.InitContainerStatuses[1].State.Running = &corev1.ContainerStateRunning{}
+
+	expectCondition(t, IsPodInitContainerRunning("init1"), pod, true)
+}
+
+func TestConditions_ErrorOnUnexpectedType(t *testing.T) {
+	_, err := IsRevisionReady(&v1alpha1.Service{})
+	if err == nil {
+		t.Fatalf("Expected error")
+	}
+	if err.Error() != "Expected object to be a revision, but was *v1alpha1.Service" {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+}
+
+func expectCondition(t *testing.T, cond Condition, obj runtime.Object, expected bool) {
+	met, err := cond(obj)
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+	if met != expected {
+		t.Fatalf("Expected condition to be '%t' for %#v", expected, obj)
+	}
+}
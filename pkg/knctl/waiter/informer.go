@@ -0,0 +1,101 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package waiter
+
+import (
+	"sync"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/tools/cache"
+)
+
+// informer notifies waiters about any change to observed objects
+type informer struct {
+	cache.SharedIndexInformer
+
+	lock      sync.Mutex
+	changedCh chan struct{}
+	listErr   error
+
+	// refs counts waits using informer; informer is stopped once it drops to 0
+	refs   int
+	stopCh chan struct{}
+}
+
+func newInformer(lw cache.ListerWatcher, objType runtime.Object) *informer {
+	inf := &informer{
+		changedCh: make(chan struct{}),
+		stopCh:    make(chan struct{}),
+	}
+
+	// Reflector retries failed lists forever (e.g. when list is forbidden);
+	// record list error so that waiters can fail instead of blocking
+	recordingLW := &cache.ListWatch{
+		ListFunc: func(opts metav1.ListOptions) (runtime.Object, error) {
+			obj, err := lw.List(opts)
+			inf.setListErr(err)
+			return obj, err
+		},
+		WatchFunc: func(opts metav1.ListOptions) (watch.Interface, error) { return lw.Watch(opts) },
+	}
+
+	inf.SharedIndexInformer = cache.NewSharedIndexInformer(recordingLW, objType, 0, cache.Indexers{})
+
+	inf.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    func(interface{}) { inf.notify() },
+		UpdateFunc: func(interface{}, interface{}) { inf.notify() },
+		DeleteFunc: func(interface{}) { inf.notify() },
+	})
+
+	return inf
+}
+
+// Changed returns channel that is closed on next change
+func (i *informer) Changed() <-chan struct{} {
+	i.lock.Lock()
+	defer i.lock.Unlock()
+
+	return i.changedCh
+}
+
+// ListErr returns error from most recent list (nil if it succeeded)
+func (i *informer) ListErr() error {
+	i.lock.Lock()
+	defer i.lock.Unlock()
+
+	return i.listErr
+}
+
+func (i *informer) setListErr(err error) {
+	i.lock.Lock()
+	i.listErr = err
+	i.lock.Unlock()
+
+	if err != nil {
+		i.notify()
+	}
+}
+
+func (i *informer) notify() {
+	i.lock.Lock()
+	defer i.lock.Unlock()
+
+	close(i.changedCh)
+	i.changedCh = make(chan struct{})
+}
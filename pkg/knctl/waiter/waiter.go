@@ -0,0 +1,331 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package waiter
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	buildv1alpha1 "github.com/knative/build/pkg/apis/build/v1alpha1"
+	buildclientset "github.com/knative/build/pkg/client/clientset/versioned"
+	"github.com/knative/serving/pkg/apis/serving/v1alpha1"
+	servingclientset "github.com/knative/serving/pkg/client/clientset/versioned"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+)
+
+type Kind string
+
+const (
+	ServiceKind  Kind = "service"
+	RouteKind    Kind = "route"
+	RevisionKind Kind = "revision"
+	BuildKind    Kind = "build"
+	PodKind      Kind = "pod"
+
+	syncPollPeriod = 100 * time.Millisecond
+)
+
+// Condition reports whether object reached desired state.
+// Returning an error stops waiting.
+type Condition func(obj runtime.Object) (bool, error)
+
+// Waiter waits for objects to satisfy conditions. Each object is observed
+// via an informer restricted to that object (by name) which is shared by all
+// concurrent waits for the same object made through the same Waiter; informer
+// is stopped once last wait using it returns. Typically single Waiter is
+// obtained from DepsFactory and used for the lifetime of the process.
+type Waiter struct {
+	coreClient    kubernetes.Interface
+	servingClient servingclientset.Interface
+	buildClient   buildclientset.Interface
+
+	lock      sync.Mutex
+	informers map[string]*informer
+	stopped   bool
+}
+
+func NewWaiter(
+	coreClient kubernetes.Interface,
+	servingClient servingclientset.Interface,
+	buildClient buildclientset.Interface,
+) *Waiter {
+	return &Waiter{
+		coreClient:    coreClient,
+		servingClient: servingClient,
+		buildClient:   buildClient,
+		informers:     map[string]*informer{},
+	}
+}
+
+// Stop stops all informers; subsequent waits return an error
+func (w *Waiter) Stop() {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	for key, inf := range w.informers {
+		close(inf.stopCh)
+		delete(w.informers, key)
+	}
+
+	w.stopped = true
+}
+
+// Wait blocks until named object satisfies condition, condition returns an error,
+// or cancelCh is closed. Last observed object (possibly nil) is returned
+// together with an indicator whether condition was satisfied.
+func (w *Waiter) Wait(kind Kind, namespace, name string, cond Condition, cancelCh chan struct{}) (runtime.Object, bool, error) {
+	inf, release, err := w.acquire(kind, namespace, name)
+	if err != nil {
+		return nil, false, err
+	}
+
+	defer release()
+
+	synced, err := w.waitForSync(inf, kind, name, cancelCh)
+	if err != nil || !synced {
+		return nil, false, err
+	}
+
+	var lastObj runtime.Object
+
+	for {
+		// Retrieve notification channel before reading the store
+		// so that changes made in between are not missed
+		changedCh := inf.Changed()
+
+		item, exists, err := inf.GetStore().GetByKey(namespace + "/" + name)
+		if err != nil {
+			return nil, false, fmt.Errorf("Getting %s '%s': %s", kind, name, err)
+		}
+
+		if exists {
+			lastObj = item.(runtime.Object).DeepCopyObject()
+
+			met, err := cond(lastObj)
+			if err != nil || met {
+				return lastObj, met, err
+			}
+		}
+
+		select {
+		case <-changedCh:
+		case <-cancelCh:
+			return lastObj, false, nil
+		}
+	}
+}
+
+// waitForSync differs from cache.WaitForCacheSync by returning
+// list error (e.g. list is forbidden) instead of waiting indefinitely
+func (w *Waiter) waitForSync(inf *informer, kind Kind, name string, cancelCh chan struct{}) (bool, error) {
+	for {
+		changedCh := inf.Changed()
+
+		if inf.HasSynced() {
+			return true, nil
+		}
+
+		if err := inf.ListErr(); err != nil {
+			return false, fmt.Errorf("Listing %s '%s': %s", kind, name, err)
+		}
+
+		select {
+		case <-changedCh:
+		case <-time.After(syncPollPeriod):
+		case <-cancelCh:
+			return false, nil
+		}
+	}
+}
+
+func (w *Waiter) ServiceReady(namespace, name string, cancelCh chan struct{}) (*v1alpha1.Service, bool, error) {
+	obj, met, err := w.Wait(ServiceKind, namespace, name, IsServiceReady, cancelCh)
+	if obj == nil {
+		return nil, met, err
+	}
+	return obj.(*v1alpha1.Service), met, err
+}
+
+func (w *Waiter) RouteTrafficAssigned(namespace, name string, cancelCh chan struct{}) (*v1alpha1.Route, bool, error) {
+	obj, met, err := w.Wait(RouteKind, namespace, name, IsRouteTrafficAssigned, cancelCh)
+	if obj == nil {
+		return nil, met, err
+	}
+	return obj.(*v1alpha1.Route), met, err
+}
+
+func (w *Waiter) RevisionReady(namespace, name string, cancelCh chan struct{}) (*v1alpha1.Revision, bool, error) {
+	obj, met, err := w.Wait(RevisionKind, namespace, name, IsRevisionReady, cancelCh)
+	if obj == nil {
+		return nil, met, err
+	}
+	return obj.(*v1alpha1.Revision), met, err
+}
+
+func (w *Waiter) BuildBuilderAssigned(namespace, name string, cancelCh chan struct{}) (*buildv1alpha1.Build, bool, error) {
+	obj, met, err := w.Wait(BuildKind, namespace, name, IsBuildBuilderAssigned, cancelCh)
+	if obj == nil {
+		return nil, met, err
+	}
+	return obj.(*buildv1alpha1.Build), met, err
+}
+
+func (w *Waiter) BuildCompleted(namespace, name string, cancelCh chan struct{}) (*buildv1alpha1.Build, bool, error) {
+	obj, met, err := w.Wait(BuildKind, namespace, name, IsBuildCompleted, cancelCh)
+	if obj == nil {
+		return nil, met, err
+	}
+	return obj.(*buildv1alpha1.Build), met, err
+}
+
+func (w *Waiter) BuildClusterPodAssigned(namespace, name string, cancelCh chan struct{}) (*buildv1alpha1.Build, bool, error) {
+	obj, met, err := w.Wait(BuildKind, namespace, name, IsBuildClusterPodAssigned, cancelCh)
+	if obj == nil {
+		return nil, met, err
+	}
+	return obj.(*buildv1alpha1.Build), met, err
+}
+
+func (w *Waiter) Pod(namespace, name string, cond Condition, cancelCh chan struct{}) (*corev1.Pod, bool, error) {
+	obj, met, err := w.Wait(PodKind, namespace, name, cond, cancelCh)
+	if obj == nil {
+		return nil, met, err
+	}
+	return obj.(*corev1.Pod), met, err
+}
+
+func (w *Waiter) acquire(kind Kind, namespace, name string) (*informer, func(), error) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	if w.stopped {
+		return nil, nil, fmt.Errorf("Expected waiter to not be stopped")
+	}
+
+	key := string(kind) + "/" + namespace + "/" + name
+
+	inf, found := w.informers[key]
+	if !found {
+		lw, objType, err := w.listWatch(kind, namespace, name)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		inf = newInformer(lw, objType)
+		w.informers[key] = inf
+
+		go inf.Run(inf.stopCh)
+	}
+
+	inf.refs++
+
+	return inf, func() { w.release(key, inf) }, nil
+}
+
+func (w *Waiter) release(key string, inf *informer) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	inf.refs--
+
+	// Informer may have been already stopped via Stop
+	if inf.refs == 0 && w.informers[key] == inf {
+		close(inf.stopCh)
+		delete(w.informers, key)
+	}
+}
+
+func (w *Waiter) listWatch(kind Kind, namespace, name string) (cache.ListerWatcher, runtime.Object, error) {
+	switch kind {
+	case ServiceKind, RouteKind, RevisionKind:
+		if w.servingClient == nil {
+			return nil, nil, fmt.Errorf("Expected serving client to wait for %s", kind)
+		}
+	case BuildKind:
+		if w.buildClient == nil {
+			return nil, nil, fmt.Errorf("Expected build client to wait for %s", kind)
+		}
+	case PodKind:
+		if w.coreClient == nil {
+			return nil, nil, fmt.Errorf("Expected core client to wait for %s", kind)
+		}
+	}
+
+	switch kind {
+	case ServiceKind:
+		client := w.servingClient.ServingV1alpha1().Services(namespace)
+		return namedListWatch(name,
+			func(opts metav1.ListOptions) (runtime.Object, error) { return client.List(opts) },
+			client.Watch,
+		), &v1alpha1.Service{}, nil
+
+	case RouteKind:
+		client := w.servingClient.ServingV1alpha1().Routes(namespace)
+		return namedListWatch(name,
+			func(opts metav1.ListOptions) (runtime.Object, error) { return client.List(opts) },
+			client.Watch,
+		), &v1alpha1.Route{}, nil
+
+	case RevisionKind:
+		client := w.servingClient.ServingV1alpha1().Revisions(namespace)
+		return namedListWatch(name,
+			func(opts metav1.ListOptions) (runtime.Object, error) { return client.List(opts) },
+			client.Watch,
+		), &v1alpha1.Revision{}, nil
+
+	case BuildKind:
+		client := w.buildClient.BuildV1alpha1().Builds(namespace)
+		return namedListWatch(name,
+			func(opts metav1.ListOptions) (runtime.Object, error) { return client.List(opts) },
+			client.Watch,
+		), &buildv1alpha1.Build{}, nil
+
+	case PodKind:
+		client := w.coreClient.CoreV1().Pods(namespace)
+		return namedListWatch(name,
+			func(opts metav1.ListOptions) (runtime.Object, error) { return client.List(opts) },
+			client.Watch,
+		), &corev1.Pod{}, nil
+
+	default:
+		return nil, nil, fmt.Errorf("Unknown kind '%s'", kind)
+	}
+}
+
+// namedListWatch restricts list and watch to a single object
+// so that waiting does not require access to all objects in namespace
+func namedListWatch(name string, listFunc cache.ListFunc, watchFunc cache.WatchFunc) *cache.ListWatch {
+	fieldSelector := fields.OneTermEqualSelector("metadata.name", name).String()
+
+	return &cache.ListWatch{
+		ListFunc: func(opts metav1.ListOptions) (runtime.Object, error) {
+			opts.FieldSelector = fieldSelector
+			return listFunc(opts)
+		},
+		WatchFunc: func(opts metav1.ListOptions) (watch.Interface, error) {
+			opts.FieldSelector = fieldSelector
+			return watchFunc(opts)
+		},
+	}
+}
@@ -0,0 +1,107 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package waiter_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cppforlife/knctl/pkg/knctl/testkit"
+	. "github.com/cppforlife/knctl/pkg/knctl/waiter"
+	servingclientset "github.com/knative/serving/pkg/client/clientset/versioned"
+	"k8s.io/client-go/rest"
+)
+
+func TestWaiterWaitReturnsObservedObject(t *testing.T) {
+	cluster := testkit.NewCluster(t,
+		testkit.NewRevision("ns1", "svc1", "rev1").Ready().Build(),
+		testkit.NewRevision("ns1", "svc1", "rev2").Build(),
+	)
+
+	w := NewWaiter(nil, cluster.ServingClient(), nil)
+	defer w.Stop()
+
+	cancelCh := make(chan struct{})
+	timer := time.AfterFunc(5*time.Second, func() { close(cancelCh) })
+	defer timer.Stop()
+
+	// Repeated waits for the same object restart its informer
+	for i := 0; i < 2; i++ {
+		rev, ready, err := w.RevisionReady("ns1", "rev1", cancelCh)
+		if err != nil {
+			t.Fatalf("Expected no error: %s", err)
+		}
+		if !ready || rev.Name != "rev1" {
+			t.Fatalf("Expected revision 'rev1' to be ready, but was: %#v", rev)
+		}
+	}
+}
+
+func TestWaiterWaitReturnsListError(t *testing.T) {
+	var fieldSelectorsLock sync.Mutex
+	var fieldSelectors []string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fieldSelectorsLock.Lock()
+		fieldSelectors = append(fieldSelectors, r.URL.Query().Get("fieldSelector"))
+		fieldSelectorsLock.Unlock()
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte(`{"kind":"Status","apiVersion":"v1","status":"Failure",` +
+			`"message":"revisions.serving.knative.dev is forbidden","reason":"Forbidden","code":403}`))
+	}))
+	defer server.Close()
+
+	servingClient, err := servingclientset.NewForConfig(&rest.Config{Host: server.URL})
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	w := NewWaiter(nil, servingClient, nil)
+	defer w.Stop()
+
+	// Without cancellation wait would block forever unless list error is returned
+	_, ready, err := w.RevisionReady("ns1", "rev1", nil)
+	if err == nil || !strings.Contains(err.Error(), "revisions.serving.knative.dev is forbidden") {
+		t.Fatalf("Expected list error, but was: %v", err)
+	}
+	if ready {
+		t.Fatalf("Expected revision to not be ready")
+	}
+
+	fieldSelectorsLock.Lock()
+	defer fieldSelectorsLock.Unlock()
+
+	if len(fieldSelectors) == 0 || fieldSelectors[0] != "metadata.name=rev1" {
+		t.Fatalf("Expected list to be restricted to named revision, but was: %#v", fieldSelectors)
+	}
+}
+
+func TestWaiterWaitAfterStop(t *testing.T) {
+	w := NewWaiter(nil, testkit.NewCluster(t).ServingClient(), nil)
+	w.Stop()
+
+	_, _, err := w.RevisionReady("ns1", "rev1", nil)
+	if err == nil || err.Error() != "Expected waiter to not be stopped" {
+		t.Fatalf("Expected error, but was: %v", err)
+	}
+}
@@ -27,7 +27,7 @@ import (
 )
 
 func TestAnnotateRevision(t *testing.T) {
-	logger := Logger{}
+	logger := NewLogger(t)
 	env := BuildEnv(t)
 	knctl := Knctl{t, env.Namespace, logger}
 
@@ -25,7 +25,7 @@ import (
 )
 
 func TestAnnotateRoute(t *testing.T) {
-	logger := Logger{}
+	logger := NewLogger(t)
 	env := BuildEnv(t)
 	knctl := Knctl{t, env.Namespace, logger}
 
@@ -26,7 +26,7 @@ import (
 )
 
 func TestAnnotateService(t *testing.T) {
-	logger := Logger{}
+	logger := NewLogger(t)
 	env := BuildEnv(t)
 	knctl := Knctl{t, env.Namespace, logger}
 
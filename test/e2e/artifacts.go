@@ -0,0 +1,95 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package e2e
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+var (
+	collectedArtifacts     = map[*testing.T]struct{}{}
+	collectedArtifactsLock sync.Mutex
+)
+
+type artifactCmd struct {
+	FileName string
+	Args     []string
+}
+
+// collectArtifacts saves namespace events, pod descriptions and Knative
+// controller logs of a failed test into $KNCTL_E2E_ARTIFACTS_DIR/<test>/.
+// Artifacts are collected at most once per test.
+func collectArtifacts(t *testing.T, namespace string, logger Logger) {
+	rootDir := os.Getenv("KNCTL_E2E_ARTIFACTS_DIR")
+	if len(rootDir) == 0 || vcrMode() == vcrModeReplay {
+		return
+	}
+
+	collectedArtifactsLock.Lock()
+	_, collected := collectedArtifacts[t]
+	collectedArtifacts[t] = struct{}{}
+	collectedArtifactsLock.Unlock()
+
+	if collected {
+		return
+	}
+
+	dir := filepath.Join(rootDir, vcrCassetteNameRegexp.ReplaceAllString(t.Name(), "_"))
+
+	err := os.MkdirAll(dir, 0755)
+	if err != nil {
+		logger.Debugf("Failed to create artifacts directory: %s\n", err)
+		return
+	}
+
+	logger.Debugf("Collecting artifacts into '%s'...\n", dir)
+
+	cmds := []artifactCmd{
+		{"events.txt", []string{"get", "events", "--sort-by", ".lastTimestamp", "-n", namespace}},
+		{"pods.txt", []string{"describe", "pods", "-n", namespace}},
+		{"knative-services.yml", []string{"get", "services.serving.knative.dev,revisions,routes", "-o", "yaml", "-n", namespace}},
+		{"knative-builds.yml", []string{"get", "builds.build.knative.dev", "-o", "yaml", "-n", namespace}},
+		{"knative-serving-controller.log", []string{"logs", "-l", "app=controller", "-n", "knative-serving", "--all-containers", "--tail", "1000"}},
+		{"knative-serving-activator.log", []string{"logs", "-l", "app=activator", "-n", "knative-serving", "--all-containers", "--tail", "1000"}},
+		{"knative-build-controller.log", []string{"logs", "-l", "app=build-controller", "-n", "knative-build", "--all-containers", "--tail", "1000"}},
+	}
+
+	for _, cmd := range cmds {
+		var out bytes.Buffer
+
+		kubectlCmd := exec.Command("kubectl", cmd.Args...)
+		kubectlCmd.Stdout = &out
+		kubectlCmd.Stderr = &out
+
+		err := kubectlCmd.Run()
+		if err != nil {
+			fmt.Fprintf(&out, "\nError: %s\n", err)
+		}
+
+		err = ioutil.WriteFile(filepath.Join(dir, cmd.FileName), out.Bytes(), 0644)
+		if err != nil {
+			logger.Debugf("Failed to write artifact '%s': %s\n", cmd.FileName, err)
+		}
+	}
+}
@@ -24,7 +24,9 @@ import (
 )
 
 func TestBuildSuccess(t *testing.T) {
-	logger := Logger{}
+	requireCapability(t, CapabilityKnativeBuild)
+
+	logger := NewLogger(t)
 	env := BuildEnv(t)
 	knctl := Knctl{t, env.Namespace, logger}
 	kubectl := Kubectl{t, env.Namespace, logger}
@@ -120,7 +122,9 @@ func TestBuildSuccess(t *testing.T) {
 }
 
 func TestBuildFailed(t *testing.T) {
-	logger := Logger{}
+	requireCapability(t, CapabilityKnativeBuild)
+
+	logger := NewLogger(t)
 	env := BuildEnv(t)
 	knctl := Knctl{t, env.Namespace, logger}
 	kubectl := Kubectl{t, env.Namespace, logger}
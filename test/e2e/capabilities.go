@@ -0,0 +1,176 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package e2e
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+type Capability string
+
+const (
+	CapabilityLoadBalancer Capability = "loadbalancer"
+	CapabilityKnativeBuild Capability = "knative-build"
+	CapabilityTekton       Capability = "tekton"
+	CapabilityEventing     Capability = "eventing"
+	CapabilityAutoTLS      Capability = "auto-tls"
+)
+
+var (
+	capabilityCRDs = map[Capability]string{
+		CapabilityKnativeBuild: "builds.build.knative.dev",
+		CapabilityTekton:       "tasks.tekton.dev",
+		CapabilityEventing:     "brokers.eventing.knative.dev",
+	}
+
+	detectedCapabilities struct {
+		once   sync.Once
+		result map[Capability]bool
+		err    error
+	}
+)
+
+// requireCapability skips test if cluster does not support given capability.
+// Detection can be overridden via KNCTL_E2E_CAPABILITIES (e.g. 'tekton=false,auto-tls=true').
+// In VCR replay mode there is no cluster to probe, hence recorded cassettes decide.
+func requireCapability(t *testing.T, capability Capability) {
+	overrides, err := capabilityOverrides()
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+
+	supported, found := overrides[capability]
+
+	if !found {
+		if vcrMode() == vcrModeReplay {
+			return
+		}
+
+		detected, err := DetectCapabilities()
+		if err != nil {
+			t.Fatalf("Detecting cluster capabilities: %s", err)
+		}
+
+		supported = detected[capability]
+	}
+
+	if !supported {
+		t.Skipf("Skipping test: cluster does not support '%s'", capability)
+	}
+}
+
+// DetectCapabilities probes cluster once per test run
+func DetectCapabilities() (map[Capability]bool, error) {
+	d := &detectedCapabilities
+
+	d.once.Do(func() {
+		d.result, d.err = detectCapabilities()
+	})
+
+	return d.result, d.err
+}
+
+func detectCapabilities() (map[Capability]bool, error) {
+	result := map[Capability]bool{}
+
+	crdsOut, err := runKubectlOutsideTest("get", "crd", "-o", "jsonpath={.items[*].metadata.name}")
+	if err != nil {
+		return nil, err
+	}
+
+	crds := map[string]struct{}{}
+	for _, name := range strings.Fields(crdsOut) {
+		crds[name] = struct{}{}
+	}
+
+	for capability, crd := range capabilityCRDs {
+		_, result[capability] = crds[crd]
+	}
+
+	servicesOut, err := runKubectlOutsideTest("get", "services", "--all-namespaces", "-o", "json")
+	if err != nil {
+		return nil, err
+	}
+
+	var services corev1.ServiceList
+
+	err = json.Unmarshal([]byte(servicesOut), &services)
+	if err != nil {
+		return nil, fmt.Errorf("Unmarshaling services: %s", err)
+	}
+
+	for _, svc := range services.Items {
+		if svc.Spec.Type == corev1.ServiceTypeLoadBalancer && len(svc.Status.LoadBalancer.Ingress) > 0 {
+			result[CapabilityLoadBalancer] = true
+		}
+	}
+
+	// Config map may not exist in which case auto TLS is disabled
+	autoTLS, _ := runKubectlOutsideTest("get", "configmap", "config-network",
+		"-n", "knative-serving", "-o", "jsonpath={.data.autoTLS}")
+
+	result[CapabilityAutoTLS] = strings.EqualFold(strings.TrimSpace(autoTLS), "Enabled")
+
+	return result, nil
+}
+
+func capabilityOverrides() (map[Capability]bool, error) {
+	result := map[Capability]bool{}
+
+	for _, pair := range strings.Split(os.Getenv("KNCTL_E2E_CAPABILITIES"), ",") {
+		pair = strings.TrimSpace(pair)
+		if len(pair) == 0 {
+			continue
+		}
+
+		pieces := strings.SplitN(pair, "=", 2)
+		if len(pieces) != 2 || (pieces[1] != "true" && pieces[1] != "false") {
+			return nil, fmt.Errorf("Expected KNCTL_E2E_CAPABILITIES item '%s' to be in format 'capability=true|false'", pair)
+		}
+
+		result[Capability(pieces[0])] = pieces[1] == "true"
+	}
+
+	return result, nil
+}
+
+// runKubectlOutsideTest does not use Kubectl helper since work done
+// outside of tests (e.g. capability detection, run namespace creation)
+// is shared by all tests and must not fail any particular one
+func runKubectlOutsideTest(args ...string) (string, error) {
+	var stdout, stderr bytes.Buffer
+
+	cmd := exec.Command("kubectl", args...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	err := cmd.Run()
+	if err != nil {
+		return "", fmt.Errorf("Running 'kubectl %s': %s (stderr: %s)", strings.Join(args, " "), err, stderr.String())
+	}
+
+	return stdout.String(), nil
+}
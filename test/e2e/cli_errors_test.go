@@ -24,7 +24,7 @@ import (
 
 func TestCLIErrorsForFlagsBeforeExtraArgs(t *testing.T) {
 	env := BuildEnv(t)
-	knctl := Knctl{t, env.Namespace, Logger{}}
+	knctl := Knctl{t, env.Namespace, NewLogger(t)}
 
 	var stderr bytes.Buffer
 
@@ -47,7 +47,7 @@ func TestCLIErrorsForFlagsBeforeExtraArgs(t *testing.T) {
 
 func TestCLIErrorsForCommandGroups(t *testing.T) {
 	env := BuildEnv(t)
-	knctl := Knctl{t, env.Namespace, Logger{}}
+	knctl := Knctl{t, env.Namespace, NewLogger(t)}
 
 	// For commands with children commands it's friendlier ux
 	// to ignore extra arguments and show available subcommands
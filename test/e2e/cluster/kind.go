@@ -0,0 +1,146 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package cluster provisions disposable clusters for e2e tests
+// so that they can run without a pre-provisioned cluster.
+package cluster
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	DefaultServingVersion = "1.2.0"
+)
+
+type KindOpts struct {
+	// Defaults to 'knctl-e2e-<timestamp>'
+	Name string
+	// Defaults to kind's default node image
+	NodeImage string
+	// Knative Serving version installed via 'knctl install' with Kourier
+	ServingVersion string
+
+	Logf func(msg string, args ...interface{})
+}
+
+// KindCluster is a kind (Kubernetes in Docker) cluster with
+// Knative Serving and Kourier installed. Requires 'kind', 'kubectl'
+// and 'knctl' commands installed on the system.
+type KindCluster struct {
+	opts           KindOpts
+	dir            string
+	KubeconfigPath string
+}
+
+func NewKindCluster(opts KindOpts) *KindCluster {
+	if len(opts.Name) == 0 {
+		opts.Name = fmt.Sprintf("knctl-e2e-%d", time.Now().Unix())
+	}
+	if len(opts.ServingVersion) == 0 {
+		opts.ServingVersion = DefaultServingVersion
+	}
+	if opts.Logf == nil {
+		opts.Logf = func(string, ...interface{}) {}
+	}
+	return &KindCluster{opts: opts}
+}
+
+func (c *KindCluster) Name() string { return c.opts.Name }
+
+// Create creates cluster and installs Knative; partially
+// created cluster is deleted if any step fails
+func (c *KindCluster) Create() error {
+	dir, err := ioutil.TempDir("", "knctl-e2e-cluster")
+	if err != nil {
+		return fmt.Errorf("Creating temp dir: %s", err)
+	}
+
+	c.dir = dir
+	c.KubeconfigPath = filepath.Join(dir, "kubeconfig")
+
+	err = c.create()
+	if err != nil {
+		c.Delete()
+		return err
+	}
+
+	return nil
+}
+
+func (c *KindCluster) create() error {
+	c.opts.Logf("Creating kind cluster '%s'\n", c.opts.Name)
+
+	args := []string{"create", "cluster", "--name", c.opts.Name, "--kubeconfig", c.KubeconfigPath, "--wait", "5m"}
+	if len(c.opts.NodeImage) > 0 {
+		args = append(args, "--image", c.opts.NodeImage)
+	}
+
+	err := c.run("kind", args...)
+	if err != nil {
+		return err
+	}
+
+	c.opts.Logf("Installing Knative Serving %s with Kourier\n", c.opts.ServingVersion)
+
+	// Node ports are used since kind does not provide load balancers
+	return c.run("knctl", "install", "--serving-version", c.opts.ServingVersion,
+		"--net", "kourier", "--node-ports", "--exclude-monitoring", "--kubeconfig", c.KubeconfigPath)
+}
+
+// CreateNamespace creates namespace if it does not exist yet
+func (c *KindCluster) CreateNamespace(name string) error {
+	err := c.run("kubectl", "get", "namespace", name, "--kubeconfig", c.KubeconfigPath)
+	if err == nil {
+		return nil
+	}
+	return c.run("kubectl", "create", "namespace", name, "--kubeconfig", c.KubeconfigPath)
+}
+
+func (c *KindCluster) Delete() error {
+	c.opts.Logf("Deleting kind cluster '%s'\n", c.opts.Name)
+
+	err := c.run("kind", "delete", "cluster", "--name", c.opts.Name, "--kubeconfig", c.KubeconfigPath)
+
+	if len(c.dir) > 0 {
+		os.RemoveAll(c.dir)
+	}
+
+	return err
+}
+
+func (c *KindCluster) run(name string, args ...string) error {
+	var output bytes.Buffer
+
+	cmd := exec.Command(name, args...)
+	cmd.Stdout = &output
+	cmd.Stderr = &output
+
+	err := cmd.Run()
+	if err != nil {
+		return fmt.Errorf("Running '%s %s': %s (output: %s)",
+			name, strings.Join(args, " "), err, strings.TrimSpace(output.String()))
+	}
+
+	return nil
+}
@@ -28,6 +28,8 @@ type Curl struct {
 }
 
 func (c Curl) WaitForContent(serviceName, expectedContent string) {
+	requireLiveCluster(c.t, "curling ingress")
+
 	var curledSuccessfully bool
 	var out string
 
@@ -46,6 +48,8 @@ func (c Curl) WaitForContent(serviceName, expectedContent string) {
 }
 
 func (c Curl) WaitForRouteContent(routeName, expectedContent string) {
+	requireLiveCluster(c.t, "curling ingress")
+
 	var curledSuccessfully bool
 	var out string
 
@@ -64,6 +68,8 @@ func (c Curl) WaitForRouteContent(routeName, expectedContent string) {
 }
 
 func (c Curl) RouteContentCounts(routeName string, times int, responseKey []string) map[string]int {
+	requireLiveCluster(c.t, "curling ingress")
+
 	result := map[string]int{}
 
 	for i := 0; i < times; i++ {
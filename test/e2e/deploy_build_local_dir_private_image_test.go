@@ -24,7 +24,9 @@ import (
 )
 
 func TestDeployBuildLocalDirPrivateImage(t *testing.T) {
-	logger := Logger{}
+	requireCapability(t, CapabilityKnativeBuild)
+
+	logger := NewLogger(t)
 	env := BuildEnv(t)
 	knctl := Knctl{t, env.Namespace, logger}
 	kubectl := Kubectl{t, env.Namespace, logger}
@@ -22,7 +22,7 @@ import (
 )
 
 func TestDeployManagedRouteLaterDeploy(t *testing.T) {
-	logger := Logger{}
+	logger := NewLogger(t)
 	env := BuildEnv(t)
 	knctl := Knctl{t, env.Namespace, logger}
 	curl := Curl{t, knctl}
@@ -107,7 +107,7 @@ func TestDeployManagedRouteLaterDeploy(t *testing.T) {
 }
 
 func TestDeployManagedRouteFirstDeploy(t *testing.T) {
-	logger := Logger{}
+	logger := NewLogger(t)
 	env := BuildEnv(t)
 	knctl := Knctl{t, env.Namespace, logger}
 	curl := Curl{t, knctl}
@@ -24,9 +24,10 @@ import (
 )
 
 func TestBasicDeploy(t *testing.T) {
-	logger := Logger{}
-	env := BuildEnv(t)
-	knctl := Knctl{t, env.Namespace, logger}
+	t.Parallel()
+
+	logger := NewLogger(t)
+	knctl := NewFixtures(t, logger).Knctl
 	curl := Curl{t, knctl}
 
 	const (
@@ -34,13 +35,6 @@ func TestBasicDeploy(t *testing.T) {
 		expectedContent = "TestBasicDeploy_Content"
 	)
 
-	cleanUp := func() {
-		knctl.RunWithOpts([]string{"service", "delete", "-s", serviceName}, RunOpts{AllowError: true})
-	}
-
-	logger.Section("Delete previous service with the same name if exists", cleanUp)
-	defer cleanUp()
-
 	logger.Section("Deploy service", func() {
 		knctl.Run([]string{
 			"deploy",
@@ -50,21 +44,8 @@ func TestBasicDeploy(t *testing.T) {
 		})
 	})
 
-	logger.Section("Checking if service was added", func() {
-		out := knctl.Run([]string{"service", "list", "--json"})
-		resp := uitest.JSONUIFromBytes(t, []byte(out))
-
-		var foundService bool
-
-		for _, row := range resp.Tables[0].Rows {
-			if row["name"] == serviceName {
-				foundService = true
-			}
-		}
-
-		if !foundService {
-			t.Fatalf("Expected to see sample service in the list of services, but did not: '%s'", out)
-		}
+	logger.Section("Checking if service was added and is ready", func() {
+		knctl.RequireServiceReady(serviceName)
 	})
 
 	logger.Section("Checking if service details can be seen", func() {
@@ -98,9 +79,10 @@ func TestBasicDeploy(t *testing.T) {
 	logger.Section("Deleting service", func() {
 		knctl.Run([]string{"service", "delete", "-s", serviceName})
 
-		out := knctl.Run([]string{"service", "list", "--json"})
-		if strings.Contains(out, serviceName) {
-			t.Fatalf("Expected to not see sample service in the list of services, but was: %s", out)
+		for _, row := range knctl.Services() {
+			if row.Name == serviceName {
+				t.Fatalf("Expected to not see sample service in the list of services, but was: %#v", row)
+			}
 		}
 	})
 }
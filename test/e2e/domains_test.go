@@ -23,7 +23,9 @@ import (
 )
 
 func TestDomains(t *testing.T) {
-	logger := Logger{}
+	requireExclusiveCluster(t, "changing default domain")
+
+	logger := NewLogger(t)
 	env := BuildEnv(t)
 	knctl := Knctl{t, env.Namespace, logger}
 
@@ -18,13 +18,45 @@ package e2e
 
 import (
 	"fmt"
+	"testing"
 )
 
-type Logger struct{}
+// Logger prints test progress; sections of loggers created
+// via NewLogger are recorded in e2e report (see report.go)
+type Logger struct {
+	t *testing.T
+}
+
+func NewLogger(t *testing.T) Logger {
+	return Logger{t}
+}
 
 func (l Logger) Section(msg string, f func()) {
 	fmt.Printf("==> %s\n", msg)
+
+	if l.t == nil {
+		f()
+		return
+	}
+
+	step := defaultReport.StartStep(l.t.Name(), msg)
+	failedBefore := l.t.Failed()
+	completed := false
+
+	// Deferred so that steps interrupted by t.Fatalf or t.Skip are recorded
+	defer func() {
+		switch {
+		case l.t.Skipped():
+			defaultReport.EndStep(step, StepSkipped)
+		case !completed || (!failedBefore && l.t.Failed()):
+			defaultReport.EndStep(step, StepFailed)
+		default:
+			defaultReport.EndStep(step, StepPassed)
+		}
+	}()
+
 	f()
+	completed = true
 }
 
 func (l Logger) Debugf(msg string, args ...interface{}) {
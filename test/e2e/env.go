@@ -48,7 +48,7 @@ type EnvBuildPrivateGit struct {
 
 func BuildEnv(t *testing.T) Env {
 	env := Env{
-		Namespace: os.Getenv("KNCTL_E2E_NAMESPACE"),
+		Namespace: envNamespace(),
 
 		BuildGitURL:        os.Getenv("KNCTL_E2E_BUILD_GIT_URL"),
 		BuildGitRevision:   os.Getenv("KNCTL_E2E_BUILD_GIT_REVISION"),
@@ -0,0 +1,94 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package e2e
+
+import (
+	"os"
+	"regexp"
+	"testing"
+
+	apirand "k8s.io/apimachinery/pkg/util/rand"
+)
+
+var (
+	nonDNSCharsRegexp = regexp.MustCompile(`[^a-z0-9-]+`)
+)
+
+// Fixtures provide a namespace unique to a single test so that tests
+// do not interfere with each other and can be run with `-parallel N`
+type Fixtures struct {
+	Namespace string
+	Knctl     Knctl
+	Kubectl   Kubectl
+}
+
+// NewFixtures creates a namespace and registers its clean up once test
+// completes. Set KNCTL_E2E_KEEP_FAILED_NAMESPACES=true to keep namespaces
+// of failed tests for debugging.
+func NewFixtures(t *testing.T, logger Logger) Fixtures {
+	startVCR(t)
+
+	nsName := uniqueNamespaceName(t.Name())
+
+	fixtures := Fixtures{
+		Namespace: nsName,
+		Knctl:     Knctl{t, nsName, logger},
+		Kubectl:   Kubectl{t, nsName, logger},
+	}
+
+	fixtures.Knctl.RunWithOpts([]string{"namespace", "create", nsName}, RunOpts{NoNamespace: true})
+
+	t.Cleanup(func() {
+		if t.Failed() {
+			collectArtifacts(t, nsName, logger)
+		}
+		if t.Failed() && os.Getenv("KNCTL_E2E_KEEP_FAILED_NAMESPACES") == "true" {
+			logger.Debugf("Keeping namespace '%s' of failed test\n", nsName)
+			return
+		}
+		fixtures.cleanUp()
+	})
+
+	return fixtures
+}
+
+func (f Fixtures) cleanUp() {
+	// Delete Knative resources explicitly so that their controllers
+	// finish clean up before namespace is removed
+	for _, res := range []string{"services.serving.knative.dev", "builds.build.knative.dev", "secrets"} {
+		f.Kubectl.RunWithOpts([]string{"delete", res, "--all", "--wait=true"}, RunOpts{AllowError: true})
+	}
+
+	f.Knctl.RunWithOpts([]string{"namespace", "delete", f.Namespace}, RunOpts{NoNamespace: true, AllowError: true})
+}
+
+// uniqueNamespaceName includes run ID so that namespaces of a single
+// run share a prefix and do not collide with other runs on the same cluster
+func uniqueNamespaceName(testName string) string {
+	prefix := "knctl-e2e-" + RunID() + "-"
+
+	if len(vcrMode()) > 0 {
+		// Recorded requests include namespace name
+		return prefix + dnsLabel(testName, 63-len(prefix))
+	}
+
+	// Random suffix avoids waiting for previous namespace
+	// of the same test to terminate (e.g. with -count N)
+	suffix := "-" + apirand.String(5)
+
+	return prefix + dnsLabel(testName, 63-len(prefix)-len(suffix)) + suffix
+}
@@ -0,0 +1,78 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package e2e
+
+import (
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	// Errors caused by cluster infrastructure rather than knctl itself
+	knownFlakyErrors = []string{
+		"connection refused",
+		"connection reset by peer",
+		"i/o timeout",
+		"TLS handshake timeout",
+		"unexpected EOF",
+		"etcdserver: request timed out",
+		"etcdserver: leader changed",
+		"the object has been modified; please apply your changes to the latest version",
+		"Internal error occurred",
+		"the server is currently unable to handle the request",
+		"the server has received too many requests",
+	}
+)
+
+// isFlakyErr classifies output of a failed command as a known flake
+func isFlakyErr(output string) bool {
+	for _, str := range knownFlakyErrors {
+		if strings.Contains(output, str) {
+			return true
+		}
+	}
+	return false
+}
+
+// flakeRetries returns how many times command failing with known flake
+// is retried (configured via KNCTL_E2E_FLAKE_RETRIES; defaults to 2)
+func flakeRetries() int {
+	val := os.Getenv("KNCTL_E2E_FLAKE_RETRIES")
+	if len(val) == 0 {
+		return 2
+	}
+
+	retries, err := strconv.Atoi(val)
+	if err != nil || retries < 0 {
+		return 0
+	}
+
+	return retries
+}
+
+// canRetry indicates if command can be safely re-executed
+// (stdin and provided writers cannot be replayed)
+func (opts RunOpts) canRetry() bool {
+	return !opts.NoRetry && opts.CancelCh == nil && opts.StdinReader == nil &&
+		opts.StdoutWriter == nil && opts.StderrWriter == nil && opts.LineMatcher == nil
+}
+
+func flakeRetryDelay(attempt int) time.Duration {
+	return time.Duration(attempt+1) * 2 * time.Second
+}
@@ -22,7 +22,7 @@ import (
 
 func TestHelpCmdRoot(t *testing.T) {
 	env := BuildEnv(t)
-	knctl := Knctl{t, env.Namespace, Logger{}}
+	knctl := Knctl{t, env.Namespace, NewLogger(t)}
 
 	out, _ := knctl.RunWithOpts([]string{"-h"}, RunOpts{NoNamespace: true})
 
@@ -87,7 +87,7 @@ Succeeded
 
 func TestHelpCmdWithChildren(t *testing.T) {
 	env := BuildEnv(t)
-	knctl := Knctl{t, env.Namespace, Logger{}}
+	knctl := Knctl{t, env.Namespace, NewLogger(t)}
 
 	out, _ := knctl.RunWithOpts([]string{"service", "-h"}, RunOpts{NoNamespace: true})
 
@@ -132,7 +132,7 @@ Succeeded
 
 func TestHelpCmdLeaf(t *testing.T) {
 	env := BuildEnv(t)
-	knctl := Knctl{t, env.Namespace, Logger{}}
+	knctl := Knctl{t, env.Namespace, NewLogger(t)}
 
 	out, _ := knctl.RunWithOpts([]string{"service", "delete", "-h"}, RunOpts{NoNamespace: true})
 
@@ -23,7 +23,7 @@ import (
 )
 
 func TestIngresses(t *testing.T) {
-	logger := Logger{}
+	logger := NewLogger(t)
 	env := BuildEnv(t)
 	knctl := Knctl{t, env.Namespace, logger}
 
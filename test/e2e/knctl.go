@@ -24,6 +24,7 @@ import (
 	"os/exec"
 	"strings"
 	"testing"
+	"time"
 )
 
 type Knctl struct {
@@ -40,6 +41,8 @@ type RunOpts struct {
 	StdinReader  io.Reader
 	CancelCh     chan struct{}
 	Redact       bool
+	NoRetry      bool         // disables retrying of known flakes (see flakes.go)
+	LineMatcher  *LineMatcher // interrupts command once stdout line matches (binary only)
 }
 
 func (k Knctl) Run(args []string) string {
@@ -48,26 +51,84 @@ func (k Knctl) Run(args []string) string {
 }
 
 func (k Knctl) RunWithOpts(args []string, opts RunOpts) (string, error) {
-	k.l.Debugf("Running '%s'...\n", k.cmdDesc(args, opts))
+	desc := k.cmdDesc(args, opts)
+	startedAt := time.Now()
+
+	k.l.Debugf("Running '%s'...\n", desc)
 
 	if !opts.NoNamespace {
 		args = append(args, []string{"-n", k.namespace}...)
 	}
 
+	args = append(args, vcrKubeconfigArgs(k.t)...)
+
+	var stdoutStr, stderrStr string
+	var err error
+
+	for attempt := 0; ; attempt++ {
+		stdoutStr, stderrStr, err = k.runOnce(args, opts)
+		if err == nil || !opts.canRetry() || attempt >= flakeRetries() {
+			break
+		}
+		if !isFlakyErr(stdoutStr + stderrStr) {
+			break
+		}
+
+		k.l.Debugf("Retrying '%s' after known flake (attempt %d): %s\n", k.cmdDesc(args, opts), attempt+1, err)
+		time.Sleep(flakeRetryDelay(attempt))
+	}
+
+	if os.Getenv("KNCTL_E2E_SHOW_RUN_STDOUT") == "true" {
+		k.l.Debugf("Command stdout:\n%s\n", stdoutStr)
+		k.l.Debugf("Command stderr:\n%s\n", stderrStr)
+	}
+
+	cmdReport := CommandReport{Command: desc, Duration: time.Since(startedAt)}
+	if err != nil {
+		cmdReport.Error = err.Error()
+		if !opts.Redact && len(stderrStr) > 0 {
+			cmdReport.Error += ": " + strings.TrimSpace(stderrStr)
+		}
+	}
+	defaultReport.RecordCommand(k.t.Name(), cmdReport)
+
+	if err != nil {
+		err = fmt.Errorf("Execution error: stdout: '%s' stderr: '%s' error: '%s'", stdoutStr, stderrStr, err)
+
+		if !opts.AllowError {
+			collectArtifacts(k.t, k.namespace, k.l)
+			k.t.Fatalf("Failed to successfully execute '%s': %v", k.cmdDesc(args, opts), err)
+		}
+	}
+
+	return stdoutStr, err
+}
+
+func (k Knctl) runOnce(args []string, opts RunOpts) (string, string, error) {
 	var stderr, stdout bytes.Buffer
+	var err error
+
+	if inProcessEnabled(opts) {
+		err = k.runInProcess(args, k.writerOr(opts.StdoutWriter, &stdout), k.writerOr(opts.StderrWriter, &stderr))
+	} else {
+		err = k.runBinary(args, opts, &stdout, &stderr)
+	}
+
+	return stdout.String(), stderr.String(), err
+}
 
+func (k Knctl) runBinary(args []string, opts RunOpts, stdout, stderr io.Writer) error {
 	cmd := exec.Command("knctl", args...)
+	cmd.Stderr = k.writerOr(opts.StderrWriter, stderr)
+	cmd.Stdout = k.writerOr(opts.StdoutWriter, stdout)
 
-	if opts.StderrWriter != nil {
-		cmd.Stderr = opts.StderrWriter
-	} else {
-		cmd.Stderr = &stderr
+	if opts.LineMatcher != nil {
+		cmd.Stdout = opts.LineMatcher.writer(cmd.Stdout)
 	}
 
-	if opts.StdoutWriter != nil {
-		cmd.Stdout = opts.StdoutWriter
-	} else {
-		cmd.Stdout = &stdout
+	err := cmd.Start()
+	if err != nil {
+		return err
 	}
 
 	if opts.CancelCh != nil {
@@ -79,24 +140,27 @@ func (k Knctl) RunWithOpts(args []string, opts RunOpts) (string, error) {
 		}()
 	}
 
-	err := cmd.Run()
-	stdoutStr := stdout.String()
-	stderrStr := stderr.String()
+	doneCh := make(chan struct{})
 
-	if os.Getenv("KNCTL_E2E_SHOW_RUN_STDOUT") == "true" {
-		k.l.Debugf("Command stdout:\n%s\n", stdoutStr)
-		k.l.Debugf("Command stderr:\n%s\n", stderrStr)
+	if opts.LineMatcher != nil {
+		go opts.LineMatcher.interruptOnMatch(cmd.Process, doneCh)
 	}
 
-	if err != nil {
-		err = fmt.Errorf("Execution error: stdout: '%s' stderr: '%s' error: '%s'", stdoutStr, stderrStr, err)
+	err = cmd.Wait()
+	close(doneCh)
 
-		if !opts.AllowError {
-			k.t.Fatalf("Failed to successfully execute '%s': %v", k.cmdDesc(args, opts), err)
-		}
+	if opts.LineMatcher != nil {
+		return opts.LineMatcher.result(err)
 	}
 
-	return stdoutStr, err
+	return err
+}
+
+func (Knctl) writerOr(writer io.Writer, defaultWriter io.Writer) io.Writer {
+	if writer != nil {
+		return writer
+	}
+	return defaultWriter
 }
 
 func (k Knctl) cmdDesc(args []string, opts RunOpts) string {
@@ -0,0 +1,78 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package e2e
+
+import (
+	"io"
+	"os"
+
+	"github.com/cppforlife/go-cli-ui/ui"
+	"github.com/cppforlife/knctl/pkg/knctl/cmd"
+
+	// Import to initialize client auth plugins.
+	_ "k8s.io/client-go/plugin/pkg/client/auth"
+)
+
+// inProcessEnabled indicates that commands should be executed within
+// test process (instead of exec'ing knctl binary) so that coverage
+// and race detector include command code
+func inProcessEnabled(opts RunOpts) bool {
+	if os.Getenv("KNCTL_E2E_IN_PROCESS") != "true" {
+		return false
+	}
+	// Interrupting and feeding stdin is only supported for knctl binary
+	return opts.CancelCh == nil && opts.StdinReader == nil && opts.LineMatcher == nil
+}
+
+// runInProcess mimics cmd/knctl/knctl.go with output captured in given writers
+func (k Knctl) runInProcess(args []string, stdoutWriter, stderrWriter io.Writer) error {
+	var parentUI ui.UI = ui.NewPaddingUI(ui.NewWriterUI(stdoutWriter, stderrWriter, ui.NewNoopLogger()))
+
+	// knctl binary does not output to TTY when run by tests
+	if !k.hasArg(args, "--tty") {
+		parentUI = ui.NewNonTTYUI(parentUI)
+	}
+
+	confUI := ui.NewWrappingConfUI(parentUI, ui.NewNoopLogger())
+	defer confUI.Flush()
+
+	if !k.hasArg(args, "--no-color") {
+		args = append(args, "--no-color")
+	}
+
+	command := cmd.NewDefaultKnctlCmd(confUI)
+	command.SetArgs(args)
+
+	err := command.Execute()
+	if err != nil {
+		confUI.ErrorLinef("Error: %v", err)
+		return err
+	}
+
+	confUI.PrintLinef("Succeeded")
+
+	return nil
+}
+
+func (Knctl) hasArg(args []string, expectedArg string) bool {
+	for _, arg := range args {
+		if arg == expectedArg {
+			return true
+		}
+	}
+	return false
+}
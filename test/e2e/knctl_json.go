@@ -0,0 +1,158 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package e2e
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	uitest "github.com/cppforlife/go-cli-ui/ui/test"
+)
+
+type ServiceRow struct {
+	Name           string `json:"name"`
+	Domain         string `json:"domain"`
+	InternalDomain string `json:"internal_domain"`
+	Annotations    string `json:"annotations"`
+	Conditions     string `json:"conditions"`
+	Age            string `json:"age"`
+}
+
+type RevisionRow struct {
+	Name        string `json:"name"`
+	Tags        string `json:"tags"`
+	Annotations string `json:"annotations"`
+	Conditions  string `json:"conditions"`
+	Age         string `json:"age"`
+	Traffic     string `json:"traffic"`
+}
+
+type RouteRow struct {
+	Name           string `json:"name"`
+	Domain         string `json:"domain"`
+	InternalDomain string `json:"internal_domain"`
+	Traffic        string `json:"traffic"`
+	Annotations    string `json:"annotations"`
+	Conditions     string `json:"conditions"`
+	Age            string `json:"age"`
+}
+
+// RunJSON runs command with --json flag and unmarshals
+// rows of the first printed table into given slice of structs
+func (k Knctl) RunJSON(args []string, rows interface{}) {
+	out := k.Run(append(args, "--json"))
+	resp := uitest.JSONUIFromBytes(k.t, []byte(out))
+
+	if len(resp.Tables) == 0 {
+		k.t.Fatalf("Expected command '%s' to output a table, but was: %s", k.cmdDesc(args, RunOpts{}), out)
+	}
+
+	bs, err := json.Marshal(resp.Tables[0].Rows)
+	if err != nil {
+		k.t.Fatalf("Expected marshaling table rows to succeed: %s", err)
+	}
+
+	err = json.Unmarshal(bs, rows)
+	if err != nil {
+		k.t.Fatalf("Expected unmarshaling table rows into %T to succeed: %s", rows, err)
+	}
+}
+
+func (k Knctl) Services() []ServiceRow {
+	var rows []ServiceRow
+	k.RunJSON([]string{"service", "list"}, &rows)
+	return rows
+}
+
+func (k Knctl) Revisions(serviceName string) []RevisionRow {
+	var rows []RevisionRow
+	k.RunJSON([]string{"revision", "list", "-s", serviceName}, &rows)
+	return rows
+}
+
+func (k Knctl) Routes() []RouteRow {
+	var rows []RouteRow
+	k.RunJSON([]string{"route", "list"}, &rows)
+	return rows
+}
+
+func (k Knctl) RequireService(serviceName string) ServiceRow {
+	rows := k.Services()
+	for _, row := range rows {
+		if row.Name == serviceName {
+			return row
+		}
+	}
+	k.t.Fatalf("Expected to find service '%s', but did not in %#v", serviceName, rows)
+	return ServiceRow{}
+}
+
+func (k Knctl) RequireRoute(routeName string) RouteRow {
+	rows := k.Routes()
+	for _, row := range rows {
+		if row.Name == routeName {
+			return row
+		}
+	}
+	k.t.Fatalf("Expected to find route '%s', but did not in %#v", routeName, rows)
+	return RouteRow{}
+}
+
+// RequireServiceReady checks that all service conditions are satisfied
+func (k Knctl) RequireServiceReady(serviceName string) {
+	row := k.RequireService(serviceName)
+
+	var ok, total int
+
+	_, err := fmt.Sscanf(row.Conditions, "%d OK / %d", &ok, &total)
+	if err != nil {
+		k.t.Fatalf("Expected to parse service '%s' conditions '%s': %s", serviceName, row.Conditions, err)
+	}
+
+	if total == 0 || ok != total {
+		k.t.Fatalf("Expected service '%s' to be ready, but conditions were '%s'", serviceName, row.Conditions)
+	}
+}
+
+// RequireTrafficSplit checks that route sends exactly given
+// percentages of traffic to destinations (configurations or revisions)
+func (k Knctl) RequireTrafficSplit(routeName string, expected map[string]int) {
+	row := k.RequireRoute(routeName)
+	actual := map[string]int{}
+
+	for _, line := range strings.Split(row.Traffic, "\n") {
+		if len(line) == 0 {
+			continue
+		}
+
+		var percent int
+		var dst string
+
+		_, err := fmt.Sscanf(line, "%d%% -> %s", &percent, &dst)
+		if err != nil {
+			k.t.Fatalf("Expected to parse route '%s' traffic '%s': %s", routeName, line, err)
+		}
+
+		actual[dst] += percent
+	}
+
+	if !reflect.DeepEqual(actual, expected) {
+		k.t.Fatalf("Expected route '%s' traffic split to be %#v, but was %#v", routeName, expected, actual)
+	}
+}
@@ -43,6 +43,8 @@ func (k Kubectl) RunWithOpts(args []string, opts RunOpts) (string, error) {
 		args = append(args, []string{"-n", k.namespace}...)
 	}
 
+	args = append(args, vcrKubeconfigArgs(k.t)...)
+
 	var stderr bytes.Buffer
 	var stdout bytes.Buffer
 
@@ -0,0 +1,137 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package e2e
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+// LineMatcher evaluates each stdout line of a command as it arrives
+// and interrupts command once a line matches, so that tests of
+// long running commands (e.g. 'logs -f') do not need to sleep
+type LineMatcher struct {
+	Match func(line string) bool
+	// Number of lines preceding matched line to keep in Context
+	ContextLines int
+	// Command fails if no line matches within timeout (zero waits until command exits)
+	Timeout time.Duration
+
+	// Populated once command completes
+	Matched bool
+	Line    string
+	Context []string
+
+	lock      sync.Mutex
+	recent    []string
+	partial   []byte
+	timedOut  bool
+	matchedCh chan struct{}
+}
+
+func (m *LineMatcher) writer(w io.Writer) io.Writer {
+	m.matchedCh = make(chan struct{})
+	return io.MultiWriter(w, lineMatcherWriter{m})
+}
+
+func (m *LineMatcher) processLines(p []byte) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if m.Matched {
+		return
+	}
+
+	m.partial = append(m.partial, p...)
+
+	for {
+		idx := bytes.IndexByte(m.partial, '\n')
+		if idx < 0 {
+			return
+		}
+
+		line := string(bytes.TrimRight(m.partial[:idx], "\r"))
+		m.partial = m.partial[idx+1:]
+
+		if m.Match(line) {
+			m.Matched = true
+			m.Line = line
+			m.Context = m.recent
+			close(m.matchedCh)
+			return
+		}
+
+		if m.ContextLines > 0 {
+			m.recent = append(m.recent, line)
+			if len(m.recent) > m.ContextLines {
+				m.recent = m.recent[1:]
+			}
+		}
+	}
+}
+
+// interruptOnMatch interrupts process once line matched or timeout expired
+func (m *LineMatcher) interruptOnMatch(process *os.Process, doneCh chan struct{}) {
+	var timeoutCh <-chan time.Time
+
+	if m.Timeout > 0 {
+		timer := time.NewTimer(m.Timeout)
+		defer timer.Stop()
+		timeoutCh = timer.C
+	}
+
+	select {
+	case <-m.matchedCh:
+		process.Signal(os.Interrupt)
+	case <-timeoutCh:
+		m.lock.Lock()
+		m.timedOut = true
+		m.lock.Unlock()
+		process.Signal(os.Interrupt)
+	case <-doneCh:
+	}
+}
+
+// result ignores error caused by interrupting command after a match
+func (m *LineMatcher) result(err error) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	switch {
+	case m.Matched:
+		return nil
+	case m.timedOut:
+		return fmt.Errorf("Expected output line to match within %s", m.Timeout)
+	case err != nil:
+		return err
+	default:
+		return fmt.Errorf("Expected output line to match before command exited")
+	}
+}
+
+type lineMatcherWriter struct {
+	m *LineMatcher
+}
+
+func (w lineMatcherWriter) Write(p []byte) (int, error) {
+	w.m.processLines(p)
+	return len(p), nil
+}
@@ -28,7 +28,7 @@ import (
 )
 
 func TestLogsFollow(t *testing.T) {
-	logger := Logger{}
+	logger := NewLogger(t)
 	env := BuildEnv(t)
 	knctl := Knctl{t, env.Namespace, logger}
 	curl := Curl{t, knctl}
@@ -65,6 +65,18 @@ func TestLogsFollow(t *testing.T) {
 		curl.WaitForContent(serviceName, expectedContentRev1)
 	})
 
+	logger.Section("Follow logs until revision 1 reports that it started", func() {
+		matcher := &LineMatcher{
+			Match:        func(line string) bool { return strings.HasSuffix(line, "Hello world sample started.") },
+			ContextLines: 5,
+			Timeout:      2 * time.Minute,
+		}
+
+		knctl.RunWithOpts([]string{"logs", "-s", serviceName, "-f"}, RunOpts{LineMatcher: matcher})
+
+		logger.Debugf("Matched log line '%s' after:\n%s\n", matcher.Line, strings.Join(matcher.Context, "\n"))
+	})
+
 	cancelCh := make(chan struct{})
 	doneCh := make(chan struct{})
 	collectedLogs := &LogsWriter{}
@@ -0,0 +1,104 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package e2e
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/cppforlife/knctl/test/e2e/cluster"
+)
+
+func TestMain(m *testing.M) {
+	deleteCluster, err := provisionCluster()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Provisioning e2e cluster: %s\n", err)
+		os.Exit(1)
+	}
+
+	deleteRunNamespace, err := createRunNamespace()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Preparing e2e run: %s\n", err)
+		deleteCluster()
+		os.Exit(1)
+	}
+
+	code := m.Run()
+
+	deleteRunNamespace()
+	deleteCluster()
+
+	err = writeReports()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Writing e2e reports: %s\n", err)
+		if code == 0 {
+			code = 1
+		}
+	}
+
+	os.Exit(code)
+}
+
+// provisionCluster creates disposable kind cluster when KNCTL_E2E_CLUSTER=kind
+// and points knctl and kubectl at it. Set KNCTL_E2E_KEEP_CLUSTER=true to keep it.
+func provisionCluster() (func(), error) {
+	noop := func() {}
+
+	switch os.Getenv("KNCTL_E2E_CLUSTER") {
+	case "":
+		return noop, nil
+	case "kind":
+	default:
+		return noop, fmt.Errorf("Expected KNCTL_E2E_CLUSTER to be 'kind' but was '%s'", os.Getenv("KNCTL_E2E_CLUSTER"))
+	}
+
+	kind := cluster.NewKindCluster(cluster.KindOpts{
+		NodeImage:      os.Getenv("KNCTL_E2E_KIND_NODE_IMAGE"),
+		ServingVersion: os.Getenv("KNCTL_E2E_SERVING_VERSION"),
+		Logf:           Logger{}.Debugf,
+	})
+
+	err := kind.Create()
+	if err != nil {
+		return noop, err
+	}
+
+	deleteFunc := func() {
+		if os.Getenv("KNCTL_E2E_KEEP_CLUSTER") == "true" {
+			Logger{}.Debugf("Keeping kind cluster '%s' (kubeconfig: %s)\n", kind.Name(), kind.KubeconfigPath)
+			return
+		}
+		err := kind.Delete()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Deleting e2e cluster: %s\n", err)
+		}
+	}
+
+	os.Setenv("KUBECONFIG", kind.KubeconfigPath)
+	os.Setenv("KNCTL_KUBECONFIG", kind.KubeconfigPath)
+
+	if ns := os.Getenv("KNCTL_E2E_NAMESPACE"); len(ns) > 0 {
+		err := kind.CreateNamespace(ns)
+		if err != nil {
+			deleteFunc()
+			return noop, err
+		}
+	}
+
+	return deleteFunc, nil
+}
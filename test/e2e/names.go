@@ -0,0 +1,131 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package e2e
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+
+	apirand "k8s.io/apimachinery/pkg/util/rand"
+)
+
+const (
+	maxRunIDLen = 12
+
+	// Knative derives names of revisions (-00001) and their
+	// deployments (-deployment) from service name; all of them
+	// are used as label values which are limited to 63 chars
+	maxUniqueNameLen = 63 - len("-00001") - len("-deployment")
+)
+
+var (
+	runIDOnce sync.Once
+	runID     string
+)
+
+// RunID identifies current suite run. It is included into names of
+// resources created by the run so that multiple runs (e.g. of different PRs)
+// can share a cluster. Set KNCTL_E2E_RUN_ID (e.g. to CI job ID) to make
+// names predictable; otherwise it's randomly generated once per process.
+func RunID() string {
+	runIDOnce.Do(func() {
+		switch {
+		case len(vcrMode()) > 0:
+			// Recorded requests include resource names
+			runID = "vcr00"
+		case len(os.Getenv("KNCTL_E2E_RUN_ID")) > 0:
+			runID = dnsLabel(os.Getenv("KNCTL_E2E_RUN_ID"), maxRunIDLen)
+		default:
+			runID = apirand.String(5)
+		}
+	})
+	return runID
+}
+
+// UniqueName suffixes base name with run ID, truncating base if necessary
+// so that result (and names Knative derives from it) are valid DNS labels
+func UniqueName(base string) string {
+	suffix := "-" + RunID()
+	return dnsLabel(base, maxUniqueNameLen-len(suffix)) + suffix
+}
+
+func dnsLabel(name string, maxLen int) string {
+	name = nonDNSCharsRegexp.ReplaceAllString(strings.ToLower(name), "-")
+	if len(name) > maxLen {
+		name = name[:maxLen]
+	}
+	return strings.Trim(name, "-")
+}
+
+// sharedCluster indicates that cluster may be used by other suite runs
+// concurrently (KNCTL_E2E_SHARED_CLUSTER=true). In that case tests that
+// use KNCTL_E2E_NAMESPACE get a namespace unique to current run and
+// tests that change cluster-wide configuration are skipped.
+func sharedCluster() bool {
+	return os.Getenv("KNCTL_E2E_SHARED_CLUSTER") == "true"
+}
+
+func envNamespace() string {
+	ns := os.Getenv("KNCTL_E2E_NAMESPACE")
+	if len(ns) > 0 && sharedCluster() {
+		return UniqueName(ns)
+	}
+	return ns
+}
+
+// requireExclusiveCluster skips test that changes cluster-wide
+// configuration when cluster is shared with other suite runs
+func requireExclusiveCluster(t *testing.T, desc string) {
+	if sharedCluster() {
+		t.Skipf("Skipping test since cluster is shared with other runs: %s affects whole cluster", desc)
+	}
+}
+
+// createRunNamespace creates namespace unique to current run when cluster
+// is shared; returned function deletes it unless KNCTL_E2E_KEEP_RUN_NAMESPACE=true
+func createRunNamespace() (func(), error) {
+	noop := func() {}
+
+	ns := envNamespace()
+	if !sharedCluster() || len(ns) == 0 || len(vcrMode()) > 0 {
+		return noop, nil
+	}
+
+	_, err := runKubectlOutsideTest("get", "namespace", ns)
+	if err != nil {
+		_, err = runKubectlOutsideTest("create", "namespace", ns)
+		if err != nil {
+			return noop, fmt.Errorf("Creating run namespace: %s", err)
+		}
+	}
+
+	deleteFunc := func() {
+		if os.Getenv("KNCTL_E2E_KEEP_RUN_NAMESPACE") == "true" {
+			Logger{}.Debugf("Keeping run namespace '%s'\n", ns)
+			return
+		}
+		_, err := runKubectlOutsideTest("delete", "namespace", ns, "--wait=false")
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Deleting run namespace: %s\n", err)
+		}
+	}
+
+	return deleteFunc, nil
+}
@@ -24,7 +24,7 @@ import (
 )
 
 func TestPods(t *testing.T) {
-	logger := Logger{}
+	logger := NewLogger(t)
 	env := BuildEnv(t)
 	knctl := Knctl{t, env.Namespace, logger}
 	curl := Curl{t, knctl}
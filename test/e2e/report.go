@@ -0,0 +1,206 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package e2e
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/cppforlife/knctl/pkg/knctl/junit"
+)
+
+const (
+	StepPassed  StepStatus = "passed"
+	StepFailed  StepStatus = "failed"
+	StepSkipped StepStatus = "skipped"
+
+	commandsOutsideSteps = "(commands outside of sections)"
+)
+
+var (
+	// defaultReport collects steps of all tests;
+	// it is written out by TestMain if requested via env variables
+	defaultReport = NewReport()
+)
+
+type StepStatus string
+
+type StepReport struct {
+	Test     string          `json:"test"`
+	Name     string          `json:"name"`
+	Status   StepStatus      `json:"status"`
+	Duration time.Duration   `json:"duration"`
+	Commands []CommandReport `json:"commands,omitempty"`
+
+	startedAt time.Time
+}
+
+type CommandReport struct {
+	Command  string        `json:"command"`
+	Duration time.Duration `json:"duration"`
+	Error    string        `json:"error,omitempty"`
+}
+
+// Report records sections (steps) of each test and knctl commands run within them
+type Report struct {
+	lock  sync.Mutex
+	steps []*StepReport
+	// open tracks currently running steps per test
+	open map[string][]*StepReport
+}
+
+func NewReport() *Report {
+	return &Report{open: map[string][]*StepReport{}}
+}
+
+func (r *Report) StartStep(test, name string) *StepReport {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	step := &StepReport{Test: test, Name: name, startedAt: time.Now()}
+
+	r.steps = append(r.steps, step)
+	r.open[test] = append(r.open[test], step)
+
+	return step
+}
+
+func (r *Report) EndStep(step *StepReport, status StepStatus) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	step.Status = status
+	step.Duration = time.Since(step.startedAt)
+
+	open := r.open[step.Test]
+
+	for i := len(open) - 1; i >= 0; i-- {
+		if open[i] == step {
+			r.open[step.Test] = append(open[:i], open[i+1:]...)
+			break
+		}
+	}
+}
+
+// RecordCommand adds command to innermost running step of a test
+func (r *Report) RecordCommand(test string, cmd CommandReport) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if open := r.open[test]; len(open) > 0 {
+		step := open[len(open)-1]
+		step.Commands = append(step.Commands, cmd)
+		return
+	}
+
+	status := StepPassed
+	if len(cmd.Error) > 0 {
+		status = StepFailed
+	}
+
+	r.steps = append(r.steps, &StepReport{
+		Test:     test,
+		Name:     commandsOutsideSteps,
+		Status:   status,
+		Duration: cmd.Duration,
+		Commands: []CommandReport{cmd},
+	})
+}
+
+func (r *Report) Steps() []StepReport {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	var result []StepReport
+	for _, step := range r.steps {
+		result = append(result, *step)
+	}
+	return result
+}
+
+func (r *Report) WriteJSON(path string) error {
+	bs, err := json.MarshalIndent(r.Steps(), "", "  ")
+	if err != nil {
+		return fmt.Errorf("Marshaling report: %s", err)
+	}
+
+	return ioutil.WriteFile(path, bs, 0644)
+}
+
+// WriteJUnit writes each step as a test case with test name as its class name
+func (r *Report) WriteJUnit(path string) error {
+	var results []junit.Result
+
+	for _, step := range r.Steps() {
+		result := junit.Result{
+			Name:      step.Name,
+			ClassName: step.Test,
+			Duration:  step.Duration,
+		}
+
+		for _, cmd := range step.Commands {
+			result.Output += fmt.Sprintf("%s (%s)\n", cmd.Command, cmd.Duration.Round(time.Millisecond))
+		}
+
+		switch step.Status {
+		case StepSkipped:
+			result.SkipReason = "Test was skipped"
+		case StepFailed:
+			result.Err = fmt.Errorf("Step failed")
+			for _, cmd := range step.Commands {
+				if len(cmd.Error) > 0 {
+					result.Err = fmt.Errorf("Command '%s' failed: %s", cmd.Command, cmd.Error)
+				}
+			}
+		}
+
+		results = append(results, result)
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("Creating report: %s", err)
+	}
+
+	defer file.Close()
+
+	return junit.Write(file, junit.NewTestSuite("knctl-e2e", results))
+}
+
+// writeReports writes report files configured via
+// KNCTL_E2E_REPORT_JUNIT and KNCTL_E2E_REPORT_JSON env variables
+func writeReports() error {
+	if path := os.Getenv("KNCTL_E2E_REPORT_JUNIT"); len(path) > 0 {
+		err := defaultReport.WriteJUnit(path)
+		if err != nil {
+			return err
+		}
+	}
+
+	if path := os.Getenv("KNCTL_E2E_REPORT_JSON"); len(path) > 0 {
+		err := defaultReport.WriteJSON(path)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -25,7 +25,7 @@ import (
 )
 
 func TestRevisions(t *testing.T) {
-	logger := Logger{}
+	logger := NewLogger(t)
 	env := BuildEnv(t)
 	knctl := Knctl{t, env.Namespace, logger}
 	curl := Curl{t, knctl}
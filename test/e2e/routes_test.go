@@ -17,16 +17,14 @@ limitations under the License.
 package e2e
 
 import (
-	"strings"
 	"testing"
-
-	uitest "github.com/cppforlife/go-cli-ui/ui/test"
 )
 
 func TestRoutes(t *testing.T) {
-	logger := Logger{}
-	env := BuildEnv(t)
-	knctl := Knctl{t, env.Namespace, logger}
+	t.Parallel()
+
+	logger := NewLogger(t)
+	knctl := NewFixtures(t, logger).Knctl
 	curl := Curl{t, knctl}
 
 	const (
@@ -35,15 +33,6 @@ func TestRoutes(t *testing.T) {
 		serviceName2 = "test-routes-service2-name"
 	)
 
-	cleanUp := func() {
-		knctl.RunWithOpts([]string{"route", "delete", "--route", routeName}, RunOpts{AllowError: true})
-		knctl.RunWithOpts([]string{"service", "delete", "-s", serviceName1}, RunOpts{AllowError: true})
-		knctl.RunWithOpts([]string{"service", "delete", "-s", serviceName2}, RunOpts{AllowError: true})
-	}
-
-	logger.Section("Delete previous route and services if exists", cleanUp)
-	defer cleanUp()
-
 	logger.Section("Deploy services that can be routed to", func() {
 		knctl.Run([]string{
 			"deploy",
@@ -70,29 +59,7 @@ func TestRoutes(t *testing.T) {
 	})
 
 	logger.Section("Checking if route was added", func() {
-		out := knctl.Run([]string{"route", "list", "--json"})
-		resp := uitest.JSONUIFromBytes(t, []byte(out))
-
-		var foundRoute bool
-
-		for _, row := range resp.Tables[0].Rows {
-			if row["name"] == routeName {
-				foundRoute = true
-
-				traffic := row["traffic"]
-
-				if !strings.Contains(traffic, "50% -> "+serviceName1) {
-					t.Fatalf("Expected route to point to '%s', but was '%s'", serviceName1, traffic)
-				}
-				if !strings.Contains(traffic, "50% -> "+serviceName2) {
-					t.Fatalf("Expected route to point to '%s', but was '%s'", serviceName2, traffic)
-				}
-			}
-		}
-
-		if !foundRoute {
-			t.Fatalf("Expected to see route in the list of routes, but did not: '%s'", out)
-		}
+		knctl.RequireTrafficSplit(routeName, map[string]int{serviceName1: 50, serviceName2: 50})
 	})
 
 	logger.Section("Check if route directs traffic to both services", func() {
@@ -123,29 +90,7 @@ func TestRoutes(t *testing.T) {
 	})
 
 	logger.Section("Checking if route was reconfigured", func() {
-		out := knctl.Run([]string{"route", "list", "--json"})
-		resp := uitest.JSONUIFromBytes(t, []byte(out))
-
-		var foundRoute bool
-
-		for _, row := range resp.Tables[0].Rows {
-			if row["name"] == routeName {
-				foundRoute = true
-
-				traffic := row["traffic"]
-
-				if !strings.Contains(traffic, "20% -> "+serviceName1) {
-					t.Fatalf("Expected route to point to '%s', but was '%s'", serviceName1, traffic)
-				}
-				if !strings.Contains(traffic, "80% -> "+serviceName2) {
-					t.Fatalf("Expected route to point to '%s', but was '%s'", serviceName2, traffic)
-				}
-			}
-		}
-
-		if !foundRoute {
-			t.Fatalf("Expected to see route in the list of routes, but did not: '%s'", out)
-		}
+		knctl.RequireTrafficSplit(routeName, map[string]int{serviceName1: 20, serviceName2: 80})
 	})
 
 	logger.Section("Check if route directs traffic to both services after being reconfigured", func() {
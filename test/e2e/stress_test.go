@@ -0,0 +1,261 @@
+//go:build e2e_stress
+// +build e2e_stress
+
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package e2e
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// StressBudget configures stress test; all values can be overridden
+// via KNCTL_E2E_STRESS_* environment variables (see README)
+type StressBudget struct {
+	Services       int           // number of services deployed concurrently
+	MaxLatency     time.Duration // per knctl command
+	MaxAPIRequests int           // per knctl command
+	CurlAttempts   int           // per service before it's considered unreachable
+}
+
+type stressCommand struct {
+	Kind        string
+	Service     string
+	Duration    time.Duration
+	APIRequests int
+	Err         error
+}
+
+type stressRecorder struct {
+	knctl Knctl
+
+	lock     sync.Mutex
+	commands []stressCommand
+	failures []string
+}
+
+// TestStress deploys, curls and deletes many services concurrently
+// to catch latency and API usage regressions that only show up under load.
+// It's excluded from regular runs; use `go test -tags e2e_stress`.
+func TestStress(t *testing.T) {
+	if os.Getenv("KNCTL_E2E_IN_PROCESS") == "true" {
+		t.Skipf("Skipping stress test: API requests are counted via --debug output of knctl binary")
+	}
+
+	requireLiveCluster(t, "stress test")
+
+	budget := stressBudgetFromEnv(t)
+	logger := NewLogger(t)
+	stress := &stressRecorder{knctl: NewFixtures(t, logger).Knctl}
+
+	logger.Section(fmt.Sprintf("Deploying, curling and deleting %d services concurrently", budget.Services), func() {
+		var wg sync.WaitGroup
+
+		for i := 0; i < budget.Services; i++ {
+			wg.Add(1)
+			go func(serviceName string) {
+				defer wg.Done()
+				stress.serviceLifecycle(serviceName, budget)
+			}(fmt.Sprintf("stress-%03d", i))
+		}
+
+		wg.Wait()
+	})
+
+	logger.Section("Checking commands against latency and API request budgets", func() {
+		for _, line := range stress.summary() {
+			logger.Debugf("%s\n", line)
+		}
+
+		violations := stress.violations(budget)
+		if len(violations) > 0 {
+			t.Fatalf("Expected all commands to succeed within budget:\n%s", strings.Join(violations, "\n"))
+		}
+	})
+}
+
+func stressBudgetFromEnv(t *testing.T) StressBudget {
+	budget := StressBudget{
+		Services:       100,
+		MaxLatency:     5 * time.Minute,
+		MaxAPIRequests: 100,
+		CurlAttempts:   120,
+	}
+
+	intFromEnv := func(name string, val *int) {
+		if str := os.Getenv(name); len(str) > 0 {
+			num, err := strconv.Atoi(str)
+			if err != nil || num < 1 {
+				t.Fatalf("Expected %s to be a positive integer but was '%s'", name, str)
+			}
+			*val = num
+		}
+	}
+
+	intFromEnv("KNCTL_E2E_STRESS_SERVICES", &budget.Services)
+	intFromEnv("KNCTL_E2E_STRESS_MAX_API_REQUESTS", &budget.MaxAPIRequests)
+	intFromEnv("KNCTL_E2E_STRESS_CURL_ATTEMPTS", &budget.CurlAttempts)
+
+	if str := os.Getenv("KNCTL_E2E_STRESS_MAX_LATENCY"); len(str) > 0 {
+		dur, err := time.ParseDuration(str)
+		if err != nil {
+			t.Fatalf("Expected KNCTL_E2E_STRESS_MAX_LATENCY to be a duration: %s", err)
+		}
+		budget.MaxLatency = dur
+	}
+
+	return budget
+}
+
+// serviceLifecycle runs in its own goroutine hence
+// it records failures instead of failing the test
+func (r *stressRecorder) serviceLifecycle(serviceName string, budget StressBudget) {
+	_, err := r.run("deploy", serviceName, []string{
+		"deploy",
+		"-s", serviceName,
+		"-i", "gcr.io/knative-samples/helloworld-go",
+		"-e", "TARGET=" + serviceName,
+		"--watch-pod-logs=false",
+	})
+	if err != nil {
+		r.fail("Deploying service '%s': %s", serviceName, err)
+		return
+	}
+
+	var curledSuccessfully bool
+
+	for i := 0; i < budget.CurlAttempts; i++ {
+		out, _ := r.run("curl", serviceName, []string{"curl", "-s", serviceName})
+		if strings.Contains(out, serviceName) {
+			curledSuccessfully = true
+			break
+		}
+		time.Sleep(1 * time.Second)
+	}
+
+	if !curledSuccessfully {
+		r.fail("Curling service '%s': did not respond with expected content after %d attempts", serviceName, budget.CurlAttempts)
+	}
+
+	_, err = r.run("service delete", serviceName, []string{"service", "delete", "-s", serviceName})
+	if err != nil {
+		r.fail("Deleting service '%s': %s", serviceName, err)
+	}
+}
+
+func (r *stressRecorder) run(kind, serviceName string, args []string) (string, error) {
+	var stderr bytes.Buffer
+
+	startedAt := time.Now()
+
+	// Known flakes are not retried since retries would skew latency and request counts
+	out, err := r.knctl.RunWithOpts(append(args, "--debug"), RunOpts{AllowError: true, NoRetry: true, StderrWriter: &stderr})
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.commands = append(r.commands, stressCommand{
+		Kind:        kind,
+		Service:     serviceName,
+		Duration:    time.Since(startedAt),
+		APIRequests: strings.Count(stderr.String(), " tag=api msg=request "),
+		Err:         err,
+	})
+
+	return out, err
+}
+
+func (r *stressRecorder) fail(msg string, args ...interface{}) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.failures = append(r.failures, fmt.Sprintf(msg, args...))
+}
+
+func (r *stressRecorder) violations(budget StressBudget) []string {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	result := append([]string{}, r.failures...)
+
+	for _, cmd := range r.commands {
+		if cmd.Duration > budget.MaxLatency {
+			result = append(result, fmt.Sprintf("Command '%s' for service '%s' took %s (budget: %s)",
+				cmd.Kind, cmd.Service, cmd.Duration.Round(time.Millisecond), budget.MaxLatency))
+		}
+		if cmd.APIRequests > budget.MaxAPIRequests {
+			result = append(result, fmt.Sprintf("Command '%s' for service '%s' made %d API requests (budget: %d)",
+				cmd.Kind, cmd.Service, cmd.APIRequests, budget.MaxAPIRequests))
+		}
+	}
+
+	return result
+}
+
+// summary shows latency percentiles and API request counts per command kind
+func (r *stressRecorder) summary() []string {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	byKind := map[string][]stressCommand{}
+	var kinds []string
+
+	for _, cmd := range r.commands {
+		if _, found := byKind[cmd.Kind]; !found {
+			kinds = append(kinds, cmd.Kind)
+		}
+		byKind[cmd.Kind] = append(byKind[cmd.Kind], cmd)
+	}
+
+	var result []string
+
+	for _, kind := range kinds {
+		cmds := byKind[kind]
+
+		sort.Slice(cmds, func(i, j int) bool { return cmds[i].Duration < cmds[j].Duration })
+
+		var errs, totalRequests, maxRequests int
+
+		for _, cmd := range cmds {
+			if cmd.Err != nil {
+				errs++
+			}
+			totalRequests += cmd.APIRequests
+			if cmd.APIRequests > maxRequests {
+				maxRequests = cmd.APIRequests
+			}
+		}
+
+		percentile := func(p float64) time.Duration {
+			return cmds[int(p*float64(len(cmds)-1))].Duration.Round(time.Millisecond)
+		}
+
+		result = append(result, fmt.Sprintf(
+			"knctl %s: runs=%d errors=%d latency p50=%s p95=%s max=%s api-requests total=%d max=%d",
+			kind, len(cmds), errs, percentile(0.5), percentile(0.95), percentile(1), totalRequests, maxRequests))
+	}
+
+	return result
+}
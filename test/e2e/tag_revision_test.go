@@ -26,7 +26,7 @@ import (
 )
 
 func TestDefaultRevisionTags(t *testing.T) {
-	logger := Logger{}
+	logger := NewLogger(t)
 	env := BuildEnv(t)
 	knctl := Knctl{t, env.Namespace, logger}
 
@@ -168,7 +168,7 @@ func TestDefaultRevisionTags(t *testing.T) {
 }
 
 func TestTagRevisions(t *testing.T) {
-	logger := Logger{}
+	logger := NewLogger(t)
 	env := BuildEnv(t)
 	knctl := Knctl{t, env.Namespace, logger}
 
@@ -0,0 +1,438 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package e2e
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+
+	cmdcore "github.com/cppforlife/knctl/pkg/knctl/cmd/core"
+	"k8s.io/client-go/rest"
+)
+
+const (
+	vcrModeRecord = "record"
+	vcrModeReplay = "replay"
+)
+
+var (
+	// Query params that change between runs and do not affect responses
+	vcrVolatileQueryParams = []string{"resourceVersion", "timeout", "timeoutSeconds"}
+
+	// Parts of JSON request bodies that change between runs
+	// (history entries are stored as escaped JSON within config maps)
+	vcrVolatileBodyPatterns = []vcrBodyPattern{
+		{regexp.MustCompile(`"(resourceVersion|uid|creationTimestamp|selfLink)":"[^"]*"`), `"$1":"<volatile>"`},
+		{regexp.MustCompile(`"name":"KNCTL_DEPLOY","value":"[^"]*"`), `"name":"KNCTL_DEPLOY","value":"<volatile>"`},
+		{regexp.MustCompile(`\\?"(user|time)\\?":\\?"[^"\\]*\\?"`), `<volatile-$1>`},
+		{regexp.MustCompile(`\\?"args\\?":\[[^\]]*\]`), `<volatile-args>`},
+		{regexp.MustCompile(`\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}(\.\d+)?Z`), `<volatile-time>`},
+	}
+
+	vcrCassetteNameRegexp = regexp.MustCompile(`[^A-Za-z0-9_-]+`)
+
+	vcrRecorders = map[*testing.T]*vcrRecorder{}
+	vcrLock      sync.Mutex
+)
+
+type vcrBodyPattern struct {
+	regexp      *regexp.Regexp
+	replacement string
+}
+
+type vcrCassette struct {
+	Interactions []vcrInteraction `json:"interactions"`
+}
+
+type vcrInteraction struct {
+	Method       string `json:"method"`
+	URL          string `json:"url"`
+	RequestBody  string `json:"requestBody,omitempty"`
+	StatusCode   int    `json:"statusCode"`
+	ContentType  string `json:"contentType,omitempty"`
+	ResponseBody string `json:"responseBody"`
+}
+
+func (i vcrInteraction) key() string { return i.Method + " " + i.URL }
+
+type vcrRecorder struct {
+	t              *testing.T
+	mode           string
+	cassettePath   string
+	kubeconfigPath string
+	server         *httptest.Server
+
+	// Used when recording
+	upstreamURL       *url.URL
+	upstreamTransport http.RoundTripper
+
+	lock          sync.Mutex
+	cassette      vcrCassette
+	replayedByKey map[string]int
+}
+
+func vcrMode() string {
+	return os.Getenv("KNCTL_E2E_VCR")
+}
+
+// startVCR records API server interactions of a test into a cassette
+// (KNCTL_E2E_VCR=record) or replays them without a cluster
+// (KNCTL_E2E_VCR=replay). knctl and kubectl are pointed at a local
+// proxy via generated kubeconfig. Does nothing if KNCTL_E2E_VCR is not set.
+func startVCR(t *testing.T) {
+	mode := vcrMode()
+
+	switch mode {
+	case "":
+		return
+	case vcrModeRecord, vcrModeReplay:
+	default:
+		t.Fatalf("Expected KNCTL_E2E_VCR to be either '%s' or '%s' but was '%s'", vcrModeRecord, vcrModeReplay, mode)
+	}
+
+	r := &vcrRecorder{
+		t:             t,
+		mode:          mode,
+		cassettePath:  vcrCassettePath(t.Name()),
+		replayedByKey: map[string]int{},
+	}
+
+	if mode == vcrModeRecord {
+		r.configureUpstream()
+	} else {
+		r.loadCassette()
+	}
+
+	r.server = httptest.NewServer(http.HandlerFunc(r.serveHTTP))
+	r.writeKubeconfig()
+
+	vcrLock.Lock()
+	vcrRecorders[t] = r
+	vcrLock.Unlock()
+
+	t.Cleanup(func() {
+		vcrLock.Lock()
+		delete(vcrRecorders, t)
+		vcrLock.Unlock()
+
+		r.server.CloseClientConnections()
+		r.server.Close()
+
+		if mode == vcrModeRecord && !t.Failed() {
+			r.saveCassette()
+		}
+	})
+}
+
+// vcrKubeconfigArgs returns flags that direct commands to VCR proxy.
+// In replay mode tests that do not use VCR are skipped since there is no cluster.
+func vcrKubeconfigArgs(t *testing.T) []string {
+	vcrLock.Lock()
+	r, found := vcrRecorders[t]
+	vcrLock.Unlock()
+
+	if !found {
+		if vcrMode() == vcrModeReplay {
+			t.Skip("Skipping test that requires cluster in VCR replay mode")
+		}
+		return nil
+	}
+
+	return []string{"--kubeconfig", r.kubeconfigPath}
+}
+
+// requireLiveCluster skips remainder of the test in VCR replay mode
+// when test interacts with something other than API server (e.g. ingress)
+func requireLiveCluster(t *testing.T, desc string) {
+	if vcrMode() == vcrModeReplay {
+		t.Skipf("Skipping remainder of the test in VCR replay mode: %s requires cluster", desc)
+	}
+}
+
+func vcrCassettePath(testName string) string {
+	dir := os.Getenv("KNCTL_E2E_VCR_DIR")
+	if len(dir) == 0 {
+		dir = filepath.Join("assets", "cassettes")
+	}
+	return filepath.Join(dir, vcrCassetteNameRegexp.ReplaceAllString(testName, "_")+".json")
+}
+
+func (r *vcrRecorder) configureUpstream() {
+	configFactory := cmdcore.NewConfigFactoryImpl()
+	configFactory.ConfigurePathResolver(cmdcore.NewKubeconfigPathFlag().Value)
+	configFactory.ConfigureContextResolver(cmdcore.NewKubeconfigContextFlag().Value)
+
+	config, err := configFactory.RESTConfig()
+	if err != nil {
+		r.t.Fatalf("Building upstream config for VCR: %s", err)
+	}
+
+	r.upstreamTransport, err = rest.TransportFor(config)
+	if err != nil {
+		r.t.Fatalf("Building upstream transport for VCR: %s", err)
+	}
+
+	r.upstreamURL, err = url.Parse(config.Host)
+	if err != nil {
+		r.t.Fatalf("Parsing upstream host for VCR: %s", err)
+	}
+}
+
+func (r *vcrRecorder) loadCassette() {
+	bs, err := ioutil.ReadFile(r.cassettePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			r.t.Skipf("Skipping test without recorded cassette '%s' in VCR replay mode", r.cassettePath)
+		}
+		r.t.Fatalf("Reading cassette: %s", err)
+	}
+
+	err = json.Unmarshal(bs, &r.cassette)
+	if err != nil {
+		r.t.Fatalf("Unmarshaling cassette '%s': %s", r.cassettePath, err)
+	}
+}
+
+func (r *vcrRecorder) saveCassette() {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	var buf bytes.Buffer
+
+	// Keep URLs and bodies readable in golden files
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+
+	err := encoder.Encode(r.cassette)
+	if err != nil {
+		r.t.Fatalf("Marshaling cassette: %s", err)
+	}
+
+	err = os.MkdirAll(filepath.Dir(r.cassettePath), 0755)
+	if err != nil {
+		r.t.Fatalf("Creating cassette directory: %s", err)
+	}
+
+	err = ioutil.WriteFile(r.cassettePath, buf.Bytes(), 0644)
+	if err != nil {
+		r.t.Fatalf("Writing cassette: %s", err)
+	}
+}
+
+func (r *vcrRecorder) writeKubeconfig() {
+	r.kubeconfigPath = filepath.Join(r.t.TempDir(), "kubeconfig")
+
+	kubeconfig := fmt.Sprintf(`
+apiVersion: v1
+kind: Config
+current-context: vcr
+clusters:
+- name: vcr
+  cluster: {server: "%s"}
+users:
+- name: vcr
+  user: {}
+contexts:
+- name: vcr
+  context: {cluster: vcr, user: vcr}
+`, r.server.URL)
+
+	err := ioutil.WriteFile(r.kubeconfigPath, []byte(kubeconfig), 0600)
+	if err != nil {
+		r.t.Fatalf("Writing VCR kubeconfig: %s", err)
+	}
+}
+
+func (r *vcrRecorder) serveHTTP(w http.ResponseWriter, req *http.Request) {
+	reqBody, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	interaction := vcrInteraction{
+		Method:      req.Method,
+		URL:         vcrNormalizedURL(req.URL),
+		RequestBody: vcrNormalizedBody(req.Header.Get("Content-Type"), reqBody),
+	}
+
+	if r.mode == vcrModeRecord {
+		r.record(w, req, reqBody, interaction)
+	} else {
+		r.replay(w, req, interaction)
+	}
+}
+
+func (r *vcrRecorder) record(w http.ResponseWriter, req *http.Request, reqBody []byte, interaction vcrInteraction) {
+	upstreamURL := *req.URL
+	upstreamURL.Scheme = r.upstreamURL.Scheme
+	upstreamURL.Host = r.upstreamURL.Host
+	upstreamURL.Path = strings.TrimSuffix(r.upstreamURL.Path, "/") + req.URL.Path
+
+	upstreamReq, err := http.NewRequest(req.Method, upstreamURL.String(), bytes.NewReader(reqBody))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	upstreamReq = upstreamReq.WithContext(req.Context())
+
+	for k, vs := range req.Header {
+		if k != "Authorization" {
+			upstreamReq.Header[k] = vs
+		}
+	}
+
+	resp, err := r.upstreamTransport.RoundTrip(upstreamReq)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+
+	defer resp.Body.Close()
+
+	for k, vs := range resp.Header {
+		w.Header()[k] = vs
+	}
+	w.WriteHeader(resp.StatusCode)
+
+	// Stream response (e.g. watches, logs) while capturing it
+	var respBody bytes.Buffer
+	buf := make([]byte, 32*1024)
+
+	for {
+		n, readErr := resp.Body.Read(buf)
+		if n > 0 {
+			respBody.Write(buf[:n])
+			w.Write(buf[:n])
+			if flusher, ok := w.(http.Flusher); ok {
+				flusher.Flush()
+			}
+		}
+		if readErr != nil {
+			break
+		}
+	}
+
+	interaction.StatusCode = resp.StatusCode
+	interaction.ContentType = resp.Header.Get("Content-Type")
+	interaction.ResponseBody = respBody.String()
+
+	r.lock.Lock()
+	r.cassette.Interactions = append(r.cassette.Interactions, interaction)
+	r.lock.Unlock()
+}
+
+// replay serves recorded interactions with the same method and URL in recorded order.
+// Once exhausted, last matching interaction is repeated (e.g. for polling).
+// Request body has to match recorded one so that tests catch changes to what
+// commands send (e.g. different service spec), not only to what they request.
+func (r *vcrRecorder) replay(w http.ResponseWriter, req *http.Request, interaction vcrInteraction) {
+	key := interaction.key()
+
+	r.lock.Lock()
+	var matches []vcrInteraction
+	for _, i := range r.cassette.Interactions {
+		if i.key() == key {
+			matches = append(matches, i)
+		}
+	}
+	idx := r.replayedByKey[key]
+	r.replayedByKey[key]++
+	r.lock.Unlock()
+
+	if len(matches) == 0 {
+		r.t.Errorf("Expected to find recorded interaction for '%s' in cassette '%s'", key, r.cassettePath)
+		http.Error(w, "VCR: no recorded interaction", http.StatusNotImplemented)
+		return
+	}
+
+	exhausted := idx >= len(matches)
+	if exhausted {
+		idx = len(matches) - 1
+	}
+
+	recorded := matches[idx]
+
+	if recorded.RequestBody != interaction.RequestBody {
+		r.t.Errorf("Expected request body for '%s' to match recorded one in cassette '%s'\nRecorded: %s\nActual: %s",
+			key, r.cassettePath, recorded.RequestBody, interaction.RequestBody)
+		http.Error(w, "VCR: request body does not match recorded interaction", http.StatusNotImplemented)
+		return
+	}
+
+	if len(recorded.ContentType) > 0 {
+		w.Header().Set("Content-Type", recorded.ContentType)
+	}
+	w.WriteHeader(recorded.StatusCode)
+	io.WriteString(w, recorded.ResponseBody)
+
+	// Recorded streams ended because client went away;
+	// keep connection open so that client does not immediately retry
+	if vcrIsStreaming(req.URL) {
+		if flusher, ok := w.(http.Flusher); ok {
+			flusher.Flush()
+		}
+		if exhausted {
+			<-req.Context().Done()
+		}
+	}
+}
+
+func vcrIsStreaming(u *url.URL) bool {
+	query := u.Query()
+	return query.Get("watch") == "true" || query.Get("follow") == "true" || strings.Contains(u.Path, "/watch/")
+}
+
+func vcrNormalizedURL(u *url.URL) string {
+	query := u.Query()
+	for _, param := range vcrVolatileQueryParams {
+		query.Del(param)
+	}
+
+	if len(query) == 0 {
+		return u.Path
+	}
+	return u.Path + "?" + query.Encode() // sorted by key
+}
+
+// vcrNormalizedBody returns JSON request body with volatile values replaced.
+// Other bodies (e.g. protobuf) are not compared since they cannot be normalized.
+func vcrNormalizedBody(contentType string, body []byte) string {
+	if len(body) == 0 || !strings.Contains(contentType, "json") {
+		return ""
+	}
+
+	result := string(body)
+	for _, pattern := range vcrVolatileBodyPatterns {
+		result = pattern.regexp.ReplaceAllString(result, pattern.replacement)
+	}
+	return result
+}
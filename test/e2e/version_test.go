@@ -23,7 +23,7 @@ import (
 
 func TestVersion(t *testing.T) {
 	env := BuildEnv(t)
-	knctl := Knctl{t, env.Namespace, Logger{}}
+	knctl := Knctl{t, env.Namespace, NewLogger(t)}
 
 	out, _ := knctl.RunWithOpts([]string{"version"}, RunOpts{NoNamespace: true})
 